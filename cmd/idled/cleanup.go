@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/younsl/idled/internal/cleanup"
+	"github.com/younsl/idled/internal/models"
+)
+
+// cleanupPath is the file of --generate-cleanup-script, empty to not generate one
+var cleanupPath string
+
+// cleanupScript collects cleanup commands with --generate-cleanup-script, nil otherwise
+var cleanupScript *cleanup.Script
+
+// addToCleanup adds the idle items of a service to the cleanup script, if one is being generated.
+// Resource groups are expanded into their individual resources.
+func addToCleanup[T any](items []T) {
+	if cleanupScript == nil {
+		return
+	}
+	resources := make([]any, 0, len(items))
+	for _, item := range items {
+		if group, ok := any(item).(models.ResourceGroup); ok {
+			resources = append(resources, group.Resources()...)
+			continue
+		}
+		resources = append(resources, item)
+	}
+	cleanupScript.Add(resources)
+}
+
+// writeCleanupScript writes the collected cleanup commands to path. The script is only
+// generated, idled never runs it.
+func writeCleanupScript(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create cleanup script: %w", err)
+	}
+	if err := cleanupScript.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close cleanup script %s: %w", path, err)
+	}
+	return nil
+}
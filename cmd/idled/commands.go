@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/younsl/idled/pkg/aws"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// serviceIdleDays is the minimum idle age set with --idle-days on a "scan <service>" command
+var serviceIdleDays int
+
+// serviceFlags registers the options that only apply to one service. They live on the
+// "scan <service>" command of the service, and on "scan all" and the root command.
+var serviceFlags = map[string]func(*pflag.FlagSet){
+	"ec2": addUseCloudTrailFlag,
+	"ec2-underutilized": func(flags *pflag.FlagSet) {
+		// Thresholds and lookback of the running instance utilization check
+		flags.Float64Var(&cpuThreshold, "cpu-threshold", aws.DefaultCPUThresholdPercent,
+			"Average CPU utilization (%) below which a running EC2 instance is underutilized")
+		flags.Float64Var(&networkThreshold, "network-threshold", aws.DefaultNetworkThresholdMBs,
+			"Average network throughput (MB/s, in and out combined) below which a running EC2 instance is underutilized")
+		flags.IntVar(&utilizationDays, "utilization-days", aws.DefaultUtilizationDays,
+			"Days of CloudWatch metrics to evaluate for running EC2 instances (1-63)")
+		flags.BoolVar(&includeASG, "include-asg", false,
+			"Also report running EC2 instances in Auto Scaling groups, which replace terminated instances")
+	},
+	"ebs": func(flags *pflag.FlagSet) {
+		addUseCloudTrailFlag(flags)
+		// EBS volumes attached to stopped instances (in-use, so skipped by default)
+		flags.BoolVar(&stoppedAttached, "include-stopped-attached", false,
+			"Also report EBS volumes attached to stopped EC2 instances")
+		// Age within which a snapshot makes an idle volume safe to delete
+		flags.IntVar(&snapshotDays, "ebs-snapshot-days", aws.DefaultSnapshotRecencyDays,
+			"Days within which an EBS snapshot counts as recent in the SNAPSHOT column and summary")
+	},
+	"iam": func(flags *pflag.FlagSet) {
+		// Access key age after which active keys are reported as stale
+		flags.IntVar(&iamKeyMaxAge, "iam-key-max-age", aws.DefaultIAMKeyMaxAge,
+			"Days after which an active IAM access key is stale (never used keys are always stale)")
+	},
+	"elb": func(flags *pflag.FlagSet) {
+		// Idle classification of load balancers whose traffic metric check failed
+		flags.BoolVar(&assumeIdleELB, "assume-idle-on-missing-metrics", false,
+			"Report load balancers without healthy targets as idle when their traffic metric check fails, instead of as uncertain")
+	},
+	"ecr": func(flags *pflag.FlagSet) {
+		// Inspector2 cross-reference flag for idle ECR repositories
+		flags.BoolVar(&inspectorCoverage, "inspector-coverage", false,
+			"Check whether idle ECR repositories are still enrolled in Inspector2 enhanced scanning")
+	},
+	"lambda": func(flags *pflag.FlagSet) {
+		// Error rate from which an invoked function is reported as failing
+		flags.Float64Var(&lambdaFailingRate, "lambda-failing-error-rate", aws.DefaultLambdaFailingErrorRate,
+			"Error rate (%) over the last 30 days from which an invoked Lambda function is reported as Failing")
+	},
+	"route53": func(flags *pflag.FlagSet) {
+		// DNS lookups of public domains to find hosted zones the domain is not delegated to
+		flags.BoolVar(&route53Delegation, "route53-check-delegation", false,
+			"Look up the NS records of public hosted zone domains to find zones they are not delegated to (requires outbound DNS)")
+	},
+	"cloudwatch": func(flags *pflag.FlagSet) {
+		// ListMetrics lookups of the metrics watched by the alarms, slow in accounts with many metrics
+		flags.BoolVar(&cloudWatchMetrics, "cloudwatch-check-metrics", false,
+			"Report CloudWatch alarms whose metric no longer exists, with one ListMetrics call per metric (slower)")
+	},
+	"logs": func(flags *pflag.FlagSet) {
+		// Days without events before a log group is considered idle
+		flags.IntVar(&logsIdleDays, "logs-idle-days", 90,
+			"Days without new log events before a CloudWatch Log Group is considered idle")
+	},
+}
+
+// addUseCloudTrailFlag registers --use-cloudtrail, shared by EC2 and EBS, unless a service
+// registered it already
+func addUseCloudTrailFlag(flags *pflag.FlagSet) {
+	if flags.Lookup("use-cloudtrail") != nil {
+		return
+	}
+	// CloudTrail lookups of EC2 stop times that cannot be parsed and of EBS detachments
+	flags.BoolVar(&useCloudTrail, "use-cloudtrail", false,
+		"Look up in CloudTrail the EC2 stop times missing from the state transition reason, and the instance each detached EBS volume was last attached to (slower)")
+}
+
+// addAllServiceFlags registers the options of every service
+func addAllServiceFlags(flags *pflag.FlagSet) {
+	for _, service := range serviceRegistry {
+		if addFlags, ok := serviceFlags[service.Name]; ok {
+			addFlags(flags)
+		}
+	}
+}
+
+// exit ends the process with an exit code, replaced in tests
+var exit = os.Exit
+
+// runAndExit runs the scan and exits with its exit code unless the scan succeeded
+func runAndExit(cmd *cobra.Command, scanServices []string) {
+	if code := run(cmd, scanServices); code != exitCodeOK {
+		exit(code)
+	}
+}
+
+// unknownServiceError reports a "scan" argument that is neither a registered service nor
+// "all", with the closest service name as a suggestion
+func unknownServiceError(cmd *cobra.Command, name string) error {
+	names := []string{"all"}
+	for _, service := range serviceRegistry {
+		names = append(names, service.Name)
+	}
+	if suggestion := utils.SuggestClosest(name, names); suggestion != "" {
+		return fmt.Errorf("unknown service '%s' for '%s', did you mean '%s'?", name, cmd.CommandPath(), suggestion)
+	}
+	return fmt.Errorf("unknown service '%s' for '%s', see '%s list-services'", name, cmd.CommandPath(), cmd.Root().Name())
+}
+
+// newScanCommand creates the "scan" command with a subcommand per registered service and "all"
+func newScanCommand() *cobra.Command {
+	scanCmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan AWS services for idle resources",
+		Long: `Scan one AWS service, or all of them, for idle resources:
+
+  idled scan ec2
+  idled scan s3 --idle-days 60
+  idled scan all -r us-east-1,eu-west-1`,
+		// Arguments that match no service subcommand are reported with a suggestion, printed once
+		// by execute instead of after the usage
+		Args:          cobra.ArbitraryArgs,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			return unknownServiceError(cmd, args[0])
+		},
+	}
+
+	for _, service := range serviceRegistry {
+		name := service.Name
+		serviceCmd := &cobra.Command{
+			Use:   name,
+			Short: service.Description,
+			Args:  cobra.NoArgs,
+			Run: func(cmd *cobra.Command, args []string) {
+				runAndExit(cmd, []string{name})
+			},
+		}
+		serviceCmd.Flags().IntVar(&serviceIdleDays, "idle-days", 0,
+			fmt.Sprintf("Only show %s resources idle for at least this many days (overrides --min-idle-days)", name))
+		if addFlags, ok := serviceFlags[name]; ok {
+			addFlags(serviceCmd.Flags())
+		}
+		scanCmd.AddCommand(serviceCmd)
+	}
+
+	allCmd := &cobra.Command{
+		Use:   "all",
+		Short: "Scan every supported service",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var names []string
+			for _, service := range serviceRegistry {
+				names = append(names, service.Name)
+			}
+			runAndExit(cmd, names)
+		},
+	}
+	addAllServiceFlags(allCmd.Flags())
+	scanCmd.AddCommand(allCmd)
+
+	return scanCmd
+}
+
+// newListServicesCommand creates the "list-services" command
+func newListServicesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-services",
+		Short: "List available services",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			newRunner().PrintServiceList(cmd.Root().Name())
+		},
+	}
+}
+
+// applyServiceIdleDays sets the minimum idle age of the scanned service from --idle-days.
+// It returns false if the value is invalid.
+func applyServiceIdleDays(cmd *cobra.Command) bool {
+	if cmd.Flags().Lookup("idle-days") == nil || !cmd.Flags().Changed("idle-days") {
+		return true
+	}
+	if serviceIdleDays < 0 {
+		fmt.Println("--idle-days must not be negative. Exiting.")
+		return false
+	}
+
+	// Copy the thresholds of the config file rather than modifying them
+	thresholds := make(map[string]int, len(serviceMinIdleDays)+1)
+	for service, days := range serviceMinIdleDays {
+		thresholds[service] = days
+	}
+	thresholds[cmd.Name()] = serviceIdleDays
+	serviceMinIdleDays = thresholds
+	return true
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// executeCommand runs idled with the given arguments and returns its exit code and output.
+// The AWS environment points at an empty home directory, and the globals are restored when
+// the test ends.
+func executeCommand(t *testing.T, args ...string) (int, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	savedOut, savedExit := out, exit
+	t.Cleanup(func() { out, exit = savedOut, savedExit })
+
+	var output bytes.Buffer
+	out = &output
+	exitCode := exitCodeOK
+	exit = func(code int) { exitCode = code }
+
+	rootCmd := newRootCommand()
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(&output)
+	rootCmd.SetErr(&output)
+	if code := execute(rootCmd); code != exitCodeOK {
+		exitCode = code
+	}
+	return exitCode, output.String()
+}
+
+func TestScanCommandHasEveryService(t *testing.T) {
+	scanCmd := newScanCommand()
+	for _, service := range serviceRegistry {
+		cmd, args, err := scanCmd.Find([]string{service.Name})
+		if err != nil || cmd.Name() != service.Name || len(args) != 0 {
+			t.Errorf("scan %s resolves to %q with args %v, error %v", service.Name, cmd.Name(), args, err)
+		}
+	}
+}
+
+func TestScanUnknownService(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "typo",
+			args: []string{"scan", "lamda"},
+			want: "unknown service 'lamda' for 'idled scan', did you mean 'lambda'?",
+		},
+		{
+			name: "no close service",
+			args: []string{"scan", "kubernetes"},
+			want: "unknown service 'kubernetes' for 'idled scan', see 'idled list-services'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, output := executeCommand(t, tt.args...)
+
+			if code != exitCodeError {
+				t.Errorf("exit code = %d, want %d", code, exitCodeError)
+			}
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, output)
+			}
+		})
+	}
+}
+
+func TestScanWithoutServicePrintsHelp(t *testing.T) {
+	code, output := executeCommand(t, "scan")
+
+	if code != exitCodeOK {
+		t.Errorf("exit code = %d, want %d", code, exitCodeOK)
+	}
+	if !strings.Contains(output, "idled scan all") {
+		t.Errorf("output is not the scan help:\n%s", output)
+	}
+}
+
+func TestUnknownServiceFailsBeforeScanning(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "deprecated --services flag", args: []string{"-s", "lamda", "--no-history", "--no-pricing-cache"}},
+		{name: "scan subcommand", args: []string{"scan", "lamda"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, output := executeCommand(t, tt.args...)
+
+			if code != exitCodeError {
+				t.Errorf("exit code = %d, want %d", code, exitCodeError)
+			}
+			if !strings.Contains(output, "'lamda'") || !strings.Contains(output, "did you mean 'lambda'?") {
+				t.Errorf("output does not suggest lambda:\n%s", output)
+			}
+			if strings.Contains(output, "resources analyzed") {
+				t.Errorf("a service was scanned:\n%s", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/younsl/idled/internal/config"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/runner"
+)
+
+var (
+	configPath         string         // --config, empty to look up ./idled.yaml then ~/.idled.yaml
+	awsProfile         string         // AWS shared config profile
+	serviceMinIdleDays map[string]int // Minimum idle age per service from the config file
+	activeMinIdleDays  int            // Minimum idle age of the service being scanned
+)
+
+// configFlags are the flags of the config file keys
+var configFlags = map[string]string{
+	"regions":              "regions",
+	"services":             "services",
+	"minIdleDays":          "min-idle-days",
+	"logsIdleDays":         "logs-idle-days",
+	"onlyIdle":             "only-idle",
+	"output":               "output",
+	"profile":              "profile",
+	"tags":                 "tag",
+	"alertCostPerResource": "alert-cost-per-resource",
+	"alertTotalCost":       "alert-total-cost",
+}
+
+// configEnvs are the environment variables of the config file keys, which take precedence
+// over the config file but not over the flags
+var configEnvs = map[string]string{
+	"profile": "AWS_PROFILE",
+}
+
+// loadConfigFile applies the config file to every setting set by neither its flag nor its
+// environment variable. Unknown keys only produce warnings. It returns false if the config
+// file cannot be loaded.
+func loadConfigFile(cmd *cobra.Command) bool {
+	path, err := config.Find(configPath)
+	if err != nil {
+		fmt.Fprintf(out, "%v. Exiting.\n", err)
+		return false
+	}
+	if path == "" {
+		return true
+	}
+
+	f, unknown, err := config.Load(path)
+	if err != nil {
+		fmt.Fprintf(out, "%s: %v. Exiting.\n", path, err)
+		return false
+	}
+	for _, key := range unknown {
+		fmt.Fprintf(out, "⚠️  WARNING: Unknown key '%s' in config file %s\n", key, path)
+	}
+
+	flags := cmd.Flags()
+	settings := config.Settings{
+		Regions:              regions,
+		Services:             services,
+		MinIdleDays:          minIdleDays,
+		IdleDays:             serviceMinIdleDays,
+		LogsIdleDays:         logsIdleDays,
+		OnlyIdle:             onlyIdle,
+		Output:               outputFormat,
+		Profile:              awsProfile,
+		Tags:                 tagArgs,
+		AlertCostPerResource: alertThresholds.CostPerResource,
+		AlertTotalCost:       alertThresholds.TotalCost,
+	}
+	f.Apply(&settings, func(key string) bool {
+		if env, ok := configEnvs[key]; ok && os.Getenv(env) != "" {
+			return true
+		}
+		return flags.Changed(configFlags[key])
+	})
+	regions, services = settings.Regions, settings.Services
+	minIdleDays, serviceMinIdleDays = settings.MinIdleDays, settings.IdleDays
+	logsIdleDays, onlyIdle = settings.LogsIdleDays, settings.OnlyIdle
+	outputFormat, awsProfile, tagArgs = settings.Output, settings.Profile, settings.Tags
+	alertThresholds.CostPerResource, alertThresholds.TotalCost = settings.AlertCostPerResource, settings.AlertTotalCost
+
+	for service := range serviceMinIdleDays {
+		if !isRegisteredService(service) {
+			fmt.Fprintf(out, "⚠️  WARNING: Unknown service '%s' under idleDays in config file %s\n", service, path)
+		}
+	}
+
+	fmt.Fprintf(out, "Config: %s\n", path)
+	return true
+}
+
+// applyProfile selects the AWS shared config profile for every client created afterwards
+func applyProfile() error {
+	if awsProfile == "" {
+		return nil
+	}
+	if err := os.Setenv("AWS_PROFILE", awsProfile); err != nil {
+		return fmt.Errorf("failed to select AWS profile %s: %w", awsProfile, err)
+	}
+	return nil
+}
+
+// withServiceSettings applies the per-service minimum idle age of the config file while
+// the service is scanned. Services run one at a time.
+func withServiceSettings(name string, process runner.Processor) runner.Processor {
+	return func(ctx context.Context, scope runner.Scope) []models.CostSummary {
+		activeMinIdleDays = minIdleDays
+		if days, ok := serviceMinIdleDays[name]; ok {
+			activeMinIdleDays = days
+		}
+		slog.Debug("Scanning service", "service", name, "regions", scope.Regions, "minIdleDays", activeMinIdleDays)
+		return process(ctx, scope)
+	}
+}
+
+// isRegisteredService reports whether a service name is known to --services
+func isRegisteredService(name string) bool {
+	for _, service := range serviceRegistry {
+		if service.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// newConfigCommand creates the "config" command with its "init" subcommand
+func newConfigCommand() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the idled config file",
+	}
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "init",
+		Short: "Print a commented example config file",
+		Long: `Print a commented example config file. Save it as ./idled.yaml or ~/.idled.yaml:
+
+  idled config init > ~/.idled.yaml`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Print(config.Example)
+		},
+	})
+	return configCmd
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/younsl/idled/internal/cleanup"
+	"github.com/younsl/idled/pkg/aws"
+)
+
+var (
+	deleteMode bool // --delete
+	assumeYes  bool // --yes
+)
+
+// deletionCandidates collects the resources that are safe to delete with --delete
+var deletionCandidates []cleanup.Candidate
+
+var (
+	// deleteInput is read for the selection and confirmation prompts of --delete
+	deleteInput io.Reader = os.Stdin
+	// deleter deletes the selected candidates
+	deleter cleanup.Deleter = awsDeleter{}
+)
+
+// addToDeletion adds the idle items of a service that are safe to delete, if --delete is set
+func addToDeletion[T any](items []T) {
+	if !deleteMode {
+		return
+	}
+	resources := make([]any, 0, len(items))
+	for _, item := range items {
+		if isIdle(item) {
+			resources = append(resources, item)
+		}
+	}
+	deletionCandidates = append(deletionCandidates, cleanup.Candidates(resources)...)
+}
+
+// awsDeleter deletes resources with a client created for the region of each resource
+type awsDeleter struct{}
+
+// DeleteVolume deletes an unattached EBS volume
+func (awsDeleter) DeleteVolume(ctx context.Context, region, volumeID string) error {
+	cfg, err := aws.LoadConfig(ctx, region)
+	if err != nil {
+		return fmt.Errorf("error loading AWS config: %w", err)
+	}
+	return aws.NewEBSClient(cfg).DeleteVolume(ctx, volumeID)
+}
+
+// ReleaseAddress releases an unassociated Elastic IP
+func (awsDeleter) ReleaseAddress(ctx context.Context, region, allocationID string) error {
+	cfg, err := aws.LoadConfig(ctx, region)
+	if err != nil {
+		return fmt.Errorf("error loading AWS config: %w", err)
+	}
+	return aws.NewEIPClient(cfg).ReleaseAddress(ctx, allocationID)
+}
+
+// DeleteBucket deletes an empty S3 bucket
+func (awsDeleter) DeleteBucket(ctx context.Context, region, bucketName string) error {
+	cfg, err := aws.LoadConfig(ctx, region)
+	if err != nil {
+		return fmt.Errorf("error loading AWS config: %w", err)
+	}
+	return aws.NewS3Client(cfg).DeleteBucket(ctx, bucketName)
+}
+
+// deleteIdleResources lists the deletion candidates, asks which to delete unless --yes is set,
+// and deletes them. Failed deletions are counted as errors in the outcome.
+func deleteIdleResources(ctx context.Context) {
+	candidates := deletionCandidates
+	if len(candidates) == 0 {
+		fmt.Fprintln(out, "\nNo unattached EBS volumes, unassociated Elastic IPs, or empty idle S3 buckets to delete.")
+		return
+	}
+	cleanup.PrintCandidates(out, candidates)
+
+	if !assumeYes {
+		selected, err := cleanup.Select(deleteInput, out, candidates)
+		if err != nil {
+			fmt.Fprintln(out, "\nDeletion aborted, no resources were deleted.")
+			return
+		}
+		candidates = selected
+	}
+
+	fmt.Fprintln(out)
+	summary := cleanup.Delete(ctx, deleter, out, candidates)
+	outcome.recordErrors(summary.Failed)
+	fmt.Fprintf(out, "\nDeleted %d of %d resources (%d failed), reclaiming an estimated $%.2f/month\n",
+		summary.Deleted, len(candidates), summary.Failed, summary.ReclaimedCost)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// recordingDeleter records the deleted resources and fails the ones listed in fail
+type recordingDeleter struct {
+	deleted []string
+	fail    map[string]bool
+}
+
+func (d *recordingDeleter) record(id string) error {
+	if d.fail[id] {
+		return errors.New("access denied")
+	}
+	d.deleted = append(d.deleted, id)
+	return nil
+}
+
+func (d *recordingDeleter) DeleteVolume(ctx context.Context, region, volumeID string) error {
+	return d.record(volumeID)
+}
+
+func (d *recordingDeleter) ReleaseAddress(ctx context.Context, region, allocationID string) error {
+	return d.record(allocationID)
+}
+
+func (d *recordingDeleter) DeleteBucket(ctx context.Context, region, bucketName string) error {
+	return d.record(bucketName)
+}
+
+// setupDeletion enables --delete with the given prompt answers and returns the fake deleter
+// and the captured output. The globals are restored when the test ends.
+func setupDeletion(t *testing.T, input string, fail ...string) (*recordingDeleter, *bytes.Buffer) {
+	t.Helper()
+	savedMode, savedYes, savedInput, savedDeleter, savedOut, savedOutcome := deleteMode, assumeYes, deleteInput, deleter, out, outcome
+	t.Cleanup(func() {
+		deleteMode, assumeYes, deleteInput, deleter, out, outcome = savedMode, savedYes, savedInput, savedDeleter, savedOut, savedOutcome
+		deletionCandidates = nil
+	})
+
+	fake := &recordingDeleter{fail: make(map[string]bool)}
+	for _, id := range fail {
+		fake.fail[id] = true
+	}
+	var output bytes.Buffer
+	deleteMode, assumeYes, deleteInput, deleter, out, outcome = true, false, strings.NewReader(input), fake, &output, scanOutcome{}
+	deletionCandidates = nil
+	return fake, &output
+}
+
+// scannedResources adds the results of an EBS, EIP, and S3 scan to the deletion candidates
+func scannedResources() {
+	addToDeletion([]models.VolumeInfo{
+		{VolumeID: "vol-unattached", Region: "us-east-1"},
+		{VolumeID: "vol-attached", Region: "us-east-1", AttachedInstanceID: "i-stopped"},
+	})
+	addToDeletion([]models.EIPInfo{
+		{AllocationID: "eipalloc-free", PublicIP: "198.51.100.1", Region: "us-east-1"},
+		{AllocationID: "eipalloc-used", PublicIP: "198.51.100.2", Region: "us-east-1", AssociationID: "eipassoc-1"},
+	})
+	addToDeletion([]models.BucketInfo{
+		{BucketName: "empty-idle", Region: "us-east-1", IsIdle: true, IsEmpty: true},
+		{BucketName: "non-empty", Region: "us-east-1", IsIdle: true},
+		{BucketName: "protected", Region: "us-east-1", IsIdle: true, IsEmpty: true, Protected: true},
+	})
+}
+
+func TestDeleteIdleResourcesRequiresConfirmation(t *testing.T) {
+	for _, answer := range []string{"yes", "y", "DELETE", ""} {
+		t.Run(fmt.Sprintf("%q", answer), func(t *testing.T) {
+			fake, output := setupDeletion(t, "all\n"+answer+"\n")
+			scannedResources()
+
+			deleteIdleResources(context.Background())
+
+			if len(fake.deleted) != 0 {
+				t.Errorf("deleted %v without typing delete", fake.deleted)
+			}
+			if !strings.Contains(output.String(), "no resources were deleted") {
+				t.Errorf("output does not report the abort:\n%s", output.String())
+			}
+		})
+	}
+}
+
+func TestDeleteIdleResourcesDeletesOnlySafeCandidates(t *testing.T) {
+	fake, _ := setupDeletion(t, "all\ndelete\n")
+	scannedResources()
+
+	deleteIdleResources(context.Background())
+
+	want := []string{"vol-unattached", "eipalloc-free", "empty-idle"}
+	if !slices.Equal(fake.deleted, want) {
+		t.Errorf("deleted %v, want %v", fake.deleted, want)
+	}
+	if outcome.errorCount != 0 {
+		t.Errorf("errorCount = %d, want 0", outcome.errorCount)
+	}
+}
+
+func TestDeleteIdleResourcesReportsFailures(t *testing.T) {
+	fake, output := setupDeletion(t, "all\ndelete\n", "vol-unattached")
+	scannedResources()
+
+	deleteIdleResources(context.Background())
+
+	if want := []string{"eipalloc-free", "empty-idle"}; !slices.Equal(fake.deleted, want) {
+		t.Errorf("deleted %v, want %v", fake.deleted, want)
+	}
+	if outcome.errorCount != 1 {
+		t.Errorf("errorCount = %d, want 1", outcome.errorCount)
+	}
+	if !strings.Contains(output.String(), "Deleted 2 of 3 resources (1 failed)") {
+		t.Errorf("output does not report the failed deletion:\n%s", output.String())
+	}
+}
+
+func TestAddToDeletionRequiresDeleteMode(t *testing.T) {
+	setupDeletion(t, "")
+	deleteMode = false
+
+	scannedResources()
+
+	if len(deletionCandidates) != 0 {
+		t.Errorf("got %d candidates without --delete, want none", len(deletionCandidates))
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/younsl/idled/internal/cleanup"
+	"github.com/younsl/idled/internal/exporter"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/notify"
+	"github.com/younsl/idled/internal/runner"
+	"github.com/younsl/idled/internal/upload"
+	"github.com/younsl/idled/pkg/aws"
+	"github.com/younsl/idled/pkg/formatter"
+)
+
+var (
+	exportFormat     string        // --export
+	listenAddr       string        // --listen
+	pushGatewayURL   string        // --push-gateway
+	scanInterval     time.Duration // --interval
+	slackWebhookURL  string        // --slack-webhook-url
+	notifyIfFindings bool          // --notify-only-if-findings
+	uploadS3URI      string        // --upload-s3
+	uploadKMSKeyID   string        // --upload-kms-key-id
+
+	uploadLocation *upload.Location // Parsed --upload-s3, nil to not upload
+	uploadDoc      *upload.Document // Results uploaded with --upload-s3, nil if not uploading
+)
+
+// costSummaries flattens the per-region summaries of all scanned services
+func costSummaries(results []runner.Result) []models.CostSummary {
+	var summaries []models.CostSummary
+	for _, result := range results {
+		summaries = append(summaries, result.Summaries...)
+	}
+	return summaries
+}
+
+// pushMetrics sends the results of a one-shot scan to the Prometheus Pushgateway
+func pushMetrics(results []runner.Result) error {
+	exp := exporter.New()
+	exp.Update(results)
+	exp.UpdateAPICalls(aws.APICallStats())
+	return exp.Push(pushGatewayURL)
+}
+
+// serveMetrics serves the results as Prometheus metrics on --listen and rescans every
+// --interval until ctx is cancelled. Metrics are only replaced by scans that complete. A tick
+// is skipped while the previous scan is still running. Every scan reuses scanRunner, which was
+// validated once, so its warnings are not repeated. The "serve" command also serves the
+// results as JSON.
+func serveMetrics(ctx context.Context, scanRunner *runner.Runner) int {
+	exp := exporter.New()
+	latest := &scanResults{}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp.Handler())
+	if serveMode {
+		latest.register(mux)
+	}
+	server := &http.Server{Addr: listenAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	if serveMode {
+		fmt.Fprintf(out, "Serving results on %s/results and Prometheus metrics on %s/metrics, rescanning every %s\n", listenAddr, listenAddr, scanInterval)
+	} else {
+		fmt.Fprintf(out, "Serving Prometheus metrics on %s/metrics, rescanning every %s\n", listenAddr, scanInterval)
+	}
+
+	scanDone := make(chan error, 1)
+	scanning := false
+	startScan := func() {
+		scanning = true
+		latest.setScanning(true)
+		go func() {
+			scanDone <- serveScan(ctx, scanRunner, exp, latest)
+		}()
+	}
+	startScan()
+
+	ticker := time.NewTicker(scanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Wait for the cancelled scan so that it does not write to a closed output
+			if scanning {
+				<-scanDone
+			}
+			slog.Info("Shutting down")
+			return exitCodeOK
+		case err := <-serverErr:
+			fmt.Fprintf(out, "Failed to serve metrics on %s: %v. Exiting.\n", listenAddr, err)
+			return exitCodeError
+		case err := <-scanDone:
+			scanning = false
+			latest.setScanning(false)
+			if err != nil {
+				fmt.Fprintf(out, "%v. Exiting.\n", err)
+				return exitCodeError
+			}
+		case <-ticker.C:
+			if scanning {
+				slog.Warn("Skipping scheduled scan, the previous scan is still running", "interval", scanInterval)
+				continue
+			}
+			startScan()
+		}
+	}
+}
+
+// serveScan runs one scan of serveMetrics, bounded by --timeout, and publishes its results
+// unless it was cut short
+func serveScan(ctx context.Context, scanRunner *runner.Runner, exp *exporter.Exporter, latest *scanResults) error {
+	scanCtx := ctx
+	if scanTimeout > 0 {
+		var cancel context.CancelFunc
+		scanCtx, cancel = context.WithTimeout(ctx, scanTimeout)
+		defer cancel()
+	}
+
+	resetScanState()
+	scanMeta.StartedAt = time.Now()
+	if serveMode {
+		// The served JSON has the same format as the document uploaded with --upload-s3
+		uploadDoc = upload.New(scanMeta)
+	}
+	slog.Info("Scan started", "services", services, "regions", scanMeta.Regions)
+
+	results, err := scanRunner.Run(scanCtx)
+	if err != nil {
+		return err
+	}
+	printRunReports(results)
+
+	if scanCtx.Err() != nil {
+		slog.Warn("Scan cut short, keeping the previous results", "duration", time.Since(scanMeta.StartedAt).Round(time.Millisecond), "error", scanCtx.Err())
+		return nil
+	}
+	exp.Update(results)
+	exp.UpdateAPICalls(aws.APICallStats())
+	if serveMode {
+		uploadDoc.Summaries = costSummaries(results)
+		uploadDoc.Metadata.GeneratedAt = time.Now().UTC()
+		latest.set(uploadDoc, results)
+	}
+	slog.Info("Scan finished", "duration", time.Since(scanMeta.StartedAt).Round(time.Millisecond),
+		"idleResources", outcome.idleCount, "monthlyCost", fmt.Sprintf("%.2f", outcome.monthlyCost), "errors", outcome.errorCount)
+	return nil
+}
+
+// slackWebhookEnv is the environment variable used when --slack-webhook-url is not set
+const slackWebhookEnv = "IDLED_SLACK_WEBHOOK_URL"
+
+// notifySlack posts a digest of the results to Slack when a webhook is configured.
+// Failures are reported as warnings and do not fail the run.
+func notifySlack(ctx context.Context, results []runner.Result) {
+	webhookURL := slackWebhookURL
+	if webhookURL == "" {
+		webhookURL = os.Getenv(slackWebhookEnv)
+	}
+	if webhookURL == "" {
+		return
+	}
+
+	summaries := costSummaries(results)
+	if notifyIfFindings && !notify.HasFindings(summaries) {
+		return
+	}
+
+	payload, err := notify.BuildSlackDigest(summaries)
+	if err == nil {
+		err = notify.PostSlack(ctx, webhookURL, payload)
+	}
+	if err != nil {
+		fmt.Fprintf(out, "\n⚠️  WARNING: Failed to send Slack notification: %v\n", err)
+		return
+	}
+	fmt.Fprintln(out, "\nSlack notification sent.")
+}
+
+// setupUpload prepares the document uploaded with --upload-s3 once the scan metadata is known
+func setupUpload() {
+	if uploadLocation != nil {
+		uploadDoc = upload.New(scanMeta)
+	}
+}
+
+// addToUpload adds the resources shown in the tables of a service to the uploaded document,
+// if one is being built. Resource groups are expanded into their individual resources.
+func addToUpload[T any](service string, items []T) {
+	if uploadDoc == nil {
+		return
+	}
+	uploadDoc.Add(service, reportResources(filterOnlyIdle(items)))
+}
+
+// uploadResults uploads the results of the scan to --upload-s3. The S3 client is created in
+// the region of the bucket, with the same profile and credentials as the scan.
+func uploadResults(ctx context.Context, results []runner.Result) error {
+	if uploadDoc == nil {
+		return nil
+	}
+	uploadDoc.Summaries = costSummaries(results)
+
+	client, err := aws.NewBucketClient(ctx, scanMeta.Regions[0], uploadLocation.Bucket)
+	if err != nil {
+		return fmt.Errorf("failed to create the S3 client for --upload-s3: %w", err)
+	}
+	key := uploadLocation.Key(scanMeta.Account, scanMeta.StartedAt)
+	if err := upload.Upload(ctx, client, *uploadLocation, key, uploadKMSKeyID, uploadDoc); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nResults uploaded to s3://%s/%s\n", uploadLocation.Bucket, key)
+	return nil
+}
+
+// resetScanState clears what the previous scan of serveMetrics collected, so that findings,
+// errors, API calls, and resources are reported per scan instead of adding up
+func resetScanState() {
+	outcome = scanOutcome{}
+	aws.ResetAPICallStats()
+	aws.ResetSkippedResources()
+	deletionCandidates = nil
+	if scanReport != nil {
+		scanReport = newScanReport()
+	}
+	if cleanupScript != nil {
+		cleanupScript = cleanup.New()
+	}
+	previousSnapshot, currentSnapshot, firstSeenIndex = nil, nil, nil
+	formatter.SetFirstSeen(nil)
+}
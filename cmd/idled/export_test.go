@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/younsl/idled/internal/cleanup"
+	"github.com/younsl/idled/internal/history"
+	"github.com/younsl/idled/internal/models"
+)
+
+func TestResetScanStateDoesNotCarryResourcesOver(t *testing.T) {
+	savedReport, savedScript, savedOutcome := scanReport, cleanupScript, outcome
+	t.Cleanup(func() {
+		scanReport, cleanupScript, outcome = savedReport, savedScript, savedOutcome
+		deletionCandidates = nil
+		previousSnapshot, currentSnapshot, firstSeenIndex = nil, nil, nil
+	})
+
+	scanReport, cleanupScript = newScanReport(), cleanup.New()
+	volumes := []models.VolumeInfo{{VolumeID: "vol-1", Region: "us-east-1"}}
+
+	// Every scan finds the same volume, which must be collected once per scan
+	for scan := 1; scan <= 2; scan++ {
+		resetScanState()
+		if len(scanReport.Sections) != 0 || cleanupScript.Len() != 0 || len(deletionCandidates) != 0 {
+			t.Fatalf("scan %d started with %d report services, %d cleanup commands, and %d deletion candidates, want none",
+				scan, len(scanReport.Sections), cleanupScript.Len(), len(deletionCandidates))
+		}
+		if outcome != (scanOutcome{}) {
+			t.Fatalf("scan %d started with outcome %+v, want none", scan, outcome)
+		}
+
+		scanReport.Add("EBS", reportResources(volumes), "")
+		addToCleanup(volumes)
+		deletionCandidates = append(deletionCandidates, cleanup.Candidates([]any{volumes[0]})...)
+		outcome.idleCount++
+		currentSnapshot = history.New("123456789012")
+		if len(scanReport.Sections) != 1 || cleanupScript.Len() != 1 || len(deletionCandidates) != 1 {
+			t.Fatalf("scan %d collected %d report services, %d cleanup commands, and %d deletion candidates, want 1 each",
+				scan, len(scanReport.Sections), cleanupScript.Len(), len(deletionCandidates))
+		}
+	}
+
+	resetScanState()
+	if currentSnapshot != nil || previousSnapshot != nil || firstSeenIndex != nil {
+		t.Error("history of the previous scan kept, want it cleared")
+	}
+	if scanReport == nil || cleanupScript == nil {
+		t.Error("report or cleanup script dropped, want them recreated empty")
+	}
+}
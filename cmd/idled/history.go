@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/younsl/idled/internal/history"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/formatter"
+)
+
+var (
+	diffMode  bool // --diff, or --diff-only
+	diffOnly  bool // --diff-only
+	noHistory bool // --no-history, or serving metrics
+
+	historyDir       string                  // Directory of the scan snapshots
+	previousSnapshot *history.Snapshot       // Most recent snapshot compared with --diff, nil if none
+	currentSnapshot  *history.Snapshot       // Snapshot of this scan, nil if neither saved nor compared
+	firstSeenPath    string                  // Path of the first-seen index
+	firstSeenIndex   *history.FirstSeenIndex // First-seen idle dates, nil if the history is disabled
+)
+
+// setupHistory prepares the snapshot of this scan of the given services and regions, loads the
+// first-seen index and, with --diff, the most recent snapshot of the same account. Failures only
+// disable the history with a warning.
+func setupHistory(services, regions []string) {
+	if noHistory && !diffMode {
+		return
+	}
+
+	dir, err := history.DefaultDir()
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  WARNING: Scan history disabled: %v\n", err)
+		return
+	}
+	historyDir = dir
+
+	// Snapshots are kept per account so that scans of different accounts are not compared
+	currentSnapshot = history.New(scanMeta.Account)
+	currentSnapshot.Filters = &history.Filters{
+		Services:    services,
+		Regions:     regions,
+		Include:     includeArgs,
+		Exclude:     excludeArgs,
+		Tags:        tagFilters,
+		Ignore:      ignoredResources,
+		MinIdleDays: minIdleDays,
+	}
+	setupFirstSeen()
+
+	if !diffMode {
+		return
+	}
+	previousSnapshot, err = history.Latest(historyDir, scanMeta.Account)
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  WARNING: Failed to load the previous scan: %v\n", err)
+	} else if previousSnapshot == nil {
+		fmt.Fprintf(out, "No previous scan found in %s, changes are reported from the next run.\n", historyDir)
+	} else {
+		checkPreviousFilters()
+	}
+}
+
+// checkPreviousFilters drops the previous snapshot from --diff if it was taken with other name,
+// tag, or idle age filters, whose resources would be reported as new or resolved only because
+// they were filtered differently. Other services and regions are only noted, since the diff
+// compares the services and regions both scans covered.
+func checkPreviousFilters() {
+	if previousSnapshot.Filters == nil {
+		fmt.Fprintf(out, "⚠️  WARNING: The previous scan did not record its filters, changes may include resources filtered differently.\n")
+		return
+	}
+	if changes := currentSnapshot.Filters.ResultChanges(*previousSnapshot.Filters); len(changes) > 0 {
+		fmt.Fprintf(out, "⚠️  WARNING: Changes are not reported because the previous scan used different filters (%s).\n",
+			strings.Join(changes, ", "))
+		previousSnapshot = nil
+		return
+	}
+	if changes := currentSnapshot.Filters.ScopeChanges(*previousSnapshot.Filters); len(changes) > 0 {
+		fmt.Fprintf(out, "Note: The previous scan used different %s, changes are reported for those scanned by both.\n",
+			strings.Join(changes, " and "))
+	}
+}
+
+// setupFirstSeen loads the first-seen index, shown in the FIRST SEEN column with --diff
+func setupFirstSeen() {
+	path, err := history.DefaultIndexPath()
+	if err == nil {
+		firstSeenIndex, err = history.LoadIndex(path, historyDir, scanMeta.Account)
+	}
+	if err != nil {
+		firstSeenIndex = nil
+		fmt.Fprintf(out, "⚠️  WARNING: First-seen dates disabled: %v\n", err)
+		return
+	}
+	firstSeenPath = path
+	formatter.SetShowFirstSeen(diffMode)
+}
+
+// trackFirstSeen records the idle resource keys of a service per region in the first-seen
+// index and passes the dates of the service to the formatter. It must run before the tables
+// of the service are printed. Regions that failed to scan must be left out so that their
+// resources keep their dates.
+func trackFirstSeen(service string, keysByRegion map[string][]string) {
+	if firstSeenIndex == nil {
+		return
+	}
+	firstSeenIndex.Update(scanMeta.Account, service, keysByRegion, scanMeta.StartedAt)
+	formatter.SetFirstSeen(firstSeenIndex.Dates(scanMeta.Account, service))
+}
+
+// trackHistory records the idle resource keys of a service per region in the snapshot of this
+// scan and, with --diff, prints the changes since the previous snapshot. Regions that failed
+// to scan must be left out so that their resources are not reported as resolved.
+func trackHistory(service string, keysByRegion map[string][]string) {
+	if currentSnapshot == nil {
+		return
+	}
+	regionNames := make([]string, 0, len(keysByRegion))
+	for region := range keysByRegion {
+		regionNames = append(regionNames, region)
+	}
+	sort.Strings(regionNames)
+	for _, region := range regionNames {
+		currentSnapshot.Add(service, region, keysByRegion[region])
+	}
+
+	if previousSnapshot == nil {
+		return
+	}
+	added, resolved, ok := previousSnapshot.Diff(service, keysByRegion)
+	if !ok {
+		fmt.Fprintf(out, "\nNo previous scan of %s in these regions to compare with.\n", service)
+		return
+	}
+	formatter.PrintDiff(out, service, previousSnapshot.CreatedAt, added, resolved)
+}
+
+// saveHistory writes the snapshot of this scan and the first-seen index unless --no-history is set
+func saveHistory() {
+	if noHistory || currentSnapshot == nil {
+		return
+	}
+	if firstSeenIndex != nil {
+		if err := history.SaveIndex(firstSeenPath, firstSeenIndex); err != nil {
+			fmt.Fprintf(out, "\n⚠️  WARNING: Failed to save the first-seen index: %v\n", err)
+		}
+	}
+	path, err := history.Save(historyDir, currentSnapshot)
+	if err != nil {
+		fmt.Fprintf(out, "\n⚠️  WARNING: Failed to save the scan history: %v\n", err)
+		return
+	}
+	fmt.Fprintf(out, "\nScan snapshot saved to %s\n", path)
+}
+
+// idleKeys returns the canonical keys of the idle items, expanding resource groups
+func idleKeys[T any](items []T) []string {
+	var keys []string
+	for _, item := range items {
+		if group, ok := any(item).(models.ResourceGroup); ok {
+			keys = append(keys, idleKeys(group.Resources())...)
+			continue
+		}
+		keyed, ok := any(item).(models.Keyed)
+		if ok && isIdle(item) {
+			keys = append(keys, keyed.SortKey())
+		}
+	}
+	return keys
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/younsl/idled/internal/history"
+)
+
+func TestCheckPreviousFilters(t *testing.T) {
+	savedOut, savedPrevious, savedCurrent := out, previousSnapshot, currentSnapshot
+	t.Cleanup(func() { out, previousSnapshot, currentSnapshot = savedOut, savedPrevious, savedCurrent })
+
+	current := history.Filters{Services: []string{"ec2"}, Regions: []string{"us-east-1"}, Include: []string{"prod-*"}}
+	tests := []struct {
+		name         string
+		previous     *history.Filters
+		wantCompared bool
+		wantOutput   string
+	}{
+		{name: "same filters", previous: &history.Filters{Services: []string{"ec2"}, Regions: []string{"us-east-1"}, Include: []string{"prod-*"}},
+			wantCompared: true},
+		{name: "other name filter", previous: &history.Filters{Services: []string{"ec2"}, Regions: []string{"us-east-1"}},
+			wantOutput: "Changes are not reported because the previous scan used different filters (--include)"},
+		{name: "other regions", previous: &history.Filters{Services: []string{"ec2"}, Regions: []string{"us-east-1", "eu-west-1"}, Include: []string{"prod-*"}},
+			wantCompared: true, wantOutput: "The previous scan used different --regions, changes are reported for those scanned by both"},
+		{name: "filters not recorded", wantCompared: true, wantOutput: "The previous scan did not record its filters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			out = &output
+			currentSnapshot = history.New("111111111111")
+			currentSnapshot.Filters = &current
+			previousSnapshot = history.New("111111111111")
+			previousSnapshot.Filters = tt.previous
+
+			checkPreviousFilters()
+
+			if compared := previousSnapshot != nil; compared != tt.wantCompared {
+				t.Errorf("previous snapshot kept = %t, want %t", compared, tt.wantCompared)
+			}
+			if tt.wantOutput == "" && output.Len() > 0 || !strings.Contains(output.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want %q", output.String(), tt.wantOutput)
+			}
+		})
+	}
+}
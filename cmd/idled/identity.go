@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/aws"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// scanMeta describes the current scan, including the AWS identity resolved at startup
+var scanMeta models.ScanMetadata
+
+// Region scanned when none is requested, resolved from the environment and profile before scanning
+var (
+	defaultRegion       = utils.GetDefaultRegion()
+	defaultRegionSource = aws.RegionSourceFallback // Where defaultRegion came from
+)
+
+// resolveIdentity looks up the AWS identity of the credentials once before scanning, so that
+// missing credentials fail fast instead of in every regional scanner, and prints the scan header.
+// scanRegions are the regions validated by the runner.
+func resolveIdentity(ctx context.Context, scanRegions []string) error {
+	requested := scanRegions
+	if len(requested) == 0 {
+		requested = []string{defaultRegion}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	identity, err := aws.GetCallerIdentity(lookupCtx, requested[0])
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// One actionable message instead of the raw SDK error chain (--profile is applied to AWS_PROFILE)
+		if hint := aws.CredentialHint(err, os.Getenv("AWS_PROFILE"), requested[0]); hint != "" {
+			return fmt.Errorf("unable to determine AWS identity: %s", hint)
+		}
+		return fmt.Errorf("unable to determine AWS identity, no valid credentials: %w", err)
+	}
+
+	scanMeta = models.ScanMetadata{
+		Account:   identity.Account,
+		CallerARN: identity.ARN,
+		Regions:   requested,
+		StartedAt: time.Now(),
+	}
+	fmt.Fprintf(out, "Account: %s (%s) — Regions: %s%s\n",
+		scanMeta.Account, scanMeta.CallerARN, strings.Join(scanMeta.Regions, ", "), defaultRegionNote())
+	return nil
+}
+
+// defaultRegionNote returns where the scanned region came from when no region was requested
+func defaultRegionNote() string {
+	if len(regions) > 0 {
+		return ""
+	}
+	switch defaultRegionSource {
+	case aws.RegionSourceEnv, aws.RegionSourceDefaultEnv:
+		return fmt.Sprintf(" (default from %s)", defaultRegionSource)
+	case aws.RegionSourceProfile:
+		profile := os.Getenv("AWS_PROFILE")
+		if profile == "" {
+			profile = "default"
+		}
+		return fmt.Sprintf(" (default from profile %s)", profile)
+	default:
+		return " (default, no region configured)"
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+)
+
+var (
+	verbose bool // Show warnings and debug messages
+	quiet   bool // Hide progress and every diagnostic message
+)
+
+// setupLogging sends the diagnostics of the scanners to stderr, keeping stdout for the
+// results: only errors by default, warnings and debug messages with --verbose, and nothing
+// with --quiet. The "serve" command also logs the start and end of each scan by default.
+func setupLogging() error {
+	if verbose && quiet {
+		return errors.New("--verbose and --quiet cannot be used together")
+	}
+	level := slog.LevelError
+	switch {
+	case verbose:
+		level = slog.LevelDebug
+	case quiet:
+		level = slog.LevelError + 4
+	case serveMode:
+		level = slog.LevelInfo
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+	return nil
+}
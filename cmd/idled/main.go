@@ -2,17 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"sort"
+	"os/signal"
 	"strings"
-	"sync"
+	"syscall"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/briandowns/spinner"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/spf13/cobra"
-	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/alert"
+	"github.com/younsl/idled/internal/runner"
 	"github.com/younsl/idled/internal/version"
 	"github.com/younsl/idled/pkg/aws"
 	"github.com/younsl/idled/pkg/formatter"
@@ -25,633 +27,533 @@ const (
 	Version        = "0.7.1"
 	BuildDate      = "2025-04-21"
 	DefaultService = "ec2"
+
+	// globalRegion is the region of global services in summaries, history, and errors
+	globalRegion = runner.GlobalRegion
 )
 
 var (
 	regions           []string
 	services          []string
 	showVersion       bool
-	supportedServices = map[string]bool{
-		"ec2":            true,
-		"ebs":            true,
-		"s3":             true,
-		"lambda":         true,
-		"eip":            true,
-		"iam":            true,
-		"config":         true,
-		"elb":            true,
-		"logs":           true,
-		"ecr":            true,
-		"msk":            true,
-		"secretsmanager": true,
-	}
+	showServiceList   bool
+	inspectorCoverage bool
+	route53Delegation bool
+	cloudWatchMetrics bool
+	useCloudTrail     bool
+	stoppedAttached   bool
+	snapshotDays      int
+	minIdleDays       int
+	logsIdleDays      int
+	cpuThreshold      float64
+	networkThreshold  float64
+	utilizationDays   int
+	includeASG        bool
+	iamKeyMaxAge      int
+	assumeIdleELB     bool
+	lambdaFailingRate float64
+	maxAPIRPS         float64
+	maxRetries        int
+	maxConcurrency    int
+	endpointURL       string
+	noVerifySSL       bool
+	alertThresholds   alert.Thresholds
+	scanTimeout       time.Duration
+	regionTimeout     time.Duration
+	onlyIdle          bool
+	tagArgs           []string
+	tagFilters        map[string]string
+	includeArgs       []string
+	excludeArgs       []string
+	nameFilter        *utils.NameFilter
 )
 
-// Define service descriptions for help text
-var serviceDescriptions = map[string]string{
-	"ec2":            "Find stopped EC2 instances",
-	"ebs":            "Find unattached EBS volumes",
-	"s3":             "Find idle S3 buckets",
-	"lambda":         "Find idle Lambda functions",
-	"eip":            "Find unattached Elastic IP addresses",
-	"iam":            "Find idle IAM users, roles, and policies",
-	"config":         "Find idle AWS Config rules, recorders, and delivery channels",
-	"elb":            "Find idle Elastic Load Balancers (ALB, NLB)",
-	"logs":           "Find idle CloudWatch Log Groups",
-	"ecr":            "Find idle ECR repositories",
-	"msk":            "Find idle/underutilized MSK clusters",
-	"secretsmanager": "Find idle Secrets Manager secrets",
-}
-
-// startResourceSpinner creates and starts a spinner with a message for the given service and regions
-func startResourceSpinner(service string, regions []string) *spinner.Spinner {
-	s := spinner.New(spinner.CharSets[9], 200*time.Millisecond)
-	regionStr := "Global"
-	if len(regions) > 0 {
-		regionStr = strings.Join(regions, ", ")
-		if len(regions) > 5 { // Limit displayed regions for conciseness
-			regionStr = fmt.Sprintf("%s, ... (%d total)", strings.Join(regions[:5], ", "), len(regions))
-		}
-	}
-	s.Suffix = fmt.Sprintf(" Analyzing %s resources in %s ...", service, regionStr)
-	// Don't set FinalMSG here as it will be set dynamically based on scan time
-	s.Start()
-	return s
+// serviceRegistry lists every scannable service with the processor that handles it
+var serviceRegistry = []runner.Service{
+	{Name: "ec2", Description: "Find stopped EC2 instances", Taggable: true, Process: processEC2},
+	{Name: "ec2-underutilized", Description: "Find running EC2 instances with low CPU and network usage", Taggable: true, Process: processEC2Underutilized},
+	{Name: "ebs", Description: "Find unattached EBS volumes", Taggable: true, Process: processEBS},
+	{Name: "s3", Description: "Find idle S3 buckets", Taggable: true, Nameable: true, Process: processS3},
+	{Name: "lambda", Description: "Find idle Lambda functions", Taggable: true, Nameable: true, Process: processLambda},
+	{Name: "sfn", Description: "Find Step Functions state machines without recent executions", Process: processStepFunctions},
+	{Name: "eip", Description: "Find unattached Elastic IPs and those on stopped instances or orphaned ENIs", Taggable: true, Process: processEIP},
+	{Name: "asg", Description: "Find zero-capacity and suspended Auto Scaling groups and those without healthy targets", Process: processASG},
+	{Name: "eni", Description: "Find orphaned network interfaces in the available status", Taggable: true, Process: processENI},
+	{Name: "iam", Description: "Find idle IAM users, roles, and policies", Global: true, Process: processIAM},
+	{Name: "config", Description: "Find idle AWS Config rules, recorders, and delivery channels", Process: processConfig},
+	{Name: "elb", Description: "Find idle Elastic Load Balancers (ALB, NLB, GWLB, CLB)", Taggable: true, Process: processELB},
+	{Name: "cloudwatch", Description: "Find CloudWatch alarms stuck in INSUFFICIENT_DATA and dashboards not modified recently", Process: processCloudWatch},
+	{Name: "logs", Description: "Find idle CloudWatch Log Groups", Nameable: true, Process: processLogs},
+	{Name: "ecr", Description: "Find idle ECR repositories", Taggable: true, Nameable: true, Process: processECR},
+	{Name: "msk", Description: "Find idle/underutilized MSK clusters", Process: processMsk},
+	{Name: "opensearch", Description: "Find OpenSearch domains without search or indexing traffic", Process: processOpenSearch},
+	{Name: "secretsmanager", Description: "Find idle Secrets Manager secrets", Process: processSecretsManager},
+	{Name: "elasticache", Description: "Find idle ElastiCache serverless caches and Valkey migration candidates", Process: processElastiCache},
+	{Name: "transfer", Description: "Find Transfer Family servers without file transfers", Process: processTransfer},
+	{Name: "beanstalk", Description: "Find Elastic Beanstalk environments without load balancer traffic", Process: processBeanstalk},
+	{Name: "workspaces", Description: "Find WorkSpaces nobody has connected to recently", Process: processWorkSpaces},
+	{Name: "sagemaker", Description: "Find idle SageMaker endpoints and notebook instances", Process: processSageMaker},
+	{Name: "resolver", Description: "Find Route 53 Resolver endpoints without DNS queries", Process: processResolver},
+	{Name: "route53", Description: "Find empty private hosted zones, undelegated public zones, and unreferenced health checks", Global: true, Process: processRoute53},
+	{Name: "cloudfront", Description: "Find disabled CloudFront distributions and those without requests", Global: true, HomeRegions: aws.CloudFrontHomeRegions, Process: processCloudFront},
+	{Name: "globalaccelerator", Description: "Find disabled Global Accelerator accelerators and those without traffic", Global: true, HomeRegions: aws.GlobalAcceleratorHomeRegions, Process: processGlobalAccelerator},
 }
 
-// Common function to start scan
-func startScan(serviceName string, regions []string) (time.Time, *spinner.Spinner) {
-	// fmt.Printf("Starting %s scan in regions: %s ...\n", serviceName, strings.Join(regions, ", ")) // Keep console clean, spinner shows info
-	scanStartTime := time.Now()
-	s := startResourceSpinner(serviceName, regions) // Pass regions to spinner
-	return scanStartTime, s
+// loadConfig loads the AWS config the API clients of a region are built from
+func loadConfig(ctx context.Context, region string) (awssdk.Config, error) {
+	return aws.LoadConfig(ctx, region)
 }
 
-// Common result structure
-type ScanResult[T any] struct {
-	Data   []T
-	Err    error
-	Region string
+// newRunner creates a Runner for the regions and services resolved from flags, the config file, and the policy
+func newRunner() *runner.Runner {
+	registry := make([]runner.Service, len(serviceRegistry))
+	for i, service := range serviceRegistry {
+		service.Process = withServiceSettings(service.Name, service.Process)
+		registry[i] = service
+	}
+	return runner.New(runner.Options{
+		Regions:        regions,
+		Services:       services,
+		DefaultRegion:  defaultRegion,
+		DefaultService: DefaultService,
+		TagFiltered:    len(tagFilters) > 0,
+		NameFiltered:   nameFilter != nil,
+		IsValidRegion:  utils.IsValidRegion,
+		KnownRegions:   utils.RegionNames(),
+		Suggest:        utils.SuggestClosest,
+		Partition:      utils.PartitionForRegion,
+		LoadConfig:     loadConfig,
+		RegionTimeout:  regionTimeout,
+		Out:            out,
+	}, registry)
 }
 
-// Common function to process results
-func processResults[T any](results []ScanResult[T], scanStartTime time.Time, s *spinner.Spinner, printTable func([]T, time.Time, time.Duration), printSummary func([]T)) {
-	scanDuration := time.Since(scanStartTime)
-	var allData []T
-	for _, result := range results {
-		if result.Err == nil {
-			allData = append(allData, result.Data...)
-		}
+// run executes the scan configured by the command line flags and returns the process exit code.
+// scanServices, if set by a "scan" subcommand, replaces the services of the flags, config file and policy.
+func run(cmd *cobra.Command, scanServices []string) int {
+	// If version flag is set, print version info and exit
+	if showVersion {
+		info := version.Get() // Call Get() to retrieve build info
+		fmt.Printf("idled version %s (BuildDate: %s, GitCommit: %s, GoVersion: %s)\n",
+			info.Version, info.BuildDate, info.GitCommit, info.GoVersion)
+		return exitCodeOK
 	}
-	s.FinalMSG = fmt.Sprintf("✓ [%d items found] resources analyzed - Completed in %.2f seconds\n",
-		len(allData), scanDuration.Seconds())
-	s.Stop()
 
-	// Display API init message if any (moved here for consistency)
-	if msg := pricing.GetInitMessage(); msg != "" {
-		fmt.Println(msg)
+	// If list services flag is set, show available services and exit
+	if showServiceList {
+		newRunner().PrintServiceList(cmd.Root().Name())
+		return exitCodeOK
 	}
 
-	allData = []T{} // Reset to re-process for error display and final table
-	for _, result := range results {
-		if result.Err != nil {
-			fmt.Printf("Error in region %s: %v\n", result.Region, result.Err)
-			continue
-		}
-		allData = append(allData, result.Data...)
+	if err := setupLogging(); err != nil {
+		fmt.Printf("%v. Exiting.\n", err)
+		return exitCodeError
 	}
-	printTable(allData, scanStartTime, scanDuration)
-	printSummary(allData)
-}
 
-// Common function to handle errors
-func handleErrors(errChan <-chan error) []string {
-	var allErrors []string
-	for err := range errChan {
-		allErrors = append(allErrors, err.Error())
+	// Apply the config file before the policy, which in turn overrides it
+	if !loadConfigFile(cmd) {
+		return exitCodeError
+	}
+	if !applyServiceIdleDays(cmd) {
+		return exitCodeError
+	}
+	if err := applyProfile(); err != nil {
+		fmt.Printf("%v. Exiting.\n", err)
+		return exitCodeError
 	}
-	return allErrors
-}
 
-// Generic function to handle service-specific scan logic
-func processService[T any](
-	serviceName string, // Service name (for spinner message)
-	regions []string, // List of regions to scan
-	getDataForRegion func(region string) ([]T, error), // Function to get data for a specific region
-	printTable func([]T, time.Time, time.Duration), // Function to print results as a table
-	printSummary func([]T), // Function to print result summary
-) {
-	scanStartTime, s := startScan(serviceName, regions)
-	results := make([]ScanResult[T], len(regions))
-	var wg sync.WaitGroup
-
-	for i, region := range regions {
-		wg.Add(1)
-		go func(idx int, r string) {
-			defer wg.Done()
-			results[idx].Region = r
-			// Execute service-specific data fetching logic
-			data, err := getDataForRegion(r)
-			results[idx].Data = data
-			results[idx].Err = err
-		}(i, region)
-	}
-
-	wg.Wait()
-	// Call common result processing function
-	processResults(results, scanStartTime, s, printTable, printSummary)
-}
+	if err := validateClientFlags(); err != nil {
+		fmt.Printf("%v. Exiting.\n", err)
+		return exitCodeError
+	}
 
-// Refactor processEC2 function (using processService)
-func processEC2(regions []string) {
-	getData := func(region string) ([]models.InstanceInfo, error) {
-		client, err := aws.NewEC2Client(region)
-		if err != nil {
-			return nil, err
-		}
-		return client.GetStoppedInstances()
+	// Cancel every in-flight AWS call on Ctrl-C, SIGTERM or --timeout
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// While serving metrics, --timeout bounds each scan instead of the whole run
+	if scanTimeout > 0 && !servingMetrics() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, scanTimeout)
+		defer cancel()
 	}
-	processService("EC2", regions, getData, formatter.PrintInstancesTable, formatter.PrintInstancesSummary)
-}
 
-// Refactor processEBS function (using processService)
-func processEBS(regions []string) {
-	getData := func(region string) ([]models.VolumeInfo, error) {
-		client, err := aws.NewEBSClient(region)
-		if err != nil {
-			return nil, err
-		}
-		return client.GetAvailableVolumes()
+	// Shared API limits and the endpoint override apply to every client, including the policy
+	// and pricing clients
+	aws.SetAPILimits(maxAPIRPS, maxConcurrency)
+	aws.SetMaxRetries(maxRetries)
+	aws.SetEndpoint(endpointURL, noVerifySSL)
+	pricing.SetConfigOptions(aws.EndpointOptions()...)
+
+	// Scan the region of the environment or the selected profile when none is requested
+	defaultRegion, defaultRegionSource = aws.ResolveDefaultRegion(ctx)
+
+	// Apply the centrally managed policy before defaults are filled in
+	if !loadPolicy(ctx, cmd) {
+		return exitCodeError
+	}
+	if scanServices != nil {
+		services = scanServices
 	}
-	processService("EBS", regions, getData, formatter.PrintVolumesTable, formatter.PrintVolumesSummary)
-}
 
-// Refactor processS3 function (using processService)
-func processS3(regions []string) {
-	getData := func(region string) ([]models.BucketInfo, error) {
-		client, err := aws.NewS3Client(region)
-		if err != nil {
-			return nil, err
-		}
-		return client.GetIdleBuckets()
+	if err := validateFlags(); err != nil {
+		fmt.Printf("%v. Exiting.\n", err)
+		return exitCodeError
+	}
+	if err := applyFlags(); err != nil {
+		fmt.Printf("%v. Exiting.\n", err)
+		return exitCodeError
 	}
-	processService("S3", regions, getData, formatter.PrintBucketsTable, formatter.PrintBucketsSummary)
-}
 
-// Refactor processLambda function (using processService)
-func processLambda(regions []string) {
-	getData := func(region string) ([]models.LambdaFunctionInfo, error) {
-		client, err := aws.NewLambdaClient(region)
-		if err != nil {
-			return nil, err
-		}
-		return client.GetIdleFunctions()
+	// Tee the results to --output-file while the spinner keeps writing to the terminal.
+	// The HTML report is written to the file once the scan completes instead.
+	teePath := outputPath
+	if outputFormat == outputFormatHTML {
+		teePath = ""
+	}
+	file, err := openOutputFile(teePath, appendOutput)
+	if err != nil {
+		fmt.Printf("%v. Exiting.\n", err)
+		return exitCodeError
+	}
+	if file != nil {
+		out = io.MultiWriter(os.Stdout, file)
+		defer func() {
+			file.Close()
+			out = os.Stdout
+		}()
 	}
-	processService("Lambda", regions, getData, formatter.PrintLambdaTable, formatter.PrintLambdaSummary)
-}
 
-// Refactor processEIP function (using processService)
-func processEIP(regions []string) {
-	getData := func(region string) ([]models.EIPInfo, error) {
-		client, err := aws.NewEIPClient(region)
-		if err != nil {
-			return nil, err
-		}
-		return client.GetUnattachedEIPs()
+	// Report unknown services and invalid regions before the identity lookup and any scan
+	scanRunner := newRunner()
+	if err := scanRunner.Validate(); err != nil {
+		fmt.Fprintf(out, "%v. Exiting.\n", err)
+		return exitCodeError
+	}
+	var scannedServices []string
+	for _, service := range scanRunner.ActiveServices() {
+		scannedServices = append(scannedServices, service.Name)
+	}
+	if err := formatter.ValidateColumns(scannedServices); err != nil {
+		fmt.Fprintf(out, "%v. Exiting.\n", err)
+		return exitCodeError
 	}
-	processService("Elastic IP", regions, getData, formatter.PrintEIPsTable, formatter.PrintEIPsSummary)
-}
 
-// Refactor processECR function (using processService)
-func processECR(regions []string) {
-	getData := func(region string) ([]models.RepositoryInfo, error) {
-		client, err := aws.NewECRClient(region)
-		if err != nil {
-			return nil, err
-		}
-		return client.GetIdleRepositories()
+	// Fail fast on missing credentials instead of failing in every regional scanner
+	if err := resolveIdentity(ctx, scanRunner.ValidRegions()); err != nil {
+		fmt.Fprintf(out, "%v. Exiting.\n", err)
+		return exitCodeError
 	}
-	processService("ECR", regions, getData, formatter.PrintECRTable, formatter.PrintECRSummary)
-}
 
-// processIAM handles the scanning of IAM resources
-func processIAM(regions []string) {
-	// Pass nil for regions as IAM is global
-	scanStartTime, _ := startScan("IAM", nil)
-	// region := regions[0] // Keep original logic for client init region
-	// fmt.Printf("Note: IAM is a global service. Region parameter '%s' will be used for configuration only.\n", region)
-	client, err := aws.NewIAMClient(regions[0]) // Use the first region for client init
-	if err != nil {
-		fmt.Printf("Error initializing IAM client: %v\n", err)
-		return
+	disablePricingAPIOutsideAWSPartition()
+
+	// Reuse the prices of previous runs and save the new ones on exit, also for partial scans
+	loadPricingCache()
+	defer savePricingCache()
+
+	// Keep rescanning and serve the results as metrics until interrupted
+	if servingMetrics() {
+		return serveMetrics(ctx, scanRunner)
 	}
-	users, err := client.GetIdleUsers()
+
+	setupHistory(scannedServices, scanRunner.ValidRegions())
+	setupUpload()
+
+	// Scan each requested service in every valid region
+	results, err := scanRunner.Run(ctx)
 	if err != nil {
-		fmt.Printf("Error getting IAM users: %v\n", err)
-	} else {
-		fmt.Println("\nIAM Users:")
-		formatter.FormatIAMUserTable(os.Stdout, users)
+		fmt.Fprintf(out, "%v. Exiting.\n", err)
+		return exitCodeError
 	}
-	roles, err := client.GetIdleRoles()
-	if err != nil {
-		fmt.Printf("Error getting IAM roles: %v\n", err)
-	} else {
-		fmt.Println("\nIAM Roles:")
-		formatter.FormatIAMRoleTable(os.Stdout, roles)
+	if scanReport != nil {
+		finishReport(ctx, results)
 	}
-	policies, err := client.GetIdlePolicies()
-	if err != nil {
-		fmt.Printf("Error getting IAM policies: %v\n", err)
-	} else {
-		fmt.Println("\nIAM Policies:")
-		formatter.FormatIAMPolicyTable(os.Stdout, policies)
+	printRunReports(results)
+
+	if outputFormat == outputFormatHTML {
+		if err := writeHTMLReport(outputPath); err != nil {
+			fmt.Fprintf(out, "%v. Exiting.\n", err)
+			return exitCodeError
+		}
+		fmt.Fprintf(out, "\nHTML report written to %s\n", outputPath)
 	}
-	scanDuration := time.Since(scanStartTime)
-	fmt.Printf("\n✓ IAM resources analyzed - Completed in %.2f seconds\n\n", scanDuration.Seconds())
-}
 
-// processConfig handles the scanning of AWS Config resources
-func processConfig(regions []string) {
-	scanStartTime, s := startScan("Config", regions)
-	results := make([]struct {
-		rules     []models.ConfigRuleInfo
-		recorders []models.ConfigRecorderInfo
-		channels  []models.ConfigDeliveryChannelInfo
-		region    string
-		err       error
-	}, len(regions))
-	var wg sync.WaitGroup
-	for i, region := range regions {
-		wg.Add(1)
-		go func(idx int, r string) {
-			defer wg.Done()
-			client, err := aws.NewConfigClient(r)
-			if err != nil {
-				fmt.Printf("Error initializing AWS Config client for region %s: %v\n", r, err)
-				results[idx].err = err
-				results[idx].region = r
-				return
-			}
-			rules, err := client.GetAllConfigRules()
-			if err != nil {
-				fmt.Printf("Error getting AWS Config rules for region %s: %v\n", r, err)
-			}
-			results[idx].rules = rules
-			recorders, err := client.GetAllConfigRecorders()
-			if err != nil {
-				fmt.Printf("Error getting AWS Config recorders for region %s: %v\n", r, err)
-			}
-			results[idx].recorders = recorders
-			channels, err := client.GetAllConfigDeliveryChannels()
-			if err != nil {
-				fmt.Printf("Error getting AWS Config delivery channels for region %s: %v\n", r, err)
-			}
-			results[idx].channels = channels
-			results[idx].region = r
-		}(i, region)
-	}
-	wg.Wait()
-
-	scanDuration := time.Since(scanStartTime)
-
-	var allRules []models.ConfigRuleInfo
-	var allRecorders []models.ConfigRecorderInfo
-	var allChannels []models.ConfigDeliveryChannelInfo
-	for _, result := range results {
-		if result.err == nil {
-			allRules = append(allRules, result.rules...)
-			allRecorders = append(allRecorders, result.recorders...)
-			allChannels = append(allChannels, result.channels...)
+	// Commands for the resources found so far are valid even if the scan was cut short
+	if cleanupScript != nil {
+		if err := writeCleanupScript(cleanupPath); err != nil {
+			fmt.Fprintf(out, "%v. Exiting.\n", err)
+			return exitCodeError
 		}
+		fmt.Fprintf(out, "\nCleanup script for %d resources written to %s (review it before running; idled does not run it)\n",
+			cleanupScript.Len(), cleanupPath)
 	}
-	totalCount := len(allRules) + len(allRecorders) + len(allChannels)
-	s.FinalMSG = fmt.Sprintf("✓ [%d resources found] AWS Config resources analyzed - Completed in %.2f seconds\n",
-		totalCount, scanDuration.Seconds())
-	s.Stop()
-	allRules = []models.ConfigRuleInfo{}
-	allRecorders = []models.ConfigRecorderInfo{}
-	allChannels = []models.ConfigDeliveryChannelInfo{}
-	for _, result := range results {
-		if result.err != nil {
-			fmt.Printf("Error in region %s: %v\n", result.region, result.err)
-			continue
+
+	// Results above are partial when the scan was cut short
+	if err := ctx.Err(); err != nil {
+		reason := "interrupted"
+		if errors.Is(err, context.DeadlineExceeded) {
+			reason = fmt.Sprintf("timed out after %s", scanTimeout)
 		}
-		allRules = append(allRules, result.rules...)
-		allRecorders = append(allRecorders, result.recorders...)
-		allChannels = append(allChannels, result.channels...)
-	}
-	if len(allRules) > 0 {
-		fmt.Println("\nAWS Config Rules:")
-		formatter.FormatConfigRulesTable(os.Stdout, allRules)
-	} else {
-		fmt.Println("\nNo AWS Config rules found.")
-	}
-	if len(allRecorders) > 0 {
-		fmt.Println("\nAWS Config Recorders:")
-		formatter.FormatConfigRecordersTable(os.Stdout, allRecorders)
-	} else {
-		fmt.Println("\nNo AWS Config recorders found.")
-	}
-	if len(allChannels) > 0 {
-		fmt.Println("\nAWS Config Delivery Channels:")
-		formatter.FormatConfigDeliveryChannelsTable(os.Stdout, allChannels)
-	} else {
-		fmt.Println("\nNo AWS Config delivery channels found.")
-	}
-	fmt.Printf("\n✓ AWS Config resources analyzed - Completed in %.2f seconds\n\n", scanDuration.Seconds())
-}
+		fmt.Fprintf(out, "\n⚠️  Scan %s: results above are partial.\n", reason)
+		return exitCodeError
+	}
+
+	// Partial scans are not saved, so the next diff does not report their missing resources as resolved
+	saveHistory()
 
-// Refactor processELB function (using processService)
-func processELB(regions []string) {
-	getData := func(region string) ([]models.ELBResource, error) {
-		cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-		if err != nil {
-			return nil, fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
+	if pushGatewayURL != "" {
+		if err := pushMetrics(results); err != nil {
+			fmt.Fprintf(out, "%v. Exiting.\n", err)
+			return exitCodeError
 		}
-		scanner := aws.NewELBScanner(cfg)
-		return scanner.GetIdleELBs(context.TODO(), region)
 	}
-	// PrintELBTable, PrintELBSummary need os.Stdout -> use anonymous functions
-	printTable := func(data []models.ELBResource, _ time.Time, _ time.Duration) {
-		formatter.PrintELBTable(os.Stdout, data)
+
+	// The upload is the output of automated runs, so a failed upload fails the run
+	if err := uploadResults(ctx, results); err != nil {
+		fmt.Fprintf(out, "%v. Exiting.\n", err)
+		return exitCodeError
 	}
-	printSummary := func(data []models.ELBResource) {
-		formatter.PrintELBSummary(os.Stdout, data)
+
+	notifySlack(ctx, results)
+
+	// Deletion only runs on complete scan results
+	if deleteMode {
+		deleteIdleResources(ctx)
 	}
-	processService("ELB (v2)", regions, getData, printTable, printSummary)
-}
 
-// processLogs handles the scanning of CloudWatch Log Groups, aligned with EC2 flow
-func processLogs(regions []string) {
-	scanStartTime, s := startScan("Logs", regions)
-	var allLogGroups []models.LogGroupInfo
-	var mu sync.Mutex
-	errChan := make(chan error, len(regions)*2)
-	var wg sync.WaitGroup
-	for _, region := range regions {
-		wg.Add(1)
-		go func(r string) {
-			defer wg.Done()
-			cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(r))
-			if err != nil {
-				errChan <- fmt.Errorf("failed to load config for region %s: %w", r, err)
-				return
-			}
-			idleThreshold := 90
-			logGroups, scanErrs := aws.ScanLogGroups(cfg, idleThreshold)
-			if len(logGroups) > 0 {
-				mu.Lock()
-				allLogGroups = append(allLogGroups, logGroups...)
-				mu.Unlock()
-			}
-			if len(scanErrs) > 0 {
-				for _, scanErr := range scanErrs {
-					errChan <- fmt.Errorf("region %s: %w", r, scanErr)
-				}
-			}
-		}(region)
-	}
-	go func() {
-		wg.Wait()
-		close(errChan)
-	}()
-	allErrors := handleErrors(errChan)
-	scanDuration := time.Since(scanStartTime)
-	s.FinalMSG = fmt.Sprintf("✓ [%d Log Groups found] Logs resources analyzed - Completed in %.2f seconds\n",
-		len(allLogGroups), scanDuration.Seconds())
-	s.Stop()
-	if len(allErrors) > 0 {
-		fmt.Printf("\nErrors during CloudWatch Logs scan:\n")
-		for _, errMsg := range allErrors {
-			fmt.Printf(" - %s\n", errMsg)
-		}
-		fmt.Println()
+	if failOnFindings {
+		fmt.Fprintf(out, "\nFindings: %d idle resources, estimated monthly cost $%.2f\n", outcome.idleCount, outcome.monthlyCost)
+	}
+	if err := file.Close(); err != nil {
+		fmt.Printf("%v. Exiting.\n", err)
+		return exitCodeError
 	}
-	formatter.PrintLogGroupsTable(allLogGroups)
+	return outcome.exitCode()
 }
 
-// processMsk processes MSK clusters (added previously)
-func processMsk(regions []string) {
-	getData := func(region string) ([]models.MskClusterInfo, error) {
-		cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-		if err != nil {
-			return nil, fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
+// printRunReports prints the combined savings report, the skipped resources, and API statistics once after
+// all services are processed. With --output markdown the collected report, which lists the API calls, is
+// printed instead of the savings, pricing API, and API call tables.
+func printRunReports(results []runner.Result) {
+	if outputFormat == outputFormatMarkdown {
+		if err := scanReport.WriteMarkdown(out); err != nil {
+			fmt.Fprintf(out, "%v\n", err)
 		}
-		scanner := aws.NewMskScanner(cfg)
-		// Modify to handle []error return type
-		data, errs := scanner.GetIdleMskClusters(context.TODO())
-		if len(errs) > 0 {
-			// Combine multiple errors into a single error message
-			var errorMessages []string
-			for _, e := range errs {
-				errorMessages = append(errorMessages, e.Error())
-			}
-			return data, fmt.Errorf("encountered %d error(s) during MSK scan: %s", len(errs), strings.Join(errorMessages, "; "))
-		}
-		return data, nil
+		formatter.PrintSkippedResources(out, aws.SkippedResources(), verbose)
+		formatter.PrintAPIErrorStats(out, aws.APIErrorCount(), aws.ThrottledRequestCount())
+		return
+	}
+	formatter.PrintSavingsReport(out, costSummaries(results))
+	if alertThresholds.Enabled() {
+		formatter.PrintAlerts(out, alert.Evaluate(costSummaries(results), alertThresholds))
 	}
-	processService("MSK", regions, getData, formatter.PrintMskTable, formatter.PrintMskSummary)
+	formatter.PrintSkippedResources(out, aws.SkippedResources(), verbose)
+	formatter.PrintPricingAPIStats(out)
+	formatter.PrintAPICallStats(out, aws.APICallStats(), results, verbose)
+	formatter.PrintAPIErrorStats(out, aws.APIErrorCount(), aws.ThrottledRequestCount())
 }
 
-// processSecretsManager processes Secrets Manager secrets
-func processSecretsManager(regions []string) {
-	getData := func(region string) ([]models.SecretInfo, error) {
-		cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-		if err != nil {
-			return nil, fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
-		}
-		scanner := aws.NewSecretsManagerScanner(cfg)
-		// Modify to handle []error return type
-		data, errs := scanner.GetIdleSecrets(context.TODO())
-		if len(errs) > 0 {
-			// Combine multiple errors into a single error message
-			var errorMessages []string
-			for _, e := range errs {
-				errorMessages = append(errorMessages, e.Error())
-			}
-			return data, fmt.Errorf("encountered %d error(s) during Secrets Manager scan: %s", len(errs), strings.Join(errorMessages, "; "))
-		}
-		return data, nil
-	}
-	// TODO: Create formatter.PrintSecretsTable and formatter.PrintSecretsSummary
-	processService("SecretsManager", regions, getData, formatter.PrintSecretsTable, formatter.PrintSecretsSummary)
+func main() {
+	os.Exit(execute(newRootCommand()))
 }
 
-// min returns the smaller of x or y
-func min(x, y int) int {
-	if x < y {
-		return x
+// execute runs the command selected by the command line and returns the exit code of a
+// command that failed. Scans exit with their own exit code unless they succeed.
+func execute(rootCmd *cobra.Command) int {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(out, err)
+		return exitCodeError
 	}
-	return y
+	return exitCodeOK
 }
 
-func main() {
-	var showServiceList bool
-
+// newRootCommand creates the idled command with its subcommands and flags
+func newRootCommand() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "idled",
 		Short: "CLI tool to find idle AWS resources",
 		Long: `idled is a CLI tool that searches for idle AWS resources
-and displays the results in a table format.`,
+and displays the results in a table format.
+
+Scan a single service with "idled scan <service>" or every service with
+"idled scan all". See "idled list-services" for the supported services.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			// If version flag is set, print version info and exit
-			if showVersion {
-				info := version.Get() // Call Get() to retrieve build info
-				fmt.Printf("idled version %s (BuildDate: %s, GitCommit: %s, GoVersion: %s)\n",
-					info.Version, info.BuildDate, info.GitCommit, info.GoVersion)
-				return
-			}
-
-			// If list services flag is set, show available services and exit
-			if showServiceList {
-				fmt.Println("Available services:")
-
-				// Get a sorted list of supported services for consistent output
-				var serviceList []string
-				for service, isSupported := range supportedServices {
-					if isSupported {
-						serviceList = append(serviceList, service)
-					}
-				}
-				sort.Strings(serviceList)
-
-				// Define default services here as well for checking
-				defaultServices := []string{DefaultService}
-
-				// Print each service with its description
-				for _, service := range serviceList {
-					description, ok := serviceDescriptions[service]
-					if !ok {
-						description = "No description available"
-					}
-					// Check if the service is a default service
-					isDefault := false
-					for _, ds := range defaultServices {
-						if service == ds {
-							isDefault = true
-							break
-						}
-					}
-
-					if isDefault {
-						fmt.Printf("  %-8s - %s (default)\n", service, description)
-					} else {
-						fmt.Printf("  %-8s - %s\n", service, description)
-					}
-				}
-
-				fmt.Println("\nExample usage:")
-				fmt.Printf("  %s --services %s\n", os.Args[0], strings.Join(serviceList[:min(3, len(serviceList))], ","))
-				return
-			}
-
-			// Use default region if none specified
-			if len(regions) == 0 {
-				regions = []string{utils.GetDefaultRegion()}
-			}
-
-			// Validate regions
-			var validRegions []string
-			for _, region := range regions {
-				if utils.IsValidRegion(region) {
-					validRegions = append(validRegions, region)
-				} else {
-					fmt.Printf("Warning: Skipping invalid region '%s'\n", region)
-				}
-			}
-
-			if len(validRegions) == 0 {
-				fmt.Println("No valid regions specified. Exiting.")
-				return
-			}
-
-			// Use default service if none specified
-			if len(services) == 0 {
-				services = []string{DefaultService}
-			}
-
-			// Validate services
-			for _, service := range services {
-				supported, exists := supportedServices[service]
-				if !exists {
-					fmt.Printf("Warning: Unknown service '%s'\n", service)
-					continue
-				}
-				if !supported {
-					fmt.Printf("Warning: Service '%s' is not yet implemented\n", service)
-				}
-			}
-
-			// Only process supported services
-			var activeServices []string
-			for _, service := range services {
-				if supported, exists := supportedServices[service]; exists && supported {
-					activeServices = append(activeServices, service)
-				}
-			}
-
-			if len(activeServices) == 0 {
-				fmt.Println("No supported services specified. Exiting.")
-				return
-			}
-
-			// Process each service
-			for _, service := range activeServices {
-				switch service {
-				case "ec2":
-					processEC2(validRegions)
-				case "ebs":
-					processEBS(validRegions)
-				case "s3":
-					processS3(validRegions)
-				case "lambda":
-					processLambda(validRegions)
-				case "eip":
-					processEIP(validRegions)
-				case "iam":
-					processIAM(validRegions)
-				case "config":
-					processConfig(validRegions)
-				case "elb":
-					processELB(validRegions)
-				case "logs":
-					processLogs(validRegions)
-				case "ecr":
-					processECR(validRegions)
-				case "secretsmanager":
-					processSecretsManager(validRegions)
-				default:
-					fmt.Printf("Service '%s' is not supported.\n", service)
-				}
-			}
-
-			// Print combined pricing API statistics once after all services are processed
-			formatter.PrintPricingAPIStats()
+			runAndExit(cmd, nil)
 		},
 	}
 
+	rootCmd.AddCommand(newScanCommand(), newListServicesCommand(), newConfigCommand(), newServeCommand())
+
+	// Flags shared by the root command and every subcommand
+	flags := rootCmd.PersistentFlags()
+
+	// Config file and AWS profile flags
+	flags.StringVar(&configPath, "config", "",
+		"Config file with default settings (default: ./idled.yaml, then ~/.idled.yaml)")
+	flags.StringVar(&awsProfile, "profile", "",
+		"AWS shared config profile to use")
+
 	// Version flag
 	rootCmd.Flags().BoolVarP(&showVersion, "version", "v", false, "Show version information")
 
-	// Service list flag (show available services)
+	// Service list flag (show available services), superseded by the list-services command
 	rootCmd.Flags().BoolVarP(&showServiceList, "list-services", "l", false, "List available services")
-
-	// Initialize default regions
-	defaultRegions := []string{utils.GetDefaultRegion()}
+	_ = rootCmd.Flags().MarkDeprecated("list-services", "use 'idled list-services' instead")
 
 	// Region flags (long and short forms)
-	rootCmd.Flags().StringSliceVarP(&regions, "regions", "r", nil,
-		fmt.Sprintf("AWS regions to check (comma separated, default: %s)", strings.Join(defaultRegions, ", ")))
+	flags.StringSliceVarP(&regions, "regions", "r", nil,
+		fmt.Sprintf("AWS regions to check (comma separated, default: AWS_REGION, AWS_DEFAULT_REGION, the profile region, or %s)", utils.GetDefaultRegion()))
 
 	// Initialize default services
 	defaultServices := []string{DefaultService}
 
-	// Service flags (long and short forms)
+	// Service flags (long and short forms), superseded by the scan subcommands
 	rootCmd.Flags().StringSliceVarP(&services, "services", "s", nil,
 		fmt.Sprintf("AWS services to check (comma separated, default: %s)", strings.Join(defaultServices, ", ")))
-
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
+	_ = rootCmd.Flags().MarkDeprecated("services", "use 'idled scan <service>' or 'idled scan all' instead")
+
+	// Service-specific flags, kept on the root command for existing scripts
+	addAllServiceFlags(rootCmd.Flags())
+
+	// Hide non-idle resources from tables while keeping summary totals
+	flags.BoolVar(&onlyIdle, "only-idle", false,
+		"Only show resources flagged as idle (summary totals still include all resources)")
+
+	// Centrally managed policy sources
+	flags.StringVar(&policySSMParam, "policy-ssm-parameter", "",
+		"SSM parameter holding the JSON policy document (e.g., /org/idled/policy)")
+	flags.StringVar(&policyAppConfig, "policy-appconfig", "",
+		"AppConfig configuration holding the JSON policy document (application/environment/profile)")
+
+	// Tag filters (repeatable)
+	flags.StringArrayVar(&tagArgs, "tag", nil,
+		"Only scan resources carrying this tag (key=value, repeatable)")
+
+	// Resource name filters (repeatable), applied before the per-resource analysis
+	flags.StringArrayVar(&includeArgs, "include", nil,
+		"Only scan resources whose name matches this glob, or /regex/ (repeatable)")
+	flags.StringArrayVar(&excludeArgs, "exclude", nil,
+		"Skip resources whose name matches this glob, or /regex/ (repeatable)")
+
+	// Tag columns appended to the tables of taggable resources
+	flags.StringSliceVar(&showTags, "show-tags", nil,
+		"Tag keys to show as extra table columns (comma separated, e.g., Team,Owner)")
+	flags.BoolVar(&showLinks, "show-links", false,
+		"Append a CONSOLE URL column with links to the AWS console")
+
+	// Column selection and ordering of the tables
+	flags.StringSliceVar(&columnArgs, "columns", nil,
+		"Table columns to show, in order (comma separated, e.g., name,region,cost)")
+	flags.StringVar(&sortBy, "sort-by", "",
+		"Sort table rows by this column, numeric columns descending (append :asc or :desc to override)")
+
+	// Copy of the rendered results written to a file
+	flags.StringVar(&outputPath, "output-file", "",
+		"Also write the results to this file (the spinner stays on the terminal)")
+	flags.StringVarP(&outputFormat, "output", "o", outputFormatTable,
+		"Output format: table, markdown, or html (html requires --output-file)")
+	flags.StringVar(&cleanupPath, "generate-cleanup-script", "",
+		"Write a shell script with commented aws-cli delete commands for the idle resources (never run by idled)")
+	flags.BoolVar(&deleteMode, "delete", false,
+		"After the scan, interactively delete unattached EBS volumes, unassociated Elastic IPs, and empty idle S3 buckets")
+	flags.BoolVar(&assumeYes, "yes", false,
+		"Delete every --delete candidate without prompting")
+	flags.BoolVar(&diffMode, "diff", false,
+		"Show the idle resources that are new or resolved since the previous scan in ~/.idled/history")
+	flags.BoolVar(&diffOnly, "diff-only", false,
+		"Print only the changes since the previous scan instead of the full tables (implies --diff)")
+	flags.BoolVar(&noHistory, "no-history", false,
+		"Do not save a snapshot of this scan to ~/.idled/history")
+	flags.BoolVar(&appendOutput, "append", false,
+		"Append to --output-file with a timestamped header instead of overwriting it")
+
+	// Prometheus metrics export
+	flags.StringVar(&exportFormat, "export", "",
+		"Serve the results as metrics and rescan every --interval (supported: prometheus)")
+	flags.StringVar(&listenAddr, "listen", ":9090",
+		"Address serving /metrics with --export prometheus")
+	flags.DurationVar(&scanInterval, "interval", 6*time.Hour,
+		"Time between scans with --export prometheus")
+	flags.StringVar(&pushGatewayURL, "push-gateway", "",
+		"Push the metrics of a single scan to this Prometheus Pushgateway URL")
+
+	// Slack digest posted after the scan
+	flags.StringVar(&slackWebhookURL, "slack-webhook-url", "",
+		"Post a digest of the findings to this Slack incoming webhook (default: $"+slackWebhookEnv+")")
+	flags.BoolVar(&notifyIfFindings, "notify-only-if-findings", false,
+		"Only post the Slack digest when idle resources were found")
+
+	// Structured results uploaded to S3 after the scan
+	flags.StringVar(&uploadS3URI, "upload-s3", "",
+		"Upload the results as JSON to this S3 location after the scan (e.g., s3://bucket/prefix/)")
+	flags.StringVar(&uploadKMSKeyID, "upload-kms-key-id", "",
+		"Encrypt the --upload-s3 object with SSE-KMS using this KMS key ID or ARN")
+
+	// Per-region subtotals in tables and region breakdowns in summaries
+	flags.StringVar(&groupBy, "group-by", "",
+		"Group table rows with subtotals and break down summaries (supported: region)")
+
+	// Shared API rate limits across all regions and services (0 disables)
+	flags.Float64Var(&maxAPIRPS, "max-api-rps", 50,
+		"Maximum AWS API requests per second across all regions and services (0 for unlimited)")
+	flags.IntVar(&maxConcurrency, "max-concurrency", 16,
+		"Maximum AWS API requests in flight at once across all regions and services (0 for unlimited)")
+	flags.IntVar(&maxRetries, "max-retries", aws.DefaultMaxRetries,
+		"Retries of a throttled or failed AWS API call with adaptive exponential backoff, after the first attempt")
+
+	// Endpoint override of every AWS client, e.g. LocalStack
+	flags.StringVar(&endpointURL, "endpoint-url", "",
+		"Send every AWS API call to this endpoint instead of the AWS endpoints, e.g. http://localhost:4566 for LocalStack (AWS_ENDPOINT_URL_<SERVICE> still overrides a service)")
+	flags.BoolVar(&noVerifySSL, "no-verify-ssl", false,
+		"Do not verify the TLS certificates of AWS endpoints, for self-signed local endpoints")
+
+	// Exit codes for CI usage
+	flags.BoolVar(&failOnFindings, "fail-on-findings", false,
+		"Exit with --findings-exit-code when idle resources are found")
+	flags.IntVar(&findingsExitCode, "findings-exit-code", defaultFindingsExitCode,
+		"Exit code used by --fail-on-findings (must be greater than 1)")
+	flags.Float64Var(&failThresholdCost, "fail-threshold-cost", 0,
+		"With --fail-on-findings, only fail when the estimated monthly cost of idle resources exceeds this amount in USD")
+
+	// Budget-style alerts printed after the savings report
+	flags.Float64Var(&alertThresholds.CostPerResource, "alert-cost-per-resource", 0,
+		"Mark idle resources whose estimated monthly cost exceeds this amount in USD and list them under Alerts (0 disables)")
+	flags.Float64Var(&alertThresholds.TotalCost, "alert-total-cost", 0,
+		"Warn when the estimated monthly cost of idle resources across all services exceeds this amount in USD (0 disables)")
+
+	// Diagnostics on stderr
+	flags.BoolVarP(&verbose, "verbose", "V", false,
+		"Show warnings and debug messages on stderr")
+	flags.BoolVarP(&quiet, "quiet", "q", false,
+		"Hide the scan progress and all warnings and errors on stderr, only print the results")
+
+	// Plain progress lines for CI logs (automatic when stdout is not a terminal)
+	flags.BoolVar(&noSpinner, "no-spinner", false,
+		"Print plain progress lines instead of a spinner (the default when stdout is not a terminal)")
+
+	// Overall scan deadline (0 disables)
+	flags.DurationVar(&scanTimeout, "timeout", 0,
+		"Abort the scan after this duration and print partial results (e.g., 10m, 0 for no limit)")
+	// Deadline of each service in each region, so one hung region does not block the scan (0 disables)
+	flags.DurationVar(&regionTimeout, "region-timeout", defaultRegionTimeout,
+		"Stop scanning a service in a region after this duration, keeping its partial results (0 for no limit)")
+
+	// Pricing API usage, for air-gapped environments and slow egress
+	flags.BoolVar(&noPricingAPI, "no-pricing-api", false,
+		"Do not call the AWS Pricing API and use the bundled default prices (pricing source Default)")
+	flags.DurationVar(&pricingAPITimeout, "pricing-api-timeout", pricing.DefaultAPITimeout,
+		"Timeout of each AWS Pricing API call")
+	flags.StringVar(&pricingRegion, "pricing-region", "",
+		"AWS Pricing API endpoint region: "+strings.Join(pricing.Regions, ", ")+" (default: $"+pricingRegionEnv+", then "+pricing.DefaultRegion+")")
+
+	// Prices kept on disk between runs
+	flags.BoolVar(&noPricingCache, "no-pricing-cache", false,
+		"Do not read or write the pricing cache in ~/.idled/pricing-cache.json")
+	flags.DurationVar(&pricingCacheTTL, "pricing-cache-ttl", pricing.DefaultDiskCacheTTL,
+		"How long prices in the pricing cache are reused before querying the AWS Pricing API again")
+
+	// Minimum idle age filter applied to every service before output
+	flags.IntVar(&minIdleDays, "min-idle-days", 0,
+		"Only show resources idle for at least this many days (0 shows all)")
+
+	return rootCmd
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/runner"
+	"github.com/younsl/idled/pkg/aws"
+	"github.com/younsl/idled/pkg/formatter"
+)
+
+func TestProcessResultsReportsTimedOutRegions(t *testing.T) {
+	savedOut, savedOutcome := out, outcome
+	t.Cleanup(func() { out, outcome = savedOut, savedOutcome })
+	var output bytes.Buffer
+	out = &output
+
+	timeout := errors.New("scan timed out after 10m0s, partial results kept")
+	results := []runner.RegionResult[models.EIPInfo]{
+		{Region: "us-east-1", Data: []models.EIPInfo{{AllocationID: "eipalloc-1", Region: "us-east-1"}}},
+		{Region: "sa-east-1", Data: []models.EIPInfo{{AllocationID: "eipalloc-2", Region: "sa-east-1"}}, Err: timeout, TimedOut: true},
+		{Region: "eu-west-1", Err: timeout, TimedOut: true},
+	}
+	progress := &scanProgress{silent: true, skippedStart: aws.SkippedCount()}
+
+	summaries := processResults(context.Background(), "Elastic IP", results, time.Now(), progress,
+		nil, formatter.PrintEIPsTable, formatter.PrintEIPsSummary)
+
+	if !strings.Contains(progress.FinalMSG, "[2 found, 0 skipped]") || !strings.Contains(progress.FinalMSG, "2 of 3 regions timed out") {
+		t.Errorf("FinalMSG = %q, want the partial count and 2 of 3 regions timed out", progress.FinalMSG)
+	}
+	for _, id := range []string{"eipalloc-1", "eipalloc-2"} {
+		if !strings.Contains(output.String(), id) {
+			t.Errorf("table does not show %s:\n%s", id, output.String())
+		}
+	}
+	if !strings.Contains(output.String(), "scan timed out after 10m0s") {
+		t.Errorf("output does not report the timeout:\n%s", output.String())
+	}
+	if len(summaries) != 3 {
+		t.Errorf("got %d summaries, want one per region", len(summaries))
+	}
+}
+
+func TestScanServiceKeepsRequestedRegionOrder(t *testing.T) {
+	savedOut, savedOutcome, savedQuiet := out, outcome, quiet
+	t.Cleanup(func() { out, outcome, quiet = savedOut, savedOutcome, savedQuiet })
+	out = &bytes.Buffer{}
+	quiet = true
+
+	// Regions finish in the reverse of the requested order and return their resources unsorted
+	scope := runner.Scope{
+		Regions: []string{"us-west-2", "eu-west-1", "us-east-1"},
+		LoadConfig: func(ctx context.Context, region string) (awssdk.Config, error) {
+			return awssdk.Config{Region: region}, nil
+		},
+	}
+	delays := map[string]time.Duration{"us-west-2": 30 * time.Millisecond, "eu-west-1": 15 * time.Millisecond}
+	scan := func(ctx context.Context, cfg awssdk.Config) ([]models.EIPInfo, error) {
+		time.Sleep(delays[cfg.Region])
+		return []models.EIPInfo{
+			{AllocationID: "eipalloc-b", Region: cfg.Region},
+			{AllocationID: "eipalloc-a", Region: cfg.Region},
+		}, nil
+	}
+	var shown []string
+	printTable := func(w io.Writer, eips []models.EIPInfo, _ time.Time, _ time.Duration) {
+		for _, eip := range eips {
+			shown = append(shown, eip.Region+"/"+eip.AllocationID)
+		}
+	}
+
+	summaries := scanService(context.Background(), "Elastic IP", scope, scan, nil, printTable, nil)
+
+	want := []string{
+		"us-west-2/eipalloc-a", "us-west-2/eipalloc-b",
+		"eu-west-1/eipalloc-a", "eu-west-1/eipalloc-b",
+		"us-east-1/eipalloc-a", "us-east-1/eipalloc-b",
+	}
+	if !slices.Equal(shown, want) {
+		t.Errorf("table rows = %v, want %v", shown, want)
+	}
+	var regions []string
+	for _, summary := range summaries {
+		regions = append(regions, summary.Region)
+	}
+	if !slices.Equal(regions, scope.Regions) {
+		t.Errorf("summaries for %v, want %v", regions, scope.Regions)
+	}
+}
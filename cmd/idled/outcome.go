@@ -0,0 +1,66 @@
+package main
+
+import "github.com/younsl/idled/internal/models"
+
+// Process exit codes
+const (
+	exitCodeOK    = 0 // No idle resources found, or findings are not treated as failures
+	exitCodeError = 1 // Invalid flags, a failed region or service scan, or an interrupted scan
+
+	// defaultFindingsExitCode is returned with --fail-on-findings when idle resources are found
+	defaultFindingsExitCode = 3
+)
+
+var (
+	failOnFindings    bool    // --fail-on-findings
+	findingsExitCode  int     // --findings-exit-code
+	failThresholdCost float64 // --fail-threshold-cost
+)
+
+// scanOutcome aggregates findings and errors across all scanned services
+type scanOutcome struct {
+	idleCount   int
+	monthlyCost float64
+	errorCount  int
+}
+
+// outcome collects the results of the current run for the exit code
+var outcome scanOutcome
+
+// recordFindings adds the idle items and their estimated monthly cost to the outcome.
+// Resource groups are expanded into their individual resources.
+func recordFindings[T any](o *scanOutcome, items []T) {
+	for _, item := range items {
+		if group, ok := any(item).(models.ResourceGroup); ok {
+			recordFindings(o, group.Resources())
+			continue
+		}
+		if !isIdle(item) {
+			continue
+		}
+		o.idleCount++
+		if costed, ok := any(item).(models.Costed); ok {
+			o.monthlyCost += costed.MonthlyCost()
+		}
+	}
+}
+
+// recordErrors adds the number of failed region or service scans to the outcome
+func (o *scanOutcome) recordErrors(n int) {
+	o.errorCount += n
+}
+
+// exitCode maps the outcome to the process exit code. Scan errors take precedence over findings.
+// With --fail-threshold-cost set, findings only fail the run when their estimated monthly cost exceeds it.
+func (o *scanOutcome) exitCode() int {
+	if o.errorCount > 0 {
+		return exitCodeError
+	}
+	if !failOnFindings || o.idleCount == 0 {
+		return exitCodeOK
+	}
+	if failThresholdCost > 0 && o.monthlyCost <= failThresholdCost {
+		return exitCodeOK
+	}
+	return findingsExitCode
+}
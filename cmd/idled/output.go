@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// out receives the rendered results. It is stdout, or stdout and --output-file while a scan runs.
+var out io.Writer = os.Stdout
+
+var (
+	outputPath   string   // --output-file
+	outputFormat string   // --output
+	appendOutput bool     // --append
+	showTags     []string // --show-tags
+	showLinks    bool     // --show-links
+	columnArgs   []string // --columns
+	sortBy       string   // --sort-by
+	groupBy      string   // --group-by
+)
+
+// outputFile receives a copy of the rendered results and records the first write error,
+// so that a failed write fails the run without cutting the terminal output short
+type outputFile struct {
+	path string
+	file *os.File
+	err  error
+}
+
+// openOutputFile opens the --output-file for writing, truncating it unless appendMode is set.
+// In append mode a timestamped header separates the runs. It returns nil if path is empty.
+func openOutputFile(path string, appendMode bool) (*outputFile, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if appendMode {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %w", err)
+	}
+
+	o := &outputFile{path: path, file: file}
+	if appendMode {
+		fmt.Fprintf(o, "\n===== idled run at %s (services: %s, regions: %s) =====\n",
+			time.Now().Format(time.RFC3339), strings.Join(services, ","), strings.Join(regions, ","))
+	}
+	return o, nil
+}
+
+// Write writes p to the file. After the first error, further writes are dropped.
+// It always reports success so that io.MultiWriter keeps writing to the terminal.
+func (o *outputFile) Write(p []byte) (int, error) {
+	if o.err == nil {
+		if _, err := o.file.Write(p); err != nil {
+			o.err = fmt.Errorf("failed to write output file %s: %w", o.path, err)
+		}
+	}
+	return len(p), nil
+}
+
+// Close closes the file and returns the first write or close error.
+// It is safe to call on a nil or already closed outputFile.
+func (o *outputFile) Close() error {
+	if o == nil || o.file == nil {
+		return nil
+	}
+	err := o.file.Close()
+	o.file = nil
+	if o.err != nil {
+		return o.err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to close output file %s: %w", o.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/younsl/idled/internal/policy"
+	"github.com/younsl/idled/pkg/aws"
+)
+
+var (
+	policySSMParam   string   // --policy-ssm-parameter
+	policyAppConfig  string   // --policy-appconfig
+	ignoredResources []string // Resources on the ignore list of the policy
+)
+
+// policyFlags are the flags of the policy keys
+var policyFlags = map[string]string{
+	"regions":     "regions",
+	"services":    "services",
+	"minIdleDays": "min-idle-days",
+	"onlyIdle":    "only-idle",
+}
+
+// policyFetcher fetches policy documents, replaced in tests
+type policyFetcher interface {
+	GetSSMPolicy(ctx context.Context, parameterName string) (*policy.Policy, policy.Source, error)
+	GetAppConfigPolicy(ctx context.Context, identifier string) (*policy.Policy, policy.Source, error)
+}
+
+// newPolicyFetcher creates the client fetching the policy from a region
+var newPolicyFetcher = func(ctx context.Context, region string) (policyFetcher, error) {
+	return aws.NewPolicyClient(ctx, region)
+}
+
+// loadPolicy fetches the centrally managed policy from SSM Parameter Store or AppConfig and
+// applies it to every setting whose flag was not explicitly set. Fetch or validation failures
+// fall back to the defaults with a warning. It returns false if the policy options are invalid.
+func loadPolicy(ctx context.Context, cmd *cobra.Command) bool {
+	if policySSMParam == "" && policyAppConfig == "" {
+		return true
+	}
+	if policySSMParam != "" && policyAppConfig != "" {
+		fmt.Fprintln(out, "Only one of --policy-ssm-parameter and --policy-appconfig can be set. Exiting.")
+		return false
+	}
+
+	// Fetch the policy from the first requested region, or the default region
+	region := defaultRegion
+	if len(regions) > 0 {
+		region = regions[0]
+	}
+
+	var p *policy.Policy
+	var source policy.Source
+	client, err := newPolicyFetcher(ctx, region)
+	if err == nil {
+		if policySSMParam != "" {
+			p, source, err = client.GetSSMPolicy(ctx, policySSMParam)
+		} else {
+			p, source, err = client.GetAppConfigPolicy(ctx, policyAppConfig)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(out, "\n⚠️  WARNING: Failed to load policy, continuing with defaults: %v\n\n", err)
+		return true
+	}
+
+	flags := cmd.Flags()
+	isSet := func(key string) bool { return flags.Changed(policyFlags[key]) }
+	settings := policy.Settings{Regions: regions, Services: services, MinIdleDays: minIdleDays, OnlyIdle: onlyIdle}
+	p.Apply(&settings, isSet)
+	regions, services = settings.Regions, settings.Services
+	minIdleDays, onlyIdle = settings.MinIdleDays, settings.OnlyIdle
+	ignoredResources = settings.Ignore
+
+	printPolicyCriteria(p, source, settings, isSet)
+	return true
+}
+
+// printPolicyCriteria prints the policy source and the effective value of every setting the
+// policy sets, noting the flags that took precedence over it
+func printPolicyCriteria(p *policy.Policy, source policy.Source, s policy.Settings, isSet func(key string) bool) {
+	fmt.Fprintf(out, "Policy: %s\n", source)
+	if p.Version != "" {
+		fmt.Fprintf(out, "  Policy version: %s\n", p.Version)
+	}
+
+	origin := func(key string) string {
+		if isSet(key) {
+			return "--" + policyFlags[key]
+		}
+		return "policy"
+	}
+	if len(p.Regions) > 0 {
+		fmt.Fprintf(out, "  Regions:        %s (%s)\n", strings.Join(s.Regions, ", "), origin("regions"))
+	}
+	if len(p.Services) > 0 {
+		fmt.Fprintf(out, "  Services:       %s (%s)\n", strings.Join(s.Services, ", "), origin("services"))
+	}
+	if p.MinIdleDays != nil {
+		fmt.Fprintf(out, "  Min idle days:  %d (%s)\n", s.MinIdleDays, origin("minIdleDays"))
+	}
+	if p.OnlyIdle != nil {
+		fmt.Fprintf(out, "  Only idle:      %t (%s)\n", s.OnlyIdle, origin("onlyIdle"))
+	}
+	if len(s.Ignore) > 0 {
+		fmt.Fprintf(out, "  Ignored:        %s (policy)\n", strings.Join(s.Ignore, ", "))
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/younsl/idled/internal/policy"
+)
+
+// fakePolicyFetcher returns a fixed policy, or err, from either source
+type fakePolicyFetcher struct {
+	policy *policy.Policy
+	err    error
+	region string
+}
+
+func (f *fakePolicyFetcher) GetSSMPolicy(ctx context.Context, parameterName string) (*policy.Policy, policy.Source, error) {
+	return f.policy, policy.Source{Kind: "SSM", Location: parameterName, Version: "3"}, f.err
+}
+
+func (f *fakePolicyFetcher) GetAppConfigPolicy(ctx context.Context, identifier string) (*policy.Policy, policy.Source, error) {
+	return f.policy, policy.Source{Kind: "AppConfig", Location: identifier}, f.err
+}
+
+func TestLoadPolicy(t *testing.T) {
+	savedOut, savedFetcher := out, newPolicyFetcher
+	savedRegions, savedServices, savedMinIdleDays, savedOnlyIdle := regions, services, minIdleDays, onlyIdle
+	savedSSM, savedAppConfig, savedIgnored := policySSMParam, policyAppConfig, ignoredResources
+	t.Cleanup(func() {
+		out, newPolicyFetcher = savedOut, savedFetcher
+		regions, services, minIdleDays, onlyIdle = savedRegions, savedServices, savedMinIdleDays, savedOnlyIdle
+		policySSMParam, policyAppConfig, ignoredResources = savedSSM, savedAppConfig, savedIgnored
+	})
+
+	policyMinIdleDays, policyOnlyIdle := 30, true
+	central := &policy.Policy{
+		Version:     "2026-10",
+		Regions:     []string{"us-east-1", "eu-west-1"},
+		MinIdleDays: &policyMinIdleDays,
+		OnlyIdle:    &policyOnlyIdle,
+		Ignore:      []string{"us-east-1/i-0123"},
+	}
+
+	tests := []struct {
+		name            string
+		args            []string
+		fetcher         *fakePolicyFetcher
+		want            bool
+		wantRegions     []string
+		wantMinIdleDays int
+		wantOnlyIdle    bool
+		wantIgnored     []string
+		wantFetchRegion string
+		wantOutput      []string
+	}{
+		{
+			name:            "no policy",
+			args:            []string{"--min-idle-days", "7"},
+			want:            true,
+			wantMinIdleDays: 7,
+		},
+		{
+			name:            "policy over defaults",
+			args:            []string{"--policy-ssm-parameter", "/org/idled/policy"},
+			fetcher:         &fakePolicyFetcher{policy: central},
+			want:            true,
+			wantRegions:     []string{"us-east-1", "eu-west-1"},
+			wantMinIdleDays: 30,
+			wantOnlyIdle:    true,
+			wantIgnored:     []string{"us-east-1/i-0123"},
+			wantOutput: []string{
+				"Policy: SSM /org/idled/policy (version 3)",
+				"  Policy version: 2026-10",
+				"  Regions:        us-east-1, eu-west-1 (policy)",
+				"  Min idle days:  30 (policy)",
+				"  Only idle:      true (policy)",
+				"  Ignored:        us-east-1/i-0123 (policy)",
+			},
+		},
+		{
+			name:            "flags over the policy",
+			args:            []string{"--policy-appconfig", "idled/prod/policy", "--regions", "ap-northeast-2", "--min-idle-days", "7"},
+			fetcher:         &fakePolicyFetcher{policy: central},
+			want:            true,
+			wantRegions:     []string{"ap-northeast-2"},
+			wantFetchRegion: "ap-northeast-2",
+			wantMinIdleDays: 7,
+			wantOnlyIdle:    true,
+			wantIgnored:     []string{"us-east-1/i-0123"},
+			wantOutput: []string{
+				"Policy: AppConfig idled/prod/policy",
+				"  Regions:        ap-northeast-2 (--regions)",
+				"  Min idle days:  7 (--min-idle-days)",
+				"  Only idle:      true (policy)",
+			},
+		},
+		{
+			name:            "fetch failed",
+			args:            []string{"--policy-ssm-parameter", "/org/idled/policy", "--min-idle-days", "7"},
+			fetcher:         &fakePolicyFetcher{err: errors.New("AccessDeniedException")},
+			want:            true,
+			wantMinIdleDays: 7,
+			wantOutput:      []string{"WARNING: Failed to load policy, continuing with defaults: AccessDeniedException"},
+		},
+		{
+			name:       "both sources",
+			args:       []string{"--policy-ssm-parameter", "/org/idled/policy", "--policy-appconfig", "idled/prod/policy"},
+			wantOutput: []string{"Only one of --policy-ssm-parameter and --policy-appconfig can be set. Exiting."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			out = &output
+			newPolicyFetcher = func(ctx context.Context, region string) (policyFetcher, error) {
+				if tt.fetcher == nil {
+					t.Fatal("policy fetched without a policy option")
+				}
+				tt.fetcher.region = region
+				return tt.fetcher, nil
+			}
+
+			ignoredResources = nil
+			cmd := newRootCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			if got := loadPolicy(context.Background(), cmd); got != tt.want {
+				t.Fatalf("loadPolicy() = %v, want %v", got, tt.want)
+			}
+			for _, line := range tt.wantOutput {
+				if !strings.Contains(output.String(), line) {
+					t.Errorf("output missing %q:\n%s", line, output.String())
+				}
+			}
+			if !tt.want {
+				return
+			}
+
+			if strings.Join(regions, ",") != strings.Join(tt.wantRegions, ",") {
+				t.Errorf("regions = %v, want %v", regions, tt.wantRegions)
+			}
+			if minIdleDays != tt.wantMinIdleDays || onlyIdle != tt.wantOnlyIdle {
+				t.Errorf("minIdleDays, onlyIdle = %d, %t, want %d, %t", minIdleDays, onlyIdle, tt.wantMinIdleDays, tt.wantOnlyIdle)
+			}
+			if strings.Join(ignoredResources, ",") != strings.Join(tt.wantIgnored, ",") {
+				t.Errorf("ignoredResources = %v, want %v", ignoredResources, tt.wantIgnored)
+			}
+			if tt.wantFetchRegion != "" && tt.fetcher.region != tt.wantFetchRegion {
+				t.Errorf("policy fetched from %s, want %s", tt.fetcher.region, tt.wantFetchRegion)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// pricingRegionEnv is the environment variable used when --pricing-region is not set
+const pricingRegionEnv = "IDLED_PRICING_REGION"
+
+var (
+	pricingRegion     string        // --pricing-region
+	noPricingAPI      bool          // --no-pricing-api
+	pricingAPITimeout time.Duration // --pricing-api-timeout
+	noPricingCache    bool          // --no-pricing-cache
+	pricingCacheTTL   time.Duration // --pricing-cache-ttl
+	pricingCachePath  string        // Disk pricing cache file, empty if disabled
+)
+
+// setupPricing applies the pricing API flags before the first price lookup
+func setupPricing() error {
+	region := pricingRegion
+	if region == "" {
+		region = os.Getenv(pricingRegionEnv)
+	}
+	if region != "" {
+		if err := pricing.SetRegion(region); err != nil {
+			return err
+		}
+	}
+
+	pricing.SetAPITimeout(pricingAPITimeout)
+	if noPricingAPI {
+		pricing.DisableAPI()
+	}
+	return nil
+}
+
+// disablePricingAPIOutsideAWSPartition disables the Pricing API when a scanned region is in the
+// GovCloud or China partition, where the API is not reachable and every lookup would time out.
+// Resources are then priced from the bundled default prices, or reported as N/A.
+func disablePricingAPIOutsideAWSPartition() {
+	if pricing.APIDisabled() {
+		return
+	}
+
+	for _, region := range scanMeta.Regions {
+		if partition := utils.PartitionForRegion(region); partition != utils.PartitionAWS {
+			pricing.DisableAPI()
+			fmt.Fprintf(out, "Pricing API not available in the %s partition, using default prices where available.\n", partition)
+			return
+		}
+	}
+}
+
+// loadPricingCache loads the prices saved by previous runs within --pricing-cache-ttl.
+// A corrupt cache file is ignored and rebuilt when the run ends, and other failures only
+// disable the disk cache with a warning. Without the pricing API, only the bundled default
+// prices are used and the disk cache is left untouched.
+func loadPricingCache() {
+	if noPricingCache || pricing.APIDisabled() {
+		return
+	}
+
+	path, err := pricing.DefaultDiskCachePath()
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  WARNING: Pricing cache disabled: %v\n", err)
+		return
+	}
+	pricingCachePath = path
+
+	if _, err := pricing.LoadDiskCache(pricingCachePath, pricingCacheTTL); err != nil {
+		fmt.Fprintf(out, "⚠️  WARNING: Ignoring the pricing cache, it is rebuilt from this run: %v\n", err)
+	}
+}
+
+// savePricingCache saves the prices retrieved so far for the next runs
+func savePricingCache() {
+	if pricingCachePath == "" {
+		return
+	}
+	if err := pricing.SaveDiskCache(pricingCachePath); err != nil {
+		fmt.Fprintf(out, "\n⚠️  WARNING: Failed to save the pricing cache: %v\n", err)
+	}
+}
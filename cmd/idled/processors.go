@@ -0,0 +1,616 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/runner"
+	"github.com/younsl/idled/pkg/aws"
+	"github.com/younsl/idled/pkg/formatter"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// serviceIdleThreshold returns the idle threshold of the service being scanned: --min-idle-days
+// or the per-service threshold of the config file if set, the scanner default otherwise
+func serviceIdleThreshold(defaultDays int) int {
+	if activeMinIdleDays > 0 {
+		return activeMinIdleDays
+	}
+	return defaultDays
+}
+
+// Refactor processEC2 function (using scanService)
+func processEC2(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.InstanceInfo, error) {
+		client := aws.NewEC2Client(cfg)
+		client.SetTagFilters(tagFilters)
+		client.SetAccountID(scanMeta.Account)
+		client.SetUseCloudTrail(useCloudTrail)
+		return client.GetStoppedInstances(ctx)
+	}
+	idleDays := func(i models.InstanceInfo) int { return i.ElapsedDays }
+	return scanService(ctx, "EC2", scope, getData, idleDays, formatter.PrintInstancesTable, formatter.PrintInstancesSummary)
+}
+
+// processEC2Underutilized finds running EC2 instances below the CPU and network thresholds
+func processEC2Underutilized(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.UnderutilizedInstanceInfo, error) {
+		client := aws.NewEC2UtilizationClient(cfg)
+		client.SetTagFilters(tagFilters)
+		client.SetAccountID(scanMeta.Account)
+		client.SetThresholds(cpuThreshold, networkThreshold)
+		client.SetLookbackDays(utilizationDays)
+		client.SetIncludeAutoScaling(includeASG)
+		client.SetProgress(activeProgress.Func(cfg.Region))
+		return client.GetUnderutilizedInstances(ctx)
+	}
+	return scanService(ctx, "EC2 (underutilized)", scope, getData, nil, formatter.PrintUnderutilizedInstancesTable, formatter.PrintUnderutilizedInstancesSummary)
+}
+
+// Refactor processEBS function (using scanService)
+func processEBS(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.VolumeInfo, error) {
+		client := aws.NewEBSClient(cfg)
+		client.SetTagFilters(tagFilters)
+		client.SetAccountID(scanMeta.Account)
+		client.SetIncludeStoppedAttached(stoppedAttached)
+		client.SetSnapshotRecencyDays(snapshotDays)
+		client.SetUseCloudTrail(useCloudTrail)
+		return client.GetAvailableVolumes(ctx)
+	}
+	idleDays := func(i models.VolumeInfo) int { return i.ElapsedDaysSinceUsed }
+	return scanService(ctx, "EBS", scope, getData, idleDays, formatter.PrintVolumesTable, formatter.PrintVolumesSummary)
+}
+
+// Refactor processS3 function (using scanService)
+func processS3(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.BucketInfo, error) {
+		client := aws.NewS3Client(cfg)
+		client.SetTagFilters(tagFilters)
+		client.SetNameFilter(nameFilter)
+		client.SetProgress(activeProgress.Func(cfg.Region))
+		return client.GetIdleBuckets(ctx)
+	}
+	idleDays := func(i models.BucketInfo) int { return i.IdleDays }
+	return scanService(ctx, "S3", scope, getData, idleDays, formatter.PrintBucketsTable, formatter.PrintBucketsSummary)
+}
+
+// Refactor processLambda function (using scanService)
+func processLambda(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.LambdaFunctionInfo, error) {
+		client := aws.NewLambdaClient(cfg)
+		client.SetTagFilters(tagFilters)
+		client.SetNameFilter(nameFilter)
+		client.SetProgress(activeProgress.Func(cfg.Region))
+		client.SetFailingErrorRate(lambdaFailingRate)
+		return client.GetIdleFunctions(ctx)
+	}
+	idleDays := func(i models.LambdaFunctionInfo) int { return i.IdleDays }
+	return scanService(ctx, "Lambda", scope, getData, idleDays, formatter.PrintLambdaTable, formatter.PrintLambdaSummary)
+}
+
+// processStepFunctions processes Step Functions state machines
+func processStepFunctions(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	idleThreshold := serviceIdleThreshold(aws.DefaultStepFunctionsIdleDays)
+	formatter.SetStepFunctionsIdleThreshold(idleThreshold)
+
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.StateMachineInfo, error) {
+		client := aws.NewStepFunctionsClient(cfg)
+		client.SetIdleThreshold(idleThreshold)
+		return client.GetStateMachines(ctx)
+	}
+	idleDays := func(i models.StateMachineInfo) int { return i.IdleDays }
+	return scanService(ctx, "Step Functions", scope, getData, idleDays, formatter.PrintStateMachinesTable, formatter.PrintStateMachinesSummary)
+}
+
+// Refactor processEIP function (using scanService)
+func processEIP(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.EIPInfo, error) {
+		client := aws.NewEIPClient(cfg)
+		client.SetTagFilters(tagFilters)
+		client.SetAccountID(scanMeta.Account)
+		return client.GetUnattachedEIPs(ctx)
+	}
+	return scanService(ctx, "Elastic IP", scope, getData, nil, formatter.PrintEIPsTable, formatter.PrintEIPsSummary)
+}
+
+// processENI processes orphaned network interfaces
+func processENI(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.ENIInfo, error) {
+		client := aws.NewENIClient(cfg)
+		client.SetTagFilters(tagFilters)
+		return client.GetOrphanedENIs(ctx)
+	}
+	return scanService(ctx, "ENI", scope, getData, nil, formatter.PrintENIsTable, formatter.PrintENIsSummary)
+}
+
+// processASG processes Auto Scaling groups
+func processASG(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	idleThreshold := serviceIdleThreshold(aws.DefaultASGIdleDays)
+	formatter.SetASGIdleThreshold(idleThreshold)
+
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.AutoScalingGroupInfo, error) {
+		scanner := aws.NewASGScanner(cfg)
+		scanner.SetIdleThreshold(idleThreshold)
+		data, errs := scanner.GetIdleAutoScalingGroups(ctx)
+		return data, errors.Join(errs...)
+	}
+	// Suspended groups and groups without healthy targets are reported regardless of their idle days
+	return scanService(ctx, "Auto Scaling", scope, getData, nil, formatter.PrintASGTable, formatter.PrintASGSummary)
+}
+
+// Refactor processECR function (using scanService)
+func processECR(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	var coverage []models.ECRScanCoverageInfo
+	var coverageErrs []string
+	var mu sync.Mutex
+
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.RepositoryInfo, error) {
+		client := aws.NewECRClient(cfg)
+		client.SetTagFilters(tagFilters)
+		client.SetNameFilter(nameFilter)
+		repos, err := client.GetIdleRepositories(ctx)
+		if err != nil || !inspectorCoverage {
+			return repos, err
+		}
+
+		// Cross-reference the idle repositories from this scan with Inspector2 coverage
+		regionCoverage, err := aws.NewInspectorClient(cfg).GetIdleRepositoryScanCoverage(ctx, repos)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			coverageErrs = append(coverageErrs, fmt.Sprintf("region %s: %v", cfg.Region, err))
+		} else {
+			coverage = append(coverage, regionCoverage...)
+		}
+		return repos, nil
+	}
+	idleDays := func(repo models.RepositoryInfo) int {
+		// Repositories without any pushed image have been idle since creation
+		if repo.LastPush != nil {
+			return daysSince(repo.LastPush)
+		}
+		return daysSince(repo.CreatedAt)
+	}
+	summaries := scanService(ctx, "ECR", scope, getData, idleDays, formatter.PrintECRTable, formatter.PrintECRSummary)
+
+	if inspectorCoverage {
+		sort.Strings(coverageErrs)
+		outcome.recordErrors(len(coverageErrs))
+		for _, errMsg := range coverageErrs {
+			fmt.Fprintf(out, "Error checking Inspector2 coverage in %s\n", errMsg)
+		}
+		models.SortByKey(coverage)
+		formatter.PrintECRScanCoverageTable(tableOut(), coverage)
+	}
+	return summaries
+}
+
+// processIAM handles the scanning of IAM resources
+func processIAM(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.IAMScanResult, error) {
+		client := aws.NewIAMClient(cfg)
+		client.SetKeyMaxAge(iamKeyMaxAge)
+		client.SetProgress(activeProgress.Func("IAM"))
+
+		var errs []error
+		var result models.IAMScanResult
+		users, err := client.GetIdleUsers(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get IAM users: %w", err))
+		}
+		result.Users = filterResources(users, func(u models.IAMUserInfo) int { return u.IdleDays })
+		roles, err := client.GetIdleRoles(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get IAM roles: %w", err))
+		}
+		result.Roles = filterResources(roles, func(r models.IAMRoleInfo) int { return r.IdleDays })
+		policies, err := client.GetIdlePolicies(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get IAM policies: %w", err))
+		}
+		result.Policies = filterResources(policies, func(p models.IAMPolicyInfo) int { return p.IdleDays })
+		return []models.IAMScanResult{result}, errors.Join(errs...)
+	}
+	return scanService(ctx, "IAM", scope, getData, nil, printIAMResults, printIAMSummaries)
+}
+
+// printIAMResults prints the IAM users, roles, and policies as separate tables
+func printIAMResults(w io.Writer, results []models.IAMScanResult, _ time.Time, _ time.Duration) {
+	users, roles, policies := mergeIAMResults(results)
+	fmt.Fprintln(w, "\nIAM Users:")
+	formatter.FormatIAMUserTable(w, filterOnlyIdle(users))
+	fmt.Fprintln(w, "\nIAM Roles:")
+	formatter.FormatIAMRoleTable(w, filterOnlyIdle(roles))
+	fmt.Fprintln(w, "\nIAM Policies:")
+	formatter.FormatIAMPolicyTable(w, filterOnlyIdle(policies))
+}
+
+// printIAMSummaries prints the summaries of the IAM users, roles, and policies
+func printIAMSummaries(w io.Writer, results []models.IAMScanResult) {
+	users, roles, policies := mergeIAMResults(results)
+	formatter.FormatIAMUserSummary(w, users)
+	formatter.FormatIAMRoleSummary(w, roles)
+	formatter.FormatIAMPolicySummary(w, policies)
+}
+
+// mergeIAMResults returns the users, roles, and policies of the results sorted by ARN
+func mergeIAMResults(results []models.IAMScanResult) ([]models.IAMUserInfo, []models.IAMRoleInfo, []models.IAMPolicyInfo) {
+	var users []models.IAMUserInfo
+	var roles []models.IAMRoleInfo
+	var policies []models.IAMPolicyInfo
+	for _, result := range results {
+		users = append(users, result.Users...)
+		roles = append(roles, result.Roles...)
+		policies = append(policies, result.Policies...)
+	}
+	models.SortByKey(users)
+	models.SortByKey(roles)
+	models.SortByKey(policies)
+	return users, roles, policies
+}
+
+// processConfig handles the scanning of AWS Config resources
+func processConfig(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.ConfigScanResult, error) {
+		client := aws.NewConfigClient(cfg)
+
+		var errs []error
+		result := models.ConfigScanResult{Region: cfg.Region}
+		rules, err := client.GetAllConfigRules(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get AWS Config rules: %w", err))
+		}
+		result.Rules = filterResources(rules, func(r models.ConfigRuleInfo) int { return r.IdleDays })
+		recorders, err := client.GetAllConfigRecorders(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get AWS Config recorders: %w", err))
+		}
+		result.Recorders = filterResources(recorders, func(r models.ConfigRecorderInfo) int { return r.IdleDays })
+		channels, err := client.GetAllConfigDeliveryChannels(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get AWS Config delivery channels: %w", err))
+		}
+		result.Channels = filterResources(channels, func(c models.ConfigDeliveryChannelInfo) int { return c.IdleDays })
+		return []models.ConfigScanResult{result}, errors.Join(errs...)
+	}
+	return scanService(ctx, "Config", scope, getData, nil, printConfigResults, nil)
+}
+
+// printConfigResults prints the AWS Config rules, recorders, and delivery channels of all
+// regions as separate sections, each followed by its summary
+func printConfigResults(w io.Writer, results []models.ConfigScanResult, _ time.Time, _ time.Duration) {
+	var allRules []models.ConfigRuleInfo
+	var allRecorders []models.ConfigRecorderInfo
+	var allChannels []models.ConfigDeliveryChannelInfo
+	for _, result := range results {
+		allRules = append(allRules, result.Rules...)
+		allRecorders = append(allRecorders, result.Recorders...)
+		allChannels = append(allChannels, result.Channels...)
+	}
+	models.SortByKey(allRules)
+	models.SortByKey(allRecorders)
+	models.SortByKey(allChannels)
+	if len(allRules) > 0 {
+		fmt.Fprintln(w, "\nAWS Config Rules:")
+		formatter.FormatConfigRulesTable(w, filterOnlyIdle(allRules))
+		formatter.FormatConfigRulesSummary(w, allRules)
+	} else {
+		fmt.Fprintln(w, "\nNo AWS Config rules found.")
+	}
+	if len(allRecorders) > 0 {
+		fmt.Fprintln(w, "\nAWS Config Recorders:")
+		formatter.FormatConfigRecordersTable(w, filterOnlyIdle(allRecorders))
+		formatter.FormatConfigRecordersSummary(w, allRecorders)
+	} else {
+		fmt.Fprintln(w, "\nNo AWS Config recorders found.")
+	}
+	if len(allChannels) > 0 {
+		fmt.Fprintln(w, "\nAWS Config Delivery Channels:")
+		formatter.FormatConfigDeliveryChannelsTable(w, filterOnlyIdle(allChannels))
+		formatter.FormatConfigDeliveryChannelsSummary(w, allChannels)
+	} else {
+		fmt.Fprintln(w, "\nNo AWS Config delivery channels found.")
+	}
+}
+
+// Refactor processELB function (using scanService)
+func processELB(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.ELBResource, error) {
+		scanner := aws.NewELBScanner(cfg)
+		scanner.SetTagFilters(tagFilters)
+		scanner.SetAssumeIdleOnMissingMetrics(assumeIdleELB)
+		// Every load balancer error is printed, alongside the load balancers evaluated before it
+		data, errs := scanner.GetIdleELBs(ctx, cfg.Region)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "ELB", scope, getData, nil, formatter.PrintELBTable, formatter.PrintELBSummary)
+}
+
+// processCloudWatch processes CloudWatch alarms and dashboards
+func processCloudWatch(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	alarmThreshold := serviceIdleThreshold(aws.DefaultCloudWatchAlarmIdleDays)
+	dashboardThreshold := serviceIdleThreshold(aws.DefaultCloudWatchDashboardIdleDays)
+	formatter.SetCloudWatchDashboardIdleThreshold(dashboardThreshold)
+
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.CloudWatchScanResult, error) {
+		scanner := aws.NewCloudWatchScanner(cfg)
+		scanner.SetIdleThresholds(alarmThreshold, dashboardThreshold)
+		scanner.SetCheckMetrics(cloudWatchMetrics)
+
+		result := models.CloudWatchScanResult{Region: cfg.Region}
+		alarms, errs := scanner.GetIdleAlarms(ctx)
+		result.Alarms = filterResources(alarms, nil)
+		dashboards, err := scanner.GetDashboards(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		result.Dashboards = filterResources(dashboards, func(d models.CloudWatchDashboardInfo) int { return d.IdleDays })
+		return []models.CloudWatchScanResult{result}, errors.Join(errs...)
+	}
+	return scanService(ctx, "CloudWatch", scope, getData, nil, printCloudWatchResults, nil)
+}
+
+// printCloudWatchResults prints the CloudWatch alarms and dashboards of all regions as
+// separate sections, followed by their summary
+func printCloudWatchResults(w io.Writer, results []models.CloudWatchScanResult, _ time.Time, _ time.Duration) {
+	var allAlarms []models.CloudWatchAlarmInfo
+	var allDashboards []models.CloudWatchDashboardInfo
+	for _, result := range results {
+		allAlarms = append(allAlarms, result.Alarms...)
+		allDashboards = append(allDashboards, result.Dashboards...)
+	}
+	models.SortByKey(allAlarms)
+	models.SortByKey(allDashboards)
+	if len(allAlarms) > 0 {
+		fmt.Fprintln(w, "\nCloudWatch Alarms:")
+		formatter.PrintCloudWatchAlarmsTable(w, allAlarms)
+	} else {
+		fmt.Fprintln(w, "\nNo idle CloudWatch alarms found.")
+	}
+	if !cloudWatchMetrics {
+		fmt.Fprintln(w, "\nAlarm metrics not checked (enable with --cloudwatch-check-metrics).")
+	}
+	if len(allDashboards) > 0 {
+		fmt.Fprintln(w, "\nCloudWatch Dashboards:")
+		formatter.PrintCloudWatchDashboardsTable(w, filterOnlyIdle(allDashboards))
+	} else {
+		fmt.Fprintln(w, "\nNo CloudWatch dashboards found.")
+	}
+	formatter.PrintCloudWatchSummary(w, allAlarms, allDashboards)
+}
+
+// processLogs handles the scanning of CloudWatch Log Groups
+func processLogs(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.LogGroupInfo, error) {
+		scanner := aws.NewLogsScanner(cfg)
+		scanner.SetIdleThreshold(logsIdleDays)
+		scanner.SetProgress(activeProgress.Func(cfg.Region))
+		scanner.SetNameFilter(nameFilter)
+		data, errs := scanner.GetIdleLogGroups(ctx)
+		return data, errors.Join(errs...)
+	}
+	idleDays := func(lg models.LogGroupInfo) int {
+		return utils.CalculateElapsedDays(time.UnixMilli(lg.LastEventMillis))
+	}
+	printTable := func(w io.Writer, logGroups []models.LogGroupInfo, _ time.Time, _ time.Duration) {
+		formatter.PrintLogGroupsTable(w, logGroups)
+	}
+	return scanService(ctx, "Logs", scope, getData, idleDays, printTable, formatter.PrintLogGroupsSummary)
+}
+
+// processMsk processes MSK clusters (added previously)
+func processMsk(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.MskClusterInfo, error) {
+		scanner := aws.NewMskScanner(cfg)
+		// Modify to handle []error return type
+		data, errs := scanner.GetIdleMskClusters(ctx)
+		if len(errs) > 0 {
+			// Combine multiple errors into a single error message
+			var errorMessages []string
+			for _, e := range errs {
+				errorMessages = append(errorMessages, e.Error())
+			}
+			return data, fmt.Errorf("encountered %d error(s) during MSK scan: %s", len(errs), strings.Join(errorMessages, "; "))
+		}
+		return data, nil
+	}
+	return scanService(ctx, "MSK", scope, getData, nil, formatter.PrintMskTable, formatter.PrintMskSummary)
+}
+
+// processSecretsManager processes Secrets Manager secrets
+func processSecretsManager(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	idleThreshold := serviceIdleThreshold(aws.DefaultSecretsIdleDays)
+	formatter.SetSecretsIdleThreshold(idleThreshold)
+
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.SecretInfo, error) {
+		scanner := aws.NewSecretsManagerScanner(cfg)
+		scanner.SetIdleThreshold(idleThreshold)
+		data, errs := scanner.GetIdleSecrets(ctx)
+		if len(errs) > 0 {
+			// Combine multiple errors into a single error message
+			var errorMessages []string
+			for _, e := range errs {
+				errorMessages = append(errorMessages, e.Error())
+			}
+			return data, fmt.Errorf("encountered %d error(s) during Secrets Manager scan: %s", len(errs), strings.Join(errorMessages, "; "))
+		}
+		return data, nil
+	}
+	idleDays := func(i models.SecretInfo) int { return i.IdleDays }
+	return scanService(ctx, "SecretsManager", scope, getData, idleDays, formatter.PrintSecretsTable, formatter.PrintSecretsSummary)
+}
+
+// processElastiCache processes ElastiCache serverless caches and provisioned Redis OSS clusters
+func processElastiCache(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.ElastiCacheInfo, error) {
+		scanner := aws.NewElastiCacheScanner(cfg)
+		data, errs := scanner.GetIdleElastiCaches(ctx)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "ElastiCache", scope, getData, nil, formatter.PrintElastiCacheTable, formatter.PrintElastiCacheSummary)
+}
+
+// processOpenSearch processes OpenSearch Service domains
+func processOpenSearch(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.OpenSearchDomainInfo, error) {
+		scanner := aws.NewOpenSearchScanner(cfg)
+		scanner.SetAccountID(scanMeta.Account)
+		data, errs := scanner.GetIdleOpenSearchDomains(ctx)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "OpenSearch", scope, getData, nil, formatter.PrintOpenSearchTable, formatter.PrintOpenSearchSummary)
+}
+
+// processTransfer processes AWS Transfer Family servers
+func processTransfer(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.TransferServerInfo, error) {
+		scanner := aws.NewTransferScanner(cfg)
+		data, errs := scanner.GetIdleTransferServers(ctx)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "Transfer", scope, getData, nil, formatter.PrintTransferTable, formatter.PrintTransferSummary)
+}
+
+// processResolver processes Route 53 Resolver endpoints
+func processResolver(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.ResolverEndpointInfo, error) {
+		scanner := aws.NewResolverScanner(cfg)
+		data, errs := scanner.GetIdleResolverEndpoints(ctx)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "Resolver", scope, getData, nil, formatter.PrintResolverTable, formatter.PrintResolverSummary)
+}
+
+// processBeanstalk processes Elastic Beanstalk environments
+func processBeanstalk(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.BeanstalkEnvironmentInfo, error) {
+		scanner := aws.NewBeanstalkScanner(cfg)
+		data, errs := scanner.GetIdleBeanstalkEnvironments(ctx)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "Beanstalk", scope, getData, nil, formatter.PrintBeanstalkTable, formatter.PrintBeanstalkSummary)
+}
+
+// processWorkSpaces processes Amazon WorkSpaces
+func processWorkSpaces(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	idleThreshold := serviceIdleThreshold(aws.DefaultWorkSpacesIdleDays)
+	formatter.SetWorkSpacesIdleThreshold(idleThreshold)
+
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.WorkSpaceInfo, error) {
+		scanner := aws.NewWorkSpacesScanner(cfg)
+		scanner.SetIdleThreshold(idleThreshold)
+		data, errs := scanner.GetIdleWorkSpaces(ctx)
+		return data, errors.Join(errs...)
+	}
+	// The scanner already applies the idle threshold, and WorkSpaces never connected to have no idle age
+	return scanService(ctx, "WorkSpaces", scope, getData, nil, formatter.PrintWorkSpacesTable, formatter.PrintWorkSpacesSummary)
+}
+
+// processSageMaker processes SageMaker endpoints and notebook instances
+func processSageMaker(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.SageMakerResourceInfo, error) {
+		scanner := aws.NewSageMakerScanner(cfg)
+		data, errs := scanner.GetIdleSageMakerResources(ctx)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "SageMaker", scope, getData, nil, formatter.PrintSageMakerTable, formatter.PrintSageMakerSummary)
+}
+
+// processRoute53 processes Route 53 hosted zones and health checks
+func processRoute53(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.Route53ScanResult, error) {
+		client := aws.NewRoute53Client(cfg)
+		client.SetCheckDelegation(route53Delegation)
+		client.SetProgress(activeProgress.Func("Route53"))
+
+		var errs []error
+		var result models.Route53ScanResult
+		zones, err := client.GetIdleHostedZones(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get Route 53 hosted zones: %w", err))
+		}
+		result.Zones = filterResources(zones, nil)
+		healthChecks, err := client.GetUnreferencedHealthChecks(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get Route 53 health checks: %w", err))
+		}
+		result.HealthChecks = filterResources(healthChecks, nil)
+		return []models.Route53ScanResult{result}, errors.Join(errs...)
+	}
+	return scanService(ctx, "Route53", scope, getData, nil, printRoute53Results, nil)
+}
+
+// printRoute53Results prints the private hosted zones, public hosted zones, and health checks
+// as separate sections, followed by their summary
+func printRoute53Results(w io.Writer, results []models.Route53ScanResult, _ time.Time, _ time.Duration) {
+	var privateZones, publicZones []models.HostedZoneInfo
+	var healthChecks []models.HealthCheckInfo
+	for _, result := range results {
+		for _, zone := range result.Zones {
+			if zone.Private {
+				privateZones = append(privateZones, zone)
+			} else {
+				publicZones = append(publicZones, zone)
+			}
+		}
+		healthChecks = append(healthChecks, result.HealthChecks...)
+	}
+	models.SortByKey(privateZones)
+	models.SortByKey(publicZones)
+	models.SortByKey(healthChecks)
+
+	if len(privateZones) > 0 {
+		fmt.Fprintln(w, "\nRoute 53 Private Hosted Zones (no records):")
+		formatter.FormatRoute53PrivateZonesTable(w, privateZones)
+	} else {
+		fmt.Fprintln(w, "\nNo private hosted zones without records found.")
+	}
+	switch {
+	case !route53Delegation:
+		fmt.Fprintln(w, "\nPublic hosted zone delegation not checked (enable with --route53-check-delegation).")
+	case len(publicZones) > 0:
+		fmt.Fprintln(w, "\nRoute 53 Public Hosted Zones (not delegated):")
+		formatter.FormatRoute53PublicZonesTable(w, publicZones)
+	default:
+		fmt.Fprintln(w, "\nNo undelegated public hosted zones found.")
+	}
+	if len(healthChecks) > 0 {
+		fmt.Fprintln(w, "\nRoute 53 Health Checks (not referenced):")
+		formatter.FormatRoute53HealthChecksTable(w, healthChecks)
+	} else {
+		fmt.Fprintln(w, "\nNo unreferenced health checks found.")
+	}
+	formatter.FormatRoute53Summary(w, append(privateZones, publicZones...), healthChecks)
+}
+
+// processCloudFront processes CloudFront distributions. CloudFront is global, and the runner
+// passes its home region, where the distribution metrics are published.
+func processCloudFront(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.DistributionInfo, error) {
+		scanner := aws.NewCloudFrontScanner(cfg)
+		scanner.SetProgress(activeProgress.Func("CloudFront"))
+		data, errs := scanner.GetIdleDistributions(ctx)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "CloudFront", scope, getData, nil, formatter.PrintCloudFrontTable, formatter.PrintCloudFrontSummary)
+}
+
+// processGlobalAccelerator processes Global Accelerator accelerators. Global Accelerator is
+// global, and the runner passes its home region, the only region serving its API and metrics.
+func processGlobalAccelerator(ctx context.Context, scope runner.Scope) []models.CostSummary {
+	getData := func(ctx context.Context, cfg awssdk.Config) ([]models.AcceleratorInfo, error) {
+		scanner := aws.NewGlobalAcceleratorScanner(cfg)
+		data, errs := scanner.GetIdleAccelerators(ctx)
+		return data, errors.Join(errs...)
+	}
+	return scanService(ctx, "GlobalAccelerator", scope, getData, nil, formatter.PrintGlobalAcceleratorTable, formatter.PrintGlobalAcceleratorSummary)
+}
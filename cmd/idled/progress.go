@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/younsl/idled/internal/progress"
+	"github.com/younsl/idled/pkg/aws"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+// plainProgressInterval is the minimum time between progress lines without a spinner
+const plainProgressInterval = 5 * time.Second
+
+var (
+	noSpinner      bool          // Print plain progress lines instead of a spinner
+	activeProgress *scanProgress // Progress display of the service being scanned, nil between scans
+)
+
+// scanProgress is the only progress display of a service scan: a spinner on a terminal, or
+// plain periodic lines when stdout is not a terminal or with --no-spinner. Scanners and
+// pricing lookups report to it through progress functions instead of owning spinners.
+type scanProgress struct {
+	FinalMSG string // Printed when the display stops
+
+	label   string
+	service string
+	silent  bool             // --quiet hides the progress
+	spinner *spinner.Spinner // nil when printing plain lines
+	tracker *progress.Tracker
+	regions *progress.Regions // nil for single-region and global scans
+
+	mu           sync.Mutex
+	lastLog      time.Time
+	regionStatus string // Regions done and pending
+	taskStatus   string // Aggregated status of the scanner progress functions
+	skippedStart int    // Resources skipped by the services scanned before this one
+}
+
+// startProgress starts the progress display of a service scanned in the given regions
+// (nil for global services). Services run one at a time.
+func startProgress(service string, regions []string) *scanProgress {
+	p := &scanProgress{label: fmt.Sprintf("Analyzing %s resources in %s", service, regionList(regions)), service: service}
+	p.skippedStart = aws.SkippedCount()
+	p.tracker = progress.NewTracker(p.update)
+	if len(regions) > 1 {
+		p.regions = progress.NewRegions(regions)
+	}
+
+	switch {
+	case quiet:
+		p.silent = true
+	case !noSpinner && stdoutIsTerminal():
+		p.spinner = spinner.New(spinner.CharSets[9], 200*time.Millisecond)
+		p.spinner.Suffix = fmt.Sprintf(" %s ...", p.label)
+		p.spinner.Start()
+	default:
+		fmt.Printf("%s ...\n", p.label)
+		p.lastLog = time.Now()
+	}
+
+	pricing.SetProgress(p.tracker.Func("pricing"))
+	activeProgress = p
+	return p
+}
+
+// Func returns the progress function of one task of the scan, such as a region.
+// It returns nil if p is nil, so that scanners report nowhere.
+func (p *scanProgress) Func(task string) progress.Func {
+	if p == nil {
+		return nil
+	}
+	return p.tracker.Func(task)
+}
+
+// skipped returns the number of resources the scanners of this service could not evaluate
+func (p *scanProgress) skipped() int {
+	return aws.SkippedCount() - p.skippedStart
+}
+
+// update shows the aggregated status of the scanners
+func (p *scanProgress) update(status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.taskStatus = status
+	p.renderLocked()
+}
+
+// consumeCompletions shows the regions done as their completions arrive, and logs each one
+// with --verbose. It returns when completions is closed.
+func (p *scanProgress) consumeCompletions(completions <-chan progress.Completion) {
+	for c := range completions {
+		logRegionCompletion(p.service, c)
+		if p.regions == nil {
+			continue
+		}
+		p.regions.Complete(c.Region)
+		p.mu.Lock()
+		p.regionStatus = p.regions.Status()
+		p.renderLocked()
+		p.mu.Unlock()
+	}
+}
+
+// renderLocked shows the region and scanner status, at most every plainProgressInterval
+// without a spinner
+func (p *scanProgress) renderLocked() {
+	if p.silent {
+		return
+	}
+	status := p.taskStatus
+	if p.regionStatus != "" {
+		status = strings.TrimSuffix(p.regionStatus+" - "+status, " - ")
+	}
+	if p.spinner != nil {
+		p.spinner.Lock()
+		if p.regionStatus != "" {
+			p.spinner.Suffix = fmt.Sprintf(" %s: %s", p.service, status)
+		} else {
+			p.spinner.Suffix = fmt.Sprintf(" %s ... %s", p.label, status)
+		}
+		p.spinner.Unlock()
+		return
+	}
+
+	if status == "" || time.Since(p.lastLog) < plainProgressInterval {
+		return
+	}
+	p.lastLog = time.Now()
+	fmt.Printf("  %s\n", status)
+}
+
+// logRegionCompletion logs the items found and the duration of a region scan, shown with --verbose
+func logRegionCompletion(service string, c progress.Completion) {
+	attrs := []any{"service", service, "region", c.Region, "items", c.Items, "duration", c.Duration.Round(time.Millisecond)}
+	if c.Err != nil {
+		attrs = append(attrs, "error", c.Err)
+	}
+	slog.Info("Region scan completed", attrs...)
+}
+
+// Stop ends the display and prints FinalMSG, unless the progress is hidden
+func (p *scanProgress) Stop() {
+	pricing.SetProgress(nil)
+	activeProgress = nil
+	if p.silent {
+		return
+	}
+	if p.spinner != nil {
+		p.spinner.FinalMSG = p.FinalMSG
+		p.spinner.Stop()
+		return
+	}
+	fmt.Print(p.FinalMSG)
+}
+
+// regionList formats the scanned regions for the progress label, shortened beyond five regions
+func regionList(regions []string) string {
+	if len(regions) == 0 {
+		return "Global"
+	}
+	if len(regions) > 5 {
+		return fmt.Sprintf("%s, ... (%d total)", strings.Join(regions[:5], ", "), len(regions))
+	}
+	return strings.Join(regions, ", ")
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal that can animate a spinner
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
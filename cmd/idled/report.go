@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/report"
+	"github.com/younsl/idled/internal/runner"
+	"github.com/younsl/idled/pkg/aws"
+	"github.com/younsl/idled/pkg/formatter"
+)
+
+// Supported --output formats
+const (
+	outputFormatTable    = "table"
+	outputFormatHTML     = "html"
+	outputFormatMarkdown = "markdown"
+)
+
+// scanReport collects the results of every service with --output html or markdown, nil otherwise
+var scanReport *report.Report
+
+// newScanReport creates an empty report whose resources link to the AWS console
+func newScanReport() *report.Report {
+	r := report.New()
+	r.Link = formatter.ResourceConsoleURL
+	return r
+}
+
+// addToReport adds the resources shown in the tables of a service and its summary to the
+// report, if one is being built. Resource groups are expanded into their individual resources.
+func addToReport[T any](service string, items []T, printSummary func(io.Writer, []T)) {
+	if scanReport == nil {
+		return
+	}
+	var summary bytes.Buffer
+	if printSummary != nil {
+		printSummary(&summary, items)
+	}
+	scanReport.Add(service, reportResources(filterOnlyIdle(items)), summary.String())
+}
+
+// reportResources converts items to report rows, expanding resource groups
+func reportResources[T any](items []T) []any {
+	var resources []any
+	for _, item := range items {
+		if group, ok := any(item).(models.ResourceGroup); ok {
+			resources = append(resources, filterOnlyIdle(group.Resources())...)
+			continue
+		}
+		resources = append(resources, item)
+	}
+	return resources
+}
+
+// tableOut returns the destination of the text tables and summaries, which are replaced
+// by the rendered report with --output markdown and left out with --diff-only
+func tableOut() io.Writer {
+	if outputFormat == outputFormatMarkdown || diffOnly {
+		return io.Discard
+	}
+	return out
+}
+
+// finishReport sets the savings totals, scan metadata, and API calls of the report from the results
+func finishReport(ctx context.Context, results []runner.Result) {
+	summaries := costSummaries(results)
+	scanReport.SetSavings(summaries)
+	scanReport.Partial = ctx.Err() != nil
+	scanReport.Account = scanMeta.Account
+
+	seen := make(map[string]bool)
+	for _, summary := range summaries {
+		if summary.Region != globalRegion && !seen[summary.Region] {
+			seen[summary.Region] = true
+			scanReport.Regions = append(scanReport.Regions, summary.Region)
+		}
+	}
+	sort.Strings(scanReport.Regions)
+	for _, result := range results {
+		scanReport.Duration += result.Duration
+	}
+	scanReport.APICalls = aws.APICallStats()
+}
+
+// writeHTMLReport renders the report to the --output-file
+func writeHTMLReport(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML report: %w", err)
+	}
+	if err := scanReport.WriteHTML(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close HTML report %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/report"
+	"github.com/younsl/idled/internal/runner"
+	"github.com/younsl/idled/pkg/formatter"
+)
+
+// renderEIPs runs the results through processResults and returns the table output followed
+// by the Markdown and HTML reports
+func renderEIPs(t *testing.T, results []runner.RegionResult[models.EIPInfo]) []byte {
+	t.Helper()
+	var output bytes.Buffer
+	out = &output
+	scanReport = &report.Report{GeneratedAt: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+
+	summaries := processResults(context.Background(), "Elastic IP", results, time.Now(), &scanProgress{silent: true},
+		nil, formatter.PrintEIPsTable, formatter.PrintEIPsSummary)
+	scanReport.SetSavings(summaries)
+	if err := scanReport.WriteMarkdown(&output); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if err := scanReport.WriteHTML(&output); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	return output.Bytes()
+}
+
+func TestOutputDoesNotDependOnScanOrder(t *testing.T) {
+	savedOut, savedReport, savedOutcome := out, scanReport, outcome
+	t.Cleanup(func() { out, scanReport, outcome = savedOut, savedReport, savedOutcome })
+
+	regions := []string{"us-east-1", "eu-west-1", "ap-northeast-2"}
+	var eips [][]models.EIPInfo
+	for i, region := range regions {
+		var found []models.EIPInfo
+		for j := range 6 {
+			found = append(found, models.EIPInfo{
+				AllocationID:         fmt.Sprintf("eipalloc-%d%d", i, j),
+				PublicIP:             fmt.Sprintf("198.51.100.%d", 10*i+j),
+				Region:               region,
+				AssociationType:      models.EIPAssociationUnattached,
+				AssociationState:     "Unattached",
+				EstimatedMonthlyCost: 3.65,
+				PricingSource:        "Default",
+				Tags:                 map[string]string{"Team": "platform", "Env": region, "Index": fmt.Sprint(j)},
+			})
+		}
+		eips = append(eips, found)
+	}
+
+	// Scanners return resources in API order, which varies between runs
+	random := rand.New(rand.NewPCG(1, 2))
+	var want []byte
+	for run := range 5 {
+		results := make([]runner.RegionResult[models.EIPInfo], len(regions))
+		for i, region := range regions {
+			data := append([]models.EIPInfo(nil), eips[i]...)
+			random.Shuffle(len(data), func(a, b int) { data[a], data[b] = data[b], data[a] })
+			results[i] = runner.RegionResult[models.EIPInfo]{Region: region, Data: data}
+		}
+
+		got := renderEIPs(t, results)
+		if run == 0 {
+			want = got
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Fatalf("run %d output differs from the first run:\n--- got:\n%s\n--- want:\n%s", run, got, want)
+		}
+	}
+}
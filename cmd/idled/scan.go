@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/progress"
+	"github.com/younsl/idled/internal/runner"
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// Common function to start scan
+func startScan(serviceName string, regions []string) (time.Time, *scanProgress) {
+	scanStartTime := time.Now()
+	s := startProgress(serviceName, regions)
+	return scanStartTime, s
+}
+
+// defaultRegionTimeout is the default --region-timeout
+const defaultRegionTimeout = 10 * time.Minute
+
+// slowestRegionsShown is the number of regions listed by logSlowestRegions
+const slowestRegionsShown = 3
+
+// filterResources drops resources on the policy ignore list and those idle for less than --min-idle-days
+func filterResources[T models.Keyed](items []T, idleDays func(T) int) []T {
+	return filterByMinIdleDays(filterIgnored(items), idleDays)
+}
+
+// filterIgnored drops resources whose canonical key matches an entry of the policy ignore list.
+// An entry matches the full key (e.g., an ARN) or its trailing resource ID or name.
+func filterIgnored[T models.Keyed](items []T) []T {
+	if len(ignoredResources) == 0 {
+		return items
+	}
+	var filtered []T
+	for _, item := range items {
+		key := item.SortKey()
+		ignored := false
+		for _, entry := range ignoredResources {
+			if key == entry || strings.HasSuffix(key, "/"+entry) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// printNameFilterNote notes how many resources of the service just scanned were skipped by
+// --include/--exclude before their analysis
+func printNameFilterNote() {
+	if excluded := nameFilter.TakeExcludedCount(); excluded > 0 {
+		fmt.Fprintf(tableOut(), "\nNote: %d resources excluded by --include/--exclude filters\n", excluded)
+	}
+}
+
+// filterByMinIdleDays keeps only the items idle for at least the minimum idle age of the service
+// being scanned (--min-idle-days or the per-service threshold of the config file).
+// Items are returned unchanged when no threshold is set or the service has no idle age (idleDays is nil).
+func filterByMinIdleDays[T any](items []T, idleDays func(T) int) []T {
+	if activeMinIdleDays <= 0 || idleDays == nil {
+		return items
+	}
+	var filtered []T
+	for _, item := range items {
+		if idleDays(item) >= activeMinIdleDays {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// filterOnlyIdle drops non-idle items when --only-idle is set
+func filterOnlyIdle[T any](items []T) []T {
+	if !onlyIdle {
+		return items
+	}
+	var filtered []T
+	for _, item := range items {
+		if isIdle(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// countShown returns the number of resources shown in the tables, counting each resource of a group
+func countShown[T any](items []T) int {
+	count := 0
+	for _, item := range items {
+		if group, ok := any(item).(models.ResourceGroup); ok {
+			count += len(filterOnlyIdle(group.Resources()))
+			continue
+		}
+		if !onlyIdle || isIdle(item) {
+			count++
+		}
+	}
+	return count
+}
+
+// isIdle reports whether an item is flagged idle. Models without an explicit idle flag
+// only contain idle resources.
+func isIdle(item any) bool {
+	flagged, ok := item.(models.IdleFlagged)
+	return !ok || flagged.IdleFlag()
+}
+
+// parseTagFilters parses repeated key=value arguments into a tag filter map
+func parseTagFilters(args []string) (map[string]string, error) {
+	filters := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid --tag %q: expected key=value", arg)
+		}
+		filters[key] = value
+	}
+	return filters, nil
+}
+
+// daysSince returns the number of days elapsed since t, or 0 if t is nil
+func daysSince(t *time.Time) int {
+	if t == nil {
+		return 0
+	}
+	return utils.CalculateElapsedDays(*t)
+}
+
+// logSlowestRegions logs the regions that took the longest to scan, shown with --verbose
+func logSlowestRegions[T any](serviceName string, results []runner.RegionResult[T]) {
+	if len(results) < 2 {
+		return
+	}
+	slowest := make([]runner.RegionResult[T], len(results))
+	copy(slowest, results)
+	sort.SliceStable(slowest, func(i, j int) bool { return slowest[i].Duration > slowest[j].Duration })
+	var regions []string
+	for _, result := range slowest[:min(slowestRegionsShown, len(slowest))] {
+		regions = append(regions, fmt.Sprintf("%s (%.2fs)", result.Region, result.Duration.Seconds()))
+	}
+	slog.Info("Slowest regions", "service", serviceName, "regions", strings.Join(regions, ", "))
+}
+
+// printRegionErrors prints each distinct scan error once with the regions it occurred in,
+// so that one root cause such as expired credentials is not repeated for every region
+func printRegionErrors[T any](results []runner.RegionResult[T]) {
+	var causes []string
+	regionsByCause := make(map[string][]string)
+	firstMessage := make(map[string]string)
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		// Errors joined by a scanner are printed one by one
+		regionErrs := []error{result.Err}
+		if joined, ok := result.Err.(interface{ Unwrap() []error }); ok {
+			regionErrs = joined.Unwrap()
+		}
+		for _, err := range regionErrs {
+			outcome.recordErrors(1)
+			// Errors that only differ by the region name share a root cause
+			message := err.Error()
+			cause := strings.ReplaceAll(message, result.Region, "<region>")
+			if _, seen := regionsByCause[cause]; !seen {
+				causes = append(causes, cause)
+				firstMessage[cause] = message
+			}
+			regionsByCause[cause] = append(regionsByCause[cause], result.Region)
+		}
+	}
+
+	for _, cause := range causes {
+		failed := regionsByCause[cause]
+		if len(failed) == 1 && failed[0] == globalRegion {
+			fmt.Fprintf(out, "Error: %s\n", firstMessage[cause])
+			continue
+		}
+		if len(failed) == 1 {
+			fmt.Fprintf(out, "Error in region %s: %s\n", failed[0], firstMessage[cause])
+			continue
+		}
+		fmt.Fprintf(out, "Error in %d regions (%s): %s\n", len(failed), strings.Join(failed, ", "), cause)
+	}
+}
+
+// Common function to process results
+func processResults[T models.Keyed](ctx context.Context, serviceName string, results []runner.RegionResult[T], scanStartTime time.Time, s *scanProgress, idleDays func(T) int, printTable func(io.Writer, []T, time.Time, time.Duration), printSummary func(io.Writer, []T)) []models.CostSummary {
+	scanDuration := time.Since(scanStartTime)
+	// Each region is sorted by canonical key and the regions keep the requested order, so
+	// output does not depend on goroutine completion order
+	for i := range results {
+		results[i].Data = filterResources(results[i].Data, idleDays)
+		models.SortByKey(results[i].Data)
+	}
+	// Regions that failed or were cut short still contribute the resources scanned so far
+	var allData []T
+	for _, result := range results {
+		allData = append(allData, result.Data...)
+	}
+	timedOut := 0
+	for _, result := range results {
+		if result.TimedOut {
+			timedOut++
+		}
+	}
+	switch {
+	case ctx.Err() != nil:
+		s.FinalMSG = fmt.Sprintf("✗ [%d found, %d skipped] resources analyzed - Interrupted after %.2f seconds\n",
+			countShown(allData), s.skipped(), scanDuration.Seconds())
+	case timedOut > 0:
+		s.FinalMSG = fmt.Sprintf("✗ [%d found, %d skipped] resources analyzed - Completed in %.2f seconds, %d of %d regions timed out\n",
+			countShown(allData), s.skipped(), scanDuration.Seconds(), timedOut, len(results))
+	default:
+		s.FinalMSG = fmt.Sprintf("✓ [%d found, %d skipped] resources analyzed - Completed in %.2f seconds\n",
+			countShown(allData), s.skipped(), scanDuration.Seconds())
+	}
+	s.Stop()
+
+	// Display API init message if any (moved here for consistency)
+	if msg := pricing.GetInitMessage(); msg != "" {
+		fmt.Fprintln(out, msg)
+	}
+
+	// Errors are printed after the spinner stops so they do not interleave with it
+	if ctx.Err() == nil {
+		printRegionErrors(results)
+	}
+	logSlowestRegions(serviceName, results)
+	recordFindings(&outcome, allData)
+
+	// Regions that failed or were cut short are left out of the history
+	keysByRegion := make(map[string][]string, len(results))
+	for _, result := range results {
+		if result.Err == nil && ctx.Err() == nil {
+			keysByRegion[result.Region] = idleKeys(result.Data)
+		}
+	}
+	trackFirstSeen(serviceName, keysByRegion)
+
+	// The table honors --only-idle while the summary keeps totals from the unfiltered set
+	printTable(tableOut(), filterOnlyIdle(allData), scanStartTime, scanDuration)
+	if printSummary != nil {
+		printSummary(tableOut(), allData)
+	}
+	printNameFilterNote()
+	addToReport(serviceName, allData, printSummary)
+	addToUpload(serviceName, allData)
+	addToCleanup(allData)
+	addToDeletion(allData)
+	trackHistory(serviceName, keysByRegion)
+
+	var summaries []models.CostSummary
+	for _, result := range results {
+		summaries = append(summaries, summarizeCosts(serviceName, result.Region, result.Data))
+	}
+	return summaries
+}
+
+// summarizeCosts counts the idle items found in one region and sums their estimated monthly cost.
+// Resource groups are expanded into their individual resources.
+func summarizeCosts[T any](serviceName, region string, items []T) models.CostSummary {
+	var zero T
+	_, estimated := any(zero).(models.Costed)
+	summary := models.CostSummary{Service: serviceName, Region: region, Estimated: estimated}
+	addCosts(&summary, items)
+	return summary
+}
+
+// addCosts adds the idle items and their estimated monthly cost to the summary
+func addCosts[T any](summary *models.CostSummary, items []T) {
+	for _, item := range items {
+		if group, ok := any(item).(models.ResourceGroup); ok {
+			addCosts(summary, group.Resources())
+			continue
+		}
+		if !isIdle(item) {
+			continue
+		}
+		summary.Count++
+		costed, ok := any(item).(models.Costed)
+		if !ok {
+			continue
+		}
+		if priced, ok := any(item).(models.Priced); ok && priced.PricingUnavailable() {
+			summary.Unpriced++
+			continue
+		}
+		summary.MonthlyCost += costed.MonthlyCost()
+		if keyed, ok := any(item).(models.Keyed); ok {
+			summary.Resources = append(summary.Resources, models.CostItem{
+				Name:        strings.TrimPrefix(keyed.SortKey(), summary.Region+"/"),
+				MonthlyCost: costed.MonthlyCost(),
+			})
+		}
+	}
+}
+
+// scanService scans a service in the regions of the scope behind the progress display and
+// reports the results. Global services show "Global" progress and are reported under globalRegion.
+func scanService[T models.Keyed](
+	ctx context.Context, // Context cancelled on Ctrl-C or --timeout
+	serviceName string, // Service name (for spinner message)
+	scope runner.Scope, // Regions to scan and the config loader of their API clients
+	scan runner.ScanFunc[T], // Function to get the data of the service in one region
+	idleDays func(T) int, // Function to get the idle age in days used by --min-idle-days (nil if not applicable)
+	printTable func(io.Writer, []T, time.Time, time.Duration), // Function to print results as a table
+	printSummary func(io.Writer, []T), // Function to print result summary (nil if printed with the table)
+) []models.CostSummary {
+	progressRegions := scope.Regions
+	if scope.Global {
+		progressRegions = nil
+	}
+	scanStartTime, s := startScan(serviceName, progressRegions)
+
+	// Regions signal their completion to the progress display as they finish
+	completions := make(chan progress.Completion)
+	consumed := make(chan struct{})
+	go func() {
+		s.consumeCompletions(completions)
+		close(consumed)
+	}()
+	results := runner.ScanRegions(ctx, scope, scan, func(result runner.RegionResult[T]) {
+		completions <- progress.Completion{Region: result.Region, Items: len(result.Data), Duration: result.Duration, Err: result.Err}
+	})
+	close(completions)
+	<-consumed
+
+	return processResults(ctx, serviceName, results, scanStartTime, s, idleDays, printTable, printSummary)
+}
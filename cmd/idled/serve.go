@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/younsl/idled/internal/runner"
+	"github.com/younsl/idled/internal/upload"
+)
+
+// serveMode is set by the "serve" command, which also serves the latest results as JSON
+var serveMode bool
+
+// newServeCommand creates the "serve" command, which rescans every --interval and serves the
+// latest results and metrics over HTTP until SIGTERM
+func newServeCommand() *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Rescan on a schedule and serve the latest results over HTTP",
+		Long: `Run the configured scan every --interval and serve the latest results on --listen:
+
+  GET /results            Results of the latest complete scan (JSON)
+  GET /results/{service}  Results of one service, e.g., /results/ec2
+  GET /metrics            Prometheus metrics of the latest complete scan
+  GET /healthz            Liveness and the time of the latest scan
+
+  idled serve --interval 12h --listen :8080 -s ec2,ebs,s3`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			serveMode = true
+			exportFormat = "prometheus"
+			runAndExit(cmd, nil)
+		},
+	}
+
+	// The services are chosen with --services, since serve has no per-service subcommands
+	serveCmd.Flags().StringSliceVarP(&services, "services", "s", nil,
+		fmt.Sprintf("AWS services to scan (comma separated, default: %s)", DefaultService))
+	addAllServiceFlags(serveCmd.Flags())
+	return serveCmd
+}
+
+// scanResults holds the results of the latest complete scan served by the "serve" command
+type scanResults struct {
+	mu        sync.RWMutex
+	doc       *upload.Document
+	services  map[string][]string // Display names of the services of each --services name
+	scannedAt time.Time
+	scanning  bool
+}
+
+// set replaces the served results with those of a complete scan
+func (s *scanResults) set(doc *upload.Document, results []runner.Result) {
+	services := make(map[string][]string)
+	for _, result := range results {
+		for _, summary := range result.Summaries {
+			if !containsFold(services[result.Service], summary.Service) {
+				services[result.Service] = append(services[result.Service], summary.Service)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc = doc
+	s.services = services
+	s.scannedAt = doc.Metadata.GeneratedAt
+}
+
+// setScanning records whether a scan is running, shown by /healthz
+func (s *scanResults) setScanning(scanning bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scanning = scanning
+}
+
+// register adds the results and health endpoints to mux
+func (s *scanResults) register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /results", s.handleResults)
+	mux.HandleFunc("GET /results/{service}", s.handleServiceResults)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+}
+
+// handleResults serves the results of every service
+func (s *scanResults) handleResults(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	doc := s.doc
+	s.mu.RUnlock()
+
+	if doc == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "no scan has completed yet"})
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// handleServiceResults serves the results of one service, named as in --services (e.g., eip)
+// or as in the tables (e.g., Elastic IP)
+func (s *scanResults) handleServiceResults(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("service")
+
+	s.mu.RLock()
+	doc := s.doc
+	names := s.services[strings.ToLower(name)]
+	s.mu.RUnlock()
+
+	if doc == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "no scan has completed yet"})
+		return
+	}
+	if len(names) == 0 {
+		names = []string{name}
+	}
+
+	filtered := upload.Document{Version: doc.Version, Metadata: doc.Metadata}
+	for _, service := range doc.Services {
+		if containsFold(names, service.Service) {
+			filtered.Services = append(filtered.Services, service)
+		}
+	}
+	for _, summary := range doc.Summaries {
+		if containsFold(names, summary.Service) {
+			filtered.Summaries = append(filtered.Summaries, summary)
+		}
+	}
+	if len(filtered.Services) == 0 && len(filtered.Summaries) == 0 {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "service " + name + " was not scanned"})
+		return
+	}
+	writeJSON(w, http.StatusOK, filtered)
+}
+
+// handleHealth reports that the server is up, with the time of the latest complete scan
+func (s *scanResults) handleHealth(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	health := struct {
+		Status   string     `json:"status"`
+		LastScan *time.Time `json:"lastScan,omitempty"`
+		Scanning bool       `json:"scanning"`
+	}{Status: "ok", Scanning: s.scanning}
+	if s.doc != nil {
+		lastScan := s.scannedAt
+		health.LastScan = &lastScan
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, health)
+}
+
+// writeJSON writes value as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+// containsFold reports whether names contains name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
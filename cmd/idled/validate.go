@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/younsl/idled/internal/cleanup"
+	"github.com/younsl/idled/internal/upload"
+	"github.com/younsl/idled/pkg/formatter"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// servingMetrics reports whether the run keeps rescanning and serves the results as metrics
+func servingMetrics() bool {
+	return exportFormat == "prometheus" && pushGatewayURL == ""
+}
+
+// validateClientFlags checks the timeouts, API limits, and endpoint override, which apply to
+// every API client including those of the policy and pricing lookups
+func validateClientFlags() error {
+	if scanTimeout < 0 || regionTimeout < 0 {
+		return errors.New("--timeout and --region-timeout must not be negative")
+	}
+	if maxAPIRPS < 0 || maxConcurrency < 0 || maxRetries < 0 {
+		return errors.New("--max-api-rps, --max-concurrency and --max-retries must not be negative")
+	}
+	if endpointURL != "" {
+		if parsed, err := url.Parse(endpointURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("Invalid --endpoint-url '%s' (expected e.g. http://localhost:4566)", endpointURL)
+		}
+	}
+	return nil
+}
+
+// validateFlags checks the scan settings once the config file and the policy are applied, and
+// the combinations of output flags that cannot be used together
+func validateFlags() error {
+	switch {
+	case minIdleDays < 0:
+		return errors.New("--min-idle-days must not be negative")
+	case logsIdleDays <= 0:
+		return errors.New("--logs-idle-days must be positive")
+	case snapshotDays <= 0:
+		return errors.New("--ebs-snapshot-days must be positive")
+	case iamKeyMaxAge <= 0:
+		return errors.New("--iam-key-max-age must be positive")
+	case cpuThreshold <= 0 || cpuThreshold > 100:
+		return errors.New("--cpu-threshold must be between 0 and 100")
+	case lambdaFailingRate <= 0 || lambdaFailingRate > 100:
+		return errors.New("--lambda-failing-error-rate must be between 0 and 100")
+	case networkThreshold <= 0:
+		return errors.New("--network-threshold must be positive")
+	case utilizationDays < 1 || utilizationDays > 63:
+		return errors.New("--utilization-days must be between 1 and 63")
+	}
+
+	switch groupBy {
+	case "", "region":
+	default:
+		return fmt.Errorf("Unsupported --group-by value '%s' (supported: region)", groupBy)
+	}
+
+	switch {
+	case findingsExitCode <= exitCodeError:
+		return fmt.Errorf("--findings-exit-code must be greater than %d", exitCodeError)
+	case alertThresholds.CostPerResource < 0 || alertThresholds.TotalCost < 0:
+		return errors.New("--alert-cost-per-resource and --alert-total-cost must not be negative")
+	case failThresholdCost < 0:
+		return errors.New("--fail-threshold-cost must not be negative")
+	}
+
+	switch exportFormat {
+	case "", "prometheus":
+	default:
+		return fmt.Errorf("Unsupported --export value '%s' (supported: prometheus)", exportFormat)
+	}
+	switch {
+	case scanInterval <= 0:
+		return errors.New("--interval must be positive")
+	case serveMode && pushGatewayURL != "":
+		return errors.New("--push-gateway cannot be used with serve")
+	case pricingCacheTTL <= 0:
+		return errors.New("--pricing-cache-ttl must be positive")
+	case pricingAPITimeout <= 0:
+		return errors.New("--pricing-api-timeout must be positive")
+	case appendOutput && outputPath == "":
+		return errors.New("--append requires --output-file")
+	}
+
+	switch outputFormat {
+	case outputFormatTable:
+	case outputFormatHTML, outputFormatMarkdown:
+		if outputFormat == outputFormatHTML && (outputPath == "" || appendOutput) {
+			return errors.New("--output html requires --output-file and cannot be used with --append")
+		}
+		if servingMetrics() {
+			return fmt.Errorf("--output %s cannot be used while serving metrics", outputFormat)
+		}
+	default:
+		return fmt.Errorf("Unsupported --output value '%s' (supported: table, markdown, html)", outputFormat)
+	}
+
+	switch {
+	case assumeYes && !deleteMode:
+		return errors.New("--yes requires --delete")
+	case deleteMode && servingMetrics():
+		return errors.New("--delete cannot be used while serving metrics")
+	case (diffMode || diffOnly) && servingMetrics():
+		return errors.New("--diff cannot be used while serving metrics")
+	case uploadKMSKeyID != "" && uploadS3URI == "":
+		return errors.New("--upload-kms-key-id requires --upload-s3")
+	case uploadS3URI != "" && servingMetrics():
+		return errors.New("--upload-s3 cannot be used while serving metrics")
+	case cleanupPath != "" && servingMetrics():
+		return errors.New("--generate-cleanup-script cannot be used while serving metrics")
+	}
+	return nil
+}
+
+// applyFlags parses the filters and sets up the table formatting, pricing, and outputs
+// selected by the validated flags
+func applyFlags() error {
+	var err error
+	if tagFilters, err = parseTagFilters(tagArgs); err != nil {
+		return err
+	}
+	if nameFilter, err = utils.NewNameFilter(includeArgs, excludeArgs); err != nil {
+		return err
+	}
+
+	formatter.SetTagColumns(showTags)
+	formatter.SetShowLinks(showLinks)
+	formatter.SetColumns(columnArgs)
+	if err := formatter.SetSortBy(sortBy); err != nil {
+		return err
+	}
+	formatter.SetGroupByRegion(groupBy == "region")
+	formatter.SetAlertCostPerResource(alertThresholds.CostPerResource)
+
+	if err := setupPricing(); err != nil {
+		return err
+	}
+
+	if outputFormat == outputFormatHTML || outputFormat == outputFormatMarkdown {
+		scanReport = newScanReport()
+	}
+
+	if diffOnly {
+		diffMode = true
+	}
+	// Serving metrics rescans continuously, so no snapshots are saved
+	if servingMetrics() {
+		noHistory = true
+	}
+
+	if uploadS3URI != "" {
+		location, err := upload.ParseLocation(uploadS3URI)
+		if err != nil {
+			return err
+		}
+		uploadLocation = &location
+	}
+
+	if cleanupPath != "" {
+		cleanupScript = cleanup.New()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+func TestValidateFlags(t *testing.T) {
+	// Binding the flags resets the globals to their defaults
+	t.Cleanup(func() { newRootCommand() })
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "defaults"},
+		{name: "negative idle days", args: []string{"--min-idle-days", "-1"}, wantErr: "--min-idle-days must not be negative"},
+		{name: "cpu threshold above 100", args: []string{"--cpu-threshold", "101"}, wantErr: "--cpu-threshold must be between 0 and 100"},
+		{name: "unsupported group by", args: []string{"--group-by", "service"}, wantErr: "Unsupported --group-by value 'service' (supported: region)"},
+		{name: "html without a file", args: []string{"--output", "html"}, wantErr: "--output html requires --output-file and cannot be used with --append"},
+		{name: "markdown while serving metrics", args: []string{"--output", "markdown", "--export", "prometheus"},
+			wantErr: "--output markdown cannot be used while serving metrics"},
+		{name: "markdown pushed to a gateway", args: []string{"--output", "markdown", "--export", "prometheus", "--push-gateway", "http://localhost:9091"}},
+		{name: "yes without delete", args: []string{"--yes"}, wantErr: "--yes requires --delete"},
+		{name: "diff only while serving metrics", args: []string{"--diff-only", "--export", "prometheus"},
+			wantErr: "--diff cannot be used while serving metrics"},
+		{name: "kms key without upload", args: []string{"--upload-kms-key-id", "alias/idled"}, wantErr: "--upload-kms-key-id requires --upload-s3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRootCommand()
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+			err := validateFlags()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateFlags() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateFlags() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateClientFlags(t *testing.T) {
+	t.Cleanup(func() { newRootCommand() })
+
+	for args, wantErr := range map[string]string{
+		"":                            "",
+		"--endpoint-url=localhost":    "Invalid --endpoint-url 'localhost' (expected e.g. http://localhost:4566)",
+		"--max-retries=-1":            "--max-api-rps, --max-concurrency and --max-retries must not be negative",
+		"--region-timeout=-1s":        "--timeout and --region-timeout must not be negative",
+		"--endpoint-url=http://h:1/x": "",
+	} {
+		cmd := newRootCommand()
+		if args != "" {
+			if err := cmd.ParseFlags([]string{args}); err != nil {
+				t.Fatalf("ParseFlags(%s) error = %v", args, err)
+			}
+		}
+		err := validateClientFlags()
+		if (err == nil && wantErr != "") || (err != nil && err.Error() != wantErr) {
+			t.Errorf("validateClientFlags() with %q error = %v, want %q", args, err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Command generate-default-prices regenerates the bundled fallback price table of pkg/pricing
+// from the AWS Pricing API. It needs AWS credentials allowed to call pricing:GetProducts and
+// prints the table to stdout:
+//
+//	go run ./hack/generate-default-prices > pkg/pricing/data/default-prices.json
+//
+// Prices that cannot be retrieved are left out with a warning on stderr.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// instanceFamilies lists the instance families in the table with their sizes
+var instanceFamilies = map[string][]string{
+	"t3":  {"nano", "micro", "small", "medium", "large", "xlarge", "2xlarge"},
+	"t3a": {"nano", "micro", "small", "medium", "large", "xlarge", "2xlarge"},
+	"t4g": {"nano", "micro", "small", "medium", "large", "xlarge", "2xlarge"},
+	"m5":  {"large", "xlarge", "2xlarge", "4xlarge"},
+	"m6i": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"m6g": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"m7i": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"m7g": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"c5":  {"large", "xlarge", "2xlarge", "4xlarge"},
+	"c6i": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"c6g": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"c7g": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"r5":  {"large", "xlarge", "2xlarge", "4xlarge"},
+	"r6i": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"r6g": {"large", "xlarge", "2xlarge", "4xlarge"},
+	"r7g": {"large", "xlarge", "2xlarge", "4xlarge"},
+}
+
+// volumeTypes lists the EBS volume types in the table
+var volumeTypes = []string{"gp2", "gp3", "io1", "io2", "st1", "sc1", "standard"}
+
+func main() {
+	table := pricing.DefaultPriceTable{
+		Version:     1,
+		GeneratedAt: time.Now().UTC().Format("2006-01-02"),
+		Source:      "AWS Pricing API (on-demand, shared tenancy, Linux for EC2)",
+		EC2:         make(map[string]map[string]float64),
+		EBS:         make(map[string]map[string]float64),
+	}
+
+	regions := make([]string, 0, len(utils.RegionDescriptiveNames))
+	for region := range utils.RegionDescriptiveNames {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	for _, region := range regions {
+		// The Pricing API has no prices for the China regions, which are billed separately
+		if utils.PartitionForRegion(region) == utils.PartitionAWSChina {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Retrieving prices in %s\n", region)
+
+		table.EC2[region] = make(map[string]float64)
+		for family, sizes := range instanceFamilies {
+			for _, size := range sizes {
+				instanceType := family + "." + size
+				price, source := pricing.GetInstanceHourlyPriceWithSource(instanceType, region, pricing.EC2OperatingSystemLinux)
+				if source != string(pricing.PricingSourceAPI) {
+					fmt.Fprintf(os.Stderr, "WARNING: no price for %s in %s\n", instanceType, region)
+					continue
+				}
+				table.EC2[region][instanceType] = price
+			}
+		}
+
+		table.EBS[region] = make(map[string]float64)
+		for _, volumeType := range volumeTypes {
+			price, source := pricing.CalculateEBSMonthlyCostWithSource(volumeType, 1, 0, 0, region)
+			if source != string(pricing.PricingSourceAPI) {
+				fmt.Fprintf(os.Stderr, "WARNING: no price for %s volumes in %s\n", volumeType, region)
+				continue
+			}
+			table.EBS[region][volumeType] = price
+		}
+	}
+
+	data, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode price table: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,64 @@
+package alert
+
+import (
+	"sort"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// Thresholds holds the alert thresholds in USD per month. Zero disables a threshold.
+type Thresholds struct {
+	CostPerResource float64 // Alert on any idle resource costing more than this (--alert-cost-per-resource)
+	TotalCost       float64 // Alert when the idle resources of all services cost more than this (--alert-total-cost)
+}
+
+// Enabled reports whether any threshold is set
+func (t Thresholds) Enabled() bool {
+	return t.CostPerResource > 0 || t.TotalCost > 0
+}
+
+// Resource is an idle resource whose estimated monthly cost exceeds the per-resource threshold
+type Resource struct {
+	models.CostItem
+	Service string // Service display name
+	Region  string // AWS region, or "global" for global services
+}
+
+// Alerts holds the thresholds exceeded by the results of a scan
+type Alerts struct {
+	Thresholds    Thresholds
+	Resources     []Resource // Resources above the per-resource threshold, most expensive first
+	TotalCost     float64    // Estimated monthly cost of the priced idle resources of all services
+	TotalExceeded bool       // Whether TotalCost exceeds the total threshold
+}
+
+// Any reports whether any threshold was exceeded
+func (a Alerts) Any() bool {
+	return len(a.Resources) > 0 || a.TotalExceeded
+}
+
+// Evaluate checks the cost summaries of all scanned services against the thresholds.
+// Only priced idle resources count, as in the savings report.
+func Evaluate(summaries []models.CostSummary, thresholds Thresholds) Alerts {
+	alerts := Alerts{Thresholds: thresholds}
+	for _, summary := range summaries {
+		if !summary.Estimated {
+			continue
+		}
+		alerts.TotalCost += summary.MonthlyCost
+		if thresholds.CostPerResource <= 0 {
+			continue
+		}
+		for _, item := range summary.Resources {
+			if item.MonthlyCost > thresholds.CostPerResource {
+				alerts.Resources = append(alerts.Resources, Resource{CostItem: item, Service: summary.Service, Region: summary.Region})
+			}
+		}
+	}
+	alerts.TotalExceeded = thresholds.TotalCost > 0 && alerts.TotalCost > thresholds.TotalCost
+
+	sort.SliceStable(alerts.Resources, func(i, j int) bool {
+		return alerts.Resources[i].MonthlyCost > alerts.Resources[j].MonthlyCost
+	})
+	return alerts
+}
@@ -0,0 +1,116 @@
+package alert
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// ebsSummary returns the summary of priced EBS volumes in us-east-1 with the given costs
+func ebsSummary(costs ...float64) models.CostSummary {
+	summary := models.CostSummary{Service: "EBS", Region: "us-east-1", Estimated: true}
+	for i, cost := range costs {
+		summary.Resources = append(summary.Resources, models.CostItem{Name: "vol-" + string(rune('a'+i)), MonthlyCost: cost})
+		summary.MonthlyCost += cost
+		summary.Count++
+	}
+	return summary
+}
+
+// resourceNames returns the names of the alerted resources in order
+func resourceNames(alerts Alerts) []string {
+	var names []string
+	for _, resource := range alerts.Resources {
+		names = append(names, resource.Name)
+	}
+	return names
+}
+
+func TestEvaluateCostPerResource(t *testing.T) {
+	tests := []struct {
+		name      string
+		summaries []models.CostSummary
+		want      []string
+	}{
+		{name: "below the threshold", summaries: []models.CostSummary{ebsSummary(49.99)}},
+		{name: "equal to the threshold", summaries: []models.CostSummary{ebsSummary(50)}},
+		{name: "above the threshold", summaries: []models.CostSummary{ebsSummary(50.01)}, want: []string{"vol-a"}},
+		{name: "most expensive first", summaries: []models.CostSummary{ebsSummary(60, 10, 80)}, want: []string{"vol-c", "vol-a"}},
+		{name: "no resources"},
+		{name: "empty summary", summaries: []models.CostSummary{ebsSummary()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := Evaluate(tt.summaries, Thresholds{CostPerResource: 50})
+
+			if got := resourceNames(alerts); !slices.Equal(got, tt.want) {
+				t.Errorf("alerted resources = %v, want %v", got, tt.want)
+			}
+			if alerts.Any() != (len(tt.want) > 0) {
+				t.Errorf("Any() = %t, want %t", alerts.Any(), len(tt.want) > 0)
+			}
+			if alerts.TotalExceeded {
+				t.Error("TotalExceeded = true without a total threshold")
+			}
+		})
+	}
+}
+
+func TestEvaluateTotalCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		summaries []models.CostSummary
+		wantTotal float64
+		wantAlert bool
+	}{
+		{name: "below the threshold", summaries: []models.CostSummary{ebsSummary(40, 59)}, wantTotal: 99},
+		{name: "equal to the threshold", summaries: []models.CostSummary{ebsSummary(40, 60)}, wantTotal: 100},
+		{name: "above the threshold", summaries: []models.CostSummary{ebsSummary(40, 60.5)}, wantTotal: 100.5, wantAlert: true},
+		{
+			name:      "across services and regions",
+			summaries: []models.CostSummary{ebsSummary(60), {Service: "Elastic IP", Region: "eu-west-1", Estimated: true, MonthlyCost: 41, Count: 1}},
+			wantTotal: 101,
+			wantAlert: true,
+		},
+		{
+			name:      "services without cost estimates do not count",
+			summaries: []models.CostSummary{ebsSummary(60), {Service: "IAM", Region: "global", MonthlyCost: 500, Count: 3}},
+			wantTotal: 60,
+		},
+		{name: "no resources"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := Evaluate(tt.summaries, Thresholds{TotalCost: 100})
+
+			if alerts.TotalCost != tt.wantTotal {
+				t.Errorf("TotalCost = %v, want %v", alerts.TotalCost, tt.wantTotal)
+			}
+			if alerts.TotalExceeded != tt.wantAlert || alerts.Any() != tt.wantAlert {
+				t.Errorf("TotalExceeded = %t, Any() = %t, want %t", alerts.TotalExceeded, alerts.Any(), tt.wantAlert)
+			}
+			if len(alerts.Resources) != 0 {
+				t.Errorf("alerted resources %v without a per-resource threshold", resourceNames(alerts))
+			}
+		})
+	}
+}
+
+func TestEvaluateDisabled(t *testing.T) {
+	thresholds := Thresholds{}
+	if thresholds.Enabled() {
+		t.Error("Enabled() = true without thresholds")
+	}
+
+	alerts := Evaluate([]models.CostSummary{ebsSummary(1000)}, thresholds)
+
+	if alerts.Any() {
+		t.Errorf("Evaluate() without thresholds = %+v, want no alert", alerts)
+	}
+	if alerts.TotalCost != 1000 {
+		t.Errorf("TotalCost = %v, want 1000", alerts.TotalCost)
+	}
+}
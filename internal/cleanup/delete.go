@@ -0,0 +1,191 @@
+package cleanup
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// confirmationPhrase must be typed to confirm the deletion unless prompts are skipped
+const confirmationPhrase = "delete"
+
+// ErrAborted is returned when the user selects no resources or does not confirm the deletion
+var ErrAborted = errors.New("deletion aborted")
+
+// Deleter deletes idle resources. Each method deletes a single resource in the given region.
+type Deleter interface {
+	DeleteVolume(ctx context.Context, region, volumeID string) error
+	ReleaseAddress(ctx context.Context, region, allocationID string) error
+	DeleteBucket(ctx context.Context, region, bucketName string) error
+}
+
+// Candidate is an idle resource that is safe to delete
+type Candidate struct {
+	Service     string
+	Name        string
+	Region      string
+	MonthlyCost float64
+	delete      func(ctx context.Context, d Deleter) error
+}
+
+// DeleteSummary counts the outcome of a deletion run
+type DeleteSummary struct {
+	Deleted       int
+	Failed        int
+	ReclaimedCost float64 // Estimated monthly cost of the deleted resources
+}
+
+// Candidates returns the resources that are safe to delete: unattached EBS volumes,
+// unassociated Elastic IPs, and empty idle S3 buckets without deletion protection.
+// Other resources are skipped.
+func Candidates(resources []any) []Candidate {
+	var candidates []Candidate
+	for _, resource := range resources {
+		var candidate Candidate
+		switch r := resource.(type) {
+		case models.VolumeInfo:
+			if r.AttachedInstanceID != "" {
+				continue
+			}
+			candidate = Candidate{Service: "EBS", Name: withName(r.VolumeID, r.Name), Region: r.Region,
+				delete: func(ctx context.Context, d Deleter) error { return d.DeleteVolume(ctx, r.Region, r.VolumeID) }}
+		case models.EIPInfo:
+			if r.AssociationID != "" {
+				continue
+			}
+			candidate = Candidate{Service: "Elastic IP", Name: withName(r.PublicIP, r.AllocationID), Region: r.Region,
+				delete: func(ctx context.Context, d Deleter) error { return d.ReleaseAddress(ctx, r.Region, r.AllocationID) }}
+		case models.BucketInfo:
+			if !r.IsIdle || !r.IsEmpty || r.Protected {
+				continue
+			}
+			candidate = Candidate{Service: "S3", Name: r.BucketName, Region: r.Region,
+				delete: func(ctx context.Context, d Deleter) error { return d.DeleteBucket(ctx, r.Region, r.BucketName) }}
+		default:
+			continue
+		}
+		if costed, ok := resource.(models.Costed); ok {
+			candidate.MonthlyCost = costed.MonthlyCost()
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// PrintCandidates prints the numbered candidate list used by Select
+func PrintCandidates(w io.Writer, candidates []Candidate) {
+	fmt.Fprintf(w, "\n## Deletion Candidates\n")
+	for i, candidate := range candidates {
+		fmt.Fprintf(w, "%3d. [%s] %s (%s) est. $%.2f/month\n", i+1, candidate.Service, candidate.Name, candidate.Region, candidate.MonthlyCost)
+	}
+}
+
+// Select asks which candidates to delete: all, none, or a comma separated list of
+// indices and ranges (e.g., 1,3,5-7). It then asks to type the confirmation phrase.
+// ErrAborted is returned if nothing is selected or the deletion is not confirmed.
+func Select(in io.Reader, w io.Writer, candidates []Candidate) ([]Candidate, error) {
+	reader := bufio.NewReader(in)
+
+	var selected []Candidate
+	for selected == nil {
+		fmt.Fprint(w, "\nSelect resources to delete (all, none, or indices like 1,3,5-7): ")
+		answer, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(answer) {
+		case "none", "":
+			return nil, ErrAborted
+		case "all":
+			selected = candidates
+		default:
+			selected, err = selectIndices(candidates, answer)
+			if err != nil {
+				fmt.Fprintf(w, "%v\n", err)
+			}
+		}
+	}
+
+	var total float64
+	for _, candidate := range selected {
+		total += candidate.MonthlyCost
+	}
+	fmt.Fprintf(w, "\nThis permanently deletes %d resources (est. $%.2f/month). Type '%s' to confirm: ",
+		len(selected), total, confirmationPhrase)
+	answer, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if answer != confirmationPhrase {
+		return nil, ErrAborted
+	}
+	return selected, nil
+}
+
+// Delete deletes the candidates one by one, reporting each result. A failed deletion
+// does not stop the remaining ones. Candidates not yet deleted when ctx is cancelled are skipped.
+func Delete(ctx context.Context, d Deleter, w io.Writer, candidates []Candidate) DeleteSummary {
+	var summary DeleteSummary
+	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := candidate.delete(ctx, d); err != nil {
+			summary.Failed++
+			fmt.Fprintf(w, "✗ [%s] %s (%s): %v\n", candidate.Service, candidate.Name, candidate.Region, err)
+			continue
+		}
+		summary.Deleted++
+		summary.ReclaimedCost += candidate.MonthlyCost
+		fmt.Fprintf(w, "✓ [%s] %s (%s) deleted\n", candidate.Service, candidate.Name, candidate.Region)
+	}
+	return summary
+}
+
+// selectIndices returns the candidates at the 1-based indices and ranges of the answer
+func selectIndices(candidates []Candidate, answer string) ([]Candidate, error) {
+	chosen := make(map[int]bool)
+	for _, part := range strings.Split(answer, ",") {
+		part = strings.TrimSpace(part)
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+				return nil, fmt.Errorf("invalid selection %q", part)
+			}
+		}
+		if start < 1 || end > len(candidates) || start > end {
+			return nil, fmt.Errorf("selection %q is out of range 1-%d", part, len(candidates))
+		}
+		for i := start; i <= end; i++ {
+			chosen[i-1] = true
+		}
+	}
+
+	var selected []Candidate
+	for i, candidate := range candidates {
+		if chosen[i] {
+			selected = append(selected, candidate)
+		}
+	}
+	return selected, nil
+}
+
+// readLine reads a trimmed line. A closed input aborts the deletion.
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", ErrAborted
+	}
+	return strings.TrimSpace(line), nil
+}
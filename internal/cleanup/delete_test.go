@@ -0,0 +1,151 @@
+package cleanup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// fakeDeleter records the deleted resources and fails the ones listed in fail
+type fakeDeleter struct {
+	deleted []string
+	fail    map[string]bool
+}
+
+func (d *fakeDeleter) record(id string) error {
+	if d.fail[id] {
+		return errors.New("access denied")
+	}
+	d.deleted = append(d.deleted, id)
+	return nil
+}
+
+func (d *fakeDeleter) DeleteVolume(ctx context.Context, region, volumeID string) error {
+	return d.record(volumeID)
+}
+
+func (d *fakeDeleter) ReleaseAddress(ctx context.Context, region, allocationID string) error {
+	return d.record(allocationID)
+}
+
+func (d *fakeDeleter) DeleteBucket(ctx context.Context, region, bucketName string) error {
+	return d.record(bucketName)
+}
+
+// deleteAll deletes the candidates and returns the resources passed to the deleter
+func deleteAll(t *testing.T, candidates []Candidate) []string {
+	t.Helper()
+	d := &fakeDeleter{}
+	Delete(context.Background(), d, &bytes.Buffer{}, candidates)
+	return d.deleted
+}
+
+func TestCandidates(t *testing.T) {
+	resources := []any{
+		models.VolumeInfo{VolumeID: "vol-unattached", Region: "us-east-1"},
+		models.VolumeInfo{VolumeID: "vol-attached", Region: "us-east-1", AttachedInstanceID: "i-stopped"},
+		models.EIPInfo{AllocationID: "eipalloc-free", PublicIP: "198.51.100.1", Region: "us-east-1"},
+		models.EIPInfo{AllocationID: "eipalloc-used", PublicIP: "198.51.100.2", Region: "us-east-1", AssociationID: "eipassoc-1"},
+		models.BucketInfo{BucketName: "empty-idle", Region: "us-east-1", IsIdle: true, IsEmpty: true},
+		models.BucketInfo{BucketName: "non-empty", Region: "us-east-1", IsIdle: true},
+		models.BucketInfo{BucketName: "protected", Region: "us-east-1", IsIdle: true, IsEmpty: true, Protected: true},
+		models.BucketInfo{BucketName: "active", Region: "us-east-1", IsEmpty: true},
+		models.InstanceInfo{InstanceID: "i-stopped", Region: "us-east-1"},
+	}
+
+	candidates := Candidates(resources)
+
+	want := []string{"vol-unattached", "eipalloc-free", "empty-idle"}
+	if got := deleteAll(t, candidates); !slices.Equal(got, want) {
+		t.Errorf("Candidates() deletes %v, want %v", got, want)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	candidates := Candidates([]any{
+		models.VolumeInfo{VolumeID: "vol-1", Region: "us-east-1"},
+		models.VolumeInfo{VolumeID: "vol-2", Region: "us-east-1"},
+		models.VolumeInfo{VolumeID: "vol-3", Region: "us-east-1"},
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		aborted bool
+	}{
+		{name: "all confirmed", input: "all\ndelete\n", want: []string{"vol-1", "vol-2", "vol-3"}},
+		{name: "indices and ranges confirmed", input: "1,3\ndelete\n", want: []string{"vol-1", "vol-3"}},
+		{name: "invalid selection asked again", input: "9\n2-3\ndelete\n", want: []string{"vol-2", "vol-3"}},
+		{name: "confirmation answered yes", input: "all\nyes\n", aborted: true},
+		{name: "confirmation in upper case", input: "all\nDELETE\n", aborted: true},
+		{name: "confirmation left empty", input: "all\n\n", aborted: true},
+		{name: "input closed before the confirmation", input: "all\n", aborted: true},
+		{name: "none selected", input: "none\n", aborted: true},
+		{name: "empty selection", input: "\n", aborted: true},
+		{name: "no input", input: "", aborted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := Select(strings.NewReader(tt.input), &bytes.Buffer{}, candidates)
+			if tt.aborted {
+				if !errors.Is(err, ErrAborted) {
+					t.Fatalf("Select() error = %v, want ErrAborted", err)
+				}
+				if selected != nil {
+					t.Errorf("Select() = %d candidates, want none", len(selected))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if got := deleteAll(t, selected); !slices.Equal(got, tt.want) {
+				t.Errorf("Select() selects %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteContinuesAfterAFailure(t *testing.T) {
+	candidates := Candidates([]any{
+		models.VolumeInfo{VolumeID: "vol-1", Region: "us-east-1"},
+		models.VolumeInfo{VolumeID: "vol-2", Region: "us-east-1"},
+		models.EIPInfo{AllocationID: "eipalloc-1", PublicIP: "198.51.100.1", Region: "us-east-1"},
+	})
+	d := &fakeDeleter{fail: map[string]bool{"vol-2": true}}
+	var output bytes.Buffer
+
+	summary := Delete(context.Background(), d, &output, candidates)
+
+	if want := []string{"vol-1", "eipalloc-1"}; !slices.Equal(d.deleted, want) {
+		t.Errorf("deleted %v, want %v", d.deleted, want)
+	}
+	if summary.Deleted != 2 || summary.Failed != 1 {
+		t.Errorf("summary = %d deleted, %d failed, want 2 deleted, 1 failed", summary.Deleted, summary.Failed)
+	}
+	if !strings.Contains(output.String(), "✗ [EBS] vol-2 (us-east-1): access denied") {
+		t.Errorf("output does not report the failed deletion:\n%s", output.String())
+	}
+}
+
+func TestDeleteStopsWhenCancelled(t *testing.T) {
+	candidates := Candidates([]any{
+		models.VolumeInfo{VolumeID: "vol-1", Region: "us-east-1"},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d := &fakeDeleter{}
+
+	summary := Delete(ctx, d, &bytes.Buffer{}, candidates)
+
+	if len(d.deleted) != 0 || summary.Deleted != 0 {
+		t.Errorf("deleted %v after cancellation, want nothing", d.deleted)
+	}
+}
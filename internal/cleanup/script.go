@@ -0,0 +1,289 @@
+package cleanup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// unknownIdleDays marks resources whose idle age is not known
+const unknownIdleDays = -1
+
+// Entry is the cleanup command for one idle resource
+type Entry struct {
+	Service       string  // Service section of the script (e.g., EC2)
+	Name          string  // Resource ID or name, with the Name tag if any
+	Region        string  // AWS region
+	IdleDays      int     // Days idle, unknownIdleDays if not known
+	MonthlySaving float64 // Estimated monthly cost of the resource
+	Command       string  // aws-cli command deleting the resource
+	Disabled      string  // Reason the command is commented out, empty if it is runnable
+}
+
+// Script collects cleanup commands for idle resources. It only generates the commands
+// and never calls AWS itself.
+type Script struct {
+	entries []Entry
+}
+
+// New creates an empty cleanup script
+func New() *Script {
+	return &Script{}
+}
+
+// Add adds a cleanup command for every idle resource of a supported type. Non-idle resources
+// and resources without a cleanup command (e.g., IAM) are skipped.
+func (s *Script) Add(resources []any) {
+	for _, resource := range resources {
+		if flagged, ok := resource.(models.IdleFlagged); ok && !flagged.IdleFlag() {
+			continue
+		}
+		entry, ok := entryFor(resource)
+		if !ok {
+			continue
+		}
+		// Deleting a protected resource fails until its protection is turned off, which is left to the user
+		if protectable, ok := resource.(models.Protectable); ok {
+			if protected, reason := protectable.DeletionProtection(); protected {
+				entry.Disabled = fmt.Sprintf("%s enabled, disable it first", reason)
+			}
+		}
+		if costed, ok := resource.(models.Costed); ok {
+			entry.MonthlySaving = costed.MonthlyCost()
+		}
+		s.entries = append(s.entries, entry)
+	}
+}
+
+// Len returns the number of resources in the script
+func (s *Script) Len() int {
+	return len(s.entries)
+}
+
+// Write writes the script. It starts with set -euo pipefail and a confirmation prompt, and
+// every command is preceded by a comment with the resource, idle days, and estimated saving.
+func (s *Script) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	runnable := 0
+	var total float64
+	for _, entry := range s.entries {
+		if entry.Disabled == "" {
+			runnable++
+			total += entry.MonthlySaving
+		}
+	}
+
+	fmt.Fprintln(bw, "#!/usr/bin/env bash")
+	fmt.Fprintf(bw, "# Cleanup script generated by idled at %s.\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintln(bw, "# idled never runs this script. Review every command before running it.")
+	fmt.Fprintln(bw, "# Commands for resources with an ambiguous status are commented out.")
+	fmt.Fprintln(bw, "set -euo pipefail")
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "echo %s\n", shellQuote(fmt.Sprintf("This script deletes %d idle AWS resources (est. $%.2f/month).", runnable, total)))
+	fmt.Fprintln(bw, `read -r -p "Type 'yes' to continue: " answer`)
+	fmt.Fprintln(bw, `if [[ "${answer}" != "yes" ]]; then`)
+	fmt.Fprintln(bw, `  echo "Aborted."`)
+	fmt.Fprintln(bw, `  exit 1`)
+	fmt.Fprintln(bw, `fi`)
+
+	service := ""
+	for _, entry := range s.entries {
+		if entry.Service != service {
+			service = entry.Service
+			fmt.Fprintf(bw, "\n# ===== %s =====\n", service)
+		}
+
+		idle := "idle for an unknown time"
+		if entry.IdleDays != unknownIdleDays {
+			idle = fmt.Sprintf("idle %d days", entry.IdleDays)
+		}
+		fmt.Fprintf(bw, "\n# %s in %s: %s, est. $%.2f/month\n", comment(entry.Name), entry.Region, idle, entry.MonthlySaving)
+		if entry.Disabled != "" {
+			fmt.Fprintf(bw, "# Commented out: %s\n", comment(entry.Disabled))
+			fmt.Fprintf(bw, "# %s\n", entry.Command)
+			continue
+		}
+		fmt.Fprintln(bw, entry.Command)
+	}
+
+	if len(s.entries) == 0 {
+		fmt.Fprintln(bw, "\n# No idle resources with a cleanup command were found.")
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write cleanup script: %w", err)
+	}
+	return nil
+}
+
+// entryFor builds the cleanup entry of a supported resource model
+func entryFor(resource any) (Entry, bool) {
+	switch r := resource.(type) {
+	case models.InstanceInfo:
+		entry := Entry{
+			Service:  "EC2",
+			Name:     withName(r.InstanceID, r.Name),
+			Region:   r.Region,
+			IdleDays: r.ElapsedDays,
+			Command:  awsCommand(r.Region, "ec2", "terminate-instances", "--instance-ids", r.InstanceID),
+		}
+		// The launch time only bounds the stop time, so the instance may have stopped recently
+		if r.StoppedTime == nil || r.StoppedTimeSource == models.StoppedTimeSourceLaunchTime {
+			entry.IdleDays = unknownIdleDays
+			entry.Disabled = "stop time unknown"
+		}
+		return entry, true
+	case models.VolumeInfo:
+		entry := Entry{
+			Service:  "EBS",
+			Name:     withName(r.VolumeID, r.Name),
+			Region:   r.Region,
+			IdleDays: r.ElapsedDaysSinceUsed,
+			Command:  awsCommand(r.Region, "ec2", "delete-volume", "--volume-id", r.VolumeID),
+		}
+		if r.AttachedInstanceID != "" {
+			entry.Disabled = fmt.Sprintf("attached to stopped instance %s, detach it first", r.AttachedInstanceID)
+		}
+		return entry, true
+	case models.EIPInfo:
+		entry := Entry{
+			Service:  "Elastic IP",
+			Name:     withName(r.PublicIP, r.AllocationID),
+			Region:   r.Region,
+			IdleDays: unknownIdleDays,
+			Command:  awsCommand(r.Region, "ec2", "release-address", "--allocation-id", r.AllocationID),
+		}
+		if r.AssociationID != "" {
+			entry.Disabled = fmt.Sprintf("still associated (%s), disassociate it first", r.AssociationState)
+		}
+		return entry, true
+	case models.LambdaFunctionInfo:
+		entry := Entry{
+			Service:  "Lambda",
+			Name:     r.FunctionName,
+			Region:   r.Region,
+			IdleDays: r.IdleDays,
+			Command:  awsCommand(r.Region, "lambda", "delete-function", "--function-name", r.FunctionName),
+		}
+		if r.Status == models.LambdaStatusFailing {
+			rate, _ := r.ErrorRate()
+			entry.Disabled = fmt.Sprintf("still invoked, with %.1f%% of invocations failing", rate)
+		} else if r.HasTrigger {
+			entry.Disabled = "has triggers configured"
+		}
+		return entry, true
+	case models.LogGroupInfo:
+		entry := Entry{
+			Service:  "Logs",
+			Name:     r.Name,
+			Region:   r.Region,
+			IdleDays: int(time.Since(time.UnixMilli(r.LastEventMillis)).Hours() / 24),
+			Command:  awsCommand(r.Region, "logs", "delete-log-group", "--log-group-name", r.Name),
+		}
+		if r.FilterCheckFailed {
+			entry.Disabled = "subscription and metric filter check failed"
+		} else if r.IsReferenced() {
+			entry.Disabled = "has subscription or metric filters"
+		}
+		return entry, true
+	case models.ELBResource:
+		entry := Entry{
+			Service:  "ELB",
+			Name:     r.Name,
+			Region:   r.Region,
+			IdleDays: unknownIdleDays,
+			Command:  awsCommand(r.Region, "elbv2", "delete-load-balancer", "--load-balancer-arn", r.ARN),
+		}
+		// Classic Load Balancers are deleted by name through the ELB (v1) API
+		if r.Type == "CLB" {
+			entry.Command = awsCommand(r.Region, "elb", "delete-load-balancer", "--load-balancer-name", r.Name)
+		}
+		if r.MetricCheckFailed {
+			entry.Disabled = "traffic metric check failed, idleness was assumed"
+		}
+		return entry, true
+	case models.RepositoryInfo:
+		entry := Entry{
+			Service:  "ECR",
+			Name:     r.Name,
+			Region:   r.Region,
+			IdleDays: daysSince(r.LastPush, r.CreatedAt),
+			Command:  awsCommand(r.Region, "ecr", "delete-repository", "--repository-name", r.Name),
+		}
+		if r.ImageCount > 0 {
+			entry.Disabled = fmt.Sprintf("contains %d images, add --force to delete them with the repository", r.ImageCount)
+		}
+		return entry, true
+	case models.SecretInfo:
+		return Entry{
+			Service:  "Secrets Manager",
+			Name:     r.Name,
+			Region:   r.Region,
+			IdleDays: r.IdleDays,
+			// Without --force-delete-without-recovery the secret can be restored for 30 days
+			Command: awsCommand(r.Region, "secretsmanager", "delete-secret", "--secret-id", r.ARN),
+		}, true
+	case models.BucketInfo:
+		entry := Entry{
+			Service:  "S3",
+			Name:     r.BucketName,
+			Region:   r.Region,
+			IdleDays: r.IdleDays,
+			Command:  awsCommand(r.Region, "s3", "rb", "s3://"+r.BucketName),
+		}
+		if !r.IsEmpty {
+			entry.Disabled = "bucket is not empty"
+		}
+		return entry, true
+	}
+	return Entry{}, false
+}
+
+// awsCommand builds an aws-cli command with shell-quoted arguments
+func awsCommand(region, service, operation string, args ...string) string {
+	parts := []string{"aws", service, operation, "--region", shellQuote(region)}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--") {
+			parts = append(parts, arg)
+		} else {
+			parts = append(parts, shellQuote(arg))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote quotes s for a POSIX shell unless it only contains safe characters
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// comment keeps text on a single comment line
+func comment(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// withName appends a display name in parentheses to a resource ID
+func withName(id, name string) string {
+	if name == "" || name == id {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", id, name)
+}
+
+// daysSince returns the days elapsed since the first non-nil time, or unknownIdleDays if all are nil
+func daysSince(times ...*time.Time) int {
+	for _, t := range times {
+		if t != nil {
+			return int(time.Since(*t).Hours() / 24)
+		}
+	}
+	return unknownIdleDays
+}
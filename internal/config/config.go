@@ -0,0 +1,256 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the config file looked up in the working directory, and as a dotfile in the home directory
+const FileName = "idled.yaml"
+
+// knownKeys are the top-level keys of a config file. Other keys are reported as unknown.
+var knownKeys = map[string]bool{
+	"regions":      true,
+	"services":     true,
+	"minIdleDays":  true,
+	"idleDays":     true,
+	"logsIdleDays": true,
+	"onlyIdle":     true,
+	"output":       true,
+	"profile":      true,
+	"tags":         true,
+
+	"alertCostPerResource": true,
+	"alertTotalCost":       true,
+}
+
+// File holds default settings read from a config file. Unset fields leave the
+// corresponding defaults untouched, and explicitly set flags take precedence.
+type File struct {
+	Regions      []string          `yaml:"regions"`      // Regions to scan (same as --regions)
+	Services     []string          `yaml:"services"`     // Services to scan (same as --services)
+	MinIdleDays  *int              `yaml:"minIdleDays"`  // Minimum idle age in days for every service (same as --min-idle-days)
+	IdleDays     map[string]int    `yaml:"idleDays"`     // Minimum idle age in days per service, overriding minIdleDays
+	LogsIdleDays *int              `yaml:"logsIdleDays"` // Days without events before a log group is idle (same as --logs-idle-days)
+	OnlyIdle     *bool             `yaml:"onlyIdle"`     // Hide non-idle resources (same as --only-idle)
+	Output       string            `yaml:"output"`       // Output format (same as --output)
+	Profile      string            `yaml:"profile"`      // AWS shared config profile (same as --profile)
+	Tags         map[string]string `yaml:"tags"`         // Tag filters (same as repeated --tag key=value)
+
+	AlertCostPerResource *float64 `yaml:"alertCostPerResource"` // Monthly cost above which an idle resource is alerted (same as --alert-cost-per-resource)
+	AlertTotalCost       *float64 `yaml:"alertTotalCost"`       // Total monthly cost above which a warning is printed (same as --alert-total-cost)
+}
+
+// Find returns the config file to load: the explicit path if set, otherwise ./idled.yaml,
+// then ~/.idled.yaml. It returns an empty path if no config file exists.
+func Find(explicit string) (string, error) {
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("config file %s: %w", explicit, err)
+		}
+		return explicit, nil
+	}
+
+	candidates := []string{FileName}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, "."+FileName))
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("config file %s: %w", candidate, err)
+		}
+	}
+	return "", nil
+}
+
+// Load reads and validates a config file. Unknown top-level keys do not fail the load,
+// they are returned by name so that the caller can warn about them.
+func Load(path string) (*File, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a YAML config document and returns its unknown top-level keys
+func Parse(data []byte) (*File, []string, error) {
+	var f File
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &f, nil, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return &f, nil, nil
+	}
+	document := root.Content[0]
+	if document.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("invalid config file: expected a mapping of settings")
+	}
+
+	var unknown []string
+	for i := 0; i < len(document.Content); i += 2 {
+		if key := document.Content[i].Value; !knownKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	if err := document.Decode(&f); err != nil {
+		return nil, nil, fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := f.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return &f, unknown, nil
+}
+
+// Validate checks that the config values are within the accepted ranges
+func (f *File) Validate() error {
+	if f.MinIdleDays != nil && *f.MinIdleDays < 0 {
+		return fmt.Errorf("invalid config file: minIdleDays must not be negative (got %d)", *f.MinIdleDays)
+	}
+	for service, days := range f.IdleDays {
+		if days < 0 {
+			return fmt.Errorf("invalid config file: idleDays.%s must not be negative (got %d)", service, days)
+		}
+	}
+	if f.LogsIdleDays != nil && *f.LogsIdleDays <= 0 {
+		return fmt.Errorf("invalid config file: logsIdleDays must be positive (got %d)", *f.LogsIdleDays)
+	}
+	for key := range f.Tags {
+		if key == "" {
+			return fmt.Errorf("invalid config file: tag keys must not be empty")
+		}
+	}
+	if f.AlertCostPerResource != nil && *f.AlertCostPerResource < 0 {
+		return fmt.Errorf("invalid config file: alertCostPerResource must not be negative (got %.2f)", *f.AlertCostPerResource)
+	}
+	if f.AlertTotalCost != nil && *f.AlertTotalCost < 0 {
+		return fmt.Errorf("invalid config file: alertTotalCost must not be negative (got %.2f)", *f.AlertTotalCost)
+	}
+	return nil
+}
+
+// Settings are the values of the settings a config file provides, as set by their flags or
+// their defaults
+type Settings struct {
+	Regions              []string
+	Services             []string
+	MinIdleDays          int
+	IdleDays             map[string]int // Minimum idle age per service
+	LogsIdleDays         int
+	OnlyIdle             bool
+	Output               string
+	Profile              string
+	Tags                 []string // key=value tag filters
+	AlertCostPerResource float64
+	AlertTotalCost       float64
+}
+
+// Apply replaces the settings of s with the values of the config file. isSet reports whether
+// the setting of a config key was set by its flag or environment variable, which take
+// precedence over the file, so settings are taken from the flag, then the environment, then
+// the config file, then the default.
+func (f *File) Apply(s *Settings, isSet func(key string) bool) {
+	if len(f.Regions) > 0 && !isSet("regions") {
+		s.Regions = f.Regions
+	}
+	if len(f.Services) > 0 && !isSet("services") {
+		s.Services = f.Services
+	}
+	if f.MinIdleDays != nil && !isSet("minIdleDays") {
+		s.MinIdleDays = *f.MinIdleDays
+	}
+	// --min-idle-days applies to every service, so it also replaces the per-service thresholds
+	if len(f.IdleDays) > 0 && !isSet("minIdleDays") {
+		s.IdleDays = f.IdleDays
+	}
+	if f.LogsIdleDays != nil && !isSet("logsIdleDays") {
+		s.LogsIdleDays = *f.LogsIdleDays
+	}
+	if f.OnlyIdle != nil && !isSet("onlyIdle") {
+		s.OnlyIdle = *f.OnlyIdle
+	}
+	if f.Output != "" && !isSet("output") {
+		s.Output = f.Output
+	}
+	if f.Profile != "" && !isSet("profile") {
+		s.Profile = f.Profile
+	}
+	if len(f.Tags) > 0 && !isSet("tags") {
+		s.Tags = nil
+		for key, value := range f.Tags {
+			s.Tags = append(s.Tags, key+"="+value)
+		}
+		sort.Strings(s.Tags)
+	}
+	if f.AlertCostPerResource != nil && !isSet("alertCostPerResource") {
+		s.AlertCostPerResource = *f.AlertCostPerResource
+	}
+	if f.AlertTotalCost != nil && !isSet("alertTotalCost") {
+		s.AlertTotalCost = *f.AlertTotalCost
+	}
+}
+
+// Example is a commented config file printed by "idled config init"
+const Example = `# idled config file
+#
+# idled reads ./idled.yaml, then ~/.idled.yaml, or the file given with --config.
+# Command line flags always take precedence over the values in this file.
+
+# Regions to scan (same as --regions)
+regions:
+  - us-east-1
+  - eu-west-1
+
+# Services scanned by "idled" without a scan subcommand (see "idled list-services")
+services:
+  - ec2
+  - ebs
+  - lambda
+  - elb
+
+# Minimum idle age in days for every service (same as --min-idle-days)
+minIdleDays: 30
+
+# Minimum idle age in days per service, overriding minIdleDays
+idleDays:
+  ec2: 60
+  lambda: 90
+
+# Days without events before a log group is idle (same as --logs-idle-days)
+# logsIdleDays: 90
+
+# Hide non-idle resources from the tables (same as --only-idle)
+# onlyIdle: true
+
+# Output format: table, markdown, or html (same as --output)
+# output: table
+
+# AWS shared config profile (same as --profile)
+# profile: default
+
+# Only report resources carrying all of these tags (same as --tag key=value)
+# tags:
+#   env: dev
+#   team: platform
+
+# Alert on idle resources costing more than this per month, in USD (same as --alert-cost-per-resource)
+# alertCostPerResource: 100
+
+# Warn when all idle resources cost more than this per month, in USD (same as --alert-total-cost)
+# alertTotalCost: 1000
+`
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		document    string
+		wantUnknown []string
+		wantErr     string
+	}{
+		{name: "empty", document: "  \n"},
+		{name: "comments only", document: "# nothing set\n"},
+		{name: "known keys", document: "regions: [us-east-1]\nminIdleDays: 30\nidleDays:\n  ec2: 60\ntags:\n  env: dev\n"},
+		{name: "unknown keys", document: "regions: [us-east-1]\nregoins: [eu-west-1]\nidle_days: 7\n", wantUnknown: []string{"idle_days", "regoins"}},
+		{name: "malformed", document: "regions: [us-east-1\nservices: ec2\n", wantErr: "invalid config file"},
+		{name: "not a mapping", document: "- us-east-1\n- eu-west-1\n", wantErr: "expected a mapping of settings"},
+		{name: "wrong type", document: "minIdleDays: thirty\n", wantErr: "invalid config file"},
+		{name: "negative idle days", document: "minIdleDays: -1\n", wantErr: "minIdleDays must not be negative"},
+		{name: "negative service idle days", document: "idleDays:\n  ec2: -5\n", wantErr: "idleDays.ec2 must not be negative"},
+		{name: "zero logs idle days", document: "logsIdleDays: 0\n", wantErr: "logsIdleDays must be positive"},
+		{name: "empty tag key", document: "tags:\n  \"\": dev\n", wantErr: "tag keys must not be empty"},
+		{name: "negative alert", document: "alertTotalCost: -10\n", wantErr: "alertTotalCost must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, unknown, err := Parse([]byte(tt.document))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || f == nil {
+				t.Fatalf("Parse() = %v, %v", f, err)
+			}
+			if !slices.Equal(unknown, tt.wantUnknown) {
+				t.Errorf("Parse() unknown keys = %v, want %v", unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestParseExample(t *testing.T) {
+	f, unknown, err := Parse([]byte(Example))
+	if err != nil || len(unknown) > 0 {
+		t.Fatalf("Parse(Example) = %v, %v", unknown, err)
+	}
+	if !slices.Equal(f.Regions, []string{"us-east-1", "eu-west-1"}) || f.IdleDays["lambda"] != 90 {
+		t.Errorf("Parse(Example) = %+v", f)
+	}
+}
+
+func TestApply(t *testing.T) {
+	f, _, err := Parse([]byte(`
+regions: [eu-west-1, ap-northeast-2]
+services: [ec2, ebs]
+minIdleDays: 30
+idleDays:
+  ec2: 60
+output: markdown
+profile: from-file
+tags:
+  team: web
+  env: dev
+alertTotalCost: 500
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	defaults := Settings{Regions: []string{"us-east-1"}, LogsIdleDays: 90, Output: "table"}
+
+	tests := []struct {
+		name  string
+		set   map[string]bool // Keys set by a flag or an environment variable
+		given Settings        // Values of the flags and the defaults
+		want  Settings
+	}{
+		{
+			name:  "file over defaults",
+			given: defaults,
+			want: Settings{
+				Regions: []string{"eu-west-1", "ap-northeast-2"}, Services: []string{"ec2", "ebs"}, MinIdleDays: 30,
+				IdleDays: map[string]int{"ec2": 60}, LogsIdleDays: 90, Output: "markdown", Profile: "from-file",
+				Tags: []string{"env=dev", "team=web"}, AlertTotalCost: 500,
+			},
+		},
+		{
+			name: "flags and environment over file",
+			set:  map[string]bool{"regions": true, "output": true, "profile": true, "tags": true, "alertTotalCost": true},
+			given: Settings{
+				Regions: []string{"sa-east-1"}, LogsIdleDays: 90, Output: "html", Profile: "from-env",
+				Tags: []string{"owner=me"}, AlertTotalCost: 0,
+			},
+			want: Settings{
+				Regions: []string{"sa-east-1"}, Services: []string{"ec2", "ebs"}, MinIdleDays: 30,
+				IdleDays: map[string]int{"ec2": 60}, LogsIdleDays: 90, Output: "html", Profile: "from-env",
+				Tags: []string{"owner=me"}, AlertTotalCost: 0,
+			},
+		},
+		{
+			name:  "min idle days flag also replaces the per-service thresholds",
+			set:   map[string]bool{"minIdleDays": true},
+			given: Settings{Regions: []string{"us-east-1"}, MinIdleDays: 7, LogsIdleDays: 90, Output: "table"},
+			want: Settings{
+				Regions: []string{"eu-west-1", "ap-northeast-2"}, Services: []string{"ec2", "ebs"}, MinIdleDays: 7,
+				LogsIdleDays: 90, Output: "markdown", Profile: "from-file", Tags: []string{"env=dev", "team=web"}, AlertTotalCost: 500,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.given
+			f.Apply(&got, func(key string) bool { return tt.set[key] })
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	home := t.TempDir()
+	work := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(work)
+
+	if path, err := Find(""); err != nil || path != "" {
+		t.Errorf("Find() without config files = %q, %v, want no file", path, err)
+	}
+
+	homeFile := filepath.Join(home, ".idled.yaml")
+	if err := os.WriteFile(homeFile, []byte("output: html\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := Find(""); err != nil || path != homeFile {
+		t.Errorf("Find() = %q, %v, want %s", path, err, homeFile)
+	}
+
+	if err := os.WriteFile(FileName, []byte("output: markdown\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := Find(""); err != nil || path != FileName {
+		t.Errorf("Find() = %q, %v, want ./%s over the home directory", path, err, FileName)
+	}
+
+	if path, err := Find(homeFile); err != nil || path != homeFile {
+		t.Errorf("Find(%s) = %q, %v, want the explicit file", homeFile, path, err)
+	}
+	if _, err := Find(filepath.Join(work, "missing.yaml")); err == nil {
+		t.Error("Find() of a missing explicit file succeeded")
+	}
+}
+
+func TestLoadMalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idled.yaml")
+	if err := os.WriteFile(path, []byte("regions:\n  - us-east-1\n services: [ec2]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Load(path); err == nil || !strings.Contains(err.Error(), "invalid config file") {
+		t.Errorf("Load() error = %v, want invalid config file", err)
+	}
+	if _, _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,104 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/runner"
+)
+
+// jobName is the Pushgateway job label of the pushed metrics
+const jobName = "idled"
+
+// Exporter exposes the results of the latest scan as Prometheus gauges
+type Exporter struct {
+	registry       *prometheus.Registry
+	idleResources  *prometheus.GaugeVec
+	monthlySavings *prometheus.GaugeVec
+	scanDuration   *prometheus.GaugeVec
+	lastScan       prometheus.Gauge
+	apiCalls       *prometheus.GaugeVec
+	apiThrottles   *prometheus.GaugeVec
+}
+
+// New creates an Exporter with its own registry
+func New() *Exporter {
+	e := &Exporter{
+		registry: prometheus.NewRegistry(),
+		idleResources: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "idled_idle_resources",
+			Help: "Number of idle resources found in the latest scan.",
+		}, []string{"service", "region"}),
+		monthlySavings: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "idled_estimated_monthly_savings_dollars",
+			Help: "Estimated monthly cost of the idle resources in USD, excluding resources without pricing data.",
+		}, []string{"service", "region"}),
+		scanDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "idled_scan_duration_seconds",
+			Help: "Time taken to scan the service in all regions.",
+		}, []string{"service"}),
+		lastScan: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "idled_last_scan_timestamp_seconds",
+			Help: "Unix time the latest scan completed.",
+		}),
+		apiCalls: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "idled_aws_api_calls",
+			Help: "Number of AWS API calls made by the latest scan.",
+		}, []string{"aws_service", "operation", "region"}),
+		apiThrottles: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "idled_aws_api_throttled_attempts",
+			Help: "Number of AWS API attempts rejected by throttling in the latest scan.",
+		}, []string{"aws_service", "operation", "region"}),
+	}
+	e.registry.MustRegister(e.idleResources, e.monthlySavings, e.scanDuration, e.lastScan, e.apiCalls, e.apiThrottles)
+	return e
+}
+
+// Update replaces the exported metrics with the results of a scan. Series of
+// services or regions missing from the results are removed.
+func (e *Exporter) Update(results []runner.Result) {
+	e.idleResources.Reset()
+	e.monthlySavings.Reset()
+	e.scanDuration.Reset()
+
+	for _, result := range results {
+		e.scanDuration.WithLabelValues(result.Service).Set(result.Duration.Seconds())
+		for _, summary := range result.Summaries {
+			// Services scanned in several passes (e.g., per region) accumulate per label set
+			e.idleResources.WithLabelValues(result.Service, summary.Region).Add(float64(summary.Count))
+			if summary.Estimated {
+				e.monthlySavings.WithLabelValues(result.Service, summary.Region).Add(summary.MonthlyCost)
+			}
+		}
+	}
+	e.lastScan.Set(float64(time.Now().Unix()))
+}
+
+// UpdateAPICalls replaces the exported AWS API call counts with those of a scan
+func (e *Exporter) UpdateAPICalls(stats []models.APICallStat) {
+	e.apiCalls.Reset()
+	e.apiThrottles.Reset()
+
+	for _, stat := range stats {
+		e.apiCalls.WithLabelValues(stat.Service, stat.Operation, stat.Region).Set(float64(stat.Calls))
+		e.apiThrottles.WithLabelValues(stat.Service, stat.Operation, stat.Region).Set(float64(stat.Throttles))
+	}
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus exposition format
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+}
+
+// Push sends the metrics to a Prometheus Pushgateway, replacing the metrics of the previous push
+func (e *Exporter) Push(url string) error {
+	if err := push.New(url, jobName).Gatherer(e.registry).Push(); err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// IndexFormatVersion is the version of the first-seen index format written by SaveIndex
+const IndexFormatVersion = 1
+
+// FirstSeenIndex records when idled first found each resource idle, for resources that do
+// not tell when their idleness began. Resources that stop being idle are pruned, so a
+// resource that becomes idle again starts over.
+type FirstSeenIndex struct {
+	Version int              `json:"version"`
+	Entries []FirstSeenEntry `json:"entries"`
+}
+
+// FirstSeenEntry holds the first-seen dates of the idle resources of one service in one region
+type FirstSeenEntry struct {
+	Account   string               `json:"account,omitempty"` // AWS account ID, empty if unknown
+	Service   string               `json:"service"`
+	Region    string               `json:"region"`
+	FirstSeen map[string]time.Time `json:"firstSeen"` // Canonical resource key to the first scan that found it idle
+}
+
+// DefaultIndexPath returns the path of the first-seen index, ~/.idled/first-seen.json
+func DefaultIndexPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".idled", "first-seen.json"), nil
+}
+
+// LoadIndex reads the first-seen index at path. A missing index is built from the snapshots
+// of the account in dir, oldest first, so that the dates go back to the first saved scan.
+func LoadIndex(path, dir, account string) (*FirstSeenIndex, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return replaySnapshots(dir, account)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read first-seen index: %w", err)
+	}
+
+	var index FirstSeenIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to decode first-seen index %s: %w", path, err)
+	}
+	if index.Version < 1 || index.Version > IndexFormatVersion {
+		return nil, fmt.Errorf("first-seen index %s has unsupported format version %d", path, index.Version)
+	}
+	return &index, nil
+}
+
+// replaySnapshots builds an index from the snapshots of the account in dir
+func replaySnapshots(dir, account string) (*FirstSeenIndex, error) {
+	index := &FirstSeenIndex{Version: IndexFormatVersion}
+	snapshots, err := readSnapshots(dir, account, 0)
+	if err != nil {
+		return index, err
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		keysByService := make(map[string]map[string][]string)
+		var services []string
+		for _, entry := range snapshots[i].Entries {
+			if keysByService[entry.Service] == nil {
+				keysByService[entry.Service] = make(map[string][]string)
+				services = append(services, entry.Service)
+			}
+			keysByService[entry.Service][entry.Region] = entry.Keys
+		}
+		for _, service := range services {
+			index.Update(account, service, keysByService[service], snapshots[i].CreatedAt)
+		}
+	}
+	return index, nil
+}
+
+// Update records the idle resource keys of a service per region found by a scan at seenAt.
+// Keys already in the index keep their date, new keys are dated seenAt, and keys of the
+// scanned regions that were not found are pruned. Regions that were not scanned are kept.
+func (x *FirstSeenIndex) Update(account, service string, keysByRegion map[string][]string, seenAt time.Time) {
+	for region, keys := range keysByRegion {
+		entry := x.entry(account, service, region)
+		firstSeen := make(map[string]time.Time, len(keys))
+		for _, key := range keys {
+			if date, ok := entry.FirstSeen[key]; ok {
+				firstSeen[key] = date
+			} else {
+				firstSeen[key] = seenAt.UTC()
+			}
+		}
+		entry.FirstSeen = firstSeen
+	}
+}
+
+// Dates returns the first-seen dates of the idle resources of a service in every region
+func (x *FirstSeenIndex) Dates(account, service string) map[string]time.Time {
+	dates := make(map[string]time.Time)
+	for _, entry := range x.Entries {
+		if entry.Account == account && entry.Service == service {
+			for key, date := range entry.FirstSeen {
+				dates[key] = date
+			}
+		}
+	}
+	return dates
+}
+
+// entry returns the entry of a service in a region, adding it if missing
+func (x *FirstSeenIndex) entry(account, service, region string) *FirstSeenEntry {
+	for i := range x.Entries {
+		if x.Entries[i].Account == account && x.Entries[i].Service == service && x.Entries[i].Region == region {
+			return &x.Entries[i]
+		}
+	}
+	x.Entries = append(x.Entries, FirstSeenEntry{Account: account, Service: service, Region: region})
+	return &x.Entries[len(x.Entries)-1]
+}
+
+// SaveIndex writes the index to path, replacing the previous one. Entries without idle
+// resources are left out.
+func SaveIndex(path string, x *FirstSeenIndex) error {
+	entries := x.Entries[:0]
+	for _, entry := range x.Entries {
+		if len(entry.FirstSeen) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Region < b.Region
+	})
+	x.Entries = entries
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create idled directory: %w", err)
+	}
+	data, err := json.MarshalIndent(x, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode first-seen index: %w", err)
+	}
+	// Written next to the index and renamed so that an interrupted run keeps the previous one
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write first-seen index: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write first-seen index: %w", err)
+	}
+	return nil
+}
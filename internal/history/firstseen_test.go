@@ -0,0 +1,131 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// day returns midnight UTC of the given day of June 2026
+func day(d int) time.Time {
+	return time.Date(2026, 6, d, 0, 0, 0, 0, time.UTC)
+}
+
+// wantDates checks the first-seen dates of a service against want, a map of key to day
+func wantDates(t *testing.T, x *FirstSeenIndex, account, service string, want map[string]int) {
+	t.Helper()
+	got := x.Dates(account, service)
+	if len(got) != len(want) {
+		t.Errorf("Dates(%s, %s) = %v, want %d keys", account, service, got, len(want))
+	}
+	for key, d := range want {
+		if date, ok := got[key]; !ok || !date.Equal(day(d)) {
+			t.Errorf("first seen of %s = %v, want %v", key, date, day(d))
+		}
+	}
+}
+
+func TestFirstSeenIndexConsecutiveRuns(t *testing.T) {
+	x := &FirstSeenIndex{Version: IndexFormatVersion}
+
+	// Run 1 finds two buckets
+	x.Update("111111111111", "s3", map[string][]string{"us-east-1": {"bucket-a", "bucket-b"}}, day(1))
+	wantDates(t, x, "111111111111", "s3", map[string]int{"bucket-a": 1, "bucket-b": 1})
+
+	// Run 2 still finds bucket-a, and bucket-c for the first time
+	x.Update("111111111111", "s3", map[string][]string{"us-east-1": {"bucket-a", "bucket-c"}}, day(2))
+	wantDates(t, x, "111111111111", "s3", map[string]int{"bucket-a": 1, "bucket-c": 2})
+
+	// Run 3 finds bucket-b idle again, which starts over
+	x.Update("111111111111", "s3", map[string][]string{"us-east-1": {"bucket-a", "bucket-b", "bucket-c"}}, day(3))
+	wantDates(t, x, "111111111111", "s3", map[string]int{"bucket-a": 1, "bucket-b": 3, "bucket-c": 2})
+
+	// Run 4 finds nothing idle
+	x.Update("111111111111", "s3", map[string][]string{"us-east-1": nil}, day(4))
+	wantDates(t, x, "111111111111", "s3", map[string]int{})
+}
+
+func TestFirstSeenIndexKeepsUnscannedRegions(t *testing.T) {
+	x := &FirstSeenIndex{Version: IndexFormatVersion}
+	x.Update("", "ecr", map[string][]string{
+		"us-east-1": {"us-east-1/repo-a"},
+		"eu-west-1": {"eu-west-1/repo-b"},
+	}, day(1))
+
+	// A later scan of us-east-1 only leaves eu-west-1 alone
+	x.Update("", "ecr", map[string][]string{"us-east-1": {"us-east-1/repo-a"}}, day(5))
+
+	wantDates(t, x, "", "ecr", map[string]int{"us-east-1/repo-a": 1, "eu-west-1/repo-b": 1})
+}
+
+func TestFirstSeenIndexSeparatesAccountsAndServices(t *testing.T) {
+	x := &FirstSeenIndex{Version: IndexFormatVersion}
+	x.Update("111111111111", "s3", map[string][]string{"global": {"shared"}}, day(1))
+	x.Update("222222222222", "s3", map[string][]string{"global": {"shared"}}, day(2))
+	x.Update("111111111111", "ecr", map[string][]string{"us-east-1": {"shared"}}, day(3))
+
+	wantDates(t, x, "111111111111", "s3", map[string]int{"shared": 1})
+	wantDates(t, x, "222222222222", "s3", map[string]int{"shared": 2})
+	wantDates(t, x, "111111111111", "ecr", map[string]int{"shared": 3})
+}
+
+func TestFirstSeenIndexSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idled", "first-seen.json")
+	x := &FirstSeenIndex{Version: IndexFormatVersion}
+	x.Update("111111111111", "s3", map[string][]string{"global": {"bucket-a"}}, day(1))
+	x.Update("111111111111", "ecr", map[string][]string{"us-east-1": nil}, day(1))
+
+	if err := SaveIndex(path, x); err != nil {
+		t.Fatalf("SaveIndex() error = %v", err)
+	}
+	loaded, err := LoadIndex(path, t.TempDir(), "111111111111")
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+
+	if len(loaded.Entries) != 1 {
+		t.Errorf("got %d entries, want the empty entry left out", len(loaded.Entries))
+	}
+	wantDates(t, loaded, "111111111111", "s3", map[string]int{"bucket-a": 1})
+}
+
+func TestLoadIndexUnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "first-seen.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "entries": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadIndex(path, t.TempDir(), ""); err == nil {
+		t.Error("LoadIndex() error = nil, want an unsupported version error")
+	}
+}
+
+func TestLoadIndexReplaysSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	for d, keys := range map[int][]string{
+		1: {"bucket-a", "bucket-b"},
+		2: {"bucket-a", "bucket-b"},
+		3: {"bucket-a", "bucket-c"},
+	} {
+		snapshot := New("111111111111")
+		snapshot.CreatedAt = day(d)
+		snapshot.Add("s3", "global", keys)
+		if _, err := Save(dir, snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := New("222222222222")
+	other.CreatedAt = day(2).Add(time.Hour)
+	other.Add("s3", "global", []string{"bucket-z"})
+	if _, err := Save(dir, other); err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := LoadIndex(filepath.Join(t.TempDir(), "first-seen.json"), dir, "111111111111")
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+
+	wantDates(t, x, "111111111111", "s3", map[string]int{"bucket-a": 1, "bucket-c": 3})
+}
@@ -0,0 +1,216 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FormatVersion is the version of the snapshot format written by Save. Snapshots with a
+// newer version are skipped by Latest, older versions are read as is.
+const FormatVersion = 1
+
+// fileTimeFormat names snapshot files so that they sort chronologically
+const fileTimeFormat = "20060102T150405Z"
+
+// Snapshot is the set of idle resources found by one scan
+type Snapshot struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Account   string         `json:"account,omitempty"` // AWS account ID, empty if unknown
+	Filters   *Filters       `json:"filters,omitempty"` // Filters of the scan, nil in snapshots that predate them
+	Entries   []ServiceEntry `json:"entries"`
+}
+
+// Filters are the scan options that limit which idle resources a snapshot holds
+type Filters struct {
+	Services    []string          `json:"services,omitempty"`
+	Regions     []string          `json:"regions,omitempty"`
+	Include     []string          `json:"include,omitempty"` // --include name patterns
+	Exclude     []string          `json:"exclude,omitempty"` // --exclude name patterns
+	Tags        map[string]string `json:"tags,omitempty"`
+	Ignore      []string          `json:"ignore,omitempty"` // Policy ignore list
+	MinIdleDays int               `json:"minIdleDays,omitempty"`
+}
+
+// ScopeChanges returns the options whose services or regions differ from the previous filters.
+// Diff only compares the services and regions both scans covered, so these changes are safe.
+func (f Filters) ScopeChanges(previous Filters) []string {
+	var changes []string
+	if !sameSet(f.Services, previous.Services) {
+		changes = append(changes, "--services")
+	}
+	if !sameSet(f.Regions, previous.Regions) {
+		changes = append(changes, "--regions")
+	}
+	return changes
+}
+
+// ResultChanges returns the options that differ from the previous filters and change which
+// resources of a scanned service and region are kept. A diff across them would report
+// resources as new or resolved only because they were filtered differently.
+func (f Filters) ResultChanges(previous Filters) []string {
+	var changes []string
+	if !sameSet(f.Include, previous.Include) {
+		changes = append(changes, "--include")
+	}
+	if !sameSet(f.Exclude, previous.Exclude) {
+		changes = append(changes, "--exclude")
+	}
+	if !maps.Equal(f.Tags, previous.Tags) {
+		changes = append(changes, "--tag")
+	}
+	if !sameSet(f.Ignore, previous.Ignore) {
+		changes = append(changes, "ignore list")
+	}
+	if f.MinIdleDays != previous.MinIdleDays {
+		changes = append(changes, "--min-idle-days")
+	}
+	return changes
+}
+
+// sameSet reports whether a and b hold the same values, in any order
+func sameSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// ServiceEntry holds the idle resources of one service in one region
+type ServiceEntry struct {
+	Service string   `json:"service"`
+	Region  string   `json:"region"`
+	Keys    []string `json:"keys"` // Canonical resource keys (e.g., us-east-1/i-0123, an IAM ARN)
+}
+
+// New creates an empty snapshot of the current format version
+func New(account string) *Snapshot {
+	return &Snapshot{Version: FormatVersion, CreatedAt: time.Now().UTC(), Account: account}
+}
+
+// Add records the idle resource keys of a service in a region
+func (s *Snapshot) Add(service, region string, keys []string) {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	s.Entries = append(s.Entries, ServiceEntry{Service: service, Region: region, Keys: sorted})
+}
+
+// Diff compares the keys found for a service in this scan with the previous snapshot.
+// Only regions the previous snapshot scanned for the service are compared, so newly
+// scanned regions are not reported as new. ok is false if the service was never scanned.
+func (s *Snapshot) Diff(service string, current map[string][]string) (added, resolved []string, ok bool) {
+	for _, entry := range s.Entries {
+		if entry.Service != service {
+			continue
+		}
+		keys, scanned := current[entry.Region]
+		if !scanned {
+			continue
+		}
+		ok = true
+
+		previous := make(map[string]bool, len(entry.Keys))
+		for _, key := range entry.Keys {
+			previous[key] = true
+		}
+		found := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			found[key] = true
+			if !previous[key] {
+				added = append(added, key)
+			}
+		}
+		for _, key := range entry.Keys {
+			if !found[key] {
+				resolved = append(resolved, key)
+			}
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(resolved)
+	return added, resolved, ok
+}
+
+// DefaultDir returns the history directory, ~/.idled/history
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".idled", "history"), nil
+}
+
+// Save writes the snapshot to dir as <timestamp>.json and returns the file path
+func Save(dir string, s *Snapshot) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create history directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	path := filepath.Join(dir, s.CreatedAt.UTC().Format(fileTimeFormat)+".json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// Latest returns the most recent readable snapshot of the account in dir, or nil if there
+// is none. Unreadable files and snapshots of a newer format version are skipped. Snapshots
+// without an account match any account.
+func Latest(dir, account string) (*Snapshot, error) {
+	snapshots, err := readSnapshots(dir, account, 1)
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+	return snapshots[0], nil
+}
+
+// readSnapshots returns up to limit readable snapshots of the account in dir, the most recent
+// first, or all of them if limit is 0
+func readSnapshots(dir, account string, limit int) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	var snapshots []*Snapshot
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		var s Snapshot
+		if err := json.Unmarshal(data, &s); err != nil || s.Version < 1 || s.Version > FormatVersion {
+			continue
+		}
+		if account != "" && s.Account != "" && s.Account != account {
+			continue
+		}
+		snapshots = append(snapshots, &s)
+		if len(snapshots) == limit {
+			break
+		}
+	}
+	return snapshots, nil
+}
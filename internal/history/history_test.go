@@ -0,0 +1,68 @@
+package history
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFiltersChanges(t *testing.T) {
+	previous := Filters{
+		Services:    []string{"ec2", "ebs"},
+		Regions:     []string{"us-east-1"},
+		Include:     []string{"prod-*"},
+		Tags:        map[string]string{"team": "web"},
+		MinIdleDays: 7,
+	}
+
+	tests := []struct {
+		name        string
+		change      func(f *Filters)
+		wantScope   []string
+		wantResults []string
+	}{
+		{name: "same filters", change: func(f *Filters) {}},
+		{name: "same values in another order", change: func(f *Filters) { f.Services = []string{"ebs", "ec2"} }},
+		{name: "other services and regions", change: func(f *Filters) {
+			f.Services = []string{"ec2"}
+			f.Regions = []string{"us-east-1", "eu-west-1"}
+		}, wantScope: []string{"--services", "--regions"}},
+		{name: "name filter removed", change: func(f *Filters) { f.Include = nil }, wantResults: []string{"--include"}},
+		{name: "name filter added", change: func(f *Filters) { f.Exclude = []string{"/^tmp-/"} }, wantResults: []string{"--exclude"}},
+		{name: "other tag value", change: func(f *Filters) { f.Tags = map[string]string{"team": "data"} }, wantResults: []string{"--tag"}},
+		{name: "ignore list and idle age", change: func(f *Filters) {
+			f.Ignore = []string{"us-east-1/vol-1"}
+			f.MinIdleDays = 30
+		}, wantResults: []string{"ignore list", "--min-idle-days"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := previous
+			tt.change(&current)
+			if got := current.ScopeChanges(previous); !slices.Equal(got, tt.wantScope) {
+				t.Errorf("ScopeChanges() = %v, want %v", got, tt.wantScope)
+			}
+			if got := current.ResultChanges(previous); !slices.Equal(got, tt.wantResults) {
+				t.Errorf("ResultChanges() = %v, want %v", got, tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestSaveKeepsFilters(t *testing.T) {
+	dir := t.TempDir()
+	s := New("111111111111")
+	s.Filters = &Filters{Services: []string{"s3"}, Include: []string{"logs-*"}, Tags: map[string]string{"env": "dev"}}
+	s.Add("s3", "us-east-1", []string{"logs-a"})
+	if _, err := Save(dir, s); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := Latest(dir, "111111111111")
+	if err != nil || got == nil {
+		t.Fatalf("Latest() = %v, %v", got, err)
+	}
+	if got.Filters == nil || got.Filters.ResultChanges(*s.Filters) != nil || got.Filters.ScopeChanges(*s.Filters) != nil {
+		t.Errorf("Latest() filters = %+v, want %+v", got.Filters, s.Filters)
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// AutoScalingGroupInfo holds information about an Auto Scaling group with zero capacity,
+// suspended scaling processes, or no healthy targets
+type AutoScalingGroupInfo struct {
+	Name               string     // Auto Scaling group name
+	ARN                string     // Auto Scaling group ARN
+	Region             string     // AWS region
+	DesiredCapacity    int32      // Desired capacity
+	MinSize            int32      // Minimum size
+	MaxSize            int32      // Maximum size
+	InstanceCount      int        // Instances currently in the group
+	SuspendedProcesses []string   // Suspended scaling processes (Launch, Terminate, ...)
+	TargetGroupCount   int        // Attached target groups
+	HealthyTargets     int        // Healthy targets across the attached target groups
+	CreatedTime        time.Time  // When the group was created
+	LastActivity       *time.Time // Start of the last scaling activity, nil if none was found within the lookback
+	IdleDays           int        // Days since the last scaling activity, or since creation up to the lookback if none was found
+	ZeroCapacity       bool       // Whether desired, min, and max have been zero for at least the idle threshold
+	NoHealthyTargets   bool       // Whether the group has capacity but its target groups have no healthy target
+}
+
+// SortKey returns the canonical sort key for the AutoScalingGroupInfo
+func (g AutoScalingGroupInfo) SortKey() string {
+	return regionKey(g.Region, g.Name)
+}
+
+// Suspended reports whether any scaling process of the group is suspended
+func (g AutoScalingGroupInfo) Suspended() bool {
+	return len(g.SuspendedProcesses) > 0
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// BeanstalkEnvironmentInfo holds information about an Elastic Beanstalk environment without traffic
+type BeanstalkEnvironmentInfo struct {
+	EnvironmentName      string    // Environment name
+	EnvironmentID        string    // Environment ID (e-...)
+	ApplicationName      string    // Application the environment belongs to
+	Region               string    // AWS region
+	Status               string    // Environment status (Ready)
+	Health               string    // Environment health color (Green, Grey, ...)
+	Platform             string    // Solution stack name
+	LoadBalancer         string    // Load balancer name or ARN
+	LoadBalancerType     string    // "ALB", "NLB", or "CLB"
+	InstanceCount        int       // EC2 instances running in the environment
+	InstanceTypes        []string  // Instance type of each EC2 instance
+	DateCreated          time.Time // When the environment was created
+	DateUpdated          time.Time // When the environment was last updated
+	RequestCount         float64   // Load balancer traffic over the check period
+	IdleReason           string    // Reason why the environment is considered idle
+	EstimatedMonthlyCost float64   // EC2 instances and load balancer cost
+	PricingSource        string    // "API", "Cache", "Default", or "N/A"
+}
+
+// SortKey returns the canonical sort key for the BeanstalkEnvironmentInfo
+func (e BeanstalkEnvironmentInfo) SortKey() string {
+	return regionKey(e.Region, e.EnvironmentName)
+}
+
+// MonthlyCost returns the estimated monthly cost of the environment's instances and load balancer
+func (e BeanstalkEnvironmentInfo) MonthlyCost() float64 {
+	return e.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the environment
+func (e BeanstalkEnvironmentInfo) PricingUnavailable() bool {
+	return e.PricingSource == "N/A"
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// DistributionInfo holds information about an idle CloudFront distribution
+type DistributionInfo struct {
+	ID               string    // Distribution ID
+	ARN              string    // Distribution ARN
+	DomainName       string    // CloudFront domain name (e.g., d111111abcdef8.cloudfront.net)
+	Aliases          []string  // Alternate domain names (CNAMEs)
+	OriginDomains    []string  // Domain names of the origins
+	Enabled          bool      // Whether the distribution accepts requests
+	Status           string    // Deployment status (Deployed or InProgress)
+	RequestCount     *float64  // Total requests over the check period, nil if not checked
+	LastModifiedTime time.Time // When the distribution configuration was last changed
+	IdleReason       string    // Reason why the distribution is considered idle
+}
+
+// SortKey returns the canonical sort key for the DistributionInfo
+func (d DistributionInfo) SortKey() string {
+	return d.ID
+}
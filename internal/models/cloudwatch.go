@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// CloudWatch alarm types
+const (
+	CloudWatchAlarmMetric    = "Metric"
+	CloudWatchAlarmComposite = "Composite"
+)
+
+// CloudWatchAlarmInfo holds information about an idle CloudWatch alarm
+type CloudWatchAlarmInfo struct {
+	Name                 string    // Alarm name
+	ARN                  string    // Alarm ARN
+	Region               string    // AWS region
+	Type                 string    // "Metric" or "Composite"
+	Namespace            string    // Namespace of the alarm metric, empty for metric math and composite alarms
+	MetricName           string    // Name of the alarm metric, empty for metric math and composite alarms
+	StateValue           string    // OK, ALARM, or INSUFFICIENT_DATA
+	StateSince           time.Time // When the alarm entered its current state
+	ActionsEnabled       bool      // Whether the alarm actions are enabled
+	IdleDays             int       // Days in the current state
+	MetricMissing        bool      // Whether a metric the alarm watches no longer exists (--cloudwatch-check-metrics)
+	IdleReason           string    // Reason why the alarm is considered idle
+	EstimatedMonthlyCost float64   // Monthly alarm charge
+}
+
+// CloudWatchDashboardInfo holds information about a CloudWatch dashboard
+type CloudWatchDashboardInfo struct {
+	Name                 string    // Dashboard name
+	ARN                  string    // Dashboard ARN
+	Region               string    // AWS region
+	LastModified         time.Time // When the dashboard was last modified
+	SizeBytes            int64     // Size of the dashboard body
+	IdleDays             int       // Days since the last modification
+	IsIdle               bool      // Whether the dashboard was not modified within the idle threshold
+	EstimatedMonthlyCost float64   // Monthly dashboard charge
+}
+
+// SortKey returns the canonical sort key for the CloudWatchAlarmInfo
+func (a CloudWatchAlarmInfo) SortKey() string {
+	return regionKey(a.Region, a.Name)
+}
+
+// MonthlyCost returns the monthly charge of the alarm
+func (a CloudWatchAlarmInfo) MonthlyCost() float64 {
+	return a.EstimatedMonthlyCost
+}
+
+// SortKey returns the canonical sort key for the CloudWatchDashboardInfo
+func (d CloudWatchDashboardInfo) SortKey() string {
+	return regionKey(d.Region, d.Name)
+}
+
+// IdleFlag reports whether the dashboard is considered idle
+func (d CloudWatchDashboardInfo) IdleFlag() bool {
+	return d.IsIdle
+}
+
+// MonthlyCost returns the monthly charge of the dashboard
+func (d CloudWatchDashboardInfo) MonthlyCost() float64 {
+	return d.EstimatedMonthlyCost
+}
+
+// CloudWatchScanResult groups the idle CloudWatch alarms and the dashboards of one region
+type CloudWatchScanResult struct {
+	Region     string
+	Alarms     []CloudWatchAlarmInfo
+	Dashboards []CloudWatchDashboardInfo
+}
+
+// SortKey returns the canonical sort key for the CloudWatchScanResult
+func (r CloudWatchScanResult) SortKey() string {
+	return r.Region
+}
+
+// Resources returns the alarms and dashboards of the region
+func (r CloudWatchScanResult) Resources() []any {
+	resources := make([]any, 0, len(r.Alarms)+len(r.Dashboards))
+	for _, alarm := range r.Alarms {
+		resources = append(resources, alarm)
+	}
+	for _, dashboard := range r.Dashboards {
+		resources = append(resources, dashboard)
+	}
+	return resources
+}
@@ -18,7 +18,14 @@ type ConfigRuleInfo struct {
 	IsActive       bool
 	IsCustom       bool
 	IsCompliant    bool
-	EvaluationMode string
+	EvaluationMode string // DETECTIVE and/or PROACTIVE
+
+	// Evaluation errors and scope
+	LastErrorCode      string   // Error code of the last failed evaluation
+	LastErrorMessage   string   // Error message of the last failed evaluation
+	IsFailing          bool     // Whether the latest evaluation within the idle window failed
+	ScopeResourceTypes []string // Resource types the rule evaluates, empty if not scoped by type
+	NoResourcesInScope bool     // Whether no resource of the scoped types exists in the region
 
 	// Idle detection
 	IdleDays     int
@@ -44,6 +51,7 @@ type ConfigRecorderInfo struct {
 	IdleDays     int
 	IsIdle       bool
 	LastActivity *time.Time
+	NeverActive  bool // Not recording and never started, stopped, or changed status
 }
 
 // ConfigDeliveryChannelInfo holds information about a Config delivery channel
@@ -60,7 +68,66 @@ type ConfigDeliveryChannelInfo struct {
 	Frequency    string
 
 	// Idle detection
-	IdleDays     int
-	IsIdle       bool
-	LastActivity *time.Time
+	IdleDays       int
+	IsIdle         bool
+	LastActivity   *time.Time
+	NeverDelivered bool // The channel status has no successful or attempted delivery
+}
+
+// SortKey returns the canonical sort key for the ConfigRuleInfo
+func (r ConfigRuleInfo) SortKey() string {
+	return regionKey(r.Region, r.RuleName)
+}
+
+// SortKey returns the canonical sort key for the ConfigRecorderInfo
+func (r ConfigRecorderInfo) SortKey() string {
+	return regionKey(r.Region, r.RecorderName)
+}
+
+// SortKey returns the canonical sort key for the ConfigDeliveryChannelInfo
+func (c ConfigDeliveryChannelInfo) SortKey() string {
+	return regionKey(c.Region, c.ChannelName)
+}
+
+// IdleFlag reports whether the rule is considered idle
+func (r ConfigRuleInfo) IdleFlag() bool {
+	return r.IsIdle
+}
+
+// IdleFlag reports whether the recorder is considered idle
+func (r ConfigRecorderInfo) IdleFlag() bool {
+	return r.IsIdle
+}
+
+// IdleFlag reports whether the delivery channel is considered idle
+func (c ConfigDeliveryChannelInfo) IdleFlag() bool {
+	return c.IsIdle
+}
+
+// ConfigScanResult groups the AWS Config rules, recorders, and delivery channels found in one region
+type ConfigScanResult struct {
+	Region    string
+	Rules     []ConfigRuleInfo
+	Recorders []ConfigRecorderInfo
+	Channels  []ConfigDeliveryChannelInfo
+}
+
+// SortKey returns the canonical sort key for the ConfigScanResult
+func (r ConfigScanResult) SortKey() string {
+	return r.Region
+}
+
+// Resources returns the rules, recorders, and delivery channels of the region
+func (r ConfigScanResult) Resources() []any {
+	resources := make([]any, 0, len(r.Rules)+len(r.Recorders)+len(r.Channels))
+	for _, rule := range r.Rules {
+		resources = append(resources, rule)
+	}
+	for _, recorder := range r.Recorders {
+		resources = append(resources, recorder)
+	}
+	for _, channel := range r.Channels {
+		resources = append(resources, channel)
+	}
+	return resources
 }
@@ -0,0 +1,23 @@
+package models
+
+// CostSummary aggregates the idle resources of one service in one region and their estimated monthly cost
+type CostSummary struct {
+	Service     string     // Service display name (e.g., EC2, Elastic IP)
+	Region      string     // AWS region, or "global" for global services
+	Count       int        // Idle resources found
+	Estimated   bool       // Whether the service provides cost estimates
+	MonthlyCost float64    // Sum of the estimated monthly costs of the priced resources
+	Unpriced    int        // Idle resources without pricing data, not included in MonthlyCost
+	Resources   []CostItem // Idle resources with a cost estimate
+}
+
+// CostItem is a single idle resource with its estimated monthly cost
+type CostItem struct {
+	Name        string  // Resource ID, name, or ARN
+	MonthlyCost float64 // Estimated monthly cost
+}
+
+// Priced is implemented by resource models whose cost comes from a pricing lookup that can fail
+type Priced interface {
+	PricingUnavailable() bool
+}
@@ -5,16 +5,49 @@ import "time"
 // VolumeInfo represents EBS volume information
 type VolumeInfo struct {
 	VolumeID             string
+	ARN                  string // Empty if the account is unknown
 	Name                 string
 	Size                 int
 	VolumeType           string
+	IOPS                 int // Provisioned IOPS, or the baseline IOPS of gp2 volumes
+	Throughput           int // Provisioned throughput in MB/s of gp3 volumes, 0 otherwise
 	State                string
 	Region               string
 	AvailabilityZone     string
 	CreationTime         time.Time
 	LastAttachmentTime   *time.Time
 	ElapsedDaysSinceUsed int
+	AttachedInstanceID   string     // Stopped instance the volume is attached to, empty when detached
+	LastInstanceID       string     // Instance the volume was last detached from per CloudTrail, empty if unknown
+	LastDetachTime       *time.Time // Time of that detachment, nil if unknown
+	CreatedBy            string     // Creator named by the tags of AWS Backup, Kubernetes, or the EBS CSI driver, empty if none
+	SourceSnapshotID     string     // Snapshot the volume was created from, empty if none
+	SourceSnapshotDesc   string     // Description of the source snapshot, empty if unknown
+	ReadOps              float64    // VolumeReadOps sum over the last 30 days
+	WriteOps             float64    // VolumeWriteOps sum over the last 30 days
+	IdleTimePercent      float64    // Share of the reported time with no IO
+	LastIOTime           *time.Time // Most recent day with read or write operations
+	HasIOMetrics         bool       // Whether CloudWatch reported any datapoints in the last 30 days
+	LatestSnapshotDate   *time.Time // Start time of the latest completed snapshot, nil if none
+	HasRecentSnapshot    bool       // Whether the latest snapshot is within the snapshot recency window
+	HasSnapshotInfo      bool       // Whether the snapshot lookup succeeded
 	EstimatedMonthlyCost float64
 	EstimatedSavings     float64
-	PricingSource        string // "API", "Cache", or "Default"
+	PricingSource        string            // "API", "Cache", "Default", or "Estimated"
+	Tags                 map[string]string // Resource tags
+}
+
+// SortKey returns the canonical sort key for the VolumeInfo
+func (v VolumeInfo) SortKey() string {
+	return regionKey(v.Region, v.VolumeID)
+}
+
+// MonthlyCost returns the estimated monthly cost of the volume
+func (v VolumeInfo) MonthlyCost() float64 {
+	return v.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the volume
+func (v VolumeInfo) PricingUnavailable() bool {
+	return v.PricingSource == "N/A"
 }
@@ -2,17 +2,95 @@ package models
 
 import "time"
 
+// Sources of InstanceInfo.StoppedTime
+const (
+	StoppedTimeSourceStateTransition = "StateTransition"
+	StoppedTimeSourceCloudTrail      = "CloudTrail"
+	StoppedTimeSourceLaunchTime      = "LaunchTime" // Lower bound, the instance stopped at or after this time
+)
+
 // InstanceInfo represents EC2 instance information
 type InstanceInfo struct {
 	InstanceID           string
+	ARN                  string // Empty if the account is unknown
 	Name                 string
 	InstanceType         string
+	OperatingSystem      string // Operating system the instance is priced for (Linux, Windows, RHEL, or SUSE)
 	Region               string
 	AvailabilityZone     string
 	StoppedTime          *time.Time
+	StoppedTimeSource    string // "StateTransition", "CloudTrail", or "LaunchTime"
 	LaunchTime           time.Time
 	ElapsedDays          int
-	EstimatedMonthlyCost float64
-	EstimatedSavings     float64
-	PricingSource        string // "API", "Cache", or "N/A"
+	EstimatedMonthlyCost float64           // Compute price per month, not billed while stopped
+	EstimatedSavings     float64           // Compute cost saved since the instance stopped
+	PricingSource        string            // "API", "Cache", "Default", "Estimated", or "N/A"
+	AttachedVolumes      int               // Number of attached EBS volumes
+	AttachedStorageGB    int               // Total size of the attached EBS volumes
+	StorageMonthlyCost   float64           // Monthly storage cost of the attached EBS volumes
+	ElasticIP            string            // Public IP of the associated Elastic IP, empty if none
+	EIPMonthlyCost       float64           // Monthly cost of the associated Elastic IP
+	CurrentMonthlyCost   float64           // Storage and Elastic IP cost still billed while stopped
+	CurrentCostUnpriced  bool              // Whether an attached volume could not be priced
+	Protected            bool              // Whether termination protection is enabled
+	ProtectionReason     string            // Protection that blocks termination, empty if not protected
+	Tags                 map[string]string // Resource tags
+}
+
+// SortKey returns the canonical sort key for the InstanceInfo
+func (i InstanceInfo) SortKey() string {
+	return regionKey(i.Region, i.InstanceID)
+}
+
+// MonthlyCost returns the estimated monthly cost the stopped instance is still billed for,
+// which terminating it would save
+func (i InstanceInfo) MonthlyCost() float64 {
+	return i.CurrentMonthlyCost
+}
+
+// PricingUnavailable reports whether the current cost of the instance could not be priced
+func (i InstanceInfo) PricingUnavailable() bool {
+	return i.CurrentCostUnpriced
+}
+
+// DeletionProtection reports whether the instance is protected against termination, and why
+func (i InstanceInfo) DeletionProtection() (bool, string) {
+	return i.Protected, i.ProtectionReason
+}
+
+// UnderutilizedInstanceInfo represents a running EC2 instance with low CPU and network usage
+type UnderutilizedInstanceInfo struct {
+	InstanceID           string
+	ARN                  string // Empty if the account is unknown
+	Name                 string
+	InstanceType         string
+	OperatingSystem      string // Operating system the instance is priced for (Linux, Windows, RHEL, or SUSE)
+	Region               string
+	AvailabilityZone     string
+	LaunchTime           time.Time
+	AutoScalingGroup     string            // Auto Scaling group of the instance, empty if none
+	LookbackDays         int               // Days of CloudWatch metrics evaluated
+	AvgCPU               float64           // Average CPUUtilization (%) over the lookback
+	MaxCPU               float64           // Maximum CPUUtilization (%) over the lookback
+	NetworkBytesPerSec   float64           // Average NetworkIn and NetworkOut combined
+	EBSBytesPerSec       float64           // Average EBSReadBytes and EBSWriteBytes combined
+	HasEBSMetrics        bool              // Whether EBS IO metrics were reported (Nitro instances only)
+	EstimatedMonthlyCost float64           // Running compute cost, saved by stopping or terminating the instance
+	PricingSource        string            // "API", "Cache", "Default", "Estimated", or "N/A"
+	Tags                 map[string]string // Resource tags
+}
+
+// SortKey returns the canonical sort key for the UnderutilizedInstanceInfo
+func (i UnderutilizedInstanceInfo) SortKey() string {
+	return regionKey(i.Region, i.InstanceID)
+}
+
+// MonthlyCost returns the estimated monthly cost of the running instance
+func (i UnderutilizedInstanceInfo) MonthlyCost() float64 {
+	return i.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the instance
+func (i UnderutilizedInstanceInfo) PricingUnavailable() bool {
+	return i.PricingSource == "N/A"
 }
@@ -11,5 +11,31 @@ type RepositoryInfo struct {
 	LastPush   *time.Time // Pointer to handle cases where no images are pushed
 	CreatedAt  *time.Time
 	Idle       bool
-	ImageCount int // Add field for image count
+	ImageCount int               // Add field for image count
+	Tags       map[string]string // Resource tags
+}
+
+// ECRScanCoverageInfo holds Inspector2 enhanced scanning coverage for an idle ECR repository
+type ECRScanCoverageInfo struct {
+	RepositoryName string
+	Region         string
+	ScanFrequency  string     // SCAN_ON_PUSH, CONTINUOUS_SCAN, or MANUAL
+	ScanStatus     string     // Inspector2 scan status code (e.g., ACTIVE)
+	LastScannedAt  *time.Time // Last time Inspector2 scanned the repository
+	LastPush       *time.Time // Last image push time from the ECR scan
+}
+
+// SortKey returns the canonical sort key for the RepositoryInfo
+func (r RepositoryInfo) SortKey() string {
+	return regionKey(r.Region, r.Name)
+}
+
+// SortKey returns the canonical sort key for the ECRScanCoverageInfo
+func (c ECRScanCoverageInfo) SortKey() string {
+	return regionKey(c.Region, c.RepositoryName)
+}
+
+// IdleFlag reports whether the repository is considered idle
+func (r RepositoryInfo) IdleFlag() bool {
+	return r.Idle
 }
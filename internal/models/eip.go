@@ -1,14 +1,39 @@
 package models
 
+// Association types of an idle Elastic IP
+const (
+	EIPAssociationUnattached      = "Unattached"
+	EIPAssociationStoppedInstance = "StoppedInstance"
+	EIPAssociationOrphanedENI     = "OrphanedENI"
+)
+
 // EIPInfo represents Elastic IP address information
 type EIPInfo struct {
 	AllocationID         string
+	ARN                  string // Empty if the account is unknown
 	PublicIP             string
 	AssociationID        string
-	AssociationState     string
+	AssociationType      string // "Unattached", "StoppedInstance", or "OrphanedENI"
+	AssociationState     string // Display text, e.g. "Stopped instance i-xxx"
 	InstanceID           string
 	NetworkInterfaceID   string
 	Region               string
 	EstimatedMonthlyCost float64
-	PricingSource        string // "API", "Cache", or "Fixed"
+	PricingSource        string            // "API", "Cache", or "Default"
+	Tags                 map[string]string // Resource tags
+}
+
+// SortKey returns the canonical sort key for the EIPInfo
+func (e EIPInfo) SortKey() string {
+	return regionKey(e.Region, e.AllocationID)
+}
+
+// MonthlyCost returns the estimated monthly cost of the Elastic IP
+func (e EIPInfo) MonthlyCost() float64 {
+	return e.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the Elastic IP
+func (e EIPInfo) PricingUnavailable() bool {
+	return e.PricingSource == "N/A"
 }
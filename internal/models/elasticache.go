@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ElastiCacheInfo holds information about an ElastiCache serverless cache or provisioned cluster
+type ElastiCacheInfo struct {
+	Name                 string     // Serverless cache name, or replication group / cache cluster ID
+	ARN                  string     // ARN of the serverless cache or cache cluster
+	Region               string     // AWS region
+	DeploymentType       string     // "Serverless" or "Provisioned"
+	Engine               string     // redis, valkey, or memcached
+	EngineVersion        string     // Engine version (major version for serverless caches)
+	NodeType             string     // Cache node type (provisioned only)
+	NodeCount            int        // Number of cache nodes (provisioned only)
+	Status               string     // Current status (e.g., available)
+	CreationTime         *time.Time // When the cache was created
+	StoredBytes          *float64   // Average data stored over the check period (serverless only)
+	RequestCount         *float64   // Total commands over the check period (serverless only)
+	IsIdle               bool       // Whether the cache received no requests over the check period
+	IdleReason           string     // Reason why the cache is considered idle
+	EstimatedMonthlyCost float64    // Estimated monthly data storage cost (serverless only)
+	ValkeyEligible       bool       // Whether the Redis OSS cache can be migrated to Valkey
+	ValkeySavingsPercent float64    // Valkey price difference in percent (0 if not eligible)
+	Recommendation       string     // Cost recommendation note
+}
+
+// SortKey returns the canonical sort key for the ElastiCacheInfo
+func (c ElastiCacheInfo) SortKey() string {
+	return regionKey(c.Region, c.Name)
+}
+
+// IdleFlag reports whether the cache is considered idle
+func (c ElastiCacheInfo) IdleFlag() bool {
+	return c.IsIdle
+}
+
+// MonthlyCost returns the estimated monthly cost of the cache
+func (c ElastiCacheInfo) MonthlyCost() float64 {
+	return c.EstimatedMonthlyCost
+}
@@ -5,13 +5,48 @@ import "time"
 // ELBResource holds information about an idle Elastic Load Balancer
 type ELBResource struct {
 	Name                 string
-	Type                 string // ALB, NLB
+	Type                 string // ALB, NLB, GWLB, CLB
 	Region               string
 	State                string // active, idle
 	CreatedTime          time.Time
-	ARN                  string
-	HealthyTargetCount   int      // Renamed from TargetCount
-	UnhealthyTargetCount int      // Added for unhealthy count
-	IdleReason           string   // Reason why it's considered idle (e.g., No targets, Low traffic)
-	LastActivitySum      *float64 // Sum of relevant CloudWatch metric over the check period (e.g., 14 days)
+	ARN                  string            // Empty for Classic Load Balancers, which have no ARN in the ELB API
+	HealthyTargetCount   int               // Renamed from TargetCount
+	UnhealthyTargetCount int               // Added for unhealthy count
+	IsIdle               bool              // False if idleness is uncertain because the metric check failed
+	IdleReason           string            // Reason why it's considered idle (e.g., No targets, Low traffic)
+	LastActivitySum      *float64          // Sum of relevant CloudWatch metric over the check period (e.g., 14 days)
+	MetricCheckFailed    bool              // The traffic metric could not be checked
+	EstimatedMonthlyCost float64           // Base LoadBalancer-hour cost, excluding LCU/NLCU charges
+	PricingSource        string            // "API", "Cache", "Default", or "N/A"
+	Protected            bool              // Whether deletion protection is enabled (ALB, NLB, and GWLB only)
+	ProtectionReason     string            // Protection that blocks deletion, empty if not protected
+	Tags                 map[string]string // Resource tags
+}
+
+// SortKey returns the canonical sort key for the ELBResource
+func (e ELBResource) SortKey() string {
+	if e.ARN == "" {
+		return regionKey(e.Region, e.Name)
+	}
+	return regionKey(e.Region, e.ARN)
+}
+
+// IdleFlag reports whether the load balancer is considered idle
+func (e ELBResource) IdleFlag() bool {
+	return e.IsIdle
+}
+
+// MonthlyCost returns the estimated monthly cost of the load balancer
+func (e ELBResource) MonthlyCost() float64 {
+	return e.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the load balancer
+func (e ELBResource) PricingUnavailable() bool {
+	return e.PricingSource == "N/A"
+}
+
+// DeletionProtection reports whether the load balancer is protected against deletion, and why
+func (e ELBResource) DeletionProtection() (bool, string) {
+	return e.Protected, e.ProtectionReason
 }
@@ -0,0 +1,35 @@
+package models
+
+// Services inferred to have created an orphaned network interface
+const (
+	ENICreatorLambda      = "Lambda"
+	ENICreatorELB         = "ELB"
+	ENICreatorRDS         = "RDS"
+	ENICreatorECS         = "ECS"
+	ENICreatorVPCEndpoint = "VPC Endpoint"
+	ENICreatorEFS         = "EFS"
+	ENICreatorOther       = "Other"
+)
+
+// ENIInfo represents a network interface in the "available" status, not attached to anything
+type ENIInfo struct {
+	NetworkInterfaceID string
+	ARN                string
+	Description        string
+	InterfaceType      string // e.g. "interface", "lambda", "network_load_balancer"
+	Creator            string // Service inferred from the description, type, and requester
+	RequesterID        string // Account or service that created the interface, if not the owner
+	RequesterManaged   bool   // Managed by an AWS service, so it cannot be deleted manually
+	SubnetID           string
+	VpcID              string
+	AvailabilityZone   string
+	PrivateIP          string
+	SecurityGroups     []string // Security group IDs
+	Region             string
+	Tags               map[string]string // Resource tags
+}
+
+// SortKey returns the canonical sort key for the ENIInfo
+func (e ENIInfo) SortKey() string {
+	return regionKey(e.Region, e.NetworkInterfaceID)
+}
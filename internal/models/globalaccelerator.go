@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// AcceleratorInfo holds information about an idle AWS Global Accelerator accelerator
+type AcceleratorInfo struct {
+	AcceleratorID        string    // Accelerator ID, the last part of the ARN and its metric dimension
+	ARN                  string    // Accelerator ARN
+	Name                 string    // Accelerator name
+	Enabled              bool      // Whether the accelerator accepts traffic
+	Status               string    // DEPLOYED or IN_PROGRESS
+	IPAddressType        string    // IPV4 or DUAL_STACK
+	DNSName              string    // Accelerator DNS name (e.g., a1234567890abcdef.awsglobalaccelerator.com)
+	ProcessedBytesIn     *float64  // Bytes received over the check period, nil if not checked
+	CreatedTime          time.Time // When the accelerator was created
+	IdleReason           string    // Reason why the accelerator is considered idle
+	EstimatedMonthlyCost float64   // Fixed hourly accelerator fee
+}
+
+// SortKey returns the canonical sort key for the AcceleratorInfo
+func (a AcceleratorInfo) SortKey() string {
+	return a.AcceleratorID
+}
+
+// MonthlyCost returns the estimated monthly fixed fee of the accelerator
+func (a AcceleratorInfo) MonthlyCost() float64 {
+	return a.EstimatedMonthlyCost
+}
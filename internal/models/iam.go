@@ -4,23 +4,48 @@ import "time"
 
 // IAMUserInfo represents information about an IAM user
 type IAMUserInfo struct {
-	UserName              string     // IAM user name
-	UserID                string     // IAM user ID
-	ARN                   string     // Full ARN of the user
-	Region                string     // AWS region (global for IAM)
-	Path                  string     // Path to the user
-	CreateDate            *time.Time // When the user was created
-	PasswordLastUsed      *time.Time // When the password was last used for console login
-	AccessKeysLastUsed    *time.Time // The most recent access key usage timestamp
-	AccessKeyCount        int        // Number of access keys associated with the user
-	LastActivity          *time.Time // The most recent activity timestamp (login or API call)
-	IsIdle                bool       // Whether the user is considered idle
-	IdleDays              int        // Days since last activity
-	HasActiveAccessKeys   bool       // Whether the user has active access keys
-	HasMFAEnabled         bool       // Whether MFA is enabled for the user
-	HasInlinePolicies     bool       // Whether the user has inline policies
-	AttachedPolicyCount   int        // Number of managed policies attached to the user
-	UnusedPermissionsInfo []string   // Information about unused permissions
+	UserName              string             // IAM user name
+	UserID                string             // IAM user ID
+	ARN                   string             // Full ARN of the user
+	Region                string             // AWS region (global for IAM)
+	Path                  string             // Path to the user
+	CreateDate            *time.Time         // When the user was created
+	PasswordLastUsed      *time.Time         // When the password was last used for console login
+	AccessKeysLastUsed    *time.Time         // The most recent access key usage timestamp
+	AccessKeyCount        int                // Number of access keys associated with the user
+	LastActivity          *time.Time         // The most recent activity timestamp (login or API call)
+	IsIdle                bool               // Whether the user is considered idle
+	IdleDays              int                // Days since last activity
+	HasActiveAccessKeys   bool               // Whether the user has active access keys
+	AccessKeys            []IAMAccessKeyInfo // Details of each access key of the user
+	HasStaleKeys          bool               // Whether an active access key is older than the maximum age or never used
+	HasConsoleAccess      bool               // Whether the user has a console password
+	HasMFAEnabled         bool               // Whether MFA is enabled for the user
+	HasInlinePolicies     bool               // Whether the user has inline policies
+	AttachedPolicyCount   int                // Number of managed policies attached to the user
+	UnusedPermissionsInfo []string           // Information about unused permissions
+}
+
+// IAMAccessKeyInfo represents an access key of an IAM user
+type IAMAccessKeyInfo struct {
+	KeyIDSuffix string     // Last 4 characters of the access key ID
+	Status      string     // Active or Inactive
+	CreateDate  *time.Time // When the key was created, i.e. last rotated
+	LastUsed    *time.Time // When the key was last used, nil if never
+	AgeDays     int        // Days since the key was created
+	IsStale     bool       // Whether the key is active and older than the maximum age or never used
+}
+
+// OldestActiveKeyDays returns the age in days of the oldest active access key of the user,
+// and false if the user has no active access key
+func (u IAMUserInfo) OldestActiveKeyDays() (int, bool) {
+	oldest, found := 0, false
+	for _, key := range u.AccessKeys {
+		if key.Status == "Active" && (!found || key.AgeDays > oldest) {
+			oldest, found = key.AgeDays, true
+		}
+	}
+	return oldest, found
 }
 
 // IAMRoleInfo represents information about an IAM role
@@ -63,3 +88,60 @@ type IAMPolicyInfo struct {
 	UsedServiceCount   int        // Number of services used through this policy
 	UnusedServiceCount int        // Number of services granted but not used
 }
+
+// SortKey returns the canonical sort key for the IAMUserInfo
+func (u IAMUserInfo) SortKey() string {
+	return u.ARN
+}
+
+// SortKey returns the canonical sort key for the IAMRoleInfo
+func (r IAMRoleInfo) SortKey() string {
+	return r.ARN
+}
+
+// SortKey returns the canonical sort key for the IAMPolicyInfo
+func (p IAMPolicyInfo) SortKey() string {
+	return p.ARN
+}
+
+// IdleFlag reports whether the user is considered idle
+func (u IAMUserInfo) IdleFlag() bool {
+	return u.IsIdle
+}
+
+// IdleFlag reports whether the role is considered idle
+func (r IAMRoleInfo) IdleFlag() bool {
+	return r.IsIdle
+}
+
+// IdleFlag reports whether the policy is considered idle
+func (p IAMPolicyInfo) IdleFlag() bool {
+	return p.IsIdle
+}
+
+// IAMScanResult groups the IAM users, roles, and policies of the account
+type IAMScanResult struct {
+	Users    []IAMUserInfo
+	Roles    []IAMRoleInfo
+	Policies []IAMPolicyInfo
+}
+
+// SortKey returns the canonical sort key for the IAMScanResult. IAM is global, so there is one per scan.
+func (r IAMScanResult) SortKey() string {
+	return "global"
+}
+
+// Resources returns the users, roles, and policies of the account
+func (r IAMScanResult) Resources() []any {
+	resources := make([]any, 0, len(r.Users)+len(r.Roles)+len(r.Policies))
+	for _, user := range r.Users {
+		resources = append(resources, user)
+	}
+	for _, role := range r.Roles {
+		resources = append(resources, role)
+	}
+	for _, policy := range r.Policies {
+		resources = append(resources, policy)
+	}
+	return resources
+}
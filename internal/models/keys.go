@@ -0,0 +1,42 @@
+package models
+
+import "sort"
+
+// Keyed is implemented by resource models that expose a canonical sort key
+type Keyed interface {
+	SortKey() string
+}
+
+// IdleFlagged is implemented by resource models that report non-idle resources
+// alongside idle ones and carry an explicit idle flag
+type IdleFlagged interface {
+	IdleFlag() bool
+}
+
+// Costed is implemented by resource models that carry an estimated monthly cost
+type Costed interface {
+	MonthlyCost() float64
+}
+
+// Protectable is implemented by resource models that report deletion or termination protection
+type Protectable interface {
+	DeletionProtection() (protected bool, reason string)
+}
+
+// ResourceGroup is implemented by models that bundle resources of several types,
+// such as all AWS Config resources found in one region
+type ResourceGroup interface {
+	Resources() []any
+}
+
+// SortByKey sorts resources by their canonical key (region, then resource ID or name)
+func SortByKey[T Keyed](items []T) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].SortKey() < items[j].SortKey()
+	})
+}
+
+// regionKey builds a canonical sort key from a region and a resource identifier
+func regionKey(region, id string) string {
+	return region + "/" + id
+}
@@ -2,21 +2,58 @@ package models
 
 import "time"
 
+// Statuses of a Lambda function
+const (
+	LambdaStatusActive  = "Active"
+	LambdaStatusIdle    = "Idle"    // Not invoked within the idle threshold
+	LambdaStatusFailing = "Failing" // Invoked, but nearly every invocation errors
+	LambdaStatusNew     = "New"     // Modified within the idle threshold and not invoked yet
+)
+
 // LambdaFunctionInfo represents information about a Lambda function
 type LambdaFunctionInfo struct {
-	FunctionName          string     // Lambda function name
-	Description           string     // Function description (if available)
-	Runtime               string     // Runtime (e.g., nodejs16.x, python3.9)
-	Region                string     // AWS region
-	MemorySize            int32      // Memory allocation in MB
-	Timeout               int32      // Function timeout in seconds
-	LastModified          *time.Time // Last modification time
-	LastInvocation        *time.Time // Last invocation time (from CloudWatch)
-	InvocationsLast30Days int64      // Number of invocations in last 30 days
-	ErrorsLast30Days      int64      // Number of errors in last 30 days
-	DurationP95Last30Days float64    // 95th percentile duration in milliseconds
-	IsIdle                bool       // Whether the function is considered idle
-	IdleDays              int        // Days since last invocation
-	EstimatedMonthlyCost  float64    // Estimated monthly cost
-	HasTrigger            bool       // Whether the function has any triggers configured
+	FunctionName           string            // Lambda function name
+	Description            string            // Function description (if available)
+	Runtime                string            // Runtime (e.g., nodejs16.x, python3.9)
+	Architecture           string            // Instruction set architecture (x86_64 or arm64)
+	Region                 string            // AWS region
+	MemorySize             int32             // Memory allocation in MB
+	Timeout                int32             // Function timeout in seconds
+	LastModified           *time.Time        // Last modification time
+	LastInvocation         *time.Time        // Last invocation time within the 90-day lookback (from CloudWatch)
+	InvocationsLast30Days  int64             // Number of invocations in last 30 days
+	ErrorsLast30Days       int64             // Number of errors in last 30 days
+	DurationP95Last30Days  float64           // 95th percentile duration in milliseconds
+	Status                 string            // "Active", "Idle", "Failing", or "New"
+	IdleDays               int               // Days since last invocation, or since the last modification if not invoked within the lookback
+	EstimatedMonthlyCost   float64           // Estimated monthly cost
+	PricingSource          string            // Source of pricing data (API, Cache, Default, N/A)
+	HasTrigger             bool              // Whether the function has any triggers configured
+	ProvisionedConcurrency int32             // Provisioned concurrency requested across all aliases and versions
+	ReservedConcurrency    *int32            // Reserved concurrency, nil if the function uses the unreserved pool
+	Tags                   map[string]string // Resource tags
+}
+
+// SortKey returns the canonical sort key for the LambdaFunctionInfo
+func (f LambdaFunctionInfo) SortKey() string {
+	return regionKey(f.Region, f.FunctionName)
+}
+
+// IdleFlag reports whether the function is idle or failing, so that it is reported as a finding
+func (f LambdaFunctionInfo) IdleFlag() bool {
+	return f.Status == LambdaStatusIdle || f.Status == LambdaStatusFailing
+}
+
+// ErrorRate returns the percentage of the invocations of the last 30 days that errored, and
+// false if the function was not invoked
+func (f LambdaFunctionInfo) ErrorRate() (float64, bool) {
+	if f.InvocationsLast30Days == 0 {
+		return 0, false
+	}
+	return float64(f.ErrorsLast30Days) / float64(f.InvocationsLast30Days) * 100, true
+}
+
+// MonthlyCost returns the estimated monthly cost of the function
+func (f LambdaFunctionInfo) MonthlyCost() float64 {
+	return f.EstimatedMonthlyCost
 }
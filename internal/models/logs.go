@@ -4,11 +4,39 @@ import "time"
 
 // LogGroupInfo holds information about a CloudWatch Log Group relevant for idle checking.
 type LogGroupInfo struct {
-	Name            string
-	RetentionDays   string
-	StoredBytes     string // Formatted string (e.g., using humanize)
-	LastEventTime   string // Formatted string (actual last event or fallback)
-	ARN             string
-	CreationTime    time.Time // Original creation time
-	LastEventMillis int64     // Timestamp for sorting (actual or creation)
+	Name                  string
+	Region                string
+	RetentionDays         string
+	StoredBytes           int64  // Raw stored bytes
+	LastEventTime         string // Formatted string (actual last event or fallback)
+	ARN                   string
+	CreationTime          time.Time // Original creation time
+	LastEventMillis       int64     // Timestamp for sorting (actual or creation)
+	EstimatedMonthlyCost  float64
+	PricingSource         string // "API", "Cache", "Default", or "N/A"
+	Recommendation        string // Suggested remediation, empty if none
+	HasSubscriptionFilter bool   // Events are streamed to a destination (e.g., a SIEM)
+	HasMetricFilter       bool   // Events feed CloudWatch metrics (e.g., for alarms)
+	FilterCheckFailed     bool   // The subscription and metric filters could not be checked
+}
+
+// SortKey returns the canonical sort key for the LogGroupInfo
+func (lg LogGroupInfo) SortKey() string {
+	return lg.ARN
+}
+
+// MonthlyCost returns the estimated monthly cost of the log group
+func (lg LogGroupInfo) MonthlyCost() float64 {
+	return lg.EstimatedMonthlyCost
+}
+
+// IsReferenced reports whether the log group is still referenced by a filter, or could not
+// be confirmed unreferenced
+func (lg LogGroupInfo) IsReferenced() bool {
+	return lg.HasSubscriptionFilter || lg.HasMetricFilter || lg.FilterCheckFailed
+}
+
+// PricingUnavailable reports whether no pricing data was found for the log group
+func (lg LogGroupInfo) PricingUnavailable() bool {
+	return lg.PricingSource == "N/A"
 }
@@ -10,10 +10,38 @@ type MskClusterInfo struct {
 	ARN               string    `header:"ARN"`
 	Region            string    `header:"Region"`
 	State             string    `header:"State"`
+	ClusterType       string    `header:"Cluster Type"` // "PROVISIONED" or "SERVERLESS"
+	BrokerCount       int       `header:"Brokers"`      // Number of broker nodes of a provisioned cluster
 	InstanceType      string    `header:"Instance Type"`
 	CreationTime      time.Time `header:"Creation Time"`
 	IsIdle            bool      `header:"Is Idle"`
 	Reason            string    `header:"Reason"`                // "No Connections", "Low CPU Usage", "No Conn & Low CPU"
 	ConnectionCount   *float64  `header:"Max Connections (30d)"` // Max connection count over the check period
 	AvgCPUUtilization *float64  `header:"Avg CPU (30d %)"`       // Average CPU Utilization over check period
+
+	ZeroConnectionDays            int `header:"Zero Conn Days (30d)"`  // Days in the check period without connections
+	ConsecutiveZeroConnectionDays int `header:"Latest Zero Conn Days"` // Zero connection days up to the latest day with data
+
+	EstimatedMonthlyCost float64 `header:"Monthly Cost"`   // Broker instance cost of a provisioned cluster, excluding storage
+	PricingSource        string  `header:"Pricing Source"` // "API", "Cache", "Default", or "N/A"
+}
+
+// SortKey returns the canonical sort key for the MskClusterInfo
+func (c MskClusterInfo) SortKey() string {
+	return regionKey(c.Region, c.ARN)
+}
+
+// MonthlyCost returns the estimated monthly broker cost of the cluster
+func (c MskClusterInfo) MonthlyCost() float64 {
+	return c.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the cluster
+func (c MskClusterInfo) PricingUnavailable() bool {
+	return c.PricingSource == "N/A"
+}
+
+// IdleFlag reports whether the cluster is considered idle
+func (c MskClusterInfo) IdleFlag() bool {
+	return c.IsIdle
 }
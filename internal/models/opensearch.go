@@ -0,0 +1,44 @@
+package models
+
+// OpenSearchDomainInfo holds information about an idle or underutilized OpenSearch Service domain
+type OpenSearchDomainInfo struct {
+	DomainName           string   // Domain name
+	ARN                  string   // Domain ARN
+	Region               string   // AWS region
+	EngineVersion        string   // e.g., "OpenSearch_2.11" or "Elasticsearch_7.10"
+	InstanceType         string   // Data node instance type
+	InstanceCount        int      // Number of data nodes
+	DedicatedMasterType  string   // Dedicated master node instance type, empty without dedicated master nodes
+	DedicatedMasterCount int      // Number of dedicated master nodes
+	WarmType             string   // UltraWarm node instance type, empty without UltraWarm nodes
+	WarmCount            int      // Number of UltraWarm nodes
+	VolumeType           string   // EBS volume type of the data nodes, empty without EBS storage
+	VolumeSizeGB         int      // EBS volume size per data node
+	SearchRate           *float64 // Average SearchRate over the check period, nil without data
+	IndexingRate         *float64 // Average IndexingRate over the check period, nil without data
+	AvgCPUUtilization    *float64 // Average CPUUtilization over the check period, nil without data
+	IsIdle               bool     // Whether the domain is idle or underutilized
+	Reason               string   // "No Search & Indexing" or "Low CPU Usage"
+	EstimatedMonthlyCost float64  // Node instance and EBS storage cost
+	PricingSource        string   // "API", "Cache", "Default", or "N/A"
+}
+
+// SortKey returns the canonical sort key for the OpenSearchDomainInfo
+func (d OpenSearchDomainInfo) SortKey() string {
+	return regionKey(d.Region, d.DomainName)
+}
+
+// MonthlyCost returns the estimated monthly node and storage cost of the domain
+func (d OpenSearchDomainInfo) MonthlyCost() float64 {
+	return d.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the domain
+func (d OpenSearchDomainInfo) PricingUnavailable() bool {
+	return d.PricingSource == "N/A"
+}
+
+// IdleFlag reports whether the domain is considered idle or underutilized
+func (d OpenSearchDomainInfo) IdleFlag() bool {
+	return d.IsIdle
+}
@@ -0,0 +1,26 @@
+package models
+
+// ResolverEndpointInfo holds information about a Route 53 Resolver endpoint without DNS queries
+type ResolverEndpointInfo struct {
+	EndpointID           string  // Resolver endpoint ID (rslvr-in-... or rslvr-out-...)
+	ARN                  string  // Resolver endpoint ARN
+	Name                 string  // Resolver endpoint name
+	Region               string  // AWS region
+	Direction            string  // INBOUND, OUTBOUND, or INBOUND_DELEGATION
+	VPCID                string  // VPC the endpoint network interfaces are in
+	IPAddressCount       int     // Number of IP addresses, each billed as an ENI
+	Status               string  // OPERATIONAL, ACTION_NEEDED, ...
+	QueryVolume          float64 // DNS queries over the check period
+	IdleReason           string  // Reason why the endpoint is considered idle
+	EstimatedMonthlyCost float64 // Hourly price per IP address
+}
+
+// SortKey returns the canonical sort key for the ResolverEndpointInfo
+func (e ResolverEndpointInfo) SortKey() string {
+	return regionKey(e.Region, e.EndpointID)
+}
+
+// MonthlyCost returns the estimated monthly cost of the endpoint network interfaces
+func (e ResolverEndpointInfo) MonthlyCost() float64 {
+	return e.EstimatedMonthlyCost
+}
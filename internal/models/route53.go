@@ -0,0 +1,66 @@
+package models
+
+// HostedZoneInfo holds information about an idle Route 53 hosted zone
+type HostedZoneInfo struct {
+	ID                   string   // Hosted zone ID without the /hostedzone/ prefix
+	Name                 string   // Domain name of the zone, without the trailing dot
+	Private              bool     // Whether the zone is a private hosted zone
+	RecordCount          int64    // Number of record sets, including the default NS and SOA records
+	NameServers          []string // Name servers of the zone's NS record (public zones only)
+	ResolvedNameServers  []string // Name servers returned by a DNS lookup of the domain (public zones only)
+	IdleReason           string   // Reason why the zone is considered idle
+	EstimatedMonthlyCost float64  // Monthly hosted zone charge
+}
+
+// HealthCheckInfo holds information about a Route 53 health check not referenced by any record set
+type HealthCheckInfo struct {
+	ID                   string // Health check ID
+	Type                 string // HTTP, HTTPS, TCP, CALCULATED, CLOUDWATCH_METRIC, ...
+	Target               string // Checked endpoint (domain name or IP address and port), "-" if none
+	Disabled             bool   // Whether the health check is disabled
+	IdleReason           string // Reason why the health check is considered idle
+	EstimatedMonthlyCost float64
+}
+
+// SortKey returns the canonical sort key for the HostedZoneInfo
+func (z HostedZoneInfo) SortKey() string {
+	return z.Name + "/" + z.ID
+}
+
+// SortKey returns the canonical sort key for the HealthCheckInfo
+func (h HealthCheckInfo) SortKey() string {
+	return h.ID
+}
+
+// MonthlyCost returns the monthly charge of the hosted zone
+func (z HostedZoneInfo) MonthlyCost() float64 {
+	return z.EstimatedMonthlyCost
+}
+
+// MonthlyCost returns the estimated monthly charge of the health check
+func (h HealthCheckInfo) MonthlyCost() float64 {
+	return h.EstimatedMonthlyCost
+}
+
+// Route53ScanResult groups the idle Route 53 hosted zones and health checks of the account
+type Route53ScanResult struct {
+	Zones        []HostedZoneInfo
+	HealthChecks []HealthCheckInfo
+}
+
+// SortKey returns the canonical sort key for the Route53ScanResult. Route 53 is global, so there is one per scan.
+func (r Route53ScanResult) SortKey() string {
+	return "global"
+}
+
+// Resources returns the hosted zones and health checks of the account
+func (r Route53ScanResult) Resources() []any {
+	resources := make([]any, 0, len(r.Zones)+len(r.HealthChecks))
+	for _, zone := range r.Zones {
+		resources = append(resources, zone)
+	}
+	for _, healthCheck := range r.HealthChecks {
+		resources = append(resources, healthCheck)
+	}
+	return resources
+}
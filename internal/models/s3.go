@@ -10,9 +10,15 @@ type BucketInfo struct {
 	ObjectCount  int64
 	TotalSize    int64 // in bytes
 
+	// Cost estimation
+	StorageTypeSizes     map[string]int64 // BucketSizeBytes by CloudWatch StorageType dimension
+	EstimatedMonthlyCost float64
+	PricingSource        string // "API", "Cache", or "Default"
+
 	// Activity metrics
-	LastModified *time.Time // Last object modification time
-	LastAccessed *time.Time // Last access time (if logging enabled)
+	LastModified    *time.Time // Last object modification time, nil if unknown
+	LastAccessed    *time.Time // Last access time (if logging enabled)
+	ActivityUnknown bool       // True if no activity date could be derived from the CloudWatch metrics
 
 	// Activity change metrics
 	ObjectCountChange int64 // Object count change over specified period
@@ -31,4 +37,36 @@ type BucketInfo struct {
 	HasWebsiteConfig     bool // True if bucket has website configuration
 	HasBucketPolicy      bool // True if bucket has a policy
 	HasEventNotification bool // True if bucket has event notifications
+	HasLifecyclePolicy   bool // True if bucket has a lifecycle policy
+	VersioningEnabled    bool // True if bucket versioning is enabled
+	IncompleteMPUCount   int  // Number of incomplete multipart uploads
+
+	// Deletion protection, only checked for idle buckets
+	Protected        bool   // True if MFA delete or Object Lock is enabled
+	ProtectionReason string // Protections that block deletion, empty if not protected
+}
+
+// SortKey returns the canonical sort key for the BucketInfo
+func (b BucketInfo) SortKey() string {
+	return regionKey(b.Region, b.BucketName)
+}
+
+// IdleFlag reports whether the bucket is considered idle
+func (b BucketInfo) IdleFlag() bool {
+	return b.IsIdle
+}
+
+// MonthlyCost returns the estimated monthly cost of the bucket
+func (b BucketInfo) MonthlyCost() float64 {
+	return b.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the bucket
+func (b BucketInfo) PricingUnavailable() bool {
+	return b.PricingSource == "N/A"
+}
+
+// DeletionProtection reports whether the bucket is protected against deletion, and why
+func (b BucketInfo) DeletionProtection() (bool, string) {
+	return b.Protected, b.ProtectionReason
 }
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// SageMaker resource types
+const (
+	SageMakerResourceEndpoint = "Endpoint"
+	SageMakerResourceNotebook = "Notebook"
+)
+
+// SageMakerResourceInfo holds information about an idle SageMaker endpoint or notebook instance
+type SageMakerResourceInfo struct {
+	Name                 string    // Endpoint or notebook instance name
+	ARN                  string    // Endpoint or notebook instance ARN
+	Region               string    // AWS region
+	ResourceType         string    // "Endpoint" or "Notebook"
+	InstanceType         string    // ML instance type (first production variant of an endpoint)
+	InstanceCount        int       // Instances of all production variants, 1 for a notebook instance
+	Serverless           bool      // Whether the endpoint only has serverless variants
+	Status               string    // InService, Stopped, ...
+	CreationTime         time.Time // When the resource was created
+	LastModifiedTime     time.Time // When the resource was last modified (e.g., started or updated)
+	VolumeSizeGB         int       // ML storage volume size of a notebook instance
+	Invocations          *float64  // Endpoint invocations over the check period, nil for notebooks
+	AvgCPUUtilization    *float64  // Average notebook CPU over the check period, nil if not published
+	IdleReason           string    // Reason why the resource is considered idle
+	EstimatedMonthlyCost float64   // Instance cost, plus ML storage for notebook instances
+	PricingSource        string    // "API", "Cache", "Default", or "N/A"
+}
+
+// SortKey returns the canonical sort key for the SageMakerResourceInfo
+func (r SageMakerResourceInfo) SortKey() string {
+	return regionKey(r.Region, r.ARN)
+}
+
+// MonthlyCost returns the estimated monthly cost of the resource
+func (r SageMakerResourceInfo) MonthlyCost() float64 {
+	return r.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no pricing data was found for the resource
+func (r SageMakerResourceInfo) PricingUnavailable() bool {
+	return r.PricingSource == "N/A"
+}
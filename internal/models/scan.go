@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ScanMetadata describes the scan that produced a set of results
+type ScanMetadata struct {
+	Account   string    // AWS account ID the credentials belong to
+	CallerARN string    // ARN of the IAM user or role running the scan
+	Regions   []string  // Regions requested for the scan
+	StartedAt time.Time // Time the scan started
+}
+
+// APICallStat counts the AWS API calls made to one operation of a service in a region
+type APICallStat struct {
+	Service   string // AWS service ID, e.g. "EC2"
+	Operation string // API operation, e.g. "DescribeVolumes"
+	Region    string
+	Calls     int64 // Calls made, each counted once however many times it was retried
+	Throttles int64 // Attempts rejected by throttling
+	Errors    int64 // Calls that failed after all retries
+}
+
+// SkippedResource is a resource a scanner could not evaluate, left out of the results
+type SkippedResource struct {
+	Service  string // Service name used with --services, e.g. "s3"
+	Region   string // AWS region, "global" for global services, "unknown" if it could not be read
+	Resource string // Resource name, ID, or ARN
+	Reason   string // Short cause shared by the resources skipped the same way, e.g. "bucket location unavailable"
+	Error    string // Error message of the failed call
+}
@@ -4,9 +4,24 @@ import "time"
 
 // SecretInfo holds information about an AWS Secrets Manager secret.
 type SecretInfo struct {
-	ARN              string    `json:"arn"`
-	Name             string    `json:"name"`
-	Region           string    `json:"region"`
-	LastAccessedDate time.Time `json:"lastAccessedDate"`
-	IdleDays         int       `json:"idleDays"`
+	ARN                  string     `json:"arn"`
+	Name                 string     `json:"name"`
+	Region               string     `json:"region"`
+	LastAccessedDate     time.Time  `json:"lastAccessedDate"` // Zero if the secret was never accessed
+	CreatedDate          time.Time  `json:"createdDate"`
+	NeverAccessed        bool       `json:"neverAccessed"` // IdleDays is counted from CreatedDate
+	IdleDays             int        `json:"idleDays"`
+	RotationEnabled      bool       `json:"rotationEnabled"`
+	LastRotatedDate      *time.Time `json:"lastRotatedDate"` // Nil if the secret was never rotated
+	EstimatedMonthlyCost float64    `json:"estimatedMonthlyCost"`
+}
+
+// SortKey returns the canonical sort key for the SecretInfo
+func (s SecretInfo) SortKey() string {
+	return regionKey(s.Region, s.ARN)
+}
+
+// MonthlyCost returns the monthly storage cost of the secret
+func (s SecretInfo) MonthlyCost() float64 {
+	return s.EstimatedMonthlyCost
 }
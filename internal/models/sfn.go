@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// StateMachineInfo represents a Step Functions state machine and its recent executions
+type StateMachineInfo struct {
+	Name               string     // State machine name
+	ARN                string     // State machine ARN
+	Type               string     // STANDARD or EXPRESS
+	Region             string     // AWS region
+	CreationDate       time.Time  // When the state machine was created
+	LastExecution      *time.Time // Last execution start, nil if none was found within the lookback
+	ExecutionsInWindow int64      // Executions started within the idle threshold (from CloudWatch)
+	IsIdle             bool       // Whether no execution started within the idle threshold
+	IdleDays           int        // Days since the last execution, or since creation up to the lookback if none was found
+}
+
+// SortKey returns the canonical sort key for the StateMachineInfo
+func (m StateMachineInfo) SortKey() string {
+	return regionKey(m.Region, m.Name)
+}
+
+// IdleFlag reports whether the state machine is considered idle
+func (m StateMachineInfo) IdleFlag() bool {
+	return m.IsIdle
+}
@@ -0,0 +1,26 @@
+package models
+
+// TransferServerInfo holds information about an AWS Transfer Family server without file transfers
+type TransferServerInfo struct {
+	ServerID             string   // Server ID (s-...)
+	ARN                  string   // Server ARN
+	Region               string   // AWS region
+	EndpointType         string   // PUBLIC, VPC, or VPC_ENDPOINT
+	Domain               string   // S3 or EFS
+	Protocols            []string // Enabled protocols (SFTP, FTPS, FTP, AS2)
+	State                string   // ONLINE, OFFLINE, ...
+	UserCount            int      // Users configured on the server
+	FilesTransferred     float64  // FilesIn and FilesOut over the check period
+	IdleReason           string   // Reason why the server is considered idle
+	EstimatedMonthlyCost float64  // Hourly endpoint price per enabled protocol
+}
+
+// SortKey returns the canonical sort key for the TransferServerInfo
+func (s TransferServerInfo) SortKey() string {
+	return regionKey(s.Region, s.ServerID)
+}
+
+// MonthlyCost returns the estimated monthly cost of the server endpoint
+func (s TransferServerInfo) MonthlyCost() float64 {
+	return s.EstimatedMonthlyCost
+}
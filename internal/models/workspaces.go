@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// WorkSpaceInfo holds information about an Amazon WorkSpace nobody has connected to recently
+type WorkSpaceInfo struct {
+	WorkspaceID          string     // WorkSpace ID (ws-...)
+	UserName             string     // User the WorkSpace is assigned to
+	DirectoryID          string     // Directory the WorkSpace belongs to
+	BundleID             string     // Bundle the WorkSpace was created from
+	ComputeType          string     // Bundle compute type (VALUE, STANDARD, ...)
+	RunningMode          string     // ALWAYS_ON, AUTO_STOP, or MANUAL
+	State                string     // AVAILABLE, STOPPED, ...
+	Region               string     // AWS region
+	LastConnection       *time.Time // Last known user connection, nil if the user never connected
+	IdleDays             int        // Days since the last connection, 0 if the user never connected
+	EstimatedMonthlyCost float64    // Monthly cost saved by removing the WorkSpace
+	PricingSource        string     // "Default" or "N/A"
+}
+
+// SortKey returns the canonical sort key for the WorkSpaceInfo
+func (w WorkSpaceInfo) SortKey() string {
+	return regionKey(w.Region, w.WorkspaceID)
+}
+
+// MonthlyCost returns the monthly cost saved by removing the WorkSpace: the full bundle
+// price for ALWAYS_ON WorkSpaces and the fixed monthly fee for AUTO_STOP ones
+func (w WorkSpaceInfo) MonthlyCost() float64 {
+	return w.EstimatedMonthlyCost
+}
+
+// PricingUnavailable reports whether no price is known for the bundle compute type
+func (w WorkSpaceInfo) PricingUnavailable() bool {
+	return w.PricingSource == "N/A"
+}
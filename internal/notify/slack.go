@@ -0,0 +1,215 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+const (
+	// topResourcesCount is the number of most expensive idle resources listed in the digest
+	topResourcesCount = 10
+	// maxSectionTextLength keeps section text below Slack's 3000 character limit
+	maxSectionTextLength = 2900
+	// maxNameLength truncates long resource names such as ARNs
+	maxNameLength = 80
+	// slackTimeout bounds the webhook request
+	slackTimeout = 10 * time.Second
+)
+
+// slackMessage is a Slack Block Kit message
+type slackMessage struct {
+	Text   string       `json:"text"` // Fallback for notifications
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackBlock is a Block Kit layout block
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackText is a Block Kit text object
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// serviceCount is the number of idle resources and their cost for one service
+type serviceCount struct {
+	service     string
+	count       int
+	monthlyCost float64
+	unpriced    int
+}
+
+// rankedResource is an idle resource ranked by estimated monthly cost
+type rankedResource struct {
+	models.CostItem
+	service string
+	region  string
+}
+
+// HasFindings reports whether any idle resource was found
+func HasFindings(summaries []models.CostSummary) bool {
+	for _, summary := range summaries {
+		if summary.Count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildSlackDigest builds a Block Kit message with the idle resource counts per service,
+// the most expensive idle resources, and the total estimated monthly savings
+func BuildSlackDigest(summaries []models.CostSummary) ([]byte, error) {
+	counts, resources := aggregate(summaries)
+
+	var total, unpriced int
+	var totalCost float64
+	for _, count := range counts {
+		total += count.count
+		totalCost += count.monthlyCost
+		unpriced += count.unpriced
+	}
+
+	message := slackMessage{
+		Text: fmt.Sprintf("idled found %d idle resources (est. $%.2f/month)", total, totalCost),
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: "idled: idle AWS resources"}},
+		},
+	}
+
+	// Idle resource counts per service
+	var lines []string
+	for _, count := range counts {
+		lines = append(lines, fmt.Sprintf("• *%s*: %d idle", count.service, count.count))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No idle resources found.")
+	}
+	message.Blocks = append(message.Blocks, section("*Idle resources by service*\n"+joinLines(lines)))
+
+	// Most expensive idle resources
+	if len(resources) > 0 {
+		lines = lines[:0]
+		for i, resource := range resources {
+			if i == topResourcesCount {
+				break
+			}
+			lines = append(lines, fmt.Sprintf("%d. `%s` (%s, %s) $%.2f/mo",
+				i+1, truncate(resource.Name, maxNameLength), resource.service, resource.region, resource.MonthlyCost))
+		}
+		message.Blocks = append(message.Blocks, section(fmt.Sprintf("*Top %d most expensive idle resources*\n%s", len(lines), joinLines(lines))))
+	}
+
+	// Total estimated savings
+	totalText := fmt.Sprintf("*Total estimated monthly savings:* $%.2f", totalCost)
+	if unpriced > 0 {
+		totalText += fmt.Sprintf("\n_%d idle resource(s) have no pricing data and are not included._", unpriced)
+	}
+	message.Blocks = append(message.Blocks, section(totalText))
+	message.Blocks = append(message.Blocks, slackBlock{
+		Type:     "context",
+		Elements: []slackText{{Type: "mrkdwn", Text: "Scanned at " + time.Now().UTC().Format(time.RFC3339)}},
+	})
+
+	return json.Marshal(message)
+}
+
+// PostSlack posts a message payload to a Slack incoming webhook
+func PostSlack(ctx context.Context, webhookURL string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, slackTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create Slack webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post Slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("slack webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// aggregate sums the summaries per service, sorted by idle count (highest first), and
+// ranks the priced idle resources of all services by monthly cost (highest first)
+func aggregate(summaries []models.CostSummary) ([]serviceCount, []rankedResource) {
+	index := make(map[string]int)
+	var counts []serviceCount
+	var resources []rankedResource
+	for _, summary := range summaries {
+		if summary.Count == 0 {
+			continue
+		}
+		i, ok := index[summary.Service]
+		if !ok {
+			i = len(counts)
+			index[summary.Service] = i
+			counts = append(counts, serviceCount{service: summary.Service})
+		}
+		counts[i].count += summary.Count
+		counts[i].monthlyCost += summary.MonthlyCost
+		counts[i].unpriced += summary.Unpriced
+		for _, item := range summary.Resources {
+			resources = append(resources, rankedResource{CostItem: item, service: summary.Service, region: summary.Region})
+		}
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].service < counts[j].service
+	})
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].MonthlyCost > resources[j].MonthlyCost
+	})
+	return counts, resources
+}
+
+// section builds a mrkdwn section block
+func section(text string) slackBlock {
+	return slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}
+}
+
+// joinLines joins lines up to the section size limit, replacing the rest with a "more" line
+func joinLines(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		more := fmt.Sprintf("…and %d more", len(lines)-i)
+		if b.Len()+len(line)+len(more)+2 > maxSectionTextLength {
+			b.WriteString(more)
+			break
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// truncate shortens s to maxLength bytes, marking the cut with ".."
+func truncate(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+	return s[:maxLength-2] + ".."
+}
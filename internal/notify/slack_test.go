@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// Slack limits of Block Kit messages
+const (
+	slackMaxBlocks            = 50
+	slackMaxSectionTextLength = 3000
+	slackMaxHeaderTextLength  = 150
+)
+
+func TestBuildSlackDigestLimits(t *testing.T) {
+	// 300 services with 20 idle resources each, named by long ARNs
+	var summaries []models.CostSummary
+	for i := 0; i < 300; i++ {
+		summary := models.CostSummary{Service: fmt.Sprintf("Service %03d", i), Region: "us-east-1", Count: 20, Estimated: true}
+		for j := 0; j < summary.Count; j++ {
+			name := fmt.Sprintf("arn:aws:service:us-east-1:123456789012:resource/%s-%03d-%02d", strings.Repeat("x", 150), i, j)
+			summary.Resources = append(summary.Resources, models.CostItem{Name: name, MonthlyCost: float64(i*100 + j)})
+			summary.MonthlyCost += float64(i*100 + j)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	payload, err := BuildSlackDigest(summaries)
+	if err != nil {
+		t.Fatalf("BuildSlackDigest() error = %v", err)
+	}
+	var message slackMessage
+	if err := json.Unmarshal(payload, &message); err != nil {
+		t.Fatalf("payload is not a Slack message: %v", err)
+	}
+
+	if len(message.Blocks) > slackMaxBlocks {
+		t.Errorf("message has %d blocks, want at most %d", len(message.Blocks), slackMaxBlocks)
+	}
+	for i, block := range message.Blocks {
+		if block.Text == nil {
+			continue
+		}
+		limit := slackMaxSectionTextLength
+		if block.Type == "header" {
+			limit = slackMaxHeaderTextLength
+		}
+		if length := utf8.RuneCountInString(block.Text.Text); length > limit {
+			t.Errorf("block %d (%s) has %d characters, want at most %d", i, block.Type, length, limit)
+		}
+	}
+
+	// The services cut from the count section are summed up in its last line
+	services := strings.Split(message.Blocks[1].Text.Text, "\n")
+	last := services[len(services)-1]
+	more, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(last, "…and "), " more"))
+	if err != nil {
+		t.Fatalf("last line of the count section = %q, want \"…and N more\"", last)
+	}
+	if listed := len(services) - 2; listed+more != 300 {
+		t.Errorf("count section lists %d services and %d more, want 300 in all", listed, more)
+	}
+
+	// The top resources are the most expensive ones, with their names shortened
+	top := strings.Split(message.Blocks[2].Text.Text, "\n")
+	if len(top) != topResourcesCount+1 || top[0] != "*Top 10 most expensive idle resources*" {
+		t.Fatalf("top section = %q, want a title and %d resources", top, topResourcesCount)
+	}
+	if !strings.HasPrefix(top[1], "1. `arn:aws:service") || !strings.Contains(top[1], "..` (Service 299, us-east-1) $29919.00/mo") {
+		t.Errorf("first top resource = %q, want the most expensive one with a shortened name", top[1])
+	}
+	if wantFallback := "idled found 6000 idle resources"; !strings.HasPrefix(message.Text, wantFallback) {
+		t.Errorf("fallback text = %q, want prefix %q", message.Text, wantFallback)
+	}
+}
+
+func TestBuildSlackDigestWithoutFindings(t *testing.T) {
+	payload, err := BuildSlackDigest([]models.CostSummary{{Service: "EC2", Region: "us-east-1"}})
+	if err != nil {
+		t.Fatalf("BuildSlackDigest() error = %v", err)
+	}
+	var message slackMessage
+	if err := json.Unmarshal(payload, &message); err != nil {
+		t.Fatal(err)
+	}
+
+	// Header, counts, total, and context, without a top resources section
+	if len(message.Blocks) != 4 || message.Blocks[1].Text.Text != "*Idle resources by service*\nNo idle resources found." {
+		t.Errorf("blocks = %+v, want the no findings digest", message.Blocks)
+	}
+}
+
+func TestJoinLines(t *testing.T) {
+	line := strings.Repeat("x", 99)
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = line
+	}
+
+	joined := joinLines(lines)
+	if len(joined) > maxSectionTextLength {
+		t.Errorf("joinLines() has %d bytes, want at most %d", len(joined), maxSectionTextLength)
+	}
+	// 28 lines of 100 bytes leave no room for a 29th and the "more" line
+	if got, want := joined[strings.LastIndex(joined, "\n")+1:], "…and 72 more"; got != want {
+		t.Errorf("last line = %q, want %q", got, want)
+	}
+	if got := joinLines(lines[:3]); got != line+"\n"+line+"\n"+line {
+		t.Errorf("joinLines() of short lines = %q, want them all", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "short", want: "short"},
+		{s: "exactly-10", want: "exactly-10"},
+		{s: "eleven-char", want: "eleven-c.."},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.s, 10); got != tt.want {
+			t.Errorf("truncate(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Policy holds centrally managed scan settings such as thresholds and ignore lists.
+// Unset fields leave the corresponding defaults and flags untouched.
+type Policy struct {
+	Version     string   `json:"version,omitempty"`     // Free-form policy version for auditing
+	Regions     []string `json:"regions,omitempty"`     // Regions to scan
+	Services    []string `json:"services,omitempty"`    // Services to scan
+	MinIdleDays *int     `json:"minIdleDays,omitempty"` // Minimum idle age in days (same as --min-idle-days)
+	OnlyIdle    *bool    `json:"onlyIdle,omitempty"`    // Hide non-idle resources (same as --only-idle)
+	Ignore      []string `json:"ignore,omitempty"`      // Resource IDs, names, or ARNs excluded from output
+}
+
+// Source describes where a policy document was fetched from
+type Source struct {
+	Kind     string // "SSM" or "AppConfig"
+	Location string // Parameter name or application/environment/profile
+	Version  string // Parameter version or configuration version label
+}
+
+// String returns a human-readable description of the policy source
+func (s Source) String() string {
+	if s.Version == "" {
+		return fmt.Sprintf("%s %s", s.Kind, s.Location)
+	}
+	return fmt.Sprintf("%s %s (version %s)", s.Kind, s.Location, s.Version)
+}
+
+// Parse decodes and validates a JSON policy document. Unknown fields are rejected
+// so that typos in centrally managed policies do not silently fall back to defaults.
+func Parse(data []byte) (*Policy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var p Policy
+	if err := decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid policy document: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks that the policy values are within the accepted ranges
+func (p *Policy) Validate() error {
+	if p.MinIdleDays != nil && *p.MinIdleDays < 0 {
+		return fmt.Errorf("invalid policy document: minIdleDays must not be negative (got %d)", *p.MinIdleDays)
+	}
+	for _, entry := range p.Ignore {
+		if strings.TrimSpace(entry) == "" {
+			return fmt.Errorf("invalid policy document: ignore entries must not be empty")
+		}
+	}
+	return nil
+}
+
+// Settings are the values of the settings a policy provides, as set by their flags, the config
+// file, or their defaults
+type Settings struct {
+	Regions     []string
+	Services    []string
+	MinIdleDays int
+	OnlyIdle    bool
+	Ignore      []string
+}
+
+// Apply replaces the settings of s with the values of the policy. isSet reports whether the
+// setting of a policy key was set by its flag, which takes precedence over the policy. The
+// ignore list has no flag and is always taken from the policy.
+func (p *Policy) Apply(s *Settings, isSet func(key string) bool) {
+	if len(p.Regions) > 0 && !isSet("regions") {
+		s.Regions = p.Regions
+	}
+	if len(p.Services) > 0 && !isSet("services") {
+		s.Services = p.Services
+	}
+	if p.MinIdleDays != nil && !isSet("minIdleDays") {
+		s.MinIdleDays = *p.MinIdleDays
+	}
+	if p.OnlyIdle != nil && !isSet("onlyIdle") {
+		s.OnlyIdle = *p.OnlyIdle
+	}
+	s.Ignore = p.Ignore
+}
+
+// ParseAppConfigIdentifier splits an "application/environment/profile" identifier
+func ParseAppConfigIdentifier(identifier string) (application, environment, profile string, err error) {
+	parts := strings.Split(identifier, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid AppConfig identifier %q: expected application/environment/profile", identifier)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
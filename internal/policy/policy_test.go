@@ -0,0 +1,106 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  string
+	}{
+		{name: "full", document: `{"version": "2026-10", "regions": ["us-east-1"], "services": ["ec2"], "minIdleDays": 30, "onlyIdle": true, "ignore": ["i-0123"]}`},
+		{name: "empty", document: `{}`},
+		{name: "malformed", document: `{"minIdleDays": 30`, wantErr: "invalid policy document"},
+		{name: "not an object", document: `["ec2"]`, wantErr: "invalid policy document"},
+		{name: "unknown field", document: `{"minIdleDay": 30}`, wantErr: `unknown field "minIdleDay"`},
+		{name: "wrong type", document: `{"minIdleDays": "30"}`, wantErr: "invalid policy document"},
+		{name: "negative idle days", document: `{"minIdleDays": -1}`, wantErr: "minIdleDays must not be negative"},
+		{name: "empty ignore entry", document: `{"ignore": ["i-0123", " "]}`, wantErr: "ignore entries must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse([]byte(tt.document))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || p == nil {
+				t.Errorf("Parse() = %v, %v", p, err)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	minIdleDays, onlyIdle := 30, true
+	p := &Policy{
+		Regions:     []string{"us-east-1", "eu-west-1"},
+		Services:    []string{"ec2", "ebs"},
+		MinIdleDays: &minIdleDays,
+		OnlyIdle:    &onlyIdle,
+		Ignore:      []string{"us-east-1/i-0123"},
+	}
+	local := Settings{Regions: []string{"ap-northeast-2"}, MinIdleDays: 7}
+
+	tests := []struct {
+		name string
+		set  map[string]bool // Keys set by their flag
+		want Settings
+	}{
+		{
+			name: "policy over the config file and defaults",
+			want: Settings{Regions: []string{"us-east-1", "eu-west-1"}, Services: []string{"ec2", "ebs"}, MinIdleDays: 30, OnlyIdle: true, Ignore: []string{"us-east-1/i-0123"}},
+		},
+		{
+			name: "flags over the policy",
+			set:  map[string]bool{"regions": true, "minIdleDays": true},
+			want: Settings{Regions: []string{"ap-northeast-2"}, Services: []string{"ec2", "ebs"}, MinIdleDays: 7, OnlyIdle: true, Ignore: []string{"us-east-1/i-0123"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := local
+			p.Apply(&got, func(key string) bool { return tt.set[key] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unset policy fields keep the local settings", func(t *testing.T) {
+		got := local
+		(&Policy{}).Apply(&got, func(string) bool { return false })
+		if !reflect.DeepEqual(got, local) {
+			t.Errorf("Apply() = %+v, want %+v", got, local)
+		}
+	})
+}
+
+func TestParseAppConfigIdentifier(t *testing.T) {
+	application, environment, profile, err := ParseAppConfigIdentifier("idled/prod/policy")
+	if err != nil || application != "idled" || environment != "prod" || profile != "policy" {
+		t.Errorf("ParseAppConfigIdentifier() = %s, %s, %s, %v", application, environment, profile, err)
+	}
+	for _, identifier := range []string{"idled/prod", "idled//policy", "a/b/c/d", ""} {
+		if _, _, _, err := ParseAppConfigIdentifier(identifier); err == nil {
+			t.Errorf("ParseAppConfigIdentifier(%q) succeeded", identifier)
+		}
+	}
+}
+
+func TestSourceString(t *testing.T) {
+	if got := (Source{Kind: "SSM", Location: "/org/idled/policy", Version: "3"}).String(); got != "SSM /org/idled/policy (version 3)" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (Source{Kind: "AppConfig", Location: "idled/prod/policy"}).String(); got != "AppConfig idled/prod/policy" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package progress
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Func receives progress updates from a scanner: done out of total items, and a short status
+// message. A total of 0 reports a message without a count. Scanners never own a display.
+type Func func(done, total int, msg string)
+
+// Report calls f, or does nothing if f is nil, so scanners can report without checking
+func (f Func) Report(done, total int, msg string) {
+	if f != nil {
+		f(done, total, msg)
+	}
+}
+
+// count is the latest progress of one task
+type count struct {
+	done  int
+	total int
+}
+
+// Tracker aggregates the progress of concurrent tasks, such as one scanner per region, into
+// a single status line and passes it to the update function on every report
+type Tracker struct {
+	mu      sync.Mutex
+	counts  map[string]count
+	message string
+	update  func(status string)
+}
+
+// NewTracker creates a Tracker calling update with the aggregated status on every report.
+// update may be nil to only aggregate.
+func NewTracker(update func(status string)) *Tracker {
+	return &Tracker{counts: make(map[string]count), update: update}
+}
+
+// Func returns the progress function of a task. Reports of the same task replace each other.
+func (t *Tracker) Func(task string) Func {
+	return func(done, total int, msg string) {
+		t.mu.Lock()
+		if total > 0 {
+			t.counts[task] = count{done: done, total: total}
+		}
+		if msg != "" {
+			t.message = msg
+		}
+		status := t.statusLocked()
+		t.mu.Unlock()
+
+		if t.update != nil {
+			t.update(status)
+		}
+	}
+}
+
+// Status returns the aggregated status: items done out of the total of all tasks with the
+// percentage, followed by the latest message. It is empty before the first report.
+func (t *Tracker) Status() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.statusLocked()
+}
+
+func (t *Tracker) sumLocked() (done, total int) {
+	for _, c := range t.counts {
+		done += min(c.done, c.total)
+		total += c.total
+	}
+	return done, total
+}
+
+func (t *Tracker) statusLocked() string {
+	done, total := t.sumLocked()
+	switch {
+	case total > 0 && t.message != "":
+		return fmt.Sprintf("%d/%d (%d%%) - %s", done, total, done*100/total, t.message)
+	case total > 0:
+		return fmt.Sprintf("%d/%d (%d%%)", done, total, done*100/total)
+	default:
+		return t.message
+	}
+}
@@ -0,0 +1,94 @@
+package progress
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestFuncReportNil(t *testing.T) {
+	var f Func
+	// A nil function ignores reports instead of panicking
+	f.Report(1, 2, "ignored")
+}
+
+func TestTrackerConcurrentReports(t *testing.T) {
+	const tasks, items = 8, 50
+	var mu sync.Mutex
+	updates := 0
+	tracker := NewTracker(func(status string) {
+		mu.Lock()
+		updates++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for task := 0; task < tasks; task++ {
+		report := tracker.Func(fmt.Sprintf("region-%d", task))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Report(0, items, "Checking")
+			for done := 1; done <= items; done++ {
+				report.Report(done, items, "")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := tracker.Status(), "400/400 (100%) - Checking"; got != want {
+		t.Errorf("Status() = %q, want %q", got, want)
+	}
+	if want := tasks * (items + 1); updates != want {
+		t.Errorf("update called %d times, want %d", updates, want)
+	}
+}
+
+func TestTrackerStatus(t *testing.T) {
+	tracker := NewTracker(nil)
+	if got := tracker.Status(); got != "" {
+		t.Errorf("Status() before any report = %q, want empty", got)
+	}
+
+	ec2 := tracker.Func("us-east-1")
+	logs := tracker.Func("eu-west-1")
+
+	ec2.Report(0, 0, "Listing instances")
+	if got, want := tracker.Status(), "Listing instances"; got != want {
+		t.Errorf("Status() without a count = %q, want %q", got, want)
+	}
+
+	ec2.Report(3, 10, "")
+	logs.Report(1, 10, "")
+	if got, want := tracker.Status(), "4/20 (20%) - Listing instances"; got != want {
+		t.Errorf("Status() = %q, want %q", got, want)
+	}
+
+	// A later report of a task replaces its count, and done is capped at the total
+	ec2.Report(12, 10, "Checking log groups")
+	if got, want := tracker.Status(), "11/20 (55%) - Checking log groups"; got != want {
+		t.Errorf("Status() after replacing a count = %q, want %q", got, want)
+	}
+}
+
+func TestRegionsStatus(t *testing.T) {
+	regions := NewRegions([]string{"us-east-1", "us-west-2", "eu-west-1", "eu-central-1", "ap-northeast-2"})
+	if got, want := regions.Status(), "0/5 regions done, currently us-east-1, us-west-2, eu-west-1, ... (+2)"; got != want {
+		t.Errorf("Status() = %q, want %q", got, want)
+	}
+
+	regions.Complete("us-west-2")
+	regions.Complete("us-west-2")
+	regions.Complete("unknown-1")
+	regions.Complete("eu-west-1")
+	if got, want := regions.Status(), "2/5 regions done, currently us-east-1, eu-central-1, ap-northeast-2"; got != want {
+		t.Errorf("Status() = %q, want %q", got, want)
+	}
+
+	for _, region := range regions.Pending() {
+		regions.Complete(region)
+	}
+	if got, want := regions.Status(), "5/5 regions done"; got != want {
+		t.Errorf("Status() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package progress
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxPendingShown is the number of pending regions listed in the status before shortening
+const maxPendingShown = 3
+
+// Completion reports the end of the scan of one region
+type Completion struct {
+	Region   string
+	Items    int           // Resources found before filtering
+	Duration time.Duration // Time spent scanning the region
+	Err      error
+}
+
+// Regions tracks the regions of a scan that are done. It is not safe for concurrent use: the
+// goroutine consuming the completion channel owns it.
+type Regions struct {
+	order []string
+	done  map[string]bool
+}
+
+// NewRegions creates a Regions with every region pending
+func NewRegions(regions []string) *Regions {
+	return &Regions{order: regions, done: make(map[string]bool, len(regions))}
+}
+
+// Complete marks a region as done. Completing a region twice or an unknown region counts once
+// or not at all.
+func (r *Regions) Complete(region string) {
+	for _, known := range r.order {
+		if known == region {
+			r.done[region] = true
+			return
+		}
+	}
+}
+
+// Pending returns the regions not done yet, in the requested order
+func (r *Regions) Pending() []string {
+	var pending []string
+	for _, region := range r.order {
+		if !r.done[region] {
+			pending = append(pending, region)
+		}
+	}
+	return pending
+}
+
+// Status returns the regions done out of the total followed by the pending regions, such as
+// "9/15 regions done, currently us-west-2, eu-central-1"
+func (r *Regions) Status() string {
+	status := fmt.Sprintf("%d/%d regions done", len(r.done), len(r.order))
+	pending := r.Pending()
+	switch {
+	case len(pending) == 0:
+		return status
+	case len(pending) > maxPendingShown:
+		return fmt.Sprintf("%s, currently %s, ... (+%d)", status,
+			strings.Join(pending[:maxPendingShown], ", "), len(pending)-maxPendingShown)
+	default:
+		return fmt.Sprintf("%s, currently %s", status, strings.Join(pending, ", "))
+	}
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// columnGap splits a tabwriter-aligned summary line into its columns
+var columnGap = regexp.MustCompile(`\s{2,}`)
+
+// markdownEscaper escapes the characters that break a GitHub-flavored Markdown table cell
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+// WriteMarkdown renders the report as GitHub-flavored Markdown: an H2 and a table per
+// service, followed by its summary as a bullet list, the estimated monthly savings, and the
+// AWS API calls made by the scan
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if r.Account != "" {
+		fmt.Fprintf(bw, "**Account:** %s\n\n", r.Account)
+	}
+	if r.Partial {
+		fmt.Fprintln(bw, "> ⚠️ The scan was interrupted or timed out. The results below are partial.")
+		fmt.Fprintln(bw)
+	}
+
+	for _, section := range r.Sections {
+		fmt.Fprintf(bw, "## %s\n\n", section.Service)
+		if section.Empty() {
+			fmt.Fprint(bw, "_Nothing found._\n\n")
+		}
+		multiple := len(section.Tables) > 1
+		for _, table := range section.Tables {
+			if len(table.Rows) == 0 {
+				continue
+			}
+			if multiple {
+				fmt.Fprintf(bw, "### %s\n\n", table.Title)
+			}
+			writeMarkdownTable(bw, table)
+			fmt.Fprintln(bw)
+		}
+		if bullets := summaryBullets(section.Summary); len(bullets) > 0 {
+			for _, bullet := range bullets {
+				fmt.Fprintln(bw, bullet)
+			}
+			fmt.Fprintln(bw)
+		}
+	}
+
+	if len(r.Savings) > 0 {
+		fmt.Fprint(bw, "## Estimated Monthly Savings\n\n")
+		fmt.Fprintln(bw, "| Service | Idle | Cost/Mo | No Pricing |")
+		fmt.Fprintln(bw, "| --- | ---: | ---: | ---: |")
+		for _, row := range append(r.Savings, r.Total) {
+			fmt.Fprintf(bw, "| %s | %d | $%.2f | %d |\n", row.Service, row.Count, row.MonthlyCost, row.Unpriced)
+		}
+		fmt.Fprintln(bw)
+	}
+
+	if len(r.APICalls) > 0 {
+		fmt.Fprint(bw, "## AWS API Calls\n\n")
+		fmt.Fprintln(bw, "| AWS Service | Operation | Region | Calls | Throttled | Failed |")
+		fmt.Fprintln(bw, "| --- | --- | --- | ---: | ---: | ---: |")
+		for _, stat := range r.APICalls {
+			fmt.Fprintf(bw, "| %s | %s | %s | %d | %d | %d |\n",
+				stat.Service, stat.Operation, stat.Region, stat.Calls, stat.Throttles, stat.Errors)
+		}
+		fmt.Fprintln(bw)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write Markdown report: %w", err)
+	}
+	return nil
+}
+
+// writeMarkdownTable writes a pipe-delimited table with a header separator row.
+// Numeric columns are right-aligned and long values are written in full.
+func writeMarkdownTable(w io.Writer, table Table) {
+	separators := make([]string, len(table.Columns))
+	for i := range table.Columns {
+		separators[i] = "---"
+		if len(table.Rows) > 0 && table.Rows[0][i].Numeric {
+			separators[i] = "---:"
+		}
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(table.Columns, " | "))
+	fmt.Fprintf(w, "| %s |\n", strings.Join(separators, " | "))
+
+	for _, row := range table.Rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			value := cell.Text
+			if cell.Full != "" {
+				value = cell.Full
+			}
+			value = markdownEscaper.Replace(value)
+			if cell.URL != "" {
+				value = fmt.Sprintf("[%s](%s)", value, cell.URL)
+			}
+			values[i] = value
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(values, " | "))
+	}
+}
+
+// summaryBullets converts a plain text summary into Markdown bullets. Section titles are
+// bolded, aligned columns are joined with ": " and ", ", and all-caps header rows are dropped.
+func summaryBullets(summary string) []string {
+	var bullets []string
+	for _, line := range strings.Split(summary, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if title, ok := strings.CutPrefix(line, "#"); ok {
+			bullets = append(bullets, "**"+strings.TrimSpace(strings.TrimLeft(title, "#"))+"**")
+			continue
+		}
+		columns := columnGap.Split(line, -1)
+		if len(columns) > 1 && !strings.ContainsFunc(line, unicode.IsLower) {
+			continue
+		}
+		text := columns[0]
+		if len(columns) > 1 {
+			text += ": " + strings.Join(columns[1:], ", ")
+		}
+		bullets = append(bullets, "- "+text)
+	}
+	return bullets
+}
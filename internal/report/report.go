@@ -0,0 +1,311 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// maxCellLength truncates long cell values such as ARNs, the full value is shown as a tooltip
+const maxCellLength = 60
+
+//go:embed report.html.tmpl
+var reportTemplate string
+
+var tmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+}).Parse(reportTemplate))
+
+// Report collects the results of every scanned service for rendering as a single HTML page or
+// Markdown document. Both renderers share the tables built from the resource models.
+type Report struct {
+	GeneratedAt time.Time
+	Account     string        // AWS account ID, empty if unknown
+	Regions     []string      // Scanned regions
+	Duration    time.Duration // Total scan time
+	Partial     bool          // Whether the scan was interrupted or timed out
+	Sections    []Section     // One section per service, in scan order
+	Savings     []SavingsRow  // Estimated monthly savings per service
+	Total       SavingsRow    // Estimated monthly savings of all services
+
+	// APICalls lists the AWS API calls made by the scan per service, operation, and region
+	APICalls []models.APICallStat
+
+	// Link returns the console URL of a resource, empty if it has none. Tables of resources
+	// with a console URL get a Console URL column. nil adds no links.
+	Link func(resource any) string
+}
+
+// Section holds the results of one service
+type Section struct {
+	Service string
+	Tables  []Table
+	Summary string // Plain text summary and age breakdown
+}
+
+// Empty reports whether the service has no resources to show
+func (s Section) Empty() bool {
+	for _, table := range s.Tables {
+		if len(table.Rows) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Table is a sortable table of resources of one type
+type Table struct {
+	Title   string
+	Columns []string
+	Rows    [][]Cell
+}
+
+// Cell is a rendered table value
+type Cell struct {
+	Text    string // Displayed value, truncated if long
+	Full    string // Untruncated value shown as a tooltip, empty if not truncated
+	SortKey string // Value used for sorting, empty to sort by Text
+	Numeric bool   // Whether the value is a number, aligned right
+	URL     string // Link target, the cell is rendered as a link if set
+}
+
+// SavingsRow is the estimated monthly cost of the idle resources of a service
+type SavingsRow struct {
+	Service     string
+	Count       int
+	MonthlyCost float64
+	Unpriced    int
+}
+
+// New creates an empty report
+func New() *Report {
+	return &Report{GeneratedAt: time.Now()}
+}
+
+// Add adds the resources and summary of a service. Resources of different types are shown
+// as separate tables, and repeated calls for the same service extend its section.
+func (r *Report) Add(service string, resources []any, summary string) {
+	i := 0
+	for i < len(r.Sections) && r.Sections[i].Service != service {
+		i++
+	}
+	if i == len(r.Sections) {
+		r.Sections = append(r.Sections, Section{Service: service})
+	}
+
+	section := &r.Sections[i]
+	section.Tables = append(section.Tables, newTables(resources, r.Link)...)
+	if summary = strings.TrimSpace(summary); summary != "" {
+		if section.Summary != "" {
+			section.Summary += "\n\n"
+		}
+		section.Summary += summary
+	}
+}
+
+// SetSavings sets the savings totals from the per-region cost summaries of all services.
+// Services without cost estimates are excluded.
+func (r *Report) SetSavings(summaries []models.CostSummary) {
+	index := make(map[string]int)
+	r.Savings = nil
+	r.Total = SavingsRow{Service: "Total"}
+	for _, summary := range summaries {
+		if !summary.Estimated || summary.Count == 0 {
+			continue
+		}
+		i, ok := index[summary.Service]
+		if !ok {
+			i = len(r.Savings)
+			index[summary.Service] = i
+			r.Savings = append(r.Savings, SavingsRow{Service: summary.Service})
+		}
+		r.Savings[i].Count += summary.Count
+		r.Savings[i].MonthlyCost += summary.MonthlyCost
+		r.Savings[i].Unpriced += summary.Unpriced
+		r.Total.Count += summary.Count
+		r.Total.MonthlyCost += summary.MonthlyCost
+		r.Total.Unpriced += summary.Unpriced
+	}
+	sort.SliceStable(r.Savings, func(i, j int) bool {
+		return r.Savings[i].MonthlyCost > r.Savings[j].MonthlyCost
+	})
+}
+
+// WriteHTML renders the report as a self-contained HTML page
+func (r *Report) WriteHTML(w io.Writer) error {
+	if err := tmpl.Execute(w, r); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
+
+// newTables builds a table for each run of resources of the same type, with a Console URL
+// column if link returns a URL for any of its resources
+func newTables(resources []any, link func(any) string) []Table {
+	var tables []Table
+	var links [][]string // Console URLs of the rows of each table
+	var current reflect.Type
+	for _, resource := range resources {
+		value := reflect.Indirect(reflect.ValueOf(resource))
+		if value.Kind() != reflect.Struct {
+			continue
+		}
+		if value.Type() != current {
+			current = value.Type()
+			tables = append(tables, newTable(current))
+			links = append(links, nil)
+		}
+		table := &tables[len(tables)-1]
+		table.Rows = append(table.Rows, newRow(value))
+		url := ""
+		if link != nil {
+			url = link(resource)
+		}
+		links[len(links)-1] = append(links[len(links)-1], url)
+	}
+	for i := range tables {
+		addLinkColumn(&tables[i], links[i])
+	}
+	return tables
+}
+
+// addLinkColumn appends a Console URL column to the table, unless none of its rows has a URL
+func addLinkColumn(table *Table, urls []string) {
+	if !slices.ContainsFunc(urls, func(url string) bool { return url != "" }) {
+		return
+	}
+	table.Columns = append(table.Columns, "Console URL")
+	for i, url := range urls {
+		cell := Cell{Text: "-"}
+		if url != "" {
+			cell = Cell{Text: "Open", URL: url}
+		}
+		table.Rows[i] = append(table.Rows[i], cell)
+	}
+}
+
+// newTable creates an empty table with a column per displayable field of the type
+func newTable(t reflect.Type) Table {
+	table := Table{Title: humanize(strings.TrimSuffix(t.Name(), "Info"))}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() && displayable(field.Type) {
+			table.Columns = append(table.Columns, humanize(field.Name))
+		}
+	}
+	return table
+}
+
+// newRow renders the displayable fields of a struct value in column order
+func newRow(value reflect.Value) []Cell {
+	var row []Cell
+	for i := 0; i < value.NumField(); i++ {
+		if field := value.Type().Field(i); field.IsExported() && displayable(field.Type) {
+			row = append(row, newCell(value.Field(i)))
+		}
+	}
+	return row
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// displayable reports whether values of the type can be shown in a single cell
+func displayable(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Struct:
+		return t == timeType
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// newCell formats a field value, keeping a sortable key for numbers and times
+func newCell(v reflect.Value) Cell {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return Cell{Text: "-"}
+		}
+		v = v.Elem()
+	}
+
+	var text, sortKey string
+	numeric := false
+	switch v.Kind() {
+	case reflect.Bool:
+		text = "No"
+		if v.Bool() {
+			text = "Yes"
+		}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		text = fmt.Sprintf("%d", v.Int())
+		sortKey, numeric = text, true
+	case reflect.Float32, reflect.Float64:
+		text = fmt.Sprintf("%.2f", v.Float())
+		sortKey, numeric = text, true
+	case reflect.Struct:
+		t := v.Interface().(time.Time) // Only time.Time is displayable
+		if t.IsZero() {
+			return Cell{Text: "-"}
+		}
+		text = t.Format("2006-01-02 15:04")
+		sortKey = t.UTC().Format(time.RFC3339)
+	case reflect.Slice:
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = v.Index(i).String()
+		}
+		text = strings.Join(values, ", ")
+	case reflect.Map:
+		var pairs []string
+		for iter := v.MapRange(); iter.Next(); {
+			pairs = append(pairs, iter.Key().String()+"="+iter.Value().String())
+		}
+		sort.Strings(pairs)
+		text = strings.Join(pairs, ", ")
+	default:
+		text = v.String()
+	}
+	if text == "" {
+		text = "-"
+	}
+
+	cell := Cell{Text: text, SortKey: sortKey, Numeric: numeric}
+	if runes := []rune(text); len(runes) > maxCellLength {
+		cell.Text = string(runes[:maxCellLength-1]) + "…"
+		cell.Full = text
+	}
+	return cell
+}
+
+// humanize splits a Go identifier into words, keeping acronyms together (e.g., InstanceID -> Instance ID)
+func humanize(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// VolumeInfo and AddressInfo stand in for the resource models, so the golden files only
+// change with the renderers
+type VolumeInfo struct {
+	VolumeID     string
+	Region       string
+	SizeGB       int32
+	Encrypted    bool
+	LastAttached *time.Time
+	CreatedAt    time.Time
+	MonthlyCost  float64
+	Description  string
+	Tags         map[string]string
+	Snapshots    []string
+	internal     string // Unexported, never shown
+}
+
+type AddressInfo struct {
+	AllocationID string
+	PublicIP     string
+	Region       string
+	MonthlyCost  float64
+}
+
+// checkGolden compares got with testdata/name, or rewrites the file with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s (run go test -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run go test -update if the change is intended\n--- got:\n%s", path, got)
+	}
+}
+
+// testReport returns a report covering every kind of cell, a service with several tables,
+// an empty service, the savings, and the API calls
+func testReport() *Report {
+	attached := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+	r := &Report{
+		GeneratedAt: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+		Account:     "123456789012",
+		Regions:     []string{"us-east-1", "eu-west-1"},
+		Duration:    83*time.Second + 456*time.Millisecond,
+		Partial:     true,
+		Link: func(resource any) string {
+			if volume, ok := resource.(VolumeInfo); ok && volume.VolumeID == "vol-0a1b2c3d" {
+				return "https://console.aws.amazon.com/ec2/home?region=us-east-1#VolumeDetails:volumeId=vol-0a1b2c3d"
+			}
+			return ""
+		},
+	}
+	r.Add("EBS", []any{
+		VolumeInfo{
+			VolumeID:     "vol-0a1b2c3d",
+			Region:       "us-east-1",
+			SizeGB:       100,
+			Encrypted:    true,
+			LastAttached: &attached,
+			CreatedAt:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			MonthlyCost:  8,
+			Description:  "Scratch volume | copied from the build host of the release pipeline before the migration",
+			Tags:         map[string]string{"Team": "platform", "Env": "dev"},
+			Snapshots:    []string{"snap-1", "snap-2"},
+		},
+		VolumeInfo{VolumeID: "vol-9f8e7d6c", Region: "eu-west-1", SizeGB: 8, MonthlyCost: 0.88},
+	}, "## Idle EBS Volumes by Region\nREGION     VOLUMES  COST/MO\nus-east-1  1        $8.00\neu-west-1  1        $0.88")
+	r.Add("Elastic IP", []any{
+		AddressInfo{AllocationID: "eipalloc-1", PublicIP: "198.51.100.1", Region: "us-east-1", MonthlyCost: 3.6},
+	}, "")
+	r.Add("Elastic IP", []any{
+		VolumeInfo{VolumeID: "vol-attached", Region: "us-east-1", SizeGB: 1},
+	}, "Total: 1 unattached <Elastic IP> & 1 volume")
+	r.Add("Lambda", nil, "")
+	r.SetSavings([]models.CostSummary{
+		{Service: "EBS", Region: "us-east-1", Count: 1, MonthlyCost: 8, Estimated: true},
+		{Service: "EBS", Region: "eu-west-1", Count: 1, MonthlyCost: 0.88, Estimated: true},
+		{Service: "Elastic IP", Region: "us-east-1", Count: 1, MonthlyCost: 3.6, Unpriced: 1, Estimated: true},
+		{Service: "Lambda", Region: "us-east-1", Count: 3},
+	})
+	r.APICalls = []models.APICallStat{
+		{Service: "EC2", Operation: "DescribeVolumes", Region: "us-east-1", Calls: 4, Throttles: 1},
+		{Service: "EC2", Operation: "DescribeAddresses", Region: "eu-west-1", Calls: 1, Errors: 1},
+	}
+	return r
+}
+
+func TestWriteHTMLGolden(t *testing.T) {
+	var output bytes.Buffer
+	if err := testReport().WriteHTML(&output); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	checkGolden(t, "report.html.golden", output.Bytes())
+}
+
+func TestWriteMarkdownGolden(t *testing.T) {
+	var output bytes.Buffer
+	if err := testReport().WriteMarkdown(&output); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	checkGolden(t, "report.md.golden", output.Bytes())
+}
+
+func TestWriteMarkdownGoldenEmpty(t *testing.T) {
+	r := &Report{GeneratedAt: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+	r.Add("EC2", nil, "")
+
+	var output bytes.Buffer
+	if err := r.WriteMarkdown(&output); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	checkGolden(t, "report-empty.md.golden", output.Bytes())
+}
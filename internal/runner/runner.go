@@ -0,0 +1,336 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// GlobalRegion is the region global services are reported under in results and summaries
+const GlobalRegion = "global"
+
+// PartitionAWS is the partition of the commercial AWS regions
+const PartitionAWS = "aws"
+
+var (
+	// ErrNoValidRegions is returned when none of the requested regions is valid
+	ErrNoValidRegions = errors.New("no valid regions specified")
+	// ErrNoSupportedServices is returned when none of the requested services is registered
+	ErrNoSupportedServices = errors.New("no supported services specified")
+)
+
+// ConfigLoader loads the AWS config that the API clients of a region are built from
+type ConfigLoader func(ctx context.Context, region string) (aws.Config, error)
+
+// Scope is what a processor scans and how
+type Scope struct {
+	// Regions to scan. Global services receive a single region, only used to configure their
+	// API clients: their home region if set, or the first valid region.
+	Regions       []string
+	Global        bool          // Whether the service is global, its results are reported under GlobalRegion
+	LoadConfig    ConfigLoader  // Loads the config the API clients of a region are built from
+	RegionTimeout time.Duration // Maximum time to scan one region, unlimited if zero
+}
+
+// Processor scans one service in the regions of the scope, prints its results, and returns
+// the idle resource counts and estimated monthly costs per region
+type Processor func(ctx context.Context, scope Scope) []models.CostSummary
+
+// ScanFunc gets the resources of a service with API clients built from cfg, loaded for one region
+type ScanFunc[T any] func(ctx context.Context, cfg aws.Config) ([]T, error)
+
+// RegionResult holds what a service scan got in one region
+type RegionResult[T any] struct {
+	Region   string        // Region scanned, GlobalRegion for global services
+	Data     []T           // Resources found, including those found before an error or timeout
+	Err      error         // Error of the scan, nil if it succeeded
+	Duration time.Duration // Time spent scanning the region
+	TimedOut bool          // Whether the region reached the region timeout
+}
+
+// ScanRegions loads the config of every region of the scope and scans the regions concurrently,
+// each within the region timeout. A region that times out keeps the resources scanned so far,
+// and its error is replaced by the timeout. done, if not nil, is called concurrently as each
+// region finishes. The results keep the order of the regions.
+func ScanRegions[T any](ctx context.Context, scope Scope, scan ScanFunc[T], done func(RegionResult[T])) []RegionResult[T] {
+	results := make([]RegionResult[T], len(scope.Regions))
+	var wg sync.WaitGroup
+	for i, region := range scope.Regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			result := scanRegion(ctx, scope, region, scan)
+			result.Duration = time.Since(start)
+			if scope.Global {
+				result.Region = GlobalRegion
+			}
+			results[i] = result
+			if done != nil {
+				done(result)
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// scanRegion scans one region within the region timeout of the scope
+func scanRegion[T any](ctx context.Context, scope Scope, region string, scan ScanFunc[T]) RegionResult[T] {
+	result := RegionResult[T]{Region: region}
+	regionCtx := ctx
+	if scope.RegionTimeout > 0 {
+		var cancel context.CancelFunc
+		regionCtx, cancel = context.WithTimeout(ctx, scope.RegionTimeout)
+		defer cancel()
+	}
+
+	cfg, err := scope.LoadConfig(regionCtx, region)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
+		return result
+	}
+	result.Data, result.Err = scan(regionCtx, cfg)
+	// Only the region deadline counts, a cancelled or timed out scan is reported once for all regions
+	if ctx.Err() == nil && errors.Is(regionCtx.Err(), context.DeadlineExceeded) {
+		result.TimedOut = true
+		result.Err = fmt.Errorf("scan timed out after %s, partial results kept", scope.RegionTimeout)
+	}
+	return result
+}
+
+// Service describes a scannable AWS service and the processor that handles it
+type Service struct {
+	Name        string            // Service name used with --services (e.g., ec2)
+	Description string            // One-line description shown by --list-services
+	Taggable    bool              // Whether the service supports --tag filtering
+	Nameable    bool              // Whether the service supports --include/--exclude name filtering
+	Global      bool              // Whether the service is global and scanned once instead of per region
+	HomeRegions map[string]string // Region a global service's API must be called in per partition (e.g., us-east-1 for CloudFront metrics in aws), the first valid region if the partition has none
+	Process     Processor         // Scans the service and prints the results
+}
+
+// Result holds the outcome of scanning one service
+type Result struct {
+	Service   string               // Service name used with --services
+	Duration  time.Duration        // Time taken to scan the service in all regions
+	Summaries []models.CostSummary // Idle resource counts and costs per region
+}
+
+// Options holds the scan settings resolved from flags and the policy
+type Options struct {
+	Regions        []string                                      // Regions to scan, DefaultRegion if empty
+	Services       []string                                      // Services to scan, DefaultService if empty
+	DefaultRegion  string                                        // Region used when none is requested
+	DefaultService string                                        // Service used when none is requested
+	TagFiltered    bool                                          // Whether --tag filters are set
+	NameFiltered   bool                                          // Whether --include/--exclude patterns are set
+	IsValidRegion  func(string) bool                             // Region validator, every region is accepted if nil
+	KnownRegions   []string                                      // Valid regions suggested for mistyped ones
+	Suggest        func(name string, candidates []string) string // Closest candidate to a mistyped name, no suggestions if nil
+	Partition      func(region string) string                    // Partition of a region (e.g., aws-cn), the home regions of the aws partition are used if nil
+	LoadConfig     ConfigLoader                                  // Loads the config the API clients of a region are built from
+	RegionTimeout  time.Duration                                 // Maximum time to scan one region, unlimited if zero
+	Out            io.Writer                                     // Destination for warnings and notes
+}
+
+// Runner validates the requested regions and services and dispatches each service to its processor
+type Runner struct {
+	opts     Options
+	services map[string]Service
+
+	validated    bool      // Whether Validate has run
+	active       []Service // Services to scan, set by Validate
+	validRegions []string  // Regions to scan, set by Validate
+}
+
+// New creates a Runner for the given options and service registry
+func New(opts Options, services []Service) *Runner {
+	registry := make(map[string]Service, len(services))
+	for _, service := range services {
+		registry[service.Name] = service
+	}
+	return &Runner{opts: opts, services: registry}
+}
+
+// Regions returns the valid regions to scan in the requested order, warning about invalid
+// and repeated ones. The default region is used when no region is requested.
+func (r *Runner) Regions() []string {
+	regions := r.opts.Regions
+	if len(regions) == 0 {
+		regions = []string{r.opts.DefaultRegion}
+	}
+
+	var validRegions []string
+	seen := make(map[string]bool, len(regions))
+	for _, region := range regions {
+		if seen[region] {
+			fmt.Fprintf(r.opts.Out, "Warning: Ignoring duplicate region '%s'\n", region)
+			continue
+		}
+		seen[region] = true
+		if r.opts.IsValidRegion == nil || r.opts.IsValidRegion(region) {
+			validRegions = append(validRegions, region)
+		} else {
+			fmt.Fprintf(r.opts.Out, "Warning: Skipping invalid region '%s'%s\n", region, r.didYouMean(region, r.opts.KnownRegions))
+		}
+	}
+	return validRegions
+}
+
+// Services returns the registered services to scan in the requested order, warning about
+// unknown and repeated ones. The default service is used when no service is requested.
+func (r *Runner) Services() []Service {
+	names := r.opts.Services
+	if len(names) == 0 {
+		names = []string{r.opts.DefaultService}
+	}
+
+	var active []Service
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			fmt.Fprintf(r.opts.Out, "Warning: Ignoring duplicate service '%s'\n", name)
+			continue
+		}
+		seen[name] = true
+		service, exists := r.services[name]
+		if !exists {
+			fmt.Fprintf(r.opts.Out, "Warning: Unknown service '%s'%s\n", name, r.didYouMean(name, r.serviceNames()))
+			continue
+		}
+		active = append(active, service)
+	}
+	return active
+}
+
+// Validate resolves the services and regions to scan, warning about unknown services and
+// invalid regions with a suggestion for likely typos, and dropping repeated ones. It fails if no service remains, or if a
+// regional service has no valid region. Call it before any slow setup so mistakes are reported
+// right away. Run validates on its first call if Validate was not called.
+func (r *Runner) Validate() error {
+	r.validated = true
+	r.active = r.Services()
+	r.validRegions = r.Regions()
+
+	if len(r.active) == 0 {
+		return ErrNoSupportedServices
+	}
+	for _, service := range r.active {
+		if !service.Global && len(r.validRegions) == 0 {
+			return ErrNoValidRegions
+		}
+	}
+	return nil
+}
+
+// ActiveServices returns the services Validate resolved, without repeating its warnings
+func (r *Runner) ActiveServices() []Service {
+	return r.active
+}
+
+// ValidRegions returns the valid, deduplicated regions Validate resolved, without repeating
+// its warnings. It is empty if only global services were requested with invalid regions.
+func (r *Runner) ValidRegions() []string {
+	return r.validRegions
+}
+
+// Run scans every requested regional service in every valid region, and every requested
+// global service once, and returns a result per scanned service. Valid regions are only
+// required for regional services. Services that have not started when ctx is cancelled are skipped.
+func (r *Runner) Run(ctx context.Context) ([]Result, error) {
+	if !r.validated {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	services, regions := r.active, r.validRegions
+
+	// Global services use the first valid region, or their home region in its partition, to
+	// stay in the partition of the scanned regions
+	globalRegion := r.opts.DefaultRegion
+	if len(regions) > 0 {
+		globalRegion = regions[0]
+	}
+	partition := PartitionAWS
+	if r.opts.Partition != nil {
+		partition = r.opts.Partition(globalRegion)
+	}
+
+	var results []Result
+	for _, service := range services {
+		if ctx.Err() != nil {
+			break
+		}
+		if r.opts.TagFiltered && !service.Taggable {
+			fmt.Fprintf(r.opts.Out, "Note: Tag filtering does not apply to '%s'; showing all resources.\n", service.Name)
+		}
+		if r.opts.NameFiltered && !service.Nameable {
+			fmt.Fprintf(r.opts.Out, "Note: Name filtering does not apply to '%s'; showing all resources.\n", service.Name)
+		}
+		scope := Scope{Regions: regions, Global: service.Global, LoadConfig: r.opts.LoadConfig, RegionTimeout: r.opts.RegionTimeout}
+		if service.Global {
+			scope.Regions = []string{globalRegion}
+			if home := service.HomeRegions[partition]; home != "" {
+				scope.Regions = []string{home}
+			}
+		}
+		start := time.Now()
+		summaries := service.Process(ctx, scope)
+		results = append(results, Result{Service: service.Name, Duration: time.Since(start), Summaries: summaries})
+	}
+	return results, nil
+}
+
+// serviceNames returns the names of the registered services in alphabetical order
+func (r *Runner) serviceNames() []string {
+	names := make([]string, 0, len(r.services))
+	for name := range r.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// didYouMean returns a suggestion for a mistyped name, or "" if no candidate is close enough
+func (r *Runner) didYouMean(name string, candidates []string) string {
+	if r.opts.Suggest == nil {
+		return ""
+	}
+	if suggestion := r.opts.Suggest(name, candidates); suggestion != "" {
+		return fmt.Sprintf(", did you mean '%s'?", suggestion)
+	}
+	return ""
+}
+
+// PrintServiceList writes the registered services with their descriptions and an example usage
+func (r *Runner) PrintServiceList(program string) {
+	names := r.serviceNames()
+
+	fmt.Fprintln(r.opts.Out, "Available services:")
+	for _, name := range names {
+		description := r.services[name].Description
+		if description == "" {
+			description = "No description available"
+		}
+		if name == r.opts.DefaultService {
+			fmt.Fprintf(r.opts.Out, "  %-8s - %s (default)\n", name, description)
+		} else {
+			fmt.Fprintf(r.opts.Out, "  %-8s - %s\n", name, description)
+		}
+	}
+
+	fmt.Fprintln(r.opts.Out, "\nExample usage:")
+	if len(names) > 0 {
+		fmt.Fprintf(r.opts.Out, "  %s scan %s\n", program, names[0])
+	}
+	fmt.Fprintf(r.opts.Out, "  %s scan all\n", program)
+}
@@ -0,0 +1,540 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// knownRegions are the regions accepted by the test runners
+var knownRegions = []string{"us-east-1", "us-west-2", "eu-west-1", "cn-north-1", "cn-northwest-1", "us-gov-west-1"}
+
+// testPartition returns the partition of a region from its prefix
+func testPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	default:
+		return PartitionAWS
+	}
+}
+
+// suggestPrefix suggests the first candidate sharing the first two characters of name
+func suggestPrefix(name string, candidates []string) string {
+	for _, candidate := range candidates {
+		if len(name) >= 2 && strings.HasPrefix(candidate, name[:2]) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// fakeLoadConfig loads a config holding only the region
+func fakeLoadConfig(ctx context.Context, region string) (aws.Config, error) {
+	return aws.Config{Region: region}, nil
+}
+
+// recorder records the scopes each service was processed with
+type recorder struct {
+	calls map[string]Scope
+	order []string
+}
+
+func (r *recorder) service(name string, global bool, homeRegions map[string]string) Service {
+	return Service{
+		Name:        name,
+		Global:      global,
+		HomeRegions: homeRegions,
+		Process: func(ctx context.Context, scope Scope) []models.CostSummary {
+			r.calls[name] = scope
+			r.order = append(r.order, name)
+			var summaries []models.CostSummary
+			for _, region := range scope.Regions {
+				summaries = append(summaries, models.CostSummary{Service: name, Region: region, Count: 1})
+			}
+			return summaries
+		},
+	}
+}
+
+// newTestRunner creates a runner with regional services ec2 and ebs and global services iam
+// and cloudfront, the latter with home regions in the aws and aws-cn partitions
+func newTestRunner(opts Options) (*Runner, *recorder, *bytes.Buffer) {
+	rec := &recorder{calls: make(map[string]Scope)}
+	var output bytes.Buffer
+	opts.Out = &output
+	if opts.DefaultRegion == "" {
+		opts.DefaultRegion = "us-east-1"
+	}
+	if opts.DefaultService == "" {
+		opts.DefaultService = "ec2"
+	}
+	opts.IsValidRegion = func(region string) bool { return slices.Contains(knownRegions, region) }
+	opts.KnownRegions = knownRegions
+	opts.Suggest = suggestPrefix
+	opts.Partition = testPartition
+	opts.LoadConfig = fakeLoadConfig
+	return New(opts, []Service{
+		rec.service("ec2", false, nil),
+		rec.service("ebs", false, nil),
+		rec.service("iam", true, nil),
+		rec.service("cloudfront", true, map[string]string{PartitionAWS: "us-east-1", "aws-cn": "cn-northwest-1"}),
+	}), rec, &output
+}
+
+func TestRegions(t *testing.T) {
+	tests := []struct {
+		name     string
+		regions  []string
+		want     []string
+		warnings []string
+	}{
+		{
+			name: "default region",
+			want: []string{"us-east-1"},
+		},
+		{
+			name:    "requested order kept",
+			regions: []string{"us-west-2", "us-east-1"},
+			want:    []string{"us-west-2", "us-east-1"},
+		},
+		{
+			name:     "duplicates dropped",
+			regions:  []string{"us-west-2", "us-east-1", "us-west-2"},
+			want:     []string{"us-west-2", "us-east-1"},
+			warnings: []string{"Warning: Ignoring duplicate region 'us-west-2'"},
+		},
+		{
+			name:     "invalid region with a suggestion",
+			regions:  []string{"us-east-9", "eu-west-1"},
+			want:     []string{"eu-west-1"},
+			warnings: []string{"Warning: Skipping invalid region 'us-east-9', did you mean 'us-east-1'?"},
+		},
+		{
+			name:     "invalid region without a suggestion",
+			regions:  []string{"mars-1"},
+			warnings: []string{"Warning: Skipping invalid region 'mars-1'\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _, output := newTestRunner(Options{Regions: tt.regions})
+
+			if got := r.Regions(); !slices.Equal(got, tt.want) {
+				t.Errorf("Regions() = %v, want %v", got, tt.want)
+			}
+			for _, warning := range tt.warnings {
+				if !strings.Contains(output.String(), warning) {
+					t.Errorf("output %q does not contain %q", output.String(), warning)
+				}
+			}
+			if len(tt.warnings) == 0 && output.Len() > 0 {
+				t.Errorf("unexpected output %q", output.String())
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		regions      []string
+		services     []string
+		wantErr      error
+		wantServices []string
+		wantOutput   string
+	}{
+		{
+			name:         "default service",
+			wantServices: []string{"ec2"},
+		},
+		{
+			name:         "duplicate service dropped",
+			services:     []string{"ebs", "ec2", "ebs"},
+			wantServices: []string{"ebs", "ec2"},
+			wantOutput:   "Warning: Ignoring duplicate service 'ebs'",
+		},
+		{
+			name:         "unknown service with a suggestion",
+			services:     []string{"ec3", "ebs"},
+			wantServices: []string{"ebs"},
+			wantOutput:   "Warning: Unknown service 'ec3', did you mean 'ec2'?",
+		},
+		{
+			name:       "no known service",
+			services:   []string{"rds"},
+			wantErr:    ErrNoSupportedServices,
+			wantOutput: "Warning: Unknown service 'rds'",
+		},
+		{
+			name:     "regional service without a valid region",
+			regions:  []string{"mars-1"},
+			services: []string{"iam", "ec2"},
+			wantErr:  ErrNoValidRegions,
+		},
+		{
+			name:         "global service without a valid region",
+			regions:      []string{"mars-1"},
+			services:     []string{"iam"},
+			wantServices: []string{"iam"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _, output := newTestRunner(Options{Regions: tt.regions, Services: tt.services})
+
+			err := r.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(output.String(), tt.wantOutput) {
+				t.Errorf("output %q does not contain %q", output.String(), tt.wantOutput)
+			}
+			if err != nil {
+				return
+			}
+			var names []string
+			for _, service := range r.ActiveServices() {
+				names = append(names, service.Name)
+			}
+			if !slices.Equal(names, tt.wantServices) {
+				t.Errorf("ActiveServices() = %v, want %v", names, tt.wantServices)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	r, rec, output := newTestRunner(Options{
+		Regions:       []string{"eu-west-1", "us-west-2"},
+		Services:      []string{"ebs", "iam", "cloudfront", "ec2"},
+		TagFiltered:   true,
+		RegionTimeout: time.Minute,
+	})
+
+	results, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if want := []string{"ebs", "iam", "cloudfront", "ec2"}; !slices.Equal(rec.order, want) {
+		t.Errorf("processed %v, want %v", rec.order, want)
+	}
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Service)
+	}
+	if !slices.Equal(names, rec.order) {
+		t.Errorf("results for %v, want %v", names, rec.order)
+	}
+
+	wantScopes := map[string]Scope{
+		"ebs":        {Regions: []string{"eu-west-1", "us-west-2"}},
+		"ec2":        {Regions: []string{"eu-west-1", "us-west-2"}},
+		"iam":        {Regions: []string{"eu-west-1"}, Global: true},
+		"cloudfront": {Regions: []string{"us-east-1"}, Global: true},
+	}
+	for name, want := range wantScopes {
+		got := rec.calls[name]
+		if !slices.Equal(got.Regions, want.Regions) || got.Global != want.Global {
+			t.Errorf("%s scope = %v (global %t), want %v (global %t)", name, got.Regions, got.Global, want.Regions, want.Global)
+		}
+		if got.LoadConfig == nil || got.RegionTimeout != time.Minute {
+			t.Errorf("%s scope does not carry the config loader and region timeout", name)
+		}
+	}
+	if got := len(results[0].Summaries); got != 2 {
+		t.Errorf("ebs has %d summaries, want one per region", got)
+	}
+	if !strings.Contains(output.String(), "Note: Tag filtering does not apply to 'iam'") {
+		t.Errorf("output %q does not note the tag filter", output.String())
+	}
+}
+
+func TestRunDeduplicatesInRequestedOrder(t *testing.T) {
+	r, rec, output := newTestRunner(Options{
+		Regions:  []string{"us-west-2", "eu-west-1", "us-west-2"},
+		Services: []string{"ebs", "ec2", "ebs"},
+	})
+
+	results, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if want := []string{"ebs", "ec2"}; !slices.Equal(rec.order, want) {
+		t.Errorf("processed %v, want %v", rec.order, want)
+	}
+	for _, name := range rec.order {
+		if got, want := rec.calls[name].Regions, []string{"us-west-2", "eu-west-1"}; !slices.Equal(got, want) {
+			t.Errorf("%s scanned %v, want %v", name, got, want)
+		}
+	}
+	var regions []string
+	for _, summary := range results[0].Summaries {
+		regions = append(regions, summary.Region)
+	}
+	if want := []string{"us-west-2", "eu-west-1"}; !slices.Equal(regions, want) {
+		t.Errorf("ebs summaries for %v, want %v", regions, want)
+	}
+	for _, warning := range []string{"Ignoring duplicate region 'us-west-2'", "Ignoring duplicate service 'ebs'"} {
+		if !strings.Contains(output.String(), warning) {
+			t.Errorf("output %q does not contain %q", output.String(), warning)
+		}
+	}
+}
+
+func TestRunGlobalHomeRegionByPartition(t *testing.T) {
+	tests := []struct {
+		name           string
+		regions        []string
+		wantIAM        string
+		wantCloudFront string
+	}{
+		{name: "aws", regions: []string{"eu-west-1", "us-west-2"}, wantIAM: "eu-west-1", wantCloudFront: "us-east-1"},
+		{name: "aws-cn", regions: []string{"cn-north-1"}, wantIAM: "cn-north-1", wantCloudFront: "cn-northwest-1"},
+		// Without a home region in the partition, the first region keeps the scan in the partition
+		{name: "aws-us-gov", regions: []string{"us-gov-west-1"}, wantIAM: "us-gov-west-1", wantCloudFront: "us-gov-west-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, rec, _ := newTestRunner(Options{Regions: tt.regions, Services: []string{"iam", "cloudfront"}})
+
+			if _, err := r.Run(context.Background()); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if got := rec.calls["iam"].Regions; !slices.Equal(got, []string{tt.wantIAM}) {
+				t.Errorf("iam regions = %v, want [%s]", got, tt.wantIAM)
+			}
+			if got := rec.calls["cloudfront"].Regions; !slices.Equal(got, []string{tt.wantCloudFront}) {
+				t.Errorf("cloudfront regions = %v, want [%s]", got, tt.wantCloudFront)
+			}
+		})
+	}
+}
+
+func TestRunWithoutPartition(t *testing.T) {
+	r, rec, _ := newTestRunner(Options{Regions: []string{"cn-north-1"}, Services: []string{"cloudfront"}})
+	r.opts.Partition = nil
+
+	if _, err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := rec.calls["cloudfront"].Regions; !slices.Equal(got, []string{"us-east-1"}) {
+		t.Errorf("cloudfront regions = %v, want the aws home region", got)
+	}
+}
+
+func TestRunValidatesOnce(t *testing.T) {
+	r, rec, output := newTestRunner(Options{Services: []string{"ec3", "ec2"}})
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	warnings := output.String()
+
+	if _, err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if output.String() != warnings {
+		t.Errorf("Run() repeated the warnings: %q", output.String())
+	}
+	if !slices.Equal(rec.order, []string{"ec2"}) {
+		t.Errorf("processed %v, want [ec2]", rec.order)
+	}
+}
+
+func TestRunReturnsValidationErrors(t *testing.T) {
+	r, rec, _ := newTestRunner(Options{Services: []string{"rds"}})
+
+	if _, err := r.Run(context.Background()); !errors.Is(err, ErrNoSupportedServices) {
+		t.Errorf("Run() error = %v, want ErrNoSupportedServices", err)
+	}
+	if len(rec.order) != 0 {
+		t.Errorf("processed %v after a validation error", rec.order)
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	r, rec, _ := newTestRunner(Options{Services: []string{"ec2", "ebs"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := r.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 0 || len(rec.order) != 0 {
+		t.Errorf("processed %v after cancellation", rec.order)
+	}
+}
+
+func TestScanRegions(t *testing.T) {
+	scope := Scope{Regions: []string{"us-west-2", "eu-west-1", "us-east-1"}, LoadConfig: fakeLoadConfig}
+	scan := func(ctx context.Context, cfg aws.Config) ([]string, error) {
+		if cfg.Region == "eu-west-1" {
+			return []string{"partial"}, errors.New("access denied")
+		}
+		return []string{cfg.Region + "/resource"}, nil
+	}
+	done := make(chan string, len(scope.Regions))
+
+	results := ScanRegions(context.Background(), scope, scan, func(result RegionResult[string]) {
+		done <- result.Region
+	})
+
+	if len(done) != len(scope.Regions) {
+		t.Errorf("done called %d times, want %d", len(done), len(scope.Regions))
+	}
+	for i, result := range results {
+		if result.Region != scope.Regions[i] {
+			t.Errorf("results[%d].Region = %s, want %s", i, result.Region, scope.Regions[i])
+		}
+	}
+	if results[1].Err == nil || !slices.Equal(results[1].Data, []string{"partial"}) {
+		t.Errorf("failed region = %v, %v, want the partial data and the error", results[1].Data, results[1].Err)
+	}
+	if results[2].Err != nil || !slices.Equal(results[2].Data, []string{"us-east-1/resource"}) {
+		t.Errorf("us-east-1 = %v, %v", results[2].Data, results[2].Err)
+	}
+}
+
+func TestScanRegionsGlobal(t *testing.T) {
+	scope := Scope{Regions: []string{"cn-north-1"}, Global: true, LoadConfig: fakeLoadConfig}
+	var scannedIn string
+	scan := func(ctx context.Context, cfg aws.Config) ([]string, error) {
+		scannedIn = cfg.Region
+		return nil, nil
+	}
+
+	results := ScanRegions(context.Background(), scope, scan, nil)
+
+	if scannedIn != "cn-north-1" {
+		t.Errorf("scanned in %s, want cn-north-1", scannedIn)
+	}
+	if len(results) != 1 || results[0].Region != GlobalRegion {
+		t.Errorf("results = %+v, want one result in %s", results, GlobalRegion)
+	}
+}
+
+func TestScanRegionsConfigError(t *testing.T) {
+	scope := Scope{
+		Regions: []string{"us-east-1"},
+		LoadConfig: func(ctx context.Context, region string) (aws.Config, error) {
+			return aws.Config{}, errors.New("no credentials")
+		},
+	}
+	scanned := false
+	scan := func(ctx context.Context, cfg aws.Config) ([]string, error) {
+		scanned = true
+		return nil, nil
+	}
+
+	results := ScanRegions(context.Background(), scope, scan, nil)
+
+	if scanned {
+		t.Error("scanned without a config")
+	}
+	want := "failed to load AWS config for region us-east-1: no credentials"
+	if results[0].Err == nil || results[0].Err.Error() != want {
+		t.Errorf("error = %v, want %s", results[0].Err, want)
+	}
+}
+
+func TestScanRegionsTimeout(t *testing.T) {
+	scope := Scope{Regions: []string{"us-east-1"}, LoadConfig: fakeLoadConfig, RegionTimeout: 10 * time.Millisecond}
+	scan := func(ctx context.Context, cfg aws.Config) ([]string, error) {
+		<-ctx.Done()
+		return []string{"scanned-before-timeout"}, ctx.Err()
+	}
+
+	results := ScanRegions(context.Background(), scope, scan, nil)
+
+	result := results[0]
+	if !result.TimedOut {
+		t.Error("TimedOut = false, want true")
+	}
+	if !slices.Equal(result.Data, []string{"scanned-before-timeout"}) {
+		t.Errorf("Data = %v, want the partial results", result.Data)
+	}
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "scan timed out after 10ms") {
+		t.Errorf("Err = %v, want the timeout", result.Err)
+	}
+}
+
+func TestScanRegionsTimeoutKeepsOtherRegions(t *testing.T) {
+	scope := Scope{Regions: []string{"us-east-1", "eu-west-1"}, LoadConfig: fakeLoadConfig, RegionTimeout: 20 * time.Millisecond}
+	// eu-west-1 hangs until its deadline
+	scan := func(ctx context.Context, cfg aws.Config) ([]string, error) {
+		if cfg.Region == "eu-west-1" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return []string{"vol-" + cfg.Region}, nil
+	}
+
+	start := time.Now()
+	results := ScanRegions(context.Background(), scope, scan, nil)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ScanRegions took %s, want it bounded by the region timeout", elapsed)
+	}
+	if results[0].TimedOut || results[0].Err != nil || !slices.Equal(results[0].Data, []string{"vol-us-east-1"}) {
+		t.Errorf("us-east-1 = %+v, want its resources without error", results[0])
+	}
+	if !results[1].TimedOut || results[1].Err == nil {
+		t.Errorf("eu-west-1 = %+v, want a timeout", results[1])
+	}
+}
+
+func TestScanRegionsCancelledIsNotTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scope := Scope{Regions: []string{"us-east-1"}, LoadConfig: fakeLoadConfig, RegionTimeout: time.Minute}
+	scan := func(ctx context.Context, cfg aws.Config) ([]string, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	results := ScanRegions(ctx, scope, scan, nil)
+
+	if results[0].TimedOut {
+		t.Error("TimedOut = true for a cancelled scan, want false")
+	}
+	if !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("Err = %v, want context.Canceled", results[0].Err)
+	}
+}
+
+func TestPrintServiceListSorted(t *testing.T) {
+	r, _, output := newTestRunner(Options{})
+
+	r.PrintServiceList("idled")
+
+	var listed []string
+	for _, line := range strings.Split(output.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 2 && fields[1] == "-" {
+			listed = append(listed, fields[0])
+		}
+	}
+	if want := []string{"cloudfront", "ebs", "ec2", "iam"}; !slices.Equal(listed, want) {
+		t.Errorf("listed %v, want %v", listed, want)
+	}
+	if !strings.Contains(output.String(), "idled scan cloudfront") {
+		t.Errorf("example does not use the first service:\n%s", output.String())
+	}
+}
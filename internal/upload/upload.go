@@ -0,0 +1,145 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/version"
+)
+
+// FormatVersion is the version of the document format written by Upload
+const FormatVersion = 1
+
+// objectName is the name of the uploaded document under its account and date prefix
+const objectName = "idled.json"
+
+// Document holds the structured results of a scan, uploaded as JSON with --upload-s3
+type Document struct {
+	Version   int                  `json:"version"`
+	Metadata  Metadata             `json:"metadata"`
+	Services  []Service            `json:"services"`
+	Summaries []models.CostSummary `json:"summaries"` // Idle resource counts and costs per service and region
+}
+
+// Metadata describes the scan that produced the document
+type Metadata struct {
+	Account     string            `json:"account"`
+	CallerARN   string            `json:"callerArn"`
+	Regions     []string          `json:"regions"`
+	StartedAt   time.Time         `json:"startedAt"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+	Build       version.BuildInfo `json:"build"`
+}
+
+// Service holds the resources found by one service
+type Service struct {
+	Service   string     `json:"service"`
+	Resources []Resource `json:"resources"`
+}
+
+// Resource is a scanned resource with the name of its model, since a service can return
+// resources of several types
+type Resource struct {
+	Type string `json:"type"` // Model name, e.g., VolumeInfo
+	Data any    `json:"data"`
+}
+
+// New creates an empty document for the scan described by meta
+func New(meta models.ScanMetadata) *Document {
+	return &Document{
+		Version: FormatVersion,
+		Metadata: Metadata{
+			Account:   meta.Account,
+			CallerARN: meta.CallerARN,
+			Regions:   meta.Regions,
+			StartedAt: meta.StartedAt.UTC(),
+			Build:     version.Get(),
+		},
+	}
+}
+
+// Add adds the resources of a service. Repeated calls for the same service extend its entry.
+func (d *Document) Add(service string, resources []any) {
+	i := 0
+	for i < len(d.Services) && d.Services[i].Service != service {
+		i++
+	}
+	if i == len(d.Services) {
+		d.Services = append(d.Services, Service{Service: service, Resources: []Resource{}})
+	}
+
+	for _, resource := range resources {
+		d.Services[i].Resources = append(d.Services[i].Resources, Resource{
+			Type: reflect.TypeOf(resource).Name(),
+			Data: resource,
+		})
+	}
+}
+
+// Location is the S3 bucket and key prefix the documents are uploaded to
+type Location struct {
+	Bucket string
+	Prefix string // Key prefix ending with a slash, empty for the bucket root
+}
+
+// ParseLocation parses an s3://bucket/prefix/ URI
+func ParseLocation(uri string) (Location, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "s3" || parsed.Host == "" {
+		return Location{}, fmt.Errorf("invalid S3 location '%s' (expected s3://bucket/prefix/)", uri)
+	}
+
+	prefix := strings.Trim(parsed.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return Location{Bucket: parsed.Host, Prefix: prefix}, nil
+}
+
+// Key returns the object key of the document of an account scanned at the given time, e.g.,
+// prefix/account=123456789012/date=2025-06-01/idled.json. Scans of the same day replace each other.
+func (l Location) Key(account string, scannedAt time.Time) string {
+	return fmt.Sprintf("%saccount=%s/date=%s/%s", l.Prefix, account, scannedAt.UTC().Format("2006-01-02"), objectName)
+}
+
+// PutObjectAPI is the S3 operation used to upload the document
+type PutObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// Upload writes the document as JSON to key in the bucket of the location, encrypted with
+// SSE-KMS when kmsKeyID is set
+func Upload(ctx context.Context, client PutObjectAPI, location Location, key, kmsKeyID string, doc *Document) error {
+	doc.Metadata.GeneratedAt = time.Now().UTC()
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the scan results: %w", err)
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(location.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	}
+	if kmsKeyID != "" {
+		input.ServerSideEncryption = s3types.ServerSideEncryptionAwsKms
+		input.SSEKMSKeyId = aws.String(kmsKeyID)
+	}
+
+	if _, err := client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("failed to upload the scan results to s3://%s/%s: %w", location.Bucket, key, err)
+	}
+	return nil
+}
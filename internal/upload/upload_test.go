@@ -0,0 +1,206 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/version"
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// recordingS3 returns a fake S3 client keeping the last uploaded object in input and body
+func recordingS3(input **s3.PutObjectInput, body *[]byte) *mocks.S3 {
+	return &mocks.S3{
+		PutObjectFunc: func(ctx context.Context, params *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			data, err := io.ReadAll(params.Body)
+			if err != nil {
+				return nil, err
+			}
+			*input, *body = params, data
+			return &s3.PutObjectOutput{}, nil
+		},
+	}
+}
+
+// checkGolden compares got with testdata/name, or rewrites the file with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s (run go test -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run go test -update if the change is intended\n--- got:\n%s", path, got)
+	}
+}
+
+func TestUploadGolden(t *testing.T) {
+	doc := New(models.ScanMetadata{
+		Account:   "123456789012",
+		CallerARN: "arn:aws:iam::123456789012:role/idled",
+		Regions:   []string{"us-east-1", "eu-west-1"},
+		StartedAt: time.Date(2026, 10, 17, 21, 0, 0, 0, time.FixedZone("KST", 9*60*60)),
+	})
+	doc.Metadata.Build = version.BuildInfo{Version: "v0.0.0-test"}
+	doc.Add("EBS", []any{
+		models.VolumeInfo{VolumeID: "vol-1", Region: "us-east-1", Size: 100, VolumeType: "gp3"},
+	})
+	doc.Add("Elastic IP", []any{
+		models.EIPInfo{AllocationID: "eipalloc-1", PublicIP: "198.51.100.1", Region: "eu-west-1", Tags: map[string]string{"Team": "platform"}},
+	})
+	doc.Add("EBS", nil)
+	doc.Add("Lambda", nil)
+	doc.Summaries = []models.CostSummary{
+		{Service: "EBS", Region: "us-east-1", Count: 1, MonthlyCost: 8},
+	}
+
+	var input *s3.PutObjectInput
+	var uploaded []byte
+	client := recordingS3(&input, &uploaded)
+	location := Location{Bucket: "reports", Prefix: "idled/"}
+	key := location.Key(doc.Metadata.Account, doc.Metadata.StartedAt)
+	if err := Upload(context.Background(), client, location, key, "", doc); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if got := aws.ToString(input.Key); got != "idled/account=123456789012/date=2026-10-17/idled.json" {
+		t.Errorf("key = %s", got)
+	}
+	if input.ServerSideEncryption != "" {
+		t.Errorf("ServerSideEncryption = %s, want none without a KMS key", input.ServerSideEncryption)
+	}
+
+	// The generation time is the time of the upload
+	generatedAt, err := doc.Metadata.GeneratedAt.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := bytes.Replace(uploaded, generatedAt, []byte(`"GENERATED-AT"`), 1)
+	checkGolden(t, "document.json.golden", append(body, '\n'))
+}
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		uri     string
+		want    Location
+		wantErr bool
+	}{
+		{uri: "s3://reports", want: Location{Bucket: "reports"}},
+		{uri: "s3://reports/", want: Location{Bucket: "reports"}},
+		{uri: "s3://reports/idled", want: Location{Bucket: "reports", Prefix: "idled/"}},
+		{uri: "s3://reports/scans/idled/", want: Location{Bucket: "reports", Prefix: "scans/idled/"}},
+		{uri: "reports/idled/", wantErr: true},
+		{uri: "https://reports.s3.amazonaws.com/idled/", wantErr: true},
+		{uri: "s3:///idled/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			got, err := ParseLocation(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLocation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLocation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationKey(t *testing.T) {
+	// Late evening in Seoul is still the previous day in UTC
+	scannedAt := time.Date(2026, 6, 2, 8, 30, 0, 0, time.FixedZone("KST", 9*60*60))
+
+	tests := []struct {
+		location Location
+		want     string
+	}{
+		{location: Location{Bucket: "reports"}, want: "account=123456789012/date=2026-06-01/idled.json"},
+		{location: Location{Bucket: "reports", Prefix: "idled/"}, want: "idled/account=123456789012/date=2026-06-01/idled.json"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.location.Key("123456789012", scannedAt); got != tt.want {
+			t.Errorf("Key() with prefix %q = %s, want %s", tt.location.Prefix, got, tt.want)
+		}
+	}
+}
+
+func TestUploadWithKMSKey(t *testing.T) {
+	var input *s3.PutObjectInput
+	var uploaded []byte
+	client := recordingS3(&input, &uploaded)
+
+	err := Upload(context.Background(), client, Location{Bucket: "reports"}, "idled.json", "alias/idled", New(models.ScanMetadata{}))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if input.ServerSideEncryption != s3types.ServerSideEncryptionAwsKms {
+		t.Errorf("ServerSideEncryption = %s, want %s", input.ServerSideEncryption, s3types.ServerSideEncryptionAwsKms)
+	}
+	if got := aws.ToString(input.SSEKMSKeyId); got != "alias/idled" {
+		t.Errorf("SSEKMSKeyId = %s, want alias/idled", got)
+	}
+	if got := aws.ToString(input.ContentType); got != "application/json" {
+		t.Errorf("ContentType = %s, want application/json", got)
+	}
+}
+
+func TestUploadError(t *testing.T) {
+	client := &mocks.S3{
+		PutObjectFunc: func(ctx context.Context, params *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			return nil, errors.New("AccessDenied")
+		},
+	}
+
+	err := Upload(context.Background(), client, Location{Bucket: "reports", Prefix: "idled/"}, "idled/idled.json", "", New(models.ScanMetadata{}))
+
+	if err == nil || !strings.Contains(err.Error(), "s3://reports/idled/idled.json") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Upload() error = %v, want the location and the cause", err)
+	}
+}
+
+func TestAddExtendsService(t *testing.T) {
+	doc := New(models.ScanMetadata{})
+	doc.Add("EBS", []any{models.VolumeInfo{VolumeID: "vol-1"}})
+	doc.Add("Elastic IP", nil)
+	doc.Add("EBS", []any{models.VolumeInfo{VolumeID: "vol-2"}, models.EIPInfo{AllocationID: "eipalloc-1"}})
+
+	if len(doc.Services) != 2 {
+		t.Fatalf("got %d services, want 2", len(doc.Services))
+	}
+	var types []string
+	for _, resource := range doc.Services[0].Resources {
+		types = append(types, resource.Type)
+	}
+	if want := []string{"VolumeInfo", "VolumeInfo", "EIPInfo"}; !slices.Equal(types, want) {
+		t.Errorf("EBS resource types = %v, want %v", types, want)
+	}
+	if doc.Services[1].Resources == nil {
+		t.Error("an empty service has null resources, want an empty list")
+	}
+}
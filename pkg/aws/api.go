@@ -0,0 +1,16 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+)
+
+// The scanners hold their AWS clients through minimal interfaces covering only the operations
+// they call, so that they can be exercised against fake clients. The constructors still wrap
+// the SDK clients.
+
+// cloudWatchMetricsAPI is the CloudWatch API used to read resource activity metrics
+type cloudWatchMetricsAPI interface {
+	GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error)
+}
@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// apiCallKey identifies the API calls counted together
+type apiCallKey struct {
+	service   string
+	operation string
+	region    string
+}
+
+// API calls made by every client built from LoadConfig, per AWS service, operation, and region
+var (
+	apiCallsMu sync.Mutex
+	apiCalls   = make(map[apiCallKey]*models.APICallStat)
+)
+
+// apiCallKeyFromContext returns the service, operation, and region of the API call of ctx
+func apiCallKeyFromContext(ctx context.Context) apiCallKey {
+	return apiCallKey{
+		service:   awsmiddleware.GetServiceID(ctx),
+		operation: awsmiddleware.GetOperationName(ctx),
+		region:    awsmiddleware.GetRegion(ctx),
+	}
+}
+
+// recordAPICall updates the counters of the API call of ctx
+func recordAPICall(ctx context.Context, update func(stat *models.APICallStat)) {
+	key := apiCallKeyFromContext(ctx)
+
+	apiCallsMu.Lock()
+	defer apiCallsMu.Unlock()
+	stat, ok := apiCalls[key]
+	if !ok {
+		stat = &models.APICallStat{Service: key.service, Operation: key.operation, Region: key.region}
+		apiCalls[key] = stat
+	}
+	update(stat)
+}
+
+// APICallStats returns the API calls made since the start or the last ResetAPICallStats,
+// sorted by service, operation, and region
+func APICallStats() []models.APICallStat {
+	apiCallsMu.Lock()
+	stats := make([]models.APICallStat, 0, len(apiCalls))
+	for _, stat := range apiCalls {
+		stats = append(stats, *stat)
+	}
+	apiCallsMu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Service != stats[j].Service {
+			return stats[i].Service < stats[j].Service
+		}
+		if stats[i].Operation != stats[j].Operation {
+			return stats[i].Operation < stats[j].Operation
+		}
+		return stats[i].Region < stats[j].Region
+	})
+	return stats
+}
+
+// ResetAPICallStats clears the API call counters, so that repeated scans are counted separately
+func ResetAPICallStats() {
+	apiCallsMu.Lock()
+	defer apiCallsMu.Unlock()
+	clear(apiCalls)
+}
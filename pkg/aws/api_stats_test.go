@@ -0,0 +1,204 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// ec2Server is a fake EC2 endpoint that throttles the first calls, then answers every
+// action with an empty result
+type ec2Server struct {
+	mu        sync.Mutex
+	throttled int      // Calls left to throttle
+	actions   []string // Action of every request, throttled or not
+}
+
+func (s *ec2Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	action := ""
+	if err := r.ParseForm(); err == nil {
+		action = r.Form.Get("Action")
+	}
+
+	s.mu.Lock()
+	s.actions = append(s.actions, action)
+	throttle := s.throttled > 0
+	if throttle {
+		s.throttled--
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml")
+	if throttle {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>Request limit exceeded.</Message></Error></Errors><RequestID>throttled</RequestID></Response>`)
+		return
+	}
+	fmt.Fprintf(w, `<%sResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>ok</requestId></%sResponse>`, action, action)
+}
+
+// calls returns the number of requests the server received
+func (s *ec2Server) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.actions)
+}
+
+// newEC2Server starts a fake EC2 endpoint throttling the first calls and returns a config
+// built by LoadConfig that sends the API calls to it, retried without delay up to the
+// maximum attempts. The API
+// call counters are reset now and when the test ends.
+func newEC2Server(t *testing.T, throttled int) (*ec2Server, aws.Config) {
+	t.Helper()
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	ResetAPICallStats()
+	t.Cleanup(ResetAPICallStats)
+
+	server := &ec2Server{throttled: throttled}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	cfg, err := LoadConfig(context.Background(), "us-east-1",
+		config.WithBaseEndpoint(httpServer.URL),
+		config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		})),
+		// The adaptive mode would pace the retries by seconds after a throttle
+		config.WithRetryer(func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.MaxAttempts = apiMaxAttempts
+				o.MaxBackoff = time.Millisecond
+				o.RateLimiter = ratelimit.None
+			})
+		}),
+	)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return server, cfg
+}
+
+func TestAPICallCounting(t *testing.T) {
+	throttlesBefore, errorsBefore := ThrottledRequestCount(), APIErrorCount()
+	server, cfg := newEC2Server(t, 2)
+	client := ec2.NewFromConfig(cfg)
+
+	if _, err := client.DescribeAddresses(context.Background(), &ec2.DescribeAddressesInput{}); err != nil {
+		t.Fatalf("DescribeAddresses() error = %v", err)
+	}
+	if _, err := client.DescribeVolumes(context.Background(), &ec2.DescribeVolumesInput{}); err != nil {
+		t.Fatalf("DescribeVolumes() error = %v", err)
+	}
+
+	want := []models.APICallStat{
+		{Service: "EC2", Operation: "DescribeAddresses", Region: "us-east-1", Calls: 1, Throttles: 2},
+		{Service: "EC2", Operation: "DescribeVolumes", Region: "us-east-1", Calls: 1},
+	}
+	got := APICallStats()
+	if len(got) != len(want) {
+		t.Fatalf("APICallStats() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("APICallStats()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if server.calls() != 4 {
+		t.Errorf("server received %d requests, want 4 including the retries", server.calls())
+	}
+	if throttles := ThrottledRequestCount() - throttlesBefore; throttles != 2 {
+		t.Errorf("ThrottledRequestCount() grew by %d, want 2", throttles)
+	}
+	if errors := APIErrorCount() - errorsBefore; errors != 0 {
+		t.Errorf("APIErrorCount() grew by %d, want 0", errors)
+	}
+}
+
+func TestAPICallCountingFailedCall(t *testing.T) {
+	saved := apiMaxAttempts
+	t.Cleanup(func() { apiMaxAttempts = saved })
+	SetMaxRetries(1)
+	errorsBefore := APIErrorCount()
+	server, cfg := newEC2Server(t, 10)
+
+	if _, err := ec2.NewFromConfig(cfg).DescribeAddresses(context.Background(), &ec2.DescribeAddressesInput{}); err == nil {
+		t.Fatal("DescribeAddresses() error = nil, want the throttling error")
+	}
+
+	want := models.APICallStat{Service: "EC2", Operation: "DescribeAddresses", Region: "us-east-1", Calls: 1, Throttles: 2, Errors: 1}
+	if got := APICallStats(); len(got) != 1 || got[0] != want {
+		t.Errorf("APICallStats() = %+v, want [%+v]", got, want)
+	}
+	if server.calls() != 2 {
+		t.Errorf("server received %d requests, want the first attempt and 1 retry", server.calls())
+	}
+	if errors := APIErrorCount() - errorsBefore; errors != 1 {
+		t.Errorf("APIErrorCount() grew by %d, want 1", errors)
+	}
+}
+
+func TestRecordAPICallConcurrently(t *testing.T) {
+	ResetAPICallStats()
+	t.Cleanup(ResetAPICallStats)
+
+	contexts := []context.Context{
+		callContext("EC2", "DescribeVolumes", "us-east-1"),
+		callContext("EC2", "DescribeVolumes", "eu-west-1"),
+		callContext("S3", "ListBuckets", "us-east-1"),
+	}
+	var wg sync.WaitGroup
+	for i := range 300 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recordAPICall(contexts[i%len(contexts)], func(stat *models.APICallStat) {
+				stat.Calls++
+				if i%10 == 0 {
+					stat.Throttles++
+				}
+			})
+		}()
+	}
+	wg.Wait()
+
+	want := []models.APICallStat{
+		{Service: "EC2", Operation: "DescribeVolumes", Region: "eu-west-1", Calls: 100, Throttles: 10},
+		{Service: "EC2", Operation: "DescribeVolumes", Region: "us-east-1", Calls: 100, Throttles: 10},
+		{Service: "S3", Operation: "ListBuckets", Region: "us-east-1", Calls: 100, Throttles: 10},
+	}
+	got := APICallStats()
+	if len(got) != len(want) {
+		t.Fatalf("APICallStats() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("APICallStats()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	ResetAPICallStats()
+	if got := APICallStats(); len(got) != 0 {
+		t.Errorf("APICallStats() after reset = %+v, want none", got)
+	}
+}
+
+// callContext returns the context of an API call to an operation of a service in a region
+func callContext(service, operation, region string) context.Context {
+	ctx := awsmiddleware.SetServiceID(context.Background(), service)
+	ctx = awsmiddleware.SetOperationName(ctx, operation)
+	return awsmiddleware.SetRegion(ctx, region)
+}
@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/inspector2"
+
+	"github.com/younsl/idled/pkg/aws/mocks"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+// The fakes must keep satisfying the APIs of the scanners they stand in for
+var (
+	_ ec2InstancesAPI                  = (*mocks.EC2)(nil)
+	_ ebsAPI                           = (*mocks.EC2)(nil)
+	_ eipAPI                           = (*mocks.EC2)(nil)
+	_ s3API                            = (*mocks.S3)(nil)
+	_ s3MetricsAPI                     = (*mocks.CloudWatch)(nil)
+	_ lambdaAPI                        = (*mocks.Lambda)(nil)
+	_ LogsAPI                          = (*mocks.Logs)(nil)
+	_ ssmParameterAPI                  = (*mocks.SSM)(nil)
+	_ appConfigAPI                     = (*mocks.AppConfig)(nil)
+	_ classicELBAPI                    = (*mocks.ELB)(nil)
+	_ elbV2API                         = (*mocks.ELBV2)(nil)
+	_ configAPI                        = (*mocks.ConfigService)(nil)
+	_ openSearchAPI                    = (*mocks.OpenSearch)(nil)
+	_ iamAPI                           = (*mocks.IAM)(nil)
+	_ cloudWatchMetricsAPI             = (*mocks.CloudWatch)(nil)
+	_ inspector2.ListCoverageAPIClient = (*mocks.Inspector2)(nil)
+)
+
+// newTestPricing returns a pricing service that only uses the fallback prices
+func newTestPricing() *pricing.PricingService {
+	service := pricing.NewPricingService()
+	service.DisableAPI()
+	return service
+}
@@ -0,0 +1,82 @@
+package aws
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// ec2ResourceARN builds the ARN of an EC2 resource, such as "instance/i-0123456789abcdef0".
+// It is empty when the account is unknown, since an ARN without one cannot be used.
+func ec2ResourceARN(region, accountID, resource string) string {
+	if accountID == "" {
+		return ""
+	}
+	return fmt.Sprintf("arn:%s:ec2:%s:%s:%s", utils.PartitionForRegion(region), region, accountID, resource)
+}
+
+// ELBARNError is returned for an ARN that is not of an ALB, NLB, GWLB, or target group
+type ELBARNError struct {
+	ARN    string
+	Reason string
+}
+
+func (e *ELBARNError) Error() string {
+	return fmt.Sprintf("malformed ELB ARN %q: %s", e.ARN, e.Reason)
+}
+
+// elbDimensionTypes are the load balancer types of ELB (v2) ARNs, the first part of their dimension
+var elbDimensionTypes = []string{"app", "net", "gwy"}
+
+// ParseELBDimensionFromARN returns the value of the CloudWatch LoadBalancer dimension of an ALB,
+// NLB, or GWLB, e.g., "app/my-alb/50dc6c495c0c9188" for
+// arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188.
+// ARNs of any partition are accepted.
+func ParseELBDimensionFromARN(lbArn string) (string, error) {
+	resource, err := elbARNResource(lbArn)
+	if err != nil {
+		return "", err
+	}
+
+	value, found := strings.CutPrefix(resource, "loadbalancer/")
+	if !found {
+		return "", &ELBARNError{ARN: lbArn, Reason: "not a load balancer"}
+	}
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || !slices.Contains(elbDimensionTypes, parts[0]) || parts[1] == "" || parts[2] == "" {
+		return "", &ELBARNError{ARN: lbArn, Reason: "expected loadbalancer/<app|net|gwy>/<name>/<id>"}
+	}
+	return value, nil
+}
+
+// ParseTargetGroupDimensionFromARN returns the value of the CloudWatch TargetGroup dimension of a
+// target group, e.g., "targetgroup/my-targets/73e2d6bc24d8a067" for
+// arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/my-targets/73e2d6bc24d8a067.
+// ARNs of any partition are accepted.
+func ParseTargetGroupDimensionFromARN(tgArn string) (string, error) {
+	resource, err := elbARNResource(tgArn)
+	if err != nil {
+		return "", err
+	}
+
+	parts := strings.Split(resource, "/")
+	if len(parts) != 3 || parts[0] != "targetgroup" || parts[1] == "" || parts[2] == "" {
+		return "", &ELBARNError{ARN: tgArn, Reason: "expected targetgroup/<name>/<id>"}
+	}
+	return resource, nil
+}
+
+// elbARNResource returns the resource part of an Elastic Load Balancing ARN
+func elbARNResource(elbArn string) (string, error) {
+	parsed, err := arn.Parse(elbArn)
+	if err != nil {
+		return "", &ELBARNError{ARN: elbArn, Reason: err.Error()}
+	}
+	if parsed.Service != "elasticloadbalancing" {
+		return "", &ELBARNError{ARN: elbArn, Reason: fmt.Sprintf("service is %s, not elasticloadbalancing", parsed.Service)}
+	}
+	return parsed.Resource, nil
+}
@@ -0,0 +1,170 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseELBDimensionFromARN(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "application load balancer",
+			arn:  "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188",
+			want: "app/my-alb/50dc6c495c0c9188",
+		},
+		{
+			name: "network load balancer",
+			arn:  "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/my-nlb/50dc6c495c0c9188",
+			want: "net/my-nlb/50dc6c495c0c9188",
+		},
+		{
+			name: "gateway load balancer",
+			arn:  "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/gwy/my-gwlb/50dc6c495c0c9188",
+			want: "gwy/my-gwlb/50dc6c495c0c9188",
+		},
+		{
+			name: "GovCloud partition",
+			arn:  "arn:aws-us-gov:elasticloadbalancing:us-gov-west-1:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188",
+			want: "app/my-alb/50dc6c495c0c9188",
+		},
+		{
+			name: "China partition",
+			arn:  "arn:aws-cn:elasticloadbalancing:cn-north-1:123456789012:loadbalancer/net/my-nlb/50dc6c495c0c9188",
+			want: "net/my-nlb/50dc6c495c0c9188",
+		},
+		{
+			name:    "target group",
+			arn:     "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/my-targets/73e2d6bc24d8a067",
+			wantErr: true,
+		},
+		{
+			name:    "unknown load balancer type",
+			arn:     "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/xyz/my-lb/50dc6c495c0c9188",
+			wantErr: true,
+		},
+		{
+			name:    "missing id",
+			arn:     "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb",
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			arn:     "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app//50dc6c495c0c9188",
+			wantErr: true,
+		},
+		{
+			name:    "extra path element",
+			arn:     "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188/listener",
+			wantErr: true,
+		},
+		{
+			name:    "not an ARN",
+			arn:     "app/my-alb/50dc6c495c0c9188",
+			wantErr: true,
+		},
+		{
+			name:    "empty string",
+			arn:     "",
+			wantErr: true,
+		},
+		{
+			name:    "non-ELB ARN",
+			arn:     "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseELBDimensionFromARN(tt.arn)
+			if tt.wantErr {
+				var arnErr *ELBARNError
+				if !errors.As(err, &arnErr) {
+					t.Fatalf("ParseELBDimensionFromARN() error = %v, want an *ELBARNError", err)
+				}
+				if arnErr.ARN != tt.arn {
+					t.Errorf("ELBARNError.ARN = %q, want %q", arnErr.ARN, tt.arn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseELBDimensionFromARN() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseELBDimensionFromARN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTargetGroupDimensionFromARN(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "target group",
+			arn:  "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/my-targets/73e2d6bc24d8a067",
+			want: "targetgroup/my-targets/73e2d6bc24d8a067",
+		},
+		{
+			name: "China partition",
+			arn:  "arn:aws-cn:elasticloadbalancing:cn-north-1:123456789012:targetgroup/my-targets/73e2d6bc24d8a067",
+			want: "targetgroup/my-targets/73e2d6bc24d8a067",
+		},
+		{
+			name:    "load balancer",
+			arn:     "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188",
+			wantErr: true,
+		},
+		{
+			name:    "missing id",
+			arn:     "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/my-targets",
+			wantErr: true,
+		},
+		{
+			name:    "not an ARN",
+			arn:     "targetgroup/my-targets/73e2d6bc24d8a067",
+			wantErr: true,
+		},
+		{
+			name:    "non-ELB ARN",
+			arn:     "arn:aws:s3:::my-bucket",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTargetGroupDimensionFromARN(tt.arn)
+			if tt.wantErr {
+				var arnErr *ELBARNError
+				if !errors.As(err, &arnErr) {
+					t.Fatalf("ParseTargetGroupDimensionFromARN() error = %v, want an *ELBARNError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTargetGroupDimensionFromARN() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTargetGroupDimensionFromARN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestELBARNErrorMessage(t *testing.T) {
+	_, err := ParseELBDimensionFromARN("arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0")
+	want := `malformed ELB ARN "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0": service is ec2, not elasticloadbalancing`
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+}
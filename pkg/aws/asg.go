@@ -0,0 +1,182 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+const (
+	// DefaultASGIdleDays is the default number of days without a scaling activity before a zero-capacity Auto Scaling group is idle
+	DefaultASGIdleDays = 30
+
+	// asgActivityLookbackDays is how long Auto Scaling keeps the scaling activities of a group
+	asgActivityLookbackDays = 42
+)
+
+// autoScalingAPI is the Auto Scaling API used by ASGScanner
+type autoScalingAPI interface {
+	autoscaling.DescribeAutoScalingGroupsAPIClient
+	DescribeScalingActivities(ctx context.Context, params *autoscaling.DescribeScalingActivitiesInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeScalingActivitiesOutput, error)
+}
+
+// targetHealthAPI is the Elastic Load Balancing API used by ASGScanner to check the health of target groups
+type targetHealthAPI interface {
+	DescribeTargetHealth(ctx context.Context, params *elbv2.DescribeTargetHealthInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetHealthOutput, error)
+}
+
+// ASGScanner contains the AWS clients needed for scanning Auto Scaling groups
+type ASGScanner struct {
+	Client        autoScalingAPI
+	ELBV2Client   targetHealthAPI
+	Region        string
+	IdleThreshold int // in days
+}
+
+// NewASGScanner creates a new ASGScanner for a given region
+func NewASGScanner(cfg aws.Config) *ASGScanner {
+	return &ASGScanner{
+		Client:        autoscaling.NewFromConfig(cfg),
+		ELBV2Client:   elbv2.NewFromConfig(cfg),
+		Region:        cfg.Region,
+		IdleThreshold: DefaultASGIdleDays,
+	}
+}
+
+// SetIdleThreshold sets the number of days without a scaling activity before a zero-capacity group is considered idle
+func (s *ASGScanner) SetIdleThreshold(days int) {
+	s.IdleThreshold = days
+}
+
+// GetIdleAutoScalingGroups returns the Auto Scaling groups with zero capacity for at least
+// IdleThreshold days, with suspended scaling processes, or whose target groups have no healthy
+// target. Groups that could not be checked are left out, and their errors are returned.
+func (s *ASGScanner) GetIdleAutoScalingGroups(ctx context.Context) ([]models.AutoScalingGroupInfo, []error) {
+	var idle []models.AutoScalingGroupInfo
+	var scanErrs []error
+
+	paginator := autoscaling.NewDescribeAutoScalingGroupsPaginator(s.Client, &autoscaling.DescribeAutoScalingGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			scanErrs = append(scanErrs, fmt.Errorf("error listing Auto Scaling groups in region %s: %w", s.Region, err))
+			break
+		}
+
+		for _, group := range page.AutoScalingGroups {
+			info, ok, err := s.checkAutoScalingGroup(ctx, group)
+			if err != nil {
+				scanErrs = append(scanErrs, err)
+				recordSkip("asg", s.Region, aws.ToString(group.AutoScalingGroupName), "activity check failed", err)
+				continue
+			}
+			if ok {
+				idle = append(idle, info)
+			}
+		}
+	}
+
+	return idle, scanErrs
+}
+
+// checkAutoScalingGroup returns the group info if the group has zero capacity for at least the
+// idle threshold, suspended scaling processes, or no healthy target. Groups being deleted are skipped.
+func (s *ASGScanner) checkAutoScalingGroup(ctx context.Context, group asgtypes.AutoScalingGroup) (models.AutoScalingGroupInfo, bool, error) {
+	if group.Status != nil {
+		// Only groups being deleted have a status
+		return models.AutoScalingGroupInfo{}, false, nil
+	}
+
+	info := models.AutoScalingGroupInfo{
+		Name:             aws.ToString(group.AutoScalingGroupName),
+		ARN:              aws.ToString(group.AutoScalingGroupARN),
+		Region:           s.Region,
+		DesiredCapacity:  aws.ToInt32(group.DesiredCapacity),
+		MinSize:          aws.ToInt32(group.MinSize),
+		MaxSize:          aws.ToInt32(group.MaxSize),
+		InstanceCount:    len(group.Instances),
+		TargetGroupCount: len(group.TargetGroupARNs),
+		CreatedTime:      aws.ToTime(group.CreatedTime),
+	}
+	for _, process := range group.SuspendedProcesses {
+		info.SuspendedProcesses = append(info.SuspendedProcesses, aws.ToString(process.ProcessName))
+	}
+
+	lastActivity, err := s.getLastActivity(ctx, info.Name)
+	if err != nil {
+		return info, false, err
+	}
+	info.LastActivity = lastActivity
+	if lastActivity != nil {
+		info.IdleDays = utils.CalculateElapsedDays(*lastActivity)
+	} else {
+		// Without a scaling activity in the lookback, the group has not scaled at least since its
+		// creation, up to the lookback
+		info.IdleDays = min(utils.CalculateElapsedDays(info.CreatedTime), asgActivityLookbackDays)
+	}
+
+	if info.DesiredCapacity == 0 && info.MinSize == 0 && info.MaxSize == 0 {
+		// A group without activity in the lookback counts from its creation when the threshold is longer
+		idleDays := info.IdleDays
+		if lastActivity == nil {
+			idleDays = utils.CalculateElapsedDays(info.CreatedTime)
+		}
+		info.ZeroCapacity = idleDays >= s.IdleThreshold
+	}
+
+	// Target groups of a group without capacity have no targets anyway
+	if info.DesiredCapacity > 0 && info.TargetGroupCount > 0 {
+		healthy, err := s.countHealthyTargets(ctx, group.TargetGroupARNs)
+		if err != nil {
+			return info, false, fmt.Errorf("error checking target health of Auto Scaling group %s: %w", info.Name, err)
+		}
+		info.HealthyTargets = healthy
+		info.NoHealthyTargets = healthy == 0
+	}
+
+	return info, info.ZeroCapacity || info.Suspended() || info.NoHealthyTargets, nil
+}
+
+// getLastActivity returns the start time of the last scaling activity of a group, nil if
+// there was none within the lookback
+func (s *ASGScanner) getLastActivity(ctx context.Context, groupName string) (*time.Time, error) {
+	output, err := s.Client.DescribeScalingActivities(ctx, &autoscaling.DescribeScalingActivitiesInput{
+		AutoScalingGroupName: aws.String(groupName),
+		MaxRecords:           aws.Int32(1), // Activities are returned most recent first
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing scaling activities of Auto Scaling group %s: %w", groupName, err)
+	}
+	if len(output.Activities) == 0 {
+		return nil, nil
+	}
+	return output.Activities[0].StartTime, nil
+}
+
+// countHealthyTargets returns the healthy targets across the given target groups
+func (s *ASGScanner) countHealthyTargets(ctx context.Context, targetGroupARNs []string) (int, error) {
+	healthy := 0
+	for _, targetGroupARN := range targetGroupARNs {
+		output, err := s.ELBV2Client.DescribeTargetHealth(ctx, &elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(targetGroupARN),
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, description := range output.TargetHealthDescriptions {
+			if description.TargetHealth != nil && description.TargetHealth.State == elbv2types.TargetHealthStateEnumHealthy {
+				healthy++
+			}
+		}
+	}
+	return healthy, nil
+}
@@ -0,0 +1,15 @@
+package aws
+
+// batchStrings splits values into batches of at most size values, used to keep
+// ID lists within the per-filter limits of the AWS APIs
+func batchStrings(values []string, size int) [][]string {
+	var batches [][]string
+	for start := 0; start < len(values); start += size {
+		end := start + size
+		if end > len(values) {
+			end = len(values)
+		}
+		batches = append(batches, values[start:end])
+	}
+	return batches
+}
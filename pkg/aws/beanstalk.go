@@ -0,0 +1,193 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticbeanstalk"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/elasticbeanstalk/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+const (
+	beanstalkCheckPeriodDays = 30
+
+	// beanstalkWebServerTier is the tier of environments serving HTTP traffic through a load balancer
+	beanstalkWebServerTier = "WebServer"
+)
+
+// beanstalkAPI is the Elastic Beanstalk API used by BeanstalkScanner
+type beanstalkAPI interface {
+	DescribeEnvironmentResources(ctx context.Context, params *elasticbeanstalk.DescribeEnvironmentResourcesInput, optFns ...func(*elasticbeanstalk.Options)) (*elasticbeanstalk.DescribeEnvironmentResourcesOutput, error)
+	DescribeEnvironments(ctx context.Context, params *elasticbeanstalk.DescribeEnvironmentsInput, optFns ...func(*elasticbeanstalk.Options)) (*elasticbeanstalk.DescribeEnvironmentsOutput, error)
+}
+
+// BeanstalkScanner contains the AWS clients needed for scanning Elastic Beanstalk environments
+type BeanstalkScanner struct {
+	BeanstalkClient beanstalkAPI
+	EC2Client       ec2.DescribeInstancesAPIClient
+	CWClient        cloudWatchMetricsAPI
+	Region          string
+	Pricing         *pricing.PricingService
+}
+
+// NewBeanstalkScanner creates a new BeanstalkScanner for a given region
+func NewBeanstalkScanner(cfg aws.Config) *BeanstalkScanner {
+	return &BeanstalkScanner{
+		BeanstalkClient: elasticbeanstalk.NewFromConfig(cfg),
+		EC2Client:       ec2.NewFromConfig(cfg),
+		CWClient:        cloudwatch.NewFromConfig(cfg),
+		Region:          cfg.Region,
+		Pricing:         pricing.Default(),
+	}
+}
+
+// GetIdleBeanstalkEnvironments returns the Ready web server environments whose load balancer
+// served no traffic over the last 30 days. Environments without a load balancer (single
+// instance and worker environments) cannot be checked and are skipped.
+func (s *BeanstalkScanner) GetIdleBeanstalkEnvironments(ctx context.Context) ([]models.BeanstalkEnvironmentInfo, []error) {
+	var idle []models.BeanstalkEnvironmentInfo
+	var errs []error
+
+	input := &elasticbeanstalk.DescribeEnvironmentsInput{IncludeDeleted: aws.Bool(false)}
+	for {
+		output, err := s.BeanstalkClient.DescribeEnvironments(ctx, input)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing Elastic Beanstalk environments in region %s: %w", s.Region, err))
+			break
+		}
+
+		for _, environment := range output.Environments {
+			if environment.Status != ebtypes.EnvironmentStatusReady {
+				continue
+			}
+			if environment.Tier != nil && aws.ToString(environment.Tier.Name) != beanstalkWebServerTier {
+				continue
+			}
+
+			info, err := s.checkEnvironment(ctx, environment)
+			if err != nil {
+				errs = append(errs, err)
+				recordSkip("beanstalk", s.Region, aws.ToString(environment.EnvironmentName), "metrics unavailable", err)
+				continue
+			}
+			if info != nil {
+				idle = append(idle, *info)
+			}
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return idle, errs
+}
+
+// checkEnvironment returns the environment info if its load balancer served no traffic over the
+// check period, or nil otherwise
+func (s *BeanstalkScanner) checkEnvironment(ctx context.Context, environment ebtypes.EnvironmentDescription) (*models.BeanstalkEnvironmentInfo, error) {
+	name := aws.ToString(environment.EnvironmentName)
+
+	resources, err := s.BeanstalkClient.DescribeEnvironmentResources(ctx, &elasticbeanstalk.DescribeEnvironmentResourcesInput{
+		EnvironmentId: environment.EnvironmentId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing resources of Elastic Beanstalk environment %s: %w", name, err)
+	}
+	if resources.EnvironmentResources == nil || len(resources.EnvironmentResources.LoadBalancers) == 0 {
+		return nil, nil
+	}
+
+	loadBalancer := aws.ToString(resources.EnvironmentResources.LoadBalancers[0].Name)
+	lbType, requestCount, err := s.getLoadBalancerTraffic(ctx, loadBalancer)
+	if err != nil {
+		return nil, fmt.Errorf("error checking traffic of Elastic Beanstalk environment %s: %w", name, err)
+	}
+	if requestCount != 0 {
+		return nil, nil
+	}
+
+	var instanceIDs []string
+	for _, instance := range resources.EnvironmentResources.Instances {
+		instanceIDs = append(instanceIDs, aws.ToString(instance.Id))
+	}
+	instanceTypes, operatingSystem, err := s.getInstanceTypes(ctx, instanceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error describing instances of Elastic Beanstalk environment %s: %w", name, err)
+	}
+
+	info := &models.BeanstalkEnvironmentInfo{
+		EnvironmentName:  name,
+		EnvironmentID:    aws.ToString(environment.EnvironmentId),
+		ApplicationName:  aws.ToString(environment.ApplicationName),
+		Region:           s.Region,
+		Status:           string(environment.Status),
+		Health:           string(environment.Health),
+		Platform:         aws.ToString(environment.SolutionStackName),
+		LoadBalancer:     loadBalancer,
+		LoadBalancerType: lbType,
+		InstanceCount:    len(instanceTypes),
+		InstanceTypes:    instanceTypes,
+		DateCreated:      aws.ToTime(environment.DateCreated),
+		DateUpdated:      aws.ToTime(environment.DateUpdated),
+		RequestCount:     requestCount,
+		IdleReason:       fmt.Sprintf("Zero load balancer traffic (%dd)", beanstalkCheckPeriodDays),
+	}
+	info.EstimatedMonthlyCost, info.PricingSource = s.Pricing.CalculateBeanstalkEnvironmentMonthlyCostWithSource(instanceTypes, operatingSystem, lbType, s.Region)
+	return info, nil
+}
+
+// getLoadBalancerTraffic returns the short type of an environment's load balancer and its traffic
+// over the check period, measured like the ELB scan: RequestCount for ALBs and Classic Load
+// Balancers, and the average ActiveFlowCount for NLBs. Beanstalk reports ALBs and NLBs by ARN
+// and Classic Load Balancers by name.
+func (s *BeanstalkScanner) getLoadBalancerTraffic(ctx context.Context, loadBalancer string) (string, float64, error) {
+	switch {
+	case strings.Contains(loadBalancer, ":loadbalancer/app/"):
+		sum, err := getLoadBalancerMetricSum(ctx, s.CWClient, loadBalancer, namespaceALB, metricRequestCount, cwtypes.StatisticSum, beanstalkCheckPeriodDays)
+		return "ALB", sum, err
+	case strings.Contains(loadBalancer, ":loadbalancer/net/"):
+		sum, err := getLoadBalancerMetricSum(ctx, s.CWClient, loadBalancer, namespaceNLB, metricActiveFlowCount, cwtypes.StatisticAverage, beanstalkCheckPeriodDays)
+		return "NLB", sum, err
+	default:
+		dimension := cloudwatchutil.Dimension("LoadBalancerName", loadBalancer)
+		sum, err := getLoadBalancerMetricValue(ctx, s.CWClient, namespaceCLB, metricRequestCount, cwtypes.StatisticSum, dimension, beanstalkCheckPeriodDays)
+		return "CLB", sum, err
+	}
+}
+
+// getInstanceTypes returns the instance type of each environment instance and the
+// operating system the instances are priced for
+func (s *BeanstalkScanner) getInstanceTypes(ctx context.Context, instanceIDs []string) ([]string, string, error) {
+	operatingSystem := pricing.EC2OperatingSystemLinux
+	if len(instanceIDs) == 0 {
+		return nil, operatingSystem, nil
+	}
+
+	var instanceTypes []string
+	paginator := ec2.NewDescribeInstancesPaginator(s.EC2Client, &ec2.DescribeInstancesInput{InstanceIds: instanceIDs})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, operatingSystem, err
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceTypes = append(instanceTypes, string(instance.InstanceType))
+				operatingSystem = instanceOperatingSystem(instance, s.Region)
+			}
+		}
+	}
+
+	return instanceTypes, operatingSystem, nil
+}
@@ -0,0 +1,175 @@
+package aws
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// DefaultMaxRetries is the default number of retries per API call after the first attempt
+const DefaultMaxRetries = 5
+
+// API limits and counters shared by every client built from LoadConfig, across all
+// regions and services
+var (
+	apiMaxAttempts   = DefaultMaxRetries + 1 // Maximum attempts per API call, including the first
+	apiLimiter       *rate.Limiter           // nil when requests per second are unlimited
+	apiSlots         chan struct{}           // nil when in-flight requests are unlimited
+	apiErrorCount    atomic.Int64
+	apiThrottleCount atomic.Int64
+	endpointURL      string // Endpoint of every service, empty for the AWS endpoints
+	skipTLSVerify    bool   // Whether TLS certificates of the endpoints are not verified
+)
+
+// SetAPILimits sets the maximum number of API requests per second and in flight at once,
+// shared across all regions and services. Zero disables the limit. It must be called
+// before scanning starts.
+func SetAPILimits(maxRPS float64, maxConcurrency int) {
+	apiLimiter = nil
+	if maxRPS > 0 {
+		burst := int(maxRPS)
+		if burst < 1 {
+			burst = 1
+		}
+		apiLimiter = rate.NewLimiter(rate.Limit(maxRPS), burst)
+	}
+
+	apiSlots = nil
+	if maxConcurrency > 0 {
+		apiSlots = make(chan struct{}, maxConcurrency)
+	}
+}
+
+// SetMaxRetries sets the number of times a throttled or failed API call is retried with
+// exponential backoff, after the first attempt. It must be called before scanning starts.
+func SetMaxRetries(retries int) {
+	apiMaxAttempts = max(retries, 0) + 1
+}
+
+// SetEndpoint sends the API calls of every service to the given endpoint instead of the AWS
+// endpoints, e.g. LocalStack, unless the endpoint of a service is set with its
+// AWS_ENDPOINT_URL_<SERVICE> environment variable. An empty URL keeps the AWS endpoints.
+// skipVerify disables the verification of TLS certificates, for self-signed local
+// endpoints. It must be called before scanning starts.
+func SetEndpoint(url string, skipVerify bool) {
+	endpointURL = url
+	skipTLSVerify = skipVerify
+}
+
+// EndpointOptions returns the config load options applying SetEndpoint, for clients that
+// do not use LoadConfig
+func EndpointOptions() []func(*config.LoadOptions) error {
+	var opts []func(*config.LoadOptions) error
+	if endpointURL != "" {
+		opts = append(opts, config.WithBaseEndpoint(endpointURL))
+	}
+	if skipTLSVerify {
+		opts = append(opts, config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+			tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		})))
+	}
+	return opts
+}
+
+// minMaxAttempts returns the maximum attempts per API call, at least the given number for
+// calls that deserve extra retries
+func minMaxAttempts(attempts int) int {
+	return max(attempts, apiMaxAttempts)
+}
+
+// APIErrorCount returns the number of API calls that failed after all retries
+func APIErrorCount() int64 {
+	return apiErrorCount.Load()
+}
+
+// ThrottledRequestCount returns the number of API attempts rejected by throttling
+func ThrottledRequestCount() int64 {
+	return apiThrottleCount.Load()
+}
+
+// LoadConfig loads the default AWS config for a region with the shared API limits,
+// adaptive retries on throttling, API call counting, and the endpoint override applied
+func LoadConfig(ctx context.Context, region string, optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+	opts := append([]func(*config.LoadOptions) error{
+		config.WithRegion(region),
+		config.WithRetryer(newAdaptiveRetryer),
+		config.WithAPIOptions([]func(*middleware.Stack) error{addAPILimitMiddleware}),
+	}, EndpointOptions()...)
+	opts = append(opts, optFns...)
+
+	return config.LoadDefaultConfig(ctx, opts...)
+}
+
+// newAdaptiveRetryer creates a retryer that backs off and slows the request rate on throttling
+func newAdaptiveRetryer() aws.Retryer {
+	return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+		o.StandardOptions = append(o.StandardOptions, func(so *retry.StandardOptions) {
+			so.MaxAttempts = apiMaxAttempts
+		})
+	})
+}
+
+// addAPILimitMiddleware applies the shared limits to every attempt and counts the calls,
+// failures, and throttles per service, operation, and region
+func addAPILimitMiddleware(stack *middleware.Stack) error {
+	// Count every call, and the calls that still fail once retries are exhausted
+	err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("IdledAPICallCount",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			if err != nil {
+				apiErrorCount.Add(1)
+			}
+			recordAPICall(ctx, func(stat *models.APICallStat) {
+				stat.Calls++
+				if err != nil {
+					stat.Errors++
+				}
+			})
+			return out, metadata, err
+		}), middleware.After)
+	if err != nil {
+		return fmt.Errorf("error adding API call count middleware: %w", err)
+	}
+
+	// Limit each attempt, so that retries are limited too
+	limit := middleware.FinalizeMiddlewareFunc("IdledAPILimit",
+		func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			if slots := apiSlots; slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, ctx.Err()
+				}
+			}
+			if limiter := apiLimiter; limiter != nil {
+				if err := limiter.Wait(ctx); err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+				}
+			}
+
+			out, metadata, err := next.HandleFinalize(ctx, in)
+			if err != nil && retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary {
+				apiThrottleCount.Add(1)
+				recordAPICall(ctx, func(stat *models.APICallStat) { stat.Throttles++ })
+			}
+			return out, metadata, err
+		})
+
+	// The retry middleware is absent for a few internal operations
+	if _, found := stack.Finalize.Get("Retry"); found {
+		return stack.Finalize.Insert(limit, "Retry", middleware.After)
+	}
+	return stack.Finalize.Add(limit, middleware.After)
+}
@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestSetMaxRetries(t *testing.T) {
+	saved := apiMaxAttempts
+	t.Cleanup(func() { apiMaxAttempts = saved })
+
+	tests := []struct {
+		retries      int
+		wantAttempts int
+	}{
+		{retries: DefaultMaxRetries, wantAttempts: 6},
+		{retries: 0, wantAttempts: 1},
+		{retries: -1, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		SetMaxRetries(tt.retries)
+		if got := newAdaptiveRetryer().MaxAttempts(); got != tt.wantAttempts {
+			t.Errorf("MaxAttempts() with %d retries = %d, want %d", tt.retries, got, tt.wantAttempts)
+		}
+	}
+
+	SetMaxRetries(2)
+	if got := minMaxAttempts(10); got != 10 {
+		t.Errorf("minMaxAttempts(10) = %d, want 10", got)
+	}
+	if got := minMaxAttempts(1); got != 3 {
+		t.Errorf("minMaxAttempts(1) = %d, want the configured 3", got)
+	}
+}
+
+func TestScanCompletesWhenThrottled(t *testing.T) {
+	tests := []struct {
+		name string
+		scan func(cfg aws.Config) error
+	}{
+		{
+			name: "Elastic IPs",
+			scan: func(cfg aws.Config) error {
+				client := NewEIPClient(cfg)
+				client.pricing = newTestPricing()
+				_, err := client.GetUnattachedEIPs(context.Background())
+				return err
+			},
+		},
+		{
+			name: "EBS volumes",
+			scan: func(cfg aws.Config) error {
+				client := NewEBSClient(cfg)
+				client.pricing = newTestPricing()
+				_, err := client.GetAvailableVolumes(context.Background())
+				return err
+			},
+		},
+		{
+			name: "EC2 instances",
+			scan: func(cfg aws.Config) error {
+				client := NewEC2Client(cfg)
+				client.pricing = newTestPricing()
+				_, err := client.GetStoppedInstances(context.Background())
+				return err
+			},
+		},
+	}
+
+	// Fewer throttles than the retries of a single call
+	const throttled = DefaultMaxRetries - 1
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, cfg := newEC2Server(t, throttled)
+
+			if err := tt.scan(cfg); err != nil {
+				t.Fatalf("scan error = %v, want the throttled calls retried", err)
+			}
+			if server.calls() <= throttled {
+				t.Errorf("server received %d requests, want more than the %d throttled", server.calls(), throttled)
+			}
+			var throttles int64
+			for _, stat := range APICallStats() {
+				throttles += stat.Throttles
+			}
+			if throttles != throttled {
+				t.Errorf("counted %d throttles, want %d", throttles, throttled)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	cftypes "github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/progress"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// CloudFrontHomeRegions are the regions CloudFront publishes its CloudWatch metrics in, per
+// partition. CloudFront is not offered in GovCloud.
+var CloudFrontHomeRegions = map[string]string{
+	utils.PartitionAWS:      "us-east-1",
+	utils.PartitionAWSChina: "cn-northwest-1",
+}
+
+const (
+	cloudFrontCheckPeriodDays = 30
+	cloudFrontNamespace       = "AWS/CloudFront"
+	cloudFrontMetricRequests  = "Requests"
+)
+
+// CloudFrontScanner contains the AWS clients needed for scanning CloudFront distributions
+type CloudFrontScanner struct {
+	CloudFrontClient cloudfront.ListDistributionsAPIClient
+	CWClient         cloudWatchMetricsAPI // Must be configured for the home region of the partition
+	Progress         progress.Func        // Receives the checked distribution count, nil for none
+}
+
+// NewCloudFrontScanner creates a new CloudFrontScanner. CloudFront is a global service, so
+// cfg should use the region of CloudFrontHomeRegions, where the distribution metrics are published.
+func NewCloudFrontScanner(cfg aws.Config) *CloudFrontScanner {
+	return &CloudFrontScanner{
+		CloudFrontClient: cloudfront.NewFromConfig(cfg),
+		CWClient:         cloudwatch.NewFromConfig(cfg),
+	}
+}
+
+// SetProgress sets the function receiving the distribution check progress
+func (s *CloudFrontScanner) SetProgress(f progress.Func) {
+	s.Progress = f
+}
+
+// GetIdleDistributions returns the distributions that are disabled or received no requests
+// over the last 30 days. Errors are collected per distribution, and the distributions whose
+// request metric could not be checked are left out.
+func (s *CloudFrontScanner) GetIdleDistributions(ctx context.Context) ([]models.DistributionInfo, []error) {
+	var summaries []cftypes.DistributionSummary
+	var errs []error
+
+	s.Progress.Report(0, 0, "Listing CloudFront distributions")
+	paginator := cloudfront.NewListDistributionsPaginator(s.CloudFrontClient, &cloudfront.ListDistributionsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing CloudFront distributions: %w", err))
+			break
+		}
+		if page.DistributionList != nil {
+			summaries = append(summaries, page.DistributionList.Items...)
+		}
+	}
+
+	var distributions []models.DistributionInfo
+	for i, summary := range summaries {
+		s.Progress.Report(i, len(summaries), "Checking CloudFront distributions")
+
+		info := models.DistributionInfo{
+			ID:               aws.ToString(summary.Id),
+			ARN:              aws.ToString(summary.ARN),
+			DomainName:       aws.ToString(summary.DomainName),
+			Enabled:          aws.ToBool(summary.Enabled),
+			Status:           aws.ToString(summary.Status),
+			LastModifiedTime: aws.ToTime(summary.LastModifiedTime),
+		}
+		if summary.Aliases != nil {
+			info.Aliases = summary.Aliases.Items
+		}
+		if summary.Origins != nil {
+			for _, origin := range summary.Origins.Items {
+				info.OriginDomains = append(info.OriginDomains, aws.ToString(origin.DomainName))
+			}
+		}
+
+		// A disabled distribution serves no requests, so its metric is not checked
+		if !info.Enabled {
+			info.IdleReason = "Disabled"
+			distributions = append(distributions, info)
+			continue
+		}
+
+		requests, err := s.getRequestCount(ctx, info.ID)
+		if err != nil {
+			errs = append(errs, err)
+			recordSkip("cloudfront", "global", info.ID, "metrics unavailable", err)
+			continue // Cannot determine idleness without the request metric
+		}
+		info.RequestCount = &requests
+		if requests == 0 {
+			info.IdleReason = fmt.Sprintf("No requests (%dd)", cloudFrontCheckPeriodDays)
+			distributions = append(distributions, info)
+		}
+	}
+	s.Progress.Report(len(summaries), len(summaries), "")
+
+	return distributions, errs
+}
+
+// getRequestCount returns the total requests of a distribution over the check period.
+// CloudFront metrics are global and published with the Region=Global dimension.
+func (s *CloudFrontScanner) getRequestCount(ctx context.Context, distributionID string) (float64, error) {
+	requests, err := getMetricsSum(ctx, s.CWClient, cloudFrontNamespace, []string{cloudFrontMetricRequests}, cloudFrontCheckPeriodDays,
+		cloudwatchutil.Dimension("DistributionId", distributionID), cloudwatchutil.Dimension("Region", "Global"))
+	if err != nil {
+		return 0, fmt.Errorf("error getting the requests of distribution %s: %w", distributionID, err)
+	}
+	return requests, nil
+}
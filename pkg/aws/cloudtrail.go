@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailtypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// cloudTrailLookbackDays is how far back CloudTrail event history is retained
+const cloudTrailLookbackDays = 90
+
+// lookupLatestEvent returns the most recent event with the given name about a resource, or nil
+// if none is found within the CloudTrail event history
+func lookupLatestEvent(ctx context.Context, client cloudtrail.LookupEventsAPIClient, resourceName, eventName string) (*cloudtrailtypes.Event, error) {
+	input := &cloudtrail.LookupEventsInput{
+		LookupAttributes: []cloudtrailtypes.LookupAttribute{
+			{
+				AttributeKey:   cloudtrailtypes.LookupAttributeKeyResourceName,
+				AttributeValue: aws.String(resourceName),
+			},
+		},
+		StartTime: aws.Time(time.Now().AddDate(0, 0, -cloudTrailLookbackDays)),
+		EndTime:   aws.Time(time.Now()),
+	}
+
+	// Events are returned newest first, so the first match is the most recent one
+	paginator := cloudtrail.NewLookupEventsPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up CloudTrail events: %w", err)
+		}
+
+		for _, event := range page.Events {
+			if aws.ToString(event.EventName) == eventName && event.EventTime != nil {
+				return &event, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
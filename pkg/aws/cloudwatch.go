@@ -0,0 +1,278 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+const (
+	// DefaultCloudWatchAlarmIdleDays is the default number of days in INSUFFICIENT_DATA before an alarm is idle
+	DefaultCloudWatchAlarmIdleDays = 30
+
+	// DefaultCloudWatchDashboardIdleDays is the default number of days without a modification before a dashboard is idle
+	DefaultCloudWatchDashboardIdleDays = 90
+
+	// cloudWatchHighResolutionPeriod is the period, in seconds, under which an alarm is high resolution
+	cloudWatchHighResolutionPeriod = 60
+
+	// cloudWatchAnomalyDetectionMetrics is the number of metrics billed for each metric of an
+	// anomaly detection alarm: the metric and the upper and lower bounds of the band
+	cloudWatchAnomalyDetectionMetrics = 3
+)
+
+// cloudWatchAPI is the CloudWatch API used by CloudWatchScanner
+type cloudWatchAPI interface {
+	cloudwatch.DescribeAlarmsAPIClient
+	cloudwatch.ListDashboardsAPIClient
+	ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error)
+}
+
+// CloudWatchScanner contains the AWS client needed for scanning CloudWatch alarms and dashboards
+type CloudWatchScanner struct {
+	Client                 cloudWatchAPI
+	Region                 string
+	AlarmIdleThreshold     int  // in days
+	DashboardIdleThreshold int  // in days
+	CheckMetrics           bool // Whether to check that the metrics of the alarms still exist
+}
+
+// NewCloudWatchScanner creates a new CloudWatchScanner for a given region
+func NewCloudWatchScanner(cfg aws.Config) *CloudWatchScanner {
+	return &CloudWatchScanner{
+		Client:                 cloudwatch.NewFromConfig(cfg),
+		Region:                 cfg.Region,
+		AlarmIdleThreshold:     DefaultCloudWatchAlarmIdleDays,
+		DashboardIdleThreshold: DefaultCloudWatchDashboardIdleDays,
+	}
+}
+
+// SetIdleThresholds sets the days in INSUFFICIENT_DATA before an alarm is considered idle, and
+// the days without a modification before a dashboard is considered idle
+func (s *CloudWatchScanner) SetIdleThresholds(alarmDays, dashboardDays int) {
+	s.AlarmIdleThreshold = alarmDays
+	s.DashboardIdleThreshold = dashboardDays
+}
+
+// SetCheckMetrics enables the ListMetrics check of the metrics watched by the alarms, which can
+// be slow in accounts with many metrics
+func (s *CloudWatchScanner) SetCheckMetrics(enabled bool) {
+	s.CheckMetrics = enabled
+}
+
+// GetIdleAlarms returns the alarms in INSUFFICIENT_DATA for at least AlarmIdleThreshold days
+// and, with CheckMetrics, the metric alarms whose metric no longer exists. Alarms whose metric
+// could not be checked are reported as errors.
+func (s *CloudWatchScanner) GetIdleAlarms(ctx context.Context) ([]models.CloudWatchAlarmInfo, []error) {
+	var idle []models.CloudWatchAlarmInfo
+	var scanErrs []error
+
+	// Alarms of the same metric share a ListMetrics call
+	metricExists := make(map[string]bool)
+
+	paginator := cloudwatch.NewDescribeAlarmsPaginator(s.Client, &cloudwatch.DescribeAlarmsInput{
+		AlarmTypes: []cwtypes.AlarmType{cwtypes.AlarmTypeMetricAlarm, cwtypes.AlarmTypeCompositeAlarm},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			scanErrs = append(scanErrs, fmt.Errorf("error describing CloudWatch alarms in region %s: %w", s.Region, err))
+			break
+		}
+
+		for _, alarm := range page.MetricAlarms {
+			info := s.metricAlarmInfo(alarm)
+			if s.CheckMetrics {
+				missing, err := s.hasMissingMetric(ctx, alarm, metricExists)
+				if err != nil {
+					scanErrs = append(scanErrs, fmt.Errorf("error checking the metrics of alarm %s: %w", info.Name, err))
+				}
+				info.MetricMissing = missing
+			}
+			if s.checkAlarm(&info) {
+				idle = append(idle, info)
+			}
+		}
+
+		for _, alarm := range page.CompositeAlarms {
+			info := models.CloudWatchAlarmInfo{
+				Name:                 aws.ToString(alarm.AlarmName),
+				ARN:                  aws.ToString(alarm.AlarmArn),
+				Region:               s.Region,
+				Type:                 models.CloudWatchAlarmComposite,
+				StateValue:           string(alarm.StateValue),
+				StateSince:           alarmStateSince(alarm.StateTransitionedTimestamp, alarm.StateUpdatedTimestamp),
+				ActionsEnabled:       aws.ToBool(alarm.ActionsEnabled),
+				EstimatedMonthlyCost: pricing.CloudWatchCompositeAlarmMonthlyPrice,
+			}
+			if s.checkAlarm(&info) {
+				idle = append(idle, info)
+			}
+		}
+	}
+
+	return idle, scanErrs
+}
+
+// metricAlarmInfo returns the info of a metric alarm, with its monthly cost
+func (s *CloudWatchScanner) metricAlarmInfo(alarm cwtypes.MetricAlarm) models.CloudWatchAlarmInfo {
+	info := models.CloudWatchAlarmInfo{
+		Name:           aws.ToString(alarm.AlarmName),
+		ARN:            aws.ToString(alarm.AlarmArn),
+		Region:         s.Region,
+		Type:           models.CloudWatchAlarmMetric,
+		Namespace:      aws.ToString(alarm.Namespace),
+		MetricName:     aws.ToString(alarm.MetricName),
+		StateValue:     string(alarm.StateValue),
+		StateSince:     alarmStateSince(alarm.StateTransitionedTimestamp, alarm.StateUpdatedTimestamp),
+		ActionsEnabled: aws.ToBool(alarm.ActionsEnabled),
+	}
+
+	// A single metric alarm watches one metric, a metric math alarm each metric of its queries
+	metrics, period := 1, aws.ToInt32(alarm.Period)
+	if len(alarm.Metrics) > 0 {
+		metrics = 0
+		for _, query := range alarm.Metrics {
+			if query.MetricStat != nil {
+				metrics++
+				period = aws.ToInt32(query.MetricStat.Period)
+			}
+		}
+	}
+	if alarm.ThresholdMetricId != nil {
+		metrics *= cloudWatchAnomalyDetectionMetrics
+	}
+	price := pricing.CloudWatchStandardAlarmMonthlyPrice
+	if period > 0 && period < cloudWatchHighResolutionPeriod {
+		price = pricing.CloudWatchHighResolutionAlarmMonthlyPrice
+	}
+	info.EstimatedMonthlyCost = float64(max(metrics, 1)) * price
+
+	return info
+}
+
+// checkAlarm sets the idle days and reason of an alarm, and reports whether it is idle: in
+// INSUFFICIENT_DATA for at least the idle threshold, or watching a metric that no longer exists
+func (s *CloudWatchScanner) checkAlarm(info *models.CloudWatchAlarmInfo) bool {
+	if !info.StateSince.IsZero() {
+		info.IdleDays = utils.CalculateElapsedDays(info.StateSince)
+	}
+
+	var reasons []string
+	if info.StateValue == string(cwtypes.StateValueInsufficientData) && info.IdleDays >= s.AlarmIdleThreshold {
+		reasons = append(reasons, fmt.Sprintf("INSUFFICIENT_DATA for %d days", info.IdleDays))
+	}
+	if info.MetricMissing {
+		reasons = append(reasons, "Metric no longer exists")
+	}
+	info.IdleReason = strings.Join(reasons, "; ")
+
+	return len(reasons) > 0
+}
+
+// alarmStateSince returns when an alarm entered its current state. The state updated timestamp
+// also changes with the state reason, so it is only used when the transition time is missing.
+func alarmStateSince(transitioned, updated *time.Time) time.Time {
+	if transitioned != nil {
+		return *transitioned
+	}
+	return aws.ToTime(updated)
+}
+
+// hasMissingMetric reports whether a metric watched by an alarm no longer exists. ListMetrics
+// only returns metrics with data points in the last two weeks.
+func (s *CloudWatchScanner) hasMissingMetric(ctx context.Context, alarm cwtypes.MetricAlarm, metricExists map[string]bool) (bool, error) {
+	var metrics []cwtypes.Metric
+	if alarm.MetricName != nil {
+		metrics = append(metrics, cwtypes.Metric{Namespace: alarm.Namespace, MetricName: alarm.MetricName, Dimensions: alarm.Dimensions})
+	}
+	for _, query := range alarm.Metrics {
+		if query.MetricStat != nil && query.MetricStat.Metric != nil {
+			metrics = append(metrics, *query.MetricStat.Metric)
+		}
+	}
+
+	for _, metric := range metrics {
+		key := metricKey(metric)
+		exists, checked := metricExists[key]
+		if !checked {
+			var err error
+			exists, err = s.metricExists(ctx, metric)
+			if err != nil {
+				return false, err
+			}
+			metricExists[key] = exists
+		}
+		if !exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// metricExists reports whether ListMetrics returns a metric with the name and dimensions of metric
+func (s *CloudWatchScanner) metricExists(ctx context.Context, metric cwtypes.Metric) (bool, error) {
+	input := &cloudwatch.ListMetricsInput{
+		Namespace:  metric.Namespace,
+		MetricName: metric.MetricName,
+	}
+	for _, dimension := range metric.Dimensions {
+		input.Dimensions = append(input.Dimensions, cwtypes.DimensionFilter{Name: dimension.Name, Value: dimension.Value})
+	}
+
+	output, err := s.Client.ListMetrics(ctx, input)
+	if err != nil {
+		return false, err
+	}
+	return len(output.Metrics) > 0, nil
+}
+
+// metricKey identifies a metric by its namespace, name, and sorted dimensions
+func metricKey(metric cwtypes.Metric) string {
+	dimensions := make([]string, 0, len(metric.Dimensions))
+	for _, dimension := range metric.Dimensions {
+		dimensions = append(dimensions, aws.ToString(dimension.Name)+"="+aws.ToString(dimension.Value))
+	}
+	sort.Strings(dimensions)
+	return aws.ToString(metric.Namespace) + "/" + aws.ToString(metric.MetricName) + "/" + strings.Join(dimensions, ",")
+}
+
+// GetDashboards returns every dashboard of the region, flagging those not modified within
+// DashboardIdleThreshold days as idle
+func (s *CloudWatchScanner) GetDashboards(ctx context.Context) ([]models.CloudWatchDashboardInfo, error) {
+	var dashboards []models.CloudWatchDashboardInfo
+
+	paginator := cloudwatch.NewListDashboardsPaginator(s.Client, &cloudwatch.ListDashboardsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return dashboards, fmt.Errorf("error listing CloudWatch dashboards in region %s: %w", s.Region, err)
+		}
+
+		for _, entry := range page.DashboardEntries {
+			dashboard := models.CloudWatchDashboardInfo{
+				Name:                 aws.ToString(entry.DashboardName),
+				ARN:                  aws.ToString(entry.DashboardArn),
+				Region:               s.Region,
+				LastModified:         aws.ToTime(entry.LastModified),
+				SizeBytes:            aws.ToInt64(entry.Size),
+				EstimatedMonthlyCost: pricing.CloudWatchDashboardMonthlyPrice,
+			}
+			dashboard.IdleDays = utils.CalculateElapsedDays(dashboard.LastModified)
+			dashboard.IsIdle = dashboard.IdleDays >= s.DashboardIdleThreshold
+			dashboards = append(dashboards, dashboard)
+		}
+	}
+
+	return dashboards, nil
+}
@@ -2,19 +2,31 @@ package aws
 
 import (
 	"context"
-	"fmt"
-	"sync"
+	"log/slog"
+	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/configservice"
 	"github.com/aws/aws-sdk-go-v2/service/configservice/types"
 	"github.com/younsl/idled/internal/models"
 )
 
+// configAPI is the AWS Config API used by ConfigClient
+type configAPI interface {
+	DescribeComplianceByConfigRule(ctx context.Context, params *configservice.DescribeComplianceByConfigRuleInput, optFns ...func(*configservice.Options)) (*configservice.DescribeComplianceByConfigRuleOutput, error)
+	DescribeConfigRuleEvaluationStatus(ctx context.Context, params *configservice.DescribeConfigRuleEvaluationStatusInput, optFns ...func(*configservice.Options)) (*configservice.DescribeConfigRuleEvaluationStatusOutput, error)
+	DescribeConfigRules(ctx context.Context, params *configservice.DescribeConfigRulesInput, optFns ...func(*configservice.Options)) (*configservice.DescribeConfigRulesOutput, error)
+	DescribeConfigurationRecorderStatus(ctx context.Context, params *configservice.DescribeConfigurationRecorderStatusInput, optFns ...func(*configservice.Options)) (*configservice.DescribeConfigurationRecorderStatusOutput, error)
+	DescribeConfigurationRecorders(ctx context.Context, params *configservice.DescribeConfigurationRecordersInput, optFns ...func(*configservice.Options)) (*configservice.DescribeConfigurationRecordersOutput, error)
+	DescribeDeliveryChannelStatus(ctx context.Context, params *configservice.DescribeDeliveryChannelStatusInput, optFns ...func(*configservice.Options)) (*configservice.DescribeDeliveryChannelStatusOutput, error)
+	DescribeDeliveryChannels(ctx context.Context, params *configservice.DescribeDeliveryChannelsInput, optFns ...func(*configservice.Options)) (*configservice.DescribeDeliveryChannelsOutput, error)
+	GetDiscoveredResourceCounts(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error)
+}
+
 // ConfigClient represents an AWS Config client
 type ConfigClient struct {
-	client *configservice.Client
+	client configAPI
 	region string
 }
 
@@ -52,23 +64,15 @@ type DeliveryChannel struct {
 }
 
 // NewConfigClient creates a new AWS Config client
-func NewConfigClient(region string) (*ConfigClient, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
-	}
-
+func NewConfigClient(cfg aws.Config) *ConfigClient {
 	return &ConfigClient{
-		client: configservice.NewFromConfig(cfg, func(o *configservice.Options) {
-			o.Region = region
-		}),
-		region: region,
-	}, nil
+		client: configservice.NewFromConfig(cfg),
+		region: cfg.Region,
+	}
 }
 
 // GetAllConfigRules returns a list of models.ConfigRuleInfo objects representing Config rules
-func (c *ConfigClient) GetAllConfigRules() ([]models.ConfigRuleInfo, error) {
-	ctx := context.Background()
+func (c *ConfigClient) GetAllConfigRules(ctx context.Context) ([]models.ConfigRuleInfo, error) {
 
 	input := &configservice.DescribeConfigRulesInput{}
 	resp, err := c.client.DescribeConfigRules(ctx, input)
@@ -79,6 +83,12 @@ func (c *ConfigClient) GetAllConfigRules() ([]models.ConfigRuleInfo, error) {
 	var configRules []models.ConfigRuleInfo
 	cutoffTime := time.Now().AddDate(0, 0, -90) // Default to 90 days for idle
 
+	// Resource counts reveal rules scoped to resource types without any resource in the region
+	resourceCounts, err := c.getDiscoveredResourceCounts(ctx)
+	if err != nil {
+		slog.Warn("Could not get AWS Config discovered resource counts, skipping the rule scope check", "region", c.region, "error", err)
+	}
+
 	for _, rule := range resp.ConfigRules {
 		// Initialize with default values
 		now := time.Now()
@@ -95,6 +105,13 @@ func (c *ConfigClient) GetAllConfigRules() ([]models.ConfigRuleInfo, error) {
 			IsCustom: rule.Source != nil && rule.Source.Owner != types.Owner("AWS"),
 		}
 
+		configRule.EvaluationMode = evaluationMode(rule.EvaluationModes)
+		if rule.Scope != nil {
+			configRule.ScopeResourceTypes = rule.Scope.ComplianceResourceTypes
+		}
+		// Without any recorded resource, the recorder is off rather than the scoped resources gone
+		configRule.NoResourcesInScope = len(resourceCounts) > 0 && noResourcesInScope(configRule.ScopeResourceTypes, resourceCounts)
+
 		// Set creation time pointer
 		configRule.CreatedTime = &createdTime
 		configRule.LastActivity = &lastActivity
@@ -140,22 +157,78 @@ func (c *ConfigClient) GetAllConfigRules() ([]models.ConfigRuleInfo, error) {
 				lastActivity = *status.LastFailedEvaluationTime
 				configRule.LastActivity = &lastActivity
 			}
+
+			// A rule is failing when its latest evaluation within the idle window failed
+			configRule.LastErrorCode = aws.ToString(status.LastErrorCode)
+			configRule.LastErrorMessage = aws.ToString(status.LastErrorMessage)
+			configRule.IsFailing = status.LastFailedEvaluationTime != nil &&
+				status.LastFailedEvaluationTime.After(cutoffTime) &&
+				(status.LastSuccessfulEvaluationTime == nil || status.LastFailedEvaluationTime.After(*status.LastSuccessfulEvaluationTime))
 		}
 
 		// Calculate idle days
 		configRule.IdleDays = int(time.Since(lastActivity).Hours() / 24)
 		configRule.IsIdle = lastActivity.Before(cutoffTime)
 
-		// 모든 규칙을 추가 (유휴 상태 필터링 제거)
+		// Add every rule, idle or not
 		configRules = append(configRules, configRule)
 	}
 
 	return configRules, nil
 }
 
+// evaluationMode returns the evaluation modes of a rule, DETECTIVE if none is set
+func evaluationMode(modes []types.EvaluationModeConfiguration) string {
+	var names []string
+	for _, mode := range modes {
+		names = append(names, string(mode.Mode))
+	}
+	if len(names) == 0 {
+		return string(types.EvaluationModeDetective)
+	}
+	return strings.Join(names, ",")
+}
+
+// noResourcesInScope reports whether a rule is scoped to resource types of which AWS Config
+// recorded no resource in the region. Rules without a resource type scope are never reported.
+func noResourcesInScope(resourceTypes []string, resourceCounts map[string]int64) bool {
+	if len(resourceTypes) == 0 {
+		return false
+	}
+	for _, resourceType := range resourceTypes {
+		if resourceCounts[resourceType] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// getDiscoveredResourceCounts returns the number of resources recorded by AWS Config per resource type
+func (c *ConfigClient) getDiscoveredResourceCounts(ctx context.Context) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	input := &configservice.GetDiscoveredResourceCountsInput{}
+
+	for {
+		resp, err := c.client.GetDiscoveredResourceCounts(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resourceCount := range resp.ResourceCounts {
+			counts[string(resourceCount.ResourceType)] = resourceCount.Count
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+
+	return counts, nil
+}
+
 // GetAllConfigRecorders returns a list of models.ConfigRecorderInfo objects representing Config recorders
-func (c *ConfigClient) GetAllConfigRecorders() ([]models.ConfigRecorderInfo, error) {
-	ctx := context.Background()
+func (c *ConfigClient) GetAllConfigRecorders(ctx context.Context) ([]models.ConfigRecorderInfo, error) {
 	var recorders []models.ConfigRecorderInfo
 
 	input := &configservice.DescribeConfigurationRecordersInput{}
@@ -200,7 +273,8 @@ func (c *ConfigClient) GetAllConfigRecorders() ([]models.ConfigRecorderInfo, err
 		// Get status details if available
 		var lastActivity time.Time
 		lastActivitySet := false
-		if status, ok := statusMap[*recorder.Name]; ok {
+		status, hasStatus := statusMap[*recorder.Name]
+		if hasStatus {
 			if status.LastStartTime != nil {
 				lastActivity = *status.LastStartTime
 				lastActivitySet = true
@@ -215,6 +289,18 @@ func (c *ConfigClient) GetAllConfigRecorders() ([]models.ConfigRecorderInfo, err
 			configRecorder.IsRecording = status.Recording
 		}
 
+		// A recorder that is not recording and was never started or stopped is the most likely
+		// to be abandoned. Its last status change is the best activity time left, if any.
+		if !lastActivitySet && !configRecorder.IsRecording {
+			if hasStatus && status.LastStatusChangeTime != nil {
+				lastActivity = *status.LastStatusChangeTime
+				lastActivitySet = true
+			} else {
+				configRecorder.NeverActive = true
+				configRecorder.IsIdle = true
+			}
+		}
+
 		// Set last activity and idle status if we have timing data
 		if lastActivitySet {
 			activityTime := lastActivity
@@ -223,7 +309,7 @@ func (c *ConfigClient) GetAllConfigRecorders() ([]models.ConfigRecorderInfo, err
 			configRecorder.IsIdle = configRecorder.IdleDays > 90
 		}
 
-		// 모든 레코더 추가 (유휴 상태 필터링 제거)
+		// Add every recorder, idle or not
 		recorders = append(recorders, configRecorder)
 	}
 
@@ -231,8 +317,7 @@ func (c *ConfigClient) GetAllConfigRecorders() ([]models.ConfigRecorderInfo, err
 }
 
 // GetAllConfigDeliveryChannels returns a list of models.ConfigDeliveryChannelInfo objects
-func (c *ConfigClient) GetAllConfigDeliveryChannels() ([]models.ConfigDeliveryChannelInfo, error) {
-	ctx := context.Background()
+func (c *ConfigClient) GetAllConfigDeliveryChannels(ctx context.Context) ([]models.ConfigDeliveryChannelInfo, error) {
 	var channels []models.ConfigDeliveryChannelInfo
 
 	input := &configservice.DescribeDeliveryChannelsInput{}
@@ -280,6 +365,14 @@ func (c *ConfigClient) GetAllConfigDeliveryChannels() ([]models.ConfigDeliveryCh
 				lastActivitySet = true
 			}
 
+			if statusResp.DeliveryChannelsStatus[0].ConfigSnapshotDeliveryInfo != nil &&
+				statusResp.DeliveryChannelsStatus[0].ConfigSnapshotDeliveryInfo.LastSuccessfulTime != nil &&
+				(statusResp.DeliveryChannelsStatus[0].ConfigSnapshotDeliveryInfo.LastSuccessfulTime.After(lastActivity) ||
+					!lastActivitySet) {
+				lastActivity = *statusResp.DeliveryChannelsStatus[0].ConfigSnapshotDeliveryInfo.LastSuccessfulTime
+				lastActivitySet = true
+			}
+
 			if statusResp.DeliveryChannelsStatus[0].ConfigStreamDeliveryInfo != nil &&
 				statusResp.DeliveryChannelsStatus[0].ConfigStreamDeliveryInfo.LastStatusChangeTime != nil &&
 				(statusResp.DeliveryChannelsStatus[0].ConfigStreamDeliveryInfo.LastStatusChangeTime.After(lastActivity) ||
@@ -294,87 +387,16 @@ func (c *ConfigClient) GetAllConfigDeliveryChannels() ([]models.ConfigDeliveryCh
 				deliveryChannel.LastActivity = &activityTime
 				deliveryChannel.IdleDays = int(time.Since(lastActivity).Hours() / 24)
 				deliveryChannel.IsIdle = deliveryChannel.IdleDays > 90 // Default 90 days for idle
+			} else {
+				// The status has no delivery at all, so nothing was ever delivered
+				deliveryChannel.NeverDelivered = true
+				deliveryChannel.IsIdle = true
 			}
 		}
 
-		// 모든 전송 채널 추가
+		// Add every delivery channel, idle or not
 		channels = append(channels, deliveryChannel)
 	}
 
 	return channels, nil
 }
-
-// GetAllConfigResources retrieves all AWS Config resources across all regions
-func GetAllConfigResources(regions []string, idleDays int) ([]models.ConfigRuleInfo, []models.ConfigRecorderInfo, []models.ConfigDeliveryChannelInfo, error) {
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var allRules []models.ConfigRuleInfo
-	var allRecorders []models.ConfigRecorderInfo
-	var allChannels []models.ConfigDeliveryChannelInfo
-	var errs []error
-
-	for _, region := range regions {
-		wg.Add(1)
-		go func(region string) {
-			defer wg.Done()
-
-			client, err := NewConfigClient(region)
-			if err != nil {
-				mu.Lock()
-				errs = append(errs, fmt.Errorf("failed to load AWS config for region %s: %w", region, err))
-				mu.Unlock()
-				return
-			}
-
-			// Get all Config rules
-			rules, err := client.GetAllConfigRules()
-			if err == nil {
-				mu.Lock()
-				allRules = append(allRules, rules...)
-				mu.Unlock()
-			} else {
-				mu.Lock()
-				errs = append(errs, err)
-				mu.Unlock()
-			}
-
-			// Get all Config recorders
-			recorders, err := client.GetAllConfigRecorders()
-			if err == nil {
-				mu.Lock()
-				allRecorders = append(allRecorders, recorders...)
-				mu.Unlock()
-			} else {
-				mu.Lock()
-				errs = append(errs, err)
-				mu.Unlock()
-			}
-
-			// Get all delivery channels
-			channels, err := client.GetAllConfigDeliveryChannels()
-			if err == nil {
-				mu.Lock()
-				allChannels = append(allChannels, channels...)
-				mu.Unlock()
-			} else {
-				mu.Lock()
-				errs = append(errs, err)
-				mu.Unlock()
-			}
-		}(region)
-	}
-
-	wg.Wait()
-
-	// If we got some results but had some errors, we'll still return what we found
-	if len(allRules) > 0 || len(allRecorders) > 0 || len(allChannels) > 0 {
-		return allRules, allRecorders, allChannels, nil
-	}
-
-	// If we have no results and have errors, return the first error
-	if len(errs) > 0 {
-		return nil, nil, nil, errs[0]
-	}
-
-	return allRules, allRecorders, allChannels, nil
-}
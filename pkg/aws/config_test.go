@@ -0,0 +1,233 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	configtypes "github.com/aws/aws-sdk-go-v2/service/configservice/types"
+
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+// daysAgo returns the time the given number of days ago
+func daysAgo(days int) *time.Time {
+	t := time.Now().AddDate(0, 0, -days)
+	return &t
+}
+
+func TestGetAllConfigRecorders(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          *configtypes.ConfigurationRecorderStatus // nil for no status
+		wantRecording   bool
+		wantIdle        bool
+		wantNeverActive bool
+		wantIdleDays    int
+		wantActivity    bool
+	}{
+		{
+			name:          "recording since a recent start",
+			status:        &configtypes.ConfigurationRecorderStatus{Recording: true, LastStartTime: daysAgo(5)},
+			wantRecording: true, wantIdleDays: 5, wantActivity: true,
+		},
+		{
+			name:     "not recording since a stop long ago",
+			status:   &configtypes.ConfigurationRecorderStatus{LastStartTime: daysAgo(200), LastStopTime: daysAgo(120)},
+			wantIdle: true, wantIdleDays: 120, wantActivity: true,
+		},
+		{
+			name:         "not recording since a recent stop",
+			status:       &configtypes.ConfigurationRecorderStatus{LastStartTime: daysAgo(200), LastStopTime: daysAgo(30)},
+			wantIdleDays: 30, wantActivity: true,
+		},
+		{
+			name:         "not recording and never started, with a status change",
+			status:       &configtypes.ConfigurationRecorderStatus{LastStatusChangeTime: daysAgo(10)},
+			wantIdleDays: 10, wantActivity: true,
+		},
+		{
+			name:     "not recording and never started, with an old status change",
+			status:   &configtypes.ConfigurationRecorderStatus{LastStatusChangeTime: daysAgo(100)},
+			wantIdle: true, wantIdleDays: 100, wantActivity: true,
+		},
+		{
+			name:     "not recording without any status time",
+			status:   &configtypes.ConfigurationRecorderStatus{},
+			wantIdle: true, wantNeverActive: true,
+		},
+		{
+			name:     "no status",
+			wantIdle: true, wantNeverActive: true,
+		},
+		{
+			name:          "recording without any status time",
+			status:        &configtypes.ConfigurationRecorderStatus{Recording: true},
+			wantRecording: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.ConfigService{
+				DescribeConfigurationRecordersFunc: func(ctx context.Context, params *configservice.DescribeConfigurationRecordersInput) (*configservice.DescribeConfigurationRecordersOutput, error) {
+					return &configservice.DescribeConfigurationRecordersOutput{ConfigurationRecorders: []configtypes.ConfigurationRecorder{{
+						Name:           aws.String("default"),
+						RecordingGroup: &configtypes.RecordingGroup{AllSupported: true},
+					}}}, nil
+				},
+				DescribeConfigurationRecorderStatusFunc: func(ctx context.Context, params *configservice.DescribeConfigurationRecorderStatusInput) (*configservice.DescribeConfigurationRecorderStatusOutput, error) {
+					output := &configservice.DescribeConfigurationRecorderStatusOutput{}
+					if tt.status != nil {
+						status := *tt.status
+						status.Name = aws.String("default")
+						output.ConfigurationRecordersStatus = []configtypes.ConfigurationRecorderStatus{status}
+					}
+					return output, nil
+				},
+			}
+
+			recorders, err := (&ConfigClient{client: client, region: "us-east-1"}).GetAllConfigRecorders(context.Background())
+			if err != nil || len(recorders) != 1 {
+				t.Fatalf("GetAllConfigRecorders() = %v, %v, want one recorder", recorders, err)
+			}
+			recorder := recorders[0]
+			if recorder.IsRecording != tt.wantRecording || recorder.IsIdle != tt.wantIdle || recorder.NeverActive != tt.wantNeverActive {
+				t.Errorf("recording, idle, never active = %v, %v, %v, want %v, %v, %v", recorder.IsRecording, recorder.IsIdle,
+					recorder.NeverActive, tt.wantRecording, tt.wantIdle, tt.wantNeverActive)
+			}
+			if recorder.IdleDays != tt.wantIdleDays || (recorder.LastActivity != nil) != tt.wantActivity {
+				t.Errorf("IdleDays = %d, LastActivity = %v, want %d days, activity %v", recorder.IdleDays, recorder.LastActivity,
+					tt.wantIdleDays, tt.wantActivity)
+			}
+			if !recorder.AllResourceTypes {
+				t.Error("AllResourceTypes = false, want true")
+			}
+		})
+	}
+}
+
+func TestGetAllConfigRecordersStatusError(t *testing.T) {
+	denied := errors.New("AccessDeniedException: not authorized")
+	client := &mocks.ConfigService{
+		DescribeConfigurationRecorderStatusFunc: func(ctx context.Context, params *configservice.DescribeConfigurationRecorderStatusInput) (*configservice.DescribeConfigurationRecorderStatusOutput, error) {
+			return nil, denied
+		},
+	}
+
+	recorders, err := (&ConfigClient{client: client, region: "us-east-1"}).GetAllConfigRecorders(context.Background())
+	if !errors.Is(err, denied) || recorders != nil {
+		t.Errorf("GetAllConfigRecorders() = %v, %v, want the status error", recorders, err)
+	}
+}
+
+func TestGetAllConfigDeliveryChannels(t *testing.T) {
+	failure := configtypes.DeliveryStatusFailure
+	tests := []struct {
+		name               string
+		status             *configtypes.DeliveryChannelStatus // nil for no status
+		statusErr          error
+		wantIdle           bool
+		wantNeverDelivered bool
+		wantIdleDays       int
+	}{
+		{
+			name: "recent delivery",
+			status: &configtypes.DeliveryChannelStatus{
+				ConfigHistoryDeliveryInfo:  &configtypes.ConfigExportDeliveryInfo{LastSuccessfulTime: daysAgo(200)},
+				ConfigSnapshotDeliveryInfo: &configtypes.ConfigExportDeliveryInfo{LastSuccessfulTime: daysAgo(1)},
+			},
+			wantIdleDays: 1,
+		},
+		{
+			name: "failed last delivery after an old success",
+			status: &configtypes.DeliveryChannelStatus{
+				ConfigHistoryDeliveryInfo: &configtypes.ConfigExportDeliveryInfo{
+					LastStatus:         failure,
+					LastAttemptTime:    daysAgo(1),
+					LastSuccessfulTime: daysAgo(120),
+				},
+			},
+			wantIdle: true, wantIdleDays: 120,
+		},
+		{
+			name: "failed delivery without any success",
+			status: &configtypes.DeliveryChannelStatus{
+				ConfigHistoryDeliveryInfo: &configtypes.ConfigExportDeliveryInfo{LastStatus: failure, LastAttemptTime: daysAgo(1)},
+			},
+			wantIdle: true, wantNeverDelivered: true,
+		},
+		{
+			name: "stream status change",
+			status: &configtypes.DeliveryChannelStatus{
+				ConfigStreamDeliveryInfo: &configtypes.ConfigStreamDeliveryInfo{LastStatusChangeTime: daysAgo(95)},
+			},
+			wantIdle: true, wantIdleDays: 95,
+		},
+		{
+			name:     "status without delivery info",
+			status:   &configtypes.DeliveryChannelStatus{},
+			wantIdle: true, wantNeverDelivered: true,
+		},
+		{
+			name: "no status",
+		},
+		{
+			name:      "status error",
+			statusErr: errors.New("ThrottlingException: rate exceeded"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.ConfigService{
+				DescribeDeliveryChannelsFunc: func(ctx context.Context, params *configservice.DescribeDeliveryChannelsInput) (*configservice.DescribeDeliveryChannelsOutput, error) {
+					return &configservice.DescribeDeliveryChannelsOutput{DeliveryChannels: []configtypes.DeliveryChannel{{
+						Name:         aws.String("default"),
+						S3BucketName: aws.String("config-bucket"),
+					}}}, nil
+				},
+				DescribeDeliveryChannelStatusFunc: func(ctx context.Context, params *configservice.DescribeDeliveryChannelStatusInput) (*configservice.DescribeDeliveryChannelStatusOutput, error) {
+					if tt.statusErr != nil {
+						return nil, tt.statusErr
+					}
+					output := &configservice.DescribeDeliveryChannelStatusOutput{}
+					if tt.status != nil {
+						output.DeliveryChannelsStatus = []configtypes.DeliveryChannelStatus{*tt.status}
+					}
+					return output, nil
+				},
+			}
+
+			channels, err := (&ConfigClient{client: client, region: "us-east-1"}).GetAllConfigDeliveryChannels(context.Background())
+			if err != nil || len(channels) != 1 {
+				t.Fatalf("GetAllConfigDeliveryChannels() = %v, %v, want one channel", channels, err)
+			}
+			channel := channels[0]
+			if channel.IsIdle != tt.wantIdle || channel.NeverDelivered != tt.wantNeverDelivered || channel.IdleDays != tt.wantIdleDays {
+				t.Errorf("idle, never delivered, idle days = %v, %v, %d, want %v, %v, %d", channel.IsIdle, channel.NeverDelivered,
+					channel.IdleDays, tt.wantIdle, tt.wantNeverDelivered, tt.wantIdleDays)
+			}
+			if channel.S3BucketName != "config-bucket" {
+				t.Errorf("S3BucketName = %q, want config-bucket", channel.S3BucketName)
+			}
+		})
+	}
+}
+
+func TestGetAllConfigDeliveryChannelsNone(t *testing.T) {
+	client := &mocks.ConfigService{
+		DescribeDeliveryChannelStatusFunc: func(ctx context.Context, params *configservice.DescribeDeliveryChannelStatusInput) (*configservice.DescribeDeliveryChannelStatusOutput, error) {
+			t.Error("DescribeDeliveryChannelStatus called without a delivery channel")
+			return &configservice.DescribeDeliveryChannelStatusOutput{}, nil
+		},
+	}
+
+	channels, err := (&ConfigClient{client: client, region: "us-east-1"}).GetAllConfigDeliveryChannels(context.Background())
+	if channels != nil || err != nil {
+		t.Errorf("GetAllConfigDeliveryChannels() = %v, %v, want no channel", channels, err)
+	}
+}
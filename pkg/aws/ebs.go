@@ -2,40 +2,137 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
 	"github.com/younsl/idled/pkg/pricing"
 	"github.com/younsl/idled/pkg/utils"
 )
 
+const (
+	// ebsMetricLookbackDays is the window used for volume IO metrics
+	ebsMetricLookbackDays = 30
+	// ebsMetricIntervalSeconds is the interval at which EBS publishes volume metrics
+	ebsMetricIntervalSeconds = 60
+	// ebsInstanceFilterBatchSize is the number of instance IDs passed per attachment filter
+	ebsInstanceFilterBatchSize = 100
+	// ebsSnapshotFilterBatchSize is the number of volume IDs passed per snapshot filter
+	ebsSnapshotFilterBatchSize = 100
+	// ebsSourceSnapshotBatchSize is the number of snapshot IDs described per call
+	ebsSourceSnapshotBatchSize = 100
+)
+
+// Tags that name what created a volume
+const (
+	backupSourceResourceTag = "aws:backup:source-resource"
+	pvcNameTag              = "kubernetes.io/created-for/pvc/name"
+	pvcNamespaceTag         = "kubernetes.io/created-for/pvc/namespace"
+	csiVolumeNameTag        = "CSIVolumeName"
+)
+
+// DefaultSnapshotRecencyDays is how recent a snapshot must be for a volume to count as snapshotted
+const DefaultSnapshotRecencyDays = 30
+
+// ebsAPI is the EC2 API used by EBSClient
+type ebsAPI interface {
+	ec2.DescribeInstancesAPIClient
+	ec2.DescribeSnapshotsAPIClient
+	ec2.DescribeVolumesAPIClient
+	DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)
+}
+
 // EBSClient struct for EBS client
 type EBSClient struct {
-	client *ec2.Client
-	region string
+	client                 ebsAPI
+	cwClient               cloudWatchMetricsAPI
+	trailClient            cloudtrail.LookupEventsAPIClient
+	region                 string
+	tagFilters             map[string]string
+	includeStoppedAttached bool
+	useCloudTrail          bool
+	snapshotRecencyDays    int    // Days within which a snapshot counts as recent
+	accountID              string // Account of the volume ARNs
+	pricing                *pricing.PricingService
 }
 
-// NewEBSClient creates a new EBSClient
-func NewEBSClient(region string) (*EBSClient, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("error loading AWS config: %w", err)
-	}
+// volumeIOMetrics holds the CloudWatch IO activity of a volume
+type volumeIOMetrics struct {
+	readOps         float64
+	writeOps        float64
+	idleTimePercent float64
+	lastIOTime      *time.Time
+	found           bool
+}
 
+// NewEBSClient creates a new EBSClient
+func NewEBSClient(cfg aws.Config) *EBSClient {
 	client := ec2.NewFromConfig(cfg)
 	return &EBSClient{
-		client: client,
-		region: region,
-	}, nil
+		client:              client,
+		cwClient:            cloudwatch.NewFromConfig(cfg),
+		trailClient:         cloudtrail.NewFromConfig(cfg),
+		region:              cfg.Region,
+		snapshotRecencyDays: DefaultSnapshotRecencyDays,
+		pricing:             pricing.Default(),
+	}
+}
+
+// SetTagFilters limits results to volumes carrying all of the given tags
+func (c *EBSClient) SetTagFilters(tags map[string]string) {
+	c.tagFilters = tags
 }
 
-// GetAvailableVolumes returns a list of all EBS volumes in Available state
-func (c *EBSClient) GetAvailableVolumes() ([]models.VolumeInfo, error) {
+// SetIncludeStoppedAttached also reports in-use volumes attached to stopped instances
+func (c *EBSClient) SetIncludeStoppedAttached(enabled bool) {
+	c.includeStoppedAttached = enabled
+}
+
+// SetUseCloudTrail enables the CloudTrail lookup of the instance each detached volume was
+// last attached to
+func (c *EBSClient) SetUseCloudTrail(enabled bool) {
+	c.useCloudTrail = enabled
+}
+
+// SetSnapshotRecencyDays sets the days within which a snapshot counts as recent
+func (c *EBSClient) SetSnapshotRecencyDays(days int) {
+	c.snapshotRecencyDays = days
+}
+
+// SetAccountID sets the account used to build the ARNs of the volumes, left empty if not set
+func (c *EBSClient) SetAccountID(accountID string) {
+	c.accountID = accountID
+}
+
+// SetPricingService sets the pricing service used to estimate volume costs
+func (c *EBSClient) SetPricingService(service *pricing.PricingService) {
+	c.pricing = service
+}
+
+// DeleteVolume deletes an EBS volume. The volume must be in the available state.
+func (c *EBSClient) DeleteVolume(ctx context.Context, volumeID string) error {
+	_, err := c.client.DeleteVolume(ctx, &ec2.DeleteVolumeInput{VolumeId: aws.String(volumeID)})
+	if err != nil {
+		return fmt.Errorf("failed to delete volume %s: %w", volumeID, err)
+	}
+	return nil
+}
+
+// GetAvailableVolumes returns a list of all EBS volumes in Available state, followed by
+// the volumes attached to stopped instances when enabled. If a listing fails part way, the
+// volumes already read are returned with the error.
+func (c *EBSClient) GetAvailableVolumes(ctx context.Context) ([]models.VolumeInfo, error) {
 	// Filter only volumes in 'available' state (unattached volumes)
 	filter := types.Filter{
 		Name:   aws.String("status"),
@@ -43,69 +140,454 @@ func (c *EBSClient) GetAvailableVolumes() ([]models.VolumeInfo, error) {
 	}
 
 	input := &ec2.DescribeVolumesInput{
-		Filters: []types.Filter{filter},
+		Filters: append([]types.Filter{filter}, ec2TagFilters(c.tagFilters)...),
 	}
 
-	result, err := c.client.DescribeVolumes(context.TODO(), input)
+	volumes := []models.VolumeInfo{}
+
+	var errs []error
+	paginator := ec2.NewDescribeVolumesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error querying EBS volumes: %w", err))
+			break
+		}
+
+		for _, volume := range page.Volumes {
+			if ctx.Err() != nil {
+				return volumes, ctx.Err()
+			}
+
+			// Get last attachment time
+			var lastAttachmentTime *time.Time
+			var elapsedDays int
+
+			if len(volume.Attachments) > 0 {
+				for _, attachment := range volume.Attachments {
+					if attachment.AttachTime != nil {
+						if lastAttachmentTime == nil || attachment.AttachTime.After(*lastAttachmentTime) {
+							lastAttachmentTime = attachment.AttachTime
+						}
+					}
+				}
+			}
+
+			// Calculate elapsed days if last attachment time is available
+			if lastAttachmentTime != nil {
+				elapsedDays = utils.CalculateElapsedDays(*lastAttachmentTime)
+			} else if volume.CreateTime != nil {
+				// If no attachment history, use creation time
+				lastAttachmentTime = volume.CreateTime
+				elapsedDays = utils.CalculateElapsedDays(*volume.CreateTime)
+			}
+
+			volumes = append(volumes, c.newVolumeInfo(ctx, volume, lastAttachmentTime, elapsedDays, ""))
+		}
+	}
+
+	if c.includeStoppedAttached {
+		attached, err := c.getStoppedAttachedVolumes(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		volumes = append(volumes, attached...)
+	}
+
+	// Snapshots are informational, so a failed lookup only leaves them unknown
+	if err := c.addSnapshotInfo(ctx, volumes); err != nil {
+		slog.Warn("Could not get EBS snapshots", "region", c.region, "error", err)
+	}
+	c.addSourceSnapshotInfo(ctx, volumes)
+	if c.useCloudTrail {
+		c.addLastAttachmentInfo(ctx, volumes)
+	}
+
+	return volumes, errors.Join(errs...)
+}
+
+// addSnapshotInfo sets the latest completed snapshot of each volume. Snapshots are filtered
+// server-side by volume ID, so accounts with many snapshots only return the relevant ones.
+func (c *EBSClient) addSnapshotInfo(ctx context.Context, volumes []models.VolumeInfo) error {
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	volumeIDs := make([]string, 0, len(volumes))
+	for _, volume := range volumes {
+		volumeIDs = append(volumeIDs, volume.VolumeID)
+	}
+
+	latestSnapshots := make(map[string]time.Time)
+	for _, batch := range batchStrings(volumeIDs, ebsSnapshotFilterBatchSize) {
+		input := &ec2.DescribeSnapshotsInput{
+			OwnerIds: []string{"self"},
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("volume-id"),
+					Values: batch,
+				},
+				{
+					Name:   aws.String("status"),
+					Values: []string{"completed"},
+				},
+			},
+		}
+
+		paginator := ec2.NewDescribeSnapshotsPaginator(c.client, input)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("error querying EBS snapshots: %w", err)
+			}
+
+			for _, snapshot := range page.Snapshots {
+				if snapshot.StartTime == nil {
+					continue
+				}
+				volumeID := aws.ToString(snapshot.VolumeId)
+				if latest, found := latestSnapshots[volumeID]; !found || snapshot.StartTime.After(latest) {
+					latestSnapshots[volumeID] = *snapshot.StartTime
+				}
+			}
+		}
+	}
+
+	recentSince := time.Now().AddDate(0, 0, -c.snapshotRecencyDays)
+	for i := range volumes {
+		volume := &volumes[i]
+		volume.HasSnapshotInfo = true
+		if latest, found := latestSnapshots[volume.VolumeID]; found {
+			volume.LatestSnapshotDate = &latest
+			volume.HasRecentSnapshot = latest.After(recentSince)
+		}
+	}
+
+	return nil
+}
+
+// addSourceSnapshotInfo sets the description of the snapshot each volume was created from. The
+// descriptions are informational, so snapshots that cannot be described only leave them empty.
+func (c *EBSClient) addSourceSnapshotInfo(ctx context.Context, volumes []models.VolumeInfo) {
+	var snapshotIDs []string
+	seen := make(map[string]bool)
+	for _, volume := range volumes {
+		if id := volume.SourceSnapshotID; id != "" && !seen[id] {
+			seen[id] = true
+			snapshotIDs = append(snapshotIDs, id)
+		}
+	}
+	if len(snapshotIDs) == 0 {
+		return
+	}
+
+	descriptions := make(map[string]string)
+	for _, batch := range batchStrings(snapshotIDs, ebsSourceSnapshotBatchSize) {
+		// A deleted or unshared snapshot fails the whole call, so the batch is described one by one
+		if err := c.describeSnapshots(ctx, batch, descriptions); err != nil {
+			for _, snapshotID := range batch {
+				if err := c.describeSnapshots(ctx, []string{snapshotID}, descriptions); err != nil {
+					slog.Debug("Could not describe the source snapshot of an EBS volume",
+						"snapshot", snapshotID, "region", c.region, "error", err)
+				}
+			}
+		}
+	}
+
+	for i := range volumes {
+		volumes[i].SourceSnapshotDesc = descriptions[volumes[i].SourceSnapshotID]
+	}
+}
+
+// describeSnapshots adds the descriptions of the given snapshots to descriptions
+func (c *EBSClient) describeSnapshots(ctx context.Context, snapshotIDs []string, descriptions map[string]string) error {
+	paginator := ec2.NewDescribeSnapshotsPaginator(c.client, &ec2.DescribeSnapshotsInput{SnapshotIds: snapshotIDs})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("error describing EBS snapshots: %w", err)
+		}
+		for _, snapshot := range page.Snapshots {
+			descriptions[aws.ToString(snapshot.SnapshotId)] = aws.ToString(snapshot.Description)
+		}
+	}
+	return nil
+}
+
+// addLastAttachmentInfo sets the instance each detached volume was last attached to from its
+// latest DetachVolume event. Volumes detached by an instance termination, or longer ago than
+// the CloudTrail event history, have none.
+func (c *EBSClient) addLastAttachmentInfo(ctx context.Context, volumes []models.VolumeInfo) {
+	for i := range volumes {
+		volume := &volumes[i]
+		if volume.AttachedInstanceID != "" || ctx.Err() != nil {
+			continue
+		}
+
+		event, err := lookupLatestEvent(ctx, c.trailClient, volume.VolumeID, "DetachVolume")
+		if err != nil {
+			slog.Warn("Could not look up CloudTrail detach event",
+				"volume", volume.VolumeID, "region", c.region, "error", err)
+			continue
+		}
+		if event == nil {
+			continue
+		}
+		volume.LastInstanceID = detachedInstanceID(aws.ToString(event.CloudTrailEvent))
+		volume.LastDetachTime = event.EventTime
+	}
+}
+
+// detachedInstanceID returns the instance of a DetachVolume CloudTrail event record. The
+// instance is optional in the request, but always part of the returned attachment.
+func detachedInstanceID(record string) string {
+	var event struct {
+		RequestParameters struct {
+			InstanceID string `json:"instanceId"`
+		} `json:"requestParameters"`
+		ResponseElements struct {
+			InstanceID string `json:"instanceId"`
+		} `json:"responseElements"`
+	}
+	if err := json.Unmarshal([]byte(record), &event); err != nil {
+		return ""
+	}
+	if event.RequestParameters.InstanceID != "" {
+		return event.RequestParameters.InstanceID
+	}
+	return event.ResponseElements.InstanceID
+}
+
+// volumeCreator names what created a volume from the tags set by AWS Backup restores and by
+// Kubernetes and the EBS CSI driver for persistent volumes, or returns "" if none is set
+func volumeCreator(tags []types.Tag) string {
+	tagMap := utils.GetTagsMap(tags)
+	switch {
+	case tagMap[backupSourceResourceTag] != "":
+		return "AWS Backup: " + tagMap[backupSourceResourceTag]
+	case tagMap[pvcNameTag] != "" && tagMap[pvcNamespaceTag] != "":
+		return "PVC: " + tagMap[pvcNamespaceTag] + "/" + tagMap[pvcNameTag]
+	case tagMap[pvcNameTag] != "":
+		return "PVC: " + tagMap[pvcNameTag]
+	case tagMap[csiVolumeNameTag] != "":
+		return "CSI: " + tagMap[csiVolumeNameTag]
+	}
+	return ""
+}
+
+// getStoppedAttachedVolumes returns in-use volumes attached to stopped instances.
+// Their idle time is counted from when the instance was stopped, falling back to
+// the attach time when the stop time cannot be parsed.
+func (c *EBSClient) getStoppedAttachedVolumes(ctx context.Context) ([]models.VolumeInfo, error) {
+	stoppedTimes, err := c.getStoppedInstanceTimes(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error querying EBS volumes: %w", err)
+		return nil, err
+	}
+	if len(stoppedTimes) == 0 {
+		return nil, nil
 	}
 
+	instanceIDs := make([]string, 0, len(stoppedTimes))
+	for instanceID := range stoppedTimes {
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+	sort.Strings(instanceIDs)
+
 	volumes := []models.VolumeInfo{}
+	for _, batch := range batchStrings(instanceIDs, ebsInstanceFilterBatchSize) {
+		filters := []types.Filter{
+			{
+				Name:   aws.String("status"),
+				Values: []string{"in-use"},
+			},
+			{
+				Name:   aws.String("attachment.instance-id"),
+				Values: batch,
+			},
+		}
 
-	for _, volume := range result.Volumes {
-		// Extract volume name
-		name := utils.GetName(volume.Tags)
+		input := &ec2.DescribeVolumesInput{
+			Filters: append(filters, ec2TagFilters(c.tagFilters)...),
+		}
 
-		// Get last attachment time
-		var lastAttachmentTime *time.Time
-		var elapsedDays int
+		paginator := ec2.NewDescribeVolumesPaginator(c.client, input)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return volumes, fmt.Errorf("error querying EBS volumes attached to stopped instances: %w", err)
+			}
 
-		if len(volume.Attachments) > 0 {
-			for _, attachment := range volume.Attachments {
-				if attachment.AttachTime != nil {
-					if lastAttachmentTime == nil || attachment.AttachTime.After(*lastAttachmentTime) {
-						lastAttachmentTime = attachment.AttachTime
+			for _, volume := range page.Volumes {
+				for _, attachment := range volume.Attachments {
+					instanceID := aws.ToString(attachment.InstanceId)
+					stoppedTime, found := stoppedTimes[instanceID]
+					if !found {
+						continue
 					}
+
+					idleSince := stoppedTime
+					if idleSince == nil {
+						idleSince = attachment.AttachTime
+					}
+
+					var elapsedDays int
+					if idleSince != nil {
+						elapsedDays = utils.CalculateElapsedDays(*idleSince)
+					}
+
+					volumes = append(volumes, c.newVolumeInfo(ctx, volume, attachment.AttachTime, elapsedDays, instanceID))
+					break
 				}
 			}
 		}
+	}
+
+	return volumes, nil
+}
+
+// getStoppedInstanceTimes returns the stopped instances in the region mapped to
+// their stop time, which is nil when it cannot be parsed
+func (c *EBSClient) getStoppedInstanceTimes(ctx context.Context) (map[string]*time.Time, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{"stopped"},
+			},
+		},
+	}
 
-		// Calculate elapsed days if last attachment time is available
-		if lastAttachmentTime != nil {
-			elapsedDays = utils.CalculateElapsedDays(*lastAttachmentTime)
-		} else if volume.CreateTime != nil {
-			// If no attachment history, use creation time
-			lastAttachmentTime = volume.CreateTime
-			elapsedDays = utils.CalculateElapsedDays(*volume.CreateTime)
+	stoppedTimes := make(map[string]*time.Time)
+	paginator := ec2.NewDescribeInstancesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error querying stopped EC2 instances: %w", err)
 		}
 
-		// Calculate cost estimates
-		volumeType := string(volume.VolumeType)
-		volumeSizeGB := int(*volume.Size)
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				stoppedTimes[aws.ToString(instance.InstanceId)] = utils.ParseStateTransitionTime(aws.ToString(instance.StateTransitionReason))
+			}
+		}
+	}
+
+	return stoppedTimes, nil
+}
+
+// newVolumeInfo builds the VolumeInfo for a volume, including its pricing and IO metrics
+func (c *EBSClient) newVolumeInfo(ctx context.Context, volume types.Volume, lastAttachmentTime *time.Time, elapsedDays int, attachedInstanceID string) models.VolumeInfo {
+	// Extract volume name
+	name := utils.GetName(volume.Tags)
+
+	// Calculate cost estimates
+	volumeType := string(volume.VolumeType)
+	volumeSizeGB := int(*volume.Size)
+	iops := int(aws.ToInt32(volume.Iops))
+	throughput := int(aws.ToInt32(volume.Throughput))
+
+	// Determine savings based on time since last use, including provisioned IOPS and throughput
+	monthlyCost, pricingSource := c.pricing.CalculateEBSMonthlyCostWithSource(volumeType, volumeSizeGB, iops, throughput, c.region)
+	savings := c.pricing.CalculateEBSSavings(volumeType, volumeSizeGB, iops, throughput, c.region, elapsedDays)
+
+	// IO metrics are informational, so a failed lookup only leaves them empty
+	metrics, err := c.getVolumeIOMetrics(ctx, aws.ToString(volume.VolumeId))
+	if err != nil {
+		slog.Warn("Could not get EBS volume IO metrics",
+			"volume", aws.ToString(volume.VolumeId), "region", c.region, "error", err)
+	}
+
+	return models.VolumeInfo{
+		VolumeID:             *volume.VolumeId,
+		ARN:                  ec2ResourceARN(c.region, c.accountID, "volume/"+aws.ToString(volume.VolumeId)),
+		Name:                 name,
+		Size:                 volumeSizeGB,
+		VolumeType:           volumeType,
+		IOPS:                 iops,
+		Throughput:           throughput,
+		State:                string(volume.State),
+		Region:               c.region,
+		AvailabilityZone:     *volume.AvailabilityZone,
+		CreationTime:         *volume.CreateTime,
+		LastAttachmentTime:   lastAttachmentTime,
+		ElapsedDaysSinceUsed: elapsedDays,
+		AttachedInstanceID:   attachedInstanceID,
+		CreatedBy:            volumeCreator(volume.Tags),
+		SourceSnapshotID:     aws.ToString(volume.SnapshotId),
+		ReadOps:              metrics.readOps,
+		WriteOps:             metrics.writeOps,
+		IdleTimePercent:      metrics.idleTimePercent,
+		LastIOTime:           metrics.lastIOTime,
+		HasIOMetrics:         metrics.found,
+		EstimatedMonthlyCost: monthlyCost,
+		EstimatedSavings:     savings,
+		PricingSource:        pricingSource,
+		Tags:                 utils.GetTagsMap(volume.Tags),
+	}
+}
+
+// getVolumeIOMetrics retrieves the read/write operations and idle time of a volume
+// over the last 30 days. EBS only publishes metrics while a volume is attached, so
+// a volume detached for longer than the window has no datapoints.
+func (c *EBSClient) getVolumeIOMetrics(ctx context.Context, volumeID string) (volumeIOMetrics, error) {
+	var metrics volumeIOMetrics
+
+	readOps, err := c.getVolumeMetric(ctx, volumeID, "VolumeReadOps", cwTypes.StatisticSum)
+	if err != nil {
+		return metrics, err
+	}
+	writeOps, err := c.getVolumeMetric(ctx, volumeID, "VolumeWriteOps", cwTypes.StatisticSum)
+	if err != nil {
+		return metrics, err
+	}
+	idleTime, err := c.getVolumeMetric(ctx, volumeID, "VolumeIdleTime", cwTypes.StatisticSum, cwTypes.StatisticSampleCount)
+	if err != nil {
+		return metrics, err
+	}
 
-		// Determine savings based on time since last use
-		monthlyCost, pricingSource := pricing.CalculateEBSMonthlyCostWithSource(volumeType, volumeSizeGB, c.region)
-		savings := pricing.CalculateEBSSavings(volumeType, volumeSizeGB, c.region, elapsedDays)
+	metrics.found = len(readOps) > 0 || len(writeOps) > 0 || len(idleTime) > 0
 
-		volumeInfo := models.VolumeInfo{
-			VolumeID:             *volume.VolumeId,
-			Name:                 name,
-			Size:                 volumeSizeGB,
-			VolumeType:           volumeType,
-			State:                string(volume.State),
-			Region:               c.region,
-			AvailabilityZone:     *volume.AvailabilityZone,
-			CreationTime:         *volume.CreateTime,
-			LastAttachmentTime:   lastAttachmentTime,
-			ElapsedDaysSinceUsed: elapsedDays,
-			EstimatedMonthlyCost: monthlyCost,
-			EstimatedSavings:     savings,
-			PricingSource:        pricingSource,
+	// Total operations and the most recent day with any IO
+	for _, datapoints := range [][]cwTypes.Datapoint{readOps, writeOps} {
+		lastIO := cloudwatchutil.LastNonZeroTimestamp(datapoints, cwTypes.StatisticSum)
+		if lastIO != nil && (metrics.lastIOTime == nil || lastIO.After(*metrics.lastIOTime)) {
+			metrics.lastIOTime = lastIO
 		}
+	}
+	metrics.readOps = cloudwatchutil.Sum(readOps, cwTypes.StatisticSum)
+	metrics.writeOps = cloudwatchutil.Sum(writeOps, cwTypes.StatisticSum)
 
-		volumes = append(volumes, volumeInfo)
+	// Idle share of the time the volume reported metrics, one sample per interval
+	var idleSeconds, samples float64
+	for _, datapoint := range idleTime {
+		if datapoint.Sum != nil {
+			idleSeconds += *datapoint.Sum
+		}
+		if datapoint.SampleCount != nil {
+			samples += *datapoint.SampleCount
+		}
+	}
+	if samples > 0 {
+		metrics.idleTimePercent = idleSeconds / (samples * ebsMetricIntervalSeconds) * 100
 	}
 
-	return volumes, nil
+	return metrics, nil
+}
+
+// getVolumeMetric retrieves daily datapoints of an AWS/EBS metric for a volume, sorted oldest first
+func (c *EBSClient) getVolumeMetric(ctx context.Context, volumeID, metricName string, statistic cwTypes.Statistic, extraStatistics ...cwTypes.Statistic) ([]cwTypes.Datapoint, error) {
+	datapoints, err := cloudwatchutil.Datapoints(ctx, c.cwClient, cloudwatchutil.MetricQuery{
+		Namespace:  "AWS/EBS",
+		Name:       metricName,
+		Dimensions: []cwTypes.Dimension{cloudwatchutil.Dimension("VolumeId", volumeID)},
+		Stat:       statistic,
+		ExtraStats: extraStatistics,
+		WindowDays: ebsMetricLookbackDays,
+		Period:     24 * time.Hour,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s metric: %w", metricName, err)
+	}
+
+	return datapoints, nil
 }
@@ -0,0 +1,218 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+// volumeStatusFilter returns the status filter of a DescribeVolumes call
+func volumeStatusFilter(params *ec2.DescribeVolumesInput) string {
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) == "status" && len(filter.Values) > 0 {
+			return filter.Values[0]
+		}
+	}
+	return ""
+}
+
+func TestGetAvailableVolumes(t *testing.T) {
+	now := time.Now().UTC()
+	createTime := now.AddDate(0, 0, -60)
+	snapshotTime := now.AddDate(0, 0, -2)
+	attachTime := now.AddDate(0, 0, -100)
+
+	fake := &mocks.EC2{
+		DescribeVolumesFunc: func(ctx context.Context, params *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+			switch volumeStatusFilter(params) {
+			case "available":
+				return &ec2.DescribeVolumesOutput{Volumes: []types.Volume{
+					{
+						VolumeId: aws.String("vol-available"), VolumeType: types.VolumeTypeGp3, Size: aws.Int32(50),
+						State: types.VolumeStateAvailable, AvailabilityZone: aws.String("us-east-1a"),
+						CreateTime: aws.Time(createTime),
+						Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String("data")}},
+					},
+				}}, nil
+			case "in-use":
+				return &ec2.DescribeVolumesOutput{Volumes: []types.Volume{
+					{
+						VolumeId: aws.String("vol-attached"), VolumeType: types.VolumeTypeGp2, Size: aws.Int32(8),
+						State: types.VolumeStateInUse, AvailabilityZone: aws.String("us-east-1a"),
+						CreateTime: aws.Time(attachTime),
+						Attachments: []types.VolumeAttachment{
+							{InstanceId: aws.String("i-stopped"), AttachTime: aws.Time(attachTime)},
+						},
+					},
+				}}, nil
+			}
+			t.Errorf("unexpected DescribeVolumes filters %v", params.Filters)
+			return &ec2.DescribeVolumesOutput{}, nil
+		},
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				{InstanceId: aws.String("i-stopped"), StateTransitionReason: aws.String("User initiated (2024-01-02 03:04:05 GMT)")},
+			}}}}, nil
+		},
+		DescribeSnapshotsFunc: func(ctx context.Context, params *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error) {
+			return &ec2.DescribeSnapshotsOutput{Snapshots: []types.Snapshot{
+				{SnapshotId: aws.String("snap-1"), VolumeId: aws.String("vol-available"), StartTime: aws.Time(snapshotTime)},
+			}}, nil
+		},
+	}
+	cw := &mocks.CloudWatch{
+		GetMetricStatisticsFunc: func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			if aws.ToString(params.MetricName) != "VolumeReadOps" || aws.ToString(params.Dimensions[0].Value) != "vol-available" {
+				return &cloudwatch.GetMetricStatisticsOutput{}, nil
+			}
+			return &cloudwatch.GetMetricStatisticsOutput{Datapoints: []cwTypes.Datapoint{
+				{Timestamp: aws.Time(now.AddDate(0, 0, -40)), Sum: aws.Float64(25)},
+			}}, nil
+		},
+	}
+	client := &EBSClient{
+		client:                 fake,
+		cwClient:               cw,
+		region:                 "us-east-1",
+		includeStoppedAttached: true,
+		snapshotRecencyDays:    DefaultSnapshotRecencyDays,
+		pricing:                newTestPricing(),
+	}
+
+	volumes, err := client.GetAvailableVolumes(context.Background())
+	if err != nil {
+		t.Fatalf("GetAvailableVolumes() error = %v", err)
+	}
+	if len(volumes) != 2 {
+		t.Fatalf("GetAvailableVolumes() returned %d volumes, want 2", len(volumes))
+	}
+
+	available := volumes[0]
+	if available.VolumeID != "vol-available" || available.Name != "data" || available.Size != 50 {
+		t.Errorf("available volume = %s %q %d GB, want vol-available \"data\" 50 GB", available.VolumeID, available.Name, available.Size)
+	}
+	if available.ElapsedDaysSinceUsed < 59 || available.LastAttachmentTime == nil || !available.LastAttachmentTime.Equal(createTime) {
+		t.Errorf("available volume idle for %d days since %v, want about 60 days since the creation time",
+			available.ElapsedDaysSinceUsed, available.LastAttachmentTime)
+	}
+	if !available.HasRecentSnapshot || available.LatestSnapshotDate == nil {
+		t.Error("available volume has no recent snapshot, want the snapshot of 2 days ago")
+	}
+	if !available.HasIOMetrics || available.ReadOps != 25 {
+		t.Errorf("available volume IO metrics = %v with %v read ops, want 25 read ops", available.HasIOMetrics, available.ReadOps)
+	}
+	if available.EstimatedMonthlyCost <= 0 {
+		t.Errorf("available volume cost = %v, want a fallback price", available.EstimatedMonthlyCost)
+	}
+
+	attached := volumes[1]
+	if attached.VolumeID != "vol-attached" || attached.AttachedInstanceID != "i-stopped" {
+		t.Errorf("attached volume = %s on %q, want vol-attached on i-stopped", attached.VolumeID, attached.AttachedInstanceID)
+	}
+	if stopped := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); attached.ElapsedDaysSinceUsed < int(now.Sub(stopped).Hours()/24)-1 {
+		t.Errorf("attached volume idle for %d days, want the days since the instance stopped", attached.ElapsedDaysSinceUsed)
+	}
+}
+
+func TestGetAvailableVolumesSkipsStoppedAttachedByDefault(t *testing.T) {
+	fake := &mocks.EC2{
+		DescribeVolumesFunc: func(ctx context.Context, params *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+			if status := volumeStatusFilter(params); status != "available" {
+				t.Errorf("DescribeVolumes status filter = %q, want only available volumes", status)
+			}
+			return &ec2.DescribeVolumesOutput{}, nil
+		},
+	}
+	client := &EBSClient{client: fake, cwClient: &mocks.CloudWatch{}, region: "us-east-1", pricing: newTestPricing()}
+
+	volumes, err := client.GetAvailableVolumes(context.Background())
+	if err != nil {
+		t.Fatalf("GetAvailableVolumes() error = %v", err)
+	}
+	if len(volumes) != 0 {
+		t.Errorf("GetAvailableVolumes() returned %d volumes, want none", len(volumes))
+	}
+}
+
+// volumePages answers DescribeVolumes with one page per volume ID list, failing the page at
+// failAt (1-based) when it is set
+func volumePages(t *testing.T, calls *int, failAt int, pages ...[]string) func(context.Context, *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+	createTime := time.Now().AddDate(0, 0, -10)
+	return func(ctx context.Context, params *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+		*calls++
+		page := 0
+		if token := aws.ToString(params.NextToken); token != "" {
+			if _, err := fmt.Sscanf(token, "page-%d", &page); err != nil {
+				t.Fatalf("unexpected NextToken %q", token)
+			}
+		}
+		if page+1 == failAt {
+			return nil, errors.New("throttled")
+		}
+		output := &ec2.DescribeVolumesOutput{}
+		for _, id := range pages[page] {
+			output.Volumes = append(output.Volumes, types.Volume{
+				VolumeId: aws.String(id), VolumeType: types.VolumeTypeGp3, Size: aws.Int32(10),
+				AvailabilityZone: aws.String("us-east-1a"), CreateTime: aws.Time(createTime),
+			})
+		}
+		if page+1 < len(pages) {
+			output.NextToken = aws.String(fmt.Sprintf("page-%d", page+1))
+		}
+		return output, nil
+	}
+}
+
+func TestGetAvailableVolumesFollowsAllPages(t *testing.T) {
+	var calls int
+	fake := &mocks.EC2{DescribeVolumesFunc: volumePages(t, &calls, 0,
+		[]string{"vol-1", "vol-2"},
+		[]string{"vol-3"},
+		[]string{"vol-4"},
+	)}
+	client := &EBSClient{client: fake, cwClient: &mocks.CloudWatch{}, region: "us-east-1", pricing: newTestPricing()}
+
+	volumes, err := client.GetAvailableVolumes(context.Background())
+	if err != nil {
+		t.Fatalf("GetAvailableVolumes() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("DescribeVolumes called %d times, want 3", calls)
+	}
+	var ids []string
+	for _, volume := range volumes {
+		ids = append(ids, volume.VolumeID)
+	}
+	if want := []string{"vol-1", "vol-2", "vol-3", "vol-4"}; !slices.Equal(ids, want) {
+		t.Errorf("GetAvailableVolumes() = %v, want %v", ids, want)
+	}
+}
+
+func TestGetAvailableVolumesKeepsPagesReadBeforeAFailure(t *testing.T) {
+	var calls int
+	fake := &mocks.EC2{DescribeVolumesFunc: volumePages(t, &calls, 3,
+		[]string{"vol-1", "vol-2"},
+		[]string{"vol-3"},
+		[]string{"vol-4"},
+	)}
+	client := &EBSClient{client: fake, cwClient: &mocks.CloudWatch{}, region: "us-east-1", pricing: newTestPricing()}
+
+	volumes, err := client.GetAvailableVolumes(context.Background())
+	if err == nil {
+		t.Fatal("GetAvailableVolumes() error = nil, want the error of the third page")
+	}
+	if len(volumes) != 3 {
+		t.Errorf("GetAvailableVolumes() returned %d volumes, want the 3 of the first two pages", len(volumes))
+	}
+}
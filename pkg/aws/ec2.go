@@ -2,11 +2,13 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/younsl/idled/internal/models"
@@ -14,28 +16,63 @@ import (
 	"github.com/younsl/idled/pkg/utils"
 )
 
+// ec2CostFilterBatchSize is the number of IDs passed per volume or address filter
+const ec2CostFilterBatchSize = 100
+
+// ec2InstancesAPI is the EC2 API used by EC2Client
+type ec2InstancesAPI interface {
+	ec2.DescribeInstancesAPIClient
+	ec2.DescribeVolumesAPIClient
+	DescribeInstanceAttribute(ctx context.Context, params *ec2.DescribeInstanceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceAttributeOutput, error)
+	DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
+}
+
 // EC2Client struct for EC2 client
 type EC2Client struct {
-	client *ec2.Client
-	region string
+	client        ec2InstancesAPI
+	trailClient   cloudtrail.LookupEventsAPIClient
+	region        string
+	tagFilters    map[string]string
+	useCloudTrail bool
+	accountID     string // Account of the instance ARNs
+	pricing       *pricing.PricingService
 }
 
 // NewEC2Client creates a new EC2Client
-func NewEC2Client(region string) (*EC2Client, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("error loading AWS config: %w", err)
-	}
-
+func NewEC2Client(cfg aws.Config) *EC2Client {
 	client := ec2.NewFromConfig(cfg)
 	return &EC2Client{
-		client: client,
-		region: region,
-	}, nil
+		client:      client,
+		trailClient: cloudtrail.NewFromConfig(cfg),
+		region:      cfg.Region,
+		pricing:     pricing.Default(),
+	}
+}
+
+// SetTagFilters limits results to instances carrying all of the given tags
+func (c *EC2Client) SetTagFilters(tags map[string]string) {
+	c.tagFilters = tags
+}
+
+// SetAccountID sets the account used to build the ARNs of the instances, left empty if not set
+func (c *EC2Client) SetAccountID(accountID string) {
+	c.accountID = accountID
+}
+
+// SetUseCloudTrail enables the CloudTrail lookup for instances whose stop time
+// cannot be parsed from the state transition reason
+func (c *EC2Client) SetUseCloudTrail(enabled bool) {
+	c.useCloudTrail = enabled
+}
+
+// SetPricingService sets the pricing service used to estimate instance costs
+func (c *EC2Client) SetPricingService(service *pricing.PricingService) {
+	c.pricing = service
 }
 
-// GetStoppedInstances returns a list of all EC2 instances in Stopped state
-func (c *EC2Client) GetStoppedInstances() ([]models.InstanceInfo, error) {
+// GetStoppedInstances returns a list of all EC2 instances in Stopped state. If the listing fails
+// part way, the instances of the pages already read are returned with the error.
+func (c *EC2Client) GetStoppedInstances(ctx context.Context) ([]models.InstanceInfo, error) {
 	// Filter only stopped instances
 	filter := types.Filter{
 		Name:   aws.String("instance-state-name"),
@@ -43,54 +80,266 @@ func (c *EC2Client) GetStoppedInstances() ([]models.InstanceInfo, error) {
 	}
 
 	input := &ec2.DescribeInstancesInput{
-		Filters: []types.Filter{filter},
-	}
-
-	result, err := c.client.DescribeInstances(context.TODO(), input)
-	if err != nil {
-		return nil, fmt.Errorf("error querying EC2 instances: %w", err)
+		Filters: append([]types.Filter{filter}, ec2TagFilters(c.tagFilters)...),
 	}
 
 	instances := []models.InstanceInfo{}
+	instanceVolumes := make(map[string][]string)
 
-	for _, reservation := range result.Reservations {
-		for _, instance := range reservation.Instances {
-			// Extract instance name
-			name := utils.GetName(instance.Tags)
+	var pageErr error
+	paginator := ec2.NewDescribeInstancesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			pageErr = fmt.Errorf("error querying EC2 instances: %w", err)
+			break
+		}
+
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if ctx.Err() != nil {
+					return instances, ctx.Err()
+				}
 
-			// Calculate stop time (extract from StateTransitionReason)
-			var stoppedTime *time.Time
-			var elapsedDays int
+				// Extract instance name
+				name := utils.GetName(instance.Tags)
 
-			if instance.StateTransitionReason != nil && len(*instance.StateTransitionReason) > 0 {
-				stoppedTime = utils.ParseStateTransitionTime(*instance.StateTransitionReason)
+				// Resolve stop time from the best available source
+				stoppedTime, stoppedTimeSource := c.resolveStoppedTime(ctx, instance)
+
+				var elapsedDays int
 				if stoppedTime != nil {
 					elapsedDays = utils.CalculateElapsedDays(*stoppedTime)
 				}
+
+				// Calculate cost estimates
+				instanceType := string(instance.InstanceType)
+				operatingSystem := instanceOperatingSystem(instance, c.region)
+				monthlyCost, pricingSource := c.pricing.CalculateMonthlyCostWithSource(instanceType, c.region, operatingSystem)
+				savings, _ := c.pricing.CalculateSavingsWithSource(instanceType, c.region, operatingSystem, elapsedDays)
+
+				instanceInfo := models.InstanceInfo{
+					InstanceID:           *instance.InstanceId,
+					ARN:                  ec2ResourceARN(c.region, c.accountID, "instance/"+aws.ToString(instance.InstanceId)),
+					Name:                 name,
+					InstanceType:         instanceType,
+					OperatingSystem:      operatingSystem,
+					Region:               c.region,
+					AvailabilityZone:     *instance.Placement.AvailabilityZone,
+					StoppedTime:          stoppedTime,
+					StoppedTimeSource:    stoppedTimeSource,
+					LaunchTime:           *instance.LaunchTime,
+					ElapsedDays:          elapsedDays,
+					EstimatedMonthlyCost: monthlyCost,
+					EstimatedSavings:     savings,
+					PricingSource:        pricingSource,
+					Tags:                 utils.GetTagsMap(instance.Tags),
+				}
+
+				for _, mapping := range instance.BlockDeviceMappings {
+					if mapping.Ebs != nil && aws.ToString(mapping.Ebs.VolumeId) != "" {
+						instanceVolumes[instanceInfo.InstanceID] = append(instanceVolumes[instanceInfo.InstanceID], aws.ToString(mapping.Ebs.VolumeId))
+					}
+				}
+
+				instances = append(instances, instanceInfo)
 			}
+		}
+	}
+
+	if err := c.addCurrentCosts(ctx, instances, instanceVolumes); err != nil {
+		return instances, errors.Join(pageErr, err)
+	}
+
+	if err := c.addTerminationProtection(ctx, instances); err != nil {
+		return instances, errors.Join(pageErr, err)
+	}
+
+	return instances, pageErr
+}
 
-			// Calculate cost estimates
-			instanceType := string(instance.InstanceType)
-			monthlyCost, pricingSource := pricing.CalculateMonthlyCostWithSource(instanceType, c.region)
-			savings, _ := pricing.CalculateSavingsWithSource(instanceType, c.region, elapsedDays)
-
-			instanceInfo := models.InstanceInfo{
-				InstanceID:           *instance.InstanceId,
-				Name:                 name,
-				InstanceType:         instanceType,
-				Region:               c.region,
-				AvailabilityZone:     *instance.Placement.AvailabilityZone,
-				StoppedTime:          stoppedTime,
-				LaunchTime:           *instance.LaunchTime,
-				ElapsedDays:          elapsedDays,
-				EstimatedMonthlyCost: monthlyCost,
-				EstimatedSavings:     savings,
-				PricingSource:        pricingSource,
+// addTerminationProtection flags the stopped instances that have termination protection enabled.
+// Instances whose attribute cannot be read are left unflagged and the scan continues.
+func (c *EC2Client) addTerminationProtection(ctx context.Context, instances []models.InstanceInfo) error {
+	var errs []error
+	for i := range instances {
+		if ctx.Err() != nil {
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+
+		result, err := c.client.DescribeInstanceAttribute(ctx, &ec2.DescribeInstanceAttributeInput{
+			InstanceId: aws.String(instances[i].InstanceID),
+			Attribute:  types.InstanceAttributeNameDisableApiTermination,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error checking termination protection of instance %s: %w", instances[i].InstanceID, err))
+			continue
+		}
+
+		if result.DisableApiTermination != nil && aws.ToBool(result.DisableApiTermination.Value) {
+			instances[i].Protected = true
+			instances[i].ProtectionReason = "termination protection"
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// instanceOperatingSystem returns the operating system an instance is priced for. Instances
+// with unknown platform details are priced as Linux.
+func instanceOperatingSystem(instance types.Instance, region string) string {
+	operatingSystem, known := pricing.EC2OperatingSystem(aws.ToString(instance.PlatformDetails))
+	if !known {
+		slog.Warn("Unknown EC2 platform details, pricing the instance as Linux",
+			"instanceId", aws.ToString(instance.InstanceId),
+			"platformDetails", aws.ToString(instance.PlatformDetails),
+			"region", region)
+	}
+	return operatingSystem
+}
+
+// addCurrentCosts sets what each stopped instance is still billed for: the storage of its
+// attached EBS volumes and its associated Elastic IP. The compute price is not billed while stopped.
+func (c *EC2Client) addCurrentCosts(ctx context.Context, instances []models.InstanceInfo, instanceVolumes map[string][]string) error {
+	var volumeIDs, instanceIDs []string
+	for _, instance := range instances {
+		volumeIDs = append(volumeIDs, instanceVolumes[instance.InstanceID]...)
+		instanceIDs = append(instanceIDs, instance.InstanceID)
+	}
+
+	volumes, err := c.getVolumes(ctx, volumeIDs)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := c.getInstanceAddresses(ctx, instanceIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range instances {
+		instance := &instances[i]
+
+		for _, volumeID := range instanceVolumes[instance.InstanceID] {
+			volume, found := volumes[volumeID]
+			if !found {
+				continue
+			}
+
+			sizeGB := int(aws.ToInt32(volume.Size))
+			iops, throughput := int(aws.ToInt32(volume.Iops)), int(aws.ToInt32(volume.Throughput))
+			monthlyCost, source := c.pricing.CalculateEBSMonthlyCostWithSource(string(volume.VolumeType), sizeGB, iops, throughput, c.region)
+			if source == string(pricing.PricingSourceNA) {
+				instance.CurrentCostUnpriced = true
+			}
+
+			instance.AttachedVolumes++
+			instance.AttachedStorageGB += sizeGB
+			instance.StorageMonthlyCost += monthlyCost
+		}
+
+		if publicIP, found := addresses[instance.InstanceID]; found {
+			instance.ElasticIP = publicIP
+			instance.EIPMonthlyCost, _ = c.pricing.GetEIPMonthlyCost(c.region)
+		}
+
+		instance.CurrentMonthlyCost = instance.StorageMonthlyCost + instance.EIPMonthlyCost
+	}
+
+	return nil
+}
+
+// getVolumes returns the given EBS volumes by ID
+func (c *EC2Client) getVolumes(ctx context.Context, volumeIDs []string) (map[string]types.Volume, error) {
+	volumes := make(map[string]types.Volume)
+
+	// Filter by ID instead of VolumeIds so that a deleted volume doesn't fail the call
+	for _, batch := range batchStrings(volumeIDs, ec2CostFilterBatchSize) {
+		input := &ec2.DescribeVolumesInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("volume-id"),
+					Values: batch,
+				},
+			},
+		}
+
+		paginator := ec2.NewDescribeVolumesPaginator(c.client, input)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error querying EBS volumes attached to stopped instances: %w", err)
 			}
 
-			instances = append(instances, instanceInfo)
+			for _, volume := range page.Volumes {
+				volumes[aws.ToString(volume.VolumeId)] = volume
+			}
 		}
 	}
 
-	return instances, nil
+	return volumes, nil
+}
+
+// getInstanceAddresses returns the public IP of the Elastic IP associated with each of the given instances
+func (c *EC2Client) getInstanceAddresses(ctx context.Context, instanceIDs []string) (map[string]string, error) {
+	addresses := make(map[string]string)
+
+	for _, batch := range batchStrings(instanceIDs, ec2CostFilterBatchSize) {
+		input := &ec2.DescribeAddressesInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("instance-id"),
+					Values: batch,
+				},
+			},
+		}
+
+		result, err := c.client.DescribeAddresses(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error querying Elastic IPs associated with stopped instances: %w", err)
+		}
+
+		for _, address := range result.Addresses {
+			addresses[aws.ToString(address.InstanceId)] = aws.ToString(address.PublicIp)
+		}
+	}
+
+	return addresses, nil
+}
+
+// resolveStoppedTime determines when an instance was stopped. The state transition
+// reason is tried first, then CloudTrail (when enabled), and finally the launch time,
+// which is only a lower bound since the instance stopped at some point after it
+func (c *EC2Client) resolveStoppedTime(ctx context.Context, instance types.Instance) (*time.Time, string) {
+	if reason := aws.ToString(instance.StateTransitionReason); reason != "" {
+		if stoppedTime := utils.ParseStateTransitionTime(reason); stoppedTime != nil {
+			return stoppedTime, models.StoppedTimeSourceStateTransition
+		}
+	}
+
+	if c.useCloudTrail {
+		stoppedTime, err := c.lookupStopEventTime(ctx, aws.ToString(instance.InstanceId))
+		if err != nil {
+			slog.Warn("Could not look up CloudTrail stop event",
+				"instance", aws.ToString(instance.InstanceId), "region", c.region, "error", err)
+		} else if stoppedTime != nil {
+			return stoppedTime, models.StoppedTimeSourceCloudTrail
+		}
+	}
+
+	if instance.LaunchTime != nil {
+		launchTime := *instance.LaunchTime
+		return &launchTime, models.StoppedTimeSourceLaunchTime
+	}
+
+	return nil, ""
+}
+
+// lookupStopEventTime returns the time of the most recent StopInstances event for
+// the instance, or nil if none is found within the CloudTrail event history
+func (c *EC2Client) lookupStopEventTime(ctx context.Context, instanceID string) (*time.Time, error) {
+	event, err := lookupLatestEvent(ctx, c.trailClient, instanceID, "StopInstances")
+	if err != nil || event == nil {
+		return nil, err
+	}
+	return event.EventTime, nil
 }
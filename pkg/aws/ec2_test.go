@@ -0,0 +1,192 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+func stoppedInstance(id, reason string, launchTime time.Time, volumeIDs ...string) types.Instance {
+	instance := types.Instance{
+		InstanceId:            aws.String(id),
+		InstanceType:          types.InstanceTypeT3Micro,
+		Placement:             &types.Placement{AvailabilityZone: aws.String("us-east-1a")},
+		LaunchTime:            aws.Time(launchTime),
+		StateTransitionReason: aws.String(reason),
+		Tags:                  []types.Tag{{Key: aws.String("Name"), Value: aws.String(id + "-name")}},
+	}
+	for _, volumeID := range volumeIDs {
+		instance.BlockDeviceMappings = append(instance.BlockDeviceMappings, types.InstanceBlockDeviceMapping{
+			Ebs: &types.EbsInstanceBlockDevice{VolumeId: aws.String(volumeID)},
+		})
+	}
+	return instance
+}
+
+func TestGetStoppedInstances(t *testing.T) {
+	launchTime := time.Now().AddDate(0, 0, -90).UTC()
+	fake := &mocks.EC2{
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+			if len(params.Filters) == 0 || aws.ToString(params.Filters[0].Name) != "instance-state-name" {
+				t.Errorf("DescribeInstances filters = %v, want the stopped state filter first", params.Filters)
+			}
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				stoppedInstance("i-transition", "User initiated (2024-01-02 03:04:05 GMT)", launchTime, "vol-1"),
+				stoppedInstance("i-launch", "", launchTime),
+			}}}}, nil
+		},
+		DescribeVolumesFunc: func(ctx context.Context, params *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
+			return &ec2.DescribeVolumesOutput{Volumes: []types.Volume{
+				{VolumeId: aws.String("vol-1"), VolumeType: types.VolumeTypeGp3, Size: aws.Int32(100)},
+			}}, nil
+		},
+		DescribeAddressesFunc: func(ctx context.Context, params *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+			return &ec2.DescribeAddressesOutput{Addresses: []types.Address{
+				{InstanceId: aws.String("i-launch"), PublicIp: aws.String("203.0.113.10")},
+			}}, nil
+		},
+		DescribeInstanceAttributeFunc: func(ctx context.Context, params *ec2.DescribeInstanceAttributeInput) (*ec2.DescribeInstanceAttributeOutput, error) {
+			protected := aws.ToString(params.InstanceId) == "i-transition"
+			return &ec2.DescribeInstanceAttributeOutput{
+				DisableApiTermination: &types.AttributeBooleanValue{Value: aws.Bool(protected)},
+			}, nil
+		},
+	}
+	client := &EC2Client{client: fake, region: "us-east-1", accountID: "123456789012", pricing: newTestPricing()}
+
+	instances, err := client.GetStoppedInstances(context.Background())
+	if err != nil {
+		t.Fatalf("GetStoppedInstances() error = %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("GetStoppedInstances() returned %d instances, want 2", len(instances))
+	}
+
+	byTransition := instances[0]
+	if byTransition.StoppedTimeSource != models.StoppedTimeSourceStateTransition {
+		t.Errorf("StoppedTimeSource = %q, want %q", byTransition.StoppedTimeSource, models.StoppedTimeSourceStateTransition)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); byTransition.StoppedTime == nil || !byTransition.StoppedTime.Equal(want) {
+		t.Errorf("StoppedTime = %v, want %v", byTransition.StoppedTime, want)
+	}
+	if byTransition.Name != "i-transition-name" {
+		t.Errorf("Name = %q, want i-transition-name", byTransition.Name)
+	}
+	if want := "arn:aws:ec2:us-east-1:123456789012:instance/i-transition"; byTransition.ARN != want {
+		t.Errorf("ARN = %q, want %q", byTransition.ARN, want)
+	}
+	if byTransition.AttachedVolumes != 1 || byTransition.AttachedStorageGB != 100 || byTransition.StorageMonthlyCost <= 0 {
+		t.Errorf("attached storage = %d volumes, %d GB, $%.2f, want 1 volume of 100 GB with a cost",
+			byTransition.AttachedVolumes, byTransition.AttachedStorageGB, byTransition.StorageMonthlyCost)
+	}
+	if !byTransition.Protected {
+		t.Error("Protected = false, want true for the instance with termination protection")
+	}
+
+	byLaunch := instances[1]
+	if byLaunch.StoppedTimeSource != models.StoppedTimeSourceLaunchTime {
+		t.Errorf("StoppedTimeSource = %q, want %q", byLaunch.StoppedTimeSource, models.StoppedTimeSourceLaunchTime)
+	}
+	if byLaunch.ElapsedDays < 89 {
+		t.Errorf("ElapsedDays = %d, want about 90", byLaunch.ElapsedDays)
+	}
+	if byLaunch.ElasticIP != "203.0.113.10" || byLaunch.EIPMonthlyCost <= 0 {
+		t.Errorf("Elastic IP = %q at $%.2f, want 203.0.113.10 with a cost", byLaunch.ElasticIP, byLaunch.EIPMonthlyCost)
+	}
+	if byLaunch.Protected {
+		t.Error("Protected = true, want false")
+	}
+}
+
+func TestGetStoppedInstancesError(t *testing.T) {
+	fake := &mocks.EC2{
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+			return nil, errors.New("access denied")
+		},
+	}
+	client := &EC2Client{client: fake, region: "us-east-1", pricing: newTestPricing()}
+
+	instances, err := client.GetStoppedInstances(context.Background())
+	if err == nil {
+		t.Fatal("GetStoppedInstances() error = nil, want the DescribeInstances error")
+	}
+	if len(instances) != 0 {
+		t.Errorf("GetStoppedInstances() returned %d instances, want none", len(instances))
+	}
+}
+
+// instancePages answers DescribeInstances with one page per instance list, failing the page at
+// failAt (1-based) when it is set
+func instancePages(t *testing.T, calls *int, failAt int, pages ...[]types.Instance) func(context.Context, *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	return func(ctx context.Context, params *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+		*calls++
+		page := 0
+		if token := aws.ToString(params.NextToken); token != "" {
+			if _, err := fmt.Sscanf(token, "page-%d", &page); err != nil {
+				t.Fatalf("unexpected NextToken %q", token)
+			}
+		}
+		if page+1 == failAt {
+			return nil, errors.New("throttled")
+		}
+		output := &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: pages[page]}}}
+		if page+1 < len(pages) {
+			output.NextToken = aws.String(fmt.Sprintf("page-%d", page+1))
+		}
+		return output, nil
+	}
+}
+
+func TestGetStoppedInstancesFollowsAllPages(t *testing.T) {
+	launchTime := time.Now().AddDate(0, 0, -10)
+	var calls int
+	fake := &mocks.EC2{DescribeInstancesFunc: instancePages(t, &calls, 0,
+		[]types.Instance{stoppedInstance("i-1", "", launchTime), stoppedInstance("i-2", "", launchTime)},
+		[]types.Instance{stoppedInstance("i-3", "", launchTime)},
+		[]types.Instance{stoppedInstance("i-4", "", launchTime), stoppedInstance("i-5", "", launchTime)},
+	)}
+	client := &EC2Client{client: fake, region: "us-east-1", pricing: newTestPricing()}
+
+	instances, err := client.GetStoppedInstances(context.Background())
+	if err != nil {
+		t.Fatalf("GetStoppedInstances() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("DescribeInstances called %d times, want 3", calls)
+	}
+	var ids []string
+	for _, instance := range instances {
+		ids = append(ids, instance.InstanceID)
+	}
+	if want := []string{"i-1", "i-2", "i-3", "i-4", "i-5"}; !slices.Equal(ids, want) {
+		t.Errorf("GetStoppedInstances() = %v, want %v", ids, want)
+	}
+}
+
+func TestGetStoppedInstancesKeepsPagesReadBeforeAFailure(t *testing.T) {
+	launchTime := time.Now().AddDate(0, 0, -10)
+	var calls int
+	fake := &mocks.EC2{DescribeInstancesFunc: instancePages(t, &calls, 2,
+		[]types.Instance{stoppedInstance("i-1", "", launchTime), stoppedInstance("i-2", "", launchTime)},
+		[]types.Instance{stoppedInstance("i-3", "", launchTime)},
+	)}
+	client := &EC2Client{client: fake, region: "us-east-1", pricing: newTestPricing()}
+
+	instances, err := client.GetStoppedInstances(context.Background())
+	if err == nil {
+		t.Fatal("GetStoppedInstances() error = nil, want the error of the second page")
+	}
+	if len(instances) != 2 {
+		t.Errorf("GetStoppedInstances() returned %d instances, want the 2 of the first page", len(instances))
+	}
+}
@@ -0,0 +1,284 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/progress"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+const (
+	// autoScalingGroupTag is set by Auto Scaling on the instances it launches
+	autoScalingGroupTag = "aws:autoscaling:groupName"
+
+	// utilizationInstanceBatchSize is the number of instances per GetMetricData call.
+	// Each instance needs one query per utilization metric, and a call accepts up to 500 queries.
+	utilizationInstanceBatchSize = 80
+
+	// Default thresholds below which a running instance is underutilized
+	DefaultCPUThresholdPercent = 5.0
+	DefaultNetworkThresholdMBs = 1.0
+	DefaultUtilizationDays     = 14
+)
+
+// utilizationMetrics are the CloudWatch metrics queried for each instance, in query ID order
+var utilizationMetrics = []struct {
+	name string
+	stat cwTypes.Statistic
+}{
+	{"CPUUtilization", cwTypes.StatisticAverage},
+	{"CPUUtilization", cwTypes.StatisticMaximum},
+	{"NetworkIn", cwTypes.StatisticSum},
+	{"NetworkOut", cwTypes.StatisticSum},
+	{"EBSReadBytes", cwTypes.StatisticSum},
+	{"EBSWriteBytes", cwTypes.StatisticSum},
+}
+
+// EC2UtilizationClient finds running EC2 instances with low CPU and network usage
+type EC2UtilizationClient struct {
+	client             ec2.DescribeInstancesAPIClient
+	cwClient           cloudwatch.GetMetricDataAPIClient
+	region             string
+	tagFilters         map[string]string
+	cpuThreshold       float64       // Average CPU utilization (%) below which an instance is underutilized
+	networkThreshold   float64       // Average network throughput (MB/s) below which an instance is underutilized
+	lookbackDays       int           // Days of metrics to evaluate
+	includeAutoScaling bool          // Whether to report instances in Auto Scaling groups
+	progress           progress.Func // Receives the evaluated instance count, nil for none
+	accountID          string        // Account of the instance ARNs
+	pricing            *pricing.PricingService
+}
+
+// NewEC2UtilizationClient creates a new EC2UtilizationClient with the default thresholds
+func NewEC2UtilizationClient(cfg aws.Config) *EC2UtilizationClient {
+	return &EC2UtilizationClient{
+		client:           ec2.NewFromConfig(cfg),
+		cwClient:         cloudwatch.NewFromConfig(cfg),
+		region:           cfg.Region,
+		cpuThreshold:     DefaultCPUThresholdPercent,
+		networkThreshold: DefaultNetworkThresholdMBs,
+		lookbackDays:     DefaultUtilizationDays,
+		pricing:          pricing.Default(),
+	}
+}
+
+// SetTagFilters limits results to instances carrying all of the given tags
+func (c *EC2UtilizationClient) SetTagFilters(tags map[string]string) {
+	c.tagFilters = tags
+}
+
+// SetThresholds sets the average CPU utilization (%) and network throughput (MB/s, in and out
+// combined) below which a running instance is underutilized
+func (c *EC2UtilizationClient) SetThresholds(cpuPercent, networkMBs float64) {
+	c.cpuThreshold = cpuPercent
+	c.networkThreshold = networkMBs
+}
+
+// SetLookbackDays sets the number of days of CloudWatch metrics to evaluate
+func (c *EC2UtilizationClient) SetLookbackDays(days int) {
+	c.lookbackDays = days
+}
+
+// SetIncludeAutoScaling also reports instances in Auto Scaling groups, which are skipped
+// by default since the group replaces terminated instances
+func (c *EC2UtilizationClient) SetIncludeAutoScaling(enabled bool) {
+	c.includeAutoScaling = enabled
+}
+
+// SetAccountID sets the account used to build the ARNs of the instances, left empty if not set
+func (c *EC2UtilizationClient) SetAccountID(accountID string) {
+	c.accountID = accountID
+}
+
+// SetProgress sets the function receiving the evaluation progress
+func (c *EC2UtilizationClient) SetProgress(f progress.Func) {
+	c.progress = f
+}
+
+// SetPricingService sets the pricing service used to estimate instance costs
+func (c *EC2UtilizationClient) SetPricingService(service *pricing.PricingService) {
+	c.pricing = service
+}
+
+// GetUnderutilizedInstances returns the running instances whose average CPU utilization and
+// network throughput over the lookback are both below the thresholds. Instances launched
+// within the lookback are skipped, since their metrics don't cover it.
+func (c *EC2UtilizationClient) GetUnderutilizedInstances(ctx context.Context) ([]models.UnderutilizedInstanceInfo, error) {
+	endTime := time.Now()
+	startTime := endTime.AddDate(0, 0, -c.lookbackDays)
+
+	candidates, err := c.getRunningInstances(ctx, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	c.progress.Report(0, len(candidates), "")
+
+	underutilized := []models.UnderutilizedInstanceInfo{}
+	for start := 0; start < len(candidates); start += utilizationInstanceBatchSize {
+		end := min(start+utilizationInstanceBatchSize, len(candidates))
+		batch := candidates[start:end]
+
+		values, err := c.getUtilizationMetrics(ctx, batch, startTime, endTime)
+		if err != nil {
+			return underutilized, err
+		}
+
+		for i := range batch {
+			instance := &batch[i]
+			if !c.applyMetrics(instance, values[i], endTime.Sub(startTime)) {
+				continue
+			}
+			instance.EstimatedMonthlyCost, instance.PricingSource = c.pricing.CalculateMonthlyCostWithSource(instance.InstanceType, c.region, instance.OperatingSystem)
+			underutilized = append(underutilized, *instance)
+		}
+
+		c.progress.Report(end, len(candidates), fmt.Sprintf("Evaluated %d running instances in %s", end, c.region))
+	}
+
+	return underutilized, nil
+}
+
+// getRunningInstances returns the running instances launched before startTime, without
+// instances in Auto Scaling groups unless they are included
+func (c *EC2UtilizationClient) getRunningInstances(ctx context.Context, startTime time.Time) ([]models.UnderutilizedInstanceInfo, error) {
+	filter := types.Filter{
+		Name:   aws.String("instance-state-name"),
+		Values: []string{"running"},
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: append([]types.Filter{filter}, ec2TagFilters(c.tagFilters)...),
+	}
+
+	instances := []models.UnderutilizedInstanceInfo{}
+	paginator := ec2.NewDescribeInstancesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error querying EC2 instances: %w", err)
+		}
+
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				autoScalingGroup := utils.GetTagValue(instance.Tags, autoScalingGroupTag)
+				if autoScalingGroup != "" && !c.includeAutoScaling {
+					continue
+				}
+				if instance.LaunchTime == nil || instance.LaunchTime.After(startTime) {
+					continue
+				}
+
+				var availabilityZone string
+				if instance.Placement != nil {
+					availabilityZone = aws.ToString(instance.Placement.AvailabilityZone)
+				}
+
+				instances = append(instances, models.UnderutilizedInstanceInfo{
+					InstanceID:       aws.ToString(instance.InstanceId),
+					ARN:              ec2ResourceARN(c.region, c.accountID, "instance/"+aws.ToString(instance.InstanceId)),
+					Name:             utils.GetName(instance.Tags),
+					InstanceType:     string(instance.InstanceType),
+					OperatingSystem:  instanceOperatingSystem(instance, c.region),
+					Region:           c.region,
+					AvailabilityZone: availabilityZone,
+					LaunchTime:       *instance.LaunchTime,
+					AutoScalingGroup: autoScalingGroup,
+					LookbackDays:     c.lookbackDays,
+					Tags:             utils.GetTagsMap(instance.Tags),
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// getUtilizationMetrics fetches the utilization metrics of a batch of instances with one
+// GetMetricData call. Each metric is aggregated into a single period covering the lookback.
+// It returns the datapoints of each instance, indexed like utilizationMetrics.
+func (c *EC2UtilizationClient) getUtilizationMetrics(ctx context.Context, instances []models.UnderutilizedInstanceInfo, startTime, endTime time.Time) ([][][]float64, error) {
+	var queries []cloudwatchutil.MetricQuery
+	for _, instance := range instances {
+		for _, metric := range utilizationMetrics {
+			queries = append(queries, cloudwatchutil.MetricQuery{
+				Namespace:  "AWS/EC2",
+				Name:       metric.name,
+				Dimensions: []cwTypes.Dimension{cloudwatchutil.Dimension("InstanceId", instance.InstanceID)},
+				Stat:       metric.stat,
+				Period:     time.Duration(c.lookbackDays) * 24 * time.Hour,
+			})
+		}
+	}
+
+	results, err := cloudwatchutil.MetricData(ctx, c.cwClient, queries, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("error getting EC2 utilization metrics: %w", err)
+	}
+
+	values := make([][][]float64, len(instances))
+	for i := range values {
+		values[i] = make([][]float64, len(utilizationMetrics))
+		for j := range utilizationMetrics {
+			values[i][j] = results[i*len(utilizationMetrics)+j].Values
+		}
+	}
+
+	return values, nil
+}
+
+// applyMetrics sets the utilization of the instance from its datapoints and reports whether
+// it is underutilized. Instances without CPU datapoints are not evaluated.
+func (c *EC2UtilizationClient) applyMetrics(instance *models.UnderutilizedInstanceInfo, values [][]float64, window time.Duration) bool {
+	if len(values[0]) == 0 {
+		slog.Debug("Skipping EC2 instance without CPU metrics", "instance", instance.InstanceID, "region", c.region)
+		return false
+	}
+
+	instance.AvgCPU = average(values[0])
+	instance.MaxCPU = maximum(values[1])
+
+	seconds := window.Seconds()
+	instance.NetworkBytesPerSec = (sum(values[2]) + sum(values[3])) / seconds
+	instance.HasEBSMetrics = len(values[4]) > 0 || len(values[5]) > 0
+	instance.EBSBytesPerSec = (sum(values[4]) + sum(values[5])) / seconds
+
+	return instance.AvgCPU < c.cpuThreshold && instance.NetworkBytesPerSec < c.networkThreshold*1024*1024
+}
+
+// sum returns the sum of the datapoints
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// average returns the mean of the datapoints, or 0 if there are none
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return sum(values) / float64(len(values))
+}
+
+// maximum returns the largest datapoint, or 0 if there are none
+func maximum(values []float64) float64 {
+	var largest float64
+	for _, v := range values {
+		largest = max(largest, v)
+	}
+	return largest
+}
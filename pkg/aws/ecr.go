@@ -2,55 +2,96 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
 	"github.com/aws/aws-sdk-go-v2/service/ecr/types"
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/utils"
 )
 
 const (
 	defaultECRIdleDays = 90
 )
 
+// ecrAPI is the ECR API used by ECRClient
+type ecrAPI interface {
+	ecr.DescribeImagesAPIClient
+	ecr.DescribeRepositoriesAPIClient
+	ListTagsForResource(ctx context.Context, params *ecr.ListTagsForResourceInput, optFns ...func(*ecr.Options)) (*ecr.ListTagsForResourceOutput, error)
+}
+
 // ECRClient wraps the ECR API calls
 type ECRClient struct {
-	client *ecr.Client
-	region string
+	client     ecrAPI
+	region     string
+	tagFilters map[string]string
+	nameFilter *utils.NameFilter
 }
 
-// NewECRClient creates a new ECR client for the specified region
-func NewECRClient(region string) (*ECRClient, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
-	}
+// NewECRClient creates a new ECR client
+func NewECRClient(cfg aws.Config) *ECRClient {
 	return &ECRClient{
 		client: ecr.NewFromConfig(cfg),
-		region: region,
-	}, nil
+		region: cfg.Region,
+	}
+}
+
+// SetTagFilters limits results to repositories carrying all of the given tags
+func (c *ECRClient) SetTagFilters(tags map[string]string) {
+	c.tagFilters = tags
+}
+
+// SetNameFilter limits results to repositories whose names pass the --include and --exclude patterns
+func (c *ECRClient) SetNameFilter(filter *utils.NameFilter) {
+	c.nameFilter = filter
 }
 
 // GetIdleRepositories retrieves ECR repositories and identifies idle ones based on last push time
-func (c *ECRClient) GetIdleRepositories() ([]models.RepositoryInfo, error) {
+func (c *ECRClient) GetIdleRepositories(ctx context.Context) ([]models.RepositoryInfo, error) {
 	var idleRepos []models.RepositoryInfo
+	var errs []error // Per-repository errors, the scan keeps going
 	paginator := ecr.NewDescribeRepositoriesPaginator(c.client, &ecr.DescribeRepositoriesInput{})
 
 	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(context.TODO())
+		output, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to describe ECR repositories in region %s: %w", c.region, err)
+			// Keep the repositories of the previous pages
+			return idleRepos, errors.Join(append(errs, fmt.Errorf("failed to describe ECR repositories in region %s: %w", c.region, err))...)
 		}
 
 		for _, repo := range output.Repositories {
-			lastPush, imageCount, err := c.getLastPushTimeAndCount(repo.RepositoryName)
+			if ctx.Err() != nil {
+				return idleRepos, errors.Join(append(errs, ctx.Err())...)
+			}
+			// Skip excluded repositories before listing their tags and images
+			if c.nameFilter.Excludes(aws.ToString(repo.RepositoryArn), aws.ToString(repo.RepositoryName)) {
+				continue
+			}
+			tags, err := c.getRepositoryTags(ctx, repo.RepositoryArn)
+			if err != nil {
+				// Tags are only mandatory when filtering by them
+				if len(c.tagFilters) > 0 {
+					errs = append(errs, fmt.Errorf("failed to list tags for ECR repository %s in region %s: %w", aws.ToString(repo.RepositoryName), c.region, err))
+					recordSkip("ecr", c.region, aws.ToString(repo.RepositoryName), "tags unavailable for --tag", err)
+					continue
+				}
+				slog.Warn("Could not list ECR repository tags", "repository", *repo.RepositoryName, "region", c.region, "error", err)
+			}
+			if !matchesTagFilters(tags, c.tagFilters) {
+				continue
+			}
+
+			lastPush, imageCount, err := c.getLastPushTimeAndCount(ctx, repo.RepositoryName)
 			if err != nil {
-				// Log or handle error, maybe mark as potentially idle or skip
-				fmt.Printf("Warning: Could not get image details for %s in %s: %v\n", *repo.RepositoryName, c.region, err)
+				// Without the images the repository would look never pushed, and so idle
+				recordSkip("ecr", c.region, aws.ToString(repo.RepositoryName), "image details unavailable", err)
+				continue
 			}
 
 			idle := isECRRepositoryIdle(lastPush)
@@ -66,15 +107,31 @@ func (c *ECRClient) GetIdleRepositories() ([]models.RepositoryInfo, error) {
 				CreatedAt:  repo.CreatedAt,
 				Idle:       idle,
 				ImageCount: imageCount,
+				Tags:       tags,
 			})
 		}
 	}
 
-	return idleRepos, nil
+	return idleRepos, errors.Join(errs...)
+}
+
+// getRepositoryTags returns the tags of an ECR repository
+func (c *ECRClient) getRepositoryTags(ctx context.Context, repoArn *string) (map[string]string, error) {
+	output, err := c.client.ListTagsForResource(ctx, &ecr.ListTagsForResourceInput{
+		ResourceArn: repoArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(output.Tags))
+	for _, tag := range output.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
 }
 
 // getLastPushTimeAndCount finds the most recent image push time and total image count for a repository
-func (c *ECRClient) getLastPushTimeAndCount(repoName *string) (*time.Time, int, error) {
+func (c *ECRClient) getLastPushTimeAndCount(ctx context.Context, repoName *string) (*time.Time, int, error) {
 	input := &ecr.DescribeImagesInput{
 		RepositoryName: repoName,
 	}
@@ -84,7 +141,7 @@ func (c *ECRClient) getLastPushTimeAndCount(repoName *string) (*time.Time, int,
 	imageCount := 0
 
 	for imagePaginator.HasMorePages() {
-		page, err := imagePaginator.NextPage(context.TODO())
+		page, err := imagePaginator.NextPage(ctx)
 		if err != nil {
 			// Handle errors, e.g., repository contains no images
 			if _, ok := err.(*types.ImageNotFoundException); ok {
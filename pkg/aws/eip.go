@@ -4,65 +4,129 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/pricing"
 	"github.com/younsl/idled/pkg/utils"
 )
 
+const (
+	// eipFilterBatchSize is the number of IDs passed per instance or ENI filter
+	eipFilterBatchSize = 100
+)
+
+// eipAPI is the EC2 API used by EIPClient
+type eipAPI interface {
+	ec2.DescribeInstancesAPIClient
+	ec2.DescribeNetworkInterfacesAPIClient
+	DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
+	ReleaseAddress(ctx context.Context, params *ec2.ReleaseAddressInput, optFns ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error)
+}
+
 // EIPClient struct for Elastic IP client
 type EIPClient struct {
-	client *ec2.Client
-	region string
+	client     eipAPI
+	region     string
+	tagFilters map[string]string
+	accountID  string // Account of the Elastic IP ARNs
+	pricing    *pricing.PricingService
 }
 
 // NewEIPClient creates a new EIPClient
-func NewEIPClient(region string) (*EIPClient, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("error loading AWS config: %w", err)
-	}
-
+func NewEIPClient(cfg aws.Config) *EIPClient {
 	client := ec2.NewFromConfig(cfg)
 	return &EIPClient{
-		client: client,
-		region: region,
-	}, nil
+		client:  client,
+		region:  cfg.Region,
+		pricing: pricing.Default(),
+	}
+}
+
+// SetTagFilters limits results to Elastic IPs carrying all of the given tags
+func (c *EIPClient) SetTagFilters(tags map[string]string) {
+	c.tagFilters = tags
 }
 
-// GetUnattachedEIPs returns a list of all Elastic IPs that are not attached to running instances
-func (c *EIPClient) GetUnattachedEIPs() ([]models.EIPInfo, error) {
-	input := &ec2.DescribeAddressesInput{}
+// SetAccountID sets the account used to build the ARNs of the Elastic IPs, left empty if not set
+func (c *EIPClient) SetAccountID(accountID string) {
+	c.accountID = accountID
+}
 
-	result, err := c.client.DescribeAddresses(context.TODO(), input)
+// SetPricingService sets the pricing service used to estimate address costs
+func (c *EIPClient) SetPricingService(service *pricing.PricingService) {
+	c.pricing = service
+}
+
+// ReleaseAddress releases an Elastic IP. The address must not be associated.
+func (c *EIPClient) ReleaseAddress(ctx context.Context, allocationID string) error {
+	_, err := c.client.ReleaseAddress(ctx, &ec2.ReleaseAddressInput{AllocationId: aws.String(allocationID)})
+	if err != nil {
+		return fmt.Errorf("failed to release Elastic IP %s: %w", allocationID, err)
+	}
+	return nil
+}
+
+// GetUnattachedEIPs returns a list of all billed Elastic IPs that are not serving traffic:
+// unassociated addresses, addresses associated with stopped instances, and addresses
+// associated with network interfaces that are not attached to anything
+func (c *EIPClient) GetUnattachedEIPs(ctx context.Context) ([]models.EIPInfo, error) {
+	input := &ec2.DescribeAddressesInput{
+		Filters: ec2TagFilters(c.tagFilters),
+	}
+
+	result, err := c.client.DescribeAddresses(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("error querying Elastic IPs: %w", err)
 	}
 
+	// Collect the associated instances and ENIs to cross-reference
+	var instanceIDs, eniIDs []string
+	for _, eip := range result.Addresses {
+		switch {
+		case aws.ToString(eip.InstanceId) != "":
+			instanceIDs = append(instanceIDs, aws.ToString(eip.InstanceId))
+		case aws.ToString(eip.NetworkInterfaceId) != "":
+			eniIDs = append(eniIDs, aws.ToString(eip.NetworkInterfaceId))
+		}
+	}
+
+	instanceStates, err := c.getInstanceStates(ctx, instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	attachedENIs, err := c.getAttachedENIs(ctx, eniIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	eips := []models.EIPInfo{}
 
 	for _, eip := range result.Addresses {
-		// Check if the EIP is not associated with any resources
-		isUnattached := eip.AssociationId == nil || *eip.AssociationId == ""
+		associationType, associationState := classifyEIPAssociation(eip, instanceStates, attachedENIs)
 
-		// We're only interested in unattached EIPs
-		if !isUnattached {
+		// We're only interested in EIPs that are billed without serving traffic
+		if associationType == "" {
 			continue
 		}
 
-		// Set fixed cost for EIPs - currently AWS charges about $0.005 per hour ($3.60 per month) for unused EIPs
-		monthlyCost := 3.60 // Fixed monthly cost for an unused EIP
+		monthlyCost, pricingSource := c.pricing.GetEIPMonthlyCost(c.region)
 
 		eipInfo := models.EIPInfo{
 			AllocationID:         *eip.AllocationId,
+			ARN:                  ec2ResourceARN(c.region, c.accountID, "elastic-ip/"+aws.ToString(eip.AllocationId)),
 			PublicIP:             *eip.PublicIp,
 			AssociationID:        utils.SafeDeref(eip.AssociationId),
-			AssociationState:     "Unattached",
+			AssociationType:      associationType,
+			AssociationState:     associationState,
 			InstanceID:           utils.SafeDeref(eip.InstanceId),
 			NetworkInterfaceID:   utils.SafeDeref(eip.NetworkInterfaceId),
 			Region:               c.region,
 			EstimatedMonthlyCost: monthlyCost,
-			PricingSource:        "Fixed", // EIP pricing is fixed
+			PricingSource:        pricingSource,
+			Tags:                 utils.GetTagsMap(eip.Tags),
 		}
 
 		eips = append(eips, eipInfo)
@@ -70,3 +134,93 @@ func (c *EIPClient) GetUnattachedEIPs() ([]models.EIPInfo, error) {
 
 	return eips, nil
 }
+
+// classifyEIPAssociation returns the association type and its display text for an
+// idle Elastic IP, or empty strings if the address is in use. Instances missing from
+// instanceStates and ENIs missing from attachedENIs are treated as in use, since they
+// may have changed between the calls.
+func classifyEIPAssociation(eip types.Address, instanceStates map[string]types.InstanceStateName, attachedENIs map[string]bool) (string, string) {
+	instanceID := aws.ToString(eip.InstanceId)
+	eniID := aws.ToString(eip.NetworkInterfaceId)
+
+	switch {
+	case aws.ToString(eip.AssociationId) == "" && instanceID == "" && eniID == "":
+		return models.EIPAssociationUnattached, "Unattached"
+	case instanceID != "":
+		if state, found := instanceStates[instanceID]; found && state == types.InstanceStateNameStopped {
+			return models.EIPAssociationStoppedInstance, "Stopped instance " + instanceID
+		}
+	case eniID != "":
+		if attached, found := attachedENIs[eniID]; found && !attached {
+			return models.EIPAssociationOrphanedENI, "Orphaned ENI " + eniID
+		}
+	}
+
+	return "", ""
+}
+
+// getInstanceStates returns the state of each of the given instances
+func (c *EIPClient) getInstanceStates(ctx context.Context, instanceIDs []string) (map[string]types.InstanceStateName, error) {
+	states := make(map[string]types.InstanceStateName)
+
+	// Filter by ID instead of InstanceIds so that a terminated instance doesn't fail the call
+	for _, batch := range batchStrings(instanceIDs, eipFilterBatchSize) {
+		input := &ec2.DescribeInstancesInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("instance-id"),
+					Values: batch,
+				},
+			},
+		}
+
+		paginator := ec2.NewDescribeInstancesPaginator(c.client, input)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error querying instances associated with Elastic IPs: %w", err)
+			}
+
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					if instance.State != nil {
+						states[aws.ToString(instance.InstanceId)] = instance.State.Name
+					}
+				}
+			}
+		}
+	}
+
+	return states, nil
+}
+
+// getAttachedENIs returns whether each of the given network interfaces is attached
+func (c *EIPClient) getAttachedENIs(ctx context.Context, eniIDs []string) (map[string]bool, error) {
+	attached := make(map[string]bool)
+
+	for _, batch := range batchStrings(eniIDs, eipFilterBatchSize) {
+		input := &ec2.DescribeNetworkInterfacesInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("network-interface-id"),
+					Values: batch,
+				},
+			},
+		}
+
+		paginator := ec2.NewDescribeNetworkInterfacesPaginator(c.client, input)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error querying network interfaces associated with Elastic IPs: %w", err)
+			}
+
+			for _, eni := range page.NetworkInterfaces {
+				attached[aws.ToString(eni.NetworkInterfaceId)] = eni.Attachment != nil &&
+					eni.Attachment.Status != types.AttachmentStatusDetached
+			}
+		}
+	}
+
+	return attached, nil
+}
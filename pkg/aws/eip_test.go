@@ -0,0 +1,144 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+func address(allocationID, associationID, instanceID, eniID string) types.Address {
+	eip := types.Address{
+		AllocationId: aws.String(allocationID),
+		PublicIp:     aws.String("203.0.113.1"),
+	}
+	if associationID != "" {
+		eip.AssociationId = aws.String(associationID)
+	}
+	if instanceID != "" {
+		eip.InstanceId = aws.String(instanceID)
+	}
+	if eniID != "" {
+		eip.NetworkInterfaceId = aws.String(eniID)
+	}
+	return eip
+}
+
+func TestGetUnattachedEIPs(t *testing.T) {
+	fake := &mocks.EC2{
+		DescribeAddressesFunc: func(ctx context.Context, params *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+			return &ec2.DescribeAddressesOutput{Addresses: []types.Address{
+				address("eipalloc-unattached", "", "", ""),
+				address("eipalloc-stopped", "eipassoc-1", "i-stopped", "eni-stopped"),
+				address("eipalloc-running", "eipassoc-2", "i-running", "eni-running"),
+				address("eipalloc-orphaned", "eipassoc-3", "", "eni-detached"),
+				address("eipalloc-attached", "eipassoc-4", "", "eni-attached"),
+				address("eipalloc-vanished", "eipassoc-5", "i-vanished", ""),
+			}}, nil
+		},
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				{InstanceId: aws.String("i-stopped"), State: &types.InstanceState{Name: types.InstanceStateNameStopped}},
+				{InstanceId: aws.String("i-running"), State: &types.InstanceState{Name: types.InstanceStateNameRunning}},
+			}}}}, nil
+		},
+		DescribeNetworkInterfacesFunc: func(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error) {
+			return &ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: []types.NetworkInterface{
+				{NetworkInterfaceId: aws.String("eni-detached")},
+				{NetworkInterfaceId: aws.String("eni-attached"), Attachment: &types.NetworkInterfaceAttachment{Status: types.AttachmentStatusAttached}},
+			}}, nil
+		},
+	}
+	client := &EIPClient{client: fake, region: "us-east-1", accountID: "123456789012", pricing: newTestPricing()}
+
+	eips, err := client.GetUnattachedEIPs(context.Background())
+	if err != nil {
+		t.Fatalf("GetUnattachedEIPs() error = %v", err)
+	}
+
+	want := map[string]string{
+		"eipalloc-unattached": models.EIPAssociationUnattached,
+		"eipalloc-stopped":    models.EIPAssociationStoppedInstance,
+		"eipalloc-orphaned":   models.EIPAssociationOrphanedENI,
+	}
+	if len(eips) != len(want) {
+		t.Fatalf("GetUnattachedEIPs() returned %d addresses, want %d", len(eips), len(want))
+	}
+	for _, eip := range eips {
+		associationType, found := want[eip.AllocationID]
+		if !found {
+			t.Errorf("GetUnattachedEIPs() returned %s, which is in use", eip.AllocationID)
+			continue
+		}
+		if eip.AssociationType != associationType {
+			t.Errorf("%s association type = %q, want %q", eip.AllocationID, eip.AssociationType, associationType)
+		}
+		if eip.EstimatedMonthlyCost <= 0 {
+			t.Errorf("%s cost = %v, want a fallback price", eip.AllocationID, eip.EstimatedMonthlyCost)
+		}
+		if wantARN := "arn:aws:ec2:us-east-1:123456789012:elastic-ip/" + eip.AllocationID; eip.ARN != wantARN {
+			t.Errorf("%s ARN = %q, want %q", eip.AllocationID, eip.ARN, wantARN)
+		}
+	}
+}
+
+func TestGetUnattachedEIPsError(t *testing.T) {
+	fake := &mocks.EC2{
+		DescribeAddressesFunc: func(ctx context.Context, params *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+			return &ec2.DescribeAddressesOutput{Addresses: []types.Address{address("eipalloc-1", "eipassoc-1", "i-1", "")}}, nil
+		},
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+	client := &EIPClient{client: fake, region: "us-east-1", pricing: newTestPricing()}
+
+	if _, err := client.GetUnattachedEIPs(context.Background()); err == nil {
+		t.Fatal("GetUnattachedEIPs() error = nil, want the DescribeInstances error")
+	}
+}
+
+func TestGetUnattachedEIPsFollowsInstancePages(t *testing.T) {
+	var addressCalls, instanceCalls int
+	fake := &mocks.EC2{
+		// DescribeAddresses is not paginated and returns every Elastic IP at once
+		DescribeAddressesFunc: func(ctx context.Context, params *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+			addressCalls++
+			return &ec2.DescribeAddressesOutput{Addresses: []types.Address{
+				address("eipalloc-1", "eipassoc-1", "i-1", ""),
+				address("eipalloc-2", "eipassoc-2", "i-2", ""),
+			}}, nil
+		},
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+			instanceCalls++
+			stopped := &types.InstanceState{Name: types.InstanceStateNameStopped}
+			if aws.ToString(params.NextToken) == "" {
+				return &ec2.DescribeInstancesOutput{
+					Reservations: []types.Reservation{{Instances: []types.Instance{{InstanceId: aws.String("i-1"), State: stopped}}}},
+					NextToken:    aws.String("page-2"),
+				}, nil
+			}
+			return &ec2.DescribeInstancesOutput{
+				Reservations: []types.Reservation{{Instances: []types.Instance{{InstanceId: aws.String("i-2"), State: stopped}}}},
+			}, nil
+		},
+	}
+	client := &EIPClient{client: fake, region: "us-east-1", pricing: newTestPricing()}
+
+	eips, err := client.GetUnattachedEIPs(context.Background())
+	if err != nil {
+		t.Fatalf("GetUnattachedEIPs() error = %v", err)
+	}
+	if addressCalls != 1 || instanceCalls != 2 {
+		t.Errorf("DescribeAddresses and DescribeInstances called %d and %d times, want 1 and 2", addressCalls, instanceCalls)
+	}
+	if len(eips) != 2 {
+		t.Errorf("GetUnattachedEIPs() returned %d addresses, want both addresses of the stopped instances", len(eips))
+	}
+}
@@ -0,0 +1,240 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+const (
+	elastiCacheCheckPeriodDays = 30
+	elastiCacheNamespace       = "AWS/ElastiCache"
+	// Serverless cache metrics use the clusterId dimension
+	elastiCacheServerlessDimension = "clusterId"
+	elastiCacheMetricRequests      = "TotalCmdsCount"
+	elastiCacheMetricStoredBytes   = "BytesUsedForCache"
+	// Redis OSS 5.0.6 and later can be upgraded in place to Valkey
+	elastiCacheValkeyMinimumVersion = "5.0.6"
+)
+
+// elastiCacheAPI is the ElastiCache API used by ElastiCacheScanner
+type elastiCacheAPI interface {
+	elasticache.DescribeCacheClustersAPIClient
+	elasticache.DescribeServerlessCachesAPIClient
+}
+
+// ElastiCacheScanner contains the AWS clients needed for scanning ElastiCache resources
+type ElastiCacheScanner struct {
+	ElastiCacheClient elastiCacheAPI
+	CWClient          cloudWatchMetricsAPI
+	Region            string
+}
+
+// NewElastiCacheScanner creates a new ElastiCacheScanner for a given region
+func NewElastiCacheScanner(cfg aws.Config) *ElastiCacheScanner {
+	return &ElastiCacheScanner{
+		ElastiCacheClient: elasticache.NewFromConfig(cfg),
+		CWClient:          cloudwatch.NewFromConfig(cfg),
+		Region:            cfg.Region,
+	}
+}
+
+// GetIdleElastiCaches scans serverless caches for idleness and provisioned Redis OSS
+// clusters for Valkey migration opportunities
+func (s *ElastiCacheScanner) GetIdleElastiCaches(ctx context.Context) ([]models.ElastiCacheInfo, []error) {
+	var allCaches []models.ElastiCacheInfo
+	var scanErrs []error
+
+	serverless, errs := s.getServerlessCaches(ctx)
+	allCaches = append(allCaches, serverless...)
+	scanErrs = append(scanErrs, errs...)
+
+	provisioned, err := s.getValkeyEligibleClusters(ctx)
+	if err != nil {
+		scanErrs = append(scanErrs, err)
+	}
+	allCaches = append(allCaches, provisioned...)
+
+	return allCaches, scanErrs
+}
+
+// getServerlessCaches lists serverless caches with their stored data and request metrics
+func (s *ElastiCacheScanner) getServerlessCaches(ctx context.Context) ([]models.ElastiCacheInfo, []error) {
+	var caches []models.ElastiCacheInfo
+	var errs []error
+
+	paginator := elasticache.NewDescribeServerlessCachesPaginator(s.ElastiCacheClient, &elasticache.DescribeServerlessCachesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing ElastiCache serverless caches: %w", err))
+			break
+		}
+
+		for _, cache := range page.ServerlessCaches {
+			name := aws.ToString(cache.ServerlessCacheName)
+			engine := aws.ToString(cache.Engine)
+			version := aws.ToString(cache.MajorEngineVersion)
+
+			storedBytes, err := s.getServerlessMetric(ctx, name, elastiCacheMetricStoredBytes, cwtypes.StatisticAverage)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			requests, err := s.getServerlessMetric(ctx, name, elastiCacheMetricRequests, cwtypes.StatisticSum)
+			if err != nil {
+				errs = append(errs, err)
+				recordSkip("elasticache", s.Region, name, "metrics unavailable", err)
+				continue // Cannot determine idleness without request metrics
+			}
+
+			// Missing datapoints mean no commands were recorded in the period
+			isIdle := requests == nil || *requests == 0
+			idleReason := ""
+			if isIdle {
+				idleReason = fmt.Sprintf("No requests (%dd)", elastiCacheCheckPeriodDays)
+			}
+
+			// Serverless caches are billed for stored data even without any requests
+			monthlyCost := pricing.CalculateElastiCacheServerlessMonthlyCost(engine, aws.ToFloat64(storedBytes))
+
+			info := models.ElastiCacheInfo{
+				Name:                 name,
+				ARN:                  aws.ToString(cache.ARN),
+				Region:               s.Region,
+				DeploymentType:       pricing.ElastiCacheDeploymentServerless,
+				Engine:               engine,
+				EngineVersion:        version,
+				Status:               aws.ToString(cache.Status),
+				CreationTime:         cache.CreateTime,
+				StoredBytes:          storedBytes,
+				RequestCount:         requests,
+				IsIdle:               isIdle,
+				IdleReason:           idleReason,
+				EstimatedMonthlyCost: monthlyCost,
+			}
+			applyValkeyRecommendation(&info)
+			caches = append(caches, info)
+		}
+	}
+
+	return caches, errs
+}
+
+// getValkeyEligibleClusters lists provisioned Redis OSS clusters that can be migrated to Valkey.
+// Nodes belonging to the same replication group are reported once.
+func (s *ElastiCacheScanner) getValkeyEligibleClusters(ctx context.Context) ([]models.ElastiCacheInfo, error) {
+	var clusters []models.ElastiCacheInfo
+	groupIndex := make(map[string]int) // Replication group ID -> index in clusters
+
+	paginator := elasticache.NewDescribeCacheClustersPaginator(s.ElastiCacheClient, &elasticache.DescribeCacheClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return clusters, fmt.Errorf("error listing ElastiCache clusters: %w", err)
+		}
+
+		for _, cluster := range page.CacheClusters {
+			engine := aws.ToString(cluster.Engine)
+			version := aws.ToString(cluster.EngineVersion)
+			if !isValkeyEligible(engine, version) {
+				continue
+			}
+
+			nodeCount := int(aws.ToInt32(cluster.NumCacheNodes))
+			groupID := aws.ToString(cluster.ReplicationGroupId)
+			if idx, found := groupIndex[groupID]; found && groupID != "" {
+				clusters[idx].NodeCount += nodeCount
+				continue
+			}
+
+			name := aws.ToString(cluster.CacheClusterId)
+			if groupID != "" {
+				name = groupID
+				groupIndex[groupID] = len(clusters)
+			}
+
+			info := models.ElastiCacheInfo{
+				Name:           name,
+				ARN:            aws.ToString(cluster.ARN),
+				Region:         s.Region,
+				DeploymentType: pricing.ElastiCacheDeploymentProvisioned,
+				Engine:         engine,
+				EngineVersion:  version,
+				NodeType:       aws.ToString(cluster.CacheNodeType),
+				NodeCount:      nodeCount,
+				Status:         aws.ToString(cluster.CacheClusterStatus),
+				CreationTime:   cluster.CacheClusterCreateTime,
+			}
+			applyValkeyRecommendation(&info)
+			clusters = append(clusters, info)
+		}
+	}
+
+	return clusters, nil
+}
+
+// applyValkeyRecommendation sets the Valkey migration fields for eligible Redis OSS caches
+func applyValkeyRecommendation(info *models.ElastiCacheInfo) {
+	if !isValkeyEligible(info.Engine, info.EngineVersion) {
+		return
+	}
+	info.ValkeyEligible = true
+	info.ValkeySavingsPercent = pricing.ElastiCacheValkeyDiscountPercent[info.DeploymentType]
+	info.Recommendation = fmt.Sprintf("Migrate to Valkey (-%.0f%%)", info.ValkeySavingsPercent)
+}
+
+// isValkeyEligible reports whether a cache running the given engine and version can be upgraded to Valkey
+func isValkeyEligible(engine, version string) bool {
+	if !strings.EqualFold(engine, "redis") {
+		return false
+	}
+	return compareEngineVersions(version, elastiCacheValkeyMinimumVersion) >= 0
+}
+
+// compareEngineVersions compares dotted engine versions such as "7.1", "6.x" or "5.0.6".
+// Non-numeric parts (e.g., "x") and missing parts are treated as 0.
+func compareEngineVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[i])
+		}
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// getServerlessMetric fetches a single statistic of a serverless cache metric over the check period,
+// or nil if no datapoints were published
+func (s *ElastiCacheScanner) getServerlessMetric(ctx context.Context, cacheName, metricName string, statistic cwtypes.Statistic) (*float64, error) {
+	value, err := cloudwatchutil.ValueOverWindow(ctx, s.CWClient, cloudwatchutil.MetricQuery{
+		Namespace:  elastiCacheNamespace,
+		Name:       metricName,
+		Dimensions: []cwtypes.Dimension{cloudwatchutil.Dimension(elastiCacheServerlessDimension, cacheName)},
+		Stat:       statistic,
+		WindowDays: elastiCacheCheckPeriodDays,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CloudWatch API error for metric %s of serverless cache %s: %w", metricName, cacheName, err)
+	}
+	return value, nil
+}
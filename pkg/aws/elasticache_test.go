@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+func TestIsValkeyEligible(t *testing.T) {
+	tests := []struct {
+		engine  string
+		version string
+		want    bool
+	}{
+		{engine: "redis", version: "7.1", want: true},
+		{engine: "redis", version: "6.x", want: true},
+		{engine: "Redis", version: "5.0.6", want: true},
+		{engine: "redis", version: "5.0.5", want: false},
+		{engine: "redis", version: "4.0.10", want: false},
+		{engine: "redis", version: "5", want: false},
+		{engine: "redis", version: "", want: false},
+		{engine: "valkey", version: "8.0", want: false},
+		{engine: "memcached", version: "1.6.22", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine+" "+tt.version, func(t *testing.T) {
+			if got := isValkeyEligible(tt.engine, tt.version); got != tt.want {
+				t.Errorf("isValkeyEligible(%q, %q) = %v, want %v", tt.engine, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyValkeyRecommendation(t *testing.T) {
+	tests := []struct {
+		name               string
+		info               models.ElastiCacheInfo
+		wantSavings        float64
+		wantRecommendation string
+	}{
+		{
+			name:               "serverless Redis OSS",
+			info:               models.ElastiCacheInfo{DeploymentType: pricing.ElastiCacheDeploymentServerless, Engine: "redis", EngineVersion: "7"},
+			wantSavings:        33,
+			wantRecommendation: "Migrate to Valkey (-33%)",
+		},
+		{
+			name:               "provisioned Redis OSS",
+			info:               models.ElastiCacheInfo{DeploymentType: pricing.ElastiCacheDeploymentProvisioned, Engine: "redis", EngineVersion: "6.2.6"},
+			wantSavings:        20,
+			wantRecommendation: "Migrate to Valkey (-20%)",
+		},
+		{
+			name: "already Valkey",
+			info: models.ElastiCacheInfo{DeploymentType: pricing.ElastiCacheDeploymentServerless, Engine: "valkey", EngineVersion: "8"},
+		},
+		{
+			name: "Redis OSS too old",
+			info: models.ElastiCacheInfo{DeploymentType: pricing.ElastiCacheDeploymentProvisioned, Engine: "redis", EngineVersion: "3.2.10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := tt.info
+			applyValkeyRecommendation(&info)
+			if info.ValkeyEligible != (tt.wantSavings > 0) || info.ValkeySavingsPercent != tt.wantSavings || info.Recommendation != tt.wantRecommendation {
+				t.Errorf("applyValkeyRecommendation() = eligible %v, savings %v, %q, want %v, %q",
+					info.ValkeyEligible, info.ValkeySavingsPercent, info.Recommendation, tt.wantSavings, tt.wantRecommendation)
+			}
+		})
+	}
+}
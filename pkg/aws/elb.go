@@ -2,17 +2,22 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
-	"time"
+	"log/slog"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	elbv1 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	elbv1types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
 	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
 )
 
 const (
@@ -20,61 +25,132 @@ const (
 	cloudWatchPeriodDays = 14
 
 	// AWS CloudWatch Namespaces
-	namespaceALB = "AWS/ApplicationELB"
-	namespaceNLB = "AWS/NetworkELB"
+	namespaceALB  = "AWS/ApplicationELB"
+	namespaceNLB  = "AWS/NetworkELB"
+	namespaceGWLB = "AWS/GatewayELB"
+	namespaceCLB  = "AWS/ELB"
 
 	// AWS CloudWatch Metric Names
 	metricRequestCount    = "RequestCount"
 	metricActiveFlowCount = "ActiveFlowCount"
+
+	// elbMetricMaxRetryAttempts allows extra retries since a failed metric check leaves idleness uncertain
+	elbMetricMaxRetryAttempts = 15
 )
 
+// classicELBAPI is the ELB (v1) API used by ELBScanner
+type classicELBAPI interface {
+	elbv1.DescribeLoadBalancersAPIClient
+	DescribeInstanceHealth(ctx context.Context, params *elbv1.DescribeInstanceHealthInput, optFns ...func(*elbv1.Options)) (*elbv1.DescribeInstanceHealthOutput, error)
+	DescribeTags(ctx context.Context, params *elbv1.DescribeTagsInput, optFns ...func(*elbv1.Options)) (*elbv1.DescribeTagsOutput, error)
+}
+
+// elbV2API is the ELB (v2) API used by ELBScanner
+type elbV2API interface {
+	elbv2.DescribeLoadBalancersAPIClient
+	elbv2.DescribeTargetGroupsAPIClient
+	DescribeLoadBalancerAttributes(ctx context.Context, params *elbv2.DescribeLoadBalancerAttributesInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeLoadBalancerAttributesOutput, error)
+	DescribeTags(ctx context.Context, params *elbv2.DescribeTagsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTagsOutput, error)
+	DescribeTargetHealth(ctx context.Context, params *elbv2.DescribeTargetHealthInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetHealthOutput, error)
+}
+
 // ELBScanner contains the AWS clients needed for scanning ELB resources
 type ELBScanner struct {
-	ELBV2Client *elbv2.Client
-	CWClient    *cloudwatch.Client
+	ELBClient   classicELBAPI // Classic Load Balancers
+	ELBV2Client elbV2API
+	CWClient    cloudWatchMetricsAPI
+	tagFilters  map[string]string
+
+	// assumeIdleOnMissingMetrics reports load balancers without healthy targets as idle
+	// even when their traffic metric could not be checked
+	assumeIdleOnMissingMetrics bool
+
+	pricing *pricing.PricingService
 }
 
 // NewELBScanner creates a new ELBScanner for a given region
 func NewELBScanner(cfg aws.Config) *ELBScanner {
 	return &ELBScanner{
+		ELBClient:   elbv1.NewFromConfig(cfg),
 		ELBV2Client: elbv2.NewFromConfig(cfg),
-		CWClient:    cloudwatch.NewFromConfig(cfg),
+		CWClient: cloudwatch.NewFromConfig(cfg, func(o *cloudwatch.Options) {
+			// Throttling errors are retried with exponential backoff before a metric check fails
+			o.Retryer = retry.AddWithMaxAttempts(o.Retryer, minMaxAttempts(elbMetricMaxRetryAttempts))
+		}),
+		pricing: pricing.Default(),
 	}
 }
 
-// GetIdleELBs scans for idle ALB and NLB resources in a specific region sequentially
-func (s *ELBScanner) GetIdleELBs(ctx context.Context, region string) ([]models.ELBResource, error) {
+// SetTagFilters limits results to load balancers carrying all of the given tags
+func (s *ELBScanner) SetTagFilters(tags map[string]string) {
+	s.tagFilters = tags
+}
+
+// SetAssumeIdleOnMissingMetrics reports load balancers without healthy targets as idle when
+// their traffic metric could not be checked, instead of only as uncertain
+func (s *ELBScanner) SetAssumeIdleOnMissingMetrics(enabled bool) {
+	s.assumeIdleOnMissingMetrics = enabled
+}
+
+// SetPricingService sets the pricing service used to estimate load balancer costs
+func (s *ELBScanner) SetPricingService(service *pricing.PricingService) {
+	s.pricing = service
+}
+
+// GetIdleELBs scans for idle ALB, NLB, GWLB, and Classic Load Balancer resources in a specific region
+// sequentially. Load balancers whose idleness is uncertain because of a failed metric check are
+// included with MetricCheckFailed set. Errors are collected per load balancer, and the load
+// balancers evaluated before a failure are still returned.
+func (s *ELBScanner) GetIdleELBs(ctx context.Context, region string) ([]models.ELBResource, []error) {
 	var idleELBs []models.ELBResource
 	var errs []error // Collect errors encountered during the scan
 
+	// Classic Load Balancers are only available through the ELB (v1) API
+	classicELBs, classicErrs := s.getIdleClassicELBs(ctx, region)
+	idleELBs = append(idleELBs, classicELBs...)
+	errs = append(errs, classicErrs...)
+
 	// Fetch Load Balancers using ELBv2 client
 	paginator := elbv2.NewDescribeLoadBalancersPaginator(s.ELBV2Client, &elbv2.DescribeLoadBalancersInput{})
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			// If pagination fails, we can't continue scanning this region
-			fetchErr := fmt.Errorf("error describing v2 load balancers in %s: %w", region, err)
-			// Return immediately with this error, potentially wrapping existing errors
-			if len(errs) > 0 {
-				return idleELBs, fmt.Errorf("pagination failed after encountering %d errors: %w. First error: %v", len(errs), fetchErr, errs[0])
+			// If pagination fails, we can't continue scanning this region, but keep the load balancers evaluated so far
+			errs = append(errs, fmt.Errorf("error describing v2 load balancers in %s: %w", region, err))
+			break
+		}
+
+		// Fetch tags for the whole page; they are required only when tag filtering is requested
+		lbTags, err := s.getLoadBalancerTags(ctx, page.LoadBalancers)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error describing load balancer tags in %s: %w", region, err))
+			if len(s.tagFilters) > 0 {
+				for _, lb := range page.LoadBalancers {
+					recordSkip("elb", region, aws.ToString(lb.LoadBalancerName), "tags unavailable for --tag", err)
+				}
+				continue
 			}
-			return nil, fetchErr
 		}
 
 		for _, lb := range page.LoadBalancers {
 			lbDesc := lb // Local copy for clarity
 
 			// Skip unsupported types
-			if lbDesc.Type != elbv2types.LoadBalancerTypeEnumApplication && lbDesc.Type != elbv2types.LoadBalancerTypeEnumNetwork {
+			shortType, supported := elbShortTypes[lbDesc.Type]
+			if !supported {
+				slog.Debug("Skipping load balancer of unsupported type", "loadBalancer", aws.ToString(lbDesc.LoadBalancerName), "type", lbDesc.Type, "region", region)
 				continue
 			}
 
 			// --- Process each LB sequentially ---
 			lbArn := aws.ToString(lbDesc.LoadBalancerArn)
+			if !matchesTagFilters(lbTags[lbArn], s.tagFilters) {
+				continue
+			}
 			lbName := aws.ToString(lbDesc.LoadBalancerName)
 			lbType := lbDesc.Type
 
-			isIdle, reason, healthyTargets, unhealthyTargets, lastActivitySum, checkErr := s.checkLoadBalancerIdleStatus(ctx, lbArn, lbType)
+			status, checkErr := s.checkLoadBalancerIdleStatus(ctx, lbArn, lbType)
 
 			if checkErr != nil {
 				// Record error for this specific LB check and continue to the next LB
@@ -83,46 +159,209 @@ func (s *ELBScanner) GetIdleELBs(ctx context.Context, region string) ([]models.E
 				continue                    // Don't add to idleELBs if check failed
 			}
 
-			if isIdle {
-				// Determine short type string
-				shortType := "Unknown"
-				if lbType == elbv2types.LoadBalancerTypeEnumApplication {
-					shortType = "ALB"
-				} else if lbType == elbv2types.LoadBalancerTypeEnumNetwork {
-					shortType = "NLB"
-				}
+			// Load balancers whose idleness is uncertain are reported too, but not as idle
+			if status.isIdle || status.metricCheckFailed {
+				monthlyCost, pricingSource := s.pricing.CalculateELBMonthlyCostWithSource(shortType, region)
 
-				idleELBs = append(idleELBs, models.ELBResource{
+				elb := models.ELBResource{
 					Name:                 lbName,
 					Type:                 shortType,
 					Region:               region,
 					State:                string(lbDesc.State.Code),
 					CreatedTime:          *lbDesc.CreatedTime,
 					ARN:                  lbArn,
-					HealthyTargetCount:   healthyTargets,
-					UnhealthyTargetCount: unhealthyTargets,
-					IdleReason:           reason,
-					LastActivitySum:      lastActivitySum,
-				})
+					HealthyTargetCount:   status.healthyTargets,
+					UnhealthyTargetCount: status.unhealthyTargets,
+					IsIdle:               status.isIdle,
+					IdleReason:           status.reason,
+					LastActivitySum:      status.metricSum,
+					MetricCheckFailed:    status.metricCheckFailed,
+					EstimatedMonthlyCost: monthlyCost,
+					PricingSource:        pricingSource,
+					Tags:                 lbTags[lbArn],
+				}
+
+				protected, err := s.hasDeletionProtection(ctx, lbArn)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("error checking deletion protection for %s %s in %s: %w", lbType, lbName, region, err))
+				} else if protected {
+					elb.Protected = true
+					elb.ProtectionReason = "deletion protection"
+				}
+
+				idleELBs = append(idleELBs, elb)
 			}
 			// --- End sequential processing for this LB ---
 		}
 	}
 
-	if len(errs) > 0 {
-		// Return results found so far, along with the first error encountered
-		return idleELBs, fmt.Errorf("encountered %d errors during ELB scan (results might be incomplete), first error: %w", len(errs), errs[0])
+	return idleELBs, errs
+}
+
+// elbShortTypes maps the supported ELB (v2) load balancer types to their short type
+var elbShortTypes = map[elbv2types.LoadBalancerTypeEnum]string{
+	elbv2types.LoadBalancerTypeEnumApplication: "ALB",
+	elbv2types.LoadBalancerTypeEnumNetwork:     "NLB",
+	elbv2types.LoadBalancerTypeEnumGateway:     "GWLB",
+}
+
+// hasDeletionProtection reports whether deletion protection is enabled on an ELB (v2) load balancer.
+// Classic Load Balancers have no deletion protection.
+func (s *ELBScanner) hasDeletionProtection(ctx context.Context, lbArn string) (bool, error) {
+	result, err := s.ELBV2Client.DescribeLoadBalancerAttributes(ctx, &elbv2.DescribeLoadBalancerAttributesInput{
+		LoadBalancerArn: aws.String(lbArn),
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, attribute := range result.Attributes {
+		if aws.ToString(attribute.Key) == "deletion_protection.enabled" {
+			return aws.ToString(attribute.Value) == "true", nil
+		}
+	}
+	return false, nil
+}
+
+// getIdleClassicELBs scans for idle Classic Load Balancers. Errors checking a single load
+// balancer are collected so that the remaining ones are still scanned.
+func (s *ELBScanner) getIdleClassicELBs(ctx context.Context, region string) ([]models.ELBResource, []error) {
+	var idleELBs []models.ELBResource
+	var errs []error
+
+	paginator := elbv1.NewDescribeLoadBalancersPaginator(s.ELBClient, &elbv1.DescribeLoadBalancersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return idleELBs, append(errs, fmt.Errorf("error describing classic load balancers in %s: %w", region, err))
+		}
+
+		// Fetch tags for the whole page; they are required only when tag filtering is requested
+		lbTags, err := s.getClassicLoadBalancerTags(ctx, page.LoadBalancerDescriptions)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error describing classic load balancer tags in %s: %w", region, err))
+			if len(s.tagFilters) > 0 {
+				for _, lbDesc := range page.LoadBalancerDescriptions {
+					recordSkip("elb", region, aws.ToString(lbDesc.LoadBalancerName), "tags unavailable for --tag", err)
+				}
+				continue
+			}
+		}
+
+		for _, lbDesc := range page.LoadBalancerDescriptions {
+			lbName := aws.ToString(lbDesc.LoadBalancerName)
+			if !matchesTagFilters(lbTags[lbName], s.tagFilters) {
+				continue
+			}
+
+			status, checkErr := s.checkClassicLoadBalancerIdleStatus(ctx, lbName)
+			if checkErr != nil {
+				errs = append(errs, fmt.Errorf("error checking idle status for CLB %s in %s: %w", lbName, region, checkErr))
+				continue
+			}
+
+			if status.isIdle || status.metricCheckFailed {
+				monthlyCost, pricingSource := s.pricing.CalculateELBMonthlyCostWithSource("CLB", region)
+
+				idleELBs = append(idleELBs, models.ELBResource{
+					Name:                 lbName,
+					Type:                 "CLB",
+					Region:               region,
+					State:                "active", // Classic Load Balancers have no state
+					CreatedTime:          aws.ToTime(lbDesc.CreatedTime),
+					HealthyTargetCount:   status.healthyTargets,
+					UnhealthyTargetCount: status.unhealthyTargets,
+					IsIdle:               status.isIdle,
+					IdleReason:           status.reason,
+					LastActivitySum:      status.metricSum,
+					MetricCheckFailed:    status.metricCheckFailed,
+					EstimatedMonthlyCost: monthlyCost,
+					PricingSource:        pricingSource,
+					Tags:                 lbTags[lbName],
+				})
+			}
+		}
+	}
+
+	return idleELBs, errs
+}
+
+// getClassicLoadBalancerTags returns the tags of the given Classic Load Balancers keyed by name.
+// DescribeTags accepts at most 20 names per call, so the load balancers are processed in batches.
+func (s *ELBScanner) getClassicLoadBalancerTags(ctx context.Context, lbs []elbv1types.LoadBalancerDescription) (map[string]map[string]string, error) {
+	const describeTagsBatchSize = 20
+
+	tags := make(map[string]map[string]string)
+	for start := 0; start < len(lbs); start += describeTagsBatchSize {
+		end := min(start+describeTagsBatchSize, len(lbs))
+
+		var names []string
+		for _, lb := range lbs[start:end] {
+			names = append(names, aws.ToString(lb.LoadBalancerName))
+		}
+
+		output, err := s.ELBClient.DescribeTags(ctx, &elbv1.DescribeTagsInput{LoadBalancerNames: names})
+		if err != nil {
+			return nil, err
+		}
+		for _, desc := range output.TagDescriptions {
+			resourceTags := make(map[string]string, len(desc.Tags))
+			for _, tag := range desc.Tags {
+				resourceTags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			tags[aws.ToString(desc.LoadBalancerName)] = resourceTags
+		}
 	}
+	return tags, nil
+}
+
+// getLoadBalancerTags returns the tags of the given load balancers keyed by ARN.
+// DescribeTags accepts at most 20 ARNs per call, so the load balancers are processed in batches.
+func (s *ELBScanner) getLoadBalancerTags(ctx context.Context, lbs []elbv2types.LoadBalancer) (map[string]map[string]string, error) {
+	const describeTagsBatchSize = 20
 
-	return idleELBs, nil // Success, no errors
+	tags := make(map[string]map[string]string)
+	for start := 0; start < len(lbs); start += describeTagsBatchSize {
+		end := start + describeTagsBatchSize
+		if end > len(lbs) {
+			end = len(lbs)
+		}
+
+		var arns []string
+		for _, lb := range lbs[start:end] {
+			arns = append(arns, aws.ToString(lb.LoadBalancerArn))
+		}
+
+		output, err := s.ELBV2Client.DescribeTags(ctx, &elbv2.DescribeTagsInput{ResourceArns: arns})
+		if err != nil {
+			return nil, err
+		}
+		for _, desc := range output.TagDescriptions {
+			resourceTags := make(map[string]string, len(desc.Tags))
+			for _, tag := range desc.Tags {
+				resourceTags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			tags[aws.ToString(desc.ResourceArn)] = resourceTags
+		}
+	}
+	return tags, nil
+}
+
+// elbIdleStatus is the outcome of the idle check of a load balancer
+type elbIdleStatus struct {
+	isIdle            bool
+	reason            string
+	healthyTargets    int
+	unhealthyTargets  int
+	metricSum         *float64 // Traffic metric over the check period, nil if the check failed
+	metricCheckFailed bool     // The traffic metric could not be checked, so idleness is uncertain
 }
 
-// checkLoadBalancerIdleStatus determines if an ALB or NLB is idle
-func (s *ELBScanner) checkLoadBalancerIdleStatus(ctx context.Context, lbArn string, lbType elbv2types.LoadBalancerTypeEnum) (isIdle bool, reason string, healthyTargets, unhealthyTargets int, metricSum *float64, err error) {
+// checkLoadBalancerIdleStatus determines if an ALB, NLB, or GWLB is idle
+func (s *ELBScanner) checkLoadBalancerIdleStatus(ctx context.Context, lbArn string, lbType elbv2types.LoadBalancerTypeEnum) (elbIdleStatus, error) {
 	// 1. Get Target Counts
 	healthyTargets, unhealthyTargets, totalTargets, err := s.getTargetCounts(ctx, lbArn)
 	if err != nil {
-		return false, "", 0, 0, nil, fmt.Errorf("failed to get target counts: %w", err)
+		return elbIdleStatus{}, fmt.Errorf("failed to get target counts: %w", err)
 	}
 
 	// 2. Determine CloudWatch parameters based on LB type
@@ -139,54 +378,95 @@ func (s *ELBScanner) checkLoadBalancerIdleStatus(ctx context.Context, lbArn stri
 		cwMetricName = metricActiveFlowCount // Use constant
 		cwStatistic = cwtypes.StatisticAverage
 		cwMetricReason = "Zero ActiveFlowCount (Avg, 14d)"
+	case elbv2types.LoadBalancerTypeEnumGateway:
+		cwNamespace = namespaceGWLB
+		cwMetricName = metricActiveFlowCount
+		cwStatistic = cwtypes.StatisticAverage
+		cwMetricReason = "Zero ActiveFlowCount (Avg, 14d)"
 	default:
 		// Should not happen due to earlier check, but handle defensively
-		return false, "", 0, 0, nil, fmt.Errorf("unsupported load balancer type: %s", lbType)
+		return elbIdleStatus{}, fmt.Errorf("unsupported load balancer type: %s", lbType)
 	}
 
 	// 3. Check CloudWatch Metric
-	sum, cwErr := s.getMetricSum(ctx, lbArn, cwNamespace, cwMetricName, cwStatistic)
-	if cwErr != nil {
-		// If CloudWatch fails, we cannot definitively say it's idle based on traffic.
-		// We might still consider it idle if there are no healthy targets.
-		if healthyTargets == 0 {
-			reason = "No healthy targets registered"
-			if totalTargets == 0 {
-				reason = "No targets registered"
-			}
-			fmt.Printf("Warning: CloudWatch check failed for %s (%s), considering idle based on target health: %v\n", lbType, lbArn, cwErr)
-			return true, reason + " (CW Check Failed)", healthyTargets, unhealthyTargets, nil, nil // Return idle, but note CW failed
-		}
-		// Healthy targets exist, but CW failed - cannot determine idle status reliably.
-		return false, "", healthyTargets, unhealthyTargets, nil, fmt.Errorf("CloudWatch check failed: %w", cwErr)
+	sum, cwErr := getLoadBalancerMetricSum(ctx, s.CWClient, lbArn, cwNamespace, cwMetricName, cwStatistic, cloudWatchPeriodDays)
+	return s.evaluateIdleStatus(string(lbType), lbArn, healthyTargets, unhealthyTargets, totalTargets, sum, cwErr, cwMetricReason)
+}
+
+// checkClassicLoadBalancerIdleStatus determines if a Classic Load Balancer is idle from the
+// health of its registered instances and its RequestCount
+func (s *ELBScanner) checkClassicLoadBalancerIdleStatus(ctx context.Context, lbName string) (elbIdleStatus, error) {
+	// 1. Get Registered Instance Health
+	output, err := s.ELBClient.DescribeInstanceHealth(ctx, &elbv1.DescribeInstanceHealthInput{
+		LoadBalancerName: aws.String(lbName),
+	})
+	if err != nil {
+		return elbIdleStatus{}, fmt.Errorf("failed to describe instance health: %w", err)
 	}
-	metricSum = &sum
 
-	// 4. Determine Idle Status based on targets and metrics
-	if healthyTargets == 0 {
-		reason = "No healthy targets registered"
-		if totalTargets == 0 {
-			reason = "No targets registered"
-		}
-		if sum == 0 {
-			return true, reason + " & " + cwMetricReason, healthyTargets, unhealthyTargets, metricSum, nil
-		} else {
-			// No healthy targets, but recent traffic? Not idle.
-			return false, "", healthyTargets, unhealthyTargets, metricSum, nil
+	var healthyInstances, unhealthyInstances int
+	for _, state := range output.InstanceStates {
+		switch aws.ToString(state.State) {
+		case "InService":
+			healthyInstances++
+		case "OutOfService":
+			unhealthyInstances++
+			// "Unknown" instances are not counted as healthy or unhealthy explicitly here
 		}
 	}
 
-	// Healthy targets > 0
-	if sum == 0 {
-		// Healthy targets exist, but no recent traffic.
-		return true, cwMetricReason, healthyTargets, unhealthyTargets, metricSum, nil
+	// 2. Check CloudWatch Metric, which Classic Load Balancers publish by name
+	dimension := cloudwatchutil.Dimension("LoadBalancerName", lbName)
+	sum, cwErr := getLoadBalancerMetricValue(ctx, s.CWClient, namespaceCLB, metricRequestCount, cwtypes.StatisticSum, dimension, cloudWatchPeriodDays)
+	return s.evaluateIdleStatus("classic", lbName, healthyInstances, unhealthyInstances, len(output.InstanceStates), sum, cwErr, "Zero RequestCount (14d)")
+}
+
+// evaluateIdleStatus determines if a load balancer is idle from its target health and the
+// traffic metric over the check period. A failed metric check never proves the absence of
+// traffic, so a load balancer without healthy targets is then only uncertain, unless idleness
+// is assumed on missing metrics.
+func (s *ELBScanner) evaluateIdleStatus(lbType, lbID string, healthyTargets, unhealthyTargets, totalTargets int, sum float64, cwErr error, cwMetricReason string) (elbIdleStatus, error) {
+	status := elbIdleStatus{
+		healthyTargets:   healthyTargets,
+		unhealthyTargets: unhealthyTargets,
+	}
+
+	targetReason := "No healthy targets registered"
+	if totalTargets == 0 {
+		targetReason = "No targets registered"
 	}
 
-	// Healthy targets and recent traffic.
-	return false, "", healthyTargets, unhealthyTargets, metricSum, nil
+	if cwErr != nil {
+		// Healthy targets exist, but CW failed - cannot determine idle status reliably.
+		if healthyTargets > 0 {
+			return status, fmt.Errorf("CloudWatch check failed: %w", cwErr)
+		}
+		slog.Warn("CloudWatch check failed for a load balancer without healthy targets", "type", lbType, "loadBalancer", lbID, "assumeIdle", s.assumeIdleOnMissingMetrics, "error", cwErr)
+		status.metricCheckFailed = true
+		status.isIdle = s.assumeIdleOnMissingMetrics
+		status.reason = targetReason + " & traffic unknown"
+		return status, nil
+	}
+	status.metricSum = &sum
+
+	// Determine Idle Status based on targets and metrics
+	switch {
+	case sum != 0:
+		// Recent traffic, with or without healthy targets. Not idle.
+	case healthyTargets == 0:
+		status.isIdle = true
+		status.reason = targetReason + " & " + cwMetricReason
+	default:
+		// Healthy targets exist, but no recent traffic.
+		status.isIdle = true
+		status.reason = cwMetricReason
+	}
+	return status, nil
 }
 
-// getTargetCounts finds the number of healthy and unhealthy targets for a given ALB/NLB ARN
+// getTargetCounts finds the number of healthy and unhealthy targets for a given ALB/NLB ARN.
+// The health of every target group is checked, and the errors of those that could not be
+// described are returned together since the counts are then incomplete.
 func (s *ELBScanner) getTargetCounts(ctx context.Context, lbArn string) (healthyCount, unhealthyCount, totalCount int, err error) {
 	tgPaginator := elbv2.NewDescribeTargetGroupsPaginator(s.ELBV2Client, &elbv2.DescribeTargetGroupsInput{
 		LoadBalancerArn: aws.String(lbArn),
@@ -195,6 +475,7 @@ func (s *ELBScanner) getTargetCounts(ctx context.Context, lbArn string) (healthy
 	healthyCount = 0
 	unhealthyCount = 0
 	totalCount = 0
+	var healthErrs []error
 
 	for tgPaginator.HasMorePages() {
 		tgPage, pageErr := tgPaginator.NextPage(ctx)
@@ -211,8 +492,8 @@ func (s *ELBScanner) getTargetCounts(ctx context.Context, lbArn string) (healthy
 			}
 			healthOutput, healthErr := s.ELBV2Client.DescribeTargetHealth(ctx, healthInput)
 			if healthErr != nil {
-				fmt.Printf("Warning: error describing target health for TG %s: %v\n", *tg.TargetGroupArn, healthErr)
-				continue // Skip this TG, but don't fail the whole LB check
+				healthErrs = append(healthErrs, fmt.Errorf("error describing target health of %s: %w", *tg.TargetGroupArn, healthErr))
+				continue // Check the remaining TGs before failing the LB check
 			}
 
 			for _, thd := range healthOutput.TargetHealthDescriptions {
@@ -229,71 +510,37 @@ func (s *ELBScanner) getTargetCounts(ctx context.Context, lbArn string) (healthy
 			}
 		}
 	}
+	if len(healthErrs) > 0 {
+		return 0, 0, 0, errors.Join(healthErrs...)
+	}
 	return healthyCount, unhealthyCount, totalCount, nil
 }
 
-// getMetricSum retrieves the sum of a specific CloudWatch metric over the last N days
-func (s *ELBScanner) getMetricSum(ctx context.Context, lbArn, namespace, metricName string, statistic cwtypes.Statistic) (float64, error) {
-	// Extract LoadBalancer name/ID from ARN for dimensions
-	arnParts := strings.Split(lbArn, ":")
-	if len(arnParts) < 6 {
-		return 0, fmt.Errorf("invalid ELB ARN format: %s", lbArn)
-	}
-	lbPart := arnParts[5]
-	// Handle different ARN formats (e.g., app/my-alb/id, net/my-nlb/id)
-	if !strings.HasPrefix(lbPart, "loadbalancer/") {
-		return 0, fmt.Errorf("unexpected ELB ARN resource format: %s", lbPart)
-	}
-	lbDimensionValue := lbPart[len("loadbalancer/"):] // Get the part after loadbalancer/
-
-	dimensionName := "LoadBalancer"
-
-	now := time.Now()
-	startTime := now.AddDate(0, 0, -cloudWatchPeriodDays)
-	endTime := now
-
-	periodSeconds := int32(cloudWatchPeriodDays * 24 * 60 * 60) // Total seconds in the period
-
-	metricInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String(namespace),
-		MetricName: aws.String(metricName),
-		Dimensions: []cwtypes.Dimension{
-			{
-				Name:  aws.String(dimensionName),
-				Value: aws.String(lbDimensionValue),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(periodSeconds),
-		Statistics: []cwtypes.Statistic{statistic},
-	}
-
-	resp, err := s.CWClient.GetMetricStatistics(ctx, metricInput)
+// getLoadBalancerMetricSum retrieves a CloudWatch metric of an ALB, NLB, or GWLB over the last days,
+// aggregated with the given statistic
+func getLoadBalancerMetricSum(ctx context.Context, client cloudWatchMetricsAPI, lbArn, namespace, metricName string, statistic cwtypes.Statistic, days int) (float64, error) {
+	lbDimensionValue, err := ParseELBDimensionFromARN(lbArn)
 	if err != nil {
-		// Check for specific errors? e.g., no metrics found might not be a hard error
-		return 0, fmt.Errorf("failed to get CloudWatch metric %s for %s (dimension: %s=%s): %w",
-			metricName, lbArn, dimensionName, lbDimensionValue, err)
+		return 0, err
 	}
 
-	sum := 0.0
-	if len(resp.Datapoints) > 0 {
-		dp := resp.Datapoints[0] // Assuming one datapoint for the whole period
-		switch statistic {
-		case cwtypes.StatisticSum:
-			if dp.Sum != nil {
-				sum = *dp.Sum
-			}
-		case cwtypes.StatisticAverage:
-			if dp.Average != nil {
-				sum = *dp.Average
-			}
-		default:
-			if dp.Sum != nil { // Default to Sum if available
-				sum = *dp.Sum
-			}
-		}
-	}
+	dimension := cloudwatchutil.Dimension("LoadBalancer", lbDimensionValue)
+	return getLoadBalancerMetricValue(ctx, client, namespace, metricName, statistic, dimension, days)
+}
 
-	return sum, nil
+// getLoadBalancerMetricValue retrieves a CloudWatch metric of a load balancer over the last days,
+// aggregated with the given statistic. Missing datapoints count as zero.
+func getLoadBalancerMetricValue(ctx context.Context, client cloudWatchMetricsAPI, namespace, metricName string, statistic cwtypes.Statistic, dimension cwtypes.Dimension, days int) (float64, error) {
+	value, err := cloudwatchutil.ValueOverWindow(ctx, client, cloudwatchutil.MetricQuery{
+		Namespace:  namespace,
+		Name:       metricName,
+		Dimensions: []cwtypes.Dimension{dimension},
+		Stat:       statistic,
+		WindowDays: days,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get CloudWatch metric %s (dimension: %s=%s): %w",
+			metricName, aws.ToString(dimension.Name), aws.ToString(dimension.Value), err)
+	}
+	return aws.ToFloat64(value), nil
 }
@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+// targetGroups answers the target group lookups with one target group per list of target states
+func targetGroups(states ...[]elbv2types.TargetHealthStateEnum) *mocks.ELBV2 {
+	groups := make(map[string][]elbv2types.TargetHealthStateEnum, len(states))
+	var arns []string
+	for i, groupStates := range states {
+		arn := fmt.Sprintf("tg-%d", i)
+		groups[arn] = groupStates
+		arns = append(arns, arn)
+	}
+
+	return &mocks.ELBV2{
+		DescribeTargetGroupsFunc: func(ctx context.Context, params *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+			output := &elbv2.DescribeTargetGroupsOutput{}
+			for _, arn := range arns {
+				output.TargetGroups = append(output.TargetGroups, elbv2types.TargetGroup{TargetGroupArn: aws.String(arn)})
+			}
+			return output, nil
+		},
+		DescribeTargetHealthFunc: func(ctx context.Context, params *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+			output := &elbv2.DescribeTargetHealthOutput{}
+			for _, state := range groups[aws.ToString(params.TargetGroupArn)] {
+				output.TargetHealthDescriptions = append(output.TargetHealthDescriptions, elbv2types.TargetHealthDescription{
+					TargetHealth: &elbv2types.TargetHealth{State: state},
+				})
+			}
+			return output, nil
+		},
+	}
+}
+
+func TestCheckLoadBalancerIdleStatus(t *testing.T) {
+	const (
+		albARN  = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188"
+		nlbARN  = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/tcp/50dc6c495c0c9188"
+		gwlbARN = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/gwy/fw/50dc6c495c0c9188"
+	)
+	healthy := []elbv2types.TargetHealthStateEnum{elbv2types.TargetHealthStateEnumHealthy}
+	unhealthy := []elbv2types.TargetHealthStateEnum{elbv2types.TargetHealthStateEnumUnhealthy}
+
+	tests := []struct {
+		name          string
+		arn           string
+		lbType        elbv2types.LoadBalancerTypeEnum
+		elbv2         *mocks.ELBV2
+		metric        *float64 // nil for no datapoints
+		metricErr     error
+		assumeIdle    bool
+		wantNamespace string
+		wantMetric    string
+		wantIdle      bool
+		wantReason    string
+		wantUncertain bool
+		wantErr       bool
+	}{
+		{
+			name: "ALB with healthy targets and no requests", arn: albARN, lbType: elbv2types.LoadBalancerTypeEnumApplication,
+			elbv2: targetGroups(healthy), metric: aws.Float64(0),
+			wantNamespace: namespaceALB, wantMetric: metricRequestCount,
+			wantIdle: true, wantReason: "Zero RequestCount (14d)",
+		},
+		{
+			name: "ALB without targets or datapoints", arn: albARN, lbType: elbv2types.LoadBalancerTypeEnumApplication,
+			elbv2:         targetGroups(),
+			wantNamespace: namespaceALB, wantMetric: metricRequestCount,
+			wantIdle: true, wantReason: "No targets registered & Zero RequestCount (14d)",
+		},
+		{
+			name: "ALB serving requests", arn: albARN, lbType: elbv2types.LoadBalancerTypeEnumApplication,
+			elbv2: targetGroups(healthy, unhealthy), metric: aws.Float64(1200),
+			wantNamespace: namespaceALB, wantMetric: metricRequestCount,
+		},
+		{
+			name: "NLB with flows but only unhealthy targets", arn: nlbARN, lbType: elbv2types.LoadBalancerTypeEnumNetwork,
+			elbv2: targetGroups(unhealthy), metric: aws.Float64(3),
+			wantNamespace: namespaceNLB, wantMetric: metricActiveFlowCount,
+		},
+		{
+			name: "NLB with unhealthy targets and no flows", arn: nlbARN, lbType: elbv2types.LoadBalancerTypeEnumNetwork,
+			elbv2: targetGroups(unhealthy), metric: aws.Float64(0),
+			wantNamespace: namespaceNLB, wantMetric: metricActiveFlowCount,
+			wantIdle: true, wantReason: "No healthy targets registered & Zero ActiveFlowCount (Avg, 14d)",
+		},
+		{
+			name: "GWLB without healthy targets and a failed metric check", arn: gwlbARN, lbType: elbv2types.LoadBalancerTypeEnumGateway,
+			elbv2: targetGroups(unhealthy), metricErr: errors.New("throttled"),
+			wantNamespace: namespaceGWLB, wantMetric: metricActiveFlowCount,
+			wantReason: "No healthy targets registered & traffic unknown", wantUncertain: true,
+		},
+		{
+			name: "failed metric check assumed idle", arn: gwlbARN, lbType: elbv2types.LoadBalancerTypeEnumGateway,
+			elbv2: targetGroups(unhealthy), metricErr: errors.New("throttled"), assumeIdle: true,
+			wantNamespace: namespaceGWLB, wantMetric: metricActiveFlowCount,
+			wantIdle: true, wantReason: "No healthy targets registered & traffic unknown", wantUncertain: true,
+		},
+		{
+			name: "healthy targets and a failed metric check", arn: albARN, lbType: elbv2types.LoadBalancerTypeEnumApplication,
+			elbv2: targetGroups(healthy), metricErr: errors.New("throttled"),
+			wantNamespace: namespaceALB, wantMetric: metricRequestCount,
+			wantErr: true,
+		},
+		{
+			name: "target health unavailable", arn: albARN, lbType: elbv2types.LoadBalancerTypeEnumApplication,
+			elbv2: &mocks.ELBV2{
+				DescribeTargetGroupsFunc: func(ctx context.Context, params *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+					return &elbv2.DescribeTargetGroupsOutput{TargetGroups: []elbv2types.TargetGroup{{TargetGroupArn: aws.String("tg")}}}, nil
+				},
+				DescribeTargetHealthFunc: func(ctx context.Context, params *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+					return nil, errors.New("access denied")
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cw := &mocks.CloudWatch{
+				GetMetricStatisticsFunc: func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error) {
+					if got := aws.ToString(params.Namespace); got != tt.wantNamespace {
+						t.Errorf("metric namespace = %q, want %q", got, tt.wantNamespace)
+					}
+					if got := aws.ToString(params.MetricName); got != tt.wantMetric {
+						t.Errorf("metric name = %q, want %q", got, tt.wantMetric)
+					}
+					if tt.metricErr != nil {
+						return nil, tt.metricErr
+					}
+					output := &cloudwatch.GetMetricStatisticsOutput{}
+					if tt.metric != nil {
+						output.Datapoints = []cwtypes.Datapoint{{
+							Timestamp: aws.Time(time.Now().Add(-time.Hour)),
+							Sum:       tt.metric,
+							Average:   tt.metric,
+						}}
+					}
+					return output, nil
+				},
+			}
+			scanner := &ELBScanner{ELBV2Client: tt.elbv2, CWClient: cw, assumeIdleOnMissingMetrics: tt.assumeIdle}
+
+			status, err := scanner.checkLoadBalancerIdleStatus(context.Background(), tt.arn, tt.lbType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkLoadBalancerIdleStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if status.isIdle != tt.wantIdle {
+				t.Errorf("isIdle = %v, want %v", status.isIdle, tt.wantIdle)
+			}
+			if status.reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", status.reason, tt.wantReason)
+			}
+			if status.metricCheckFailed != tt.wantUncertain {
+				t.Errorf("metricCheckFailed = %v, want %v", status.metricCheckFailed, tt.wantUncertain)
+			}
+		})
+	}
+}
+
+func TestCheckLoadBalancerIdleStatusUnsupportedType(t *testing.T) {
+	scanner := &ELBScanner{ELBV2Client: targetGroups(), CWClient: &mocks.CloudWatch{}}
+
+	_, err := scanner.checkLoadBalancerIdleStatus(context.Background(), "arn", elbv2types.LoadBalancerTypeEnum("classic"))
+	if err == nil {
+		t.Fatal("checkLoadBalancerIdleStatus() error = nil, want an unsupported type error")
+	}
+}
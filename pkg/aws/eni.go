@@ -0,0 +1,116 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// ENIClient struct for network interface client
+type ENIClient struct {
+	client     ec2.DescribeNetworkInterfacesAPIClient
+	region     string
+	tagFilters map[string]string
+}
+
+// NewENIClient creates a new ENIClient
+func NewENIClient(cfg aws.Config) *ENIClient {
+	return &ENIClient{
+		client: ec2.NewFromConfig(cfg),
+		region: cfg.Region,
+	}
+}
+
+// SetTagFilters limits results to network interfaces carrying all of the given tags
+func (c *ENIClient) SetTagFilters(tags map[string]string) {
+	c.tagFilters = tags
+}
+
+// GetOrphanedENIs returns the network interfaces in the "available" status, which are not
+// attached to any instance or service. They are free, but clutter subnets and block VPC deletion.
+func (c *ENIClient) GetOrphanedENIs(ctx context.Context) ([]models.ENIInfo, error) {
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: append([]types.Filter{
+			{
+				Name:   aws.String("status"),
+				Values: []string{string(types.NetworkInterfaceStatusAvailable)},
+			},
+		}, ec2TagFilters(c.tagFilters)...),
+	}
+
+	enis := []models.ENIInfo{}
+
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return enis, fmt.Errorf("error querying network interfaces: %w", err)
+		}
+
+		for _, eni := range page.NetworkInterfaces {
+			var securityGroups []string
+			for _, group := range eni.Groups {
+				securityGroups = append(securityGroups, aws.ToString(group.GroupId))
+			}
+
+			enis = append(enis, models.ENIInfo{
+				NetworkInterfaceID: aws.ToString(eni.NetworkInterfaceId),
+				ARN:                ec2ResourceARN(c.region, aws.ToString(eni.OwnerId), "network-interface/"+aws.ToString(eni.NetworkInterfaceId)),
+				Description:        aws.ToString(eni.Description),
+				InterfaceType:      string(eni.InterfaceType),
+				Creator:            inferENICreator(eni),
+				RequesterID:        aws.ToString(eni.RequesterId),
+				RequesterManaged:   aws.ToBool(eni.RequesterManaged),
+				SubnetID:           aws.ToString(eni.SubnetId),
+				VpcID:              aws.ToString(eni.VpcId),
+				AvailabilityZone:   aws.ToString(eni.AvailabilityZone),
+				PrivateIP:          aws.ToString(eni.PrivateIpAddress),
+				SecurityGroups:     securityGroups,
+				Region:             c.region,
+				Tags:               utils.GetTagsMap(eni.TagSet),
+			})
+		}
+	}
+
+	return enis, nil
+}
+
+// inferENICreator guesses the service that created a network interface from its type, its
+// description, which AWS services fill with a recognizable prefix, and its requester
+func inferENICreator(eni types.NetworkInterface) string {
+	description := aws.ToString(eni.Description)
+	requester := aws.ToString(eni.RequesterId)
+
+	switch {
+	case eni.InterfaceType == types.NetworkInterfaceTypeLambda || strings.HasPrefix(description, "AWS Lambda VPC ENI"):
+		return models.ENICreatorLambda
+	case eni.InterfaceType == types.NetworkInterfaceTypeLoadBalancer ||
+		eni.InterfaceType == types.NetworkInterfaceTypeNetworkLoadBalancer ||
+		eni.InterfaceType == types.NetworkInterfaceTypeGatewayLoadBalancer ||
+		strings.HasPrefix(description, "ELB "):
+		return models.ENICreatorELB
+	case description == "RDSNetworkInterface" || requester == "amazon-rds":
+		return models.ENICreatorRDS
+	case isServiceARN(description, "ecs"):
+		return models.ENICreatorECS
+	case eni.InterfaceType == types.NetworkInterfaceTypeVpcEndpoint || strings.HasPrefix(description, "VPC Endpoint Interface"):
+		return models.ENICreatorVPCEndpoint
+	case strings.HasPrefix(description, "EFS mount target"):
+		return models.ENICreatorEFS
+	default:
+		return models.ENICreatorOther
+	}
+}
+
+// isServiceARN reports whether s is the ARN of a resource of the given service, in any partition
+func isServiceARN(s, service string) bool {
+	parsed, err := arn.Parse(s)
+	return err == nil && parsed.Service == service
+}
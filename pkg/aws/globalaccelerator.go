@@ -0,0 +1,126 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/globalaccelerator"
+	gatypes "github.com/aws/aws-sdk-go-v2/service/globalaccelerator/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// GlobalAcceleratorHomeRegions are the only regions serving the Global Accelerator API, and
+// the regions its CloudWatch metrics are published in, per partition. Global Accelerator is
+// only offered in the aws partition.
+var GlobalAcceleratorHomeRegions = map[string]string{
+	utils.PartitionAWS: "us-west-2",
+}
+
+const (
+	globalAcceleratorCheckPeriodDays = 30
+	globalAcceleratorNamespace       = "AWS/GlobalAccelerator"
+	globalAcceleratorMetricBytesIn   = "ProcessedBytesIn"
+)
+
+// globalAcceleratorAPI is the Global Accelerator API used by GlobalAcceleratorScanner
+type globalAcceleratorAPI interface {
+	ListAccelerators(ctx context.Context, params *globalaccelerator.ListAcceleratorsInput, optFns ...func(*globalaccelerator.Options)) (*globalaccelerator.ListAcceleratorsOutput, error)
+}
+
+// GlobalAcceleratorScanner contains the AWS clients needed for scanning Global Accelerator accelerators
+type GlobalAcceleratorScanner struct {
+	GlobalAcceleratorClient globalAcceleratorAPI
+	CWClient                cloudWatchMetricsAPI // Must be configured for the home region of the partition
+}
+
+// NewGlobalAcceleratorScanner creates a new GlobalAcceleratorScanner. Global Accelerator is a
+// global service whose API is only served in us-west-2, so cfg should use the region of
+// GlobalAcceleratorHomeRegions.
+func NewGlobalAcceleratorScanner(cfg aws.Config) *GlobalAcceleratorScanner {
+	return &GlobalAcceleratorScanner{
+		GlobalAcceleratorClient: globalaccelerator.NewFromConfig(cfg),
+		CWClient:                cloudwatch.NewFromConfig(cfg),
+	}
+}
+
+// GetIdleAccelerators returns the standard accelerators that are disabled or received no traffic
+// over the last 30 days. The accelerators whose traffic metric could not be checked are left out.
+func (s *GlobalAcceleratorScanner) GetIdleAccelerators(ctx context.Context) ([]models.AcceleratorInfo, []error) {
+	var idle []models.AcceleratorInfo
+	var errs []error
+
+	paginator := globalaccelerator.NewListAcceleratorsPaginator(s.GlobalAcceleratorClient, &globalaccelerator.ListAcceleratorsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing Global Accelerator accelerators: %w", err))
+			break
+		}
+
+		for _, accelerator := range page.Accelerators {
+			info, err := s.checkAccelerator(ctx, accelerator)
+			if err != nil {
+				errs = append(errs, err)
+				recordSkip("globalaccelerator", "global", aws.ToString(accelerator.Name), "metrics unavailable", err)
+				continue
+			}
+			if info != nil {
+				idle = append(idle, *info)
+			}
+		}
+	}
+
+	return idle, errs
+}
+
+// checkAccelerator returns the accelerator info if the accelerator is disabled or received no
+// traffic over the check period, or nil otherwise
+func (s *GlobalAcceleratorScanner) checkAccelerator(ctx context.Context, accelerator gatypes.Accelerator) (*models.AcceleratorInfo, error) {
+	arn := aws.ToString(accelerator.AcceleratorArn)
+	info := &models.AcceleratorInfo{
+		AcceleratorID:        acceleratorID(arn),
+		ARN:                  arn,
+		Name:                 aws.ToString(accelerator.Name),
+		Enabled:              aws.ToBool(accelerator.Enabled),
+		Status:               string(accelerator.Status),
+		IPAddressType:        string(accelerator.IpAddressType),
+		DNSName:              aws.ToString(accelerator.DnsName),
+		CreatedTime:          aws.ToTime(accelerator.CreatedTime),
+		EstimatedMonthlyCost: pricing.GlobalAcceleratorHourlyPrice * utils.GetMonthlyHours(),
+	}
+
+	// A disabled accelerator receives no traffic, but its fixed fee is charged until it is deleted
+	if !info.Enabled {
+		info.IdleReason = "Disabled"
+		return info, nil
+	}
+
+	bytesIn, err := getMetricsSum(ctx, s.CWClient, globalAcceleratorNamespace, []string{globalAcceleratorMetricBytesIn}, globalAcceleratorCheckPeriodDays,
+		cloudwatchutil.Dimension("Accelerator", info.AcceleratorID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting the traffic of accelerator %s: %w", info.Name, err)
+	}
+	if bytesIn != 0 {
+		return nil, nil
+	}
+	info.ProcessedBytesIn = &bytesIn
+	info.IdleReason = fmt.Sprintf("No traffic (%dd)", globalAcceleratorCheckPeriodDays)
+	return info, nil
+}
+
+// acceleratorID returns the ID of an accelerator, the part of its ARN after "accelerator/",
+// which is the value of the Accelerator metric dimension
+func acceleratorID(arn string) string {
+	_, id, found := strings.Cut(arn, ":accelerator/")
+	if !found {
+		return arn
+	}
+	return id
+}
@@ -2,39 +2,63 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
+	"log/slog"
 
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
-	"github.com/briandowns/spinner"
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/progress"
 	"github.com/younsl/idled/pkg/utils"
 )
 
+// DefaultIAMKeyMaxAge is the default age in days after which an active access key is stale
+const DefaultIAMKeyMaxAge = 90
+
+// iamAPI is the IAM API used by IAMClient
+type iamAPI interface {
+	GenerateServiceLastAccessedDetails(ctx context.Context, params *iam.GenerateServiceLastAccessedDetailsInput, optFns ...func(*iam.Options)) (*iam.GenerateServiceLastAccessedDetailsOutput, error)
+	GetAccessKeyLastUsed(ctx context.Context, params *iam.GetAccessKeyLastUsedInput, optFns ...func(*iam.Options)) (*iam.GetAccessKeyLastUsedOutput, error)
+	GetLoginProfile(ctx context.Context, params *iam.GetLoginProfileInput, optFns ...func(*iam.Options)) (*iam.GetLoginProfileOutput, error)
+	GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	ListAccessKeys(ctx context.Context, params *iam.ListAccessKeysInput, optFns ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error)
+	ListAttachedRolePolicies(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error)
+	ListAttachedUserPolicies(ctx context.Context, params *iam.ListAttachedUserPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error)
+	ListMFADevices(ctx context.Context, params *iam.ListMFADevicesInput, optFns ...func(*iam.Options)) (*iam.ListMFADevicesOutput, error)
+	ListPolicies(ctx context.Context, params *iam.ListPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListPoliciesOutput, error)
+	ListPolicyVersions(ctx context.Context, params *iam.ListPolicyVersionsInput, optFns ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error)
+	ListRolePolicies(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error)
+	ListRoles(ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error)
+	ListUserPolicies(ctx context.Context, params *iam.ListUserPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error)
+	ListUsers(ctx context.Context, params *iam.ListUsersInput, optFns ...func(*iam.Options)) (*iam.ListUsersOutput, error)
+}
+
 // IAMClient struct for IAM client
 type IAMClient struct {
-	client        *iam.Client
+	client        iamAPI
 	region        string
-	idleThreshold int // in days
+	idleThreshold int           // in days
+	keyMaxAge     int           // Days after which an active access key is stale
+	progress      progress.Func // Receives the listing and analysis progress, nil for none
 }
 
 // NewIAMClient creates a new IAMClient
-func NewIAMClient(region string) (*IAMClient, error) {
-	// IAM is a global service but we maintain region for consistency with other clients
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("error loading AWS config: %w", err)
-	}
-
+func NewIAMClient(cfg aws.Config) *IAMClient {
 	client := iam.NewFromConfig(cfg)
 
 	return &IAMClient{
 		client:        client,
-		region:        region,
+		region:        cfg.Region,
 		idleThreshold: 90, // Default: consider IAM resources idle after 90 days of inactivity
-	}, nil
+		keyMaxAge:     DefaultIAMKeyMaxAge,
+	}
+}
+
+// SetKeyMaxAge sets the age in days after which an active access key is stale
+func (c *IAMClient) SetKeyMaxAge(days int) {
+	c.keyMaxAge = days
 }
 
 // SetIdleThreshold sets the threshold in days for considering IAM resources as idle
@@ -42,27 +66,30 @@ func (c *IAMClient) SetIdleThreshold(days int) {
 	c.idleThreshold = days
 }
 
+// SetProgress sets the function receiving the listing and analysis progress
+func (c *IAMClient) SetProgress(f progress.Func) {
+	c.progress = f
+}
+
 // GetIdleUsers returns a list of IAM users with their usage metrics and idle status
-func (c *IAMClient) GetIdleUsers() ([]models.IAMUserInfo, error) {
-	// Create spinner for progress indication
-	sp := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	sp.Prefix = "Scanning IAM users "
-	sp.Suffix = " (this is a global service)"
-	sp.Start()
+func (c *IAMClient) GetIdleUsers(ctx context.Context) ([]models.IAMUserInfo, error) {
+	c.progress.Report(0, 0, "Listing IAM users")
 
 	// List all IAM users
 	var users []types.User
 	var marker *string
+	var errs []error // Listing and per-user errors, the scan keeps going
 
 	for {
 		input := &iam.ListUsersInput{
 			Marker: marker,
 		}
 
-		result, err := c.client.ListUsers(context.TODO(), input)
+		result, err := c.client.ListUsers(ctx, input)
 		if err != nil {
-			sp.Stop()
-			return nil, fmt.Errorf("error listing IAM users: %w", err)
+			// Analyze the users listed so far, the marker of the next page is lost
+			errs = append(errs, fmt.Errorf("error listing IAM users: %w", err))
+			break
 		}
 
 		users = append(users, result.Users...)
@@ -74,67 +101,58 @@ func (c *IAMClient) GetIdleUsers() ([]models.IAMUserInfo, error) {
 	}
 
 	totalUsers := len(users)
-	sp.FinalMSG = fmt.Sprintf("✓ Found %d IAM users\n", totalUsers)
-	sp.Stop()
+	c.progress.Report(0, 0, fmt.Sprintf("Found %d IAM users", totalUsers))
 
 	if totalUsers == 0 {
-		return []models.IAMUserInfo{}, nil
+		return []models.IAMUserInfo{}, errors.Join(errs...)
 	}
 
 	// Process each user
 	var userInfos []models.IAMUserInfo
 
-	// Create a new spinner for analyzing users
-	sp = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	sp.Prefix = "Analyzing IAM users activity and permissions "
-	sp.Start()
-
+	c.progress.Report(0, totalUsers, "Analyzing IAM users activity and permissions")
 	processedCount := 0
 	for _, user := range users {
+		if ctx.Err() != nil {
+			break
+		}
 		userName := *user.UserName
 
 		// Get user info
-		userInfo, err := c.analyzeUser(user)
+		userInfo, err := c.analyzeUser(ctx, user)
+		processedCount++
+		c.progress.Report(processedCount, totalUsers, "")
 		if err != nil {
-			fmt.Printf("Warning: Error analyzing user %s: %v\n", userName, err)
+			errs = append(errs, fmt.Errorf("error analyzing IAM user %s: %w", userName, err))
+			recordSkip("iam", "global", aws.ToString(user.Arn), "user analysis failed", err)
 			continue
 		}
 
 		userInfos = append(userInfos, userInfo)
-		processedCount++
-
-		// Update progress
-		percentage := (processedCount * 100) / totalUsers
-		sp.Suffix = fmt.Sprintf(" (%d/%d, %d%%)", processedCount, totalUsers, percentage)
 	}
 
-	sp.FinalMSG = fmt.Sprintf("✓ Completed analysis of %d IAM users\n", processedCount)
-	sp.Stop()
-
-	return userInfos, nil
+	return userInfos, errors.Join(append(errs, ctx.Err())...)
 }
 
 // GetIdleRoles returns a list of IAM roles with their usage metrics and idle status
-func (c *IAMClient) GetIdleRoles() ([]models.IAMRoleInfo, error) {
-	// Create spinner for progress indication
-	sp := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	sp.Prefix = "Scanning IAM roles "
-	sp.Suffix = " (this is a global service)"
-	sp.Start()
+func (c *IAMClient) GetIdleRoles(ctx context.Context) ([]models.IAMRoleInfo, error) {
+	c.progress.Report(0, 0, "Listing IAM roles")
 
 	// List all IAM roles
 	var roles []types.Role
 	var marker *string
+	var errs []error // Listing and per-role errors, the scan keeps going
 
 	for {
 		input := &iam.ListRolesInput{
 			Marker: marker,
 		}
 
-		result, err := c.client.ListRoles(context.TODO(), input)
+		result, err := c.client.ListRoles(ctx, input)
 		if err != nil {
-			sp.Stop()
-			return nil, fmt.Errorf("error listing IAM roles: %w", err)
+			// Analyze the roles listed so far, the marker of the next page is lost
+			errs = append(errs, fmt.Errorf("error listing IAM roles: %w", err))
+			break
 		}
 
 		roles = append(roles, result.Roles...)
@@ -146,57 +164,47 @@ func (c *IAMClient) GetIdleRoles() ([]models.IAMRoleInfo, error) {
 	}
 
 	totalRoles := len(roles)
-	sp.FinalMSG = fmt.Sprintf("✓ Found %d IAM roles\n", totalRoles)
-	sp.Stop()
+	c.progress.Report(0, 0, fmt.Sprintf("Found %d IAM roles", totalRoles))
 
 	if totalRoles == 0 {
-		return []models.IAMRoleInfo{}, nil
+		return []models.IAMRoleInfo{}, errors.Join(errs...)
 	}
 
 	// Process each role
 	var roleInfos []models.IAMRoleInfo
 
-	// Create a new spinner for analyzing roles
-	sp = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	sp.Prefix = "Analyzing IAM roles activity and permissions "
-	sp.Start()
-
+	c.progress.Report(0, totalRoles, "Analyzing IAM roles activity and permissions")
 	processedCount := 0
 	for _, role := range roles {
+		if ctx.Err() != nil {
+			break
+		}
 		roleName := *role.RoleName
 
 		// Get role info
-		roleInfo, err := c.analyzeRole(role)
+		roleInfo, err := c.analyzeRole(ctx, role)
+		processedCount++
+		c.progress.Report(processedCount, totalRoles, "")
 		if err != nil {
-			fmt.Printf("Warning: Error analyzing role %s: %v\n", roleName, err)
+			errs = append(errs, fmt.Errorf("error analyzing IAM role %s: %w", roleName, err))
+			recordSkip("iam", "global", aws.ToString(role.Arn), "role analysis failed", err)
 			continue
 		}
 
 		roleInfos = append(roleInfos, roleInfo)
-		processedCount++
-
-		// Update progress
-		percentage := (processedCount * 100) / totalRoles
-		sp.Suffix = fmt.Sprintf(" (%d/%d, %d%%)", processedCount, totalRoles, percentage)
 	}
 
-	sp.FinalMSG = fmt.Sprintf("✓ Completed analysis of %d IAM roles\n", processedCount)
-	sp.Stop()
-
-	return roleInfos, nil
+	return roleInfos, errors.Join(append(errs, ctx.Err())...)
 }
 
 // GetIdlePolicies returns a list of IAM policies with their usage metrics and idle status
-func (c *IAMClient) GetIdlePolicies() ([]models.IAMPolicyInfo, error) {
-	// Create spinner for progress indication
-	sp := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	sp.Prefix = "Scanning IAM policies "
-	sp.Suffix = " (this is a global service)"
-	sp.Start()
+func (c *IAMClient) GetIdlePolicies(ctx context.Context) ([]models.IAMPolicyInfo, error) {
+	c.progress.Report(0, 0, "Listing IAM policies")
 
 	// List all customer managed IAM policies
 	var policies []types.Policy
 	var marker *string
+	var errs []error // Listing and per-policy errors, the scan keeps going
 
 	for {
 		input := &iam.ListPoliciesInput{
@@ -205,10 +213,11 @@ func (c *IAMClient) GetIdlePolicies() ([]models.IAMPolicyInfo, error) {
 			OnlyAttached: false,                      // Include non-attached policies
 		}
 
-		result, err := c.client.ListPolicies(context.TODO(), input)
+		result, err := c.client.ListPolicies(ctx, input)
 		if err != nil {
-			sp.Stop()
-			return nil, fmt.Errorf("error listing IAM policies: %w", err)
+			// Analyze the policies listed so far, the marker of the next page is lost
+			errs = append(errs, fmt.Errorf("error listing IAM policies: %w", err))
+			break
 		}
 
 		policies = append(policies, result.Policies...)
@@ -220,49 +229,41 @@ func (c *IAMClient) GetIdlePolicies() ([]models.IAMPolicyInfo, error) {
 	}
 
 	totalPolicies := len(policies)
-	sp.FinalMSG = fmt.Sprintf("✓ Found %d customer managed IAM policies\n", totalPolicies)
-	sp.Stop()
+	c.progress.Report(0, 0, fmt.Sprintf("Found %d customer managed IAM policies", totalPolicies))
 
 	if totalPolicies == 0 {
-		return []models.IAMPolicyInfo{}, nil
+		return []models.IAMPolicyInfo{}, errors.Join(errs...)
 	}
 
 	// Process each policy
 	var policyInfos []models.IAMPolicyInfo
 
-	// Create a new spinner for analyzing policies
-	sp = spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	sp.Prefix = "Analyzing IAM policies usage and attachment "
-	sp.Start()
-
+	c.progress.Report(0, totalPolicies, "Analyzing IAM policies usage and attachment")
 	processedCount := 0
 	for _, policy := range policies {
+		if ctx.Err() != nil {
+			break
+		}
 		policyName := *policy.PolicyName
 
 		// Get policy info
-		policyInfo, err := c.analyzePolicy(policy)
+		policyInfo, err := c.analyzePolicy(ctx, policy)
+		processedCount++
+		c.progress.Report(processedCount, totalPolicies, "")
 		if err != nil {
-			fmt.Printf("Warning: Error analyzing policy %s: %v\n", policyName, err)
+			errs = append(errs, fmt.Errorf("error analyzing IAM policy %s: %w", policyName, err))
+			recordSkip("iam", "global", aws.ToString(policy.Arn), "policy analysis failed", err)
 			continue
 		}
 
 		policyInfos = append(policyInfos, policyInfo)
-		processedCount++
-
-		// Update progress
-		percentage := (processedCount * 100) / totalPolicies
-		sp.Suffix = fmt.Sprintf(" (%d/%d, %d%%)", processedCount, totalPolicies, percentage)
 	}
 
-	sp.FinalMSG = fmt.Sprintf("✓ Completed analysis of %d IAM policies\n", processedCount)
-	sp.Stop()
-
-	return policyInfos, nil
+	return policyInfos, errors.Join(append(errs, ctx.Err())...)
 }
 
 // analyzeUser gathers information about a single IAM user
-func (c *IAMClient) analyzeUser(user types.User) (models.IAMUserInfo, error) {
-	ctx := context.TODO()
+func (c *IAMClient) analyzeUser(ctx context.Context, user types.User) (models.IAMUserInfo, error) {
 	userName := *user.UserName
 
 	// Initialize with basic information
@@ -302,15 +303,36 @@ func (c *IAMClient) analyzeUser(user types.User) (models.IAMUserInfo, error) {
 
 		// Check for active access keys
 		for _, key := range accessKeys.AccessKeyMetadata {
+			keyInfo := models.IAMAccessKeyInfo{
+				KeyIDSuffix: accessKeyIDSuffix(aws.ToString(key.AccessKeyId)),
+				Status:      string(key.Status),
+				CreateDate:  key.CreateDate,
+			}
+			if key.CreateDate != nil {
+				keyInfo.AgeDays = utils.CalculateElapsedDays(*key.CreateDate)
+			}
+
+			// Get last used information for each access key
+			keyLastUsed, err := c.client.GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{
+				AccessKeyId: key.AccessKeyId,
+			})
+			if err != nil {
+				slog.Warn("Could not get access key last used date", "user", userName, "key", keyInfo.KeyIDSuffix, "error", err)
+			} else if keyLastUsed.AccessKeyLastUsed != nil {
+				keyInfo.LastUsed = keyLastUsed.AccessKeyLastUsed.LastUsedDate
+			}
+
 			if key.Status == types.StatusTypeActive {
 				userInfo.HasActiveAccessKeys = true
 
-				// Get last used information for each access key
-				keyLastUsed, err := c.client.GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{
-					AccessKeyId: key.AccessKeyId,
-				})
-				if err == nil && keyLastUsed.AccessKeyLastUsed.LastUsedDate != nil {
-					lastUsedDate := keyLastUsed.AccessKeyLastUsed.LastUsedDate
+				// The last used date is unknown if the lookup failed, so the key is only stale by age then
+				keyInfo.IsStale = keyInfo.AgeDays > c.keyMaxAge || (err == nil && keyInfo.LastUsed == nil)
+				if keyInfo.IsStale {
+					userInfo.HasStaleKeys = true
+				}
+
+				if keyInfo.LastUsed != nil {
+					lastUsedDate := keyInfo.LastUsed
 
 					// Update access keys last used time
 					if userInfo.AccessKeysLastUsed == nil || lastUsedDate.After(*userInfo.AccessKeysLastUsed) {
@@ -324,9 +346,23 @@ func (c *IAMClient) analyzeUser(user types.User) (models.IAMUserInfo, error) {
 					}
 				}
 			}
+
+			userInfo.AccessKeys = append(userInfo.AccessKeys, keyInfo)
 		}
 	}
 
+	// Check if user has a console password
+	_, err = c.client.GetLoginProfile(ctx, &iam.GetLoginProfileInput{
+		UserName: &userName,
+	})
+	var noSuchEntity *types.NoSuchEntityException
+	switch {
+	case err == nil:
+		userInfo.HasConsoleAccess = true
+	case !errors.As(err, &noSuchEntity):
+		slog.Warn("Could not get IAM user login profile", "user", userName, "error", err)
+	}
+
 	// Check if user has MFA enabled
 	mfaDevices, err := c.client.ListMFADevices(ctx, &iam.ListMFADevicesInput{
 		UserName: &userName,
@@ -378,8 +414,7 @@ func (c *IAMClient) analyzeUser(user types.User) (models.IAMUserInfo, error) {
 }
 
 // analyzeRole gathers information about a single IAM role
-func (c *IAMClient) analyzeRole(role types.Role) (models.IAMRoleInfo, error) {
-	ctx := context.TODO()
+func (c *IAMClient) analyzeRole(ctx context.Context, role types.Role) (models.IAMRoleInfo, error) {
 	roleName := *role.RoleName
 
 	// Initialize with basic information
@@ -471,8 +506,7 @@ func (c *IAMClient) analyzeRole(role types.Role) (models.IAMRoleInfo, error) {
 }
 
 // analyzePolicy gathers information about a single IAM policy
-func (c *IAMClient) analyzePolicy(policy types.Policy) (models.IAMPolicyInfo, error) {
-	ctx := context.TODO()
+func (c *IAMClient) analyzePolicy(ctx context.Context, policy types.Policy) (models.IAMPolicyInfo, error) {
 	policyName := *policy.PolicyName
 
 	// Initialize with basic information
@@ -540,3 +574,12 @@ func (c *IAMClient) analyzePolicy(policy types.Policy) (models.IAMPolicyInfo, er
 func contains(s string, substr string) bool {
 	return len(s) >= len(substr) && s[len(s)-len(substr):] == substr
 }
+
+// accessKeyIDSuffix returns the last 4 characters of an access key ID, enough to identify
+// the key without printing it in full
+func accessKeyIDSuffix(accessKeyID string) string {
+	if len(accessKeyID) <= 4 {
+		return accessKeyID
+	}
+	return accessKeyID[len(accessKeyID)-4:]
+}
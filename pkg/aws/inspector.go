@@ -0,0 +1,135 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/inspector2"
+	"github.com/aws/aws-sdk-go-v2/service/inspector2/types"
+	"github.com/younsl/idled/internal/models"
+)
+
+const (
+	// inspectorCoverageBatchSize is the maximum number of values accepted by a single
+	// CoverageStringFilter list in ListCoverage filter criteria
+	inspectorCoverageBatchSize = 10
+)
+
+// InspectorClient wraps the Amazon Inspector2 API calls
+type InspectorClient struct {
+	client inspector2.ListCoverageAPIClient
+	region string
+}
+
+// NewInspectorClient creates a new Inspector2 client
+func NewInspectorClient(cfg aws.Config) *InspectorClient {
+	return &InspectorClient{
+		client: inspector2.NewFromConfig(cfg),
+		region: cfg.Region,
+	}
+}
+
+// GetIdleRepositoryScanCoverage returns the idle repositories that are still enrolled
+// in Inspector2 enhanced scanning. Only repositories flagged idle are looked up.
+func (c *InspectorClient) GetIdleRepositoryScanCoverage(ctx context.Context, repos []models.RepositoryInfo) ([]models.ECRScanCoverageInfo, error) {
+	var idleRepos []models.RepositoryInfo
+	var names []string
+	for _, repo := range repos {
+		if repo.Idle {
+			idleRepos = append(idleRepos, repo)
+			names = append(names, repo.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	coverage := make(map[string]types.CoveredResource)
+	for _, batch := range batchStrings(names, inspectorCoverageBatchSize) {
+		if err := c.listRepositoryCoverage(ctx, batch, coverage); err != nil {
+			return nil, err
+		}
+	}
+
+	return joinRepositoryCoverage(idleRepos, coverage), nil
+}
+
+// listRepositoryCoverage queries ListCoverage for a batch of ECR repository names
+// and stores the covered resources keyed by repository name
+func (c *InspectorClient) listRepositoryCoverage(ctx context.Context, names []string, coverage map[string]types.CoveredResource) error {
+	nameFilters := make([]types.CoverageStringFilter, 0, len(names))
+	for _, name := range names {
+		nameFilters = append(nameFilters, types.CoverageStringFilter{
+			Comparison: types.CoverageStringComparisonEquals,
+			Value:      aws.String(name),
+		})
+	}
+
+	input := &inspector2.ListCoverageInput{
+		FilterCriteria: &types.CoverageFilterCriteria{
+			ResourceType: []types.CoverageStringFilter{
+				{
+					Comparison: types.CoverageStringComparisonEquals,
+					Value:      aws.String(string(types.CoverageResourceTypeAwsEcrRepository)),
+				},
+			},
+			EcrRepositoryName: nameFilters,
+		},
+	}
+
+	paginator := inspector2.NewListCoveragePaginator(c.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list Inspector2 coverage in region %s: %w", c.region, err)
+		}
+
+		for _, resource := range page.CoveredResources {
+			if resource.ResourceMetadata == nil || resource.ResourceMetadata.EcrRepository == nil {
+				continue
+			}
+			name := aws.ToString(resource.ResourceMetadata.EcrRepository.Name)
+			if name == "" {
+				continue
+			}
+			coverage[name] = resource
+		}
+	}
+
+	return nil
+}
+
+// joinRepositoryCoverage joins idle repositories with their Inspector2 coverage,
+// keeping only repositories actively enrolled in enhanced scanning
+func joinRepositoryCoverage(idleRepos []models.RepositoryInfo, coverage map[string]types.CoveredResource) []models.ECRScanCoverageInfo {
+	var enrolled []models.ECRScanCoverageInfo
+	for _, repo := range idleRepos {
+		resource, found := coverage[repo.Name]
+		if !found {
+			continue
+		}
+
+		statusCode := ""
+		if resource.ScanStatus != nil {
+			statusCode = string(resource.ScanStatus.StatusCode)
+		}
+		if statusCode != string(types.ScanStatusCodeActive) {
+			continue
+		}
+
+		enrolled = append(enrolled, models.ECRScanCoverageInfo{
+			RepositoryName: repo.Name,
+			Region:         repo.Region,
+			ScanFrequency:  string(resource.ResourceMetadata.EcrRepository.ScanFrequency),
+			ScanStatus:     statusCode,
+			LastScannedAt:  resource.LastScannedAt,
+			LastPush:       repo.LastPush,
+		})
+	}
+
+	models.SortByKey(enrolled)
+
+	return enrolled
+}
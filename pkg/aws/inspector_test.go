@@ -0,0 +1,145 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/inspector2"
+	inspectortypes "github.com/aws/aws-sdk-go-v2/service/inspector2/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+// coveredRepository returns the Inspector2 coverage of an ECR repository with a scan status
+func coveredRepository(name string, status inspectortypes.ScanStatusCode) inspectortypes.CoveredResource {
+	return inspectortypes.CoveredResource{
+		ResourceMetadata: &inspectortypes.ResourceScanMetadata{
+			EcrRepository: &inspectortypes.EcrRepositoryMetadata{
+				Name:          aws.String(name),
+				ScanFrequency: inspectortypes.EcrScanFrequencyContinuousScan,
+			},
+		},
+		ScanStatus: &inspectortypes.ScanStatus{StatusCode: status},
+	}
+}
+
+func TestGetIdleRepositoryScanCoverageBatches(t *testing.T) {
+	// 25 repositories, of which 23 are idle: more than two batches of names
+	var repos []models.RepositoryInfo
+	for i := 0; i < 25; i++ {
+		repos = append(repos, models.RepositoryInfo{
+			Name:   fmt.Sprintf("repo-%02d", i),
+			Region: "us-east-1",
+			Idle:   i != 3 && i != 17,
+		})
+	}
+
+	var batches [][]string
+	client := &mocks.Inspector2{
+		ListCoverageFunc: func(ctx context.Context, params *inspector2.ListCoverageInput) (*inspector2.ListCoverageOutput, error) {
+			var names []string
+			for _, filter := range params.FilterCriteria.EcrRepositoryName {
+				names = append(names, aws.ToString(filter.Value))
+			}
+			if params.NextToken == nil {
+				batches = append(batches, names)
+			}
+
+			// Each batch answers over two pages, and repositories divisible by 5 are not covered
+			half := len(names) / 2
+			page, nextToken := names[:half], aws.String("page-2")
+			if params.NextToken != nil {
+				page, nextToken = names[half:], nil
+			}
+			var resources []inspectortypes.CoveredResource
+			for _, name := range page {
+				var i int
+				fmt.Sscanf(name, "repo-%d", &i)
+				switch {
+				case i%5 == 0:
+				case i%7 == 0:
+					resources = append(resources, coveredRepository(name, inspectortypes.ScanStatusCodeInactive))
+				default:
+					resources = append(resources, coveredRepository(name, inspectortypes.ScanStatusCodeActive))
+				}
+			}
+			return &inspector2.ListCoverageOutput{CoveredResources: resources, NextToken: nextToken}, nil
+		},
+	}
+
+	inspector := &InspectorClient{client: client, region: "us-east-1"}
+	coverage, err := inspector.GetIdleRepositoryScanCoverage(context.Background(), repos)
+	if err != nil {
+		t.Fatalf("GetIdleRepositoryScanCoverage() error = %v", err)
+	}
+
+	if len(batches) != 3 || len(batches[0]) != inspectorCoverageBatchSize || len(batches[1]) != inspectorCoverageBatchSize || len(batches[2]) != 3 {
+		t.Fatalf("ListCoverage batches = %v, want batches of 10, 10 and 3 names", batches)
+	}
+	for _, batch := range batches {
+		for _, name := range batch {
+			if name == "repo-03" || name == "repo-17" {
+				t.Errorf("ListCoverage queried the active repository %s", name)
+			}
+		}
+	}
+
+	var got []string
+	for _, info := range coverage {
+		if info.ScanStatus != string(inspectortypes.ScanStatusCodeActive) || info.ScanFrequency != string(inspectortypes.EcrScanFrequencyContinuousScan) {
+			t.Errorf("%s: coverage = %+v", info.RepositoryName, info)
+		}
+		got = append(got, info.RepositoryName)
+	}
+	want := []string{
+		"repo-01", "repo-02", "repo-04", "repo-06", "repo-08", "repo-09", "repo-11", "repo-12",
+		"repo-13", "repo-16", "repo-18", "repo-19", "repo-22", "repo-23", "repo-24",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIdleRepositoryScanCoverage() = %v, want %v", got, want)
+	}
+}
+
+func TestGetIdleRepositoryScanCoverageError(t *testing.T) {
+	denied := errors.New("AccessDeniedException: not authorized")
+	calls := 0
+	client := &mocks.Inspector2{
+		ListCoverageFunc: func(ctx context.Context, params *inspector2.ListCoverageInput) (*inspector2.ListCoverageOutput, error) {
+			calls++
+			if calls == 2 {
+				return nil, denied
+			}
+			return &inspector2.ListCoverageOutput{}, nil
+		},
+	}
+	var repos []models.RepositoryInfo
+	for i := 0; i < 15; i++ {
+		repos = append(repos, models.RepositoryInfo{Name: fmt.Sprintf("repo-%02d", i), Region: "us-east-1", Idle: true})
+	}
+
+	inspector := &InspectorClient{client: client, region: "us-east-1"}
+	coverage, err := inspector.GetIdleRepositoryScanCoverage(context.Background(), repos)
+	if !errors.Is(err, denied) || coverage != nil {
+		t.Errorf("GetIdleRepositoryScanCoverage() = %v, %v, want the error of the second batch", coverage, err)
+	}
+}
+
+func TestGetIdleRepositoryScanCoverageNoIdle(t *testing.T) {
+	client := &mocks.Inspector2{
+		ListCoverageFunc: func(ctx context.Context, params *inspector2.ListCoverageInput) (*inspector2.ListCoverageOutput, error) {
+			t.Error("ListCoverage called without idle repositories")
+			return &inspector2.ListCoverageOutput{}, nil
+		},
+	}
+
+	inspector := &InspectorClient{client: client, region: "us-east-1"}
+	coverage, err := inspector.GetIdleRepositoryScanCoverage(context.Background(), []models.RepositoryInfo{{Name: "active"}})
+	if coverage != nil || err != nil {
+		t.Errorf("GetIdleRepositoryScanCoverage() = %v, %v, want nothing", coverage, err)
+	}
+}
@@ -4,44 +4,67 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
+	"log/slog"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	lambdaTypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
-	"github.com/briandowns/spinner"
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/progress"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
 	"github.com/younsl/idled/pkg/utils"
 )
 
+const (
+	// lambdaInvocationLookbackDays is how far back the last invocation is searched. Invocation
+	// counts, errors, and durations still cover the last 30 days.
+	lambdaInvocationLookbackDays = 90
+)
+
+// DefaultLambdaFailingErrorRate is the percentage of errored invocations over the last 30 days
+// from which a function is reported as failing
+const DefaultLambdaFailingErrorRate = 95.0
+
+// lambdaAPI is the Lambda API used by LambdaClient
+type lambdaAPI interface {
+	lambda.ListProvisionedConcurrencyConfigsAPIClient
+	GetFunctionConcurrency(ctx context.Context, params *lambda.GetFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionConcurrencyOutput, error)
+	GetPolicy(ctx context.Context, params *lambda.GetPolicyInput, optFns ...func(*lambda.Options)) (*lambda.GetPolicyOutput, error)
+	ListEventSourceMappings(ctx context.Context, params *lambda.ListEventSourceMappingsInput, optFns ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error)
+	ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error)
+	ListTags(ctx context.Context, params *lambda.ListTagsInput, optFns ...func(*lambda.Options)) (*lambda.ListTagsOutput, error)
+}
+
 // LambdaClient struct for Lambda client
 type LambdaClient struct {
-	client        *lambda.Client
-	cwClient      *cloudwatch.Client
+	client        lambdaAPI
+	cwClient      cloudWatchMetricsAPI
 	region        string
-	idleThreshold int // in days
+	idleThreshold int     // in days
+	failingRate   float64 // Error rate (%) from which an invoked function is failing
+	tagFilters    map[string]string
+	nameFilter    *utils.NameFilter
+	progress      progress.Func // Receives the analyzed function count, nil for none
+	pricing       *pricing.PricingService
 }
 
 // NewLambdaClient creates a new LambdaClient
-func NewLambdaClient(region string) (*LambdaClient, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
-	if err != nil {
-		return nil, fmt.Errorf("error loading AWS config: %w", err)
-	}
-
+func NewLambdaClient(cfg aws.Config) *LambdaClient {
 	client := lambda.NewFromConfig(cfg)
 	cwClient := cloudwatch.NewFromConfig(cfg)
 
 	return &LambdaClient{
 		client:        client,
 		cwClient:      cwClient,
-		region:        region,
+		region:        cfg.Region,
 		idleThreshold: 30, // Default: consider functions idle after 30 days of inactivity
-	}, nil
+		failingRate:   DefaultLambdaFailingErrorRate,
+		pricing:       pricing.Default(),
+	}
 }
 
 // SetIdleThreshold sets the threshold in days for considering a function as idle
@@ -49,24 +72,77 @@ func (c *LambdaClient) SetIdleThreshold(days int) {
 	c.idleThreshold = days
 }
 
+// SetFailingErrorRate sets the error rate (%) over the last 30 days from which an invoked
+// function is reported as failing
+func (c *LambdaClient) SetFailingErrorRate(percent float64) {
+	c.failingRate = percent
+}
+
+// SetTagFilters limits results to functions carrying all of the given tags
+func (c *LambdaClient) SetTagFilters(tags map[string]string) {
+	c.tagFilters = tags
+}
+
+// SetNameFilter limits results to functions whose names pass the --include and --exclude patterns
+func (c *LambdaClient) SetNameFilter(filter *utils.NameFilter) {
+	c.nameFilter = filter
+}
+
+// SetProgress sets the function receiving the analysis progress
+func (c *LambdaClient) SetProgress(f progress.Func) {
+	c.progress = f
+}
+
+// SetPricingService sets the pricing service used to estimate function costs
+func (c *LambdaClient) SetPricingService(service *pricing.PricingService) {
+	c.pricing = service
+}
+
 // GetIdleFunctions returns a list of Lambda functions with their usage metrics
-func (c *LambdaClient) GetIdleFunctions() ([]models.LambdaFunctionInfo, error) {
+func (c *LambdaClient) GetIdleFunctions(ctx context.Context) ([]models.LambdaFunctionInfo, error) {
 	// Get all Lambda functions in the region
 	var functions []lambdaTypes.FunctionConfiguration
+	functionTags := make(map[string]map[string]string) // Function ARN -> tags
 	var nextMarker *string
 	var functionInfos []models.LambdaFunctionInfo
+	var errs []error // Listing and per-function errors, the scan keeps going
 
 	for {
 		input := &lambda.ListFunctionsInput{
 			Marker: nextMarker,
 		}
 
-		result, err := c.client.ListFunctions(context.TODO(), input)
+		result, err := c.client.ListFunctions(ctx, input)
 		if err != nil {
-			return nil, fmt.Errorf("error listing Lambda functions: %w", err)
+			// Analyze the functions listed so far, the marker of the next page is lost
+			errs = append(errs, fmt.Errorf("error listing Lambda functions: %w", err))
+			break
 		}
 
-		functions = append(functions, result.Functions...)
+		for _, function := range result.Functions {
+			// Skip excluded functions before listing their tags
+			if c.nameFilter.Excludes(aws.ToString(function.FunctionArn), aws.ToString(function.FunctionName)) {
+				continue
+			}
+
+			tagsOutput, err := c.client.ListTags(ctx, &lambda.ListTagsInput{
+				Resource: function.FunctionArn,
+			})
+			if err != nil {
+				// Tags are only mandatory when filtering by them
+				if len(c.tagFilters) > 0 {
+					errs = append(errs, fmt.Errorf("error listing tags for Lambda function %s: %w", aws.ToString(function.FunctionName), err))
+					recordSkip("lambda", c.region, aws.ToString(function.FunctionName), "tags unavailable for --tag", err)
+					continue
+				}
+			} else {
+				functionTags[aws.ToString(function.FunctionArn)] = tagsOutput.Tags
+			}
+			if !matchesTagFilters(functionTags[aws.ToString(function.FunctionArn)], c.tagFilters) {
+				continue
+			}
+			functions = append(functions, function)
+		}
 
 		if result.NextMarker == nil || *result.NextMarker == "" {
 			break
@@ -76,64 +152,37 @@ func (c *LambdaClient) GetIdleFunctions() ([]models.LambdaFunctionInfo, error) {
 
 	totalFunctions := len(functions)
 	if totalFunctions == 0 {
-		return functionInfos, nil
+		return functionInfos, errors.Join(errs...)
 	}
 
-	// Create a silent spinner just for local progress tracking
-	sp := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	sp.Suffix = fmt.Sprintf(" Progress: 0/%d functions", totalFunctions)
-
-	// Don't display this spinner to avoid conflict with the main spinner
-	// Instead, we'll use it to track progress silently
-	// sp.Start()
-	// defer sp.Stop()
-
-	// Process each function, tracking progress
 	processedCount := 0
-	lastPercentage := 0
-	currentFunctionName := ""
+	c.progress.Report(0, totalFunctions, "")
 
 	for _, function := range functions {
-		// Update current function name (for progress tracking)
-		if function.FunctionName != nil {
-			currentFunctionName = *function.FunctionName
-			sp.Lock()
-			sp.Suffix = fmt.Sprintf(" [%d/%d] Analyzing: %s",
-				processedCount+1, totalFunctions, currentFunctionName)
-			sp.Unlock()
+		if ctx.Err() != nil {
+			return functionInfos, errors.Join(append(errs, ctx.Err())...)
 		}
 
 		// Get function metrics
-		functionInfo, err := c.analyzeFunction(function)
+		functionInfo, err := c.analyzeFunction(ctx, function)
+		processedCount++
+		c.progress.Report(processedCount, totalFunctions, "Analyzed Lambda function "+aws.ToString(function.FunctionName))
 		if err != nil {
-			// Log error and continue with next function
+			errs = append(errs, fmt.Errorf("error analyzing Lambda function %s: %w", aws.ToString(function.FunctionName), err))
+			recordSkip("lambda", c.region, aws.ToString(function.FunctionName), "metrics unavailable", err)
 			continue
 		}
 
+		functionInfo.Tags = functionTags[aws.ToString(function.FunctionArn)]
 		functionInfos = append(functionInfos, functionInfo)
-
-		// Update progress
-		processedCount++
-		currentPercentage := (processedCount * 100) / totalFunctions
-
-		// Update progress info every 10% increment
-		if currentPercentage >= lastPercentage+10 || processedCount == totalFunctions {
-			sp.Lock()
-			sp.Suffix = fmt.Sprintf(" %d/%d functions completed (%d%%) - Last: %s",
-				processedCount, totalFunctions, currentPercentage, currentFunctionName)
-			sp.Unlock()
-			lastPercentage = currentPercentage
-		}
 	}
 
-	// No final message needed as we're using the main spinner's message
-	// sp.FinalMSG = fmt.Sprintf("✓ Completed analysis of %d Lambda functions\n", totalFunctions)
-
-	return functionInfos, nil
+	return functionInfos, errors.Join(errs...)
 }
 
-// analyzeFunction gathers information and metrics for a single Lambda function
-func (c *LambdaClient) analyzeFunction(function lambdaTypes.FunctionConfiguration) (models.LambdaFunctionInfo, error) {
+// analyzeFunction gathers information and metrics for a single Lambda function. It fails if the
+// invocation metrics cannot be read, since the function would otherwise look never invoked.
+func (c *LambdaClient) analyzeFunction(ctx context.Context, function lambdaTypes.FunctionConfiguration) (models.LambdaFunctionInfo, error) {
 	functionName := *function.FunctionName
 
 	// Initialize with basic information
@@ -141,6 +190,12 @@ func (c *LambdaClient) analyzeFunction(function lambdaTypes.FunctionConfiguratio
 		FunctionName: functionName,
 		Region:       c.region,
 		Runtime:      string(function.Runtime),
+		Architecture: pricing.LambdaArchitectureX86,
+	}
+
+	// Functions run on a single architecture, x86_64 unless configured otherwise
+	if len(function.Architectures) > 0 {
+		functionInfo.Architecture = string(function.Architectures[0])
 	}
 
 	// Handle pointer values
@@ -166,19 +221,23 @@ func (c *LambdaClient) analyzeFunction(function lambdaTypes.FunctionConfiguratio
 	}
 
 	// Get CloudWatch metrics for invocations
-	invocations, errorCount, lastInvocation, duration, err := c.getFunctionMetrics(functionName)
+	invocations, errorCount, lastInvocation, duration, err := c.getFunctionMetrics(ctx, functionName)
 	if err != nil {
-		// Just continue with what we have - this is non-critical
-	} else {
-		functionInfo.InvocationsLast30Days = invocations
-		functionInfo.ErrorsLast30Days = errorCount
-		functionInfo.LastInvocation = lastInvocation
-		functionInfo.DurationP95Last30Days = duration
-
-		// Calculate idle days if we have last invocation data
-		if lastInvocation != nil {
-			functionInfo.IdleDays = utils.CalculateElapsedDays(*lastInvocation)
-		}
+		return functionInfo, fmt.Errorf("error getting CloudWatch metrics: %w", err)
+	}
+	functionInfo.InvocationsLast30Days = invocations
+	functionInfo.ErrorsLast30Days = errorCount
+	functionInfo.LastInvocation = lastInvocation
+	functionInfo.DurationP95Last30Days = duration
+
+	// Calculate idle days if we have last invocation data
+	if lastInvocation != nil {
+		functionInfo.IdleDays = utils.CalculateElapsedDays(*lastInvocation)
+	}
+	// Without an invocation in the lookback window, the function has been idle at least since its
+	// last modification, up to the lookback
+	if functionInfo.LastInvocation == nil && functionInfo.LastModified != nil {
+		functionInfo.IdleDays = min(utils.CalculateElapsedDays(*functionInfo.LastModified), lambdaInvocationLookbackDays)
 	}
 
 	// Check for triggers
@@ -186,7 +245,7 @@ func (c *LambdaClient) analyzeFunction(function lambdaTypes.FunctionConfiguratio
 	listMappingsInput := &lambda.ListEventSourceMappingsInput{
 		FunctionName: aws.String(functionName),
 	}
-	mappingsResult, err := c.client.ListEventSourceMappings(context.TODO(), listMappingsInput)
+	mappingsResult, err := c.client.ListEventSourceMappings(ctx, listMappingsInput)
 	if err == nil && len(mappingsResult.EventSourceMappings) > 0 {
 		hasEventSourceMapping = true
 	}
@@ -195,7 +254,7 @@ func (c *LambdaClient) analyzeFunction(function lambdaTypes.FunctionConfiguratio
 	getPolicyInput := &lambda.GetPolicyInput{
 		FunctionName: aws.String(functionName),
 	}
-	_, err = c.client.GetPolicy(context.TODO(), getPolicyInput)
+	_, err = c.client.GetPolicy(ctx, getPolicyInput)
 	if err == nil {
 		hasPolicy = true
 	} else {
@@ -209,137 +268,131 @@ func (c *LambdaClient) analyzeFunction(function lambdaTypes.FunctionConfiguratio
 
 	functionInfo.HasTrigger = hasEventSourceMapping || hasPolicy
 
-	// Calculate estimated monthly cost
-	functionInfo.EstimatedMonthlyCost = calculateLambdaCost(functionInfo)
+	// Reserved and provisioned concurrency are read independently, and a failure only loses its
+	// own value. Without the provisioned concurrency the cost leaves out its monthly charge.
+	functionInfo.ReservedConcurrency, err = c.getReservedConcurrency(ctx, functionName)
+	if err != nil {
+		slog.Warn("Could not get Lambda reserved concurrency", "function", functionName, "region", c.region, "error", err)
+	}
+	functionInfo.ProvisionedConcurrency, err = c.getProvisionedConcurrency(ctx, functionName)
+	if err != nil {
+		slog.Warn("Could not get Lambda provisioned concurrency, its cost is left out", "function", functionName, "region", c.region, "error", err)
+	}
 
-	// Determine if the function is idle
-	functionInfo.IsIdle = c.determineFunctionIdleStatus(&functionInfo)
+	// Calculate estimated monthly cost
+	prices, pricingSource := c.pricing.GetLambdaPricing(c.region, functionInfo.Architecture)
+	functionInfo.EstimatedMonthlyCost = calculateLambdaCost(functionInfo, prices)
+	functionInfo.PricingSource = pricingSource
+
+	// Determine if the function is idle or failing
+	functionInfo.Status = c.classifyFunction(functionInfo)
+	if functionInfo.Status != models.LambdaStatusIdle && functionInfo.LastInvocation == nil {
+		functionInfo.IdleDays = 0
+	}
 
 	return functionInfo, nil
 }
 
 // getFunctionMetrics retrieves CloudWatch metrics for a Lambda function
-func (c *LambdaClient) getFunctionMetrics(functionName string) (int64, int64, *time.Time, float64, error) {
-	ctx := context.TODO()
+func (c *LambdaClient) getFunctionMetrics(ctx context.Context, functionName string) (int64, int64, *time.Time, float64, error) {
 	endTime := time.Now()
 	startTime := endTime.AddDate(0, 0, -30) // Last 30 days
-
-	// Get invocation metrics
-	invocationsInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/Lambda"),
-		MetricName: aws.String("Invocations"),
-		Dimensions: []cwTypes.Dimension{
-			{
-				Name:  aws.String("FunctionName"),
-				Value: aws.String(functionName),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(86400), // 1 day
-		Statistics: []cwTypes.Statistic{cwTypes.StatisticSum},
-	}
-
-	invocationsResult, err := c.cwClient.GetMetricStatistics(ctx, invocationsInput)
+	dimensions := []cwTypes.Dimension{cloudwatchutil.Dimension("FunctionName", functionName)}
+
+	// Get invocation metrics over the longer lookback to find the last invocation
+	invocations, err := cloudwatchutil.Datapoints(ctx, c.cwClient, cloudwatchutil.MetricQuery{
+		Namespace:  "AWS/Lambda",
+		Name:       "Invocations",
+		Dimensions: dimensions,
+		Stat:       cwTypes.StatisticSum,
+		WindowDays: lambdaInvocationLookbackDays,
+		End:        endTime,
+		Period:     24 * time.Hour,
+	})
 	if err != nil {
 		return 0, 0, nil, 0, err
 	}
 
 	// Get error metrics
-	errorsInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/Lambda"),
-		MetricName: aws.String("Errors"),
-		Dimensions: []cwTypes.Dimension{
-			{
-				Name:  aws.String("FunctionName"),
-				Value: aws.String(functionName),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(86400), // 1 day
-		Statistics: []cwTypes.Statistic{cwTypes.StatisticSum},
-	}
-
-	errorsResult, err := c.cwClient.GetMetricStatistics(ctx, errorsInput)
+	errorCount, err := cloudwatchutil.SumOverWindow(ctx, c.cwClient, cloudwatchutil.MetricQuery{
+		Namespace:  "AWS/Lambda",
+		Name:       "Errors",
+		Dimensions: dimensions,
+		Stat:       cwTypes.StatisticSum,
+		WindowDays: 30,
+		End:        endTime,
+		Period:     24 * time.Hour,
+	})
 	if err != nil {
 		return 0, 0, nil, 0, err
 	}
 
-	// Get duration metrics (average)
-	durationInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/Lambda"),
-		MetricName: aws.String("Duration"),
-		Dimensions: []cwTypes.Dimension{
-			{
-				Name:  aws.String("FunctionName"),
-				Value: aws.String(functionName),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(2592000), // 30 days
-		Statistics: []cwTypes.Statistic{cwTypes.StatisticAverage},
-	}
-
-	durationResult, err := c.cwClient.GetMetricStatistics(ctx, durationInput)
+	// Get duration metrics (average), a single datapoint for the 30 days
+	durations, err := cloudwatchutil.Datapoints(ctx, c.cwClient, cloudwatchutil.MetricQuery{
+		Namespace:  "AWS/Lambda",
+		Name:       "Duration",
+		Dimensions: dimensions,
+		Stat:       cwTypes.StatisticAverage,
+		WindowDays: 30,
+		End:        endTime,
+	})
 	if err != nil {
 		return 0, 0, nil, 0, err
 	}
 
-	// Sum up invocations
-	var totalInvocations, totalErrors int64
-	var lastInvocationTime *time.Time
-	var avgDuration float64
-
-	// Process invocations, tracking the most recent non-zero invocation
-	if len(invocationsResult.Datapoints) > 0 {
-		// Sort by timestamp (descending)
-		sort.Slice(invocationsResult.Datapoints, func(i, j int) bool {
-			return invocationsResult.Datapoints[i].Timestamp.After(*invocationsResult.Datapoints[j].Timestamp)
-		})
-
-		for _, datapoint := range invocationsResult.Datapoints {
-			if datapoint.Sum != nil {
-				sum := int64(*datapoint.Sum)
-				totalInvocations += sum
-
-				// If we have invocations and haven't set last invocation time yet
-				if sum > 0 && lastInvocationTime == nil {
-					lastInvocationTime = datapoint.Timestamp
-				}
-			}
+	// Only the last 30 days count towards the invocation total
+	var totalInvocations int64
+	for _, datapoint := range invocations {
+		if datapoint.Sum != nil && !datapoint.Timestamp.Before(startTime) {
+			totalInvocations += int64(*datapoint.Sum)
 		}
 	}
+	lastInvocationTime := cloudwatchutil.LastNonZeroTimestamp(invocations, cwTypes.StatisticSum)
 
-	// Sum up errors
-	for _, datapoint := range errorsResult.Datapoints {
-		if datapoint.Sum != nil {
-			totalErrors += int64(*datapoint.Sum)
-		}
-	}
+	// Use the most recent duration datapoint
+	avgDuration := aws.ToFloat64(cloudwatchutil.Latest(durations, cwTypes.StatisticAverage))
 
-	// Get average duration
-	if len(durationResult.Datapoints) > 0 {
-		// Sort by timestamp (descending) to get most recent
-		sort.Slice(durationResult.Datapoints, func(i, j int) bool {
-			return durationResult.Datapoints[i].Timestamp.After(*durationResult.Datapoints[j].Timestamp)
-		})
+	return totalInvocations, int64(errorCount), lastInvocationTime, avgDuration, nil
+}
 
-		if durationResult.Datapoints[0].Average != nil {
-			avgDuration = *durationResult.Datapoints[0].Average
-		}
+// getReservedConcurrency returns the reserved concurrency of a function, nil if it has none
+func (c *LambdaClient) getReservedConcurrency(ctx context.Context, functionName string) (*int32, error) {
+	concurrency, err := c.client.GetFunctionConcurrency(ctx, &lambda.GetFunctionConcurrencyInput{
+		FunctionName: aws.String(functionName),
+	})
+	if err != nil {
+		return nil, err
 	}
+	return concurrency.ReservedConcurrentExecutions, nil
+}
 
-	return totalInvocations, totalErrors, lastInvocationTime, avgDuration, nil
+// getProvisionedConcurrency returns the provisioned concurrency of a function summed over all
+// aliases and versions
+func (c *LambdaClient) getProvisionedConcurrency(ctx context.Context, functionName string) (int32, error) {
+	var provisioned int32
+	paginator := lambda.NewListProvisionedConcurrencyConfigsPaginator(c.client, &lambda.ListProvisionedConcurrencyConfigsInput{
+		FunctionName: aws.String(functionName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, config := range page.ProvisionedConcurrencyConfigs {
+			// Provisioned concurrency is billed as requested, even while it is still being allocated
+			provisioned += aws.ToInt32(config.RequestedProvisionedConcurrentExecutions)
+		}
+	}
+	return provisioned, nil
 }
 
-// calculateLambdaCost estimates the monthly cost of a Lambda function
-func calculateLambdaCost(functionInfo models.LambdaFunctionInfo) float64 {
+// calculateLambdaCost estimates the monthly cost of a Lambda function from the prices of its
+// region and architecture
+func calculateLambdaCost(functionInfo models.LambdaFunctionInfo, prices pricing.LambdaPrices) float64 {
 	// Lambda pricing (simplified model):
 	// - Free tier: 1M requests free and 400,000 GB-seconds of compute time per month
-	// - $0.20 per 1M requests
-	// - $0.0000166667 per GB-second
+	// - A price per request and per GB-second of compute, the latter at its first tier
+	// - A price per GB-second of provisioned concurrency, for every second it is configured
 
 	// Estimate monthly invocations based on 30-day history
 	monthlyInvocations := functionInfo.InvocationsLast30Days
@@ -354,30 +407,37 @@ func calculateLambdaCost(functionInfo models.LambdaFunctionInfo) float64 {
 	gbSeconds := float64(monthlyInvocations) * avgDurationSec * float64(functionInfo.MemorySize) / 1024
 
 	// Calculate cost (ignoring free tier for simplicity)
-	requestsCost := float64(monthlyInvocations) * 0.20 / 1000000
-	computeCost := gbSeconds * 0.0000166667
+	requestsCost := float64(monthlyInvocations) * prices.Request
+	computeCost := gbSeconds * prices.DurationPerGBSecond
+
+	// Provisioned concurrency is billed for the whole month regardless of invocations
+	provisionedGBSeconds := float64(functionInfo.ProvisionedConcurrency) * float64(functionInfo.MemorySize) / 1024 * utils.GetMonthlyHours() * 3600
+	provisionedCost := provisionedGBSeconds * prices.ProvisionedPerGBSecond
 
 	// Total monthly cost
-	return requestsCost + computeCost
+	return requestsCost + computeCost + provisionedCost
 }
 
-// determineFunctionIdleStatus determines if a function is idle based on metrics
-func (c *LambdaClient) determineFunctionIdleStatus(functionInfo *models.LambdaFunctionInfo) bool {
-	// If no invocations in the last 30 days, it's definitely idle
-	if functionInfo.InvocationsLast30Days == 0 {
-		return true
+// classifyFunction returns the status of a function from its metrics. Invoked functions are
+// failing when their error rate over the last 30 days reaches the failing threshold. Functions
+// never invoked within the lookback are idle once their last deployment is older than the idle
+// threshold, so newly deployed functions are not reported before their first trigger.
+func (c *LambdaClient) classifyFunction(functionInfo models.LambdaFunctionInfo) string {
+	if rate, ok := functionInfo.ErrorRate(); ok && rate >= c.failingRate {
+		return models.LambdaStatusFailing
 	}
 
-	// If we have last invocation data, check against threshold
-	if functionInfo.LastInvocation != nil {
-		daysSinceInvocation := utils.CalculateElapsedDays(*functionInfo.LastInvocation)
-
-		// If last invocation is older than threshold, consider it idle
-		if daysSinceInvocation > c.idleThreshold {
-			return true
+	if functionInfo.InvocationsLast30Days == 0 && functionInfo.LastInvocation == nil {
+		if functionInfo.LastModified != nil && utils.CalculateElapsedDays(*functionInfo.LastModified) <= c.idleThreshold {
+			return models.LambdaStatusNew
 		}
+		return models.LambdaStatusIdle
+	}
+
+	// If last invocation is older than threshold, consider it idle
+	if functionInfo.LastInvocation != nil && utils.CalculateElapsedDays(*functionInfo.LastInvocation) > c.idleThreshold {
+		return models.LambdaStatusIdle
 	}
 
-	// Not idle by our criteria
-	return false
+	return models.LambdaStatusActive
 }
@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdaTypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+func TestClassifyFunction(t *testing.T) {
+	daysAgo := func(days int) *time.Time {
+		at := time.Now().AddDate(0, 0, -days)
+		return &at
+	}
+
+	tests := []struct {
+		name     string
+		function models.LambdaFunctionInfo
+		want     string
+	}{
+		{
+			name:     "recently invoked",
+			function: models.LambdaFunctionInfo{InvocationsLast30Days: 100, ErrorsLast30Days: 1, LastInvocation: daysAgo(1), LastModified: daysAgo(200)},
+			want:     models.LambdaStatusActive,
+		},
+		{
+			name:     "error rate at the failing threshold",
+			function: models.LambdaFunctionInfo{InvocationsLast30Days: 100, ErrorsLast30Days: 95, LastInvocation: daysAgo(1)},
+			want:     models.LambdaStatusFailing,
+		},
+		{
+			name:     "error rate below the failing threshold",
+			function: models.LambdaFunctionInfo{InvocationsLast30Days: 100, ErrorsLast30Days: 94, LastInvocation: daysAgo(1)},
+			want:     models.LambdaStatusActive,
+		},
+		{
+			name:     "never invoked and deployed long ago",
+			function: models.LambdaFunctionInfo{LastModified: daysAgo(90)},
+			want:     models.LambdaStatusIdle,
+		},
+		{
+			name:     "never invoked and deployed recently",
+			function: models.LambdaFunctionInfo{LastModified: daysAgo(3)},
+			want:     models.LambdaStatusNew,
+		},
+		{
+			name:     "never invoked without a deployment date",
+			function: models.LambdaFunctionInfo{},
+			want:     models.LambdaStatusIdle,
+		},
+		{
+			name:     "last invoked before the idle threshold",
+			function: models.LambdaFunctionInfo{LastInvocation: daysAgo(45), LastModified: daysAgo(300)},
+			want:     models.LambdaStatusIdle,
+		},
+		{
+			name:     "last invoked within the idle threshold without invocations in 30 days",
+			function: models.LambdaFunctionInfo{LastInvocation: daysAgo(20)},
+			want:     models.LambdaStatusActive,
+		},
+	}
+
+	client := &LambdaClient{idleThreshold: 30, failingRate: DefaultLambdaFailingErrorRate}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := client.classifyFunction(tt.function); got != tt.want {
+				t.Errorf("classifyFunction() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFunctionConcurrency(t *testing.T) {
+	reserved := func(ctx context.Context, params *lambda.GetFunctionConcurrencyInput) (*lambda.GetFunctionConcurrencyOutput, error) {
+		return &lambda.GetFunctionConcurrencyOutput{ReservedConcurrentExecutions: aws.Int32(10)}, nil
+	}
+	provisioned := func(ctx context.Context, params *lambda.ListProvisionedConcurrencyConfigsInput) (*lambda.ListProvisionedConcurrencyConfigsOutput, error) {
+		return &lambda.ListProvisionedConcurrencyConfigsOutput{
+			ProvisionedConcurrencyConfigs: []lambdaTypes.ProvisionedConcurrencyConfigListItem{
+				{RequestedProvisionedConcurrentExecutions: aws.Int32(2)},
+				{RequestedProvisionedConcurrentExecutions: aws.Int32(3)},
+			},
+		}, nil
+	}
+	denied := errors.New("access denied")
+
+	tests := []struct {
+		name            string
+		lambda          *mocks.Lambda
+		wantReserved    *int32
+		wantProvisioned int32
+	}{
+		{
+			name:            "both readable",
+			lambda:          &mocks.Lambda{GetFunctionConcurrencyFunc: reserved, ListProvisionedConcurrencyConfigsFunc: provisioned},
+			wantReserved:    aws.Int32(10),
+			wantProvisioned: 5,
+		},
+		{
+			name: "reserved concurrency unreadable",
+			lambda: &mocks.Lambda{
+				GetFunctionConcurrencyFunc: func(ctx context.Context, params *lambda.GetFunctionConcurrencyInput) (*lambda.GetFunctionConcurrencyOutput, error) {
+					return nil, denied
+				},
+				ListProvisionedConcurrencyConfigsFunc: provisioned,
+			},
+			wantProvisioned: 5,
+		},
+		{
+			name: "provisioned concurrency unreadable",
+			lambda: &mocks.Lambda{
+				GetFunctionConcurrencyFunc: reserved,
+				ListProvisionedConcurrencyConfigsFunc: func(ctx context.Context, params *lambda.ListProvisionedConcurrencyConfigsInput) (*lambda.ListProvisionedConcurrencyConfigsOutput, error) {
+					return nil, denied
+				},
+			},
+			wantReserved: aws.Int32(10),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &LambdaClient{
+				client:        tt.lambda,
+				cwClient:      &mocks.CloudWatch{},
+				region:        "us-east-1",
+				idleThreshold: 30,
+				failingRate:   DefaultLambdaFailingErrorRate,
+				pricing:       newTestPricing(),
+			}
+
+			info, err := client.analyzeFunction(context.Background(), lambdaTypes.FunctionConfiguration{
+				FunctionName: aws.String("worker"),
+				MemorySize:   aws.Int32(1024),
+			})
+			if err != nil {
+				t.Fatalf("analyzeFunction() error = %v", err)
+			}
+			if (info.ReservedConcurrency == nil) != (tt.wantReserved == nil) || aws.ToInt32(info.ReservedConcurrency) != aws.ToInt32(tt.wantReserved) {
+				t.Errorf("ReservedConcurrency = %v, want %v", info.ReservedConcurrency, tt.wantReserved)
+			}
+			if info.ProvisionedConcurrency != tt.wantProvisioned {
+				t.Errorf("ProvisionedConcurrency = %d, want %d", info.ProvisionedConcurrency, tt.wantProvisioned)
+			}
+			if tt.wantProvisioned > 0 && info.EstimatedMonthlyCost == 0 {
+				t.Error("EstimatedMonthlyCost leaves out the provisioned concurrency")
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+//go:build integration
+
+package aws
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Run with `make test-integration`, which starts LocalStack and sets LOCALSTACK_ENDPOINT
+
+const (
+	integrationRegion = "us-east-1"
+	integrationTagKey = "idled-integration"
+)
+
+// newLocalStackConfig returns a config whose clients call LocalStack, skipping the test
+// when LOCALSTACK_ENDPOINT is not set
+func newLocalStackConfig(t *testing.T) aws.Config {
+	t.Helper()
+	endpoint := os.Getenv("LOCALSTACK_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("LOCALSTACK_ENDPOINT is not set")
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	SetEndpoint(endpoint, false)
+	t.Cleanup(func() { SetEndpoint("", false) })
+
+	cfg, err := LoadConfig(context.Background(), integrationRegion)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return cfg
+}
+
+// integrationTags tags the seeded resources with the name of the test, so that the scanners
+// only report what the test created
+func integrationTags(t *testing.T, resource types.ResourceType) []types.TagSpecification {
+	return []types.TagSpecification{{
+		ResourceType: resource,
+		Tags:         []types.Tag{{Key: aws.String(integrationTagKey), Value: aws.String(t.Name())}},
+	}}
+}
+
+// seedStoppedInstance launches an instance and stops it
+func seedStoppedInstance(t *testing.T, client *ec2.Client) string {
+	t.Helper()
+	ctx := context.Background()
+
+	images, err := client.DescribeImages(ctx, &ec2.DescribeImagesInput{})
+	if err != nil || len(images.Images) == 0 {
+		t.Fatalf("no image to launch an instance from: %v", err)
+	}
+	run, err := client.RunInstances(ctx, &ec2.RunInstancesInput{
+		ImageId:           images.Images[0].ImageId,
+		InstanceType:      types.InstanceTypeT3Micro,
+		MinCount:          aws.Int32(1),
+		MaxCount:          aws.Int32(1),
+		TagSpecifications: integrationTags(t, types.ResourceTypeInstance),
+	})
+	if err != nil {
+		t.Fatalf("RunInstances() error = %v", err)
+	}
+	instanceID := aws.ToString(run.Instances[0].InstanceId)
+	t.Cleanup(func() {
+		client.TerminateInstances(context.Background(), &ec2.TerminateInstancesInput{InstanceIds: []string{instanceID}})
+	})
+
+	if _, err := client.StopInstances(ctx, &ec2.StopInstancesInput{InstanceIds: []string{instanceID}}); err != nil {
+		t.Fatalf("StopInstances() error = %v", err)
+	}
+	waiter := ec2.NewInstanceStoppedWaiter(client, func(o *ec2.InstanceStoppedWaiterOptions) {
+		o.MinDelay, o.MaxDelay = time.Second, time.Second
+	})
+	if err := waiter.Wait(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}}, time.Minute); err != nil {
+		t.Fatalf("instance %s did not stop: %v", instanceID, err)
+	}
+	return instanceID
+}
+
+// seedAvailableVolume creates a volume that is not attached to any instance
+func seedAvailableVolume(t *testing.T, client *ec2.Client) string {
+	t.Helper()
+	volume, err := client.CreateVolume(context.Background(), &ec2.CreateVolumeInput{
+		AvailabilityZone:  aws.String(integrationRegion + "a"),
+		Size:              aws.Int32(8),
+		VolumeType:        types.VolumeTypeGp3,
+		TagSpecifications: integrationTags(t, types.ResourceTypeVolume),
+	})
+	if err != nil {
+		t.Fatalf("CreateVolume() error = %v", err)
+	}
+	volumeID := aws.ToString(volume.VolumeId)
+	t.Cleanup(func() {
+		client.DeleteVolume(context.Background(), &ec2.DeleteVolumeInput{VolumeId: aws.String(volumeID)})
+	})
+	return volumeID
+}
+
+func TestIntegrationGetStoppedInstances(t *testing.T) {
+	cfg := newLocalStackConfig(t)
+	instanceID := seedStoppedInstance(t, ec2.NewFromConfig(cfg))
+
+	client := NewEC2Client(cfg)
+	client.SetTagFilters(map[string]string{integrationTagKey: t.Name()})
+	client.SetPricingService(newTestPricing())
+	instances, err := client.GetStoppedInstances(context.Background())
+	if err != nil {
+		t.Fatalf("GetStoppedInstances() error = %v", err)
+	}
+
+	if len(instances) != 1 || instances[0].InstanceID != instanceID {
+		t.Fatalf("GetStoppedInstances() = %v, want only %s", instances, instanceID)
+	}
+	if got := instances[0]; got.Region != integrationRegion || got.InstanceType != string(types.InstanceTypeT3Micro) {
+		t.Errorf("instance = %s %s, want %s %s", got.Region, got.InstanceType, integrationRegion, types.InstanceTypeT3Micro)
+	}
+}
+
+func TestIntegrationGetAvailableVolumes(t *testing.T) {
+	cfg := newLocalStackConfig(t)
+	volumeID := seedAvailableVolume(t, ec2.NewFromConfig(cfg))
+
+	client := NewEBSClient(cfg)
+	client.SetTagFilters(map[string]string{integrationTagKey: t.Name()})
+	client.SetPricingService(newTestPricing())
+	volumes, err := client.GetAvailableVolumes(context.Background())
+	if err != nil {
+		t.Fatalf("GetAvailableVolumes() error = %v", err)
+	}
+
+	var ids []string
+	for _, volume := range volumes {
+		ids = append(ids, volume.VolumeID)
+	}
+	if !slices.Equal(ids, []string{volumeID}) {
+		t.Fatalf("GetAvailableVolumes() = %v, want only %s", ids, volumeID)
+	}
+	if got := volumes[0]; got.Size != 8 || got.VolumeType != string(types.VolumeTypeGp3) {
+		t.Errorf("volume = %d GiB %s, want 8 GiB gp3", got.Size, got.VolumeType)
+	}
+}
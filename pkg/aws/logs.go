@@ -4,21 +4,138 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
-	"github.com/briandowns/spinner"
-	"github.com/dustin/go-humanize"
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/progress"
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
 )
 
-func getActualLastEventTimestamp(ctx context.Context, client *cloudwatchlogs.Client, logGroupName string) (int64, error) {
+const (
+	// defaultLogsIdleDays is the default number of days without events before a log group is idle
+	defaultLogsIdleDays = 90
+
+	// logsScanConcurrency is the number of log groups checked in parallel
+	logsScanConcurrency = 8
+
+	// logsMaxRetryAttempts allows extra retries since parallel checks are often throttled
+	logsMaxRetryAttempts = 10
+
+	// logsRetentionRecommendationBytes is the stored size above which a log group
+	// without a retention policy is recommended one
+	logsRetentionRecommendationBytes = 1024 * 1024 * 1024 // 1 GiB
+)
+
+// LogsAPI is the subset of the CloudWatch Logs API used by LogsScanner
+type LogsAPI interface {
+	DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	DescribeLogStreams(ctx context.Context, params *cloudwatchlogs.DescribeLogStreamsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error)
+	FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error)
+	DescribeSubscriptionFilters(ctx context.Context, params *cloudwatchlogs.DescribeSubscriptionFiltersInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeSubscriptionFiltersOutput, error)
+	DescribeMetricFilters(ctx context.Context, params *cloudwatchlogs.DescribeMetricFiltersInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeMetricFiltersOutput, error)
+}
+
+// LogsScanner contains the AWS client needed for scanning CloudWatch Log Groups
+type LogsScanner struct {
+	Client        LogsAPI
+	Region        string
+	IdleThreshold int           // in days
+	Progress      progress.Func // Receives the checked log group count, nil for none
+	NameFilter    *utils.NameFilter
+	Pricing       *pricing.PricingService
+}
+
+// NewLogsScanner creates a new LogsScanner for a given region
+func NewLogsScanner(cfg aws.Config) *LogsScanner {
+	return &LogsScanner{
+		Client: cloudwatchlogs.NewFromConfig(cfg, func(o *cloudwatchlogs.Options) {
+			// Throttling errors are retried with exponential backoff
+			o.Retryer = retry.AddWithMaxAttempts(o.Retryer, minMaxAttempts(logsMaxRetryAttempts))
+		}),
+		Region:        cfg.Region,
+		IdleThreshold: defaultLogsIdleDays,
+		Pricing:       pricing.Default(),
+	}
+}
+
+// SetIdleThreshold sets the number of days without events before a log group is considered idle
+func (s *LogsScanner) SetIdleThreshold(days int) {
+	s.IdleThreshold = days
+}
+
+// SetProgress sets the function receiving the log group check progress
+func (s *LogsScanner) SetProgress(f progress.Func) {
+	s.Progress = f
+}
+
+// SetNameFilter limits results to log groups whose names pass the --include and --exclude patterns
+func (s *LogsScanner) SetNameFilter(filter *utils.NameFilter) {
+	s.NameFilter = filter
+}
+
+// SetPricingService sets the pricing service used to estimate log storage costs
+func (s *LogsScanner) SetPricingService(service *pricing.PricingService) {
+	s.Pricing = service
+}
+
+// getLastEventTimestamp returns the timestamp of the most recent event in a log group, or 0
+// if it has none. It reads the newest log stream's LastEventTimestamp, which is cheap but
+// updated on an eventual consistency basis. When that metadata looks stale, an event after
+// the idle threshold is searched for instead, so an active group is never reported idle.
+func getLastEventTimestamp(ctx context.Context, client LogsAPI, logGroupName string, idleThresholdTime int64) (int64, error) {
+	streamsInput := &cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName: aws.String(logGroupName),
+		OrderBy:      types.OrderByLastEventTime,
+		Descending:   aws.Bool(true),
+		Limit:        aws.Int32(1),
+	}
+
+	resp, err := client.DescribeLogStreams(ctx, streamsInput)
+	if err != nil {
+		var resourceNotFound *types.ResourceNotFoundException
+		if errors.As(err, &resourceNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("DescribeLogStreams failed for %s: %w", logGroupName, err)
+	}
+
+	// A group without streams has never received events
+	if len(resp.LogStreams) == 0 {
+		return 0, nil
+	}
+
+	stream := resp.LogStreams[0]
+	lastEventTimestamp := aws.ToInt64(stream.LastEventTimestamp)
+	if lastEventTimestamp >= idleThresholdTime || !isLogStreamMetadataStale(stream, idleThresholdTime) {
+		return lastEventTimestamp, nil
+	}
+
+	return getLastEventTimestampSince(ctx, client, logGroupName, idleThresholdTime, lastEventTimestamp)
+}
+
+// isLogStreamMetadataStale reports whether a stream's LastEventTimestamp may lag behind
+// its events: it is missing, or the stream ingested data after the idle threshold
+func isLogStreamMetadataStale(stream types.LogStream, idleThresholdTime int64) bool {
+	if stream.LastEventTimestamp == nil {
+		return true
+	}
+	return aws.ToInt64(stream.LastIngestionTime) >= idleThresholdTime
+}
+
+// getLastEventTimestampSince searches for an event after startTime with FilterLogEvents,
+// bounded to the idle window, and returns its timestamp or fallback if none is found
+func getLastEventTimestampSince(ctx context.Context, client LogsAPI, logGroupName string, startTime, fallback int64) (int64, error) {
 	filterInput := &cloudwatchlogs.FilterLogEventsInput{
 		LogGroupName: aws.String(logGroupName),
 		Limit:        aws.Int32(1),
-		StartTime:    aws.Int64(0),
+		StartTime:    aws.Int64(startTime),
 		EndTime:      aws.Int64(time.Now().UnixMilli()),
 	}
 
@@ -26,85 +143,111 @@ func getActualLastEventTimestamp(ctx context.Context, client *cloudwatchlogs.Cli
 	if err != nil {
 		var resourceNotFound *types.ResourceNotFoundException
 		if errors.As(err, &resourceNotFound) {
-			return 0, nil
+			return fallback, nil
 		}
-		return 0, fmt.Errorf("FilterLogEvents failed for %s: %w", logGroupName, err)
+		return fallback, fmt.Errorf("FilterLogEvents failed for %s: %w", logGroupName, err)
 	}
 
 	if len(resp.Events) > 0 && resp.Events[0].Timestamp != nil {
 		return *resp.Events[0].Timestamp, nil
 	}
 
-	return 0, nil
+	return fallback, nil
 }
 
-func ScanLogGroups(cfg aws.Config, idleThresholdDays int) ([]models.LogGroupInfo, []error) {
-	s := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
-	s.Suffix = " Scanning CloudWatch Log Groups ..."
-	s.Start()
+// getLogGroupFilters reports whether a log group has subscription filters (e.g., streaming
+// to a SIEM) or metric filters (e.g., powering alarms). It is only called for idle log
+// groups, since each check costs two API calls.
+func getLogGroupFilters(ctx context.Context, client LogsAPI, logGroupName string) (hasSubscription, hasMetric bool, err error) {
+	subscriptions, err := client.DescribeSubscriptionFilters(ctx, &cloudwatchlogs.DescribeSubscriptionFiltersInput{
+		LogGroupName: aws.String(logGroupName),
+		Limit:        aws.Int32(1),
+	})
+	if err != nil {
+		var resourceNotFound *types.ResourceNotFoundException
+		if errors.As(err, &resourceNotFound) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("DescribeSubscriptionFilters failed for %s: %w", logGroupName, err)
+	}
+
+	metrics, err := client.DescribeMetricFilters(ctx, &cloudwatchlogs.DescribeMetricFiltersInput{
+		LogGroupName: aws.String(logGroupName),
+		Limit:        aws.Int32(1),
+	})
+	if err != nil {
+		var resourceNotFound *types.ResourceNotFoundException
+		if errors.As(err, &resourceNotFound) {
+			return false, false, nil
+		}
+		return len(subscriptions.SubscriptionFilters) > 0, false, fmt.Errorf("DescribeMetricFilters failed for %s: %w", logGroupName, err)
+	}
+
+	return len(subscriptions.SubscriptionFilters) > 0, len(metrics.MetricFilters) > 0, nil
+}
 
-	client := cloudwatchlogs.NewFromConfig(cfg)
+// GetIdleLogGroups scans all log groups in the region and returns those whose last
+// event (or creation time, if they have no events) is older than the idle threshold. If the
+// listing fails part way, the log groups of the pages already read are checked and returned
+// with the error.
+func (s *LogsScanner) GetIdleLogGroups(ctx context.Context) ([]models.LogGroupInfo, []error) {
 	var preliminaryGroups []types.LogGroup
 	var fetchErrors []error
-	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(client, &cloudwatchlogs.DescribeLogGroupsInput{})
+	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(s.Client, &cloudwatchlogs.DescribeLogGroupsInput{})
 
 	pageCount := 0
 	for paginator.HasMorePages() {
 		pageCount++
-		output, err := paginator.NextPage(context.TODO())
+		output, err := paginator.NextPage(ctx)
 		if err != nil {
-			fetchErr := fmt.Errorf("error fetching log groups page %d: %w", pageCount, err)
-			fetchErrors = append(fetchErrors, fetchErr)
-			continue
+			// The paginator does not advance on an error, so retrying the page would never end
+			fetchErrors = append(fetchErrors, fmt.Errorf("error fetching log groups page %d: %w", pageCount, err))
+			break
+		}
+		for _, lg := range output.LogGroups {
+			// Skip excluded log groups before checking their streams
+			if s.NameFilter.Excludes(s.Region+"/"+aws.ToString(lg.LogGroupName), aws.ToString(lg.LogGroupName)) {
+				continue
+			}
+			preliminaryGroups = append(preliminaryGroups, lg)
 		}
-		preliminaryGroups = append(preliminaryGroups, output.LogGroups...)
 	}
 
-	var finalLogGroups []models.LogGroupInfo
-	var checkErrors []error
-	idleThresholdTime := time.Now().AddDate(0, 0, -idleThresholdDays).UnixMilli()
+	idleThresholdTime := time.Now().AddDate(0, 0, -s.IdleThreshold).UnixMilli()
 
-	for _, lg := range preliminaryGroups {
-		retention := "Never expire"
-		if lg.RetentionInDays != nil {
-			retention = fmt.Sprintf("%d days", *lg.RetentionInDays)
-		}
+	// Check log groups concurrently, keeping results in the DescribeLogGroups order
+	results := make([]*models.LogGroupInfo, len(preliminaryGroups))
+	checkErrs := make([]error, len(preliminaryGroups))
+	indexes := make(chan int)
 
-		creationTimestamp := int64(0)
-		if lg.CreationTime != nil {
-			creationTimestamp = *lg.CreationTime
-		}
+	var checked atomic.Int64
+	s.Progress.Report(0, len(preliminaryGroups), "Checking log groups in "+s.Region)
 
-		actualLastEventTimestamp, err := getActualLastEventTimestamp(context.TODO(), client, aws.ToString(lg.LogGroupName))
-		if err != nil {
-			checkErrors = append(checkErrors, fmt.Errorf("failed check for %s: %w", aws.ToString(lg.LogGroupName), err))
-		}
+	var wg sync.WaitGroup
+	for worker := 0; worker < logsScanConcurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], checkErrs[i] = s.checkLogGroup(ctx, preliminaryGroups[i], idleThresholdTime)
+				s.Progress.Report(int(checked.Add(1)), len(preliminaryGroups), "")
+			}
+		}()
+	}
+	for i := range preliminaryGroups {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
 
-		var effectiveTimestamp int64
-		var displayTimeStr string
-
-		if actualLastEventTimestamp > 0 {
-			effectiveTimestamp = actualLastEventTimestamp
-			displayTimeStr = time.UnixMilli(effectiveTimestamp).Format("2006-01-02 15:04:05")
-		} else if creationTimestamp > 0 {
-			effectiveTimestamp = creationTimestamp
-			displayTimeStr = fmt.Sprintf("N/A (Created: %s)", time.UnixMilli(creationTimestamp).Format("2006-01-02 15:04:05"))
-		} else {
-			effectiveTimestamp = 0
-			displayTimeStr = "N/A"
+	var finalLogGroups []models.LogGroupInfo
+	var checkErrors []error
+	for i, info := range results {
+		if checkErrs[i] != nil {
+			checkErrors = append(checkErrors, checkErrs[i])
 		}
-
-		if effectiveTimestamp > 0 && effectiveTimestamp < idleThresholdTime {
-			info := models.LogGroupInfo{
-				Name:            aws.ToString(lg.LogGroupName),
-				RetentionDays:   retention,
-				StoredBytes:     humanize.Bytes(uint64(aws.ToInt64(lg.StoredBytes))),
-				LastEventTime:   displayTimeStr,
-				ARN:             aws.ToString(lg.Arn),
-				CreationTime:    time.UnixMilli(creationTimestamp),
-				LastEventMillis: effectiveTimestamp,
-			}
-			finalLogGroups = append(finalLogGroups, info)
+		if info != nil {
+			finalLogGroups = append(finalLogGroups, *info)
 		}
 	}
 
@@ -112,3 +255,76 @@ func ScanLogGroups(cfg aws.Config, idleThresholdDays int) ([]models.LogGroupInfo
 
 	return finalLogGroups, allErrors
 }
+
+// checkLogGroup returns the log group info if the group is idle, or nil otherwise. A failed
+// last event lookup is returned as an error alongside the creation time based result, and a
+// failed filter lookup alongside the result with FilterCheckFailed set.
+func (s *LogsScanner) checkLogGroup(ctx context.Context, lg types.LogGroup, idleThresholdTime int64) (*models.LogGroupInfo, error) {
+	retention := "Never expire"
+	if lg.RetentionInDays != nil {
+		retention = fmt.Sprintf("%d days", *lg.RetentionInDays)
+	}
+
+	creationTimestamp := int64(0)
+	if lg.CreationTime != nil {
+		creationTimestamp = *lg.CreationTime
+	}
+
+	var checkErr error
+	actualLastEventTimestamp, err := getLastEventTimestamp(ctx, s.Client, aws.ToString(lg.LogGroupName), idleThresholdTime)
+	if err != nil {
+		checkErr = fmt.Errorf("failed check for %s: %w", aws.ToString(lg.LogGroupName), err)
+	}
+
+	var effectiveTimestamp int64
+	var displayTimeStr string
+
+	if actualLastEventTimestamp > 0 {
+		effectiveTimestamp = actualLastEventTimestamp
+		displayTimeStr = time.UnixMilli(effectiveTimestamp).Format("2006-01-02 15:04:05")
+	} else if creationTimestamp > 0 {
+		effectiveTimestamp = creationTimestamp
+		displayTimeStr = fmt.Sprintf("N/A (Created: %s)", time.UnixMilli(creationTimestamp).Format("2006-01-02 15:04:05"))
+	} else {
+		effectiveTimestamp = 0
+		displayTimeStr = "N/A"
+	}
+
+	if effectiveTimestamp <= 0 || effectiveTimestamp >= idleThresholdTime {
+		return nil, checkErr
+	}
+
+	// Idle groups may still be referenced by subscription or metric filters
+	hasSubscription, hasMetric, err := getLogGroupFilters(ctx, s.Client, aws.ToString(lg.LogGroupName))
+	if err != nil {
+		checkErr = errors.Join(checkErr, fmt.Errorf("failed filter check for %s: %w", aws.ToString(lg.LogGroupName), err))
+	}
+
+	storedBytes := aws.ToInt64(lg.StoredBytes)
+	monthlyCost, pricingSource := s.Pricing.CalculateLogsStorageMonthlyCostWithSource(storedBytes, s.Region)
+
+	return &models.LogGroupInfo{
+		Name:                  aws.ToString(lg.LogGroupName),
+		Region:                s.Region,
+		RetentionDays:         retention,
+		StoredBytes:           storedBytes,
+		LastEventTime:         displayTimeStr,
+		ARN:                   aws.ToString(lg.Arn),
+		CreationTime:          time.UnixMilli(creationTimestamp),
+		LastEventMillis:       effectiveTimestamp,
+		EstimatedMonthlyCost:  monthlyCost,
+		PricingSource:         pricingSource,
+		Recommendation:        logGroupRecommendation(lg.RetentionInDays, storedBytes),
+		HasSubscriptionFilter: hasSubscription,
+		HasMetricFilter:       hasMetric,
+		FilterCheckFailed:     err != nil,
+	}, checkErr
+}
+
+// logGroupRecommendation suggests a retention policy for large log groups that never expire
+func logGroupRecommendation(retentionInDays *int32, storedBytes int64) string {
+	if retentionInDays == nil && storedBytes >= logsRetentionRecommendationBytes {
+		return "Set a retention policy"
+	}
+	return ""
+}
@@ -0,0 +1,247 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/aws/mocks"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+// newTestLogsScanner returns a scanner of us-east-1 with the default idle threshold
+func newTestLogsScanner(client *mocks.Logs) *LogsScanner {
+	return &LogsScanner{Client: client, Region: "us-east-1", IdleThreshold: defaultLogsIdleDays, Pricing: newTestPricing()}
+}
+
+// scanLogGroups runs GetIdleLogGroups, failing the test if it does not return within a second
+func scanLogGroups(t *testing.T, ctx context.Context, scanner *LogsScanner) ([]models.LogGroupInfo, []error) {
+	t.Helper()
+	type result struct {
+		groups []models.LogGroupInfo
+		errs   []error
+	}
+	done := make(chan result, 1)
+	go func() {
+		groups, errs := scanner.GetIdleLogGroups(ctx)
+		done <- result{groups, errs}
+	}()
+	select {
+	case r := <-done:
+		return r.groups, r.errs
+	case <-time.After(time.Second):
+		t.Fatal("GetIdleLogGroups() did not return")
+		return nil, nil
+	}
+}
+
+func TestGetIdleLogGroupsStopsOnPageError(t *testing.T) {
+	created := time.Now().AddDate(0, 0, -200).UnixMilli()
+	denied := errors.New("AccessDeniedException: not authorized")
+	calls := 0
+	client := &mocks.Logs{
+		DescribeLogGroupsFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+			calls++
+			if params.NextToken == nil {
+				return &cloudwatchlogs.DescribeLogGroupsOutput{
+					LogGroups: []logstypes.LogGroup{{LogGroupName: aws.String("/app/old"), CreationTime: aws.Int64(created)}},
+					NextToken: aws.String("page-2"),
+				}, nil
+			}
+			return nil, denied
+		},
+	}
+
+	groups, errs := scanLogGroups(t, context.Background(), newTestLogsScanner(client))
+
+	if calls != 2 {
+		t.Errorf("DescribeLogGroups called %d times, want 2", calls)
+	}
+	if len(groups) != 1 || groups[0].Name != "/app/old" {
+		t.Errorf("GetIdleLogGroups() = %v, want the group of the first page", groups)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], denied) {
+		t.Errorf("GetIdleLogGroups() errors = %v, want the page error", errs)
+	}
+}
+
+func TestGetIdleLogGroupsStopsWhenCancelled(t *testing.T) {
+	client := &mocks.Logs{
+		DescribeLogGroupsFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+			return nil, ctx.Err()
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	groups, errs := scanLogGroups(t, ctx, newTestLogsScanner(client))
+
+	if len(groups) != 0 || len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("GetIdleLogGroups() = %v, %v, want no groups and the cancellation", groups, errs)
+	}
+}
+
+func TestGetIdleLogGroupsClassifiesByLastEvent(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(0, 0, -200).UnixMilli()
+	recent := now.AddDate(0, 0, -1).UnixMilli()
+	streams := map[string][]logstypes.LogStream{
+		"/app/old-stream":    {{LastEventTimestamp: aws.Int64(old), LastIngestionTime: aws.Int64(old)}},
+		"/app/recent-stream": {{LastEventTimestamp: aws.Int64(recent), LastIngestionTime: aws.Int64(recent)}},
+		// LastEventTimestamp lags behind a recent ingestion, so the events are searched
+		"/app/stale-metadata": {{LastEventTimestamp: aws.Int64(old), LastIngestionTime: aws.Int64(recent)}},
+	}
+	client := &mocks.Logs{
+		DescribeLogGroupsFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+			return &cloudwatchlogs.DescribeLogGroupsOutput{LogGroups: []logstypes.LogGroup{
+				{LogGroupName: aws.String("/app/old-stream"), CreationTime: aws.Int64(old)},
+				{LogGroupName: aws.String("/app/recent-stream"), CreationTime: aws.Int64(old)},
+				{LogGroupName: aws.String("/app/stale-metadata"), CreationTime: aws.Int64(old)},
+				{LogGroupName: aws.String("/app/no-streams"), CreationTime: aws.Int64(old)},
+				{LogGroupName: aws.String("/app/new-no-streams"), CreationTime: aws.Int64(recent)},
+				{LogGroupName: aws.String("/app/no-creation-time")},
+			}}, nil
+		},
+		DescribeLogStreamsFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeLogStreamsInput) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+			return &cloudwatchlogs.DescribeLogStreamsOutput{LogStreams: streams[aws.ToString(params.LogGroupName)]}, nil
+		},
+		FilterLogEventsFunc: func(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+			if aws.ToString(params.LogGroupName) != "/app/stale-metadata" {
+				t.Errorf("FilterLogEvents called for %s", aws.ToString(params.LogGroupName))
+			}
+			return &cloudwatchlogs.FilterLogEventsOutput{Events: []logstypes.FilteredLogEvent{{Timestamp: aws.Int64(recent)}}}, nil
+		},
+	}
+
+	groups, errs := scanLogGroups(t, context.Background(), newTestLogsScanner(client))
+
+	if len(errs) != 0 {
+		t.Fatalf("GetIdleLogGroups() errors = %v", errs)
+	}
+	if len(groups) != 2 || groups[0].Name != "/app/old-stream" || groups[1].Name != "/app/no-streams" {
+		t.Fatalf("GetIdleLogGroups() = %v, want /app/old-stream and /app/no-streams", groups)
+	}
+	oldTime := time.UnixMilli(old).Format("2006-01-02 15:04:05")
+	if got := groups[0].LastEventTime; got != oldTime {
+		t.Errorf("LastEventTime = %q, want %q", got, oldTime)
+	}
+	if got, want := groups[1].LastEventTime, "N/A (Created: "+oldTime+")"; got != want {
+		t.Errorf("LastEventTime without streams = %q, want %q", got, want)
+	}
+}
+
+func TestGetIdleLogGroupsRetentionAndCost(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -200).UnixMilli()
+	const gib = 1024 * 1024 * 1024
+	client := &mocks.Logs{
+		DescribeLogGroupsFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+			return &cloudwatchlogs.DescribeLogGroupsOutput{LogGroups: []logstypes.LogGroup{
+				{LogGroupName: aws.String("/app/never-expire"), CreationTime: aws.Int64(old), StoredBytes: aws.Int64(10 * gib)},
+				{LogGroupName: aws.String("/app/retained"), CreationTime: aws.Int64(old), StoredBytes: aws.Int64(10 * gib), RetentionInDays: aws.Int32(30)},
+				{LogGroupName: aws.String("/app/empty"), CreationTime: aws.Int64(old)},
+			}}, nil
+		},
+	}
+
+	groups, errs := scanLogGroups(t, context.Background(), newTestLogsScanner(client))
+
+	if len(errs) != 0 || len(groups) != 3 {
+		t.Fatalf("GetIdleLogGroups() = %v, %v, want 3 groups", groups, errs)
+	}
+	tests := []struct {
+		retention      string
+		cost           float64
+		recommendation string
+	}{
+		{retention: "Never expire", cost: 0.3, recommendation: "Set a retention policy"},
+		{retention: "30 days", cost: 0.3},
+		{retention: "Never expire"},
+	}
+	for i, tt := range tests {
+		group := groups[i]
+		if group.RetentionDays != tt.retention {
+			t.Errorf("%s: RetentionDays = %q, want %q", group.Name, group.RetentionDays, tt.retention)
+		}
+		if math.Abs(group.EstimatedMonthlyCost-tt.cost) > 1e-9 || group.PricingSource != string(pricing.PricingSourceDefault) {
+			t.Errorf("%s: cost = %v (%s), want %v from the default prices", group.Name, group.EstimatedMonthlyCost, group.PricingSource, tt.cost)
+		}
+		if group.Recommendation != tt.recommendation {
+			t.Errorf("%s: Recommendation = %q, want %q", group.Name, group.Recommendation, tt.recommendation)
+		}
+	}
+}
+
+func TestGetIdleLogGroupsFilters(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -200).UnixMilli()
+	denied := errors.New("AccessDeniedException: not authorized")
+	client := &mocks.Logs{
+		DescribeLogGroupsFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+			return &cloudwatchlogs.DescribeLogGroupsOutput{LogGroups: []logstypes.LogGroup{
+				{LogGroupName: aws.String("/app/subscribed"), CreationTime: aws.Int64(old)},
+				{LogGroupName: aws.String("/app/metric"), CreationTime: aws.Int64(old)},
+				{LogGroupName: aws.String("/app/denied"), CreationTime: aws.Int64(old)},
+			}}, nil
+		},
+		DescribeSubscriptionFiltersFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeSubscriptionFiltersInput) (*cloudwatchlogs.DescribeSubscriptionFiltersOutput, error) {
+			switch aws.ToString(params.LogGroupName) {
+			case "/app/subscribed":
+				return &cloudwatchlogs.DescribeSubscriptionFiltersOutput{SubscriptionFilters: []logstypes.SubscriptionFilter{{}}}, nil
+			case "/app/denied":
+				return nil, denied
+			}
+			return &cloudwatchlogs.DescribeSubscriptionFiltersOutput{}, nil
+		},
+		DescribeMetricFiltersFunc: func(ctx context.Context, params *cloudwatchlogs.DescribeMetricFiltersInput) (*cloudwatchlogs.DescribeMetricFiltersOutput, error) {
+			if aws.ToString(params.LogGroupName) == "/app/metric" {
+				return &cloudwatchlogs.DescribeMetricFiltersOutput{MetricFilters: []logstypes.MetricFilter{{}}}, nil
+			}
+			return &cloudwatchlogs.DescribeMetricFiltersOutput{}, nil
+		},
+	}
+
+	groups, errs := scanLogGroups(t, context.Background(), newTestLogsScanner(client))
+
+	if len(groups) != 3 {
+		t.Fatalf("GetIdleLogGroups() = %v, want 3 groups", groups)
+	}
+	if !groups[0].HasSubscriptionFilter || groups[0].HasMetricFilter || groups[0].FilterCheckFailed {
+		t.Errorf("/app/subscribed = %+v, want only a subscription filter", groups[0])
+	}
+	if groups[1].HasSubscriptionFilter || !groups[1].HasMetricFilter || groups[1].FilterCheckFailed {
+		t.Errorf("/app/metric = %+v, want only a metric filter", groups[1])
+	}
+	if !groups[2].FilterCheckFailed {
+		t.Errorf("/app/denied = %+v, want FilterCheckFailed", groups[2])
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], denied) {
+		t.Errorf("GetIdleLogGroups() errors = %v, want the filter error", errs)
+	}
+}
+
+func TestLogGroupRecommendation(t *testing.T) {
+	tests := []struct {
+		name        string
+		retention   *int32
+		storedBytes int64
+		want        string
+	}{
+		{name: "large without retention", storedBytes: logsRetentionRecommendationBytes, want: "Set a retention policy"},
+		{name: "small without retention", storedBytes: logsRetentionRecommendationBytes - 1},
+		{name: "large with retention", retention: aws.Int32(14), storedBytes: 2 * logsRetentionRecommendationBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logGroupRecommendation(tt.retention, tt.storedBytes); got != tt.want {
+				t.Errorf("logGroupRecommendation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+)
+
+// getMetricsSum returns the sum of the given metrics of a resource over the last days, added up
+// across the metrics. It backs the zero-traffic checks: a resource that published no datapoints
+// served nothing over the period, so missing datapoints count as zero.
+func getMetricsSum(ctx context.Context, client cloudWatchMetricsAPI, namespace string, metricNames []string, days int, dimensions ...cwtypes.Dimension) (float64, error) {
+	var total float64
+	for _, metricName := range metricNames {
+		sum, err := cloudwatchutil.SumOverWindow(ctx, client, cloudwatchutil.MetricQuery{
+			Namespace:  namespace,
+			Name:       metricName,
+			Dimensions: dimensions,
+			Stat:       cwtypes.StatisticSum,
+			WindowDays: days,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("CloudWatch API error for metric %s: %w", metricName, err)
+		}
+		total += sum
+	}
+	return total, nil
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/appconfig"
+)
+
+// AppConfig is a fake AppConfig client
+type AppConfig struct {
+	GetConfigurationFunc func(ctx context.Context, params *appconfig.GetConfigurationInput) (*appconfig.GetConfigurationOutput, error)
+}
+
+// GetConfiguration calls GetConfigurationFunc, or returns an empty output if it is nil
+func (m *AppConfig) GetConfiguration(ctx context.Context, params *appconfig.GetConfigurationInput, optFns ...func(*appconfig.Options)) (*appconfig.GetConfigurationOutput, error) {
+	if m.GetConfigurationFunc == nil {
+		return &appconfig.GetConfigurationOutput{}, nil
+	}
+	return m.GetConfigurationFunc(ctx, params)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+)
+
+// CloudTrail is a fake CloudTrail client
+type CloudTrail struct {
+	LookupEventsFunc func(ctx context.Context, params *cloudtrail.LookupEventsInput) (*cloudtrail.LookupEventsOutput, error)
+}
+
+// LookupEvents calls LookupEventsFunc, or returns an empty output if it is nil
+func (m *CloudTrail) LookupEvents(ctx context.Context, params *cloudtrail.LookupEventsInput, optFns ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	if m.LookupEventsFunc == nil {
+		return &cloudtrail.LookupEventsOutput{}, nil
+	}
+	return m.LookupEventsFunc(ctx, params)
+}
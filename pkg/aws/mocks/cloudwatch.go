@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+)
+
+// CloudWatch is a fake CloudWatch client
+type CloudWatch struct {
+	GetMetricDataFunc       func(ctx context.Context, params *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error)
+	GetMetricStatisticsFunc func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error)
+	ListMetricsFunc         func(ctx context.Context, params *cloudwatch.ListMetricsInput) (*cloudwatch.ListMetricsOutput, error)
+}
+
+// GetMetricData calls GetMetricDataFunc, or returns an empty output if it is nil
+func (m *CloudWatch) GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	if m.GetMetricDataFunc == nil {
+		return &cloudwatch.GetMetricDataOutput{}, nil
+	}
+	return m.GetMetricDataFunc(ctx, params)
+}
+
+// GetMetricStatistics calls GetMetricStatisticsFunc, or returns an empty output if it is nil
+func (m *CloudWatch) GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	if m.GetMetricStatisticsFunc == nil {
+		return &cloudwatch.GetMetricStatisticsOutput{}, nil
+	}
+	return m.GetMetricStatisticsFunc(ctx, params)
+}
+
+// ListMetrics calls ListMetricsFunc, or returns an empty output if it is nil
+func (m *CloudWatch) ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error) {
+	if m.ListMetricsFunc == nil {
+		return &cloudwatch.ListMetricsOutput{}, nil
+	}
+	return m.ListMetricsFunc(ctx, params)
+}
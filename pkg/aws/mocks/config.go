@@ -0,0 +1,83 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+)
+
+// ConfigService is a fake AWS Config client
+type ConfigService struct {
+	DescribeComplianceByConfigRuleFunc      func(ctx context.Context, params *configservice.DescribeComplianceByConfigRuleInput) (*configservice.DescribeComplianceByConfigRuleOutput, error)
+	DescribeConfigRuleEvaluationStatusFunc  func(ctx context.Context, params *configservice.DescribeConfigRuleEvaluationStatusInput) (*configservice.DescribeConfigRuleEvaluationStatusOutput, error)
+	DescribeConfigRulesFunc                 func(ctx context.Context, params *configservice.DescribeConfigRulesInput) (*configservice.DescribeConfigRulesOutput, error)
+	DescribeConfigurationRecorderStatusFunc func(ctx context.Context, params *configservice.DescribeConfigurationRecorderStatusInput) (*configservice.DescribeConfigurationRecorderStatusOutput, error)
+	DescribeConfigurationRecordersFunc      func(ctx context.Context, params *configservice.DescribeConfigurationRecordersInput) (*configservice.DescribeConfigurationRecordersOutput, error)
+	DescribeDeliveryChannelStatusFunc       func(ctx context.Context, params *configservice.DescribeDeliveryChannelStatusInput) (*configservice.DescribeDeliveryChannelStatusOutput, error)
+	DescribeDeliveryChannelsFunc            func(ctx context.Context, params *configservice.DescribeDeliveryChannelsInput) (*configservice.DescribeDeliveryChannelsOutput, error)
+	GetDiscoveredResourceCountsFunc         func(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput) (*configservice.GetDiscoveredResourceCountsOutput, error)
+}
+
+// DescribeComplianceByConfigRule calls DescribeComplianceByConfigRuleFunc, or returns an empty output if it is nil
+func (m *ConfigService) DescribeComplianceByConfigRule(ctx context.Context, params *configservice.DescribeComplianceByConfigRuleInput, optFns ...func(*configservice.Options)) (*configservice.DescribeComplianceByConfigRuleOutput, error) {
+	if m.DescribeComplianceByConfigRuleFunc == nil {
+		return &configservice.DescribeComplianceByConfigRuleOutput{}, nil
+	}
+	return m.DescribeComplianceByConfigRuleFunc(ctx, params)
+}
+
+// DescribeConfigRuleEvaluationStatus calls DescribeConfigRuleEvaluationStatusFunc, or returns an empty output if it is nil
+func (m *ConfigService) DescribeConfigRuleEvaluationStatus(ctx context.Context, params *configservice.DescribeConfigRuleEvaluationStatusInput, optFns ...func(*configservice.Options)) (*configservice.DescribeConfigRuleEvaluationStatusOutput, error) {
+	if m.DescribeConfigRuleEvaluationStatusFunc == nil {
+		return &configservice.DescribeConfigRuleEvaluationStatusOutput{}, nil
+	}
+	return m.DescribeConfigRuleEvaluationStatusFunc(ctx, params)
+}
+
+// DescribeConfigRules calls DescribeConfigRulesFunc, or returns an empty output if it is nil
+func (m *ConfigService) DescribeConfigRules(ctx context.Context, params *configservice.DescribeConfigRulesInput, optFns ...func(*configservice.Options)) (*configservice.DescribeConfigRulesOutput, error) {
+	if m.DescribeConfigRulesFunc == nil {
+		return &configservice.DescribeConfigRulesOutput{}, nil
+	}
+	return m.DescribeConfigRulesFunc(ctx, params)
+}
+
+// DescribeConfigurationRecorderStatus calls DescribeConfigurationRecorderStatusFunc, or returns an empty output if it is nil
+func (m *ConfigService) DescribeConfigurationRecorderStatus(ctx context.Context, params *configservice.DescribeConfigurationRecorderStatusInput, optFns ...func(*configservice.Options)) (*configservice.DescribeConfigurationRecorderStatusOutput, error) {
+	if m.DescribeConfigurationRecorderStatusFunc == nil {
+		return &configservice.DescribeConfigurationRecorderStatusOutput{}, nil
+	}
+	return m.DescribeConfigurationRecorderStatusFunc(ctx, params)
+}
+
+// DescribeConfigurationRecorders calls DescribeConfigurationRecordersFunc, or returns an empty output if it is nil
+func (m *ConfigService) DescribeConfigurationRecorders(ctx context.Context, params *configservice.DescribeConfigurationRecordersInput, optFns ...func(*configservice.Options)) (*configservice.DescribeConfigurationRecordersOutput, error) {
+	if m.DescribeConfigurationRecordersFunc == nil {
+		return &configservice.DescribeConfigurationRecordersOutput{}, nil
+	}
+	return m.DescribeConfigurationRecordersFunc(ctx, params)
+}
+
+// DescribeDeliveryChannelStatus calls DescribeDeliveryChannelStatusFunc, or returns an empty output if it is nil
+func (m *ConfigService) DescribeDeliveryChannelStatus(ctx context.Context, params *configservice.DescribeDeliveryChannelStatusInput, optFns ...func(*configservice.Options)) (*configservice.DescribeDeliveryChannelStatusOutput, error) {
+	if m.DescribeDeliveryChannelStatusFunc == nil {
+		return &configservice.DescribeDeliveryChannelStatusOutput{}, nil
+	}
+	return m.DescribeDeliveryChannelStatusFunc(ctx, params)
+}
+
+// DescribeDeliveryChannels calls DescribeDeliveryChannelsFunc, or returns an empty output if it is nil
+func (m *ConfigService) DescribeDeliveryChannels(ctx context.Context, params *configservice.DescribeDeliveryChannelsInput, optFns ...func(*configservice.Options)) (*configservice.DescribeDeliveryChannelsOutput, error) {
+	if m.DescribeDeliveryChannelsFunc == nil {
+		return &configservice.DescribeDeliveryChannelsOutput{}, nil
+	}
+	return m.DescribeDeliveryChannelsFunc(ctx, params)
+}
+
+// GetDiscoveredResourceCounts calls GetDiscoveredResourceCountsFunc, or returns an empty output if it is nil
+func (m *ConfigService) GetDiscoveredResourceCounts(ctx context.Context, params *configservice.GetDiscoveredResourceCountsInput, optFns ...func(*configservice.Options)) (*configservice.GetDiscoveredResourceCountsOutput, error) {
+	if m.GetDiscoveredResourceCountsFunc == nil {
+		return &configservice.GetDiscoveredResourceCountsOutput{}, nil
+	}
+	return m.GetDiscoveredResourceCountsFunc(ctx, params)
+}
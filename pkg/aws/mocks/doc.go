@@ -0,0 +1,4 @@
+// Package mocks provides fake AWS clients for the unit tests of the scanners. Each fake
+// answers an operation with its function field, or with an empty output if the field is
+// nil, so that a test only sets the operations it exercises.
+package mocks
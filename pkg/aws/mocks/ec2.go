@@ -0,0 +1,83 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// EC2 is a fake EC2 client
+type EC2 struct {
+	DeleteVolumeFunc              func(ctx context.Context, params *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error)
+	DescribeAddressesFunc         func(ctx context.Context, params *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error)
+	DescribeInstanceAttributeFunc func(ctx context.Context, params *ec2.DescribeInstanceAttributeInput) (*ec2.DescribeInstanceAttributeOutput, error)
+	DescribeInstancesFunc         func(ctx context.Context, params *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
+	DescribeNetworkInterfacesFunc func(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error)
+	DescribeSnapshotsFunc         func(ctx context.Context, params *ec2.DescribeSnapshotsInput) (*ec2.DescribeSnapshotsOutput, error)
+	DescribeVolumesFunc           func(ctx context.Context, params *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
+	ReleaseAddressFunc            func(ctx context.Context, params *ec2.ReleaseAddressInput) (*ec2.ReleaseAddressOutput, error)
+}
+
+// DeleteVolume calls DeleteVolumeFunc, or returns an empty output if it is nil
+func (m *EC2) DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+	if m.DeleteVolumeFunc == nil {
+		return &ec2.DeleteVolumeOutput{}, nil
+	}
+	return m.DeleteVolumeFunc(ctx, params)
+}
+
+// DescribeAddresses calls DescribeAddressesFunc, or returns an empty output if it is nil
+func (m *EC2) DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	if m.DescribeAddressesFunc == nil {
+		return &ec2.DescribeAddressesOutput{}, nil
+	}
+	return m.DescribeAddressesFunc(ctx, params)
+}
+
+// DescribeInstanceAttribute calls DescribeInstanceAttributeFunc, or returns an empty output if it is nil
+func (m *EC2) DescribeInstanceAttribute(ctx context.Context, params *ec2.DescribeInstanceAttributeInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceAttributeOutput, error) {
+	if m.DescribeInstanceAttributeFunc == nil {
+		return &ec2.DescribeInstanceAttributeOutput{}, nil
+	}
+	return m.DescribeInstanceAttributeFunc(ctx, params)
+}
+
+// DescribeInstances calls DescribeInstancesFunc, or returns an empty output if it is nil
+func (m *EC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	if m.DescribeInstancesFunc == nil {
+		return &ec2.DescribeInstancesOutput{}, nil
+	}
+	return m.DescribeInstancesFunc(ctx, params)
+}
+
+// DescribeNetworkInterfaces calls DescribeNetworkInterfacesFunc, or returns an empty output if it is nil
+func (m *EC2) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	if m.DescribeNetworkInterfacesFunc == nil {
+		return &ec2.DescribeNetworkInterfacesOutput{}, nil
+	}
+	return m.DescribeNetworkInterfacesFunc(ctx, params)
+}
+
+// DescribeSnapshots calls DescribeSnapshotsFunc, or returns an empty output if it is nil
+func (m *EC2) DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error) {
+	if m.DescribeSnapshotsFunc == nil {
+		return &ec2.DescribeSnapshotsOutput{}, nil
+	}
+	return m.DescribeSnapshotsFunc(ctx, params)
+}
+
+// DescribeVolumes calls DescribeVolumesFunc, or returns an empty output if it is nil
+func (m *EC2) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	if m.DescribeVolumesFunc == nil {
+		return &ec2.DescribeVolumesOutput{}, nil
+	}
+	return m.DescribeVolumesFunc(ctx, params)
+}
+
+// ReleaseAddress calls ReleaseAddressFunc, or returns an empty output if it is nil
+func (m *EC2) ReleaseAddress(ctx context.Context, params *ec2.ReleaseAddressInput, optFns ...func(*ec2.Options)) (*ec2.ReleaseAddressOutput, error) {
+	if m.ReleaseAddressFunc == nil {
+		return &ec2.ReleaseAddressOutput{}, nil
+	}
+	return m.ReleaseAddressFunc(ctx, params)
+}
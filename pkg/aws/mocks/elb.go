@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+
+	elbv1 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+)
+
+// ELB is a fake Classic Load Balancing client
+type ELB struct {
+	DescribeInstanceHealthFunc func(ctx context.Context, params *elbv1.DescribeInstanceHealthInput) (*elbv1.DescribeInstanceHealthOutput, error)
+	DescribeLoadBalancersFunc  func(ctx context.Context, params *elbv1.DescribeLoadBalancersInput) (*elbv1.DescribeLoadBalancersOutput, error)
+	DescribeTagsFunc           func(ctx context.Context, params *elbv1.DescribeTagsInput) (*elbv1.DescribeTagsOutput, error)
+}
+
+// DescribeInstanceHealth calls DescribeInstanceHealthFunc, or returns an empty output if it is nil
+func (m *ELB) DescribeInstanceHealth(ctx context.Context, params *elbv1.DescribeInstanceHealthInput, optFns ...func(*elbv1.Options)) (*elbv1.DescribeInstanceHealthOutput, error) {
+	if m.DescribeInstanceHealthFunc == nil {
+		return &elbv1.DescribeInstanceHealthOutput{}, nil
+	}
+	return m.DescribeInstanceHealthFunc(ctx, params)
+}
+
+// DescribeLoadBalancers calls DescribeLoadBalancersFunc, or returns an empty output if it is nil
+func (m *ELB) DescribeLoadBalancers(ctx context.Context, params *elbv1.DescribeLoadBalancersInput, optFns ...func(*elbv1.Options)) (*elbv1.DescribeLoadBalancersOutput, error) {
+	if m.DescribeLoadBalancersFunc == nil {
+		return &elbv1.DescribeLoadBalancersOutput{}, nil
+	}
+	return m.DescribeLoadBalancersFunc(ctx, params)
+}
+
+// DescribeTags calls DescribeTagsFunc, or returns an empty output if it is nil
+func (m *ELB) DescribeTags(ctx context.Context, params *elbv1.DescribeTagsInput, optFns ...func(*elbv1.Options)) (*elbv1.DescribeTagsOutput, error) {
+	if m.DescribeTagsFunc == nil {
+		return &elbv1.DescribeTagsOutput{}, nil
+	}
+	return m.DescribeTagsFunc(ctx, params)
+}
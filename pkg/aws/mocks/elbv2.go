@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+)
+
+// ELBV2 is a fake Elastic Load Balancing v2 client
+type ELBV2 struct {
+	DescribeLoadBalancerAttributesFunc func(ctx context.Context, params *elbv2.DescribeLoadBalancerAttributesInput) (*elbv2.DescribeLoadBalancerAttributesOutput, error)
+	DescribeLoadBalancersFunc          func(ctx context.Context, params *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error)
+	DescribeTagsFunc                   func(ctx context.Context, params *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error)
+	DescribeTargetGroupsFunc           func(ctx context.Context, params *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error)
+	DescribeTargetHealthFunc           func(ctx context.Context, params *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error)
+}
+
+// DescribeLoadBalancerAttributes calls DescribeLoadBalancerAttributesFunc, or returns an empty output if it is nil
+func (m *ELBV2) DescribeLoadBalancerAttributes(ctx context.Context, params *elbv2.DescribeLoadBalancerAttributesInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeLoadBalancerAttributesOutput, error) {
+	if m.DescribeLoadBalancerAttributesFunc == nil {
+		return &elbv2.DescribeLoadBalancerAttributesOutput{}, nil
+	}
+	return m.DescribeLoadBalancerAttributesFunc(ctx, params)
+}
+
+// DescribeLoadBalancers calls DescribeLoadBalancersFunc, or returns an empty output if it is nil
+func (m *ELBV2) DescribeLoadBalancers(ctx context.Context, params *elbv2.DescribeLoadBalancersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeLoadBalancersOutput, error) {
+	if m.DescribeLoadBalancersFunc == nil {
+		return &elbv2.DescribeLoadBalancersOutput{}, nil
+	}
+	return m.DescribeLoadBalancersFunc(ctx, params)
+}
+
+// DescribeTags calls DescribeTagsFunc, or returns an empty output if it is nil
+func (m *ELBV2) DescribeTags(ctx context.Context, params *elbv2.DescribeTagsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTagsOutput, error) {
+	if m.DescribeTagsFunc == nil {
+		return &elbv2.DescribeTagsOutput{}, nil
+	}
+	return m.DescribeTagsFunc(ctx, params)
+}
+
+// DescribeTargetGroups calls DescribeTargetGroupsFunc, or returns an empty output if it is nil
+func (m *ELBV2) DescribeTargetGroups(ctx context.Context, params *elbv2.DescribeTargetGroupsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetGroupsOutput, error) {
+	if m.DescribeTargetGroupsFunc == nil {
+		return &elbv2.DescribeTargetGroupsOutput{}, nil
+	}
+	return m.DescribeTargetGroupsFunc(ctx, params)
+}
+
+// DescribeTargetHealth calls DescribeTargetHealthFunc, or returns an empty output if it is nil
+func (m *ELBV2) DescribeTargetHealth(ctx context.Context, params *elbv2.DescribeTargetHealthInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetHealthOutput, error) {
+	if m.DescribeTargetHealthFunc == nil {
+		return &elbv2.DescribeTargetHealthOutput{}, nil
+	}
+	return m.DescribeTargetHealthFunc(ctx, params)
+}
@@ -0,0 +1,137 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// IAM is a fake IAM client
+type IAM struct {
+	GenerateServiceLastAccessedDetailsFunc func(ctx context.Context, params *iam.GenerateServiceLastAccessedDetailsInput) (*iam.GenerateServiceLastAccessedDetailsOutput, error)
+	GetAccessKeyLastUsedFunc               func(ctx context.Context, params *iam.GetAccessKeyLastUsedInput) (*iam.GetAccessKeyLastUsedOutput, error)
+	GetLoginProfileFunc                    func(ctx context.Context, params *iam.GetLoginProfileInput) (*iam.GetLoginProfileOutput, error)
+	GetRoleFunc                            func(ctx context.Context, params *iam.GetRoleInput) (*iam.GetRoleOutput, error)
+	ListAccessKeysFunc                     func(ctx context.Context, params *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error)
+	ListAttachedRolePoliciesFunc           func(ctx context.Context, params *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error)
+	ListAttachedUserPoliciesFunc           func(ctx context.Context, params *iam.ListAttachedUserPoliciesInput) (*iam.ListAttachedUserPoliciesOutput, error)
+	ListMFADevicesFunc                     func(ctx context.Context, params *iam.ListMFADevicesInput) (*iam.ListMFADevicesOutput, error)
+	ListPoliciesFunc                       func(ctx context.Context, params *iam.ListPoliciesInput) (*iam.ListPoliciesOutput, error)
+	ListPolicyVersionsFunc                 func(ctx context.Context, params *iam.ListPolicyVersionsInput) (*iam.ListPolicyVersionsOutput, error)
+	ListRolePoliciesFunc                   func(ctx context.Context, params *iam.ListRolePoliciesInput) (*iam.ListRolePoliciesOutput, error)
+	ListRolesFunc                          func(ctx context.Context, params *iam.ListRolesInput) (*iam.ListRolesOutput, error)
+	ListUserPoliciesFunc                   func(ctx context.Context, params *iam.ListUserPoliciesInput) (*iam.ListUserPoliciesOutput, error)
+	ListUsersFunc                          func(ctx context.Context, params *iam.ListUsersInput) (*iam.ListUsersOutput, error)
+}
+
+// GenerateServiceLastAccessedDetails calls GenerateServiceLastAccessedDetailsFunc, or returns an empty output if it is nil
+func (m *IAM) GenerateServiceLastAccessedDetails(ctx context.Context, params *iam.GenerateServiceLastAccessedDetailsInput, optFns ...func(*iam.Options)) (*iam.GenerateServiceLastAccessedDetailsOutput, error) {
+	if m.GenerateServiceLastAccessedDetailsFunc == nil {
+		return &iam.GenerateServiceLastAccessedDetailsOutput{}, nil
+	}
+	return m.GenerateServiceLastAccessedDetailsFunc(ctx, params)
+}
+
+// GetAccessKeyLastUsed calls GetAccessKeyLastUsedFunc, or returns an empty output if it is nil
+func (m *IAM) GetAccessKeyLastUsed(ctx context.Context, params *iam.GetAccessKeyLastUsedInput, optFns ...func(*iam.Options)) (*iam.GetAccessKeyLastUsedOutput, error) {
+	if m.GetAccessKeyLastUsedFunc == nil {
+		return &iam.GetAccessKeyLastUsedOutput{}, nil
+	}
+	return m.GetAccessKeyLastUsedFunc(ctx, params)
+}
+
+// GetLoginProfile calls GetLoginProfileFunc, or returns an empty output if it is nil
+func (m *IAM) GetLoginProfile(ctx context.Context, params *iam.GetLoginProfileInput, optFns ...func(*iam.Options)) (*iam.GetLoginProfileOutput, error) {
+	if m.GetLoginProfileFunc == nil {
+		return &iam.GetLoginProfileOutput{}, nil
+	}
+	return m.GetLoginProfileFunc(ctx, params)
+}
+
+// GetRole calls GetRoleFunc, or returns an empty output if it is nil
+func (m *IAM) GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	if m.GetRoleFunc == nil {
+		return &iam.GetRoleOutput{}, nil
+	}
+	return m.GetRoleFunc(ctx, params)
+}
+
+// ListAccessKeys calls ListAccessKeysFunc, or returns an empty output if it is nil
+func (m *IAM) ListAccessKeys(ctx context.Context, params *iam.ListAccessKeysInput, optFns ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+	if m.ListAccessKeysFunc == nil {
+		return &iam.ListAccessKeysOutput{}, nil
+	}
+	return m.ListAccessKeysFunc(ctx, params)
+}
+
+// ListAttachedRolePolicies calls ListAttachedRolePoliciesFunc, or returns an empty output if it is nil
+func (m *IAM) ListAttachedRolePolicies(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	if m.ListAttachedRolePoliciesFunc == nil {
+		return &iam.ListAttachedRolePoliciesOutput{}, nil
+	}
+	return m.ListAttachedRolePoliciesFunc(ctx, params)
+}
+
+// ListAttachedUserPolicies calls ListAttachedUserPoliciesFunc, or returns an empty output if it is nil
+func (m *IAM) ListAttachedUserPolicies(ctx context.Context, params *iam.ListAttachedUserPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error) {
+	if m.ListAttachedUserPoliciesFunc == nil {
+		return &iam.ListAttachedUserPoliciesOutput{}, nil
+	}
+	return m.ListAttachedUserPoliciesFunc(ctx, params)
+}
+
+// ListMFADevices calls ListMFADevicesFunc, or returns an empty output if it is nil
+func (m *IAM) ListMFADevices(ctx context.Context, params *iam.ListMFADevicesInput, optFns ...func(*iam.Options)) (*iam.ListMFADevicesOutput, error) {
+	if m.ListMFADevicesFunc == nil {
+		return &iam.ListMFADevicesOutput{}, nil
+	}
+	return m.ListMFADevicesFunc(ctx, params)
+}
+
+// ListPolicies calls ListPoliciesFunc, or returns an empty output if it is nil
+func (m *IAM) ListPolicies(ctx context.Context, params *iam.ListPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListPoliciesOutput, error) {
+	if m.ListPoliciesFunc == nil {
+		return &iam.ListPoliciesOutput{}, nil
+	}
+	return m.ListPoliciesFunc(ctx, params)
+}
+
+// ListPolicyVersions calls ListPolicyVersionsFunc, or returns an empty output if it is nil
+func (m *IAM) ListPolicyVersions(ctx context.Context, params *iam.ListPolicyVersionsInput, optFns ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error) {
+	if m.ListPolicyVersionsFunc == nil {
+		return &iam.ListPolicyVersionsOutput{}, nil
+	}
+	return m.ListPolicyVersionsFunc(ctx, params)
+}
+
+// ListRolePolicies calls ListRolePoliciesFunc, or returns an empty output if it is nil
+func (m *IAM) ListRolePolicies(ctx context.Context, params *iam.ListRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+	if m.ListRolePoliciesFunc == nil {
+		return &iam.ListRolePoliciesOutput{}, nil
+	}
+	return m.ListRolePoliciesFunc(ctx, params)
+}
+
+// ListRoles calls ListRolesFunc, or returns an empty output if it is nil
+func (m *IAM) ListRoles(ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
+	if m.ListRolesFunc == nil {
+		return &iam.ListRolesOutput{}, nil
+	}
+	return m.ListRolesFunc(ctx, params)
+}
+
+// ListUserPolicies calls ListUserPoliciesFunc, or returns an empty output if it is nil
+func (m *IAM) ListUserPolicies(ctx context.Context, params *iam.ListUserPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error) {
+	if m.ListUserPoliciesFunc == nil {
+		return &iam.ListUserPoliciesOutput{}, nil
+	}
+	return m.ListUserPoliciesFunc(ctx, params)
+}
+
+// ListUsers calls ListUsersFunc, or returns an empty output if it is nil
+func (m *IAM) ListUsers(ctx context.Context, params *iam.ListUsersInput, optFns ...func(*iam.Options)) (*iam.ListUsersOutput, error) {
+	if m.ListUsersFunc == nil {
+		return &iam.ListUsersOutput{}, nil
+	}
+	return m.ListUsersFunc(ctx, params)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/inspector2"
+)
+
+// Inspector2 is a fake Amazon Inspector2 client
+type Inspector2 struct {
+	ListCoverageFunc func(ctx context.Context, params *inspector2.ListCoverageInput) (*inspector2.ListCoverageOutput, error)
+}
+
+// ListCoverage calls ListCoverageFunc, or returns an empty output if it is nil
+func (m *Inspector2) ListCoverage(ctx context.Context, params *inspector2.ListCoverageInput, optFns ...func(*inspector2.Options)) (*inspector2.ListCoverageOutput, error) {
+	if m.ListCoverageFunc == nil {
+		return &inspector2.ListCoverageOutput{}, nil
+	}
+	return m.ListCoverageFunc(ctx, params)
+}
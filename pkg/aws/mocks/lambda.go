@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+// Lambda is a fake Lambda client
+type Lambda struct {
+	GetFunctionConcurrencyFunc            func(ctx context.Context, params *lambda.GetFunctionConcurrencyInput) (*lambda.GetFunctionConcurrencyOutput, error)
+	GetPolicyFunc                         func(ctx context.Context, params *lambda.GetPolicyInput) (*lambda.GetPolicyOutput, error)
+	ListEventSourceMappingsFunc           func(ctx context.Context, params *lambda.ListEventSourceMappingsInput) (*lambda.ListEventSourceMappingsOutput, error)
+	ListFunctionsFunc                     func(ctx context.Context, params *lambda.ListFunctionsInput) (*lambda.ListFunctionsOutput, error)
+	ListProvisionedConcurrencyConfigsFunc func(ctx context.Context, params *lambda.ListProvisionedConcurrencyConfigsInput) (*lambda.ListProvisionedConcurrencyConfigsOutput, error)
+	ListTagsFunc                          func(ctx context.Context, params *lambda.ListTagsInput) (*lambda.ListTagsOutput, error)
+}
+
+// GetFunctionConcurrency calls GetFunctionConcurrencyFunc, or returns an empty output if it is nil
+func (m *Lambda) GetFunctionConcurrency(ctx context.Context, params *lambda.GetFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionConcurrencyOutput, error) {
+	if m.GetFunctionConcurrencyFunc == nil {
+		return &lambda.GetFunctionConcurrencyOutput{}, nil
+	}
+	return m.GetFunctionConcurrencyFunc(ctx, params)
+}
+
+// GetPolicy calls GetPolicyFunc, or returns an empty output if it is nil
+func (m *Lambda) GetPolicy(ctx context.Context, params *lambda.GetPolicyInput, optFns ...func(*lambda.Options)) (*lambda.GetPolicyOutput, error) {
+	if m.GetPolicyFunc == nil {
+		return &lambda.GetPolicyOutput{}, nil
+	}
+	return m.GetPolicyFunc(ctx, params)
+}
+
+// ListEventSourceMappings calls ListEventSourceMappingsFunc, or returns an empty output if it is nil
+func (m *Lambda) ListEventSourceMappings(ctx context.Context, params *lambda.ListEventSourceMappingsInput, optFns ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error) {
+	if m.ListEventSourceMappingsFunc == nil {
+		return &lambda.ListEventSourceMappingsOutput{}, nil
+	}
+	return m.ListEventSourceMappingsFunc(ctx, params)
+}
+
+// ListFunctions calls ListFunctionsFunc, or returns an empty output if it is nil
+func (m *Lambda) ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	if m.ListFunctionsFunc == nil {
+		return &lambda.ListFunctionsOutput{}, nil
+	}
+	return m.ListFunctionsFunc(ctx, params)
+}
+
+// ListProvisionedConcurrencyConfigs calls ListProvisionedConcurrencyConfigsFunc, or returns an empty output if it is nil
+func (m *Lambda) ListProvisionedConcurrencyConfigs(ctx context.Context, params *lambda.ListProvisionedConcurrencyConfigsInput, optFns ...func(*lambda.Options)) (*lambda.ListProvisionedConcurrencyConfigsOutput, error) {
+	if m.ListProvisionedConcurrencyConfigsFunc == nil {
+		return &lambda.ListProvisionedConcurrencyConfigsOutput{}, nil
+	}
+	return m.ListProvisionedConcurrencyConfigsFunc(ctx, params)
+}
+
+// ListTags calls ListTagsFunc, or returns an empty output if it is nil
+func (m *Lambda) ListTags(ctx context.Context, params *lambda.ListTagsInput, optFns ...func(*lambda.Options)) (*lambda.ListTagsOutput, error) {
+	if m.ListTagsFunc == nil {
+		return &lambda.ListTagsOutput{}, nil
+	}
+	return m.ListTagsFunc(ctx, params)
+}
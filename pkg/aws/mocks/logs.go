@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// Logs is a fake CloudWatch Logs client
+type Logs struct {
+	DescribeLogGroupsFunc           func(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	DescribeLogStreamsFunc          func(ctx context.Context, params *cloudwatchlogs.DescribeLogStreamsInput) (*cloudwatchlogs.DescribeLogStreamsOutput, error)
+	FilterLogEventsFunc             func(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput) (*cloudwatchlogs.FilterLogEventsOutput, error)
+	DescribeSubscriptionFiltersFunc func(ctx context.Context, params *cloudwatchlogs.DescribeSubscriptionFiltersInput) (*cloudwatchlogs.DescribeSubscriptionFiltersOutput, error)
+	DescribeMetricFiltersFunc       func(ctx context.Context, params *cloudwatchlogs.DescribeMetricFiltersInput) (*cloudwatchlogs.DescribeMetricFiltersOutput, error)
+}
+
+// DescribeLogGroups calls DescribeLogGroupsFunc, or returns an empty output if it is nil
+func (m *Logs) DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	if m.DescribeLogGroupsFunc == nil {
+		return &cloudwatchlogs.DescribeLogGroupsOutput{}, nil
+	}
+	return m.DescribeLogGroupsFunc(ctx, params)
+}
+
+// DescribeLogStreams calls DescribeLogStreamsFunc, or returns an empty output if it is nil
+func (m *Logs) DescribeLogStreams(ctx context.Context, params *cloudwatchlogs.DescribeLogStreamsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	if m.DescribeLogStreamsFunc == nil {
+		return &cloudwatchlogs.DescribeLogStreamsOutput{}, nil
+	}
+	return m.DescribeLogStreamsFunc(ctx, params)
+}
+
+// FilterLogEvents calls FilterLogEventsFunc, or returns an empty output if it is nil
+func (m *Logs) FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	if m.FilterLogEventsFunc == nil {
+		return &cloudwatchlogs.FilterLogEventsOutput{}, nil
+	}
+	return m.FilterLogEventsFunc(ctx, params)
+}
+
+// DescribeSubscriptionFilters calls DescribeSubscriptionFiltersFunc, or returns an empty output if it is nil
+func (m *Logs) DescribeSubscriptionFilters(ctx context.Context, params *cloudwatchlogs.DescribeSubscriptionFiltersInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeSubscriptionFiltersOutput, error) {
+	if m.DescribeSubscriptionFiltersFunc == nil {
+		return &cloudwatchlogs.DescribeSubscriptionFiltersOutput{}, nil
+	}
+	return m.DescribeSubscriptionFiltersFunc(ctx, params)
+}
+
+// DescribeMetricFilters calls DescribeMetricFiltersFunc, or returns an empty output if it is nil
+func (m *Logs) DescribeMetricFilters(ctx context.Context, params *cloudwatchlogs.DescribeMetricFiltersInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeMetricFiltersOutput, error) {
+	if m.DescribeMetricFiltersFunc == nil {
+		return &cloudwatchlogs.DescribeMetricFiltersOutput{}, nil
+	}
+	return m.DescribeMetricFiltersFunc(ctx, params)
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+)
+
+// OpenSearch is a fake OpenSearch Service client
+type OpenSearch struct {
+	ListDomainNamesFunc func(ctx context.Context, params *opensearch.ListDomainNamesInput) (*opensearch.ListDomainNamesOutput, error)
+	DescribeDomainsFunc func(ctx context.Context, params *opensearch.DescribeDomainsInput) (*opensearch.DescribeDomainsOutput, error)
+}
+
+// ListDomainNames calls ListDomainNamesFunc, or returns an empty output if it is nil
+func (m *OpenSearch) ListDomainNames(ctx context.Context, params *opensearch.ListDomainNamesInput, optFns ...func(*opensearch.Options)) (*opensearch.ListDomainNamesOutput, error) {
+	if m.ListDomainNamesFunc == nil {
+		return &opensearch.ListDomainNamesOutput{}, nil
+	}
+	return m.ListDomainNamesFunc(ctx, params)
+}
+
+// DescribeDomains calls DescribeDomainsFunc, or returns an empty output if it is nil
+func (m *OpenSearch) DescribeDomains(ctx context.Context, params *opensearch.DescribeDomainsInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeDomainsOutput, error) {
+	if m.DescribeDomainsFunc == nil {
+		return &opensearch.DescribeDomainsOutput{}, nil
+	}
+	return m.DescribeDomainsFunc(ctx, params)
+}
@@ -0,0 +1,128 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3 is a fake S3 client
+type S3 struct {
+	DeleteBucketFunc                       func(ctx context.Context, params *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error)
+	GetBucketLifecycleConfigurationFunc    func(ctx context.Context, params *s3.GetBucketLifecycleConfigurationInput) (*s3.GetBucketLifecycleConfigurationOutput, error)
+	GetBucketLocationFunc                  func(ctx context.Context, params *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error)
+	GetBucketNotificationConfigurationFunc func(ctx context.Context, params *s3.GetBucketNotificationConfigurationInput) (*s3.GetBucketNotificationConfigurationOutput, error)
+	GetBucketPolicyFunc                    func(ctx context.Context, params *s3.GetBucketPolicyInput) (*s3.GetBucketPolicyOutput, error)
+	GetBucketTaggingFunc                   func(ctx context.Context, params *s3.GetBucketTaggingInput) (*s3.GetBucketTaggingOutput, error)
+	GetBucketVersioningFunc                func(ctx context.Context, params *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error)
+	GetBucketWebsiteFunc                   func(ctx context.Context, params *s3.GetBucketWebsiteInput) (*s3.GetBucketWebsiteOutput, error)
+	GetObjectLockConfigurationFunc         func(ctx context.Context, params *s3.GetObjectLockConfigurationInput) (*s3.GetObjectLockConfigurationOutput, error)
+	HeadBucketFunc                         func(ctx context.Context, params *s3.HeadBucketInput) (*s3.HeadBucketOutput, error)
+	ListBucketsFunc                        func(ctx context.Context, params *s3.ListBucketsInput) (*s3.ListBucketsOutput, error)
+	ListMultipartUploadsFunc               func(ctx context.Context, params *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error)
+	PutObjectFunc                          func(ctx context.Context, params *s3.PutObjectInput) (*s3.PutObjectOutput, error)
+}
+
+// DeleteBucket calls DeleteBucketFunc, or returns an empty output if it is nil
+func (m *S3) DeleteBucket(ctx context.Context, params *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+	if m.DeleteBucketFunc == nil {
+		return &s3.DeleteBucketOutput{}, nil
+	}
+	return m.DeleteBucketFunc(ctx, params)
+}
+
+// GetBucketLifecycleConfiguration calls GetBucketLifecycleConfigurationFunc, or returns an empty output if it is nil
+func (m *S3) GetBucketLifecycleConfiguration(ctx context.Context, params *s3.GetBucketLifecycleConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+	if m.GetBucketLifecycleConfigurationFunc == nil {
+		return &s3.GetBucketLifecycleConfigurationOutput{}, nil
+	}
+	return m.GetBucketLifecycleConfigurationFunc(ctx, params)
+}
+
+// GetBucketLocation calls GetBucketLocationFunc, or returns an empty output if it is nil
+func (m *S3) GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	if m.GetBucketLocationFunc == nil {
+		return &s3.GetBucketLocationOutput{}, nil
+	}
+	return m.GetBucketLocationFunc(ctx, params)
+}
+
+// GetBucketNotificationConfiguration calls GetBucketNotificationConfigurationFunc, or returns an empty output if it is nil
+func (m *S3) GetBucketNotificationConfiguration(ctx context.Context, params *s3.GetBucketNotificationConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error) {
+	if m.GetBucketNotificationConfigurationFunc == nil {
+		return &s3.GetBucketNotificationConfigurationOutput{}, nil
+	}
+	return m.GetBucketNotificationConfigurationFunc(ctx, params)
+}
+
+// GetBucketPolicy calls GetBucketPolicyFunc, or returns an empty output if it is nil
+func (m *S3) GetBucketPolicy(ctx context.Context, params *s3.GetBucketPolicyInput, optFns ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+	if m.GetBucketPolicyFunc == nil {
+		return &s3.GetBucketPolicyOutput{}, nil
+	}
+	return m.GetBucketPolicyFunc(ctx, params)
+}
+
+// GetBucketTagging calls GetBucketTaggingFunc, or returns an empty output if it is nil
+func (m *S3) GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	if m.GetBucketTaggingFunc == nil {
+		return &s3.GetBucketTaggingOutput{}, nil
+	}
+	return m.GetBucketTaggingFunc(ctx, params)
+}
+
+// GetBucketVersioning calls GetBucketVersioningFunc, or returns an empty output if it is nil
+func (m *S3) GetBucketVersioning(ctx context.Context, params *s3.GetBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+	if m.GetBucketVersioningFunc == nil {
+		return &s3.GetBucketVersioningOutput{}, nil
+	}
+	return m.GetBucketVersioningFunc(ctx, params)
+}
+
+// GetBucketWebsite calls GetBucketWebsiteFunc, or returns an empty output if it is nil
+func (m *S3) GetBucketWebsite(ctx context.Context, params *s3.GetBucketWebsiteInput, optFns ...func(*s3.Options)) (*s3.GetBucketWebsiteOutput, error) {
+	if m.GetBucketWebsiteFunc == nil {
+		return &s3.GetBucketWebsiteOutput{}, nil
+	}
+	return m.GetBucketWebsiteFunc(ctx, params)
+}
+
+// GetObjectLockConfiguration calls GetObjectLockConfigurationFunc, or returns an empty output if it is nil
+func (m *S3) GetObjectLockConfiguration(ctx context.Context, params *s3.GetObjectLockConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+	if m.GetObjectLockConfigurationFunc == nil {
+		return &s3.GetObjectLockConfigurationOutput{}, nil
+	}
+	return m.GetObjectLockConfigurationFunc(ctx, params)
+}
+
+// HeadBucket calls HeadBucketFunc, or returns an empty output if it is nil
+func (m *S3) HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+	if m.HeadBucketFunc == nil {
+		return &s3.HeadBucketOutput{}, nil
+	}
+	return m.HeadBucketFunc(ctx, params)
+}
+
+// ListBuckets calls ListBucketsFunc, or returns an empty output if it is nil
+func (m *S3) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	if m.ListBucketsFunc == nil {
+		return &s3.ListBucketsOutput{}, nil
+	}
+	return m.ListBucketsFunc(ctx, params)
+}
+
+// ListMultipartUploads calls ListMultipartUploadsFunc, or returns an empty output if it is nil
+func (m *S3) ListMultipartUploads(ctx context.Context, params *s3.ListMultipartUploadsInput, optFns ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+	if m.ListMultipartUploadsFunc == nil {
+		return &s3.ListMultipartUploadsOutput{}, nil
+	}
+	return m.ListMultipartUploadsFunc(ctx, params)
+}
+
+// PutObject calls PutObjectFunc, or returns an empty output if it is nil
+func (m *S3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if m.PutObjectFunc == nil {
+		return &s3.PutObjectOutput{}, nil
+	}
+	return m.PutObjectFunc(ctx, params)
+}
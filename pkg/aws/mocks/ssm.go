@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// SSM is a fake SSM client
+type SSM struct {
+	GetParameterFunc func(ctx context.Context, params *ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
+}
+
+// GetParameter calls GetParameterFunc, or returns an empty output if it is nil
+func (m *SSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	if m.GetParameterFunc == nil {
+		return &ssm.GetParameterOutput{}, nil
+	}
+	return m.GetParameterFunc(ctx, params)
+}
@@ -3,6 +3,8 @@ package aws
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -10,10 +12,9 @@ import (
 	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/kafka"
 	"github.com/aws/aws-sdk-go-v2/service/kafka/types"
-
-	// kafkaconnecttypes "github.com/aws/aws-sdk-go-v2/service/kafkaconnect/types" // State type might be directly in kafka types
 	"github.com/younsl/idled/internal/models"
-	// Alias for pkg utils
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
 )
 
 const (
@@ -23,6 +24,8 @@ const (
 	mskMetricConnectionCount = "ConnectionCount"
 	mskConnStatistic         = cwtypes.StatisticMaximum
 	idleConnectionThreshold  = 0
+	// Consecutive days up to the latest one without connections for a cluster to be idle
+	mskIdleConnectionDays = 7
 	// CPU Check
 	mskMetricCPUSystem     = "CpuSystem"
 	mskMetricCPUUser       = "CpuUser"
@@ -30,11 +33,18 @@ const (
 	lowCPUThresholdPercent = 30.0 // Changed threshold to 30%
 )
 
+// kafkaAPI is the MSK API used by MskScanner
+type kafkaAPI interface {
+	kafka.ListClustersV2APIClient
+	kafka.ListNodesAPIClient
+}
+
 // MskScanner contains the AWS clients needed for scanning MSK resources
 type MskScanner struct {
-	KafkaClient *kafka.Client
-	CWClient    *cloudwatch.Client
+	KafkaClient kafkaAPI
+	CWClient    cloudwatch.GetMetricDataAPIClient
 	Region      string
+	Pricing     *pricing.PricingService
 }
 
 // NewMskScanner creates a new MskScanner for a given region
@@ -43,334 +53,264 @@ func NewMskScanner(cfg aws.Config) *MskScanner {
 		KafkaClient: kafka.NewFromConfig(cfg),
 		CWClient:    cloudwatch.NewFromConfig(cfg),
 		Region:      cfg.Region,
+		Pricing:     pricing.Default(),
 	}
 }
 
-// GetIdleMskClusters scans all MSK clusters and identifies idle/underutilized ones
+// GetIdleMskClusters scans all provisioned and serverless MSK clusters and identifies idle/underutilized ones
 func (s *MskScanner) GetIdleMskClusters(ctx context.Context) ([]models.MskClusterInfo, []error) {
 	var allClusters []models.MskClusterInfo
-	var clusterArns []string
+	var clusters []types.Cluster
 	var scanErrs []error
-	clusterDetails := make(map[string]*types.ClusterInfo)
 
-	// 1. List all clusters using ListClusters (pagination)
-	listPaginator := kafka.NewListClustersPaginator(s.KafkaClient, &kafka.ListClustersInput{})
+	// 1. List all clusters using ListClustersV2 (pagination), which also returns serverless clusters
+	listPaginator := kafka.NewListClustersV2Paginator(s.KafkaClient, &kafka.ListClustersV2Input{})
 	pageCount := 0
 	for listPaginator.HasMorePages() {
 		pageCount++
 		listOutput, err := listPaginator.NextPage(ctx)
 		if err != nil {
-			// Error message is handled by the main error processing logic
-			// sp.FinalMSG = fmt.Sprintf("✗ Error listing MSK clusters page %d in %s\n", pageCount, s.Region)
 			scanErrs = append(scanErrs, fmt.Errorf("error listing MSK clusters page %d: %w", pageCount, err))
 			break // Stop processing this region on pagination error
 		}
-		if listOutput != nil {
-			for _, clusterInfo := range listOutput.ClusterInfoList {
-				if clusterInfo.ClusterArn != nil {
-					arn := *clusterInfo.ClusterArn
-					clusterArns = append(clusterArns, arn)
-					// Store the pointer to ClusterInfo from ListClusters initially
-					// Need to make a copy if we modify it later based on DescribeCluster?
-					// Let's store the value first, then update with DescribeCluster info.
-					tempInfo := clusterInfo // Create a copy
-					clusterDetails[arn] = &tempInfo
-				}
+		for _, cluster := range listOutput.ClusterInfoList {
+			if cluster.ClusterArn != nil {
+				clusters = append(clusters, cluster)
 			}
 		}
 	}
 
-	if len(clusterArns) == 0 {
-		// No need for specific message here, main handler reports 0 items found.
-		// sp.FinalMSG = fmt.Sprintf("✓ No MSK clusters found in %s\n", s.Region)
-		return allClusters, scanErrs
-	}
-
-	// 2. Describe each cluster and List Nodes
-	brokerIDsMap := make(map[string][]string) // Map ARN to list of Broker IDs
-	for arn, detailsPtr := range clusterDetails {
-		// Describe Cluster (mainly for CreationTime?)
-		descInput := &kafka.DescribeClusterInput{ClusterArn: aws.String(arn)}
-		descOutput, descErr := s.KafkaClient.DescribeCluster(ctx, descInput)
-		if descErr != nil {
-			warnMsg := fmt.Sprintf("Warning: could not describe MSK cluster %s in %s: %v", arn, s.Region, descErr)
-			fmt.Println(warnMsg) // Print warning
-			scanErrs = append(scanErrs, fmt.Errorf(warnMsg))
-			delete(clusterDetails, arn)
-			continue
-		}
-		if descOutput != nil && descOutput.ClusterInfo != nil {
-			describedInfo := descOutput.ClusterInfo
-			detailsPtr.CreationTime = describedInfo.CreationTime
-			detailsPtr.State = describedInfo.State
-			detailsPtr.ClusterName = describedInfo.ClusterName
-		} else {
-			// Handle unexpected empty response
-			warnMsg := fmt.Sprintf("Warning: DescribeCluster returned empty info for %s in %s", arn, s.Region)
-			fmt.Println(warnMsg)
-			delete(clusterDetails, arn)
-			continue
+	// 2. Check the activity of each cluster
+	for _, cluster := range clusters {
+		arn := aws.ToString(cluster.ClusterArn)
+		clusterName := aws.ToString(cluster.ClusterName)
+
+		info := models.MskClusterInfo{
+			ClusterName:   clusterName,
+			ARN:           arn,
+			Region:        s.Region,
+			State:         string(cluster.State),
+			ClusterType:   string(cluster.ClusterType),
+			InstanceType:  "N/A",
+			CreationTime:  aws.ToTime(cluster.CreationTime),
+			PricingSource: string(pricing.PricingSourceNA),
 		}
 
-		// List Nodes to get Broker IDs
-		nodesInput := &kafka.ListNodesInput{ClusterArn: aws.String(arn)}
-		var brokerIDs []string
-		nodesPaginator := kafka.NewListNodesPaginator(s.KafkaClient, nodesInput)
-		for nodesPaginator.HasMorePages() {
-			nodesOutput, nodesErr := nodesPaginator.NextPage(ctx)
-			if nodesErr != nil {
-				warnMsg := fmt.Sprintf("Warning: could not list nodes for cluster %s: %v", arn, nodesErr)
-				fmt.Println(warnMsg)
-				scanErrs = append(scanErrs, fmt.Errorf(warnMsg))
-				// Mark broker list as potentially incomplete or break?
-				// Let's break for now, as we can't reliably get metrics without all brokers
-				brokerIDs = nil // Indicate failure to get broker IDs
-				break
-			}
-			if nodesOutput != nil {
-				for _, nodeInfo := range nodesOutput.NodeInfoList {
-					if nodeInfo.BrokerNodeInfo != nil && nodeInfo.BrokerNodeInfo.BrokerId != nil {
-						// Format BrokerId (*float64) as integer string for dimension
-						brokerIDs = append(brokerIDs, fmt.Sprintf("%d", int64(*nodeInfo.BrokerNodeInfo.BrokerId)))
-					}
+		// Serverless clusters have no brokers, so their metrics only have the Cluster Name dimension
+		brokerIDs := []string{""}
+		if cluster.ClusterType == types.ClusterTypeProvisioned {
+			if cluster.Provisioned != nil {
+				info.BrokerCount = int(aws.ToInt32(cluster.Provisioned.NumberOfBrokerNodes))
+				if cluster.Provisioned.BrokerNodeGroupInfo != nil && cluster.Provisioned.BrokerNodeGroupInfo.InstanceType != nil {
+					info.InstanceType = *cluster.Provisioned.BrokerNodeGroupInfo.InstanceType
+					info.EstimatedMonthlyCost, info.PricingSource = s.Pricing.CalculateMSKMonthlyCostWithSource(info.InstanceType, info.BrokerCount, s.Region)
 				}
 			}
-		}
-		if brokerIDs != nil { // Only store if ListNodes succeeded
-			brokerIDsMap[arn] = brokerIDs
-		} else {
-			// Failed to get broker IDs, remove cluster from further processing
-			delete(clusterDetails, arn)
-		}
-	}
 
-	processedCount := 0
-	for arn, details := range clusterDetails {
-		processedCount++
-		// Update suffix for progress
-		// sp.Suffix = fmt.Sprintf(" (%d/%d)", processedCount, totalClusters)
-
-		creationTime := aws.ToTime(details.CreationTime)
-		state := details.State
-		clusterName := aws.ToString(details.ClusterName)
-		brokerIDs := brokerIDsMap[arn]
-
-		// Get Instance Type from BrokerNodeGroupInfo
-		instanceType := "N/A"
-		if details.BrokerNodeGroupInfo != nil && details.BrokerNodeGroupInfo.InstanceType != nil { // Check pointer
-			instanceType = *details.BrokerNodeGroupInfo.InstanceType // Dereference pointer
+			var err error
+			brokerIDs, err = s.listBrokerIDs(ctx, arn)
+			if err != nil {
+				slog.Warn("Could not list MSK cluster nodes", "cluster", arn, "error", err)
+				scanErrs = append(scanErrs, fmt.Errorf("could not list nodes for cluster %s: %w", arn, err))
+				allClusters = append(allClusters, info)
+				continue
+			}
 		}
 
-		// Check Connection Count using broker IDs
-		maxConnections, connErrs := s.getMaxConnectionCount(ctx, clusterName, brokerIDs)
-		if len(connErrs) > 0 {
-			scanErrs = append(scanErrs, connErrs...)
+		// Clusters still being created have no brokers and no metrics yet, so report them with their state only
+		if len(brokerIDs) == 0 {
+			allClusters = append(allClusters, info)
+			continue
 		}
 
-		// Check CPU Utilization using broker IDs
-		avgCPU, cpuErrs := s.getAvgCPUUtilization(ctx, clusterName, brokerIDs)
-		if len(cpuErrs) > 0 {
-			scanErrs = append(scanErrs, cpuErrs...)
+		// Check connection count and CPU utilization from the daily broker metrics
+		activity, err := s.getClusterActivity(ctx, clusterName, brokerIDs)
+		if err != nil {
+			slog.Warn("Could not get MSK broker metrics", "cluster", clusterName, "region", s.Region, "error", err)
+			scanErrs = append(scanErrs, err)
 		}
 
-		isIdle := false
-		reason := "" // Default reason is empty (not idle)
-		connIdle := maxConnections != nil && *maxConnections <= idleConnectionThreshold
-		cpuIdle := avgCPU != nil && *avgCPU < lowCPUThresholdPercent
+		connIdle := activity.consecutiveZeroConnectionDays >= mskIdleConnectionDays
+		cpuIdle := activity.avgCPU != nil && *activity.avgCPU < lowCPUThresholdPercent
 
 		if connIdle && cpuIdle {
-			isIdle = true
-			reason = "No Conn & Low CPU"
+			info.IsIdle = true
+			info.Reason = "No Conn & Low CPU"
 		} else if connIdle {
-			isIdle = true
-			reason = "No Connections"
+			info.IsIdle = true
+			info.Reason = "No Connections"
 		} else if cpuIdle {
-			isIdle = true
-			reason = "Low CPU Usage"
+			info.IsIdle = true
+			info.Reason = "Low CPU Usage"
 		}
 
-		// Append ALL successfully processed clusters to the result slice
-		allClusters = append(allClusters, models.MskClusterInfo{
-			ClusterName:       clusterName,
-			ARN:               arn,
-			Region:            s.Region,
-			State:             string(state),
-			InstanceType:      instanceType,
-			CreationTime:      creationTime,
-			IsIdle:            isIdle, // Mark true/false
-			Reason:            reason, // Populate reason if idle, otherwise empty
-			ConnectionCount:   maxConnections,
-			AvgCPUUtilization: avgCPU,
-		})
+		info.ConnectionCount = activity.maxConnections
+		info.AvgCPUUtilization = activity.avgCPU
+		info.ZeroConnectionDays = activity.zeroConnectionDays
+		info.ConsecutiveZeroConnectionDays = activity.consecutiveZeroConnectionDays
+
+		// Append ALL processed clusters to the result slice
+		allClusters = append(allClusters, info)
 	}
 
 	return allClusters, scanErrs // Return results and any errors encountered during the scan
 }
 
-// getMaxConnectionCount retrieves the maximum connection count across all brokers
-func (s *MskScanner) getMaxConnectionCount(ctx context.Context, clusterName string, brokerIDs []string) (*float64, []error) {
-	var maxConn *float64
-	var errs []error
-	foundData := false
-
-	if len(brokerIDs) == 0 {
-		return nil, []error{fmt.Errorf("no broker IDs provided for cluster %s", clusterName)}
-	}
-
-	for _, brokerID := range brokerIDs {
-		brokerIDStr := brokerID // Capture loop variable for pointer
-		conn, err := s.getMetricValue(ctx, clusterName, mskMetricConnectionCount, mskConnStatistic, &brokerIDStr)
+// listBrokerIDs returns the broker IDs of a provisioned cluster, formatted for the Broker ID dimension
+func (s *MskScanner) listBrokerIDs(ctx context.Context, arn string) ([]string, error) {
+	var brokerIDs []string
+	nodesPaginator := kafka.NewListNodesPaginator(s.KafkaClient, &kafka.ListNodesInput{ClusterArn: aws.String(arn)})
+	for nodesPaginator.HasMorePages() {
+		nodesOutput, err := nodesPaginator.NextPage(ctx)
 		if err != nil {
-			err := fmt.Errorf("broker %s: %w", brokerID, err)
-			err_msg := fmt.Sprintf("getMaxConnectionCount error for %s", err.Error())
-			err = fmt.Errorf(err_msg)
-			err = fmt.Errorf("broker %s: %w", brokerID, err)
-			fmt.Printf("Warning: %s\n", err.Error())
-			errs = append(errs, err) // Append the error with broker context
-			continue                 // Try next broker
+			return nil, err
 		}
-		if conn != nil {
-			foundData = true
-			if maxConn == nil || *conn > *maxConn {
-				maxConn = conn // Keep track of the highest max found
+		for _, nodeInfo := range nodesOutput.NodeInfoList {
+			if nodeInfo.BrokerNodeInfo != nil && nodeInfo.BrokerNodeInfo.BrokerId != nil {
+				// Format BrokerId (*float64) as integer string for dimension
+				brokerIDs = append(brokerIDs, fmt.Sprintf("%d", int64(*nodeInfo.BrokerNodeInfo.BrokerId)))
 			}
 		}
 	}
+	return brokerIDs, nil
+}
 
-	if !foundData && len(errs) == len(brokerIDs) {
-		// If we had errors for every broker and found no data, return the errors
-		return nil, errs
-	}
+// mskBrokerMetrics are the CloudWatch metrics queried for each broker, in query ID order
+var mskBrokerMetrics = []struct {
+	name string
+	stat cwtypes.Statistic
+}{
+	{mskMetricConnectionCount, mskConnStatistic},
+	{mskMetricCPUSystem, mskCPUStatistic},
+	{mskMetricCPUUser, mskCPUStatistic},
+}
 
-	// Return the highest max found, or nil if no data points were found for any broker
-	// Return collected errors (might be empty if all succeeded)
-	return maxConn, errs
+// mskDatapoint is a daily datapoint of a broker metric
+type mskDatapoint struct {
+	timestamp time.Time
+	value     float64
 }
 
-// getAvgCPUUtilization retrieves the average CPU utilization across all brokers
-func (s *MskScanner) getAvgCPUUtilization(ctx context.Context, clusterName string, brokerIDs []string) (*float64, []error) {
-	var totalCPU float64
-	var cpuCount int
-	var errs []error
-	foundData := false
+// mskActivity is the activity of a cluster aggregated from the daily metrics of its brokers
+type mskActivity struct {
+	maxConnections                *float64 // Highest daily connection count of any broker, nil without data
+	avgCPU                        *float64 // Average of the daily CpuUser + CpuSystem of each broker, nil without data
+	zeroConnectionDays            int      // Days on which no broker had a connection
+	consecutiveZeroConnectionDays int      // Zero connection days up to the latest day with data
+}
 
+// getClusterActivity fetches the daily broker metrics of a cluster and aggregates them
+func (s *MskScanner) getClusterActivity(ctx context.Context, clusterName string, brokerIDs []string) (mskActivity, error) {
 	if len(brokerIDs) == 0 {
-		return nil, []error{fmt.Errorf("no broker IDs provided for cluster %s", clusterName)}
+		return mskActivity{}, fmt.Errorf("no broker IDs provided for cluster %s", clusterName)
+	}
+
+	// Whole days only, so that every period covers a full day
+	endTime := time.Now().UTC().Truncate(24 * time.Hour)
+	startTime := endTime.AddDate(0, 0, -mskCheckPeriodDays)
+
+	series, err := s.getBrokerMetrics(ctx, clusterName, brokerIDs, startTime, endTime)
+	if err != nil {
+		return mskActivity{}, err
 	}
 
+	return aggregateMskMetrics(series), nil
+}
+
+// getBrokerMetrics fetches the daily mskBrokerMetrics of all brokers of a cluster with
+// GetMetricData. An empty broker ID queries the cluster level metrics of a serverless cluster.
+// It returns the datapoints of each broker, indexed like mskBrokerMetrics.
+func (s *MskScanner) getBrokerMetrics(ctx context.Context, clusterName string, brokerIDs []string, startTime, endTime time.Time) ([][][]mskDatapoint, error) {
+	var queries []cloudwatchutil.MetricQuery
 	for _, brokerID := range brokerIDs {
-		brokerIDStr := brokerID // Capture loop variable
-		avgSystem, errSys := s.getMetricValue(ctx, clusterName, mskMetricCPUSystem, mskCPUStatistic, &brokerIDStr)
-		avgUser, errUser := s.getMetricValue(ctx, clusterName, mskMetricCPUUser, mskCPUStatistic, &brokerIDStr)
-
-		if errSys != nil {
-			err := fmt.Errorf("broker %s (CpuSystem): %w", brokerID, errSys)
-			fmt.Printf("Warning: %s\n", err.Error())
-			err = fmt.Errorf("broker %s (CpuSystem): %w", brokerID, errSys)
-			fmt.Printf("Warning: %s\n", err.Error())
-			errs = append(errs, err) // Append the error with broker context
-		}
-		if errUser != nil {
-			err := fmt.Errorf("broker %s (CpuUser): %w", brokerID, errUser)
-			fmt.Printf("Warning: %s\n", err.Error())
-			err = fmt.Errorf("broker %s (CpuUser): %w", brokerID, errUser)
-			fmt.Printf("Warning: %s\n", err.Error())
-			errs = append(errs, err) // Append the error with broker context
+		dimensions := []cwtypes.Dimension{cloudwatchutil.Dimension("Cluster Name", clusterName)}
+		if brokerID != "" {
+			dimensions = append(dimensions, cloudwatchutil.Dimension("Broker ID", brokerID))
 		}
 
-		// Only aggregate if both metrics were successfully retrieved for this broker
-		if avgSystem != nil && avgUser != nil {
-			foundData = true
-			totalCPU += (*avgSystem + *avgUser)
-			cpuCount++
+		for _, metric := range mskBrokerMetrics {
+			queries = append(queries, cloudwatchutil.MetricQuery{
+				Namespace:  mskNamespace,
+				Name:       metric.name,
+				Dimensions: dimensions,
+				Stat:       metric.stat,
+				Period:     24 * time.Hour,
+			})
 		}
-		// If either metric is nil, or if errors occurred (errSys or errUser != nil),
-		// we simply don't update totalCPU or cpuCount for this broker.
-		// Errors were already appended to the errs slice earlier.
 	}
 
-	if !foundData {
-		// If no data was found for any broker (either due to errors or no datapoints)
-		return nil, errs // Return nil value and any errors encountered
+	results, err := cloudwatchutil.MetricData(ctx, s.CWClient, queries, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("CloudWatch API error for broker metrics of cluster %s: %w", clusterName, err)
 	}
 
-	if cpuCount == 0 {
-		// This should ideally not happen if foundData is true, but handle defensively
-		return nil, errs
+	series := make([][][]mskDatapoint, len(brokerIDs))
+	for i := range series {
+		series[i] = make([][]mskDatapoint, len(mskBrokerMetrics))
+		for j := range mskBrokerMetrics {
+			result := results[i*len(mskBrokerMetrics)+j]
+			for k := range result.Values {
+				series[i][j] = append(series[i][j], mskDatapoint{timestamp: result.Timestamps[k], value: result.Values[k]})
+			}
+		}
 	}
 
-	overallAvg := totalCPU / float64(cpuCount)
-	return &overallAvg, errs
+	return series, nil
 }
 
-// getMetricValue is a generic helper to fetch a specific metric value
-// Added brokerID parameter for broker-level metrics
-func (s *MskScanner) getMetricValue(ctx context.Context, clusterName, metricName string, statistic cwtypes.Statistic, brokerID *string) (*float64, error) {
-	now := time.Now()
-	startTime := now.AddDate(0, 0, -mskCheckPeriodDays)
-	endTime := now
-	periodSeconds := int32(mskCheckPeriodDays * 24 * 60 * 60)
-
-	dimensions := []cwtypes.Dimension{
-		{
-			Name:  aws.String("Cluster Name"),
-			Value: aws.String(clusterName),
-		},
-	}
+// aggregateMskMetrics aggregates the daily datapoints of each broker, indexed like
+// mskBrokerMetrics, into the activity of the cluster. A day without connection datapoints
+// is not counted as a zero connection day.
+func aggregateMskMetrics(series [][][]mskDatapoint) mskActivity {
+	var activity mskActivity
 
-	// Add Broker ID dimension if provided
-	if brokerID != nil {
-		dimensions = append(dimensions, cwtypes.Dimension{
-			Name:  aws.String("Broker ID"),
-			Value: brokerID,
-		})
-	}
+	dailyConnections := make(map[int64]float64)
+	var cpuTotal float64
+	var cpuCount int
+	for _, broker := range series {
+		for _, dp := range broker[0] {
+			day := dp.timestamp.Unix()
+			if current, found := dailyConnections[day]; !found || dp.value > current {
+				dailyConnections[day] = dp.value
+			}
+		}
 
-	metricInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String(mskNamespace),
-		MetricName: aws.String(metricName),
-		Dimensions: dimensions, // Use the constructed dimensions slice
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(periodSeconds),
-		Statistics: []cwtypes.Statistic{statistic},
+		// CPU usage is CpuSystem + CpuUser, so only days with both count
+		cpuUser := make(map[int64]float64)
+		for _, dp := range broker[2] {
+			cpuUser[dp.timestamp.Unix()] = dp.value
+		}
+		for _, dp := range broker[1] {
+			if user, found := cpuUser[dp.timestamp.Unix()]; found {
+				cpuTotal += dp.value + user
+				cpuCount++
+			}
+		}
 	}
 
-	resp, err := s.CWClient.GetMetricStatistics(ctx, metricInput)
-	if err != nil {
-		// Construct more informative error message including broker ID if present
-		logDetail := fmt.Sprintf("metric %s for cluster %s", metricName, clusterName)
-		if brokerID != nil {
-			logDetail += fmt.Sprintf(" (broker %s)", *brokerID)
-		}
-		return nil, fmt.Errorf("CloudWatch API error for %s: %w", logDetail, err)
+	if cpuCount > 0 {
+		avgCPU := cpuTotal / float64(cpuCount)
+		activity.avgCPU = &avgCPU
 	}
 
-	if len(resp.Datapoints) == 0 {
-		return nil, nil // No data found
+	days := make([]int64, 0, len(dailyConnections))
+	for day := range dailyConnections {
+		days = append(days, day)
 	}
+	slices.Sort(days)
 
-	dp := resp.Datapoints[0]
-	switch statistic {
-	case cwtypes.StatisticMaximum:
-		if dp.Maximum != nil {
-			return dp.Maximum, nil
+	for _, day := range days {
+		connections := dailyConnections[day]
+		if activity.maxConnections == nil || connections > *activity.maxConnections {
+			activity.maxConnections = &connections
 		}
-	case cwtypes.StatisticAverage:
-		if dp.Average != nil {
-			return dp.Average, nil
-		}
-	case cwtypes.StatisticSum:
-		if dp.Sum != nil {
-			return dp.Sum, nil
+		if connections <= idleConnectionThreshold {
+			activity.zeroConnectionDays++
+			activity.consecutiveZeroConnectionDays++
+		} else {
+			activity.consecutiveZeroConnectionDays = 0
 		}
-	default:
-		return nil, fmt.Errorf("unsupported statistic %s requested", statistic)
 	}
 
-	logDetail := fmt.Sprintf("cluster %s", clusterName)
-	if brokerID != nil {
-		logDetail += fmt.Sprintf(" (broker %s)", *brokerID)
-	}
-	return nil, fmt.Errorf("no %s value found in datapoint for %s", statistic, logDetail)
+	return activity
 }
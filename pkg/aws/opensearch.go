@@ -0,0 +1,184 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	opensearchtypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+const (
+	openSearchCheckPeriodDays = 30
+	openSearchNamespace       = "AWS/ES"
+
+	// openSearchLowCPUThresholdPercent is the average CPU utilization below which a domain
+	// with traffic is reported as underutilized
+	openSearchLowCPUThresholdPercent = 10.0
+
+	// openSearchDescribeBatchSize is the number of domains per DescribeDomains call
+	openSearchDescribeBatchSize = 5
+)
+
+// openSearchAPI is the OpenSearch Service API used by OpenSearchScanner
+type openSearchAPI interface {
+	ListDomainNames(ctx context.Context, params *opensearch.ListDomainNamesInput, optFns ...func(*opensearch.Options)) (*opensearch.ListDomainNamesOutput, error)
+	DescribeDomains(ctx context.Context, params *opensearch.DescribeDomainsInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeDomainsOutput, error)
+}
+
+// OpenSearchScanner contains the AWS clients needed for scanning OpenSearch Service domains
+type OpenSearchScanner struct {
+	OpenSearchClient openSearchAPI
+	CWClient         cloudWatchMetricsAPI
+	Region           string
+	accountID        string // Account of the ClientId metric dimension
+	pricing          *pricing.PricingService
+}
+
+// NewOpenSearchScanner creates a new OpenSearchScanner for a given region
+func NewOpenSearchScanner(cfg aws.Config) *OpenSearchScanner {
+	return &OpenSearchScanner{
+		OpenSearchClient: opensearch.NewFromConfig(cfg),
+		CWClient:         cloudwatch.NewFromConfig(cfg),
+		Region:           cfg.Region,
+		pricing:          pricing.Default(),
+	}
+}
+
+// SetAccountID sets the account of the domains, required by the ClientId dimension of their metrics
+func (s *OpenSearchScanner) SetAccountID(accountID string) {
+	s.accountID = accountID
+}
+
+// GetIdleOpenSearchDomains returns the OpenSearch and Elasticsearch domains without search and
+// indexing traffic over the last 30 days, and those with traffic but a low average CPU utilization.
+// Domains that are being created or deleted are skipped.
+func (s *OpenSearchScanner) GetIdleOpenSearchDomains(ctx context.Context) ([]models.OpenSearchDomainInfo, []error) {
+	if s.accountID == "" {
+		return nil, []error{errors.New("the account ID is required to read OpenSearch metrics, which are keyed by ClientId")}
+	}
+
+	// ListDomainNames returns every domain of the region at once
+	listOutput, err := s.OpenSearchClient.ListDomainNames(ctx, &opensearch.ListDomainNamesInput{})
+	if err != nil {
+		return nil, []error{fmt.Errorf("error listing OpenSearch domains in region %s: %w", s.Region, err)}
+	}
+	var domainNames []string
+	for _, domain := range listOutput.DomainNames {
+		domainNames = append(domainNames, aws.ToString(domain.DomainName))
+	}
+
+	var idle []models.OpenSearchDomainInfo
+	var errs []error
+	for _, batch := range batchStrings(domainNames, openSearchDescribeBatchSize) {
+		output, err := s.OpenSearchClient.DescribeDomains(ctx, &opensearch.DescribeDomainsInput{DomainNames: batch})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error describing OpenSearch domains in region %s: %w", s.Region, err))
+			continue
+		}
+
+		for _, domain := range output.DomainStatusList {
+			if !aws.ToBool(domain.Created) || aws.ToBool(domain.Deleted) {
+				continue
+			}
+
+			info, err := s.checkDomain(ctx, domain)
+			if err != nil {
+				errs = append(errs, err)
+				recordSkip("opensearch", s.Region, aws.ToString(domain.DomainName), "metrics unavailable", err)
+				continue
+			}
+			if info.IsIdle {
+				idle = append(idle, info)
+			}
+		}
+	}
+
+	return idle, errs
+}
+
+// checkDomain reads the configuration and metrics of a domain and decides whether it is idle
+func (s *OpenSearchScanner) checkDomain(ctx context.Context, domain opensearchtypes.DomainStatus) (models.OpenSearchDomainInfo, error) {
+	domainName := aws.ToString(domain.DomainName)
+	info := models.OpenSearchDomainInfo{
+		DomainName:    domainName,
+		ARN:           aws.ToString(domain.ARN),
+		Region:        s.Region,
+		EngineVersion: aws.ToString(domain.EngineVersion),
+	}
+
+	instanceCounts := make(map[string]int)
+	if config := domain.ClusterConfig; config != nil {
+		info.InstanceType = string(config.InstanceType)
+		info.InstanceCount = int(aws.ToInt32(config.InstanceCount))
+		instanceCounts[info.InstanceType] += info.InstanceCount
+		if aws.ToBool(config.DedicatedMasterEnabled) {
+			info.DedicatedMasterType = string(config.DedicatedMasterType)
+			info.DedicatedMasterCount = int(aws.ToInt32(config.DedicatedMasterCount))
+			instanceCounts[info.DedicatedMasterType] += info.DedicatedMasterCount
+		}
+		if aws.ToBool(config.WarmEnabled) {
+			info.WarmType = string(config.WarmType)
+			info.WarmCount = int(aws.ToInt32(config.WarmCount))
+			instanceCounts[info.WarmType] += info.WarmCount
+		}
+	}
+	if ebs := domain.EBSOptions; ebs != nil && aws.ToBool(ebs.EBSEnabled) {
+		info.VolumeType = string(ebs.VolumeType)
+		info.VolumeSizeGB = int(aws.ToInt32(ebs.VolumeSize))
+	}
+
+	var err error
+	if info.SearchRate, err = s.getMetricAverage(ctx, domainName, "SearchRate"); err != nil {
+		return info, fmt.Errorf("error getting SearchRate of OpenSearch domain %s: %w", domainName, err)
+	}
+	if info.IndexingRate, err = s.getMetricAverage(ctx, domainName, "IndexingRate"); err != nil {
+		return info, fmt.Errorf("error getting IndexingRate of OpenSearch domain %s: %w", domainName, err)
+	}
+	if info.AvgCPUUtilization, err = s.getMetricAverage(ctx, domainName, "CPUUtilization"); err != nil {
+		return info, fmt.Errorf("error getting CPUUtilization of OpenSearch domain %s: %w", domainName, err)
+	}
+
+	// A domain without rate datapoints served no requests over the period
+	noTraffic := aws.ToFloat64(info.SearchRate) == 0 && aws.ToFloat64(info.IndexingRate) == 0
+	lowCPU := info.AvgCPUUtilization != nil && *info.AvgCPUUtilization < openSearchLowCPUThresholdPercent
+	switch {
+	case noTraffic:
+		info.IsIdle = true
+		info.Reason = "No Search & Indexing"
+	case lowCPU:
+		info.IsIdle = true
+		info.Reason = "Low CPU Usage"
+	default:
+		return info, nil
+	}
+
+	nodeCost, pricingSource := s.pricing.CalculateOpenSearchDomainMonthlyCostWithSource(instanceCounts, s.Region)
+	storageCost := float64(info.VolumeSizeGB*info.InstanceCount) * pricing.OpenSearchStorageMonthlyPricePerGB
+	info.EstimatedMonthlyCost = nodeCost + storageCost
+	info.PricingSource = pricingSource
+
+	return info, nil
+}
+
+// getMetricAverage returns the average of a domain metric over the check period, or nil without datapoints
+func (s *OpenSearchScanner) getMetricAverage(ctx context.Context, domainName, metricName string) (*float64, error) {
+	return cloudwatchutil.AverageOverWindow(ctx, s.CWClient, cloudwatchutil.MetricQuery{
+		Namespace: openSearchNamespace,
+		Name:      metricName,
+		Dimensions: []cwtypes.Dimension{
+			cloudwatchutil.Dimension("DomainName", domainName),
+			cloudwatchutil.Dimension("ClientId", s.accountID),
+		},
+		Stat:       cwtypes.StatisticAverage,
+		WindowDays: openSearchCheckPeriodDays,
+	})
+}
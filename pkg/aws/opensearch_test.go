@@ -0,0 +1,215 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	opensearchtypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+// openSearchDomain returns a created domain of two m6g.large.search data nodes with 100 GB volumes
+func openSearchDomain(name string) opensearchtypes.DomainStatus {
+	return opensearchtypes.DomainStatus{
+		DomainName:    aws.String(name),
+		ARN:           aws.String("arn:aws:es:us-east-1:123456789012:domain/" + name),
+		EngineVersion: aws.String("OpenSearch_2.11"),
+		Created:       aws.Bool(true),
+		ClusterConfig: &opensearchtypes.ClusterConfig{
+			InstanceType:  opensearchtypes.OpenSearchPartitionInstanceTypeM6gLargeSearch,
+			InstanceCount: aws.Int32(2),
+		},
+		EBSOptions: &opensearchtypes.EBSOptions{
+			EBSEnabled: aws.Bool(true),
+			VolumeType: opensearchtypes.VolumeTypeGp3,
+			VolumeSize: aws.Int32(100),
+		},
+	}
+}
+
+// newTestOpenSearchScanner returns a scanner of the domains answering their metrics from
+// metrics, keyed by domain and metric name. A metric without a value has no datapoints.
+func newTestOpenSearchScanner(t *testing.T, domains []opensearchtypes.DomainStatus, metrics map[string]map[string]float64, metricErrs map[string]error) (*OpenSearchScanner, *[][]string) {
+	t.Helper()
+	var batches [][]string
+	client := &mocks.OpenSearch{
+		ListDomainNamesFunc: func(ctx context.Context, params *opensearch.ListDomainNamesInput) (*opensearch.ListDomainNamesOutput, error) {
+			output := &opensearch.ListDomainNamesOutput{}
+			for _, domain := range domains {
+				output.DomainNames = append(output.DomainNames, opensearchtypes.DomainInfo{DomainName: domain.DomainName})
+			}
+			return output, nil
+		},
+		DescribeDomainsFunc: func(ctx context.Context, params *opensearch.DescribeDomainsInput) (*opensearch.DescribeDomainsOutput, error) {
+			batches = append(batches, params.DomainNames)
+			output := &opensearch.DescribeDomainsOutput{}
+			for _, domain := range domains {
+				for _, name := range params.DomainNames {
+					if aws.ToString(domain.DomainName) == name {
+						output.DomainStatusList = append(output.DomainStatusList, domain)
+					}
+				}
+			}
+			return output, nil
+		},
+	}
+	cw := &mocks.CloudWatch{
+		GetMetricStatisticsFunc: func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			dimensions := make(map[string]string)
+			for _, dimension := range params.Dimensions {
+				dimensions[aws.ToString(dimension.Name)] = aws.ToString(dimension.Value)
+			}
+			if dimensions["ClientId"] != "123456789012" {
+				t.Errorf("ClientId dimension = %q, want the account ID", dimensions["ClientId"])
+			}
+			domain, metric := dimensions["DomainName"], aws.ToString(params.MetricName)
+			if err := metricErrs[domain+"/"+metric]; err != nil {
+				return nil, err
+			}
+			output := &cloudwatch.GetMetricStatisticsOutput{}
+			if value, found := metrics[domain][metric]; found {
+				output.Datapoints = []cwtypes.Datapoint{{Average: aws.Float64(value)}}
+			}
+			return output, nil
+		},
+	}
+
+	scanner := &OpenSearchScanner{OpenSearchClient: client, CWClient: cw, Region: "us-east-1", pricing: newTestPricing()}
+	scanner.SetAccountID("123456789012")
+	return scanner, &batches
+}
+
+func TestGetIdleOpenSearchDomains(t *testing.T) {
+	resetSkips(t)
+	creating := openSearchDomain("creating")
+	creating.Created = aws.Bool(false)
+	deleting := openSearchDomain("deleting")
+	deleting.Deleted = aws.Bool(true)
+	domains := []opensearchtypes.DomainStatus{
+		openSearchDomain("no-traffic"),
+		openSearchDomain("busy"),
+		openSearchDomain("low-cpu"),
+		creating,
+		deleting,
+		openSearchDomain("no-datapoints"),
+	}
+	metrics := map[string]map[string]float64{
+		"no-traffic": {"SearchRate": 0, "IndexingRate": 0, "CPUUtilization": 30},
+		"busy":       {"SearchRate": 50, "IndexingRate": 10, "CPUUtilization": 40},
+		"low-cpu":    {"SearchRate": 1, "CPUUtilization": 5},
+	}
+
+	scanner, batches := newTestOpenSearchScanner(t, domains, metrics, nil)
+	idle, errs := scanner.GetIdleOpenSearchDomains(context.Background())
+
+	if len(errs) != 0 {
+		t.Fatalf("GetIdleOpenSearchDomains() errors = %v", errs)
+	}
+	if len(*batches) != 2 || len((*batches)[0]) != openSearchDescribeBatchSize || len((*batches)[1]) != 1 {
+		t.Errorf("DescribeDomains batches = %v, want 5 and 1 domains", *batches)
+	}
+
+	var got []string
+	for _, domain := range idle {
+		got = append(got, domain.DomainName+": "+domain.Reason)
+	}
+	want := []string{"no-traffic: No Search & Indexing", "low-cpu: Low CPU Usage", "no-datapoints: No Search & Indexing"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetIdleOpenSearchDomains() = %v, want %v", got, want)
+	}
+
+	// Two m6g.large.search nodes at $0.128 per hour and 2 x 100 GB of storage at $0.122 per GB-month
+	domain := idle[0]
+	if wantCost := 2*0.128*730 + 200*0.122; math.Abs(domain.EstimatedMonthlyCost-wantCost) > 1e-9 {
+		t.Errorf("EstimatedMonthlyCost = %v, want %v", domain.EstimatedMonthlyCost, wantCost)
+	}
+	if domain.InstanceType != "m6g.large.search" || domain.InstanceCount != 2 || domain.VolumeSizeGB != 100 || domain.VolumeType != "gp3" {
+		t.Errorf("domain = %+v, want two m6g.large.search nodes with 100 GB gp3 volumes", domain)
+	}
+	if aws.ToFloat64(domain.AvgCPUUtilization) != 30 || idle[2].AvgCPUUtilization != nil {
+		t.Errorf("AvgCPUUtilization = %v and %v, want 30 and none", domain.AvgCPUUtilization, idle[2].AvgCPUUtilization)
+	}
+	if len(SkippedResources()) != 0 {
+		t.Errorf("SkippedResources() = %v, want none", SkippedResources())
+	}
+}
+
+func TestGetIdleOpenSearchDomainsMetricErrors(t *testing.T) {
+	resetSkips(t)
+	throttled := errors.New("Throttling: rate exceeded")
+	domains := []opensearchtypes.DomainStatus{openSearchDomain("search-failed"), openSearchDomain("cpu-failed"), openSearchDomain("no-traffic")}
+	metricErrs := map[string]error{
+		"search-failed/SearchRate":  throttled,
+		"cpu-failed/CPUUtilization": throttled,
+	}
+
+	scanner, _ := newTestOpenSearchScanner(t, domains, nil, metricErrs)
+	idle, errs := scanner.GetIdleOpenSearchDomains(context.Background())
+
+	if len(idle) != 1 || idle[0].DomainName != "no-traffic" {
+		t.Errorf("GetIdleOpenSearchDomains() = %v, want only no-traffic", idle)
+	}
+	if len(errs) != 2 || !errors.Is(errs[0], throttled) || !errors.Is(errs[1], throttled) {
+		t.Fatalf("GetIdleOpenSearchDomains() errors = %v, want the two metric errors", errs)
+	}
+	wantErr := "error getting CPUUtilization of OpenSearch domain cpu-failed: Throttling: rate exceeded"
+	if errs[1].Error() != wantErr {
+		t.Errorf("error = %q, want %q", errs[1], wantErr)
+	}
+	wantSkips := []string{"cpu-failed: metrics unavailable", "search-failed: metrics unavailable"}
+	if got := skipSummary(SkippedResources()); !reflect.DeepEqual(got, wantSkips) {
+		t.Errorf("SkippedResources() = %v, want %v", got, wantSkips)
+	}
+}
+
+func TestGetIdleOpenSearchDomainsListErrors(t *testing.T) {
+	denied := errors.New("AccessDeniedException: not authorized")
+
+	scanner := &OpenSearchScanner{OpenSearchClient: &mocks.OpenSearch{}, CWClient: &mocks.CloudWatch{}, Region: "us-east-1", pricing: newTestPricing()}
+	if idle, errs := scanner.GetIdleOpenSearchDomains(context.Background()); idle != nil || len(errs) != 1 {
+		t.Errorf("GetIdleOpenSearchDomains() without an account ID = %v, %v, want an error", idle, errs)
+	}
+
+	scanner.SetAccountID("123456789012")
+	scanner.OpenSearchClient = &mocks.OpenSearch{
+		ListDomainNamesFunc: func(ctx context.Context, params *opensearch.ListDomainNamesInput) (*opensearch.ListDomainNamesOutput, error) {
+			return nil, denied
+		},
+	}
+	idle, errs := scanner.GetIdleOpenSearchDomains(context.Background())
+	if idle != nil || len(errs) != 1 || !errors.Is(errs[0], denied) {
+		t.Errorf("GetIdleOpenSearchDomains() = %v, %v, want the list error", idle, errs)
+	}
+
+	calls := 0
+	scanner.OpenSearchClient = &mocks.OpenSearch{
+		ListDomainNamesFunc: func(ctx context.Context, params *opensearch.ListDomainNamesInput) (*opensearch.ListDomainNamesOutput, error) {
+			output := &opensearch.ListDomainNamesOutput{}
+			for i := 0; i < 7; i++ {
+				output.DomainNames = append(output.DomainNames, opensearchtypes.DomainInfo{DomainName: aws.String(fmt.Sprintf("domain-%d", i))})
+			}
+			return output, nil
+		},
+		DescribeDomainsFunc: func(ctx context.Context, params *opensearch.DescribeDomainsInput) (*opensearch.DescribeDomainsOutput, error) {
+			calls++
+			if calls == 1 {
+				return nil, denied
+			}
+			return &opensearch.DescribeDomainsOutput{DomainStatusList: []opensearchtypes.DomainStatus{openSearchDomain("domain-5")}}, nil
+		},
+	}
+	idle, errs = scanner.GetIdleOpenSearchDomains(context.Background())
+	// A failed batch does not stop the next one
+	if len(idle) != 1 || idle[0].DomainName != "domain-5" || len(errs) != 1 || !errors.Is(errs[0], denied) {
+		t.Errorf("GetIdleOpenSearchDomains() = %v, %v, want domain-5 and the error of the first batch", idle, errs)
+	}
+}
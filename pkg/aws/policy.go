@@ -0,0 +1,97 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appconfig"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/younsl/idled/internal/policy"
+)
+
+// policyClientID identifies idled to AppConfig when fetching the deployed configuration
+const policyClientID = "idled"
+
+// ssmParameterAPI is the SSM API used by PolicyClient
+type ssmParameterAPI interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// appConfigAPI is the AppConfig API used by PolicyClient
+type appConfigAPI interface {
+	GetConfiguration(ctx context.Context, params *appconfig.GetConfigurationInput, optFns ...func(*appconfig.Options)) (*appconfig.GetConfigurationOutput, error)
+}
+
+// PolicyClient fetches centrally managed policy documents from SSM Parameter Store or AppConfig
+type PolicyClient struct {
+	ssmClient       ssmParameterAPI
+	appConfigClient appConfigAPI
+	region          string
+}
+
+// NewPolicyClient creates a new policy client for the specified region
+func NewPolicyClient(ctx context.Context, region string) (*PolicyClient, error) {
+	cfg, err := LoadConfig(ctx, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
+	}
+	return &PolicyClient{
+		ssmClient:       ssm.NewFromConfig(cfg),
+		appConfigClient: appconfig.NewFromConfig(cfg),
+		region:          region,
+	}, nil
+}
+
+// GetSSMPolicy fetches and validates a policy document stored in an SSM parameter
+func (c *PolicyClient) GetSSMPolicy(ctx context.Context, parameterName string) (*policy.Policy, policy.Source, error) {
+	source := policy.Source{Kind: "SSM", Location: parameterName}
+
+	output, err := c.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(parameterName),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, source, fmt.Errorf("failed to get SSM parameter %s in region %s: %w", parameterName, c.region, err)
+	}
+	if output.Parameter == nil {
+		return nil, source, fmt.Errorf("SSM parameter %s returned no value", parameterName)
+	}
+	source.Version = strconv.FormatInt(output.Parameter.Version, 10)
+
+	p, err := policy.Parse([]byte(aws.ToString(output.Parameter.Value)))
+	if err != nil {
+		return nil, source, err
+	}
+	return p, source, nil
+}
+
+// GetAppConfigPolicy fetches and validates the deployed policy document of an AppConfig
+// configuration profile identified as "application/environment/profile"
+func (c *PolicyClient) GetAppConfigPolicy(ctx context.Context, identifier string) (*policy.Policy, policy.Source, error) {
+	source := policy.Source{Kind: "AppConfig", Location: identifier}
+
+	application, environment, profile, err := policy.ParseAppConfigIdentifier(identifier)
+	if err != nil {
+		return nil, source, err
+	}
+
+	// GetConfiguration returns the configuration currently deployed to the environment
+	output, err := c.appConfigClient.GetConfiguration(ctx, &appconfig.GetConfigurationInput{
+		Application:   aws.String(application),
+		Environment:   aws.String(environment),
+		Configuration: aws.String(profile),
+		ClientId:      aws.String(policyClientID),
+	})
+	if err != nil {
+		return nil, source, fmt.Errorf("failed to get AppConfig configuration %s in region %s: %w", identifier, c.region, err)
+	}
+	source.Version = aws.ToString(output.ConfigurationVersion)
+
+	p, err := policy.Parse(output.Content)
+	if err != nil {
+		return nil, source, err
+	}
+	return p, source, nil
+}
@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appconfig"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+const testPolicyDocument = `{"version": "2026-10", "minIdleDays": 30, "ignore": ["us-east-1/i-0123"]}`
+
+// ssmParameter answers GetParameter with a parameter holding value, or with err
+func ssmParameter(value string, err error) *mocks.SSM {
+	return &mocks.SSM{
+		GetParameterFunc: func(ctx context.Context, params *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+			if err != nil {
+				return nil, err
+			}
+			if !aws.ToBool(params.WithDecryption) {
+				return nil, errors.New("SecureString parameters must be read with decryption")
+			}
+			return &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{
+				Name:    params.Name,
+				Value:   aws.String(value),
+				Version: 3,
+			}}, nil
+		},
+	}
+}
+
+func TestGetSSMPolicy(t *testing.T) {
+	denied := errors.New("AccessDeniedException: not authorized to perform ssm:GetParameter")
+
+	tests := []struct {
+		name        string
+		client      *mocks.SSM
+		wantIdle    int
+		wantVersion string
+		wantErr     string
+	}{
+		{name: "fetched", client: ssmParameter(testPolicyDocument, nil), wantIdle: 30, wantVersion: "3"},
+		{name: "malformed", client: ssmParameter(`{"minIdleDays": 30,}`, nil), wantVersion: "3", wantErr: "invalid policy document"},
+		{name: "unknown field", client: ssmParameter(`{"minIdleDay": 30}`, nil), wantVersion: "3", wantErr: "invalid policy document"},
+		{name: "fetch failed", client: ssmParameter("", denied), wantErr: "failed to get SSM parameter /org/idled/policy in region us-east-1"},
+		{name: "no value", client: &mocks.SSM{}, wantErr: "returned no value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &PolicyClient{ssmClient: tt.client, region: "us-east-1"}
+			p, source, err := client.GetSSMPolicy(context.Background(), "/org/idled/policy")
+
+			if source.Kind != "SSM" || source.Location != "/org/idled/policy" || source.Version != tt.wantVersion {
+				t.Errorf("source = %+v, want SSM /org/idled/policy version %q", source, tt.wantVersion)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("GetSSMPolicy() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || p.MinIdleDays == nil || *p.MinIdleDays != tt.wantIdle || p.Version != "2026-10" {
+				t.Errorf("GetSSMPolicy() = %+v, %v", p, err)
+			}
+		})
+	}
+}
+
+func TestGetAppConfigPolicy(t *testing.T) {
+	var requested *appconfig.GetConfigurationInput
+	configuration := func(content string) *mocks.AppConfig {
+		return &mocks.AppConfig{
+			GetConfigurationFunc: func(ctx context.Context, params *appconfig.GetConfigurationInput) (*appconfig.GetConfigurationOutput, error) {
+				requested = params
+				return &appconfig.GetConfigurationOutput{Content: []byte(content), ConfigurationVersion: aws.String("7")}, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		identifier string
+		client     *mocks.AppConfig
+		wantErr    string
+	}{
+		{name: "fetched", identifier: "idled/prod/policy", client: configuration(testPolicyDocument)},
+		{name: "malformed", identifier: "idled/prod/policy", client: configuration(`minIdleDays: 30`), wantErr: "invalid policy document"},
+		{name: "invalid identifier", identifier: "idled/prod", client: configuration(testPolicyDocument), wantErr: "expected application/environment/profile"},
+		{name: "fetch failed", identifier: "idled/prod/policy", client: &mocks.AppConfig{
+			GetConfigurationFunc: func(ctx context.Context, params *appconfig.GetConfigurationInput) (*appconfig.GetConfigurationOutput, error) {
+				return nil, errors.New("ResourceNotFoundException")
+			},
+		}, wantErr: "failed to get AppConfig configuration idled/prod/policy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			client := &PolicyClient{appConfigClient: tt.client, region: "us-east-1"}
+			p, source, err := client.GetAppConfigPolicy(context.Background(), tt.identifier)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("GetAppConfigPolicy() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || p.MinIdleDays == nil || *p.MinIdleDays != 30 {
+				t.Fatalf("GetAppConfigPolicy() = %+v, %v", p, err)
+			}
+			if source.String() != "AppConfig idled/prod/policy (version 7)" {
+				t.Errorf("source = %s", source)
+			}
+			if aws.ToString(requested.Application) != "idled" || aws.ToString(requested.Environment) != "prod" ||
+				aws.ToString(requested.Configuration) != "policy" || aws.ToString(requested.ClientId) != policyClientID {
+				t.Errorf("GetConfiguration() input = %+v", requested)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// Sources of the default region, in order of precedence
+const (
+	RegionSourceEnv        = "AWS_REGION"
+	RegionSourceDefaultEnv = "AWS_DEFAULT_REGION"
+	RegionSourceProfile    = "profile"
+	RegionSourceFallback   = "fallback"
+)
+
+// ResolveDefaultRegion returns the region to scan when none is requested and the source it
+// came from: the AWS_REGION or AWS_DEFAULT_REGION environment variable, the region of the
+// selected shared config profile, or us-east-1 as the last resort
+func ResolveDefaultRegion(ctx context.Context) (string, string) {
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region, RegionSourceEnv
+	}
+	// The SDK only reads AWS_REGION, while the AWS CLI also honors AWS_DEFAULT_REGION
+	if region := os.Getenv("AWS_DEFAULT_REGION"); region != "" {
+		return region, RegionSourceDefaultEnv
+	}
+
+	// An unreadable shared config is reported by the first client, not here
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err == nil && cfg.Region != "" {
+		return cfg.Region, RegionSourceProfile
+	}
+
+	return utils.GetDefaultRegion(), RegionSourceFallback
+}
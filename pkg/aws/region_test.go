@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveDefaultRegion(t *testing.T) {
+	sharedConfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(sharedConfig, []byte("[default]\nregion = eu-west-1\n\n[profile seoul]\nregion = ap-northeast-2\n\n[profile bare]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		awsRegion        string
+		awsDefaultRegion string
+		profile          string
+		configFile       string
+		wantRegion       string
+		wantSource       string
+	}{
+		{
+			name:      "AWS_REGION first",
+			awsRegion: "us-west-2", awsDefaultRegion: "eu-central-1", configFile: sharedConfig,
+			wantRegion: "us-west-2", wantSource: RegionSourceEnv,
+		},
+		{
+			name:             "AWS_DEFAULT_REGION before the profile",
+			awsDefaultRegion: "eu-central-1", configFile: sharedConfig,
+			wantRegion: "eu-central-1", wantSource: RegionSourceDefaultEnv,
+		},
+		{
+			name:       "default profile",
+			configFile: sharedConfig,
+			wantRegion: "eu-west-1", wantSource: RegionSourceProfile,
+		},
+		{
+			name:    "selected profile",
+			profile: "seoul", configFile: sharedConfig,
+			wantRegion: "ap-northeast-2", wantSource: RegionSourceProfile,
+		},
+		{
+			name:    "profile without a region",
+			profile: "bare", configFile: sharedConfig,
+			wantRegion: "us-east-1", wantSource: RegionSourceFallback,
+		},
+		{
+			name:       "no shared config",
+			configFile: filepath.Join(t.TempDir(), "missing"),
+			wantRegion: "us-east-1", wantSource: RegionSourceFallback,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", tt.awsRegion)
+			t.Setenv("AWS_DEFAULT_REGION", tt.awsDefaultRegion)
+			t.Setenv("AWS_PROFILE", tt.profile)
+			t.Setenv("AWS_CONFIG_FILE", tt.configFile)
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+			region, source := ResolveDefaultRegion(context.Background())
+			if region != tt.wantRegion || source != tt.wantSource {
+				t.Errorf("ResolveDefaultRegion() = %s, %s, want %s, %s", region, source, tt.wantRegion, tt.wantSource)
+			}
+		})
+	}
+}
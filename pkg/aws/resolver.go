@@ -0,0 +1,117 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/route53resolver"
+	resolvertypes "github.com/aws/aws-sdk-go-v2/service/route53resolver/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+const (
+	resolverCheckPeriodDays = 30
+	resolverNamespace       = "AWS/Route53Resolver"
+)
+
+var (
+	// resolverInboundMetrics count the DNS queries received by an inbound endpoint
+	resolverInboundMetrics = []string{"InboundQueryVolume"}
+
+	// resolverOutboundMetrics count the DNS queries forwarded by an outbound endpoint, including
+	// those from VPCs of other accounts the endpoint is shared with through its rules
+	resolverOutboundMetrics = []string{"OutboundQueryAggregateVolume"}
+)
+
+// resolverAPI is the Route 53 Resolver API used by ResolverScanner
+type resolverAPI interface {
+	ListResolverEndpoints(ctx context.Context, params *route53resolver.ListResolverEndpointsInput, optFns ...func(*route53resolver.Options)) (*route53resolver.ListResolverEndpointsOutput, error)
+}
+
+// ResolverScanner contains the AWS clients needed for scanning Route 53 Resolver endpoints
+type ResolverScanner struct {
+	ResolverClient resolverAPI
+	CWClient       cloudWatchMetricsAPI
+	Region         string
+}
+
+// NewResolverScanner creates a new ResolverScanner for a given region
+func NewResolverScanner(cfg aws.Config) *ResolverScanner {
+	return &ResolverScanner{
+		ResolverClient: route53resolver.NewFromConfig(cfg),
+		CWClient:       cloudwatch.NewFromConfig(cfg),
+		Region:         cfg.Region,
+	}
+}
+
+// GetIdleResolverEndpoints returns the inbound and outbound Resolver endpoints that handled no
+// DNS queries over the last 30 days. Endpoints that are being created or deleted are skipped.
+func (s *ResolverScanner) GetIdleResolverEndpoints(ctx context.Context) ([]models.ResolverEndpointInfo, []error) {
+	var idle []models.ResolverEndpointInfo
+	var errs []error
+
+	paginator := route53resolver.NewListResolverEndpointsPaginator(s.ResolverClient, &route53resolver.ListResolverEndpointsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing Route 53 Resolver endpoints in region %s: %w", s.Region, err))
+			break
+		}
+
+		for _, endpoint := range page.ResolverEndpoints {
+			if endpoint.Status == resolvertypes.ResolverEndpointStatusCreating || endpoint.Status == resolvertypes.ResolverEndpointStatusDeleting {
+				continue
+			}
+
+			info, err := s.checkEndpoint(ctx, endpoint)
+			if err != nil {
+				errs = append(errs, err)
+				recordSkip("resolver", s.Region, aws.ToString(endpoint.Id), "metrics unavailable", err)
+				continue
+			}
+			if info != nil {
+				idle = append(idle, *info)
+			}
+		}
+	}
+
+	return idle, errs
+}
+
+// checkEndpoint returns the endpoint info if the endpoint handled no queries over the check period, or nil otherwise
+func (s *ResolverScanner) checkEndpoint(ctx context.Context, endpoint resolvertypes.ResolverEndpoint) (*models.ResolverEndpointInfo, error) {
+	endpointID := aws.ToString(endpoint.Id)
+
+	metrics := resolverInboundMetrics
+	if endpoint.Direction == resolvertypes.ResolverEndpointDirectionOutbound {
+		metrics = resolverOutboundMetrics
+	}
+	queries, err := getMetricsSum(ctx, s.CWClient, resolverNamespace, metrics, resolverCheckPeriodDays, cloudwatchutil.Dimension("EndpointId", endpointID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting the query volume of Resolver endpoint %s: %w", endpointID, err)
+	}
+	if queries != 0 {
+		return nil, nil
+	}
+
+	ipAddressCount := int(aws.ToInt32(endpoint.IpAddressCount))
+	return &models.ResolverEndpointInfo{
+		EndpointID:           endpointID,
+		ARN:                  aws.ToString(endpoint.Arn),
+		Name:                 aws.ToString(endpoint.Name),
+		Region:               s.Region,
+		Direction:            string(endpoint.Direction),
+		VPCID:                aws.ToString(endpoint.HostVPCId),
+		IPAddressCount:       ipAddressCount,
+		Status:               string(endpoint.Status),
+		QueryVolume:          queries,
+		IdleReason:           fmt.Sprintf("No DNS queries (%dd)", resolverCheckPeriodDays),
+		EstimatedMonthlyCost: float64(ipAddressCount) * pricing.Route53ResolverEndpointHourlyPricePerENI * utils.GetMonthlyHours(),
+	}, nil
+}
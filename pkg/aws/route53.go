@@ -0,0 +1,318 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/progress"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+// route53DefaultRecordCount is the number of record sets of a new hosted zone (NS and SOA)
+const route53DefaultRecordCount = 2
+
+// route53API is the Route 53 API used by Route53Client
+type route53API interface {
+	route53.ListHealthChecksAPIClient
+	route53.ListHostedZonesAPIClient
+	route53.ListResourceRecordSetsAPIClient
+}
+
+// Route53Client struct for Route 53 client
+type Route53Client struct {
+	client          route53API
+	checkDelegation bool // Whether public zones are checked with DNS lookups
+	lookupNS        func(ctx context.Context, name string) ([]*net.NS, error)
+	progress        progress.Func // Receives the listing and analysis progress, nil for none
+}
+
+// NewRoute53Client creates a new Route53Client
+func NewRoute53Client(cfg aws.Config) *Route53Client {
+	return &Route53Client{
+		client:   route53.NewFromConfig(cfg),
+		lookupNS: net.DefaultResolver.LookupNS,
+	}
+}
+
+// SetCheckDelegation enables the delegation check of public hosted zones, which looks up
+// the NS records of each public domain and so requires outbound DNS
+func (c *Route53Client) SetCheckDelegation(enabled bool) {
+	c.checkDelegation = enabled
+}
+
+// SetProgress sets the function receiving the listing and analysis progress
+func (c *Route53Client) SetProgress(f progress.Func) {
+	c.progress = f
+}
+
+// listHostedZones returns the hosted zones managed by the account. Zones created by another
+// service (e.g., AWS Cloud Map) are skipped, since they are deleted with that service.
+func (c *Route53Client) listHostedZones(ctx context.Context) ([]r53types.HostedZone, error) {
+	var zones []r53types.HostedZone
+	paginator := route53.NewListHostedZonesPaginator(c.client, &route53.ListHostedZonesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return zones, fmt.Errorf("error listing hosted zones: %w", err)
+		}
+		for _, zone := range page.HostedZones {
+			if zone.LinkedService == nil {
+				zones = append(zones, zone)
+			}
+		}
+	}
+	return zones, nil
+}
+
+// GetIdleHostedZones returns the private hosted zones without records beyond the default
+// NS and SOA records and, if the delegation check is enabled, the public hosted zones whose
+// domain is not delegated to the zone's name servers
+func (c *Route53Client) GetIdleHostedZones(ctx context.Context) ([]models.HostedZoneInfo, error) {
+	c.progress.Report(0, 0, "Listing Route 53 hosted zones")
+	zones, err := c.listHostedZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var idleZones []models.HostedZoneInfo
+	var errs []error
+	for i, zone := range zones {
+		c.progress.Report(i, len(zones), "Checking Route 53 hosted zones")
+
+		info := models.HostedZoneInfo{
+			ID:                   strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/"),
+			Name:                 strings.TrimSuffix(aws.ToString(zone.Name), "."),
+			RecordCount:          aws.ToInt64(zone.ResourceRecordSetCount),
+			EstimatedMonthlyCost: pricing.Route53HostedZoneMonthlyPrice,
+		}
+		if zone.Config != nil {
+			info.Private = zone.Config.PrivateZone
+		}
+
+		if info.Private {
+			if info.RecordCount <= route53DefaultRecordCount {
+				info.IdleReason = "No records"
+				idleZones = append(idleZones, info)
+			}
+			continue
+		}
+
+		if !c.checkDelegation {
+			continue
+		}
+		reason, err := c.checkZoneDelegation(ctx, zone, &info)
+		if err != nil {
+			errs = append(errs, err)
+			recordSkip("route53", "global", info.Name, "delegation check failed", err)
+			continue
+		}
+		if reason != "" {
+			info.IdleReason = reason
+			idleZones = append(idleZones, info)
+		}
+	}
+	c.progress.Report(len(zones), len(zones), "")
+
+	return idleZones, errors.Join(errs...)
+}
+
+// checkZoneDelegation compares the name servers of a public zone with a DNS lookup of its
+// domain and returns why the zone is not used, or "" if the domain is delegated to it
+func (c *Route53Client) checkZoneDelegation(ctx context.Context, zone r53types.HostedZone, info *models.HostedZoneInfo) (string, error) {
+	// The apex NS record is listed first when the listing starts at the zone name
+	output, err := c.client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    zone.Id,
+		StartRecordName: zone.Name,
+		StartRecordType: r53types.RRTypeNs,
+		MaxItems:        aws.Int32(1),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error listing NS records of hosted zone %s: %w", info.Name, err)
+	}
+	for _, recordSet := range output.ResourceRecordSets {
+		if recordSet.Type != r53types.RRTypeNs || aws.ToString(recordSet.Name) != aws.ToString(zone.Name) {
+			continue
+		}
+		for _, record := range recordSet.ResourceRecords {
+			info.NameServers = append(info.NameServers, normalizeDNSName(aws.ToString(record.Value)))
+		}
+	}
+
+	resolved, err := c.lookupNS(ctx, info.Name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return "Domain does not resolve", nil
+		}
+		return "", fmt.Errorf("error looking up NS records of %s: %w", info.Name, err)
+	}
+	for _, ns := range resolved {
+		info.ResolvedNameServers = append(info.ResolvedNameServers, normalizeDNSName(ns.Host))
+	}
+	sort.Strings(info.ResolvedNameServers)
+
+	for _, resolvedNS := range info.ResolvedNameServers {
+		for _, zoneNS := range info.NameServers {
+			if resolvedNS == zoneNS {
+				return "", nil
+			}
+		}
+	}
+	return "Delegated to other name servers", nil
+}
+
+// normalizeDNSName lowercases a domain name and removes its trailing dot
+func normalizeDNSName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// GetUnreferencedHealthChecks returns the health checks that no record set and no calculated
+// health check refers to. Health checks used only by CloudWatch alarms are reported as well.
+func (c *Route53Client) GetUnreferencedHealthChecks(ctx context.Context) ([]models.HealthCheckInfo, error) {
+	c.progress.Report(0, 0, "Listing Route 53 health checks")
+
+	var healthChecks []r53types.HealthCheck
+	paginator := route53.NewListHealthChecksPaginator(c.client, &route53.ListHealthChecksInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing health checks: %w", err)
+		}
+		for _, healthCheck := range page.HealthChecks {
+			// Health checks created by another service are deleted with that service
+			if healthCheck.LinkedService == nil {
+				healthChecks = append(healthChecks, healthCheck)
+			}
+		}
+	}
+	if len(healthChecks) == 0 {
+		return nil, nil
+	}
+
+	referenced, err := c.referencedHealthChecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, healthCheck := range healthChecks {
+		if healthCheck.HealthCheckConfig == nil {
+			continue
+		}
+		for _, child := range healthCheck.HealthCheckConfig.ChildHealthChecks {
+			referenced[child] = true
+		}
+	}
+
+	var unreferenced []models.HealthCheckInfo
+	for _, healthCheck := range healthChecks {
+		id := aws.ToString(healthCheck.Id)
+		if referenced[id] {
+			continue
+		}
+
+		info := models.HealthCheckInfo{
+			ID:         id,
+			Target:     "-",
+			IdleReason: "Not referenced by any record set",
+		}
+		if config := healthCheck.HealthCheckConfig; config != nil {
+			info.Type = string(config.Type)
+			info.Target = healthCheckTarget(config)
+			info.Disabled = aws.ToBool(config.Disabled)
+			info.EstimatedMonthlyCost = healthCheckMonthlyCost(config)
+		}
+		unreferenced = append(unreferenced, info)
+	}
+
+	return unreferenced, nil
+}
+
+// referencedHealthChecks returns the IDs of the health checks used by the record sets of all
+// hosted zones, including zones created by other services
+func (c *Route53Client) referencedHealthChecks(ctx context.Context) (map[string]bool, error) {
+	var zones []r53types.HostedZone
+	zonePaginator := route53.NewListHostedZonesPaginator(c.client, &route53.ListHostedZonesInput{})
+	for zonePaginator.HasMorePages() {
+		page, err := zonePaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing hosted zones: %w", err)
+		}
+		zones = append(zones, page.HostedZones...)
+	}
+
+	referenced := make(map[string]bool)
+	for i, zone := range zones {
+		c.progress.Report(i, len(zones), "Checking Route 53 health check references")
+
+		paginator := route53.NewListResourceRecordSetsPaginator(c.client, &route53.ListResourceRecordSetsInput{
+			HostedZoneId: zone.Id,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error listing record sets of hosted zone %s: %w", aws.ToString(zone.Name), err)
+			}
+			for _, recordSet := range page.ResourceRecordSets {
+				if recordSet.HealthCheckId != nil {
+					referenced[aws.ToString(recordSet.HealthCheckId)] = true
+				}
+			}
+		}
+	}
+	c.progress.Report(len(zones), len(zones), "")
+
+	return referenced, nil
+}
+
+// healthCheckTarget returns the endpoint checked by a health check, or "-" if it checks
+// other health checks, a CloudWatch alarm, or a routing control
+func healthCheckTarget(config *r53types.HealthCheckConfig) string {
+	host := strings.TrimSuffix(aws.ToString(config.FullyQualifiedDomainName), ".")
+	if host == "" {
+		host = aws.ToString(config.IPAddress)
+	}
+	if host == "" {
+		return "-"
+	}
+	if config.Port != nil {
+		return fmt.Sprintf("%s:%d", host, aws.ToInt32(config.Port))
+	}
+	return host
+}
+
+// healthCheckMonthlyCost estimates the monthly charge of a health check. Endpoints are
+// treated as AWS endpoints only if their domain name is under amazonaws.com, so the cost of
+// health checks on AWS resources addressed by IP or a custom domain may be overstated.
+func healthCheckMonthlyCost(config *r53types.HealthCheckConfig) float64 {
+	switch config.Type {
+	case r53types.HealthCheckTypeCalculated, r53types.HealthCheckTypeCloudwatchMetric, r53types.HealthCheckTypeRecoveryControl:
+		return pricing.Route53HealthCheckAWSMonthlyPrice
+	}
+
+	features := 0
+	switch config.Type {
+	case r53types.HealthCheckTypeHttps, r53types.HealthCheckTypeHttpStrMatch:
+		features++
+	case r53types.HealthCheckTypeHttpsStrMatch:
+		features += 2 // HTTPS and string matching
+	}
+	if aws.ToInt32(config.RequestInterval) == 10 {
+		features++
+	}
+	if aws.ToBool(config.MeasureLatency) {
+		features++
+	}
+
+	if strings.HasSuffix(normalizeDNSName(aws.ToString(config.FullyQualifiedDomainName)), ".amazonaws.com") {
+		return pricing.Route53HealthCheckAWSMonthlyPrice + float64(features)*pricing.Route53HealthCheckAWSFeatureMonthlyPrice
+	}
+	return pricing.Route53HealthCheckNonAWSMonthlyPrice + float64(features)*pricing.Route53HealthCheckNonAWSFeatureMonthlyPrice
+}
@@ -2,9 +2,11 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,30 +15,51 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/progress"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
 	"github.com/younsl/idled/pkg/utils"
 )
 
+// s3API is the S3 API used by S3Client
+type s3API interface {
+	DeleteBucket(ctx context.Context, params *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
+	GetBucketLifecycleConfiguration(ctx context.Context, params *s3.GetBucketLifecycleConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error)
+	GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	GetBucketNotificationConfiguration(ctx context.Context, params *s3.GetBucketNotificationConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error)
+	GetBucketPolicy(ctx context.Context, params *s3.GetBucketPolicyInput, optFns ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error)
+	GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+	GetBucketVersioning(ctx context.Context, params *s3.GetBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error)
+	GetBucketWebsite(ctx context.Context, params *s3.GetBucketWebsiteInput, optFns ...func(*s3.Options)) (*s3.GetBucketWebsiteOutput, error)
+	GetObjectLockConfiguration(ctx context.Context, params *s3.GetObjectLockConfigurationInput, optFns ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error)
+	HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+	ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	ListMultipartUploads(ctx context.Context, params *s3.ListMultipartUploadsInput, optFns ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error)
+}
+
+// s3MetricsAPI is the CloudWatch API used by S3Client
+type s3MetricsAPI interface {
+	cloudWatchMetricsAPI
+	cloudwatch.ListMetricsAPIClient
+}
+
 // S3Client struct for S3 client
 type S3Client struct {
-	client        *s3.Client
-	cwClient      *cloudwatch.Client
+	client        s3API
+	cwClient      s3MetricsAPI
 	region        string
 	idleThreshold int // in days
+	tagFilters    map[string]string
+	nameFilter    *utils.NameFilter
+	progress      progress.Func // Receives the checked bucket count, nil for none
+	pricing       *pricing.PricingService
 }
 
 // NewS3Client creates a new S3Client
-func NewS3Client(region string) (*S3Client, error) {
-	// Use LoadDefaultConfig with explicit options
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithRegion(region),
-		config.WithRetryMode(aws.RetryModeStandard),
-		config.WithEC2IMDSClientEnableState(imds.ClientEnabled),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error loading AWS config: %w", err)
-	}
-
+func NewS3Client(cfg aws.Config) *S3Client {
 	// Initialize S3 client with explicit config
 	s3Client := s3.NewFromConfig(cfg, func(o *s3.Options) {
 		o.UsePathStyle = true // Use path-style addressing which is more reliable
@@ -48,9 +71,47 @@ func NewS3Client(region string) (*S3Client, error) {
 	return &S3Client{
 		client:        s3Client,
 		cwClient:      cwClient,
-		region:        region,
+		region:        cfg.Region,
 		idleThreshold: 30, // Default: consider buckets idle after 30 days of inactivity
-	}, nil
+		pricing:       pricing.Default(),
+	}
+}
+
+// NewBucketClient creates an S3 client for the objects of a bucket, in the region of the
+// bucket. The region is looked up from the given region, which is kept if the bucket location
+// cannot be read.
+func NewBucketClient(ctx context.Context, region, bucket string) (*s3.Client, error) {
+	client, err := newBucketClient(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return client, nil
+	}
+	// An empty location constraint is us-east-1
+	bucketRegion := "us-east-1"
+	if location.LocationConstraint != "" {
+		bucketRegion = string(location.LocationConstraint)
+	}
+	if bucketRegion == region {
+		return client, nil
+	}
+	return newBucketClient(ctx, bucketRegion)
+}
+
+// newBucketClient creates an S3 client in a region with the same options as the S3 scanner
+func newBucketClient(ctx context.Context, region string) (*s3.Client, error) {
+	cfg, err := LoadConfig(ctx, region,
+		config.WithEC2IMDSClientEnableState(imds.ClientEnabled),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS config: %w", err)
+	}
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = true
+	}), nil
 }
 
 // SetIdleThreshold sets the threshold in days for considering a bucket as idle
@@ -58,10 +119,39 @@ func (c *S3Client) SetIdleThreshold(days int) {
 	c.idleThreshold = days
 }
 
+// SetTagFilters limits results to buckets carrying all of the given tags
+func (c *S3Client) SetTagFilters(tags map[string]string) {
+	c.tagFilters = tags
+}
+
+// SetNameFilter limits results to buckets whose names pass the --include and --exclude patterns
+func (c *S3Client) SetNameFilter(filter *utils.NameFilter) {
+	c.nameFilter = filter
+}
+
+// SetProgress sets the function receiving the bucket lookup and analysis progress
+func (c *S3Client) SetProgress(f progress.Func) {
+	c.progress = f
+}
+
+// SetPricingService sets the pricing service used to estimate bucket storage costs
+func (c *S3Client) SetPricingService(service *pricing.PricingService) {
+	c.pricing = service
+}
+
+// DeleteBucket deletes an S3 bucket. The bucket must be empty.
+func (c *S3Client) DeleteBucket(ctx context.Context, bucketName string) error {
+	_, err := c.client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String(bucketName)})
+	if err != nil {
+		return fmt.Errorf("failed to delete bucket %s: %w", bucketName, err)
+	}
+	return nil
+}
+
 // GetIdleBuckets returns a list of S3 buckets with idle detection metrics
-func (c *S3Client) GetIdleBuckets() ([]models.BucketInfo, error) {
+func (c *S3Client) GetIdleBuckets(ctx context.Context) ([]models.BucketInfo, error) {
 	// List all buckets
-	result, err := c.client.ListBuckets(context.TODO(), &s3.ListBucketsInput{})
+	result, err := c.client.ListBuckets(ctx, &s3.ListBucketsInput{})
 	if err != nil {
 		return nil, fmt.Errorf("error listing S3 buckets: %w", err)
 	}
@@ -69,12 +159,25 @@ func (c *S3Client) GetIdleBuckets() ([]models.BucketInfo, error) {
 	var bucketInfos []models.BucketInfo
 	var regionBuckets []string // Store bucket names instead of bucket objects
 
-	// First filter buckets by region (this is faster)
+	// Drop buckets excluded by name before any per-bucket call. Bucket names are global, so
+	// each bucket is counted once across the regions scanned.
+	buckets := make([]s3Types.Bucket, 0, len(result.Buckets))
 	for _, bucket := range result.Buckets {
+		if !c.nameFilter.Excludes(aws.ToString(bucket.Name), aws.ToString(bucket.Name)) {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	// First filter buckets by region (this is faster). ListBuckets returns the buckets of every
+	// region, so the region lookups are reported as progress.
+	c.progress.Report(0, len(buckets), "Locating S3 buckets in "+c.region)
+	for i, bucket := range buckets {
+		c.progress.Report(i+1, len(buckets), "")
 		// Skip buckets from other regions
-		location, err := c.getBucketRegion(*bucket.Name)
+		location, err := c.getBucketRegion(ctx, *bucket.Name)
 		if err != nil {
-			// Skip buckets we can't access
+			// The region of the bucket is unknown, so every region scanned records the same skip
+			recordSkip("s3", "unknown", *bucket.Name, "bucket location unavailable", err)
 			continue
 		}
 
@@ -83,6 +186,18 @@ func (c *S3Client) GetIdleBuckets() ([]models.BucketInfo, error) {
 			continue
 		}
 
+		// Skip buckets that do not carry the requested tags
+		if len(c.tagFilters) > 0 {
+			tags, err := c.getBucketTags(ctx, *bucket.Name)
+			if err != nil {
+				recordSkip("s3", c.region, *bucket.Name, "tags unavailable for --tag", err)
+				continue
+			}
+			if !matchesTagFilters(tags, c.tagFilters) {
+				continue
+			}
+		}
+
 		// Store just the bucket name
 		regionBuckets = append(regionBuckets, *bucket.Name)
 	}
@@ -93,7 +208,13 @@ func (c *S3Client) GetIdleBuckets() ([]models.BucketInfo, error) {
 	}
 
 	// Process each bucket
-	for _, bucketName := range regionBuckets {
+	for i, bucketName := range regionBuckets {
+		c.progress.Report(i, totalBuckets, "Analyzing S3 bucket "+bucketName)
+		// Stop early and keep the buckets analyzed so far when the scan is cancelled
+		if ctx.Err() != nil {
+			return bucketInfos, ctx.Err()
+		}
+
 		// Find the matching bucket object to get creation date
 		var creationDate time.Time
 		for _, b := range result.Buckets {
@@ -104,21 +225,40 @@ func (c *S3Client) GetIdleBuckets() ([]models.BucketInfo, error) {
 		}
 
 		// Get basic bucket info
-		bucketInfo, err := c.analyzeBucket(bucketName, creationDate)
+		bucketInfo, err := c.analyzeBucket(ctx, bucketName, creationDate)
 		if err != nil {
-			// Log error and continue with next bucket
+			recordSkip("s3", c.region, bucketName, "bucket analysis failed", err)
 			continue
 		}
 
 		bucketInfos = append(bucketInfos, bucketInfo)
 	}
+	c.progress.Report(totalBuckets, totalBuckets, "")
 
 	return bucketInfos, nil
 }
 
+// getBucketTags returns the tags of a bucket, or an empty map if the bucket has no tag set
+func (c *S3Client) getBucketTags(ctx context.Context, bucketName string) (map[string]string, error) {
+	tags := make(map[string]string)
+	output, err := c.client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+			return tags, nil
+		}
+		return nil, err
+	}
+	for _, tag := range output.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
 // getBucketRegion determines the region for a bucket
-func (c *S3Client) getBucketRegion(bucketName string) (string, error) {
-	ctx := context.TODO()
+func (c *S3Client) getBucketRegion(ctx context.Context, bucketName string) (string, error) {
 	location, err := c.client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
 		Bucket: aws.String(bucketName),
 	})
@@ -137,8 +277,7 @@ func (c *S3Client) getBucketRegion(bucketName string) (string, error) {
 }
 
 // analyzeBucket gathers information and analytics for a single bucket
-func (c *S3Client) analyzeBucket(bucketName string, creationDate time.Time) (models.BucketInfo, error) {
-	ctx := context.TODO()
+func (c *S3Client) analyzeBucket(ctx context.Context, bucketName string, creationDate time.Time) (models.BucketInfo, error) {
 
 	bucketInfo := models.BucketInfo{
 		BucketName:   bucketName,
@@ -155,7 +294,7 @@ func (c *S3Client) analyzeBucket(bucketName string, creationDate time.Time) (mod
 	}
 
 	// Get object count and total size
-	objCount, totalSize, lastModified, err := c.getBucketStats(bucketName)
+	objCount, totalSize, lastModified, err := c.getBucketStats(ctx, bucketName)
 	if err != nil {
 		return bucketInfo, fmt.Errorf("error getting bucket stats: %w", err)
 	}
@@ -163,98 +302,106 @@ func (c *S3Client) analyzeBucket(bucketName string, creationDate time.Time) (mod
 	bucketInfo.ObjectCount = objCount
 	bucketInfo.TotalSize = totalSize
 	bucketInfo.LastModified = lastModified
+	bucketInfo.ActivityUnknown = lastModified == nil
 	bucketInfo.IsEmpty = (objCount == 0)
 
+	// Estimate storage cost from the size of each storage class
+	storageTypeSizes, err := c.getStorageTypeSizes(ctx, bucketName)
+	if err != nil {
+		// Fall back to pricing the Standard size only
+		slog.Warn("Could not retrieve S3 storage class sizes", "bucket", bucketName, "error", err)
+		storageTypeSizes = map[string]int64{"StandardStorage": totalSize}
+	}
+	bucketInfo.StorageTypeSizes = storageTypeSizes
+	bucketInfo.EstimatedMonthlyCost, bucketInfo.PricingSource = c.pricing.CalculateS3MonthlyCostWithSource(storageTypeSizes, c.region)
+
 	// Get CloudWatch metrics for API calls
-	getRequests, putRequests, err := c.getBucketAPIActivity(bucketName)
+	getRequests, putRequests, err := c.getBucketAPIActivity(ctx, bucketName)
 	if err != nil {
 		// Just log the error and continue - this is non-critical
-		fmt.Printf("Warning: Could not retrieve CloudWatch metrics for bucket %s: %v\n", bucketName, err)
+		slog.Warn("Could not retrieve S3 CloudWatch metrics", "bucket", bucketName, "error", err)
 	} else {
 		bucketInfo.GetRequestsLast30Days = getRequests
 		bucketInfo.PutRequestsLast30Days = putRequests
 	}
 
 	// Check for website configuration
-	hasWebsiteConfig, err := c.hasBucketWebsiteConfig(bucketName)
+	hasWebsiteConfig, err := c.hasBucketWebsiteConfig(ctx, bucketName)
 	if err == nil {
 		bucketInfo.HasWebsiteConfig = hasWebsiteConfig
 	}
 
 	// Check for bucket policy
-	hasBucketPolicy, err := c.hasBucketPolicy(bucketName)
+	hasBucketPolicy, err := c.hasBucketPolicy(ctx, bucketName)
 	if err == nil {
 		bucketInfo.HasBucketPolicy = hasBucketPolicy
 	}
 
 	// Check for event notifications
-	hasNotification, err := c.hasBucketNotification(bucketName)
+	hasNotification, err := c.hasBucketNotification(ctx, bucketName)
 	if err == nil {
 		bucketInfo.HasEventNotification = hasNotification
 	}
 
+	// Check for a lifecycle policy
+	hasLifecycle, err := c.hasBucketLifecycleConfig(ctx, bucketName)
+	if err == nil {
+		bucketInfo.HasLifecyclePolicy = hasLifecycle
+	}
+
+	// Check for versioning, where noncurrent versions and delete markers are billed as storage
+	versioningEnabled, mfaDelete, err := c.getBucketVersioning(ctx, bucketName)
+	if err == nil {
+		bucketInfo.VersioningEnabled = versioningEnabled
+	}
+
+	// Count incomplete multipart uploads, whose parts are billed until aborted
+	mpuCount, err := c.countIncompleteMultipartUploads(ctx, bucketName)
+	if err != nil {
+		slog.Warn("Could not list S3 incomplete multipart uploads", "bucket", bucketName, "error", err)
+	} else {
+		bucketInfo.IncompleteMPUCount = mpuCount
+	}
+
 	// Determine if bucket is idle
 	bucketInfo.IsIdle = c.determineBucketIdleStatus(&bucketInfo)
-	if bucketInfo.IsIdle && bucketInfo.LastModified != nil {
+	switch {
+	case !bucketInfo.IsIdle:
+	case bucketInfo.IsEmpty:
+		bucketInfo.IdleDays = utils.CalculateElapsedDays(bucketInfo.CreationTime)
+	case bucketInfo.LastModified != nil:
 		bucketInfo.IdleDays = utils.CalculateElapsedDays(*bucketInfo.LastModified)
 	}
 
+	// Check deletion protection only for idle buckets, which are the cleanup candidates
+	if bucketInfo.IsIdle {
+		var protections []string
+		if mfaDelete {
+			protections = append(protections, "MFA delete")
+		}
+		objectLock, err := c.hasObjectLock(ctx, bucketName)
+		if err != nil {
+			slog.Warn("Could not retrieve S3 Object Lock configuration", "bucket", bucketName, "error", err)
+		} else if objectLock {
+			protections = append(protections, "Object Lock")
+		}
+		bucketInfo.Protected = len(protections) > 0
+		bucketInfo.ProtectionReason = strings.Join(protections, ", ")
+	}
+
 	return bucketInfo, nil
 }
 
-// getBucketStats gets statistics about the bucket
-func (c *S3Client) getBucketStats(bucketName string) (int64, int64, *time.Time, error) {
+// getBucketStats gets statistics about the bucket. The last modification time is nil when
+// the metrics do not reveal any activity.
+func (c *S3Client) getBucketStats(ctx context.Context, bucketName string) (int64, int64, *time.Time, error) {
 	// Use CloudWatch metrics instead of listing all objects
-	ctx := context.TODO()
-	endTime := time.Now()
-	startTime := endTime.AddDate(0, 0, -30) // Last 30 days
-
-	// Get bucket size from CloudWatch metrics
-	sizeInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/S3"),
-		MetricName: aws.String("BucketSizeBytes"),
-		Dimensions: []cwTypes.Dimension{
-			{
-				Name:  aws.String("BucketName"),
-				Value: aws.String(bucketName),
-			},
-			{
-				Name:  aws.String("StorageType"),
-				Value: aws.String("StandardStorage"),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(86400), // 1 day
-		Statistics: []cwTypes.Statistic{cwTypes.StatisticAverage},
-	}
-
-	sizeResult, err := c.cwClient.GetMetricStatistics(ctx, sizeInput)
+	sizeDatapoints, err := c.getDailyStorageMetric(ctx, bucketName, "BucketSizeBytes", "StandardStorage", 30)
 	if err != nil {
 		return 0, 0, nil, fmt.Errorf("error getting bucket size metrics: %w", err)
 	}
 
-	// Get object count from CloudWatch metrics
-	countInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/S3"),
-		MetricName: aws.String("NumberOfObjects"),
-		Dimensions: []cwTypes.Dimension{
-			{
-				Name:  aws.String("BucketName"),
-				Value: aws.String(bucketName),
-			},
-			{
-				Name:  aws.String("StorageType"),
-				Value: aws.String("AllStorageTypes"),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(86400), // 1 day
-		Statistics: []cwTypes.Statistic{cwTypes.StatisticAverage},
-	}
-
-	countResult, err := c.cwClient.GetMetricStatistics(ctx, countInput)
+	countDatapoints, err := c.getDailyStorageMetric(ctx, bucketName, "NumberOfObjects", "AllStorageTypes", 30)
 	if err != nil {
 		return 0, 0, nil, fmt.Errorf("error getting object count metrics: %w", err)
 	}
@@ -264,73 +411,117 @@ func (c *S3Client) getBucketStats(bucketName string) (int64, int64, *time.Time,
 	var objectCount int64
 	var lastModified *time.Time
 
-	// Process size metric results - get the most recent data point
-	if len(sizeResult.Datapoints) > 0 {
-		// Sort datapoints by timestamp (descending)
-		sort.Slice(sizeResult.Datapoints, func(i, j int) bool {
-			return sizeResult.Datapoints[i].Timestamp.After(*sizeResult.Datapoints[j].Timestamp)
-		})
+	// Process size metric results - use the most recent data point
+	if latest := cloudwatchutil.Latest(sizeDatapoints, cwTypes.StatisticAverage); latest != nil {
+		totalSize = int64(*latest)
+	}
 
-		// Use the most recent datapoint
-		if sizeResult.Datapoints[0].Average != nil {
-			totalSize = int64(*sizeResult.Datapoints[0].Average)
+	// Try to find when the bucket size last changed significantly
+	lastChanged := findLastMetricChange(sizeDatapoints)
+	if lastChanged != nil && !lastChanged.After(time.Now()) { // Ensure we don't use future dates
+		lastModified = lastChanged
+	}
+
+	// Process object count metric results
+	if latest := cloudwatchutil.Latest(countDatapoints, cwTypes.StatisticAverage); latest != nil {
+		objectCount = int64(*latest)
+	}
+
+	// If we don't have lastModified from size metrics, try from count metrics
+	if lastModified == nil {
+		lastChanged := findLastMetricChange(countDatapoints)
+		if lastChanged != nil && !lastChanged.After(time.Now()) {
+			lastModified = lastChanged
 		}
+	}
 
-		// Try to find when the bucket size last changed significantly
-		lastChanged := findLastMetricChange(sizeResult.Datapoints)
-		if lastChanged != nil && (lastModified == nil || lastChanged.Before(*lastModified)) {
-			if !lastChanged.After(time.Now()) { // Ensure we don't use future dates
-				lastModified = lastChanged
+	// Fallback: if we couldn't determine lastModified from the storage metrics,
+	// use the earliest API activity as a proxy for the last use
+	if lastModified == nil {
+		for _, apiType := range []string{"GetRequests", "PutRequests"} {
+			activityTime := findEarliestActivity(ctx, c.cwClient, bucketName, apiType)
+			if activityTime != nil && !activityTime.After(time.Now()) && (lastModified == nil || activityTime.Before(*lastModified)) {
+				lastModified = activityTime
 			}
 		}
 	}
+	// Without any activity date, lastModified stays nil and the activity is reported as unknown
+	// rather than guessed
 
-	// Process object count metric results
-	if len(countResult.Datapoints) > 0 {
-		// Sort datapoints by timestamp (descending)
-		sort.Slice(countResult.Datapoints, func(i, j int) bool {
-			return countResult.Datapoints[i].Timestamp.After(*countResult.Datapoints[j].Timestamp)
-		})
+	return objectCount, totalSize, lastModified, nil
+}
 
-		// Use the most recent datapoint
-		if countResult.Datapoints[0].Average != nil {
-			objectCount = int64(*countResult.Datapoints[0].Average)
+// getStorageTypeSizes returns the latest BucketSizeBytes of each priced storage class
+// in the bucket. Only storage classes that reported metrics recently are queried.
+func (c *S3Client) getStorageTypeSizes(ctx context.Context, bucketName string) (map[string]int64, error) {
+
+	input := &cloudwatch.ListMetricsInput{
+		Namespace:  aws.String("AWS/S3"),
+		MetricName: aws.String("BucketSizeBytes"),
+		Dimensions: []cwTypes.DimensionFilter{
+			{
+				Name:  aws.String("BucketName"),
+				Value: aws.String(bucketName),
+			},
+		},
+	}
+
+	var storageTypes []string
+	paginator := cloudwatch.NewListMetricsPaginator(c.cwClient, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing bucket size metrics: %w", err)
 		}
 
-		// If we don't have lastModified from size metrics, try from count metrics
-		if lastModified == nil {
-			lastChanged := findLastMetricChange(countResult.Datapoints)
-			if lastChanged != nil && !lastChanged.After(time.Now()) {
-				lastModified = lastChanged
+		for _, metric := range page.Metrics {
+			for _, dimension := range metric.Dimensions {
+				if aws.ToString(dimension.Name) != "StorageType" {
+					continue
+				}
+				storageType := aws.ToString(dimension.Value)
+				if _, priced := pricing.S3StorageVolumeTypes[storageType]; priced {
+					storageTypes = append(storageTypes, storageType)
+				}
 			}
 		}
 	}
 
-	// Fallback: if we couldn't determine lastModified from metrics or it's in the future,
-	// use creation date or a reasonable fallback
-	if lastModified == nil || lastModified.After(time.Now()) {
-		// Try to use creation date if available
-		for _, apiType := range []string{"GetRequests", "PutRequests"} {
-			// Find the earliest API activity as a proxy for creation/first use
-			activityTime := findEarliestActivity(c.cwClient, bucketName, apiType)
-			if activityTime != nil && (lastModified == nil || activityTime.Before(*lastModified)) {
-				lastModified = activityTime
-			}
+	sizes := make(map[string]int64)
+	for _, storageType := range storageTypes {
+		// BucketSizeBytes is reported once a day
+		datapoints, err := c.getDailyStorageMetric(ctx, bucketName, "BucketSizeBytes", storageType, 3)
+		if err != nil {
+			return nil, fmt.Errorf("error getting %s size metrics: %w", storageType, err)
 		}
 
-		// If still no valid date, use a more conservative estimate
-		if lastModified == nil || lastModified.After(time.Now()) {
-			// Use 90 days ago as a safe fallback - better to potentially mark as idle
-			// than to incorrectly mark as recently active
-			t := time.Now().AddDate(0, 0, -90)
-			lastModified = &t
+		// Use the most recent datapoint
+		if latest := cloudwatchutil.Latest(datapoints, cwTypes.StatisticAverage); latest != nil {
+			sizes[storageType] = int64(*latest)
 		}
 	}
 
-	return objectCount, totalSize, lastModified, nil
+	return sizes, nil
 }
 
-// findLastMetricChange analyzes metric datapoints to find the last significant change
+// getDailyStorageMetric retrieves the daily Average datapoints of a bucket storage metric of a
+// storage type over the last days, sorted oldest first
+func (c *S3Client) getDailyStorageMetric(ctx context.Context, bucketName, metricName, storageType string, days int) ([]cwTypes.Datapoint, error) {
+	return cloudwatchutil.Datapoints(ctx, c.cwClient, cloudwatchutil.MetricQuery{
+		Namespace: "AWS/S3",
+		Name:      metricName,
+		Dimensions: []cwTypes.Dimension{
+			cloudwatchutil.Dimension("BucketName", bucketName),
+			cloudwatchutil.Dimension("StorageType", storageType),
+		},
+		Stat:       cwTypes.StatisticAverage,
+		WindowDays: days,
+		Period:     24 * time.Hour,
+	})
+}
+
+// findLastMetricChange analyzes metric datapoints, sorted oldest first, to find the last
+// significant change
 func findLastMetricChange(datapoints []cwTypes.Datapoint) *time.Time {
 	if len(datapoints) < 2 {
 		if len(datapoints) == 1 {
@@ -339,11 +530,6 @@ func findLastMetricChange(datapoints []cwTypes.Datapoint) *time.Time {
 		return nil
 	}
 
-	// Sort by timestamp (ascending)
-	sort.Slice(datapoints, func(i, j int) bool {
-		return datapoints[i].Timestamp.Before(*datapoints[j].Timestamp)
-	})
-
 	var lastChangeTime *time.Time
 	var prevValue float64
 	if datapoints[0].Average != nil {
@@ -370,109 +556,52 @@ func findLastMetricChange(datapoints []cwTypes.Datapoint) *time.Time {
 }
 
 // findEarliestActivity finds the earliest recorded API activity for a bucket
-func findEarliestActivity(cwClient *cloudwatch.Client, bucketName string, metricName string) *time.Time {
-	ctx := context.TODO()
-	endTime := time.Now()
-	startTime := endTime.AddDate(0, 0, -90) // Look back 90 days max
-
-	metricsInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/S3"),
-		MetricName: aws.String(metricName),
-		Dimensions: []cwTypes.Dimension{
-			{
-				Name:  aws.String("BucketName"),
-				Value: aws.String(bucketName),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(86400), // 1 day
-		Statistics: []cwTypes.Statistic{cwTypes.StatisticSum},
-	}
-
-	result, err := cwClient.GetMetricStatistics(ctx, metricsInput)
-	if err != nil || len(result.Datapoints) == 0 {
+func findEarliestActivity(ctx context.Context, cwClient cloudWatchMetricsAPI, bucketName string, metricName string) *time.Time {
+	datapoints, err := cloudwatchutil.Datapoints(ctx, cwClient, cloudwatchutil.MetricQuery{
+		Namespace:  "AWS/S3",
+		Name:       metricName,
+		Dimensions: []cwTypes.Dimension{cloudwatchutil.Dimension("BucketName", bucketName)},
+		Stat:       cwTypes.StatisticSum,
+		WindowDays: 90, // Look back 90 days max
+		Period:     24 * time.Hour,
+	})
+	if err != nil {
 		return nil
 	}
 
-	// Find the earliest datapoint with activity
-	sort.Slice(result.Datapoints, func(i, j int) bool {
-		return result.Datapoints[i].Timestamp.Before(*result.Datapoints[j].Timestamp)
-	})
-
 	// Find first datapoint with non-zero activity
-	for _, dp := range result.Datapoints {
-		if dp.Sum != nil && *dp.Sum > 0 {
-			return dp.Timestamp
-		}
-	}
-
-	return nil
+	return cloudwatchutil.FirstNonZeroTimestamp(datapoints, cwTypes.StatisticSum)
 }
 
 // getBucketAPIActivity gets API call activity from CloudWatch metrics
-func (c *S3Client) getBucketAPIActivity(bucketName string) (int64, int64, error) {
-	// Time period for metrics: last 30 days
-	endTime := time.Now()
-	startTime := endTime.AddDate(0, 0, -30)
-
-	// GetObject requests
-	getRequestsInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/S3"),
-		MetricName: aws.String("GetRequests"),
-		Dimensions: []cwTypes.Dimension{
-			{
-				Name:  aws.String("BucketName"),
-				Value: aws.String(bucketName),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(86400), // 1 day
-		Statistics: []cwTypes.Statistic{cwTypes.StatisticSum},
+func (c *S3Client) getBucketAPIActivity(ctx context.Context, bucketName string) (int64, int64, error) {
+	// Daily request counts over the last 30 days
+	requestQuery := func(metricName string) cloudwatchutil.MetricQuery {
+		return cloudwatchutil.MetricQuery{
+			Namespace:  "AWS/S3",
+			Name:       metricName,
+			Dimensions: []cwTypes.Dimension{cloudwatchutil.Dimension("BucketName", bucketName)},
+			Stat:       cwTypes.StatisticSum,
+			WindowDays: 30,
+			Period:     24 * time.Hour,
+		}
 	}
 
-	getResult, err := c.cwClient.GetMetricStatistics(context.TODO(), getRequestsInput)
+	getRequests, err := cloudwatchutil.SumOverWindow(ctx, c.cwClient, requestQuery("GetRequests"))
 	if err != nil {
 		return 0, 0, err
 	}
-
-	// PutObject requests
-	putRequestsInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/S3"),
-		MetricName: aws.String("PutRequests"),
-		Dimensions: []cwTypes.Dimension{
-			{
-				Name:  aws.String("BucketName"),
-				Value: aws.String(bucketName),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(86400), // 1 day
-		Statistics: []cwTypes.Statistic{cwTypes.StatisticSum},
-	}
-
-	putResult, err := c.cwClient.GetMetricStatistics(context.TODO(), putRequestsInput)
+	putRequests, err := cloudwatchutil.SumOverWindow(ctx, c.cwClient, requestQuery("PutRequests"))
 	if err != nil {
 		return 0, 0, err
 	}
 
-	// Sum up the values
-	var getRequests, putRequests int64
-	for _, datapoint := range getResult.Datapoints {
-		getRequests += int64(*datapoint.Sum)
-	}
-	for _, datapoint := range putResult.Datapoints {
-		putRequests += int64(*datapoint.Sum)
-	}
-
-	return getRequests, putRequests, nil
+	return int64(getRequests), int64(putRequests), nil
 }
 
 // hasBucketWebsiteConfig checks if bucket has website configuration
-func (c *S3Client) hasBucketWebsiteConfig(bucketName string) (bool, error) {
-	_, err := c.client.GetBucketWebsite(context.TODO(), &s3.GetBucketWebsiteInput{
+func (c *S3Client) hasBucketWebsiteConfig(ctx context.Context, bucketName string) (bool, error) {
+	_, err := c.client.GetBucketWebsite(ctx, &s3.GetBucketWebsiteInput{
 		Bucket: aws.String(bucketName),
 	})
 	if err != nil {
@@ -483,8 +612,8 @@ func (c *S3Client) hasBucketWebsiteConfig(bucketName string) (bool, error) {
 }
 
 // hasBucketPolicy checks if bucket has a policy
-func (c *S3Client) hasBucketPolicy(bucketName string) (bool, error) {
-	result, err := c.client.GetBucketPolicy(context.TODO(), &s3.GetBucketPolicyInput{
+func (c *S3Client) hasBucketPolicy(ctx context.Context, bucketName string) (bool, error) {
+	result, err := c.client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
 		Bucket: aws.String(bucketName),
 	})
 	if err != nil {
@@ -496,8 +625,8 @@ func (c *S3Client) hasBucketPolicy(bucketName string) (bool, error) {
 }
 
 // hasBucketNotification checks if bucket has event notifications
-func (c *S3Client) hasBucketNotification(bucketName string) (bool, error) {
-	result, err := c.client.GetBucketNotificationConfiguration(context.TODO(),
+func (c *S3Client) hasBucketNotification(ctx context.Context, bucketName string) (bool, error) {
+	result, err := c.client.GetBucketNotificationConfiguration(ctx,
 		&s3.GetBucketNotificationConfigurationInput{
 			Bucket: aws.String(bucketName),
 		})
@@ -513,25 +642,89 @@ func (c *S3Client) hasBucketNotification(bucketName string) (bool, error) {
 	return hasLambda || hasQueue || hasTopic, nil
 }
 
+// hasBucketLifecycleConfig checks if bucket has a lifecycle policy
+func (c *S3Client) hasBucketLifecycleConfig(ctx context.Context, bucketName string) (bool, error) {
+	result, err := c.client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchLifecycleConfiguration" {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(result.Rules) > 0, nil
+}
+
+// getBucketVersioning checks if bucket versioning and MFA delete are enabled. Buckets that
+// never had versioning enabled report no status.
+func (c *S3Client) getBucketVersioning(ctx context.Context, bucketName string) (enabled, mfaDelete bool, err error) {
+	result, err := c.client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return false, false, err
+	}
+	return result.Status == s3Types.BucketVersioningStatusEnabled, result.MFADelete == s3Types.MFADeleteStatusEnabled, nil
+}
+
+// hasObjectLock checks if Object Lock is enabled on a bucket
+func (c *S3Client) hasObjectLock(ctx context.Context, bucketName string) (bool, error) {
+	result, err := c.client.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ObjectLockConfigurationNotFoundError" {
+			return false, nil
+		}
+		return false, err
+	}
+	return result.ObjectLockConfiguration != nil &&
+		result.ObjectLockConfiguration.ObjectLockEnabled == s3Types.ObjectLockEnabledEnabled, nil
+}
+
+// countIncompleteMultipartUploads returns the number of multipart uploads in progress in a bucket
+func (c *S3Client) countIncompleteMultipartUploads(ctx context.Context, bucketName string) (int, error) {
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucketName),
+	}
+
+	count := 0
+	for {
+		result, err := c.client.ListMultipartUploads(ctx, input)
+		if err != nil {
+			return 0, err
+		}
+		count += len(result.Uploads)
+
+		if !aws.ToBool(result.IsTruncated) {
+			return count, nil
+		}
+		input.KeyMarker = result.NextKeyMarker
+		input.UploadIdMarker = result.NextUploadIdMarker
+	}
+}
+
 // determineBucketIdleStatus determines if a bucket is idle based on multiple criteria
 func (c *S3Client) determineBucketIdleStatus(bucketInfo *models.BucketInfo) bool {
-	// Empty buckets are considered idle
+	// Empty buckets are idle once they are older than the threshold, so that buckets
+	// created for a new project are not reported right away
 	if bucketInfo.IsEmpty {
-		return true
+		return !bucketInfo.CreationTime.IsZero() &&
+			utils.CalculateElapsedDays(bucketInfo.CreationTime) > c.idleThreshold
 	}
 
-	// No last modified date means we can't reliably determine status
-	// Conservatively mark as not idle unless very clear evidence
+	// Without an activity date the status cannot be determined, so the bucket is
+	// conservatively not idle and its activity is reported as unknown
 	if bucketInfo.LastModified == nil {
-		// Only mark as idle if zero API activity
-		return bucketInfo.GetRequestsLast30Days == 0 && bucketInfo.PutRequestsLast30Days == 0
+		return false
 	}
 
 	// Calculate days since last modification
 	daysSinceModified := utils.CalculateElapsedDays(*bucketInfo.LastModified)
 
-	// Debug logging removed for clarity
-
 	// Primary idle check: No PUT requests and older than threshold
 	if bucketInfo.PutRequestsLast30Days == 0 && daysSinceModified > c.idleThreshold {
 		// For buckets with minimal GET activity
@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+// storageMetrics answers the S3 metric lookups with the given daily datapoints by metric name
+func storageMetrics(datapoints map[string][]cwTypes.Datapoint) *mocks.CloudWatch {
+	return &mocks.CloudWatch{
+		GetMetricStatisticsFunc: func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			return &cloudwatch.GetMetricStatisticsOutput{Datapoints: datapoints[aws.ToString(params.MetricName)]}, nil
+		},
+	}
+}
+
+// bucketWithoutConfig answers the bucket configuration lookups as for a bucket without any
+func bucketWithoutConfig() *mocks.S3 {
+	return &mocks.S3{
+		GetBucketWebsiteFunc: func(ctx context.Context, params *s3.GetBucketWebsiteInput) (*s3.GetBucketWebsiteOutput, error) {
+			return nil, errors.New("NoSuchWebsiteConfiguration")
+		},
+	}
+}
+
+func TestAnalyzeBucket(t *testing.T) {
+	now := time.Now().UTC()
+	daysAgo := func(days int) *time.Time { return aws.Time(now.AddDate(0, 0, -days)) }
+
+	tests := []struct {
+		name         string
+		creationDays int
+		metrics      map[string][]cwTypes.Datapoint
+		versioning   s3Types.BucketVersioningStatus
+		mfaDelete    s3Types.MFADeleteStatus
+		wantEmpty    bool
+		wantIdle     bool
+		wantUnknown  bool
+		wantProtect  string
+	}{
+		{
+			name:         "old empty bucket is idle",
+			creationDays: 100,
+			wantEmpty:    true,
+			wantIdle:     true,
+			wantUnknown:  true,
+		},
+		{
+			name:         "new empty bucket is not idle yet",
+			creationDays: 5,
+			wantEmpty:    true,
+			wantUnknown:  true,
+		},
+		{
+			name:         "bucket unchanged for months without requests is idle",
+			creationDays: 400,
+			metrics: map[string][]cwTypes.Datapoint{
+				"BucketSizeBytes": {{Timestamp: daysAgo(60), Average: aws.Float64(1024)}},
+				"NumberOfObjects": {{Timestamp: daysAgo(60), Average: aws.Float64(3)}},
+			},
+			mfaDelete:   s3Types.MFADeleteStatusEnabled,
+			versioning:  s3Types.BucketVersioningStatusEnabled,
+			wantIdle:    true,
+			wantProtect: "MFA delete",
+		},
+		{
+			name:         "bucket written to recently is not idle",
+			creationDays: 400,
+			metrics: map[string][]cwTypes.Datapoint{
+				"BucketSizeBytes": {{Timestamp: daysAgo(1), Average: aws.Float64(2048)}},
+				"NumberOfObjects": {{Timestamp: daysAgo(1), Average: aws.Float64(5)}},
+				"PutRequests":     {{Timestamp: daysAgo(1), Sum: aws.Float64(10)}},
+			},
+		},
+		{
+			name:         "bucket without an activity date is not idle",
+			creationDays: 400,
+			metrics: map[string][]cwTypes.Datapoint{
+				"NumberOfObjects": {
+					{Timestamp: daysAgo(2), Average: aws.Float64(5)},
+					{Timestamp: daysAgo(1), Average: aws.Float64(5)},
+				},
+			},
+			wantUnknown: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := bucketWithoutConfig()
+			fake.GetBucketVersioningFunc = func(ctx context.Context, params *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+				return &s3.GetBucketVersioningOutput{Status: tt.versioning, MFADelete: tt.mfaDelete}, nil
+			}
+			client := &S3Client{
+				client:        fake,
+				cwClient:      storageMetrics(tt.metrics),
+				region:        "us-east-1",
+				idleThreshold: 30,
+				pricing:       newTestPricing(),
+			}
+
+			info, err := client.analyzeBucket(context.Background(), "bucket", now.AddDate(0, 0, -tt.creationDays))
+			if err != nil {
+				t.Fatalf("analyzeBucket() error = %v", err)
+			}
+			if info.IsEmpty != tt.wantEmpty {
+				t.Errorf("IsEmpty = %v, want %v", info.IsEmpty, tt.wantEmpty)
+			}
+			if info.IsIdle != tt.wantIdle {
+				t.Errorf("IsIdle = %v, want %v", info.IsIdle, tt.wantIdle)
+			}
+			if info.ActivityUnknown != tt.wantUnknown {
+				t.Errorf("ActivityUnknown = %v, want %v", info.ActivityUnknown, tt.wantUnknown)
+			}
+			if info.ProtectionReason != tt.wantProtect {
+				t.Errorf("ProtectionReason = %q, want %q", info.ProtectionReason, tt.wantProtect)
+			}
+			if info.VersioningEnabled != (tt.versioning == s3Types.BucketVersioningStatusEnabled) {
+				t.Errorf("VersioningEnabled = %v, want %v", info.VersioningEnabled, tt.versioning)
+			}
+			if info.HasWebsiteConfig {
+				t.Error("HasWebsiteConfig = true, want false")
+			}
+		})
+	}
+}
+
+func TestAnalyzeBucketInaccessible(t *testing.T) {
+	fake := bucketWithoutConfig()
+	fake.HeadBucketFunc = func(ctx context.Context, params *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+		return nil, errors.New("forbidden")
+	}
+	client := &S3Client{client: fake, cwClient: storageMetrics(nil), region: "us-east-1", idleThreshold: 30, pricing: newTestPricing()}
+
+	if _, err := client.analyzeBucket(context.Background(), "bucket", time.Now()); err == nil {
+		t.Fatal("analyzeBucket() error = nil, want the HeadBucket error")
+	}
+}
+
+func TestAnalyzeBucketStorageClassCost(t *testing.T) {
+	now := time.Now().UTC()
+	cw := storageMetrics(map[string][]cwTypes.Datapoint{
+		"BucketSizeBytes": {{Timestamp: aws.Time(now.AddDate(0, 0, -1)), Average: aws.Float64(100 << 30)}},
+		"NumberOfObjects": {{Timestamp: aws.Time(now.AddDate(0, 0, -1)), Average: aws.Float64(1)}},
+	})
+	cw.ListMetricsFunc = func(ctx context.Context, params *cloudwatch.ListMetricsInput) (*cloudwatch.ListMetricsOutput, error) {
+		return &cloudwatch.ListMetricsOutput{Metrics: []cwTypes.Metric{
+			{Dimensions: []cwTypes.Dimension{{Name: aws.String("StorageType"), Value: aws.String("StandardStorage")}}},
+		}}, nil
+	}
+	client := &S3Client{client: bucketWithoutConfig(), cwClient: cw, region: "us-east-1", idleThreshold: 30, pricing: newTestPricing()}
+
+	info, err := client.analyzeBucket(context.Background(), "bucket", now.AddDate(-1, 0, 0))
+	if err != nil {
+		t.Fatalf("analyzeBucket() error = %v", err)
+	}
+	if got := info.StorageTypeSizes["StandardStorage"]; got != 100<<30 {
+		t.Errorf("StandardStorage size = %d, want %d", got, int64(100<<30))
+	}
+	if info.EstimatedMonthlyCost <= 0 {
+		t.Errorf("EstimatedMonthlyCost = %v, want the price of 100 GB", info.EstimatedMonthlyCost)
+	}
+}
@@ -0,0 +1,282 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+const (
+	sageMakerCheckPeriodDays = 30
+
+	// Endpoint invocations, published per production variant
+	sageMakerEndpointNamespace    = "AWS/SageMaker"
+	sageMakerMetricInvocations    = "Invocations"
+	sageMakerNotebookNamespace    = "/aws/sagemaker/NotebookInstances"
+	sageMakerMetricCPUUtilization = "CPUUtilization"
+
+	// sageMakerNotebookCPUThreshold is the average CPU (%) below which a notebook instance is idle
+	sageMakerNotebookCPUThreshold = 5.0
+
+	// sageMakerLargeVolumeGB is the ML storage volume size from which a stopped notebook instance is reported
+	sageMakerLargeVolumeGB = 100
+)
+
+// sageMakerAPI is the SageMaker API used by SageMakerScanner
+type sageMakerAPI interface {
+	sagemaker.ListEndpointsAPIClient
+	sagemaker.ListNotebookInstancesAPIClient
+	DescribeEndpoint(ctx context.Context, params *sagemaker.DescribeEndpointInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeEndpointOutput, error)
+	DescribeEndpointConfig(ctx context.Context, params *sagemaker.DescribeEndpointConfigInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeEndpointConfigOutput, error)
+	DescribeNotebookInstance(ctx context.Context, params *sagemaker.DescribeNotebookInstanceInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeNotebookInstanceOutput, error)
+}
+
+// SageMakerScanner contains the AWS clients needed for scanning SageMaker resources
+type SageMakerScanner struct {
+	SageMakerClient sageMakerAPI
+	CWClient        cloudWatchMetricsAPI
+	Region          string
+	Pricing         *pricing.PricingService
+}
+
+// NewSageMakerScanner creates a new SageMakerScanner for a given region
+func NewSageMakerScanner(cfg aws.Config) *SageMakerScanner {
+	return &SageMakerScanner{
+		SageMakerClient: sagemaker.NewFromConfig(cfg),
+		CWClient:        cloudwatch.NewFromConfig(cfg),
+		Region:          cfg.Region,
+		Pricing:         pricing.Default(),
+	}
+}
+
+// GetIdleSageMakerResources returns the in-service endpoints without invocations over the last
+// 30 days, the in-service notebook instances unchanged and without CPU usage over the last
+// 30 days, and the stopped notebook instances with a large ML storage volume
+func (s *SageMakerScanner) GetIdleSageMakerResources(ctx context.Context) ([]models.SageMakerResourceInfo, []error) {
+	endpoints, scanErrs := s.getIdleEndpoints(ctx)
+	notebooks, notebookErrs := s.getIdleNotebooks(ctx)
+	return append(endpoints, notebooks...), append(scanErrs, notebookErrs...)
+}
+
+// getIdleEndpoints lists in-service endpoints and returns those without invocations
+func (s *SageMakerScanner) getIdleEndpoints(ctx context.Context) ([]models.SageMakerResourceInfo, []error) {
+	var endpoints []models.SageMakerResourceInfo
+	var errs []error
+
+	paginator := sagemaker.NewListEndpointsPaginator(s.SageMakerClient, &sagemaker.ListEndpointsInput{
+		StatusEquals: smtypes.EndpointStatusInService,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing SageMaker endpoints: %w", err))
+			break
+		}
+
+		for _, summary := range page.Endpoints {
+			info, err := s.checkEndpoint(ctx, summary)
+			if err != nil {
+				errs = append(errs, err)
+				recordSkip("sagemaker", s.Region, aws.ToString(summary.EndpointName), "endpoint details unavailable", err)
+				continue // Cannot determine idleness without the variants and their invocations
+			}
+			if info != nil {
+				endpoints = append(endpoints, *info)
+			}
+		}
+	}
+
+	return endpoints, errs
+}
+
+// checkEndpoint returns the endpoint info if the endpoint had no invocations over the check
+// period, or nil otherwise. Its cost is the instance cost of its provisioned variants.
+func (s *SageMakerScanner) checkEndpoint(ctx context.Context, summary smtypes.EndpointSummary) (*models.SageMakerResourceInfo, error) {
+	name := aws.ToString(summary.EndpointName)
+
+	endpoint, err := s.SageMakerClient.DescribeEndpoint(ctx, &sagemaker.DescribeEndpointInput{EndpointName: summary.EndpointName})
+	if err != nil {
+		return nil, fmt.Errorf("error describing SageMaker endpoint %s: %w", name, err)
+	}
+
+	// Instance types are only part of the endpoint configuration
+	config, err := s.SageMakerClient.DescribeEndpointConfig(ctx, &sagemaker.DescribeEndpointConfigInput{EndpointConfigName: endpoint.EndpointConfigName})
+	if err != nil {
+		return nil, fmt.Errorf("error describing endpoint configuration of SageMaker endpoint %s: %w", name, err)
+	}
+	instanceTypes := make(map[string]string, len(config.ProductionVariants))
+	for _, variant := range config.ProductionVariants {
+		instanceTypes[aws.ToString(variant.VariantName)] = string(variant.InstanceType)
+	}
+
+	var invocations float64
+	instanceCounts := make(map[string]int)
+	info := &models.SageMakerResourceInfo{
+		Name:             name,
+		ARN:              aws.ToString(summary.EndpointArn),
+		Region:           s.Region,
+		ResourceType:     models.SageMakerResourceEndpoint,
+		Serverless:       true,
+		Status:           string(summary.EndpointStatus),
+		CreationTime:     aws.ToTime(summary.CreationTime),
+		LastModifiedTime: aws.ToTime(summary.LastModifiedTime),
+	}
+	for _, variant := range endpoint.ProductionVariants {
+		variantName := aws.ToString(variant.VariantName)
+		variantInvocations, err := s.getMetricValue(ctx, sageMakerEndpointNamespace, sageMakerMetricInvocations, cwtypes.StatisticSum,
+			cloudwatchutil.Dimension("EndpointName", name), cloudwatchutil.Dimension("VariantName", variantName))
+		if err != nil {
+			return nil, err
+		}
+		invocations += aws.ToFloat64(variantInvocations)
+
+		// Serverless variants are only billed per use
+		if variant.CurrentServerlessConfig != nil {
+			continue
+		}
+		info.Serverless = false
+		count := int(aws.ToInt32(variant.CurrentInstanceCount))
+		instanceType := instanceTypes[variantName]
+		instanceCounts[instanceType] += count
+		info.InstanceCount += count
+		if info.InstanceType == "" {
+			info.InstanceType = instanceType
+		}
+	}
+
+	if invocations > 0 {
+		return nil, nil
+	}
+
+	info.Invocations = &invocations
+	info.IdleReason = fmt.Sprintf("No invocations (%dd)", sageMakerCheckPeriodDays)
+	info.EstimatedMonthlyCost, info.PricingSource = s.Pricing.CalculateSageMakerEndpointMonthlyCostWithSource(instanceCounts, s.Region)
+	if info.Serverless {
+		// Serverless endpoints are only billed per invocation, so an idle one costs nothing
+		info.PricingSource = string(pricing.PricingSourceDefault)
+	}
+	return info, nil
+}
+
+// getIdleNotebooks lists notebook instances and returns the idle in-service ones and the
+// stopped ones with a large ML storage volume
+func (s *SageMakerScanner) getIdleNotebooks(ctx context.Context) ([]models.SageMakerResourceInfo, []error) {
+	var notebooks []models.SageMakerResourceInfo
+	var errs []error
+
+	idleSince := time.Now().AddDate(0, 0, -sageMakerCheckPeriodDays)
+	paginator := sagemaker.NewListNotebookInstancesPaginator(s.SageMakerClient, &sagemaker.ListNotebookInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing SageMaker notebook instances: %w", err))
+			break
+		}
+
+		for _, summary := range page.NotebookInstances {
+			switch summary.NotebookInstanceStatus {
+			case smtypes.NotebookInstanceStatusInService:
+				// A notebook started or updated recently is in use
+				if aws.ToTime(summary.LastModifiedTime).After(idleSince) {
+					continue
+				}
+			case smtypes.NotebookInstanceStatusStopped:
+			default:
+				continue
+			}
+
+			info, err := s.checkNotebook(ctx, summary)
+			if err != nil {
+				errs = append(errs, err)
+				recordSkip("sagemaker", s.Region, aws.ToString(summary.NotebookInstanceName), "metrics unavailable", err)
+				continue
+			}
+			if info != nil {
+				notebooks = append(notebooks, *info)
+			}
+		}
+	}
+
+	return notebooks, errs
+}
+
+// checkNotebook returns the notebook instance info if the notebook is idle, or nil otherwise.
+// An in-service notebook is idle if its average CPU is below the threshold, or not published.
+// A stopped notebook is only billed for its ML storage, so it is reported if the volume is large.
+func (s *SageMakerScanner) checkNotebook(ctx context.Context, summary smtypes.NotebookInstanceSummary) (*models.SageMakerResourceInfo, error) {
+	name := aws.ToString(summary.NotebookInstanceName)
+
+	notebook, err := s.SageMakerClient.DescribeNotebookInstance(ctx, &sagemaker.DescribeNotebookInstanceInput{NotebookInstanceName: summary.NotebookInstanceName})
+	if err != nil {
+		return nil, fmt.Errorf("error describing SageMaker notebook instance %s: %w", name, err)
+	}
+
+	info := &models.SageMakerResourceInfo{
+		Name:             name,
+		ARN:              aws.ToString(summary.NotebookInstanceArn),
+		Region:           s.Region,
+		ResourceType:     models.SageMakerResourceNotebook,
+		InstanceType:     string(summary.InstanceType),
+		InstanceCount:    1,
+		Status:           string(summary.NotebookInstanceStatus),
+		CreationTime:     aws.ToTime(summary.CreationTime),
+		LastModifiedTime: aws.ToTime(summary.LastModifiedTime),
+		VolumeSizeGB:     int(aws.ToInt32(notebook.VolumeSizeInGB)),
+	}
+	storageCost := float64(info.VolumeSizeGB) * pricing.SageMakerStorageMonthlyPricePerGB
+
+	if summary.NotebookInstanceStatus == smtypes.NotebookInstanceStatusStopped {
+		if info.VolumeSizeGB < sageMakerLargeVolumeGB {
+			return nil, nil
+		}
+		info.IdleReason = fmt.Sprintf("Stopped with %d GB volume", info.VolumeSizeGB)
+		info.EstimatedMonthlyCost = storageCost
+		info.PricingSource = string(pricing.PricingSourceDefault)
+		return info, nil
+	}
+
+	cpu, err := s.getMetricValue(ctx, sageMakerNotebookNamespace, sageMakerMetricCPUUtilization, cwtypes.StatisticAverage,
+		cloudwatchutil.Dimension("NotebookInstanceName", name))
+	if err != nil {
+		return nil, err
+	}
+	if cpu != nil && *cpu >= sageMakerNotebookCPUThreshold {
+		return nil, nil
+	}
+
+	info.AvgCPUUtilization = cpu
+	info.IdleReason = fmt.Sprintf("Unchanged and low CPU (%dd)", sageMakerCheckPeriodDays)
+	if cpu == nil {
+		info.IdleReason = fmt.Sprintf("Unchanged, no CPU data (%dd)", sageMakerCheckPeriodDays)
+	}
+	instanceCost, source := s.Pricing.CalculateSageMakerMonthlyCostWithSource(pricing.SageMakerComponentNotebook, info.InstanceType, 1, s.Region)
+	info.EstimatedMonthlyCost = instanceCost + storageCost
+	info.PricingSource = source
+	return info, nil
+}
+
+// getMetricValue fetches a single statistic of a SageMaker metric over the check period,
+// or nil if no datapoints were published
+func (s *SageMakerScanner) getMetricValue(ctx context.Context, namespace, metricName string, statistic cwtypes.Statistic, dimensions ...cwtypes.Dimension) (*float64, error) {
+	value, err := cloudwatchutil.ValueOverWindow(ctx, s.CWClient, cloudwatchutil.MetricQuery{
+		Namespace:  namespace,
+		Name:       metricName,
+		Dimensions: dimensions,
+		Stat:       statistic,
+		WindowDays: sageMakerCheckPeriodDays,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CloudWatch API error for metric %s of %s: %w", metricName, aws.ToString(dimensions[0].Value), err)
+	}
+	return value, nil
+}
@@ -7,31 +7,40 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
-
-	// smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 
 	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/pricing"
 )
 
 const (
-	secretsManagerIdleDays = 90
+	// DefaultSecretsIdleDays is the default number of days without access before a secret is idle
+	DefaultSecretsIdleDays = 90
 )
 
 // SecretsManagerScanner contains the AWS client needed for scanning Secrets Manager resources
 type SecretsManagerScanner struct {
-	Client *secretsmanager.Client
-	Region string
+	Client        secretsmanager.ListSecretsAPIClient
+	Region        string
+	IdleThreshold int // in days
 }
 
 // NewSecretsManagerScanner creates a new SecretsManagerScanner for a given region
 func NewSecretsManagerScanner(cfg aws.Config) *SecretsManagerScanner {
 	return &SecretsManagerScanner{
-		Client: secretsmanager.NewFromConfig(cfg),
-		Region: cfg.Region,
+		Client:        secretsmanager.NewFromConfig(cfg),
+		Region:        cfg.Region,
+		IdleThreshold: DefaultSecretsIdleDays,
 	}
 }
 
-// GetIdleSecrets scans all secrets in the region and identifies idle ones.
+// SetIdleThreshold sets the number of days without access before a secret is considered idle
+func (s *SecretsManagerScanner) SetIdleThreshold(days int) {
+	s.IdleThreshold = days
+}
+
+// GetIdleSecrets scans all secrets in the region and identifies idle ones. A secret is idle if
+// it was last accessed at least IdleThreshold days ago, or was never accessed and created that long ago.
 func (s *SecretsManagerScanner) GetIdleSecrets(ctx context.Context) ([]models.SecretInfo, []error) {
 	var idleSecrets []models.SecretInfo
 	var scanErrs []error
@@ -50,28 +59,9 @@ func (s *SecretsManagerScanner) GetIdleSecrets(ctx context.Context) ([]models.Se
 			break // Stop processing this region on pagination error
 		}
 
-		if output != nil {
-			for _, secret := range output.SecretList {
-				// Check if LastAccessedDate is available
-				if secret.LastAccessedDate != nil {
-					lastAccessed := aws.ToTime(secret.LastAccessedDate)
-					idleDuration := now.Sub(lastAccessed)
-					idleDays := int(idleDuration.Hours() / 24)
-
-					if idleDays > secretsManagerIdleDays {
-						idleSecrets = append(idleSecrets, models.SecretInfo{
-							ARN:              aws.ToString(secret.ARN),
-							Name:             aws.ToString(secret.Name),
-							Region:           s.Region,
-							LastAccessedDate: lastAccessed,
-							IdleDays:         idleDays,
-						})
-					}
-				} else {
-					// Secret has never been accessed, consider it idle based on creation date?
-					// For now, we only consider secrets with a LastAccessedDate.
-					// Alternatively, could check CreationDate if LastAccessedDate is nil.
-				}
+		for _, secret := range output.SecretList {
+			if info, ok := s.checkSecret(secret, now); ok {
+				idleSecrets = append(idleSecrets, info)
 			}
 		}
 	}
@@ -79,12 +69,34 @@ func (s *SecretsManagerScanner) GetIdleSecrets(ctx context.Context) ([]models.Se
 	return idleSecrets, scanErrs
 }
 
-// TODO: Define models.SecretInfo in internal/models/aws.go if it doesn't exist.
-// Example structure:
-// type SecretInfo struct {
-// 	 ARN            string
-// 	 Name           string
-// 	 Region         string
-// 	 LastAccessedDate time.Time
-// 	 IdleDays       int
-// }
+// checkSecret returns the secret info if the secret is idle. Secrets that were never
+// accessed are aged from their creation date, since they are the most likely to be abandoned.
+func (s *SecretsManagerScanner) checkSecret(secret smtypes.SecretListEntry, now time.Time) (models.SecretInfo, bool) {
+	neverAccessed := secret.LastAccessedDate == nil
+	idleSince := aws.ToTime(secret.LastAccessedDate)
+	if neverAccessed {
+		// Without a creation date the idle age cannot be determined
+		if secret.CreatedDate == nil {
+			return models.SecretInfo{}, false
+		}
+		idleSince = aws.ToTime(secret.CreatedDate)
+	}
+
+	idleDays := int(now.Sub(idleSince).Hours() / 24)
+	if idleDays < s.IdleThreshold {
+		return models.SecretInfo{}, false
+	}
+
+	return models.SecretInfo{
+		ARN:                  aws.ToString(secret.ARN),
+		Name:                 aws.ToString(secret.Name),
+		Region:               s.Region,
+		LastAccessedDate:     aws.ToTime(secret.LastAccessedDate),
+		CreatedDate:          aws.ToTime(secret.CreatedDate),
+		NeverAccessed:        neverAccessed,
+		IdleDays:             idleDays,
+		RotationEnabled:      aws.ToBool(secret.RotationEnabled),
+		LastRotatedDate:      secret.LastRotatedDate,
+		EstimatedMonthlyCost: pricing.SecretsManagerMonthlyPricePerSecret,
+	}, true
+}
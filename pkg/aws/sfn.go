@@ -0,0 +1,161 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+const (
+	// DefaultStepFunctionsIdleDays is the default number of days without an execution before a state machine is idle
+	DefaultStepFunctionsIdleDays = 90
+
+	// sfnMinLookbackDays is the shortest period searched for the last execution. The idle
+	// threshold extends it when longer.
+	sfnMinLookbackDays = 90
+)
+
+// sfnAPI is the Step Functions API used by StepFunctionsClient
+type sfnAPI interface {
+	sfn.ListStateMachinesAPIClient
+	ListExecutions(ctx context.Context, params *sfn.ListExecutionsInput, optFns ...func(*sfn.Options)) (*sfn.ListExecutionsOutput, error)
+}
+
+// StepFunctionsClient struct for Step Functions client
+type StepFunctionsClient struct {
+	client        sfnAPI
+	cwClient      cloudWatchMetricsAPI
+	region        string
+	idleThreshold int // in days
+}
+
+// NewStepFunctionsClient creates a new StepFunctionsClient
+func NewStepFunctionsClient(cfg aws.Config) *StepFunctionsClient {
+	return &StepFunctionsClient{
+		client:        sfn.NewFromConfig(cfg),
+		cwClient:      cloudwatch.NewFromConfig(cfg),
+		region:        cfg.Region,
+		idleThreshold: DefaultStepFunctionsIdleDays,
+	}
+}
+
+// SetIdleThreshold sets the number of days without an execution before a state machine is considered idle
+func (c *StepFunctionsClient) SetIdleThreshold(days int) {
+	c.idleThreshold = days
+}
+
+// GetStateMachines returns every state machine in the region with its last execution, flagging
+// those without an execution within the idle threshold as idle. State machines that could not
+// be checked are left out, and their errors are returned together.
+func (c *StepFunctionsClient) GetStateMachines(ctx context.Context) ([]models.StateMachineInfo, error) {
+	var machines []models.StateMachineInfo
+	var checkErrs []error
+
+	paginator := sfn.NewListStateMachinesPaginator(c.client, &sfn.ListStateMachinesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			checkErrs = append(checkErrs, fmt.Errorf("error listing state machines: %w", err))
+			break
+		}
+
+		for _, item := range page.StateMachines {
+			machine, err := c.analyzeStateMachine(ctx, item)
+			if err != nil {
+				checkErrs = append(checkErrs, err)
+				recordSkip("sfn", c.region, aws.ToString(item.Name), "execution history unavailable", err)
+				continue
+			}
+			machines = append(machines, machine)
+		}
+	}
+
+	return machines, errors.Join(checkErrs...)
+}
+
+// analyzeStateMachine finds the last execution of a state machine and whether it is idle.
+// Express workflows keep no execution history, so they are only checked with CloudWatch.
+// Standard workflows are also listed with ListExecutions, which returns the exact start time
+// of executions closed within the last 90 days.
+func (c *StepFunctionsClient) analyzeStateMachine(ctx context.Context, item sfntypes.StateMachineListItem) (models.StateMachineInfo, error) {
+	machine := models.StateMachineInfo{
+		Name:         aws.ToString(item.Name),
+		ARN:          aws.ToString(item.StateMachineArn),
+		Type:         string(item.Type),
+		Region:       c.region,
+		CreationDate: aws.ToTime(item.CreationDate),
+	}
+
+	lookbackDays := max(c.idleThreshold, sfnMinLookbackDays)
+	executions, lastExecution, err := c.getExecutionMetrics(ctx, machine.ARN, lookbackDays)
+	if err != nil {
+		return machine, fmt.Errorf("error getting execution metrics of state machine %s: %w", machine.Name, err)
+	}
+	machine.ExecutionsInWindow = executions
+	machine.LastExecution = lastExecution
+
+	if item.Type == sfntypes.StateMachineTypeStandard {
+		output, err := c.client.ListExecutions(ctx, &sfn.ListExecutionsInput{
+			StateMachineArn: item.StateMachineArn,
+			MaxResults:      1, // Executions are returned most recent first
+		})
+		if err != nil {
+			return machine, fmt.Errorf("error listing executions of state machine %s: %w", machine.Name, err)
+		}
+		if len(output.Executions) > 0 {
+			machine.LastExecution = output.Executions[0].StartDate
+		}
+	}
+
+	if machine.LastExecution != nil {
+		machine.IdleDays = utils.CalculateElapsedDays(*machine.LastExecution)
+	} else {
+		// Without an execution in the lookback, the state machine has been idle at least since
+		// its creation, up to the lookback
+		machine.IdleDays = min(utils.CalculateElapsedDays(machine.CreationDate), lookbackDays)
+	}
+	machine.IsIdle = machine.ExecutionsInWindow == 0 && machine.IdleDays >= c.idleThreshold
+
+	return machine, nil
+}
+
+// getExecutionMetrics returns the executions started within the idle threshold and the day of
+// the last execution within the lookback, from the daily ExecutionsStarted metric
+func (c *StepFunctionsClient) getExecutionMetrics(ctx context.Context, stateMachineArn string, lookbackDays int) (int64, *time.Time, error) {
+	endTime := time.Now()
+	windowStart := endTime.AddDate(0, 0, -c.idleThreshold)
+
+	datapoints, err := cloudwatchutil.Datapoints(ctx, c.cwClient, cloudwatchutil.MetricQuery{
+		Namespace:  "AWS/States",
+		Name:       "ExecutionsStarted",
+		Dimensions: []cwtypes.Dimension{cloudwatchutil.Dimension("StateMachineArn", stateMachineArn)},
+		Stat:       cwtypes.StatisticSum,
+		WindowDays: lookbackDays,
+		End:        endTime,
+		Period:     24 * time.Hour,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var executions int64
+	for _, datapoint := range datapoints {
+		if datapoint.Sum != nil && datapoint.Timestamp != nil && !datapoint.Timestamp.Before(windowStart) {
+			executions += int64(*datapoint.Sum)
+		}
+	}
+	lastExecution := cloudwatchutil.LastNonZeroTimestamp(datapoints, cwtypes.StatisticSum)
+
+	return executions, lastExecution, nil
+}
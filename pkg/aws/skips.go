@@ -0,0 +1,67 @@
+package aws
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// Resources the scanners could not evaluate, across every service and region
+var (
+	skippedMu sync.Mutex
+	skipped   []models.SkippedResource
+)
+
+// recordSkip records a resource left out of the results because it could not be evaluated.
+// A resource skipped again for the same reason, e.g., by the scan of another region, is
+// recorded once.
+func recordSkip(service, region, resource, reason string, err error) {
+	skip := models.SkippedResource{Service: service, Region: region, Resource: resource, Reason: reason}
+	if err != nil {
+		skip.Error = err.Error()
+	}
+
+	skippedMu.Lock()
+	defer skippedMu.Unlock()
+	for _, recorded := range skipped {
+		if recorded.Service == skip.Service && recorded.Region == skip.Region && recorded.Resource == skip.Resource && recorded.Reason == skip.Reason {
+			return
+		}
+	}
+	skipped = append(skipped, skip)
+}
+
+// SkippedResources returns the resources skipped since the start or the last
+// ResetSkippedResources, sorted by service, region, and resource
+func SkippedResources() []models.SkippedResource {
+	skippedMu.Lock()
+	resources := append([]models.SkippedResource(nil), skipped...)
+	skippedMu.Unlock()
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].Service != resources[j].Service {
+			return resources[i].Service < resources[j].Service
+		}
+		if resources[i].Region != resources[j].Region {
+			return resources[i].Region < resources[j].Region
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+	return resources
+}
+
+// SkippedCount returns the number of resources skipped since the start or the last
+// ResetSkippedResources
+func SkippedCount() int {
+	skippedMu.Lock()
+	defer skippedMu.Unlock()
+	return len(skipped)
+}
+
+// ResetSkippedResources clears the skipped resources, so that repeated scans are counted separately
+func ResetSkippedResources() {
+	skippedMu.Lock()
+	defer skippedMu.Unlock()
+	skipped = nil
+}
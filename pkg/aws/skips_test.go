@@ -0,0 +1,144 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdaTypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+// resetSkips clears the skipped resources now and when the test ends
+func resetSkips(t *testing.T) {
+	t.Helper()
+	ResetSkippedResources()
+	t.Cleanup(ResetSkippedResources)
+}
+
+// skipSummary returns "resource: reason" for each skipped resource
+func skipSummary(skips []models.SkippedResource) []string {
+	var summary []string
+	for _, skip := range skips {
+		summary = append(summary, skip.Resource+": "+skip.Reason)
+	}
+	return summary
+}
+
+func TestRecordSkip(t *testing.T) {
+	resetSkips(t)
+	denied := errors.New("access denied")
+
+	recordSkip("s3", "unknown", "bucket-b", "bucket location unavailable", denied)
+	recordSkip("lambda", "us-east-1", "worker", "metrics unavailable", nil)
+	recordSkip("s3", "unknown", "bucket-a", "bucket location unavailable", denied)
+	// The scan of another region finds the same bucket
+	recordSkip("s3", "unknown", "bucket-b", "bucket location unavailable", denied)
+
+	if got := SkippedCount(); got != 3 {
+		t.Errorf("SkippedCount() = %d, want 3", got)
+	}
+	skips := SkippedResources()
+	want := []string{"worker: metrics unavailable", "bucket-a: bucket location unavailable", "bucket-b: bucket location unavailable"}
+	if got := skipSummary(skips); !slices.Equal(got, want) {
+		t.Errorf("SkippedResources() = %v, want %v", got, want)
+	}
+	if skips[1].Error != "access denied" || skips[0].Error != "" {
+		t.Errorf("errors = %q, %q, want the recorded errors", skips[1].Error, skips[0].Error)
+	}
+
+	ResetSkippedResources()
+	if got := SkippedCount(); got != 0 {
+		t.Errorf("SkippedCount() after reset = %d, want 0", got)
+	}
+}
+
+func TestGetIdleFunctionsRecordsSkips(t *testing.T) {
+	resetSkips(t)
+	client := &LambdaClient{
+		client: &mocks.Lambda{
+			ListFunctionsFunc: func(ctx context.Context, params *lambda.ListFunctionsInput) (*lambda.ListFunctionsOutput, error) {
+				return &lambda.ListFunctionsOutput{Functions: []lambdaTypes.FunctionConfiguration{
+					{FunctionName: aws.String("worker"), FunctionArn: aws.String("arn:aws:lambda:us-east-1:123456789012:function:worker")},
+					{FunctionName: aws.String("broken"), FunctionArn: aws.String("arn:aws:lambda:us-east-1:123456789012:function:broken")},
+				}}, nil
+			},
+		},
+		cwClient: &mocks.CloudWatch{
+			GetMetricStatisticsFunc: func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error) {
+				if aws.ToString(params.Dimensions[0].Value) == "broken" {
+					return nil, errors.New("throttled")
+				}
+				return &cloudwatch.GetMetricStatisticsOutput{}, nil
+			},
+		},
+		region:        "us-east-1",
+		idleThreshold: 30,
+		failingRate:   DefaultLambdaFailingErrorRate,
+		pricing:       newTestPricing(),
+	}
+
+	functions, err := client.GetIdleFunctions(context.Background())
+
+	if err == nil {
+		t.Error("GetIdleFunctions() error = nil, want the metrics error")
+	}
+	if len(functions) != 1 || functions[0].FunctionName != "worker" {
+		t.Errorf("GetIdleFunctions() = %v, want worker only", functions)
+	}
+	if got, want := skipSummary(SkippedResources()), []string{"broken: metrics unavailable"}; !slices.Equal(got, want) {
+		t.Errorf("skipped %v, want %v", got, want)
+	}
+}
+
+func TestGetIdleBucketsRecordsSkips(t *testing.T) {
+	resetSkips(t)
+	fake := bucketWithoutConfig()
+	fake.ListBucketsFunc = func(ctx context.Context, params *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
+		created := aws.Time(time.Now().AddDate(-1, 0, 0))
+		return &s3.ListBucketsOutput{Buckets: []s3Types.Bucket{
+			{Name: aws.String("readable"), CreationDate: created},
+			{Name: aws.String("unlocated"), CreationDate: created},
+			{Name: aws.String("forbidden"), CreationDate: created},
+		}}, nil
+	}
+	fake.GetBucketLocationFunc = func(ctx context.Context, params *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+		if aws.ToString(params.Bucket) == "unlocated" {
+			return nil, errors.New("access denied")
+		}
+		return &s3.GetBucketLocationOutput{}, nil
+	}
+	fake.HeadBucketFunc = func(ctx context.Context, params *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+		if aws.ToString(params.Bucket) == "forbidden" {
+			return nil, errors.New("forbidden")
+		}
+		return &s3.HeadBucketOutput{}, nil
+	}
+	client := &S3Client{client: fake, cwClient: storageMetrics(nil), region: "us-east-1", idleThreshold: 30, pricing: newTestPricing()}
+
+	buckets, err := client.GetIdleBuckets(context.Background())
+
+	if err != nil {
+		t.Fatalf("GetIdleBuckets() error = %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].BucketName != "readable" {
+		t.Errorf("GetIdleBuckets() = %v, want readable only", buckets)
+	}
+	skips := SkippedResources()
+	want := []string{"unlocated: bucket location unavailable", "forbidden: bucket analysis failed"}
+	if got := skipSummary(skips); !slices.Equal(got, want) {
+		t.Errorf("skipped %v, want %v", got, want)
+	}
+	if len(skips) == 2 && (skips[0].Region != "unknown" || skips[1].Region != "us-east-1") {
+		t.Errorf("skip regions = %s, %s, want unknown and us-east-1", skips[0].Region, skips[1].Region)
+	}
+}
@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+)
+
+// CallerIdentity is the AWS identity the credentials belong to
+type CallerIdentity struct {
+	Account string // AWS account ID
+	ARN     string // ARN of the calling IAM user or role
+}
+
+// GetCallerIdentity returns the account and ARN of the identity the credentials belong to
+func GetCallerIdentity(ctx context.Context, region string) (CallerIdentity, error) {
+	cfg, err := LoadConfig(ctx, region)
+	if err != nil {
+		return CallerIdentity{}, fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
+	}
+	output, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return CallerIdentity{}, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	return CallerIdentity{Account: aws.ToString(output.Account), ARN: aws.ToString(output.Arn)}, nil
+}
+
+// CredentialHint returns an actionable message for the common reasons a credentials check
+// fails: missing, expired or invalid credentials, an expired SSO session, and an unreachable
+// endpoint. It returns an empty string if the error is not recognized.
+func CredentialHint(err error, profile, region string) string {
+	login := "aws sso login"
+	if profile != "" {
+		login += " --profile " + profile
+	}
+	message := err.Error()
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ExpiredToken", "ExpiredTokenException", "RequestExpired":
+			return fmt.Sprintf("AWS credentials expired — refresh them, e.g. run `%s`", login)
+		case "InvalidClientTokenId", "UnrecognizedClientException", "SignatureDoesNotMatch":
+			return "AWS credentials are invalid — check the access key of the selected profile"
+		}
+	}
+
+	switch {
+	case strings.Contains(message, "SSO") && (strings.Contains(message, "expired") || strings.Contains(message, "token")):
+		return fmt.Sprintf("AWS SSO session expired — run `%s`", login)
+	case strings.Contains(message, "failed to refresh cached credentials") ||
+		strings.Contains(message, "no valid providers") ||
+		strings.Contains(message, "NoCredentialProviders"):
+		return "No AWS credentials found — set AWS_PROFILE, use --profile, or configure credentials with `aws configure`"
+	}
+
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	if errors.As(err, &dnsErr) || errors.As(err, &opErr) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("AWS STS endpoint in region %s is unreachable — check the network connection and the region", region)
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package aws
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// matchesTagFilters reports whether tags contain every requested key/value pair.
+// An empty filter set matches every resource.
+func matchesTagFilters(tags, filters map[string]string) bool {
+	for key, value := range filters {
+		if tagValue, found := tags[key]; !found || tagValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ec2TagFilters converts tag filters into EC2 API filters (tag:Key = Value) so that
+// filtering happens server-side. Keys are sorted for deterministic requests.
+func ec2TagFilters(filters map[string]string) []types.Filter {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ec2Filters := make([]types.Filter, 0, len(keys))
+	for _, key := range keys {
+		ec2Filters = append(ec2Filters, types.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: []string{filters[key]},
+		})
+	}
+	return ec2Filters
+}
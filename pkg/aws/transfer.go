@@ -0,0 +1,115 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/transfer"
+	transfertypes "github.com/aws/aws-sdk-go-v2/service/transfer/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/cloudwatchutil"
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+const (
+	transferCheckPeriodDays = 30
+	transferNamespace       = "AWS/Transfer"
+)
+
+// transferFileMetrics are the metrics summed to count the files transferred by a server
+var transferFileMetrics = []string{"FilesIn", "FilesOut"}
+
+// transferAPI is the Transfer Family API used by TransferScanner
+type transferAPI interface {
+	transfer.ListServersAPIClient
+	DescribeServer(ctx context.Context, params *transfer.DescribeServerInput, optFns ...func(*transfer.Options)) (*transfer.DescribeServerOutput, error)
+}
+
+// TransferScanner contains the AWS clients needed for scanning Transfer Family servers
+type TransferScanner struct {
+	TransferClient transferAPI
+	CWClient       cloudWatchMetricsAPI
+	Region         string
+}
+
+// NewTransferScanner creates a new TransferScanner for a given region
+func NewTransferScanner(cfg aws.Config) *TransferScanner {
+	return &TransferScanner{
+		TransferClient: transfer.NewFromConfig(cfg),
+		CWClient:       cloudwatch.NewFromConfig(cfg),
+		Region:         cfg.Region,
+	}
+}
+
+// GetIdleTransferServers returns the Transfer Family servers that transferred no files over the
+// last 30 days. Stopped servers are included, since their endpoint is billed until deleted.
+func (s *TransferScanner) GetIdleTransferServers(ctx context.Context) ([]models.TransferServerInfo, []error) {
+	var idle []models.TransferServerInfo
+	var errs []error
+
+	paginator := transfer.NewListServersPaginator(s.TransferClient, &transfer.ListServersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing Transfer Family servers in region %s: %w", s.Region, err))
+			break
+		}
+
+		for _, server := range page.Servers {
+			info, err := s.checkServer(ctx, server)
+			if err != nil {
+				errs = append(errs, err)
+				recordSkip("transfer", s.Region, aws.ToString(server.ServerId), "metrics unavailable", err)
+				continue
+			}
+			if info != nil {
+				idle = append(idle, *info)
+			}
+		}
+	}
+
+	return idle, errs
+}
+
+// checkServer returns the server info if the server transferred no files over the check period, or nil otherwise
+func (s *TransferScanner) checkServer(ctx context.Context, server transfertypes.ListedServer) (*models.TransferServerInfo, error) {
+	serverID := aws.ToString(server.ServerId)
+
+	filesTransferred, err := getMetricsSum(ctx, s.CWClient, transferNamespace, transferFileMetrics, transferCheckPeriodDays, cloudwatchutil.Dimension("ServerId", serverID))
+	if err != nil {
+		return nil, fmt.Errorf("error getting the file metrics of Transfer Family server %s: %w", serverID, err)
+	}
+	if filesTransferred != 0 {
+		return nil, nil
+	}
+
+	// The protocols, which the endpoint is billed for, are only returned by DescribeServer
+	output, err := s.TransferClient.DescribeServer(ctx, &transfer.DescribeServerInput{ServerId: server.ServerId})
+	if err != nil {
+		return nil, fmt.Errorf("error describing Transfer Family server %s: %w", serverID, err)
+	}
+	var protocols []string
+	if output.Server != nil {
+		for _, protocol := range output.Server.Protocols {
+			protocols = append(protocols, string(protocol))
+		}
+	}
+
+	return &models.TransferServerInfo{
+		ServerID:             serverID,
+		ARN:                  aws.ToString(server.Arn),
+		Region:               s.Region,
+		EndpointType:         string(server.EndpointType),
+		Domain:               string(server.Domain),
+		Protocols:            protocols,
+		State:                string(server.State),
+		UserCount:            int(aws.ToInt32(server.UserCount)),
+		FilesTransferred:     filesTransferred,
+		IdleReason:           fmt.Sprintf("No files transferred (%dd)", transferCheckPeriodDays),
+		EstimatedMonthlyCost: float64(len(protocols)) * pricing.TransferFamilyHourlyPricePerProtocol * utils.GetMonthlyHours(),
+	}, nil
+}
@@ -0,0 +1,139 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/workspaces"
+	wstypes "github.com/aws/aws-sdk-go-v2/service/workspaces/types"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/pricing"
+)
+
+const (
+	// DefaultWorkSpacesIdleDays is the default number of days without a user connection before a WorkSpace is idle
+	DefaultWorkSpacesIdleDays = 30
+)
+
+// workSpacesAPI is the WorkSpaces API used by WorkSpacesScanner
+type workSpacesAPI interface {
+	workspaces.DescribeWorkspacesAPIClient
+	DescribeWorkspacesConnectionStatus(ctx context.Context, params *workspaces.DescribeWorkspacesConnectionStatusInput, optFns ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesConnectionStatusOutput, error)
+}
+
+// WorkSpacesScanner contains the AWS client needed for scanning WorkSpaces
+type WorkSpacesScanner struct {
+	Client        workSpacesAPI
+	Region        string
+	IdleThreshold int // in days
+}
+
+// NewWorkSpacesScanner creates a new WorkSpacesScanner for a given region
+func NewWorkSpacesScanner(cfg aws.Config) *WorkSpacesScanner {
+	return &WorkSpacesScanner{
+		Client:        workspaces.NewFromConfig(cfg),
+		Region:        cfg.Region,
+		IdleThreshold: DefaultWorkSpacesIdleDays,
+	}
+}
+
+// SetIdleThreshold sets the number of days without a user connection before a WorkSpace is considered idle
+func (s *WorkSpacesScanner) SetIdleThreshold(days int) {
+	s.IdleThreshold = days
+}
+
+// GetIdleWorkSpaces returns the WorkSpaces whose user last connected at least IdleThreshold
+// days ago, or never connected
+func (s *WorkSpacesScanner) GetIdleWorkSpaces(ctx context.Context) ([]models.WorkSpaceInfo, []error) {
+	connections, err := s.getLastConnections(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var idle []models.WorkSpaceInfo
+	var scanErrs []error
+
+	now := time.Now()
+	paginator := workspaces.NewDescribeWorkspacesPaginator(s.Client, &workspaces.DescribeWorkspacesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			scanErrs = append(scanErrs, fmt.Errorf("error listing WorkSpaces in region %s: %w", s.Region, err))
+			break
+		}
+
+		for _, workspace := range page.Workspaces {
+			if info, ok := s.checkWorkSpace(workspace, connections[aws.ToString(workspace.WorkspaceId)], now); ok {
+				idle = append(idle, info)
+			}
+		}
+	}
+
+	return idle, scanErrs
+}
+
+// getLastConnections returns the last known user connection of every WorkSpace in the region,
+// keyed by WorkSpace ID. WorkSpaces the user never connected to have a nil timestamp.
+func (s *WorkSpacesScanner) getLastConnections(ctx context.Context) (map[string]*time.Time, error) {
+	connections := make(map[string]*time.Time)
+
+	input := &workspaces.DescribeWorkspacesConnectionStatusInput{}
+	for {
+		output, err := s.Client.DescribeWorkspacesConnectionStatus(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error describing WorkSpaces connection status in region %s: %w", s.Region, err)
+		}
+
+		for _, status := range output.WorkspacesConnectionStatus {
+			connections[aws.ToString(status.WorkspaceId)] = status.LastKnownUserConnectionTimestamp
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return connections, nil
+}
+
+// checkWorkSpace returns the WorkSpace info if nobody connected to it within the idle threshold.
+// WorkSpaces that are being created or terminated are skipped.
+func (s *WorkSpacesScanner) checkWorkSpace(workspace wstypes.Workspace, lastConnection *time.Time, now time.Time) (models.WorkSpaceInfo, bool) {
+	switch workspace.State {
+	case wstypes.WorkspaceStatePending, wstypes.WorkspaceStateTerminating, wstypes.WorkspaceStateTerminated:
+		return models.WorkSpaceInfo{}, false
+	}
+
+	idleDays := 0
+	if lastConnection != nil {
+		idleDays = int(now.Sub(*lastConnection).Hours() / 24)
+		if idleDays < s.IdleThreshold {
+			return models.WorkSpaceInfo{}, false
+		}
+	}
+
+	info := models.WorkSpaceInfo{
+		WorkspaceID:    aws.ToString(workspace.WorkspaceId),
+		UserName:       aws.ToString(workspace.UserName),
+		DirectoryID:    aws.ToString(workspace.DirectoryId),
+		BundleID:       aws.ToString(workspace.BundleId),
+		State:          string(workspace.State),
+		Region:         s.Region,
+		LastConnection: lastConnection,
+		IdleDays:       idleDays,
+	}
+	if workspace.WorkspaceProperties != nil {
+		info.ComputeType = string(workspace.WorkspaceProperties.ComputeTypeName)
+		info.RunningMode = string(workspace.WorkspaceProperties.RunningMode)
+	}
+
+	savings, source := pricing.GetWorkSpacesMonthlySavingsWithSource(info.ComputeType, info.RunningMode)
+	info.EstimatedMonthlyCost = savings
+	info.PricingSource = source
+
+	return info, true
+}
@@ -0,0 +1,290 @@
+// Package cloudwatchutil reads CloudWatch metric statistics for the scanners. It builds the
+// GetMetricStatistics and GetMetricData requests of a metric window and reduces the returned
+// datapoints, so that the scanners only decide what the numbers mean.
+package cloudwatchutil
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// StatisticsAPI is the CloudWatch API the window helpers read metric statistics with
+type StatisticsAPI interface {
+	GetMetricStatistics(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricStatisticsOutput, error)
+}
+
+// MetricQuery describes a statistic of a metric over a window of whole days
+type MetricQuery struct {
+	Namespace  string
+	Name       string
+	Dimensions []types.Dimension
+	Stat       types.Statistic
+	ExtraStats []types.Statistic // Also requested, for callers reading several statistics of each datapoint
+	WindowDays int               // Length of the window, in days
+	End        time.Time         // End of the window, now if zero
+	Period     time.Duration     // Datapoint period, a single period covering the window if zero
+}
+
+// Dimension returns a metric dimension with the given name and value
+func Dimension(name, value string) types.Dimension {
+	return types.Dimension{Name: aws.String(name), Value: aws.String(value)}
+}
+
+// window returns the start and end of the query window
+func (q MetricQuery) window() (time.Time, time.Time) {
+	end := q.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.AddDate(0, 0, -q.WindowDays), end
+}
+
+// periodSeconds returns the datapoint period of the query in seconds
+func (q MetricQuery) periodSeconds() int32 {
+	if q.Period == 0 {
+		return int32(q.WindowDays * 24 * 60 * 60)
+	}
+	return int32(q.Period / time.Second)
+}
+
+// Datapoints returns the datapoints of the query sorted by timestamp, oldest first. A metric
+// that published nothing over the window has no datapoints and no error.
+func Datapoints(ctx context.Context, client StatisticsAPI, q MetricQuery) ([]types.Datapoint, error) {
+	start, end := q.window()
+	output, err := client.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(q.Namespace),
+		MetricName: aws.String(q.Name),
+		Dimensions: q.Dimensions,
+		StartTime:  aws.Time(start),
+		EndTime:    aws.Time(end),
+		Period:     aws.Int32(q.periodSeconds()),
+		Statistics: append([]types.Statistic{q.Stat}, q.ExtraStats...),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	datapoints := output.Datapoints
+	slices.SortStableFunc(datapoints, func(a, b types.Datapoint) int {
+		return aws.ToTime(a.Timestamp).Compare(aws.ToTime(b.Timestamp))
+	})
+	return datapoints, nil
+}
+
+// StatValue returns a statistic of a datapoint and whether the datapoint carries it
+func StatValue(datapoint types.Datapoint, stat types.Statistic) (float64, bool) {
+	var value *float64
+	switch stat {
+	case types.StatisticSum:
+		value = datapoint.Sum
+	case types.StatisticAverage:
+		value = datapoint.Average
+	case types.StatisticMaximum:
+		value = datapoint.Maximum
+	case types.StatisticMinimum:
+		value = datapoint.Minimum
+	case types.StatisticSampleCount:
+		value = datapoint.SampleCount
+	}
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
+
+// Sum adds up a statistic over the datapoints
+func Sum(datapoints []types.Datapoint, stat types.Statistic) float64 {
+	var total float64
+	for _, datapoint := range datapoints {
+		value, _ := StatValue(datapoint, stat)
+		total += value
+	}
+	return total
+}
+
+// SumOverWindow returns the query statistic added up over the window, zero without datapoints.
+// A single period covering the window may still be split into two datapoints depending on its
+// alignment, so all of them are added up.
+func SumOverWindow(ctx context.Context, client StatisticsAPI, q MetricQuery) (float64, error) {
+	datapoints, err := Datapoints(ctx, client, q)
+	if err != nil {
+		return 0, err
+	}
+	return Sum(datapoints, q.Stat), nil
+}
+
+// AverageOverWindow returns the mean of the query statistic across the datapoints of the window,
+// or nil without datapoints
+func AverageOverWindow(ctx context.Context, client StatisticsAPI, q MetricQuery) (*float64, error) {
+	datapoints, err := Datapoints(ctx, client, q)
+	if err != nil {
+		return nil, err
+	}
+	return Average(datapoints, q.Stat), nil
+}
+
+// MaxOverWindow returns the largest query statistic of the window, or nil without datapoints
+func MaxOverWindow(ctx context.Context, client StatisticsAPI, q MetricQuery) (*float64, error) {
+	datapoints, err := Datapoints(ctx, client, q)
+	if err != nil {
+		return nil, err
+	}
+	return Max(datapoints, q.Stat), nil
+}
+
+// ValueOverWindow returns the query statistic over the window, or nil without datapoints:
+// the Sum and SampleCount datapoints are added up, the Maximum the largest one, and the
+// other statistics averaged
+func ValueOverWindow(ctx context.Context, client StatisticsAPI, q MetricQuery) (*float64, error) {
+	datapoints, err := Datapoints(ctx, client, q)
+	if err != nil || len(datapoints) == 0 {
+		return nil, err
+	}
+
+	switch q.Stat {
+	case types.StatisticSum, types.StatisticSampleCount:
+		total := Sum(datapoints, q.Stat)
+		return &total, nil
+	case types.StatisticMaximum:
+		return Max(datapoints, q.Stat), nil
+	default:
+		return Average(datapoints, q.Stat), nil
+	}
+}
+
+// Average returns the mean of a statistic across the datapoints carrying it, or nil without one
+func Average(datapoints []types.Datapoint, stat types.Statistic) *float64 {
+	var total float64
+	var count int
+	for _, datapoint := range datapoints {
+		if value, ok := StatValue(datapoint, stat); ok {
+			total += value
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	average := total / float64(count)
+	return &average
+}
+
+// Max returns the largest statistic of the datapoints, or nil without one
+func Max(datapoints []types.Datapoint, stat types.Statistic) *float64 {
+	var largest *float64
+	for _, datapoint := range datapoints {
+		if value, ok := StatValue(datapoint, stat); ok && (largest == nil || value > *largest) {
+			largest = &value
+		}
+	}
+	return largest
+}
+
+// Latest returns the statistic of the most recent datapoint carrying it, or nil without one.
+// The datapoints must be sorted oldest first, as returned by Datapoints.
+func Latest(datapoints []types.Datapoint, stat types.Statistic) *float64 {
+	for i := len(datapoints) - 1; i >= 0; i-- {
+		if value, ok := StatValue(datapoints[i], stat); ok {
+			return &value
+		}
+	}
+	return nil
+}
+
+// LastNonZeroTimestamp returns the timestamp of the most recent datapoint with a positive
+// statistic, or nil if there is none. The datapoints must be sorted oldest first.
+func LastNonZeroTimestamp(datapoints []types.Datapoint, stat types.Statistic) *time.Time {
+	for i := len(datapoints) - 1; i >= 0; i-- {
+		if value, ok := StatValue(datapoints[i], stat); ok && value > 0 && datapoints[i].Timestamp != nil {
+			return datapoints[i].Timestamp
+		}
+	}
+	return nil
+}
+
+// FirstNonZeroTimestamp returns the timestamp of the oldest datapoint with a positive statistic,
+// or nil if there is none. The datapoints must be sorted oldest first.
+func FirstNonZeroTimestamp(datapoints []types.Datapoint, stat types.Statistic) *time.Time {
+	for _, datapoint := range datapoints {
+		if value, ok := StatValue(datapoint, stat); ok && value > 0 && datapoint.Timestamp != nil {
+			return datapoint.Timestamp
+		}
+	}
+	return nil
+}
+
+// maxDataQueries is the number of queries GetMetricData accepts per call
+const maxDataQueries = 500
+
+// Series holds the datapoints GetMetricData returned for one query
+type Series struct {
+	Timestamps []time.Time
+	Values     []float64
+}
+
+// MetricData runs the queries with GetMetricData between start and end, in calls of at most 500
+// queries, and returns the series of each query, indexed like queries. The window of the queries
+// is ignored, and a zero Period covers the whole range.
+func MetricData(ctx context.Context, client cloudwatch.GetMetricDataAPIClient, queries []MetricQuery, start, end time.Time) ([]Series, error) {
+	series := make([]Series, len(queries))
+
+	for batchStart := 0; batchStart < len(queries); batchStart += maxDataQueries {
+		batchEnd := min(batchStart+maxDataQueries, len(queries))
+
+		dataQueries := make([]types.MetricDataQuery, 0, batchEnd-batchStart)
+		ids := make(map[string]int, batchEnd-batchStart)
+		for i := batchStart; i < batchEnd; i++ {
+			q := queries[i]
+			period := int32(q.Period / time.Second)
+			if q.Period == 0 {
+				period = int32(end.Sub(start) / time.Second)
+			}
+
+			id := fmt.Sprintf("m%d", i)
+			ids[id] = i
+			dataQueries = append(dataQueries, types.MetricDataQuery{
+				Id: aws.String(id),
+				MetricStat: &types.MetricStat{
+					Metric: &types.Metric{
+						Namespace:  aws.String(q.Namespace),
+						MetricName: aws.String(q.Name),
+						Dimensions: q.Dimensions,
+					},
+					Period: aws.Int32(period),
+					Stat:   aws.String(string(q.Stat)),
+				},
+				ReturnData: aws.Bool(true),
+			})
+		}
+
+		paginator := cloudwatch.NewGetMetricDataPaginator(client, &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: dataQueries,
+			StartTime:         aws.Time(start),
+			EndTime:           aws.Time(end),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, result := range page.MetricDataResults {
+				i, found := ids[aws.ToString(result.Id)]
+				if !found {
+					continue
+				}
+				n := min(len(result.Timestamps), len(result.Values))
+				series[i].Timestamps = append(series[i].Timestamps, result.Timestamps[:n]...)
+				series[i].Values = append(series[i].Values, result.Values[:n]...)
+			}
+		}
+	}
+
+	return series, nil
+}
@@ -0,0 +1,268 @@
+package cloudwatchutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/younsl/idled/pkg/aws/mocks"
+)
+
+var end = time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+
+// day returns the timestamp of the given day of June 2026
+func day(d int) *time.Time {
+	return aws.Time(time.Date(2026, 6, d, 0, 0, 0, 0, time.UTC))
+}
+
+// sum returns a datapoint of the given day carrying only a Sum
+func sum(d int, value float64) types.Datapoint {
+	return types.Datapoint{Timestamp: day(d), Sum: aws.Float64(value)}
+}
+
+// statistics answers GetMetricStatistics with the datapoints, recording the last input
+func statistics(input **cloudwatch.GetMetricStatisticsInput, datapoints ...types.Datapoint) *mocks.CloudWatch {
+	return &mocks.CloudWatch{
+		GetMetricStatisticsFunc: func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			if input != nil {
+				*input = params
+			}
+			return &cloudwatch.GetMetricStatisticsOutput{Datapoints: slices.Clone(datapoints)}, nil
+		},
+	}
+}
+
+func TestDatapoints(t *testing.T) {
+	var input *cloudwatch.GetMetricStatisticsInput
+	client := statistics(&input, sum(3, 3), sum(1, 1), sum(2, 2))
+
+	datapoints, err := Datapoints(context.Background(), client, MetricQuery{
+		Namespace:  "AWS/Lambda",
+		Name:       "Invocations",
+		Dimensions: []types.Dimension{Dimension("FunctionName", "worker")},
+		Stat:       types.StatisticSum,
+		ExtraStats: []types.Statistic{types.StatisticMaximum},
+		WindowDays: 14,
+		End:        end,
+		Period:     24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Datapoints() error = %v", err)
+	}
+
+	var days []int
+	for _, datapoint := range datapoints {
+		days = append(days, datapoint.Timestamp.Day())
+	}
+	if !slices.Equal(days, []int{1, 2, 3}) {
+		t.Errorf("datapoint days = %v, want oldest first", days)
+	}
+	if got := aws.ToTime(input.StartTime); !got.Equal(end.AddDate(0, 0, -14)) {
+		t.Errorf("StartTime = %v, want 14 days before the end", got)
+	}
+	if got := aws.ToInt32(input.Period); got != 86400 {
+		t.Errorf("Period = %d, want 86400", got)
+	}
+	if !slices.Equal(input.Statistics, []types.Statistic{types.StatisticSum, types.StatisticMaximum}) {
+		t.Errorf("Statistics = %v, want Sum and Maximum", input.Statistics)
+	}
+	if got := aws.ToString(input.Dimensions[0].Value); got != "worker" {
+		t.Errorf("dimension value = %s, want worker", got)
+	}
+}
+
+func TestDatapointsSinglePeriod(t *testing.T) {
+	var input *cloudwatch.GetMetricStatisticsInput
+	if _, err := Datapoints(context.Background(), statistics(&input), MetricQuery{Stat: types.StatisticSum, WindowDays: 30, End: end}); err != nil {
+		t.Fatalf("Datapoints() error = %v", err)
+	}
+
+	if got := aws.ToInt32(input.Period); got != 30*86400 {
+		t.Errorf("Period = %d, want the whole 30-day window", got)
+	}
+}
+
+func TestDatapointsError(t *testing.T) {
+	client := &mocks.CloudWatch{
+		GetMetricStatisticsFunc: func(ctx context.Context, params *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+
+	if _, err := SumOverWindow(context.Background(), client, MetricQuery{Stat: types.StatisticSum, WindowDays: 1}); err == nil {
+		t.Error("SumOverWindow() error = nil, want the API error")
+	}
+	if value, err := ValueOverWindow(context.Background(), client, MetricQuery{Stat: types.StatisticAverage, WindowDays: 1}); err == nil || value != nil {
+		t.Errorf("ValueOverWindow() = %v, %v, want nil and the API error", value, err)
+	}
+}
+
+func TestReductions(t *testing.T) {
+	datapoints := []types.Datapoint{
+		{Timestamp: day(1), Sum: aws.Float64(0), Average: aws.Float64(2)},
+		{Timestamp: day(2), Sum: aws.Float64(5), Maximum: aws.Float64(9)},
+		{Timestamp: day(3), Sum: aws.Float64(0), Average: aws.Float64(4)},
+		{Timestamp: day(4), Sum: aws.Float64(3)},
+		{Timestamp: day(5), Sum: aws.Float64(0)},
+	}
+
+	if got := Sum(datapoints, types.StatisticSum); got != 8 {
+		t.Errorf("Sum() = %v, want 8", got)
+	}
+	if got := Average(datapoints, types.StatisticAverage); got == nil || *got != 3 {
+		t.Errorf("Average() = %v, want 3 over the datapoints carrying it", got)
+	}
+	if got := Max(datapoints, types.StatisticMaximum); got == nil || *got != 9 {
+		t.Errorf("Max() = %v, want 9", got)
+	}
+	if got := Latest(datapoints, types.StatisticAverage); got == nil || *got != 4 {
+		t.Errorf("Latest() = %v, want 4", got)
+	}
+	if got := LastNonZeroTimestamp(datapoints, types.StatisticSum); got == nil || got.Day() != 4 {
+		t.Errorf("LastNonZeroTimestamp() = %v, want day 4", got)
+	}
+	if got := FirstNonZeroTimestamp(datapoints, types.StatisticSum); got == nil || got.Day() != 2 {
+		t.Errorf("FirstNonZeroTimestamp() = %v, want day 2", got)
+	}
+
+	// Without datapoints carrying the statistic
+	if got := Average(datapoints, types.StatisticMinimum); got != nil {
+		t.Errorf("Average() of a missing statistic = %v, want nil", *got)
+	}
+	if got := Max(nil, types.StatisticMaximum); got != nil {
+		t.Errorf("Max() without datapoints = %v, want nil", *got)
+	}
+	if got := Latest(nil, types.StatisticSum); got != nil {
+		t.Errorf("Latest() without datapoints = %v, want nil", *got)
+	}
+	zeros := []types.Datapoint{sum(1, 0), sum(2, 0)}
+	if got := LastNonZeroTimestamp(zeros, types.StatisticSum); got != nil {
+		t.Errorf("LastNonZeroTimestamp() of zeros = %v, want nil", got)
+	}
+	if got := FirstNonZeroTimestamp(zeros, types.StatisticSum); got != nil {
+		t.Errorf("FirstNonZeroTimestamp() of zeros = %v, want nil", got)
+	}
+}
+
+func TestValueOverWindow(t *testing.T) {
+	// A single period covering the window split into two datapoints
+	datapoints := []types.Datapoint{
+		{Timestamp: day(1), Sum: aws.Float64(10), SampleCount: aws.Float64(2), Maximum: aws.Float64(7), Average: aws.Float64(1)},
+		{Timestamp: day(2), Sum: aws.Float64(20), SampleCount: aws.Float64(3), Maximum: aws.Float64(4), Average: aws.Float64(3)},
+	}
+
+	tests := []struct {
+		stat types.Statistic
+		want float64
+	}{
+		{stat: types.StatisticSum, want: 30},
+		{stat: types.StatisticSampleCount, want: 5},
+		{stat: types.StatisticMaximum, want: 7},
+		{stat: types.StatisticAverage, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.stat), func(t *testing.T) {
+			got, err := ValueOverWindow(context.Background(), statistics(nil, datapoints...), MetricQuery{Stat: tt.stat, WindowDays: 30})
+			if err != nil {
+				t.Fatalf("ValueOverWindow() error = %v", err)
+			}
+			if got == nil || *got != tt.want {
+				t.Errorf("ValueOverWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, err := ValueOverWindow(context.Background(), statistics(nil), MetricQuery{Stat: types.StatisticSum, WindowDays: 30}); err != nil || got != nil {
+		t.Errorf("ValueOverWindow() without datapoints = %v, %v, want nil", got, err)
+	}
+}
+
+func TestWindowHelpers(t *testing.T) {
+	client := statistics(nil, sum(2, 4), sum(1, 6))
+	q := MetricQuery{Stat: types.StatisticSum, WindowDays: 30}
+
+	if got, err := SumOverWindow(context.Background(), client, q); err != nil || got != 10 {
+		t.Errorf("SumOverWindow() = %v, %v, want 10", got, err)
+	}
+	if got, err := AverageOverWindow(context.Background(), client, q); err != nil || got == nil || *got != 5 {
+		t.Errorf("AverageOverWindow() = %v, %v, want 5", got, err)
+	}
+	if got, err := MaxOverWindow(context.Background(), client, q); err != nil || got == nil || *got != 6 {
+		t.Errorf("MaxOverWindow() = %v, %v, want 6", got, err)
+	}
+}
+
+func TestMetricData(t *testing.T) {
+	var calls, batchSizes []int
+	client := &mocks.CloudWatch{
+		GetMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+			calls = append(calls, len(params.MetricDataQueries))
+			output := &cloudwatch.GetMetricDataOutput{}
+			// The first page returns the first query of the batch, the second page the rest
+			queries := params.MetricDataQueries
+			if params.NextToken == nil {
+				batchSizes = append(batchSizes, len(queries))
+				queries = queries[:1]
+				output.NextToken = aws.String("page-2")
+			} else {
+				queries = queries[1:]
+			}
+			for _, query := range queries {
+				var i int
+				fmt.Sscanf(aws.ToString(query.Id), "m%d", &i)
+				output.MetricDataResults = append(output.MetricDataResults, types.MetricDataResult{
+					Id:         query.Id,
+					Timestamps: []time.Time{*day(1), *day(2)},
+					Values:     []float64{float64(i), float64(i) + 0.5},
+				})
+			}
+			// A result of an unknown query is ignored
+			output.MetricDataResults = append(output.MetricDataResults, types.MetricDataResult{Id: aws.String("other"), Values: []float64{1}})
+			return output, nil
+		},
+	}
+
+	queries := make([]MetricQuery, 501)
+	for i := range queries {
+		queries[i] = MetricQuery{Namespace: "AWS/S3", Name: "NumberOfObjects", Stat: types.StatisticAverage}
+	}
+	series, err := MetricData(context.Background(), client, queries, end.AddDate(0, 0, -1), end)
+	if err != nil {
+		t.Fatalf("MetricData() error = %v", err)
+	}
+
+	if !slices.Equal(batchSizes, []int{500, 1}) {
+		t.Errorf("batch sizes = %v, want 500 and 1", batchSizes)
+	}
+	if len(calls) != 4 {
+		t.Errorf("made %d calls, want 2 pages per batch", len(calls))
+	}
+	if len(series) != 501 {
+		t.Fatalf("got %d series, want one per query", len(series))
+	}
+	for _, i := range []int{0, 1, 499, 500} {
+		if want := []float64{float64(i), float64(i) + 0.5}; !slices.Equal(series[i].Values, want) || len(series[i].Timestamps) != 2 {
+			t.Errorf("series %d = %+v, want values %v", i, series[i], want)
+		}
+	}
+}
+
+func TestMetricDataError(t *testing.T) {
+	client := &mocks.CloudWatch{
+		GetMetricDataFunc: func(ctx context.Context, params *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+
+	if _, err := MetricData(context.Background(), client, []MetricQuery{{Stat: types.StatisticSum}}, end.AddDate(0, 0, -1), end); err == nil {
+		t.Error("MetricData() error = nil, want the API error")
+	}
+}
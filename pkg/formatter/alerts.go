@@ -0,0 +1,69 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/younsl/idled/internal/alert"
+	"github.com/younsl/idled/internal/models"
+)
+
+// alertMarker is appended to the cost cells of idle resources above --alert-cost-per-resource
+const alertMarker = " (!)"
+
+// alertColumnKeys are the columns marked for idle resources above the per-resource threshold
+var alertColumnKeys = []string{"cost", "savings"}
+
+// alertCostPerResource is the monthly cost above which an idle resource is marked, 0 to disable
+var alertCostPerResource float64
+
+// SetAlertCostPerResource sets the monthly cost above which idle resources are marked in the tables
+func SetAlertCostPerResource(threshold float64) {
+	alertCostPerResource = threshold
+}
+
+// exceedsCostAlert reports whether a resource is idle and costs more than --alert-cost-per-resource
+func exceedsCostAlert(resource any) bool {
+	if alertCostPerResource <= 0 {
+		return false
+	}
+	costed, ok := resource.(models.Costed)
+	if !ok || costed.MonthlyCost() <= alertCostPerResource {
+		return false
+	}
+	if flagged, ok := resource.(models.IdleFlagged); ok && !flagged.IdleFlag() {
+		return false
+	}
+	if priced, ok := resource.(models.Priced); ok && priced.PricingUnavailable() {
+		return false
+	}
+	return true
+}
+
+// PrintAlerts prints the idle resources above the per-resource threshold and a warning when
+// the total monthly cost exceeds the total threshold. Nothing is printed if no threshold was exceeded.
+func PrintAlerts(writer io.Writer, alerts alert.Alerts) {
+	if !alerts.Any() {
+		return
+	}
+
+	fmt.Fprintln(writer, "\n## Alerts")
+
+	if len(alerts.Resources) > 0 {
+		fmt.Fprintf(writer, "%d idle resource(s) cost more than $%.2f per month%s:\n\n",
+			len(alerts.Resources), alerts.Thresholds.CostPerResource, alertMarker)
+
+		w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "SERVICE\tREGION\tRESOURCE\tCOST/MO")
+		for _, resource := range alerts.Resources {
+			fmt.Fprintf(w, "%s\t%s\t%s\t$%.2f\n", resource.Service, resource.Region, resource.Name, resource.MonthlyCost)
+		}
+		w.Flush()
+	}
+
+	if alerts.TotalExceeded {
+		fmt.Fprintf(writer, "\n🚨 ALERT: Idle resources cost $%.2f per month across all services, above the $%.2f alert threshold.\n",
+			alerts.TotalCost, alerts.Thresholds.TotalCost)
+	}
+}
@@ -0,0 +1,143 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// asgIdleThreshold is the number of days without a scaling activity used by the Auto Scaling scan
+var asgIdleThreshold = 30
+
+// SetASGIdleThreshold sets the idle threshold shown in the Auto Scaling summary
+func SetASGIdleThreshold(days int) {
+	asgIdleThreshold = days
+}
+
+// PrintASGTable prints the idle Auto Scaling groups in a table format
+func PrintASGTable(writer io.Writer, groups []models.AutoScalingGroupInfo, _ time.Time, _ time.Duration) {
+	if len(groups) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort zero-capacity groups first, then by idle days (descending)
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].ZeroCapacity != groups[j].ZeroCapacity {
+			return groups[i].ZeroCapacity
+		}
+		return groups[i].IdleDays > groups[j].IdleDays
+	})
+
+	asgTable.Print(writer, groups)
+}
+
+// asgTable defines the columns of the idle Auto Scaling groups table
+var asgTable = newTable(Table[models.AutoScalingGroupInfo]{
+	Service: "asg",
+	Columns: []Column[models.AutoScalingGroupInfo]{
+		{Key: "name", Header: "ASG NAME", Value: func(g models.AutoScalingGroupInfo) string { return truncateString(g.Name, 50) }},
+		{Key: "region", Header: "REGION", Value: func(g models.AutoScalingGroupInfo) string { return g.Region }},
+		{Key: "capacity", Header: "DESIRED/MIN/MAX", Value: func(g models.AutoScalingGroupInfo) string {
+			return fmt.Sprintf("%d/%d/%d", g.DesiredCapacity, g.MinSize, g.MaxSize)
+		}},
+		{Key: "instances", Header: "INSTANCES",
+			Value:  func(g models.AutoScalingGroupInfo) string { return strconv.Itoa(g.InstanceCount) },
+			Number: func(g models.AutoScalingGroupInfo) float64 { return float64(g.InstanceCount) }},
+		{Key: "healthy-targets", Header: "HEALTHY TARGETS", Value: formatASGHealthyTargets, Hidden: true},
+		{Key: "suspended", Header: "SUSPENDED PROCESSES", Value: func(g models.AutoScalingGroupInfo) string {
+			if !g.Suspended() {
+				return "-"
+			}
+			return truncateString(strings.Join(g.SuspendedProcesses, ","), 50)
+		}},
+		{Key: "last-activity", Header: "LAST ACTIVITY", Value: func(g models.AutoScalingGroupInfo) string {
+			if g.LastActivity == nil {
+				return "None found"
+			}
+			return g.LastActivity.Format("2006-01-02")
+		}},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value: func(g models.AutoScalingGroupInfo) string {
+				if g.LastActivity == nil {
+					return strconv.Itoa(g.IdleDays) + "+"
+				}
+				return strconv.Itoa(g.IdleDays)
+			},
+			Number: func(g models.AutoScalingGroupInfo) float64 { return float64(g.IdleDays) }},
+		{Key: "findings", Header: "FINDINGS", Value: formatASGFindings, Total: asgFindingsTotal},
+	},
+	Region: func(g models.AutoScalingGroupInfo) string { return g.Region },
+	Links:  true,
+})
+
+// formatASGHealthyTargets formats the healthy targets of a group, "-" without target groups
+func formatASGHealthyTargets(group models.AutoScalingGroupInfo) string {
+	if group.TargetGroupCount == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d (%d target groups)", group.HealthyTargets, group.TargetGroupCount)
+}
+
+// formatASGFindings lists why a group was reported
+func formatASGFindings(group models.AutoScalingGroupInfo) string {
+	var findings []string
+	if group.ZeroCapacity {
+		findings = append(findings, "Zero capacity")
+	}
+	if group.Suspended() {
+		findings = append(findings, "Suspended")
+	}
+	if group.NoHealthyTargets {
+		findings = append(findings, "No healthy targets")
+	}
+	return strings.Join(findings, ", ")
+}
+
+// countASGFindings returns the zero-capacity, suspended, and unhealthy groups
+func countASGFindings(groups []models.AutoScalingGroupInfo) (zeroCapacity, suspended, noHealthyTargets int) {
+	for _, group := range groups {
+		if group.ZeroCapacity {
+			zeroCapacity++
+		}
+		if group.Suspended() {
+			suspended++
+		}
+		if group.NoHealthyTargets {
+			noHealthyTargets++
+		}
+	}
+	return zeroCapacity, suspended, noHealthyTargets
+}
+
+// asgFindingsTotal formats the zero-capacity and suspended group counts for the total row
+func asgFindingsTotal(groups []models.AutoScalingGroupInfo) string {
+	zeroCapacity, suspended, _ := countASGFindings(groups)
+	return fmt.Sprintf("%d zero-capacity, %d suspended", zeroCapacity, suspended)
+}
+
+// PrintASGSummary prints the zero-capacity, suspended, and unhealthy Auto Scaling group counts
+func PrintASGSummary(writer io.Writer, groups []models.AutoScalingGroupInfo) {
+	if len(groups) == 0 {
+		return
+	}
+
+	zeroCapacity, suspended, noHealthyTargets := countASGFindings(groups)
+
+	fmt.Fprintln(writer, "\n## Auto Scaling Summary")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "FINDING\tASGS")
+	fmt.Fprintf(w, "Zero-capacity ASGs\t%d\n", zeroCapacity)
+	fmt.Fprintf(w, "Suspended ASGs\t%d\n", suspended)
+	fmt.Fprintf(w, "ASGs without healthy targets\t%d\n", noHealthyTargets)
+	w.Flush()
+
+	fmt.Fprintf(writer, "\nZero-capacity ASGs have had desired, min, and max set to 0 for at least %d days. Auto Scaling groups have no direct cost, but keep launch templates, alarms, and lifecycle hooks around.\n", asgIdleThreshold)
+}
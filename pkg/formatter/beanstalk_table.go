@@ -0,0 +1,113 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintBeanstalkTable prints the idle Elastic Beanstalk environments in a table format.
+func PrintBeanstalkTable(writer io.Writer, environments []models.BeanstalkEnvironmentInfo, _ time.Time, _ time.Duration) {
+	if len(environments) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by estimated cost (descending), the most expensive environments first
+	sort.SliceStable(environments, func(i, j int) bool {
+		return environments[i].EstimatedMonthlyCost > environments[j].EstimatedMonthlyCost
+	})
+
+	beanstalkEnvironmentsTable.Print(writer, environments)
+}
+
+// beanstalkEnvironmentsTable defines the columns of the idle Elastic Beanstalk environments table
+var beanstalkEnvironmentsTable = newTable(Table[models.BeanstalkEnvironmentInfo]{
+	Service: "beanstalk",
+	Columns: []Column[models.BeanstalkEnvironmentInfo]{
+		{Key: "environment", Header: "ENVIRONMENT", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.EnvironmentName }},
+		{Key: "application", Header: "APPLICATION", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.ApplicationName }},
+		{Key: "region", Header: "REGION", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.Region }},
+		{Key: "status", Header: "STATUS", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.Status }},
+		{Key: "health", Header: "HEALTH", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.Health }},
+		{Key: "lb-type", Header: "LB TYPE", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.LoadBalancerType }},
+		{Key: "instances", Header: "INSTANCES",
+			Value:  func(e models.BeanstalkEnvironmentInfo) string { return strconv.Itoa(e.InstanceCount) },
+			Number: func(e models.BeanstalkEnvironmentInfo) float64 { return float64(e.InstanceCount) }},
+		{Key: "instance-types", Header: "INSTANCE TYPES", Value: func(e models.BeanstalkEnvironmentInfo) string {
+			if len(e.InstanceTypes) == 0 {
+				return "-"
+			}
+			return formatInstanceTypes(e.InstanceTypes)
+		}},
+		{Key: "created", Header: "CREATED", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.DateCreated.Format("2006-01-02") }},
+		{Key: "last-updated", Header: "LAST UPDATED", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.DateUpdated.Format("2006-01-02") }},
+		{Key: "cost", Header: "COST/MO",
+			Value: func(e models.BeanstalkEnvironmentInfo) string {
+				if e.PricingUnavailable() {
+					return "N/A"
+				}
+				return fmt.Sprintf("$%.2f", e.EstimatedMonthlyCost)
+			},
+			Number: func(e models.BeanstalkEnvironmentInfo) float64 { return e.EstimatedMonthlyCost },
+			Total:  sumColumn(func(e models.BeanstalkEnvironmentInfo) float64 { return e.EstimatedMonthlyCost })},
+		{Key: "pricing", Header: "PRICING", Value: func(e models.BeanstalkEnvironmentInfo) string { return GetPricingMarker(e.PricingSource) }},
+		{Key: "reason", Header: "REASON", Value: func(e models.BeanstalkEnvironmentInfo) string { return e.IdleReason }},
+	},
+	Links: true,
+})
+
+// formatInstanceTypes returns the instance types with their counts, e.g. "t3.small x2, t3.large"
+func formatInstanceTypes(instanceTypes []string) string {
+	var order []string
+	counts := make(map[string]int)
+	for _, instanceType := range instanceTypes {
+		if counts[instanceType] == 0 {
+			order = append(order, instanceType)
+		}
+		counts[instanceType]++
+	}
+
+	parts := make([]string, 0, len(order))
+	for _, instanceType := range order {
+		if counts[instanceType] > 1 {
+			instanceType = fmt.Sprintf("%s x%d", instanceType, counts[instanceType])
+		}
+		parts = append(parts, instanceType)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PrintBeanstalkSummary prints the idle Elastic Beanstalk environment count, instances, and cost
+func PrintBeanstalkSummary(writer io.Writer, environments []models.BeanstalkEnvironmentInfo) {
+	if len(environments) == 0 {
+		return
+	}
+
+	var totalInstances int
+	var totalMonthlyCost float64
+	for _, environment := range environments {
+		totalInstances += environment.InstanceCount
+		totalMonthlyCost += environment.EstimatedMonthlyCost
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## Elastic Beanstalk Summary")
+	fmt.Fprintf(w, "Idle environments:\t%d\n", len(environments))
+	fmt.Fprintf(w, "EC2 instances:\t%d\n", totalInstances)
+	fmt.Fprintf(w, "Estimated monthly cost:\t$%.2f\n", totalMonthlyCost)
+
+	w.Flush()
+
+	printRegionBreakdown(writer, "Elastic Beanstalk Environments by Region", environments,
+		func(e models.BeanstalkEnvironmentInfo) string { return e.Region },
+		func(e models.BeanstalkEnvironmentInfo) float64 { return e.EstimatedMonthlyCost },
+		nil)
+}
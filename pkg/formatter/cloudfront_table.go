@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintCloudFrontTable prints the idle CloudFront distributions in a table format.
+func PrintCloudFrontTable(writer io.Writer, distributions []models.DistributionInfo, _ time.Time, _ time.Duration) {
+	if len(distributions) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by last modified time, oldest first
+	sort.SliceStable(distributions, func(i, j int) bool {
+		return distributions[i].LastModifiedTime.Before(distributions[j].LastModifiedTime)
+	})
+
+	distributionsTable.Print(writer, distributions)
+}
+
+// distributionsTable defines the columns of the idle CloudFront distributions table
+var distributionsTable = newTable(Table[models.DistributionInfo]{
+	Service: "cloudfront",
+	Columns: []Column[models.DistributionInfo]{
+		{Key: "id", Header: "ID", Value: func(d models.DistributionInfo) string { return d.ID }},
+		{Key: "aliases", Header: "ALIASES", Value: func(d models.DistributionInfo) string { return joinFirst(d.Aliases) }},
+		{Key: "origin", Header: "ORIGIN", Value: func(d models.DistributionInfo) string { return joinFirst(d.OriginDomains) }},
+		{Key: "enabled", Header: "ENABLED", Value: func(d models.DistributionInfo) string { return strconv.FormatBool(d.Enabled) }},
+		{Key: "status", Header: "STATUS", Value: func(d models.DistributionInfo) string { return d.Status }},
+		{Key: "requests", Header: "REQUESTS (30d)",
+			Value: func(d models.DistributionInfo) string {
+				if d.RequestCount == nil {
+					return "-"
+				}
+				return fmt.Sprintf("%.0f", *d.RequestCount)
+			},
+			Number: func(d models.DistributionInfo) float64 { return optionalFloat(d.RequestCount) }},
+		{Key: "last-modified", Header: "LAST MODIFIED", Value: func(d models.DistributionInfo) string {
+			if d.LastModifiedTime.IsZero() {
+				return "N/A"
+			}
+			return d.LastModifiedTime.Format("2006-01-02")
+		}},
+		{Key: "idle-reason", Header: "IDLE REASON", Value: func(d models.DistributionInfo) string { return d.IdleReason }},
+	},
+	NoTotal: true,
+})
+
+// joinFirst returns the first value with the count of the others, or "-" if there are none
+func joinFirst(values []string) string {
+	switch len(values) {
+	case 0:
+		return "-"
+	case 1:
+		return truncateString(values[0], 50)
+	default:
+		return fmt.Sprintf("%s (+%d)", truncateString(values[0], 50), len(values)-1)
+	}
+}
+
+// PrintCloudFrontSummary prints the number of idle distributions by reason
+func PrintCloudFrontSummary(writer io.Writer, distributions []models.DistributionInfo) {
+	if len(distributions) == 0 {
+		return
+	}
+
+	disabledCount := 0
+	for _, distribution := range distributions {
+		if !distribution.Enabled {
+			disabledCount++
+		}
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## CloudFront Summary")
+	fmt.Fprintf(w, "Idle distributions:\t%d\n", len(distributions))
+	fmt.Fprintf(w, "Disabled:\t%d\n", disabledCount)
+	fmt.Fprintf(w, "Enabled without requests:\t%d\n", len(distributions)-disabledCount)
+
+	w.Flush()
+
+	if disabledCount < len(distributions) {
+		fmt.Fprintln(writer, "\nEnabled distributions without requests may point at deleted origins. Check their aliases and DNS records before disabling them.")
+	}
+}
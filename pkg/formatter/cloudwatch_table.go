@@ -0,0 +1,152 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// cloudWatchDashboardIdleThreshold is the number of days without a modification used by the CloudWatch scan
+var cloudWatchDashboardIdleThreshold = 90
+
+// SetCloudWatchDashboardIdleThreshold sets the idle threshold shown in the CloudWatch summary
+func SetCloudWatchDashboardIdleThreshold(days int) {
+	cloudWatchDashboardIdleThreshold = days
+}
+
+// PrintCloudWatchAlarmsTable prints the idle CloudWatch alarms in a table format
+func PrintCloudWatchAlarmsTable(writer io.Writer, alarms []models.CloudWatchAlarmInfo) {
+	// Sort by idle days (descending), the alarms stuck the longest first
+	sort.SliceStable(alarms, func(i, j int) bool {
+		return alarms[i].IdleDays > alarms[j].IdleDays
+	})
+
+	cloudWatchAlarmsTable.Print(writer, alarms)
+}
+
+// cloudWatchAlarmsTable defines the columns of the idle CloudWatch alarms table
+var cloudWatchAlarmsTable = newTable(Table[models.CloudWatchAlarmInfo]{
+	Service: "cloudwatch",
+	Columns: []Column[models.CloudWatchAlarmInfo]{
+		{Key: "name", Header: "ALARM NAME", Value: func(a models.CloudWatchAlarmInfo) string { return truncateString(a.Name, 50) }},
+		{Key: "type", Header: "TYPE", Value: func(a models.CloudWatchAlarmInfo) string { return a.Type }},
+		{Key: "region", Header: "REGION", Value: func(a models.CloudWatchAlarmInfo) string { return a.Region }},
+		{Key: "metric", Header: "METRIC", Value: func(a models.CloudWatchAlarmInfo) string {
+			if a.MetricName == "" {
+				return "-"
+			}
+			return truncateString(a.Namespace+"/"+a.MetricName, 50)
+		}},
+		{Key: "state", Header: "STATE", Value: func(a models.CloudWatchAlarmInfo) string { return a.StateValue }},
+		{Key: "state-since", Header: "STATE SINCE", Value: func(a models.CloudWatchAlarmInfo) string { return a.StateSince.Format("2006-01-02") }},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value:  func(a models.CloudWatchAlarmInfo) string { return strconv.Itoa(a.IdleDays) },
+			Number: func(a models.CloudWatchAlarmInfo) float64 { return float64(a.IdleDays) }},
+		{Key: "actions", Header: "ACTIONS", Value: func(a models.CloudWatchAlarmInfo) string {
+			if a.ActionsEnabled {
+				return "Enabled"
+			}
+			return "Disabled"
+		}, Hidden: true},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(a models.CloudWatchAlarmInfo) string { return fmt.Sprintf("$%.2f", a.EstimatedMonthlyCost) },
+			Number: func(a models.CloudWatchAlarmInfo) float64 { return a.EstimatedMonthlyCost },
+			Total:  sumColumn(func(a models.CloudWatchAlarmInfo) float64 { return a.EstimatedMonthlyCost })},
+		{Key: "reason", Header: "REASON", Value: func(a models.CloudWatchAlarmInfo) string { return a.IdleReason }},
+	},
+	Region: func(a models.CloudWatchAlarmInfo) string { return a.Region },
+	Links:  true,
+})
+
+// PrintCloudWatchDashboardsTable prints the CloudWatch dashboards in a table format
+func PrintCloudWatchDashboardsTable(writer io.Writer, dashboards []models.CloudWatchDashboardInfo) {
+	// Sort by idle status and then by idle days (descending)
+	sort.SliceStable(dashboards, func(i, j int) bool {
+		if dashboards[i].IsIdle != dashboards[j].IsIdle {
+			return dashboards[i].IsIdle // Idle dashboards first
+		}
+		return dashboards[i].IdleDays > dashboards[j].IdleDays
+	})
+
+	cloudWatchDashboardsTable.Print(writer, dashboards)
+}
+
+// cloudWatchDashboardsTable defines the columns of the CloudWatch dashboards table
+var cloudWatchDashboardsTable = newTable(Table[models.CloudWatchDashboardInfo]{
+	Service: "cloudwatch",
+	Columns: []Column[models.CloudWatchDashboardInfo]{
+		{Key: "name", Header: "DASHBOARD NAME", Value: func(d models.CloudWatchDashboardInfo) string { return truncateString(d.Name, 50) }},
+		{Key: "region", Header: "REGION", Value: func(d models.CloudWatchDashboardInfo) string { return d.Region }},
+		{Key: "last-modified", Header: "LAST MODIFIED", Value: func(d models.CloudWatchDashboardInfo) string { return d.LastModified.Format("2006-01-02") }},
+		{Key: "size", Header: "SIZE",
+			Value:  func(d models.CloudWatchDashboardInfo) string { return fmt.Sprintf("%d B", d.SizeBytes) },
+			Number: func(d models.CloudWatchDashboardInfo) float64 { return float64(d.SizeBytes) }, Hidden: true},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value:  func(d models.CloudWatchDashboardInfo) string { return strconv.Itoa(d.IdleDays) },
+			Number: func(d models.CloudWatchDashboardInfo) float64 { return float64(d.IdleDays) }},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(d models.CloudWatchDashboardInfo) string { return fmt.Sprintf("$%.2f", d.EstimatedMonthlyCost) },
+			Number: func(d models.CloudWatchDashboardInfo) float64 { return d.EstimatedMonthlyCost },
+			Total:  sumColumn(func(d models.CloudWatchDashboardInfo) float64 { return d.EstimatedMonthlyCost })},
+		{Key: "status", Header: "STATUS", Value: func(d models.CloudWatchDashboardInfo) string {
+			if d.IsIdle {
+				return "Idle"
+			}
+			return "Active"
+		}, Total: cloudWatchDashboardIdleTotal},
+	},
+	Region: func(d models.CloudWatchDashboardInfo) string { return d.Region },
+	Links:  true,
+})
+
+// cloudWatchDashboardIdleTotal formats the number of idle dashboards for the total row
+func cloudWatchDashboardIdleTotal(dashboards []models.CloudWatchDashboardInfo) string {
+	idleCount := 0
+	for _, dashboard := range dashboards {
+		if dashboard.IsIdle {
+			idleCount++
+		}
+	}
+	return fmt.Sprintf("%d idle", idleCount)
+}
+
+// PrintCloudWatchSummary prints the idle alarm and dashboard counts and their monthly cost
+func PrintCloudWatchSummary(writer io.Writer, alarms []models.CloudWatchAlarmInfo, dashboards []models.CloudWatchDashboardInfo) {
+	if len(alarms) == 0 && len(dashboards) == 0 {
+		return
+	}
+
+	var insufficientData, metricMissing, idleDashboards int
+	var alarmCost, dashboardCost float64
+	for _, alarm := range alarms {
+		if alarm.MetricMissing {
+			metricMissing++
+		} else {
+			insufficientData++
+		}
+		alarmCost += alarm.EstimatedMonthlyCost
+	}
+	for _, dashboard := range dashboards {
+		if dashboard.IsIdle {
+			idleDashboards++
+			dashboardCost += dashboard.EstimatedMonthlyCost
+		}
+	}
+
+	fmt.Fprintln(writer, "\n## CloudWatch Summary")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "RESOURCE\tIDLE\tCOST/MO")
+	fmt.Fprintf(w, "Alarms in INSUFFICIENT_DATA\t%d\t\n", insufficientData)
+	fmt.Fprintf(w, "Alarms with a missing metric\t%d\t\n", metricMissing)
+	fmt.Fprintf(w, "Alarms (total)\t%d\t$%.2f\n", len(alarms), alarmCost)
+	fmt.Fprintf(w, "Dashboards\t%d\t$%.2f\n", idleDashboards, dashboardCost)
+	fmt.Fprintf(w, "Total\t%d\t$%.2f\n", len(alarms)+idleDashboards, alarmCost+dashboardCost)
+	w.Flush()
+
+	fmt.Fprintf(writer, "\nDashboards not modified in the last %d days are idle. Costs do not deduct the free tier (10 alarm metrics, 3 dashboards).\n", cloudWatchDashboardIdleThreshold)
+}
@@ -0,0 +1,346 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// Column is a column of the table of a resource type. The columns of each type are defined
+// once and drive the text table, --columns, and --sort-by.
+type Column[T any] struct {
+	Key    string           // Name used by --columns and --sort-by, e.g., "cost"
+	Header string           // Table header, e.g., "COST/MO"
+	Value  func(T) string   // Cell text
+	Number func(T) float64  // Numeric value sorted descending by default, nil to sort by the cell text
+	Total  func([]T) string // Cell of the total and subtotal rows, nil if blank
+	Hidden bool             // Only shown when selected with --columns
+	Shown  func() bool      // Whether the column is shown by default, nil if it always is unless Hidden
+}
+
+// Table defines the columns of the table of a resource type
+type Table[T any] struct {
+	Service string                    // Service the table belongs to, as named by --services
+	Columns []Column[T]               // Columns in their default order
+	Region  func(T) string            // Region of a resource, rows are grouped with --group-by region. nil to never group.
+	Tags    func(T) map[string]string // Tags shown with --show-tags, nil if the resource is not taggable
+	Links   bool                      // Whether --show-links adds a console URL column
+	NoTotal bool                      // Whether to leave out the total row
+}
+
+// columnRegistry holds the column keys of the tables of each service
+var columnRegistry = make(map[string][]string)
+
+// newTable registers the columns of a table under its service
+func newTable[T any](table Table[T]) *Table[T] {
+	for _, column := range table.Columns {
+		if !slices.Contains(columnRegistry[table.Service], column.Key) {
+			columnRegistry[table.Service] = append(columnRegistry[table.Service], column.Key)
+		}
+	}
+	return &table
+}
+
+// ServiceColumns returns the column keys of the tables of a service, nil if it has none
+func ServiceColumns(service string) []string {
+	return columnRegistry[service]
+}
+
+var (
+	selectedColumns  []string // --columns, nil for the default columns of each table
+	sortColumn       string   // --sort-by column key, empty for the default order of each table
+	sortDescending   bool     // Sort direction of sortColumn
+	sortDirectionSet bool     // Whether --sort-by set the direction with :asc or :desc
+)
+
+// SetColumns sets the columns shown in every table, in the given order (--columns)
+func SetColumns(keys []string) {
+	selectedColumns = nil
+	for _, key := range keys {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			selectedColumns = append(selectedColumns, key)
+		}
+	}
+}
+
+// SetSortBy sets the column every table is sorted by (--sort-by). Numeric columns sort
+// descending and text columns ascending, unless the key ends with ":asc" or ":desc".
+func SetSortBy(spec string) error {
+	key, direction, hasDirection := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+	sortColumn, sortDirectionSet = key, hasDirection
+	switch {
+	case !hasDirection:
+	case direction == "asc":
+		sortDescending = false
+	case direction == "desc":
+		sortDescending = true
+	default:
+		return fmt.Errorf("invalid sort direction '%s' in --sort-by (supported: asc, desc)", direction)
+	}
+	return nil
+}
+
+// ValidateColumns checks the --columns and --sort-by keys against the tables of the scanned
+// services. A key has to belong to at least one of them, tables without it ignore it.
+func ValidateColumns(services []string) error {
+	keys := slices.Clone(selectedColumns)
+	if sortColumn != "" {
+		keys = append(keys, sortColumn)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var tabled, untabled []string
+	for _, service := range services {
+		if len(columnRegistry[service]) > 0 {
+			tabled = append(tabled, service)
+		} else {
+			untabled = append(untabled, service)
+		}
+	}
+	if len(tabled) == 0 {
+		return fmt.Errorf("--columns and --sort-by are not supported for %s", strings.Join(untabled, ", "))
+	}
+
+	for _, key := range keys {
+		found := false
+		var candidates []string
+		for _, service := range tabled {
+			found = found || slices.Contains(columnRegistry[service], key)
+			candidates = append(candidates, columnRegistry[service]...)
+		}
+		slices.Sort(candidates)
+		candidates = slices.Compact(candidates)
+		if found {
+			continue
+		}
+		suggestion := ""
+		if closest := utils.SuggestClosest(key, candidates); closest != "" {
+			suggestion = fmt.Sprintf(", did you mean '%s'?", closest)
+		}
+		return fmt.Errorf("unknown column '%s'%s\nValid columns:\n%s", key, suggestion, validColumns(tabled))
+	}
+	return nil
+}
+
+// validColumns lists the column keys of each service, one service per line
+func validColumns(services []string) string {
+	var lines []string
+	for _, service := range services {
+		lines = append(lines, fmt.Sprintf("  %s: %s", service, strings.Join(columnRegistry[service], ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// column returns the column with the given key
+func (t *Table[T]) column(key string) (Column[T], bool) {
+	for _, column := range t.Columns {
+		if column.Key == key {
+			return column, true
+		}
+	}
+	return Column[T]{}, false
+}
+
+// Visible returns the columns shown: those selected with --columns in their order, or the
+// default columns if none was selected or none belongs to the table
+func (t *Table[T]) Visible() []Column[T] {
+	var columns []Column[T]
+	for _, key := range selectedColumns {
+		if column, ok := t.column(key); ok {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) > 0 {
+		return columns
+	}
+	for _, column := range t.Columns {
+		if !column.Hidden && (column.Shown == nil || column.Shown()) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// Sort sorts the items by the --sort-by column, keeping the default order of the formatter
+// for ties. It does nothing if no column is set or the table does not have it.
+func (t *Table[T]) Sort(items []T) {
+	column, ok := t.column(sortColumn)
+	if !ok {
+		return
+	}
+	descending := column.Number != nil
+	if sortDirectionSet {
+		descending = sortDescending
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if column.Number != nil {
+			a, b := column.Number(items[i]), column.Number(items[j])
+			if descending {
+				return a > b
+			}
+			return a < b
+		}
+		a, b := column.Value(items[i]), column.Value(items[j])
+		if descending {
+			return a > b
+		}
+		return a < b
+	})
+}
+
+// Rows renders the header and rows of the visible columns, without tags, links, or totals,
+// for output formats other than the text table
+func (t *Table[T]) Rows(items []T) (header []string, rows [][]string) {
+	columns := t.Visible()
+	for _, column := range columns {
+		header = append(header, column.Header)
+	}
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = column.Value(item)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// Print sorts the items and prints the table: the header, a row per item grouped by region
+// with --group-by region, and the total row
+func (t *Table[T]) Print(writer io.Writer, items []T) {
+	t.Sort(items)
+	columns := t.Visible()
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	header := strings.Join(headers, "\t")
+	if t.Tags != nil {
+		header += tagHeader()
+	}
+	if t.Links {
+		header += linkHeader()
+	}
+	fmt.Fprintln(w, header)
+
+	printRow := func(item T) {
+		alerted := exceedsCostAlert(item)
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = column.Value(item)
+			if alerted && slices.Contains(alertColumnKeys, column.Key) {
+				cells[i] += alertMarker
+			}
+		}
+		row := strings.Join(cells, "\t")
+		if t.Tags != nil {
+			row += tagCells(t.Tags(item))
+		}
+		if t.Links {
+			row += linkCell(item)
+		}
+		fmt.Fprintln(w, row)
+	}
+	printTotal := func(label string, group []T) {
+		if !t.NoTotal {
+			printTotalRow(w, columns, label, group)
+		}
+	}
+
+	if t.Region == nil {
+		for _, item := range items {
+			printRow(item)
+		}
+	} else {
+		printRowsByRegion(items, t.Region, printRow, printTotal)
+	}
+	printTotal("Total:", items)
+	w.Flush()
+}
+
+// printTotalRow prints the label in the first column and the totals of the other columns
+func printTotalRow[T any](w io.Writer, columns []Column[T], label string, items []T) {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		if column.Total != nil {
+			cells[i] = column.Total(items)
+		}
+	}
+	if len(cells) > 0 {
+		cells[0] = strings.TrimSpace(label + " " + cells[0])
+	}
+	fmt.Fprintln(w, strings.Join(cells, "\t"))
+}
+
+// sumColumn returns a total cell formatting the sum of a value as a dollar amount
+func sumColumn[T any](value func(T) float64) func([]T) string {
+	return func(items []T) string {
+		var total float64
+		for _, item := range items {
+			total += value(item)
+		}
+		return fmt.Sprintf("$%.2f", total)
+	}
+}
+
+// sumCountColumn returns a total cell formatting the sum of a value as a dollar amount,
+// followed by the number of items, e.g., "$12.34 (3 EIPs)"
+func sumCountColumn[T any](value func(T) float64, unit string) func([]T) string {
+	sum := sumColumn(value)
+	return func(items []T) string {
+		return fmt.Sprintf("%s (%d %s)", sum(items), len(items), unit)
+	}
+}
+
+// countColumn returns a total cell formatting the number of items
+func countColumn[T any](format string) func([]T) string {
+	return func(items []T) string {
+		return fmt.Sprintf(format, len(items))
+	}
+}
+
+// formatPricedCost formats a dollar amount, or "N/A" if the resource could not be priced
+func formatPricedCost(cost float64, pricingSource string) string {
+	if pricingSource == "N/A" {
+		return "N/A"
+	}
+	return fmt.Sprintf("$%.2f", cost)
+}
+
+// setHeader changes the header of a column, for headers showing a setting such as a threshold
+func (t *Table[T]) setHeader(key, header string) {
+	for i := range t.Columns {
+		if t.Columns[i].Key == key {
+			t.Columns[i].Header = header
+		}
+	}
+}
+
+// protectedColumn is the PROTECTED column of resources that report deletion or termination protection
+func protectedColumn[T models.Protectable]() Column[T] {
+	return Column[T]{Key: "protected", Header: "PROTECTED", Value: func(item T) string {
+		protected, reason := item.DeletionProtection()
+		if !protected {
+			return "No"
+		}
+		return fmt.Sprintf("Yes (%s)", reason)
+	}}
+}
+
+// optionalFloat returns a metric for sorting, -1 if it is missing
+func optionalFloat(value *float64) float64 {
+	if value == nil {
+		return -1
+	}
+	return *value
+}
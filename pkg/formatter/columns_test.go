@@ -0,0 +1,197 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testItem is a resource of the test table
+type testItem struct {
+	Name string
+	Cost float64
+}
+
+var showDetail bool
+
+// newTestTable registers a table of testItem under the "test" service, removed when the test ends
+func newTestTable(t *testing.T) *Table[testItem] {
+	t.Helper()
+	t.Cleanup(func() { delete(columnRegistry, "test") })
+	return newTable(Table[testItem]{
+		Service: "test",
+		Columns: []Column[testItem]{
+			{Key: "name", Header: "NAME", Value: func(i testItem) string { return i.Name }},
+			{Key: "cost", Header: "COST/MO",
+				Value:  func(i testItem) string { return fmt.Sprintf("$%.2f", i.Cost) },
+				Number: func(i testItem) float64 { return i.Cost },
+				Total:  sumColumn(func(i testItem) float64 { return i.Cost })},
+			{Key: "detail", Header: "DETAIL", Value: func(i testItem) string { return "detail" }, Shown: func() bool { return showDetail }},
+			{Key: "id", Header: "ID", Value: func(i testItem) string { return "id-" + i.Name }, Hidden: true},
+		},
+	})
+}
+
+// resetColumns restores the default columns and order when the test ends
+func resetColumns(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		SetColumns(nil)
+		_ = SetSortBy("")
+		sortDescending = false
+		showDetail = false
+	})
+}
+
+// headers returns the headers of the visible columns of the table
+func headers(table *Table[testItem]) []string {
+	header, _ := table.Rows(nil)
+	return header
+}
+
+func TestServiceColumns(t *testing.T) {
+	newTestTable(t)
+	// A second table of the service only adds its new keys
+	newTable(Table[testItem]{Service: "test", Columns: []Column[testItem]{{Key: "cost"}, {Key: "region"}}})
+
+	want := []string{"name", "cost", "detail", "id", "region"}
+	if got := ServiceColumns("test"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ServiceColumns() = %v, want %v", got, want)
+	}
+	if got := ServiceColumns("unknown"); got != nil {
+		t.Errorf("ServiceColumns(unknown) = %v, want nil", got)
+	}
+	if got := ServiceColumns("eip"); len(got) == 0 || got[0] != "allocation-id" {
+		t.Errorf("ServiceColumns(eip) = %v, want the columns of the Elastic IP table", got)
+	}
+}
+
+func TestVisible(t *testing.T) {
+	table := newTestTable(t)
+	resetColumns(t)
+
+	tests := []struct {
+		name       string
+		columns    []string
+		showDetail bool
+		want       []string
+	}{
+		{name: "defaults leave out hidden columns", want: []string{"NAME", "COST/MO"}},
+		{name: "defaults include shown columns", showDetail: true, want: []string{"NAME", "COST/MO", "DETAIL"}},
+		{name: "selected in their order", columns: []string{"cost", "name"}, want: []string{"COST/MO", "NAME"}},
+		{name: "selected hidden column", columns: []string{" ID ", "name"}, want: []string{"ID", "NAME"}},
+		{name: "keys of other tables are skipped", columns: []string{"region", "cost"}, want: []string{"COST/MO"}},
+		{name: "defaults if no key belongs to the table", columns: []string{"region"}, want: []string{"NAME", "COST/MO"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetColumns(tt.columns)
+			showDetail = tt.showDetail
+			if got := headers(table); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Visible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	table := newTestTable(t)
+	resetColumns(t)
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{sortBy: "", want: []string{"b", "a", "c", "d"}},
+		{sortBy: "cost", want: []string{"c", "b", "d", "a"}},
+		{sortBy: "cost:asc", want: []string{"a", "b", "d", "c"}},
+		{sortBy: "name", want: []string{"a", "b", "c", "d"}},
+		{sortBy: "NAME:desc", want: []string{"d", "c", "b", "a"}},
+		{sortBy: "region", want: []string{"b", "a", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			if err := SetSortBy(tt.sortBy); err != nil {
+				t.Fatalf("SetSortBy() error = %v", err)
+			}
+			// b and d cost the same, so they keep their order
+			items := []testItem{{"b", 2}, {"a", 1}, {"c", 3}, {"d", 2}}
+			table.Sort(items)
+			var got []string
+			for _, item := range items {
+				got = append(got, item.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := SetSortBy("cost:up"); err == nil || !strings.Contains(err.Error(), "invalid sort direction 'up'") {
+		t.Errorf("SetSortBy(cost:up) error = %v", err)
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	newTestTable(t)
+	resetColumns(t)
+
+	tests := []struct {
+		name     string
+		columns  []string
+		sortBy   string
+		services []string
+		wantErr  string
+	}{
+		{name: "no selection", services: []string{"test", "iam-untabled"}},
+		{name: "known columns", columns: []string{"name", "id"}, sortBy: "cost:asc", services: []string{"test"}},
+		{name: "column of another scanned table", columns: []string{"public-ip", "name"}, services: []string{"test", "eip"}},
+		{name: "unknown column", columns: []string{"nme"}, services: []string{"test"},
+			wantErr: "unknown column 'nme', did you mean 'name'?\nValid columns:\n  test: name, cost, detail, id"},
+		{name: "unknown sort column", sortBy: "price", services: []string{"test"},
+			wantErr: "unknown column 'price'"},
+		{name: "column of a table not scanned", columns: []string{"public-ip"}, services: []string{"test"},
+			wantErr: "unknown column 'public-ip'"},
+		{name: "no table", columns: []string{"name"}, services: []string{"untabled"},
+			wantErr: "--columns and --sort-by are not supported for untabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetColumns(tt.columns)
+			if err := SetSortBy(tt.sortBy); err != nil {
+				t.Fatalf("SetSortBy() error = %v", err)
+			}
+			err := ValidateColumns(tt.services)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateColumns() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateColumns() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTablePrint(t *testing.T) {
+	table := newTestTable(t)
+	resetColumns(t)
+	SetColumns([]string{"cost", "name"})
+	if err := SetSortBy("cost"); err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	table.Print(&output, []testItem{{"a", 1}, {"b", 2.5}})
+	want := "COST/MO       NAME\n$2.50         b\n$1.00         a\nTotal: $3.50  \n"
+	if got := output.String(); got != want {
+		t.Errorf("Print() = %q, want %q", got, want)
+	}
+}
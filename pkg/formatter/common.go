@@ -2,18 +2,132 @@ package formatter
 
 import (
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 )
 
 // printTimestamp prints the scan timestamp and duration
 // NOTE: This function is deprecated and kept for reference only.
 // All formatters now use tabwriter for consistent output.
-func printTimestamp(scanStartTime time.Time, scanDuration time.Duration) {
+func printTimestamp(writer io.Writer, scanStartTime time.Time, scanDuration time.Duration) {
 	// Format the scan time
 	timeStr := scanStartTime.Format("2006-01-02 15:04:05")
 
 	// Format the duration
 	durationStr := fmt.Sprintf("%.2fs", scanDuration.Seconds())
 
-	fmt.Printf("Scan completed at %s (took %s)\n", timeStr, durationStr)
+	fmt.Fprintf(writer, "Scan completed at %s (took %s)\n", timeStr, durationStr)
+}
+
+// tagColumns holds the tag keys appended as extra table columns (--show-tags)
+var tagColumns []string
+
+// SetTagColumns sets the tag keys appended as extra columns to the tables of taggable resources
+func SetTagColumns(keys []string) {
+	tagColumns = keys
+}
+
+// tagHeader returns the extra header cells for the configured tag columns
+func tagHeader() string {
+	var header strings.Builder
+	for _, key := range tagColumns {
+		header.WriteString("\tTAG:" + key)
+	}
+	return header.String()
+}
+
+// tagCells returns the extra row cells for the configured tag columns, blank when a tag is missing
+func tagCells(tags map[string]string) string {
+	var cells strings.Builder
+	for _, key := range tagColumns {
+		cells.WriteString("\t" + tags[key])
+	}
+	return cells.String()
+}
+
+// groupByRegion enables per-region grouping in tables and summaries (--group-by region)
+var groupByRegion bool
+
+// SetGroupByRegion enables per-region subtotal rows in tables and region breakdowns in summaries
+func SetGroupByRegion(enabled bool) {
+	groupByRegion = enabled
+}
+
+// regionGroup holds the items of one region in table order
+type regionGroup[T any] struct {
+	region string
+	items  []T
+}
+
+// splitByRegion groups items by region, sorted by region name. Items keep their
+// relative order within each region.
+func splitByRegion[T any](items []T, region func(T) string) []regionGroup[T] {
+	index := make(map[string]int)
+	var groups []regionGroup[T]
+	for _, item := range items {
+		r := region(item)
+		i, ok := index[r]
+		if !ok {
+			i = len(groups)
+			index[r] = i
+			groups = append(groups, regionGroup[T]{region: r})
+		}
+		groups[i].items = append(groups[i].items, item)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].region < groups[j].region
+	})
+	return groups
+}
+
+// printRowsByRegion prints a row for each item. With --group-by region, rows are grouped
+// by region and each region is followed by a subtotal row labeled with the region.
+func printRowsByRegion[T any](items []T, region func(T) string, printRow func(T), printSubtotal func(label string, items []T)) {
+	if !groupByRegion {
+		for _, item := range items {
+			printRow(item)
+		}
+		return
+	}
+	for _, group := range splitByRegion(items, region) {
+		for _, item := range group.items {
+			printRow(item)
+		}
+		printSubtotal(fmt.Sprintf("Subtotal (%s):", group.region), group.items)
+	}
+}
+
+// printRegionBreakdown prints the count and estimated monthly cost of the items per region
+// when grouping by region. Savings are shown only when a savings function is given.
+func printRegionBreakdown[T any](writer io.Writer, title string, items []T, region func(T) string, cost func(T) float64, savings func(T) float64) {
+	if !groupByRegion || len(items) == 0 {
+		return
+	}
+
+	fmt.Fprintf(writer, "\n## %s\n", title)
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if savings != nil {
+		fmt.Fprintln(w, "REGION\tCOUNT\tCOST/MO\tSAVINGS")
+	} else {
+		fmt.Fprintln(w, "REGION\tCOUNT\tCOST/MO")
+	}
+	for _, group := range splitByRegion(items, region) {
+		var totalCost, totalSavings float64
+		for _, item := range group.items {
+			totalCost += cost(item)
+			if savings != nil {
+				totalSavings += savings(item)
+			}
+		}
+		if savings != nil {
+			fmt.Fprintf(w, "%s\t%d\t$%.2f\t$%.2f\n", group.region, len(group.items), totalCost, totalSavings)
+		} else {
+			fmt.Fprintf(w, "%s\t%d\t$%.2f\n", group.region, len(group.items), totalCost)
+		}
+	}
+	w.Flush()
 }
@@ -17,7 +17,7 @@ func FormatConfigRulesTable(writer io.Writer, rules []models.ConfigRuleInfo) {
 	}
 
 	// Sort rules: idle rules first, then by idle days (descending)
-	sort.Slice(rules, func(i, j int) bool {
+	sort.SliceStable(rules, func(i, j int) bool {
 		if rules[i].IsIdle != rules[j].IsIdle {
 			return rules[i].IsIdle // true comes first
 		}
@@ -28,7 +28,7 @@ func FormatConfigRulesTable(writer io.Writer, rules []models.ConfigRuleInfo) {
 	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', tabwriter.TabIndent)
 
 	// Print header
-	fmt.Fprintln(w, "RULE NAME\tRULE ID\tCUSTOM\tSTATUS\tCOMPLIANT\tEVALUATION MODE\tLAST ACTIVITY\tIDLE\tREGION")
+	fmt.Fprintln(w, "RULE NAME\tRULE ID\tCUSTOM\tSTATUS\tCOMPLIANT\tEVALUATION MODE\tSTATUS DETAIL\tLAST ACTIVITY\tIDLE\tREGION")
 
 	// Print each rule
 	for _, rule := range rules {
@@ -61,13 +61,14 @@ func FormatConfigRulesTable(writer io.Writer, rules []models.ConfigRuleInfo) {
 			idleStatus = "Yes"
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			rule.RuleName,
 			rule.RuleID,
 			customStr,
 			statusStr,
 			compliantStr,
 			rule.EvaluationMode,
+			configRuleStatusDetail(rule),
 			lastActivityStr,
 			idleStatus,
 			rule.Region,
@@ -75,16 +76,44 @@ func FormatConfigRulesTable(writer io.Writer, rules []models.ConfigRuleInfo) {
 	}
 
 	w.Flush()
+}
+
+// configRuleStatusDetail describes why a rule needs attention beyond being idle
+func configRuleStatusDetail(rule models.ConfigRuleInfo) string {
+	switch {
+	case rule.IsFailing && rule.LastErrorCode != "":
+		return "Failing: " + rule.LastErrorCode
+	case rule.IsFailing:
+		return "Failing"
+	case rule.NoResourcesInScope:
+		return "No recorded resources in scope"
+	default:
+		return "-"
+	}
+}
+
+// FormatConfigRulesSummary writes the idle summary line for AWS Config rules
+func FormatConfigRulesSummary(writer io.Writer, rules []models.ConfigRuleInfo) {
+	if len(rules) == 0 {
+		return
+	}
 
-	// Print summary
 	idleCount := 0
 	customCount := 0
 	inactiveCount := 0
+	var failing []models.ConfigRuleInfo
+	noResourcesCount := 0
 
 	for _, rule := range rules {
 		if rule.IsIdle {
 			idleCount++
 		}
+		if rule.IsFailing {
+			failing = append(failing, rule)
+		}
+		if rule.NoResourcesInScope {
+			noResourcesCount++
+		}
 		if rule.IsCustom {
 			customCount++
 		}
@@ -95,6 +124,17 @@ func FormatConfigRulesTable(writer io.Writer, rules []models.ConfigRuleInfo) {
 
 	fmt.Fprintf(writer, "\nSummary: %d idle AWS Config rules out of %d total rules (%d custom, %d inactive)\n",
 		idleCount, len(rules), customCount, inactiveCount)
+	fmt.Fprintf(writer, "Failing rules: %d, rules without recorded resources in scope: %d\n",
+		len(failing), noResourcesCount)
+
+	// Failing custom rules keep invoking their Lambda function, so show why they fail
+	for _, rule := range failing {
+		message := rule.LastErrorMessage
+		if message == "" {
+			message = "no error message"
+		}
+		fmt.Fprintf(writer, "  - %s (%s): %s\n", rule.RuleName, rule.Region, message)
+	}
 }
 
 // FormatConfigRecordersTable writes AWS Config recorders information in a table format
@@ -105,7 +145,7 @@ func FormatConfigRecordersTable(writer io.Writer, recorders []models.ConfigRecor
 	}
 
 	// Sort recorders: idle recorders first, then by idle days (descending)
-	sort.Slice(recorders, func(i, j int) bool {
+	sort.SliceStable(recorders, func(i, j int) bool {
 		if recorders[i].IsIdle != recorders[j].IsIdle {
 			return recorders[i].IsIdle // true comes first
 		}
@@ -121,8 +161,12 @@ func FormatConfigRecordersTable(writer io.Writer, recorders []models.ConfigRecor
 	// Print each recorder
 	for _, recorder := range recorders {
 		lastActivityStr := "Never"
+		idleDaysStr := fmt.Sprintf("%d", recorder.IdleDays)
 		if recorder.LastActivity != nil {
 			lastActivityStr = formatDate(*recorder.LastActivity)
+		} else if recorder.NeverActive {
+			lastActivityStr = "Never active"
+			idleDaysStr = "-"
 		}
 
 		statusStr := "Not Recording"
@@ -140,22 +184,29 @@ func FormatConfigRecordersTable(writer io.Writer, recorders []models.ConfigRecor
 			idleStatus = "Yes"
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			recorder.RecorderName,
 			statusStr,
 			resourceCoverageStr,
 			lastActivityStr,
-			recorder.IdleDays,
+			idleDaysStr,
 			idleStatus,
 			recorder.Region,
 		)
 	}
 
 	w.Flush()
+}
+
+// FormatConfigRecordersSummary writes the idle summary line for AWS Config recorders
+func FormatConfigRecordersSummary(writer io.Writer, recorders []models.ConfigRecorderInfo) {
+	if len(recorders) == 0 {
+		return
+	}
 
-	// Print summary
 	idleCount := 0
 	notRecordingCount := 0
+	neverActiveCount := 0
 
 	for _, recorder := range recorders {
 		if recorder.IsIdle {
@@ -164,10 +215,13 @@ func FormatConfigRecordersTable(writer io.Writer, recorders []models.ConfigRecor
 		if !recorder.IsRecording {
 			notRecordingCount++
 		}
+		if recorder.NeverActive {
+			neverActiveCount++
+		}
 	}
 
-	fmt.Fprintf(writer, "\nSummary: %d idle AWS Config recorders out of %d total recorders (%d not recording)\n",
-		idleCount, len(recorders), notRecordingCount)
+	fmt.Fprintf(writer, "\nSummary: %d idle AWS Config recorders out of %d total recorders (%d not recording, %d never active)\n",
+		idleCount, len(recorders), notRecordingCount, neverActiveCount)
 }
 
 // FormatConfigDeliveryChannelsTable writes AWS Config delivery channels information in a table format
@@ -178,7 +232,7 @@ func FormatConfigDeliveryChannelsTable(writer io.Writer, channels []models.Confi
 	}
 
 	// Sort channels: idle channels first, then by idle days (descending)
-	sort.Slice(channels, func(i, j int) bool {
+	sort.SliceStable(channels, func(i, j int) bool {
 		if channels[i].IsIdle != channels[j].IsIdle {
 			return channels[i].IsIdle // true comes first
 		}
@@ -194,8 +248,12 @@ func FormatConfigDeliveryChannelsTable(writer io.Writer, channels []models.Confi
 	// Print each channel
 	for _, channel := range channels {
 		lastActivityStr := "Never"
+		idleDaysStr := fmt.Sprintf("%d", channel.IdleDays)
 		if channel.LastActivity != nil {
 			lastActivityStr = formatDate(*channel.LastActivity)
+		} else if channel.NeverDelivered {
+			lastActivityStr = "Never delivered"
+			idleDaysStr = "-"
 		}
 
 		snsTopicStr := "-"
@@ -213,28 +271,38 @@ func FormatConfigDeliveryChannelsTable(writer io.Writer, channels []models.Confi
 			idleStatus = "Yes"
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			channel.ChannelName,
 			channel.S3BucketName,
 			snsTopicStr,
 			frequencyStr,
 			lastActivityStr,
-			channel.IdleDays,
+			idleDaysStr,
 			idleStatus,
 			channel.Region,
 		)
 	}
 
 	w.Flush()
+}
+
+// FormatConfigDeliveryChannelsSummary writes the idle summary line for AWS Config delivery channels
+func FormatConfigDeliveryChannelsSummary(writer io.Writer, channels []models.ConfigDeliveryChannelInfo) {
+	if len(channels) == 0 {
+		return
+	}
 
-	// Print summary
 	idleCount := 0
+	neverDeliveredCount := 0
 	for _, channel := range channels {
 		if channel.IsIdle {
 			idleCount++
 		}
+		if channel.NeverDelivered {
+			neverDeliveredCount++
+		}
 	}
 
-	fmt.Fprintf(writer, "\nSummary: %d idle AWS Config delivery channels out of %d total delivery channels\n",
-		idleCount, len(channels))
+	fmt.Fprintf(writer, "\nSummary: %d idle AWS Config delivery channels out of %d total delivery channels (%d never delivered)\n",
+		idleCount, len(channels), neverDeliveredCount)
 }
@@ -2,7 +2,7 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
 	"strings"
 	"text/tabwriter"
@@ -14,114 +14,201 @@ import (
 // MAX_NAME_WIDTH defines the maximum width for Name column
 const MAX_NAME_WIDTH = 20
 
+// maxVolumeSourceLength truncates long snapshot descriptions in the SOURCE column
+const maxVolumeSourceLength = 40
+
 // PrintVolumesTable prints a formatted table of available EBS volumes
-func PrintVolumesTable(volumes []models.VolumeInfo, scanTime time.Time, scanDuration time.Duration) {
+func PrintVolumesTable(writer io.Writer, volumes []models.VolumeInfo, scanTime time.Time, scanDuration time.Duration) {
 	if len(volumes) == 0 {
-		fmt.Println("No available EBS volumes found.")
+		fmt.Fprintln(writer, "No available EBS volumes found.")
 		return
 	}
 
 	// Sort volumes by estimated savings (highest first)
-	sort.Slice(volumes, func(i, j int) bool {
+	sort.SliceStable(volumes, func(i, j int) bool {
 		return volumes[i].EstimatedSavings > volumes[j].EstimatedSavings
 	})
 
-	// kubectl 스타일 tabwriter 설정
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
-
-	// Print header as requested
-	fmt.Fprintln(w, "NAME\tVOLUME ID\tTYPE\tSIZE\tSTATUS\tMONTHLY SAVINGS\tPRICING")
-
-	// Pre-process names to handle Korean and get max string width
-	processedNames := make([]string, len(volumes))
-	for i, volume := range volumes {
-		// Handle empty name case
-		name := volume.Name
-		if name == "" {
-			name = "N/A"
-		}
+	volumesTable.Print(writer, volumes)
+}
 
-		// Limit name length and ensure proper width for Korean
-		if StringWidth(name) > MAX_NAME_WIDTH {
-			// Truncate name if it's too long while preserving proper display width
-			truncated := ""
-			currentWidth := 0
-			for _, r := range name {
-				charWidth := RuneWidth(r)
-				if currentWidth+charWidth > MAX_NAME_WIDTH-2 { // -2 for ".."
-					break
-				}
-				truncated += string(r)
-				currentWidth += charWidth
-			}
-			name = truncated + ".."
-		}
+// formatVolumeName truncates and pads a volume name to MAX_NAME_WIDTH, handling wide (e.g., Korean) characters
+func formatVolumeName(name string) string {
+	// Handle empty name case
+	if name == "" {
+		name = "N/A"
+	}
 
-		// Pad with spaces to ensure consistent column width
-		nameWidth := StringWidth(name)
-		paddingNeeded := MAX_NAME_WIDTH - nameWidth
-		if paddingNeeded > 0 {
-			name = name + strings.Repeat(" ", paddingNeeded)
+	// Limit name length and ensure proper width for Korean
+	if StringWidth(name) > MAX_NAME_WIDTH {
+		// Truncate name if it's too long while preserving proper display width
+		truncated := ""
+		currentWidth := 0
+		for _, r := range name {
+			charWidth := RuneWidth(r)
+			if currentWidth+charWidth > MAX_NAME_WIDTH-2 { // -2 for ".."
+				break
+			}
+			truncated += string(r)
+			currentWidth += charWidth
 		}
+		name = truncated + ".."
+	}
 
-		processedNames[i] = name
+	// Pad with spaces to ensure consistent column width
+	if paddingNeeded := MAX_NAME_WIDTH - StringWidth(name); paddingNeeded > 0 {
+		name = name + strings.Repeat(" ", paddingNeeded)
 	}
 
-	// Print each volume
-	for i, volume := range volumes {
-		// Format the monthly cost and savings with 2 decimal places
-		var savings string
-		if volume.PricingSource == "N/A" {
-			savings = "N/A"
-		} else {
-			savings = fmt.Sprintf("$%.2f", volume.EstimatedSavings)
-		}
+	return name
+}
 
-		// Add a marker for pricing source
-		pricingMarker := GetPricingMarker(volume.PricingSource)
-
-		// Use pre-processed name with proper spacing
-		fmt.Fprintf(w, "%s\t%s\t%s\t%d GB\t%s\t%s\t%s\n",
-			processedNames[i],
-			volume.VolumeID,
-			volume.VolumeType,
-			volume.Size,
-			volume.State,
-			savings,
-			pricingMarker,
-		)
+// attachedTo returns the stopped instance a volume is attached to, or "-" if detached
+func attachedTo(volume models.VolumeInfo) string {
+	if volume.AttachedInstanceID == "" {
+		return "-"
 	}
+	return volume.AttachedInstanceID
+}
 
-	// Print totals
-	printVolumeTotals(w, volumes)
+// lastAttached returns the instance a detached volume was last attached to with the detach
+// date, or "-" if unknown
+func lastAttached(volume models.VolumeInfo) string {
+	switch {
+	case volume.LastInstanceID != "" && volume.LastDetachTime != nil:
+		return fmt.Sprintf("%s (%s)", volume.LastInstanceID, volume.LastDetachTime.Format("2006-01-02"))
+	case volume.LastInstanceID != "":
+		return volume.LastInstanceID
+	}
+	return "-"
+}
 
-	w.Flush()
+// volumeSource returns what created a volume: the creator named by its tags, or else the
+// description or ID of the snapshot it was restored from, "-" if it was created empty
+func volumeSource(volume models.VolumeInfo) string {
+	switch {
+	case volume.CreatedBy != "":
+		return volume.CreatedBy
+	case volume.SourceSnapshotDesc != "":
+		return truncateString(volume.SourceSnapshotDesc, maxVolumeSourceLength)
+	case volume.SourceSnapshotID != "":
+		return volume.SourceSnapshotID
+	}
+	return "-"
 }
 
-// printVolumeTotals prints the summary information at the bottom of the table
-func printVolumeTotals(w *tabwriter.Writer, volumes []models.VolumeInfo) {
-	totalSize := 0
+// sourceSnapshot returns the snapshot a volume was created from, or "-" if it was created empty
+func sourceSnapshot(volume models.VolumeInfo) string {
+	if volume.SourceSnapshotID == "" {
+		return "-"
+	}
+	return volume.SourceSnapshotID
+}
 
-	// Calculate total potential savings
-	var totalSavings float64
+// latestSnapshot returns the date of the latest snapshot of a volume, "None" without one, or
+// "-" if the snapshot lookup failed
+func latestSnapshot(volume models.VolumeInfo) string {
+	if !volume.HasSnapshotInfo {
+		return "-"
+	}
+	if volume.LatestSnapshotDate == nil {
+		return "None"
+	}
+	return volume.LatestSnapshotDate.Format("2006-01-02")
+}
 
-	for _, volume := range volumes {
-		totalSavings += volume.EstimatedSavings
-		totalSize += volume.Size
+// volumeIOOps formats the read and write operations of a volume, "-" when CloudWatch reported nothing in the window
+func volumeIOOps(volume models.VolumeInfo) string {
+	if !volume.HasIOMetrics {
+		return "-"
 	}
+	return fmt.Sprintf("%.0f", volume.ReadOps+volume.WriteOps)
+}
 
-	// Format totals with 2 decimal places
-	formattedSavings := fmt.Sprintf("$%.2f", totalSavings)
+// volumeIdlePercent formats the share of the window a volume had no IO, "-" without metrics
+func volumeIdlePercent(volume models.VolumeInfo) string {
+	if !volume.HasIOMetrics {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", volume.IdleTimePercent)
+}
 
-	// Print summary with kubernetes style alignment
-	fmt.Fprintf(w, "Total:\t\t\t%d GB\t\t%s\n",
-		totalSize,
-		formattedSavings,
-	)
+// volumeLastIO formats the last day with IO, "None" if there was none in the window, or "-" without metrics
+func volumeLastIO(volume models.VolumeInfo) string {
+	switch {
+	case !volume.HasIOMetrics:
+		return "-"
+	case volume.LastIOTime == nil:
+		return "None"
+	default:
+		return volume.LastIOTime.Format("2006-01-02")
+	}
 }
 
+// volumeIOPS formats the provisioned performance, "-" for volume types without IOPS (st1, sc1, standard)
+func volumeIOPS(volume models.VolumeInfo) string {
+	if volume.IOPS == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", volume.IOPS)
+}
+
+// volumesTable defines the columns of the available EBS volumes table
+var volumesTable = newTable(Table[models.VolumeInfo]{
+	Service: "ebs",
+	Columns: []Column[models.VolumeInfo]{
+		{Key: "name", Header: "NAME", Value: func(v models.VolumeInfo) string { return formatVolumeName(v.Name) }},
+		{Key: "volume-id", Header: "VOLUME ID", Value: func(v models.VolumeInfo) string { return v.VolumeID }},
+		{Key: "type", Header: "TYPE", Value: func(v models.VolumeInfo) string { return v.VolumeType }},
+		{Key: "region", Header: "REGION", Value: func(v models.VolumeInfo) string { return v.Region },
+			Total: countColumn[models.VolumeInfo]("%d vols")},
+		{Key: "az", Header: "AZ", Value: func(v models.VolumeInfo) string { return v.AvailabilityZone }, Hidden: true},
+		{Key: "size", Header: "SIZE",
+			Value:  func(v models.VolumeInfo) string { return fmt.Sprintf("%d GB", v.Size) },
+			Number: func(v models.VolumeInfo) float64 { return float64(v.Size) },
+			Total: func(volumes []models.VolumeInfo) string {
+				totalSize := 0
+				for _, volume := range volumes {
+					totalSize += volume.Size
+				}
+				return fmt.Sprintf("%d GB", totalSize)
+			}},
+		{Key: "iops", Header: "IOPS", Value: volumeIOPS, Number: func(v models.VolumeInfo) float64 { return float64(v.IOPS) }},
+		{Key: "status", Header: "STATUS", Value: func(v models.VolumeInfo) string { return v.State }},
+		{Key: "attached-to", Header: "ATTACHED-TO", Value: attachedTo},
+		{Key: "last-attached", Header: "LAST ATTACHED", Value: lastAttached},
+		{Key: "io-ops", Header: "IO OPS (30D)",
+			Value:  volumeIOOps,
+			Number: func(v models.VolumeInfo) float64 { return v.ReadOps + v.WriteOps }},
+		{Key: "idle-percent", Header: "IDLE %",
+			Value:  volumeIdlePercent,
+			Number: func(v models.VolumeInfo) float64 { return v.IdleTimePercent }},
+		{Key: "last-io", Header: "LAST IO", Value: volumeLastIO},
+		{Key: "snapshot", Header: "SNAPSHOT", Value: latestSnapshot},
+		{Key: "source", Header: "SOURCE", Value: volumeSource},
+		{Key: "source-snapshot", Header: "SOURCE SNAPSHOT", Value: sourceSnapshot, Hidden: true},
+		{Key: "created", Header: "CREATED", Value: func(v models.VolumeInfo) string { return v.CreationTime.Format("2006-01-02") }, Hidden: true},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value:  func(v models.VolumeInfo) string { return fmt.Sprintf("%d", v.ElapsedDaysSinceUsed) },
+			Number: func(v models.VolumeInfo) float64 { return float64(v.ElapsedDaysSinceUsed) }, Hidden: true},
+		{Key: "savings", Header: "MONTHLY SAVINGS",
+			Value:  func(v models.VolumeInfo) string { return formatPricedCost(v.EstimatedSavings, v.PricingSource) },
+			Number: func(v models.VolumeInfo) float64 { return v.EstimatedSavings },
+			Total:  sumColumn(func(v models.VolumeInfo) float64 { return v.EstimatedSavings })},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(v models.VolumeInfo) string { return formatPricedCost(v.EstimatedMonthlyCost, v.PricingSource) },
+			Number: func(v models.VolumeInfo) float64 { return v.EstimatedMonthlyCost },
+			Total:  sumColumn(func(v models.VolumeInfo) float64 { return v.EstimatedMonthlyCost }), Hidden: true},
+		{Key: "pricing", Header: "PRICING", Value: func(v models.VolumeInfo) string { return GetPricingMarker(v.PricingSource) }},
+		{Key: "arn", Header: "ARN", Value: func(v models.VolumeInfo) string { return v.ARN }, Hidden: true},
+	},
+	Region: func(v models.VolumeInfo) string { return v.Region },
+	Tags:   func(v models.VolumeInfo) map[string]string { return v.Tags },
+	Links:  true,
+})
+
 // PrintVolumesSummary displays summary information about volumes
-func PrintVolumesSummary(volumes []models.VolumeInfo) {
+func PrintVolumesSummary(writer io.Writer, volumes []models.VolumeInfo) {
 	if len(volumes) == 0 {
 		return
 	}
@@ -141,10 +228,10 @@ func PrintVolumesSummary(volumes []models.VolumeInfo) {
 		volumeTypes[volume.VolumeType] = typeInfo
 	}
 
-	fmt.Println("\n## Available EBS Volumes Summary")
+	fmt.Fprintln(writer, "\n## Idle EBS Volumes Summary")
 
 	// kubectl 스타일 tabwriter 설정
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 
 	// Print header
 	fmt.Fprintln(w, "VOLUME TYPE\tCOUNT\tTOTAL SIZE\tPOTENTIAL MONTHLY SAVINGS")
@@ -168,4 +255,83 @@ func PrintVolumesSummary(volumes []models.VolumeInfo) {
 	}
 
 	w.Flush()
+
+	printVolumeCategories(writer, volumes)
+	printVolumeSnapshotBreakdown(writer, volumes)
+	printRegionBreakdown(writer, "Idle EBS Volumes by Region", volumes,
+		func(v models.VolumeInfo) string { return v.Region },
+		func(v models.VolumeInfo) float64 { return v.EstimatedMonthlyCost },
+		func(v models.VolumeInfo) float64 { return v.EstimatedSavings })
+}
+
+// printVolumeCategories breaks volumes down into detached volumes and volumes
+// attached to stopped instances, only when the latter were scanned
+func printVolumeCategories(writer io.Writer, volumes []models.VolumeInfo) {
+	var detachedCount, attachedCount, detachedSize, attachedSize int
+	var detachedSavings, attachedSavings float64
+
+	for _, volume := range volumes {
+		if volume.AttachedInstanceID == "" {
+			detachedCount++
+			detachedSize += volume.Size
+			detachedSavings += volume.EstimatedSavings
+		} else {
+			attachedCount++
+			attachedSize += volume.Size
+			attachedSavings += volume.EstimatedSavings
+		}
+	}
+
+	if attachedCount == 0 {
+		return
+	}
+
+	fmt.Fprintln(writer, "\n## Idle EBS Volumes by Category")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tCOUNT\tTOTAL SIZE\tPOTENTIAL MONTHLY SAVINGS")
+	fmt.Fprintf(w, "Detached\t%d\t%d GB\t$%.2f\n", detachedCount, detachedSize, detachedSavings)
+	fmt.Fprintf(w, "Attached to stopped instance\t%d\t%d GB\t$%.2f\n", attachedCount, attachedSize, attachedSavings)
+	w.Flush()
+}
+
+// printVolumeSnapshotBreakdown splits the potential savings into volumes that are safe to
+// delete because they have a recent snapshot and volumes without one. Volumes whose snapshot
+// lookup failed are listed separately.
+func printVolumeSnapshotBreakdown(writer io.Writer, volumes []models.VolumeInfo) {
+	var snapshottedCount, unsnapshottedCount, unknownCount int
+	var snapshottedSize, unsnapshottedSize, unknownSize int
+	var snapshottedSavings, unsnapshottedSavings, unknownSavings float64
+
+	for _, volume := range volumes {
+		switch {
+		case !volume.HasSnapshotInfo:
+			unknownCount++
+			unknownSize += volume.Size
+			unknownSavings += volume.EstimatedSavings
+		case volume.HasRecentSnapshot:
+			snapshottedCount++
+			snapshottedSize += volume.Size
+			snapshottedSavings += volume.EstimatedSavings
+		default:
+			unsnapshottedCount++
+			unsnapshottedSize += volume.Size
+			unsnapshottedSavings += volume.EstimatedSavings
+		}
+	}
+
+	if unknownCount == len(volumes) {
+		return
+	}
+
+	fmt.Fprintln(writer, "\n## Idle EBS Volumes by Snapshot")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tCOUNT\tTOTAL SIZE\tPOTENTIAL MONTHLY SAVINGS")
+	fmt.Fprintf(w, "Safe to delete (snapshotted)\t%d\t%d GB\t$%.2f\n", snapshottedCount, snapshottedSize, snapshottedSavings)
+	fmt.Fprintf(w, "No recent snapshot\t%d\t%d GB\t$%.2f\n", unsnapshottedCount, unsnapshottedSize, unsnapshottedSavings)
+	if unknownCount > 0 {
+		fmt.Fprintf(w, "Unknown (snapshot lookup failed)\t%d\t%d GB\t$%.2f\n", unknownCount, unknownSize, unknownSavings)
+	}
+	w.Flush()
 }
@@ -2,7 +2,7 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
 	"text/tabwriter"
 	"time"
@@ -11,64 +11,53 @@ import (
 )
 
 // PrintInstancesTable prints a formatted table of EC2 instances
-func PrintInstancesTable(instances []models.InstanceInfo, scanTime time.Time, scanDuration time.Duration) {
+func PrintInstancesTable(writer io.Writer, instances []models.InstanceInfo, scanTime time.Time, scanDuration time.Duration) {
 	if len(instances) == 0 {
-		fmt.Println("No idle instances found.")
+		fmt.Fprintln(writer, "No idle instances found.")
 		return
 	}
 
 	// Sort instances by elapsed days (longest first)
-	sort.Slice(instances, func(i, j int) bool {
+	sort.SliceStable(instances, func(i, j int) bool {
 		return instances[i].ElapsedDays > instances[j].ElapsedDays
 	})
 
-	// kubectl 스타일 tabwriter 설정
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	instancesTable.Print(writer, instances)
 
-	// Print header
-	fmt.Fprintln(w, "INSTANCE ID\tNAME\tTYPE\tREGION\tSTOPPED SINCE\tDAYS\tCOST/MO\tTOTAL SAVED\tPRICING")
-
-	// Print each instance
+	hasEstimates := false
 	for _, instance := range instances {
-		// Format the stopped time
-		stoppedTimeStr := ""
-		if instance.StoppedTime != nil {
-			stoppedTimeStr = instance.StoppedTime.Format("2006-01-02")
-		} else {
-			stoppedTimeStr = "Unknown"
-		}
-
-		// Format the monthly cost and savings with 2 decimal places
-		var monthlyCost, savings string
-		if instance.PricingSource == "N/A" {
-			monthlyCost = "N/A"
-			savings = "N/A"
-		} else {
-			monthlyCost = fmt.Sprintf("$%.2f", instance.EstimatedMonthlyCost)
-			savings = fmt.Sprintf("$%.2f", instance.EstimatedSavings)
+		if instance.StoppedTimeSource == models.StoppedTimeSourceLaunchTime {
+			hasEstimates = true
 		}
+	}
+	if hasEstimates {
+		fmt.Fprintln(writer, "\n≥ Stop time unknown, estimated from the last launch time. DAYS and COMPUTE SAVED are upper bounds.")
+	}
+	fmt.Fprintln(writer, "CURRENT COST/MO is what stopped instances are still billed for: attached EBS volumes and Elastic IPs.")
+}
 
-		// Get pricing source marker
-		pricingMarker := GetPricingMarker(instance.PricingSource)
-
-		// Print row
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
-			instance.InstanceID,
-			getInstanceName(instance.Name),
-			instance.InstanceType,
-			instance.Region,
-			stoppedTimeStr,
-			instance.ElapsedDays,
-			monthlyCost,
-			savings,
-			pricingMarker,
-		)
+// formatCurrentCost formats the storage and Elastic IP cost still billed for a stopped instance
+func formatCurrentCost(instance models.InstanceInfo) string {
+	if instance.CurrentCostUnpriced {
+		return "N/A"
+	}
+	if instance.ElasticIP != "" {
+		return fmt.Sprintf("$%.2f (+EIP)", instance.CurrentMonthlyCost)
 	}
+	return fmt.Sprintf("$%.2f", instance.CurrentMonthlyCost)
+}
 
-	// Print totals without separator
-	printTotals(w, instances)
+// formatStoppedTime formats the stop date, marking launch time estimates with "≥"
+func formatStoppedTime(instance models.InstanceInfo) string {
+	if instance.StoppedTime == nil {
+		return "Unknown"
+	}
 
-	w.Flush()
+	stoppedTimeStr := instance.StoppedTime.Format("2006-01-02")
+	if instance.StoppedTimeSource == models.StoppedTimeSourceLaunchTime {
+		return "≥" + stoppedTimeStr
+	}
+	return stoppedTimeStr
 }
 
 // getInstanceName returns a formatted instance name or <unnamed> if empty
@@ -79,33 +68,42 @@ func getInstanceName(name string) string {
 	return name
 }
 
-// printTotals prints the summary information at the bottom of the table
-func printTotals(w *tabwriter.Writer, instances []models.InstanceInfo) {
-	totalInstances := len(instances)
-
-	// Calculate total potential monthly cost and actual savings
-	var totalMonthlyCost float64
-	var totalSavings float64
-
-	for _, instance := range instances {
-		totalMonthlyCost += instance.EstimatedMonthlyCost
-		totalSavings += instance.EstimatedSavings
-	}
-
-	// Format totals with 2 decimal places
-	formattedMonthlyCost := fmt.Sprintf("$%.2f", totalMonthlyCost)
-	formattedSavings := fmt.Sprintf("$%.2f", totalSavings)
-
-	// Print summary with kubernetes style alignment
-	fmt.Fprintf(w, "Total:\t\t\t\t\t%d\t%s\t%s\n",
-		totalInstances,
-		formattedMonthlyCost,
-		formattedSavings,
-	)
-}
+// instancesTable defines the columns of the stopped EC2 instances table
+var instancesTable = newTable(Table[models.InstanceInfo]{
+	Service: "ec2",
+	Columns: []Column[models.InstanceInfo]{
+		{Key: "instance-id", Header: "INSTANCE ID", Value: func(i models.InstanceInfo) string { return i.InstanceID }},
+		{Key: "name", Header: "NAME", Value: func(i models.InstanceInfo) string { return getInstanceName(i.Name) }},
+		{Key: "type", Header: "TYPE", Value: func(i models.InstanceInfo) string { return i.InstanceType }},
+		{Key: "region", Header: "REGION", Value: func(i models.InstanceInfo) string { return i.Region }},
+		{Key: "az", Header: "AZ", Value: func(i models.InstanceInfo) string { return i.AvailabilityZone }, Hidden: true},
+		{Key: "stopped-since", Header: "STOPPED SINCE", Value: formatStoppedTime},
+		{Key: "days", Header: "DAYS",
+			Value:  func(i models.InstanceInfo) string { return fmt.Sprintf("%d", i.ElapsedDays) },
+			Number: func(i models.InstanceInfo) float64 { return float64(i.ElapsedDays) },
+			Total:  countColumn[models.InstanceInfo]("%d")},
+		{Key: "compute-cost", Header: "COMPUTE/MO",
+			Value:  func(i models.InstanceInfo) string { return formatPricedCost(i.EstimatedMonthlyCost, i.PricingSource) },
+			Number: func(i models.InstanceInfo) float64 { return i.EstimatedMonthlyCost },
+			Total:  sumColumn(func(i models.InstanceInfo) float64 { return i.EstimatedMonthlyCost })},
+		{Key: "compute-saved", Header: "COMPUTE SAVED",
+			Value:  func(i models.InstanceInfo) string { return formatPricedCost(i.EstimatedSavings, i.PricingSource) },
+			Number: func(i models.InstanceInfo) float64 { return i.EstimatedSavings },
+			Total:  sumColumn(func(i models.InstanceInfo) float64 { return i.EstimatedSavings })},
+		{Key: "cost", Header: "CURRENT COST/MO", Value: formatCurrentCost,
+			Number: func(i models.InstanceInfo) float64 { return i.CurrentMonthlyCost },
+			Total:  sumColumn(func(i models.InstanceInfo) float64 { return i.CurrentMonthlyCost })},
+		{Key: "pricing", Header: "PRICING", Value: func(i models.InstanceInfo) string { return GetPricingMarker(i.PricingSource) }},
+		protectedColumn[models.InstanceInfo](),
+		{Key: "arn", Header: "ARN", Value: func(i models.InstanceInfo) string { return i.ARN }, Hidden: true},
+	},
+	Region: func(i models.InstanceInfo) string { return i.Region },
+	Tags:   func(i models.InstanceInfo) map[string]string { return i.Tags },
+	Links:  true,
+})
 
 // PrintInstancesSummary displays summary information about instances
-func PrintInstancesSummary(instances []models.InstanceInfo) {
+func PrintInstancesSummary(writer io.Writer, instances []models.InstanceInfo) {
 	if len(instances) == 0 {
 		return
 	}
@@ -141,10 +139,10 @@ func PrintInstancesSummary(instances []models.InstanceInfo) {
 		}
 	}
 
-	fmt.Println("\n## Stopped EC2 Instances Summary")
+	fmt.Fprintln(writer, "\n## Stopped EC2 Instances Summary")
 
 	// 요약 정보 출력을 kubectl 스타일로 설정
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 
 	// Print header
 	fmt.Fprintln(w, "PERIOD STOPPED\tINSTANCE COUNT")
@@ -158,6 +156,34 @@ func PrintInstancesSummary(instances []models.InstanceInfo) {
 	}
 
 	w.Flush()
+
+	// Compare keeping the instances stopped with terminating them
+	var computeSaved, storageCost, eipCost float64
+	var volumeCount, eipCount int
+	for _, instance := range instances {
+		computeSaved += instance.EstimatedSavings
+		storageCost += instance.StorageMonthlyCost
+		eipCost += instance.EIPMonthlyCost
+		volumeCount += instance.AttachedVolumes
+		if instance.ElasticIP != "" {
+			eipCount++
+		}
+	}
+
+	fmt.Fprintln(writer, "\n## Stopped EC2 Instances Cost")
+
+	w = tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "COST\tAMOUNT")
+	fmt.Fprintf(w, "Compute saved while stopped\t$%.2f\n", computeSaved)
+	fmt.Fprintf(w, "EBS storage (%d volumes)\t$%.2f/mo\n", volumeCount, storageCost)
+	fmt.Fprintf(w, "Elastic IPs (%d)\t$%.2f/mo\n", eipCount, eipCost)
+	fmt.Fprintf(w, "Saved by terminating\t$%.2f/mo\n", storageCost+eipCost)
+	w.Flush()
+
+	printRegionBreakdown(writer, "Stopped EC2 Instances by Region", instances,
+		func(i models.InstanceInfo) string { return i.Region },
+		func(i models.InstanceInfo) float64 { return i.CurrentMonthlyCost },
+		func(i models.InstanceInfo) float64 { return i.EstimatedSavings })
 }
 
 // GetPricingMarker returns a suitable marker for the pricing source
@@ -167,6 +193,10 @@ func GetPricingMarker(source string) string {
 		return "API"
 	case "Cache":
 		return "CACHE"
+	case "Default":
+		return "DEFAULT"
+	case "Estimated":
+		return "ESTIMATED"
 	case "N/A":
 		return "N/A"
 	default:
@@ -0,0 +1,129 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// PrintUnderutilizedInstancesTable prints a formatted table of underutilized running EC2 instances
+func PrintUnderutilizedInstancesTable(writer io.Writer, instances []models.UnderutilizedInstanceInfo, scanTime time.Time, scanDuration time.Duration) {
+	if len(instances) == 0 {
+		fmt.Fprintln(writer, "No underutilized running instances found.")
+		return
+	}
+
+	// Sort instances by monthly cost (most expensive first)
+	sort.SliceStable(instances, func(i, j int) bool {
+		return instances[i].EstimatedMonthlyCost > instances[j].EstimatedMonthlyCost
+	})
+
+	underutilizedInstancesTable.Print(writer, instances)
+
+	fmt.Fprintf(writer, "\nMetrics cover the last %d days. NETWORK (inbound and outbound) and EBS IO (reads and writes) are average throughputs.\n",
+		instances[0].LookbackDays)
+}
+
+// formatThroughput formats an average throughput in bytes per second
+func formatThroughput(bytesPerSec float64) string {
+	return utils.FormatBytes(int64(bytesPerSec)) + "/s"
+}
+
+// formatAutoScalingGroup returns the Auto Scaling group of an instance, or "-" if none
+func formatAutoScalingGroup(instance models.UnderutilizedInstanceInfo) string {
+	if instance.AutoScalingGroup == "" {
+		return "-"
+	}
+	return instance.AutoScalingGroup
+}
+
+// underutilizedInstancesTable defines the columns of the underutilized EC2 instances table
+var underutilizedInstancesTable = newTable(Table[models.UnderutilizedInstanceInfo]{
+	Service: "ec2-underutilized",
+	Columns: []Column[models.UnderutilizedInstanceInfo]{
+		{Key: "instance-id", Header: "INSTANCE ID", Value: func(i models.UnderutilizedInstanceInfo) string { return i.InstanceID }},
+		{Key: "name", Header: "NAME", Value: func(i models.UnderutilizedInstanceInfo) string { return getInstanceName(i.Name) }},
+		{Key: "type", Header: "TYPE", Value: func(i models.UnderutilizedInstanceInfo) string { return i.InstanceType }},
+		{Key: "region", Header: "REGION", Value: func(i models.UnderutilizedInstanceInfo) string { return i.Region }},
+		{Key: "az", Header: "AZ", Value: func(i models.UnderutilizedInstanceInfo) string { return i.AvailabilityZone }, Hidden: true},
+		{Key: "launched", Header: "LAUNCHED", Value: func(i models.UnderutilizedInstanceInfo) string { return i.LaunchTime.Format("2006-01-02") }, Hidden: true},
+		{Key: "avg-cpu", Header: "AVG CPU",
+			Value:  func(i models.UnderutilizedInstanceInfo) string { return fmt.Sprintf("%.1f%%", i.AvgCPU) },
+			Number: func(i models.UnderutilizedInstanceInfo) float64 { return i.AvgCPU }},
+		{Key: "max-cpu", Header: "MAX CPU",
+			Value:  func(i models.UnderutilizedInstanceInfo) string { return fmt.Sprintf("%.1f%%", i.MaxCPU) },
+			Number: func(i models.UnderutilizedInstanceInfo) float64 { return i.MaxCPU }},
+		{Key: "network", Header: "NETWORK",
+			Value:  func(i models.UnderutilizedInstanceInfo) string { return formatThroughput(i.NetworkBytesPerSec) },
+			Number: func(i models.UnderutilizedInstanceInfo) float64 { return i.NetworkBytesPerSec }},
+		{Key: "ebs-io", Header: "EBS IO",
+			Value: func(i models.UnderutilizedInstanceInfo) string {
+				if !i.HasEBSMetrics {
+					return "-"
+				}
+				return formatThroughput(i.EBSBytesPerSec)
+			},
+			Number: func(i models.UnderutilizedInstanceInfo) float64 { return i.EBSBytesPerSec }},
+		{Key: "asg", Header: "ASG", Value: formatAutoScalingGroup},
+		{Key: "cost", Header: "COST/MO",
+			Value: func(i models.UnderutilizedInstanceInfo) string {
+				return formatPricedCost(i.EstimatedMonthlyCost, i.PricingSource)
+			},
+			Number: func(i models.UnderutilizedInstanceInfo) float64 { return i.EstimatedMonthlyCost },
+			Total:  sumCountColumn(func(i models.UnderutilizedInstanceInfo) float64 { return i.EstimatedMonthlyCost }, "instances")},
+		{Key: "pricing", Header: "PRICING", Value: func(i models.UnderutilizedInstanceInfo) string { return GetPricingMarker(i.PricingSource) }},
+		{Key: "arn", Header: "ARN", Value: func(i models.UnderutilizedInstanceInfo) string { return i.ARN }, Hidden: true},
+	},
+	Region: func(i models.UnderutilizedInstanceInfo) string { return i.Region },
+	Tags:   func(i models.UnderutilizedInstanceInfo) map[string]string { return i.Tags },
+	Links:  true,
+})
+
+// PrintUnderutilizedInstancesSummary displays summary information about underutilized running instances
+func PrintUnderutilizedInstancesSummary(writer io.Writer, instances []models.UnderutilizedInstanceInfo) {
+	if len(instances) == 0 {
+		return
+	}
+
+	// Group by instance type
+	type typeTotal struct {
+		count int
+		cost  float64
+	}
+	totals := make(map[string]*typeTotal)
+	var instanceTypes []string
+	for _, instance := range instances {
+		total, found := totals[instance.InstanceType]
+		if !found {
+			total = &typeTotal{}
+			totals[instance.InstanceType] = total
+			instanceTypes = append(instanceTypes, instance.InstanceType)
+		}
+		total.count++
+		total.cost += instance.EstimatedMonthlyCost
+	}
+
+	// Most expensive instance types first
+	sort.SliceStable(instanceTypes, func(i, j int) bool {
+		return totals[instanceTypes[i]].cost > totals[instanceTypes[j]].cost
+	})
+
+	fmt.Fprintln(writer, "\n## Underutilized EC2 Instances by Type")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tCOUNT\tCOST/MO")
+	for _, instanceType := range instanceTypes {
+		fmt.Fprintf(w, "%s\t%d\t$%.2f\n", instanceType, totals[instanceType].count, totals[instanceType].cost)
+	}
+	w.Flush()
+
+	printRegionBreakdown(writer, "Underutilized EC2 Instances by Region", instances,
+		func(i models.UnderutilizedInstanceInfo) string { return i.Region },
+		func(i models.UnderutilizedInstanceInfo) float64 { return i.EstimatedMonthlyCost },
+		nil)
+}
@@ -2,8 +2,9 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
+	"strconv"
 	"text/tabwriter"
 	"time"
 
@@ -12,14 +13,14 @@ import (
 )
 
 // PrintECRTable formats and prints ECR repository information in a table, mimicking EC2 style.
-func PrintECRTable(repos []models.RepositoryInfo, _ time.Time, _ time.Duration) { // scanStartTime, scanDuration removed as spinner handles it
+func PrintECRTable(writer io.Writer, repos []models.RepositoryInfo, _ time.Time, _ time.Duration) { // scanStartTime, scanDuration removed as spinner handles it
 	if len(repos) == 0 {
 		// Message handled by spinner
 		return
 	}
 
 	// Sort by last push time (oldest first, nil/never last)
-	sort.Slice(repos, func(i, j int) bool {
+	sort.SliceStable(repos, func(i, j int) bool {
 		if repos[i].LastPush == nil && repos[j].LastPush == nil {
 			return repos[i].Name < repos[j].Name // Secondary sort by name if both never pushed
 		}
@@ -32,41 +33,87 @@ func PrintECRTable(repos []models.RepositoryInfo, _ time.Time, _ time.Duration)
 		return repos[i].LastPush.Before(*repos[j].LastPush)
 	})
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0) // Use tabwriter like EC2
+	repositoriesTable.Print(writer, repos)
+}
 
-	// Print header, matching EC2 style, with TOTAL IMAGE
-	fmt.Fprintln(w, "NAME\tREGION\tLAST PUSH\tTOTAL IMAGE\tIDLE")
+// repositoriesTable defines the columns of the ECR repositories table
+var repositoriesTable = newTable(Table[models.RepositoryInfo]{
+	Service: "ecr",
+	Columns: []Column[models.RepositoryInfo]{
+		{Key: "name", Header: "NAME", Value: func(r models.RepositoryInfo) string { return r.Name }},
+		{Key: "region", Header: "REGION", Value: func(r models.RepositoryInfo) string { return r.Region }},
+		{Key: "last-push", Header: "LAST PUSH",
+			Value: func(r models.RepositoryInfo) string {
+				if r.LastPush == nil {
+					return "Never"
+				}
+				return utils.FormatTimeAgo(*r.LastPush) // Use the shortened format
+			},
+			Number: func(r models.RepositoryInfo) float64 {
+				if r.LastPush == nil {
+					return 0
+				}
+				return float64(r.LastPush.Unix())
+			}},
+		{Key: "images", Header: "TOTAL IMAGE",
+			Value:  func(r models.RepositoryInfo) string { return strconv.Itoa(r.ImageCount) },
+			Number: func(r models.RepositoryInfo) float64 { return float64(r.ImageCount) }},
+		{Key: "idle", Header: "IDLE", Value: func(r models.RepositoryInfo) string { return strconv.FormatBool(r.Idle) }},
+		firstSeenColumn[models.RepositoryInfo](),
+	},
+	Tags:    func(r models.RepositoryInfo) map[string]string { return r.Tags },
+	NoTotal: true,
+})
 
+// PrintECRSummary prints a simple summary of total and idle repositories.
+func PrintECRSummary(writer io.Writer, repos []models.RepositoryInfo) {
+	if len(repos) == 0 {
+		return // No summary needed if no repos found
+	}
+	idleCount := 0
 	for _, repo := range repos {
+		if repo.Idle {
+			idleCount++
+		}
+	}
+	fmt.Fprintf(writer, "\nECR Summary: %d total repositories found, %d identified as idle.\n", len(repos), idleCount)
+}
+
+// PrintECRScanCoverageTable prints idle ECR repositories that are still enrolled in
+// Amazon Inspector2 enhanced scanning.
+func PrintECRScanCoverageTable(writer io.Writer, coverage []models.ECRScanCoverageInfo) {
+	fmt.Fprintln(writer, "\n## Inspector2 Enhanced Scanning (Idle ECR Repositories)")
+
+	if len(coverage) == 0 {
+		fmt.Fprintln(writer, "No idle repositories are enrolled in enhanced scanning.")
+		return
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "NAME\tREGION\tSCAN FREQUENCY\tLAST SCANNED\tLAST PUSH")
+
+	for _, repo := range coverage {
+		lastScannedStr := "Never"
+		if repo.LastScannedAt != nil {
+			lastScannedStr = utils.FormatTimeAgo(*repo.LastScannedAt)
+		}
 		lastPushStr := "Never"
 		if repo.LastPush != nil {
-			lastPushStr = utils.FormatTimeAgo(*repo.LastPush) // Use the shortened format
+			lastPushStr = utils.FormatTimeAgo(*repo.LastPush)
 		}
-		idleStr := fmt.Sprintf("%t", repo.Idle)
 
-		// Print row using tabwriter, including image count
-		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
-			repo.Name,
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			repo.RepositoryName,
 			repo.Region,
+			repo.ScanFrequency,
+			lastScannedStr,
 			lastPushStr,
-			repo.ImageCount, // Add image count here
-			idleStr,
 		)
 	}
 
 	w.Flush()
-}
 
-// PrintECRSummary prints a simple summary of total and idle repositories.
-func PrintECRSummary(repos []models.RepositoryInfo) {
-	if len(repos) == 0 {
-		return // No summary needed if no repos found
-	}
-	idleCount := 0
-	for _, repo := range repos {
-		if repo.Idle {
-			idleCount++
-		}
-	}
-	fmt.Printf("\nECR Summary: %d total repositories found, %d identified as idle.\n", len(repos), idleCount)
+	fmt.Fprintf(writer, "\n%d idle repositories still enrolled in enhanced scanning.\n", len(coverage))
+	fmt.Fprintln(writer, "Recommendation: exclude these repositories from enhanced scanning with a registry scanning filter to avoid Inspector2 rescan costs.")
 }
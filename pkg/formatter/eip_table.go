@@ -2,7 +2,7 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
 	"text/tabwriter"
 	"time"
@@ -10,80 +10,59 @@ import (
 	"github.com/younsl/idled/internal/models"
 )
 
-// PrintEIPsTable prints a formatted table of unattached Elastic IPs
-func PrintEIPsTable(eips []models.EIPInfo, scanTime time.Time, scanDuration time.Duration) {
+// PrintEIPsTable prints a formatted table of idle Elastic IPs
+func PrintEIPsTable(writer io.Writer, eips []models.EIPInfo, scanTime time.Time, scanDuration time.Duration) {
 	if len(eips) == 0 {
-		fmt.Println("No unattached Elastic IPs found.")
+		fmt.Fprintln(writer, "No idle Elastic IPs found.")
 		return
 	}
 
 	// Sort EIPs alphabetically by region
-	sort.Slice(eips, func(i, j int) bool {
+	sort.SliceStable(eips, func(i, j int) bool {
 		if eips[i].Region == eips[j].Region {
 			return eips[i].PublicIP < eips[j].PublicIP
 		}
 		return eips[i].Region < eips[j].Region
 	})
 
-	// Set up tabwriter with kubectl style spacing
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
-
-	// Print header
-	fmt.Fprintln(w, "ALLOCATION ID\tPUBLIC IP\tREGION\tSTATUS\tCOST/MO")
-
-	// Print each EIP
-	for _, eip := range eips {
-		// Format the monthly cost with 2 decimal places
-		monthlyCost := fmt.Sprintf("$%.2f", eip.EstimatedMonthlyCost)
-
-		// Print row
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			eip.AllocationID,
-			eip.PublicIP,
-			eip.Region,
-			eip.AssociationState,
-			monthlyCost,
-		)
-	}
-
-	// Print totals
-	printEIPTotals(w, eips)
-
-	w.Flush()
+	eipsTable.Print(writer, eips)
 }
 
-// printEIPTotals prints the summary information at the bottom of the table
-func printEIPTotals(w *tabwriter.Writer, eips []models.EIPInfo) {
-	totalEIPs := len(eips)
-
-	// Calculate total potential monthly cost
-	var totalMonthlyCost float64
-
-	for _, eip := range eips {
-		totalMonthlyCost += eip.EstimatedMonthlyCost
-	}
-
-	// Format totals with 2 decimal places
-	formattedMonthlyCost := fmt.Sprintf("$%.2f", totalMonthlyCost)
-
-	// Print summary with kubernetes style alignment
-	fmt.Fprintf(w, "Total:\t\t\t\t%s (%d EIPs)\n",
-		formattedMonthlyCost,
-		totalEIPs,
-	)
-}
-
-// PrintEIPsSummary displays summary information about unattached Elastic IPs
-func PrintEIPsSummary(eips []models.EIPInfo) {
+// eipsTable defines the columns of the idle Elastic IPs table
+var eipsTable = newTable(Table[models.EIPInfo]{
+	Service: "eip",
+	Columns: []Column[models.EIPInfo]{
+		{Key: "allocation-id", Header: "ALLOCATION ID", Value: func(e models.EIPInfo) string { return e.AllocationID }},
+		{Key: "public-ip", Header: "PUBLIC IP", Value: func(e models.EIPInfo) string { return e.PublicIP }},
+		{Key: "region", Header: "REGION", Value: func(e models.EIPInfo) string { return e.Region }},
+		{Key: "association", Header: "ASSOCIATION", Value: func(e models.EIPInfo) string { return e.AssociationState }},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(e models.EIPInfo) string { return fmt.Sprintf("$%.2f", e.EstimatedMonthlyCost) },
+			Number: func(e models.EIPInfo) float64 { return e.EstimatedMonthlyCost },
+			Total:  sumCountColumn(func(e models.EIPInfo) float64 { return e.EstimatedMonthlyCost }, "EIPs")},
+		{Key: "pricing", Header: "PRICING", Value: func(e models.EIPInfo) string { return GetPricingMarker(e.PricingSource) }},
+		firstSeenColumn[models.EIPInfo](),
+		{Key: "arn", Header: "ARN", Value: func(e models.EIPInfo) string { return e.ARN }, Hidden: true},
+	},
+	Region: func(e models.EIPInfo) string { return e.Region },
+	Tags:   func(e models.EIPInfo) map[string]string { return e.Tags },
+	Links:  true,
+})
+
+// PrintEIPsSummary displays summary information about idle Elastic IPs
+func PrintEIPsSummary(writer io.Writer, eips []models.EIPInfo) {
 	if len(eips) == 0 {
 		return
 	}
 
-	// Group by region
-	regionCounts := make(map[string]int)
+	// Group by region and association type
+	regionCounts := make(map[string]map[string]int)
 
 	for _, eip := range eips {
-		regionCounts[eip.Region]++
+		if regionCounts[eip.Region] == nil {
+			regionCounts[eip.Region] = make(map[string]int)
+		}
+		regionCounts[eip.Region][eip.AssociationType]++
 	}
 
 	// Prepare sorted list of regions
@@ -93,17 +72,24 @@ func PrintEIPsSummary(eips []models.EIPInfo) {
 	}
 	sort.Strings(regions)
 
-	fmt.Println("\n## Unattached Elastic IPs by Region")
+	fmt.Fprintln(writer, "\n## Idle Elastic IPs by Region")
 
 	// Set up tabwriter with kubectl style spacing
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 
 	// Print header
-	fmt.Fprintln(w, "REGION\tCOUNT")
+	fmt.Fprintln(w, "REGION\tUNATTACHED\tSTOPPED INSTANCE\tORPHANED ENI\tCOUNT")
 
 	// Print rows for each region
 	for _, region := range regions {
-		fmt.Fprintf(w, "%s\t%d\n", region, regionCounts[region])
+		counts := regionCounts[region]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n",
+			region,
+			counts[models.EIPAssociationUnattached],
+			counts[models.EIPAssociationStoppedInstance],
+			counts[models.EIPAssociationOrphanedENI],
+			counts[models.EIPAssociationUnattached]+counts[models.EIPAssociationStoppedInstance]+counts[models.EIPAssociationOrphanedENI],
+		)
 	}
 
 	w.Flush()
@@ -0,0 +1,79 @@
+package formatter
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares got with testdata/name, or rewrites the file with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s (run go test -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run go test -update if the change is intended\n--- got:\n%s", path, got)
+	}
+}
+
+func TestPrintEIPsTableGolden(t *testing.T) {
+	eips := []models.EIPInfo{
+		{
+			AllocationID:         "eipalloc-2",
+			PublicIP:             "198.51.100.2",
+			Region:               "us-east-1",
+			AssociationType:      models.EIPAssociationStoppedInstance,
+			AssociationState:     "Stopped instance i-0123456789abcdef0",
+			EstimatedMonthlyCost: 3.65,
+			PricingSource:        "API",
+		},
+		{
+			AllocationID:         "eipalloc-1",
+			PublicIP:             "198.51.100.1",
+			Region:               "us-east-1",
+			AssociationType:      models.EIPAssociationUnattached,
+			AssociationState:     "Unattached",
+			EstimatedMonthlyCost: 3.65,
+			PricingSource:        "Default",
+		},
+		{
+			AllocationID:         "eipalloc-3",
+			PublicIP:             "203.0.113.7",
+			Region:               "ap-northeast-2",
+			AssociationType:      models.EIPAssociationOrphanedENI,
+			AssociationState:     "Orphaned ENI eni-0abc",
+			EstimatedMonthlyCost: 3.65,
+			PricingSource:        "N/A",
+		},
+	}
+
+	var output bytes.Buffer
+	PrintEIPsTable(&output, eips, time.Time{}, 0)
+	PrintEIPsSummary(&output, eips)
+	checkGolden(t, "eip-table.golden", output.Bytes())
+}
+
+func TestPrintEIPsTableEmpty(t *testing.T) {
+	var output bytes.Buffer
+	PrintEIPsTable(&output, nil, time.Time{}, 0)
+	PrintEIPsSummary(&output, nil)
+	if got, want := output.String(), "No idle Elastic IPs found.\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
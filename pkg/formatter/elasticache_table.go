@@ -0,0 +1,124 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/pricing"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// PrintElastiCacheTable prints ElastiCache serverless caches and Valkey migration candidates in a table format.
+func PrintElastiCacheTable(writer io.Writer, caches []models.ElastiCacheInfo, _ time.Time, _ time.Duration) {
+	if len(caches) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort caches: idle first, then by estimated cost (descending)
+	sort.SliceStable(caches, func(i, j int) bool {
+		if caches[i].IsIdle != caches[j].IsIdle {
+			return caches[i].IsIdle // true comes first
+		}
+		return caches[i].EstimatedMonthlyCost > caches[j].EstimatedMonthlyCost
+	})
+
+	elastiCacheTable.Print(writer, caches)
+}
+
+// elastiCacheTable defines the columns of the ElastiCache table
+var elastiCacheTable = newTable(Table[models.ElastiCacheInfo]{
+	Service: "elasticache",
+	Columns: []Column[models.ElastiCacheInfo]{
+		{Key: "name", Header: "NAME", Value: func(c models.ElastiCacheInfo) string { return c.Name }},
+		{Key: "type", Header: "TYPE", Value: func(c models.ElastiCacheInfo) string { return c.DeploymentType }},
+		{Key: "region", Header: "REGION", Value: func(c models.ElastiCacheInfo) string { return c.Region }},
+		{Key: "engine", Header: "ENGINE", Value: func(c models.ElastiCacheInfo) string { return c.Engine }},
+		{Key: "version", Header: "VERSION", Value: func(c models.ElastiCacheInfo) string { return c.EngineVersion }},
+		{Key: "node-type", Header: "NODE TYPE", Value: func(c models.ElastiCacheInfo) string {
+			if c.NodeType == "" {
+				return "-"
+			}
+			return fmt.Sprintf("%s x%d", c.NodeType, c.NodeCount)
+		}},
+		{Key: "status", Header: "STATUS", Value: func(c models.ElastiCacheInfo) string { return c.Status }},
+		{Key: "stored-data", Header: "STORED DATA",
+			Value: func(c models.ElastiCacheInfo) string {
+				if c.StoredBytes == nil {
+					return "N/A"
+				}
+				return utils.FormatBytes(int64(*c.StoredBytes))
+			},
+			Number: func(c models.ElastiCacheInfo) float64 { return optionalFloat(c.StoredBytes) }},
+		{Key: "requests", Header: "REQUESTS (30d)",
+			Value: func(c models.ElastiCacheInfo) string {
+				if c.RequestCount != nil {
+					return fmt.Sprintf("%.0f", *c.RequestCount)
+				}
+				if c.IsIdle {
+					return "0"
+				}
+				return "N/A"
+			},
+			Number: func(c models.ElastiCacheInfo) float64 { return optionalFloat(c.RequestCount) }},
+		{Key: "cost", Header: "EST. COST/MONTH",
+			Value: func(c models.ElastiCacheInfo) string {
+				if c.EstimatedMonthlyCost <= 0 {
+					return "N/A"
+				}
+				return fmt.Sprintf("$%.2f", c.EstimatedMonthlyCost)
+			},
+			Number: func(c models.ElastiCacheInfo) float64 { return c.EstimatedMonthlyCost }},
+		{Key: "idle", Header: "IDLE", Value: func(c models.ElastiCacheInfo) string { return strconv.FormatBool(c.IsIdle) }},
+		{Key: "recommendation", Header: "RECOMMENDATION", Value: func(c models.ElastiCacheInfo) string {
+			if c.Recommendation == "" {
+				return "-"
+			}
+			return c.Recommendation
+		}},
+	},
+	NoTotal: true,
+})
+
+// PrintElastiCacheSummary prints idle serverless storage costs and Valkey migration savings leads.
+func PrintElastiCacheSummary(writer io.Writer, caches []models.ElastiCacheInfo) {
+	if len(caches) == 0 {
+		return
+	}
+
+	serverlessCount := 0
+	idleCount := 0
+	valkeyCount := 0
+	var idleStorageCost float64
+	var serverlessValkeySavings float64
+
+	for _, cache := range caches {
+		if cache.DeploymentType == pricing.ElastiCacheDeploymentServerless {
+			serverlessCount++
+		}
+		if cache.IsIdle {
+			idleCount++
+			idleStorageCost += cache.EstimatedMonthlyCost
+		}
+		if cache.ValkeyEligible {
+			valkeyCount++
+			serverlessValkeySavings += cache.EstimatedMonthlyCost * cache.ValkeySavingsPercent / 100
+		}
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## ElastiCache Summary")
+	fmt.Fprintf(w, "Serverless caches scanned:\t%d\n", serverlessCount)
+	fmt.Fprintf(w, "Idle serverless caches:\t%d\n", idleCount)
+	fmt.Fprintf(w, "Idle serverless storage cost:\t$%.2f/month\n", idleStorageCost)
+	fmt.Fprintf(w, "Valkey migration candidates:\t%d\n", valkeyCount)
+	fmt.Fprintf(w, "Serverless Valkey storage savings:\t$%.2f/month\n", serverlessValkeySavings)
+
+	w.Flush()
+}
@@ -3,53 +3,89 @@ package formatter
 import (
 	"fmt"
 	"io"
-	"text/tabwriter"
 	"time"
 
 	"github.com/younsl/idled/internal/models"
 )
 
-const (
-	elbHeader = "NAME\tTYPE\tREGION\tSTATE\tCREATED\tARN\tTG(H/U)\tTRAFFIC (14d)\tIDLE REASON"
-	elbFormat = "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
-)
-
 // PrintELBTable prints the idle ELB results in a table format using tabwriter
-func PrintELBTable(w io.Writer, elbs []models.ELBResource) {
+func PrintELBTable(w io.Writer, elbs []models.ELBResource, _ time.Time, _ time.Duration) {
 	if len(elbs) == 0 {
 		fmt.Fprintln(w, "No idle Elastic Load Balancers found.")
 		return
 	}
 
-	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) // minwidth, tabwidth, padding, padchar, flags
-	fmt.Fprintln(tw, elbHeader)
+	elbsTable.Print(w, elbs)
+}
 
-	for _, elb := range elbs {
-		createdStr := elb.CreatedTime.Format(time.RFC3339)
+// elbsTable defines the columns of the idle ELB table. The total row counts the load
+// balancers under the ARN column and sums the cost of the idle ones.
+var elbsTable = newTable(Table[models.ELBResource]{
+	Service: "elb",
+	Columns: []Column[models.ELBResource]{
+		{Key: "name", Header: "NAME", Value: func(e models.ELBResource) string { return e.Name }},
+		{Key: "type", Header: "TYPE", Value: func(e models.ELBResource) string { return e.Type }},
+		{Key: "region", Header: "REGION", Value: func(e models.ELBResource) string { return e.Region }},
+		{Key: "state", Header: "STATE", Value: func(e models.ELBResource) string { return e.State }},
+		{Key: "created", Header: "CREATED", Value: func(e models.ELBResource) string { return e.CreatedTime.Format(time.RFC3339) }},
+		{Key: "arn", Header: "ARN", Value: formatELBARN, Total: countColumn[models.ELBResource]("%d LBs")},
+		{Key: "targets", Header: "TG(H/U)", Value: func(e models.ELBResource) string {
+			return fmt.Sprintf("%d/%d", e.HealthyTargetCount, e.UnhealthyTargetCount)
+		}},
+		{Key: "traffic", Header: "TRAFFIC (14d)", Value: formatELBTraffic, Number: func(e models.ELBResource) float64 { return optionalFloat(e.LastActivitySum) }},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(e models.ELBResource) string { return formatPricedCost(e.EstimatedMonthlyCost, e.PricingSource) },
+			Number: func(e models.ELBResource) float64 { return e.EstimatedMonthlyCost },
+			Total:  func(elbs []models.ELBResource) string { return fmt.Sprintf("$%.2f", totalELBMonthlyCost(elbs)) }},
+		{Key: "pricing", Header: "PRICING", Value: func(e models.ELBResource) string { return GetPricingMarker(e.PricingSource) }},
+		protectedColumn[models.ELBResource](),
+		{Key: "idle-reason", Header: "IDLE REASON", Value: formatELBIdleReason},
+	},
+	Region: func(e models.ELBResource) string { return e.Region },
+	Tags:   func(e models.ELBResource) map[string]string { return e.Tags },
+	Links:  true,
+})
 
-		// Format LastActivitySum nicely
-		lastActivityStr := "N/A"
-		if elb.LastActivitySum != nil {
-			lastActivityStr = fmt.Sprintf("%.2f", *elb.LastActivitySum)
-		}
+// formatELBARN formats the ARN of a load balancer, "-" if unknown
+func formatELBARN(elb models.ELBResource) string {
+	if elb.ARN == "" {
+		return "-"
+	}
+	return elb.ARN
+}
 
-		// Format targets as H/U
-		targetsStr := fmt.Sprintf("%d/%d", elb.HealthyTargetCount, elb.UnhealthyTargetCount)
+// formatELBTraffic formats the traffic of the last 14 days, or why it is unknown
+func formatELBTraffic(elb models.ELBResource) string {
+	if elb.LastActivitySum != nil {
+		return fmt.Sprintf("%.2f", *elb.LastActivitySum)
+	}
+	if elb.MetricCheckFailed {
+		return "Check failed"
+	}
+	return "N/A"
+}
 
-		fmt.Fprintf(tw, elbFormat,
-			elb.Name,
-			elb.Type,
-			elb.Region,
-			elb.State,
-			createdStr,
-			elb.ARN,
-			targetsStr, // Use H/U formatted string
-			lastActivityStr,
-			elb.IdleReason,
-		)
+// formatELBIdleReason formats the idle reason. Uncertain load balancers are not idle unless
+// idleness was assumed on missing metrics.
+func formatELBIdleReason(elb models.ELBResource) string {
+	if elb.MetricCheckFailed && !elb.IsIdle {
+		return "Uncertain: " + elb.IdleReason
+	}
+	if elb.MetricCheckFailed {
+		return elb.IdleReason + " (assumed idle)"
 	}
+	return elb.IdleReason
+}
 
-	tw.Flush()
+// totalELBMonthlyCost sums the estimated monthly cost of the idle load balancers
+func totalELBMonthlyCost(elbs []models.ELBResource) float64 {
+	var total float64
+	for _, elb := range elbs {
+		if elb.IsIdle {
+			total += elb.EstimatedMonthlyCost
+		}
+	}
+	return total
 }
 
 // PrintELBSummary prints a summary of the ELB scan results
@@ -57,7 +93,20 @@ func PrintELBSummary(w io.Writer, elbs []models.ELBResource) {
 	// Optionally add a summary, similar to other resources if needed
 	// For now, keep it simple.
 	if len(elbs) > 0 {
-		fmt.Fprintf(w, "\nFound %d idle Elastic Load Balancers.\n", len(elbs))
+		idleCount, uncertainCount := 0, 0
+		for _, elb := range elbs {
+			if elb.IsIdle {
+				idleCount++
+			} else if elb.MetricCheckFailed {
+				uncertainCount++
+			}
+		}
+
+		fmt.Fprintf(w, "\nFound %d idle Elastic Load Balancers.\n", idleCount)
+		if uncertainCount > 0 {
+			fmt.Fprintf(w, "%d Elastic Load Balancers without healthy targets are uncertain because their traffic metric check failed (use --assume-idle-on-missing-metrics to report them as idle).\n", uncertainCount)
+		}
+		fmt.Fprintf(w, "Potential monthly savings: $%.2f (base LoadBalancer-hour charges, excluding LCU/NLCU).\n", totalELBMonthlyCost(elbs))
 		fmt.Fprintf(w, "Idle Reason indicates why an ELB is considered idle (e.g., no healthy targets or zero traffic over 14 days).\n")
 	}
 }
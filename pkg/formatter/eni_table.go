@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintENIsTable prints a formatted table of orphaned network interfaces
+func PrintENIsTable(writer io.Writer, enis []models.ENIInfo, _ time.Time, _ time.Duration) {
+	if len(enis) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by region, then by creator and interface ID
+	sort.SliceStable(enis, func(i, j int) bool {
+		if enis[i].Region != enis[j].Region {
+			return enis[i].Region < enis[j].Region
+		}
+		if enis[i].Creator != enis[j].Creator {
+			return enis[i].Creator < enis[j].Creator
+		}
+		return enis[i].NetworkInterfaceID < enis[j].NetworkInterfaceID
+	})
+
+	enisTable.Print(writer, enis)
+}
+
+// enisTable defines the columns of the orphaned network interfaces table
+var enisTable = newTable(Table[models.ENIInfo]{
+	Service: "eni",
+	Columns: []Column[models.ENIInfo]{
+		{Key: "eni-id", Header: "ENI ID", Value: func(e models.ENIInfo) string { return e.NetworkInterfaceID }},
+		{Key: "creator", Header: "CREATOR", Value: func(e models.ENIInfo) string { return e.Creator },
+			Total: countColumn[models.ENIInfo]("%d ENIs")},
+		{Key: "description", Header: "DESCRIPTION", Value: func(e models.ENIInfo) string {
+			if e.Description == "" {
+				return "-"
+			}
+			return truncateString(e.Description, 50)
+		}},
+		{Key: "region", Header: "REGION", Value: func(e models.ENIInfo) string { return e.Region }},
+		{Key: "vpc", Header: "VPC", Value: func(e models.ENIInfo) string { return e.VpcID }},
+		{Key: "subnet", Header: "SUBNET", Value: func(e models.ENIInfo) string { return e.SubnetID }},
+		{Key: "private-ip", Header: "PRIVATE IP", Value: func(e models.ENIInfo) string { return e.PrivateIP }, Hidden: true},
+		{Key: "security-groups", Header: "SECURITY GROUPS", Value: func(e models.ENIInfo) string { return joinFirst(e.SecurityGroups) }},
+		{Key: "requester-managed", Header: "REQUESTER MANAGED", Value: func(e models.ENIInfo) string {
+			if e.RequesterManaged {
+				return "Yes"
+			}
+			return "No"
+		}},
+		// Network interfaces have no creation timestamp
+		{Key: "age", Header: "AGE", Value: func(models.ENIInfo) string { return "Unknown" }},
+		firstSeenColumn[models.ENIInfo](),
+		{Key: "arn", Header: "ARN", Value: func(e models.ENIInfo) string { return e.ARN }, Hidden: true},
+	},
+	Region: func(e models.ENIInfo) string { return e.Region },
+	Tags:   func(e models.ENIInfo) map[string]string { return e.Tags },
+	Links:  true,
+})
+
+// PrintENIsSummary displays the orphaned network interfaces grouped by the service inferred to have created them
+func PrintENIsSummary(writer io.Writer, enis []models.ENIInfo) {
+	if len(enis) == 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	requesterManaged := make(map[string]int)
+	totalRequesterManaged := 0
+	for _, eni := range enis {
+		counts[eni.Creator]++
+		if eni.RequesterManaged {
+			requesterManaged[eni.Creator]++
+			totalRequesterManaged++
+		}
+	}
+
+	fmt.Fprintln(writer, "\n## Orphaned ENIs by Creator")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CREATOR\tCOUNT\tREQUESTER MANAGED")
+	for _, creator := range []string{
+		models.ENICreatorLambda,
+		models.ENICreatorELB,
+		models.ENICreatorRDS,
+		models.ENICreatorECS,
+		models.ENICreatorVPCEndpoint,
+		models.ENICreatorEFS,
+		models.ENICreatorOther,
+	} {
+		if counts[creator] == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\n", creator, counts[creator], requesterManaged[creator])
+	}
+	w.Flush()
+
+	if totalRequesterManaged > 0 {
+		fmt.Fprintf(writer, "\n%d requester-managed ENIs cannot be deleted manually. They are released when the owning service resource is deleted.\n", totalRequesterManaged)
+	}
+}
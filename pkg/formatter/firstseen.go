@@ -0,0 +1,36 @@
+package formatter
+
+import (
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+var (
+	firstSeenDates map[string]time.Time // First-seen idle dates of the service being printed, by canonical key
+	showFirstSeen  bool                 // Whether the FIRST SEEN column is shown by default (--diff)
+)
+
+// SetFirstSeen sets the dates idled first found the resources of the next tables idle, from
+// the local scan history. Services are printed one at a time, so only the dates of the
+// service being printed are needed.
+func SetFirstSeen(dates map[string]time.Time) {
+	firstSeenDates = dates
+}
+
+// SetShowFirstSeen shows the FIRST SEEN column by default
+func SetShowFirstSeen(enabled bool) {
+	showFirstSeen = enabled
+}
+
+// firstSeenColumn is the FIRST SEEN column of resources that do not tell when their idleness
+// began, showing the first scan in the local history that found them idle
+func firstSeenColumn[T models.Keyed]() Column[T] {
+	return Column[T]{Key: "first-seen", Header: "FIRST SEEN", Value: func(item T) string {
+		date, ok := firstSeenDates[item.SortKey()]
+		if !ok {
+			return "-"
+		}
+		return date.Local().Format("2006-01-02")
+	}, Shown: func() bool { return showFirstSeen }}
+}
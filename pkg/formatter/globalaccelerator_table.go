@@ -0,0 +1,86 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintGlobalAcceleratorTable prints the idle Global Accelerator accelerators in a table format.
+func PrintGlobalAcceleratorTable(writer io.Writer, accelerators []models.AcceleratorInfo, _ time.Time, _ time.Duration) {
+	if len(accelerators) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by creation time, oldest first
+	sort.SliceStable(accelerators, func(i, j int) bool {
+		return accelerators[i].CreatedTime.Before(accelerators[j].CreatedTime)
+	})
+
+	acceleratorsTable.Print(writer, accelerators)
+}
+
+// acceleratorsTable defines the columns of the idle Global Accelerator accelerators table
+var acceleratorsTable = newTable(Table[models.AcceleratorInfo]{
+	Service: "globalaccelerator",
+	Columns: []Column[models.AcceleratorInfo]{
+		{Key: "name", Header: "NAME", Value: func(a models.AcceleratorInfo) string { return a.Name },
+			Total: countColumn[models.AcceleratorInfo]("%d accelerators")},
+		{Key: "id", Header: "ID", Value: func(a models.AcceleratorInfo) string { return a.AcceleratorID }, Hidden: true},
+		{Key: "dns-name", Header: "DNS NAME", Value: func(a models.AcceleratorInfo) string { return a.DNSName }},
+		{Key: "ip-type", Header: "IP TYPE", Value: func(a models.AcceleratorInfo) string { return a.IPAddressType }},
+		{Key: "enabled", Header: "ENABLED", Value: func(a models.AcceleratorInfo) string { return strconv.FormatBool(a.Enabled) }},
+		{Key: "status", Header: "STATUS", Value: func(a models.AcceleratorInfo) string { return a.Status }},
+		{Key: "bytes-in", Header: "BYTES IN (30d)",
+			Value: func(a models.AcceleratorInfo) string {
+				if a.ProcessedBytesIn == nil {
+					return "-"
+				}
+				return fmt.Sprintf("%.0f", *a.ProcessedBytesIn)
+			},
+			Number: func(a models.AcceleratorInfo) float64 { return optionalFloat(a.ProcessedBytesIn) }},
+		{Key: "created", Header: "CREATED", Value: func(a models.AcceleratorInfo) string {
+			if a.CreatedTime.IsZero() {
+				return "N/A"
+			}
+			return a.CreatedTime.Format("2006-01-02")
+		}},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(a models.AcceleratorInfo) string { return fmt.Sprintf("$%.2f", a.EstimatedMonthlyCost) },
+			Number: func(a models.AcceleratorInfo) float64 { return a.EstimatedMonthlyCost },
+			Total:  sumColumn(func(a models.AcceleratorInfo) float64 { return a.EstimatedMonthlyCost })},
+		{Key: "reason", Header: "REASON", Value: func(a models.AcceleratorInfo) string { return a.IdleReason }},
+	},
+})
+
+// PrintGlobalAcceleratorSummary prints the number of idle accelerators by reason and their monthly cost
+func PrintGlobalAcceleratorSummary(writer io.Writer, accelerators []models.AcceleratorInfo) {
+	if len(accelerators) == 0 {
+		return
+	}
+
+	disabledCount := 0
+	var totalMonthlyCost float64
+	for _, accelerator := range accelerators {
+		if !accelerator.Enabled {
+			disabledCount++
+		}
+		totalMonthlyCost += accelerator.EstimatedMonthlyCost
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## Global Accelerator Summary")
+	fmt.Fprintf(w, "Idle accelerators:\t%d\n", len(accelerators))
+	fmt.Fprintf(w, "Disabled:\t%d\n", disabledCount)
+	fmt.Fprintf(w, "Enabled without traffic:\t%d\n", len(accelerators)-disabledCount)
+	fmt.Fprintf(w, "Estimated monthly cost:\t$%.2f\n", totalMonthlyCost)
+
+	w.Flush()
+}
@@ -18,7 +18,7 @@ func FormatIAMUserTable(writer io.Writer, users []models.IAMUserInfo) {
 	}
 
 	// Sort users: idle users first, then by idle days (descending)
-	sort.Slice(users, func(i, j int) bool {
+	sort.SliceStable(users, func(i, j int) bool {
 		if users[i].IsIdle != users[j].IsIdle {
 			return users[i].IsIdle // true comes first
 		}
@@ -29,7 +29,7 @@ func FormatIAMUserTable(writer io.Writer, users []models.IAMUserInfo) {
 	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', tabwriter.TabIndent)
 
 	// Print header
-	fmt.Fprintln(w, "USER NAME\tUSER ID\tAGE (DAYS)\tLAST ACTIVITY\tACCESS KEYS\tMFA\tATTACHED POLICIES\tIDLE\tREGION")
+	fmt.Fprintln(w, "USER NAME\tUSER ID\tAGE (DAYS)\tLAST ACTIVITY\tACCESS KEYS\tKEY AGE\tMFA\tATTACHED POLICIES\tIDLE\tREGION")
 
 	// Print each user
 	for _, user := range users {
@@ -43,6 +43,15 @@ func FormatIAMUserTable(writer io.Writer, users []models.IAMUserInfo) {
 			accessKeysInfo += " (active)"
 		}
 
+		// Age of the oldest active key, marked when any active key is stale
+		keyAge := "-"
+		if days, found := user.OldestActiveKeyDays(); found {
+			keyAge = fmt.Sprintf("%dd", days)
+		}
+		if user.HasStaleKeys {
+			keyAge += " (stale)"
+		}
+
 		mfaStatus := "No"
 		if user.HasMFAEnabled {
 			mfaStatus = "Yes"
@@ -53,12 +62,13 @@ func FormatIAMUserTable(writer io.Writer, users []models.IAMUserInfo) {
 			idleStatus = "Yes"
 		}
 
-		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%d\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
 			user.UserName,
 			user.UserID,
 			user.IdleDays,
 			lastActivityStr,
 			accessKeysInfo,
+			keyAge,
 			mfaStatus,
 			user.AttachedPolicyCount,
 			idleStatus,
@@ -68,16 +78,73 @@ func FormatIAMUserTable(writer io.Writer, users []models.IAMUserInfo) {
 
 	w.Flush()
 
-	// Print summary
+	formatStaleAccessKeys(writer, users)
+}
+
+// formatStaleAccessKeys writes the active access keys that are older than the maximum age or never used
+func formatStaleAccessKeys(writer io.Writer, users []models.IAMUserInfo) {
+	var stale int
+	for _, user := range users {
+		for _, key := range user.AccessKeys {
+			if key.IsStale {
+				stale++
+			}
+		}
+	}
+	if stale == 0 {
+		return
+	}
+
+	fmt.Fprintln(writer, "\nStale Access Keys:")
+
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "USER NAME\tKEY\tCREATED\tLAST USED\tAGE (DAYS)")
+	for _, user := range users {
+		for _, key := range user.AccessKeys {
+			if !key.IsStale {
+				continue
+			}
+
+			created := "Unknown"
+			if key.CreateDate != nil {
+				created = formatDate(*key.CreateDate)
+			}
+			lastUsed := "Never"
+			if key.LastUsed != nil {
+				lastUsed = formatDate(*key.LastUsed)
+			}
+
+			fmt.Fprintf(w, "%s\t...%s\t%s\t%s\t%d\n", user.UserName, key.KeyIDSuffix, created, lastUsed, key.AgeDays)
+		}
+	}
+	w.Flush()
+}
+
+// FormatIAMUserSummary writes the idle summary line for IAM users
+func FormatIAMUserSummary(writer io.Writer, users []models.IAMUserInfo) {
+	if len(users) == 0 {
+		return
+	}
+
 	idleCount := 0
+	staleKeyCount := 0
+	noMFACount := 0
 	for _, user := range users {
 		if user.IsIdle {
 			idleCount++
 		}
+		if user.HasStaleKeys {
+			staleKeyCount++
+		}
+		if user.HasConsoleAccess && !user.HasMFAEnabled {
+			noMFACount++
+		}
 	}
 
 	fmt.Fprintf(writer, "\nSummary: %d idle IAM users out of %d total users\n",
 		idleCount, len(users))
+	fmt.Fprintf(writer, "Users with stale access keys: %d\n", staleKeyCount)
+	fmt.Fprintf(writer, "Users with console access but no MFA: %d\n", noMFACount)
 }
 
 // FormatIAMRoleTable writes IAM role information in a table format
@@ -88,7 +155,7 @@ func FormatIAMRoleTable(writer io.Writer, roles []models.IAMRoleInfo) {
 	}
 
 	// Sort roles: idle roles first, then by idle days (descending)
-	sort.Slice(roles, func(i, j int) bool {
+	sort.SliceStable(roles, func(i, j int) bool {
 		if roles[i].IsIdle != roles[j].IsIdle {
 			return roles[i].IsIdle // true comes first
 		}
@@ -137,8 +204,14 @@ func FormatIAMRoleTable(writer io.Writer, roles []models.IAMRoleInfo) {
 	}
 
 	w.Flush()
+}
+
+// FormatIAMRoleSummary writes the idle summary line for IAM roles
+func FormatIAMRoleSummary(writer io.Writer, roles []models.IAMRoleInfo) {
+	if len(roles) == 0 {
+		return
+	}
 
-	// Print summary
 	idleCount := 0
 	serviceLinkedCount := 0
 	crossAccountCount := 0
@@ -168,7 +241,7 @@ func FormatIAMPolicyTable(writer io.Writer, policies []models.IAMPolicyInfo) {
 	}
 
 	// Sort policies: idle policies first, then by idle days (descending)
-	sort.Slice(policies, func(i, j int) bool {
+	sort.SliceStable(policies, func(i, j int) bool {
 		if policies[i].IsIdle != policies[j].IsIdle {
 			return policies[i].IsIdle // true comes first
 		}
@@ -206,8 +279,14 @@ func FormatIAMPolicyTable(writer io.Writer, policies []models.IAMPolicyInfo) {
 	}
 
 	w.Flush()
+}
+
+// FormatIAMPolicySummary writes the idle summary line for IAM policies
+func FormatIAMPolicySummary(writer io.Writer, policies []models.IAMPolicyInfo) {
+	if len(policies) == 0 {
+		return
+	}
 
-	// Print summary
 	idleCount := 0
 	unattachedCount := 0
 
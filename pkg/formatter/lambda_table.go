@@ -2,7 +2,7 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
 	"strconv"
 	"text/tabwriter"
@@ -12,106 +12,172 @@ import (
 )
 
 // PrintLambdaTable formats and prints Lambda functions info in a table
-func PrintLambdaTable(functions []models.LambdaFunctionInfo, scanTime time.Time, scanDuration time.Duration) {
+func PrintLambdaTable(writer io.Writer, functions []models.LambdaFunctionInfo, scanTime time.Time, scanDuration time.Duration) {
 	// Early return if no results
 	if len(functions) == 0 {
-		fmt.Println("No Lambda functions found.")
+		fmt.Fprintln(writer, "No Lambda functions found.")
 		return
 	}
 
-	// Sort functions by idle status and then by idle days (descending)
-	sort.Slice(functions, func(i, j int) bool {
-		if functions[i].IsIdle != functions[j].IsIdle {
-			return functions[i].IsIdle // Idle functions first
+	// Sort functions by idle status and then by idle days (descending). Idle functions with
+	// provisioned concurrency come first, since they are billed without being invoked.
+	// Failing functions are sorted with the idle ones.
+	sort.SliceStable(functions, func(i, j int) bool {
+		if functions[i].IdleFlag() != functions[j].IdleFlag() {
+			return functions[i].IdleFlag() // Idle and failing functions first
+		}
+		if idleWithPC(functions[i]) != idleWithPC(functions[j]) {
+			return idleWithPC(functions[i])
 		}
 		return functions[i].IdleDays > functions[j].IdleDays // Then by idle days (descending)
 	})
 
-	// Use tabwriter for aligned columns with kubectl style spacing
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
-
-	// Print header
-	fmt.Fprintln(w, "FUNCTION\tRUNTIME\tMEMORY\tREGION\tTRIGGER\tLAST INVOKE\tIDLE DAYS\tCOST/MO\tSTATUS")
-
-	// Loop through each function
-	for _, function := range functions {
-		// Format last invocation
-		lastInvocation := "Unknown"
-		if function.LastInvocation != nil {
-			lastInvocation = function.LastInvocation.Format("2006-01-02")
-		}
+	lambdaFunctionsTable.Print(writer, functions)
+}
 
-		// Format memory size
-		memorySize := fmt.Sprintf("%d MB", function.MemorySize)
+// lambdaFunctionsTable defines the columns of the Lambda functions table
+var lambdaFunctionsTable = newTable(Table[models.LambdaFunctionInfo]{
+	Service: "lambda",
+	Columns: []Column[models.LambdaFunctionInfo]{
+		{Key: "function", Header: "FUNCTION", Value: func(f models.LambdaFunctionInfo) string { return truncateString(f.FunctionName, 50) }},
+		{Key: "runtime", Header: "RUNTIME", Value: func(f models.LambdaFunctionInfo) string { return f.Runtime }},
+		{Key: "arch", Header: "ARCH", Value: func(f models.LambdaFunctionInfo) string { return f.Architecture }},
+		{Key: "memory", Header: "MEMORY",
+			Value:  func(f models.LambdaFunctionInfo) string { return fmt.Sprintf("%d MB", f.MemorySize) },
+			Number: func(f models.LambdaFunctionInfo) float64 { return float64(f.MemorySize) }},
+		{Key: "region", Header: "REGION",
+			Value: func(f models.LambdaFunctionInfo) string { return f.Region },
+			Total: countColumn[models.LambdaFunctionInfo]("%d functions")},
+		{Key: "trigger", Header: "TRIGGER", Value: formatLambdaTrigger},
+		{Key: "pc", Header: "PC",
+			Value:  formatLambdaProvisioned,
+			Number: func(f models.LambdaFunctionInfo) float64 { return float64(f.ProvisionedConcurrency) }},
+		{Key: "reserved", Header: "RESERVED",
+			Value:  formatLambdaReserved,
+			Number: lambdaReserved},
+		{Key: "errors", Header: "ERRORS",
+			Value:  func(f models.LambdaFunctionInfo) string { return strconv.FormatInt(f.ErrorsLast30Days, 10) },
+			Number: func(f models.LambdaFunctionInfo) float64 { return float64(f.ErrorsLast30Days) }},
+		{Key: "error-rate", Header: "ERROR %",
+			Value:  formatLambdaErrorRate,
+			Number: lambdaErrorRate},
+		{Key: "last-invoke", Header: "LAST INVOKE", Value: formatLambdaLastInvocation},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value:  formatLambdaIdleDays,
+			Number: func(f models.LambdaFunctionInfo) float64 { return float64(f.IdleDays) }},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(f models.LambdaFunctionInfo) string { return fmt.Sprintf("$%.2f", f.EstimatedMonthlyCost) },
+			Number: func(f models.LambdaFunctionInfo) float64 { return f.EstimatedMonthlyCost },
+			Total:  sumColumn(func(f models.LambdaFunctionInfo) float64 { return f.EstimatedMonthlyCost })},
+		{Key: "pricing", Header: "PRICING", Value: func(f models.LambdaFunctionInfo) string { return GetPricingMarker(f.PricingSource) }},
+		{Key: "status", Header: "STATUS", Value: formatLambdaStatus, Total: lambdaIdleTotal},
+	},
+	Region: func(f models.LambdaFunctionInfo) string { return f.Region },
+	Tags:   func(f models.LambdaFunctionInfo) map[string]string { return f.Tags },
+	Links:  true,
+})
+
+// formatLambdaTrigger formats whether a function has an event source or trigger
+func formatLambdaTrigger(function models.LambdaFunctionInfo) string {
+	if function.HasTrigger {
+		return "Yes"
+	}
+	return "No"
+}
 
-		// Format idle days
-		idleDays := strconv.Itoa(function.IdleDays)
-		if function.IdleDays == 0 && !function.IsIdle {
-			idleDays = "-"
-		}
+// formatLambdaProvisioned formats the provisioned concurrency of a function
+func formatLambdaProvisioned(function models.LambdaFunctionInfo) string {
+	if function.ProvisionedConcurrency > 0 {
+		return strconv.Itoa(int(function.ProvisionedConcurrency))
+	}
+	return "-"
+}
 
-		// Format cost estimation
-		cost := fmt.Sprintf("$%.2f", function.EstimatedMonthlyCost)
+// formatLambdaReserved formats the reserved concurrency of a function
+func formatLambdaReserved(function models.LambdaFunctionInfo) string {
+	if function.ReservedConcurrency != nil {
+		return strconv.Itoa(int(*function.ReservedConcurrency))
+	}
+	return "-"
+}
 
-		// Determine status
-		status := "Active"
-		if function.IsIdle {
-			status = "Idle"
-		}
+// lambdaReserved returns the reserved concurrency of a function, -1 if it has none
+func lambdaReserved(function models.LambdaFunctionInfo) float64 {
+	if function.ReservedConcurrency != nil {
+		return float64(*function.ReservedConcurrency)
+	}
+	return -1
+}
 
-		// Format trigger status
-		triggerStatus := "No"
-		if function.HasTrigger {
-			triggerStatus = "Yes"
-		}
+// formatLambdaLastInvocation formats the date of the last invocation of a function
+func formatLambdaLastInvocation(function models.LambdaFunctionInfo) string {
+	if function.LastInvocation != nil {
+		return function.LastInvocation.Format("2006-01-02")
+	}
+	return "Unknown"
+}
 
-		// Format and print the row
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			truncateString(function.FunctionName, 50),
-			function.Runtime,
-			memorySize,
-			function.Region,
-			triggerStatus,
-			lastInvocation,
-			idleDays,
-			cost,
-			status,
-		)
+// formatLambdaErrorRate formats the error rate of a function over the last 30 days, "N/A"
+// without invocations
+func formatLambdaErrorRate(function models.LambdaFunctionInfo) string {
+	rate, ok := function.ErrorRate()
+	if !ok {
+		return "N/A"
 	}
+	return fmt.Sprintf("%.1f%%", rate)
+}
 
-	// Print totals
-	printLambdaTotals(w, functions)
+// lambdaErrorRate returns the error rate of a function, -1 without invocations
+func lambdaErrorRate(function models.LambdaFunctionInfo) float64 {
+	if rate, ok := function.ErrorRate(); ok {
+		return rate
+	}
+	return -1
+}
 
-	// Flush the tabwriter buffer
-	w.Flush()
+// formatLambdaIdleDays formats the idle days of a function, "-" for active functions
+func formatLambdaIdleDays(function models.LambdaFunctionInfo) string {
+	if function.IdleDays == 0 && function.Status != models.LambdaStatusIdle {
+		return "-"
+	}
+	return strconv.Itoa(function.IdleDays)
 }
 
-// printLambdaTotals prints the summary information at the bottom of the table
-func printLambdaTotals(w *tabwriter.Writer, functions []models.LambdaFunctionInfo) {
-	totalFunctions := len(functions)
-	idleCount := 0
-	var totalMonthlyCost float64
+// formatLambdaStatus formats the status of a function
+func formatLambdaStatus(function models.LambdaFunctionInfo) string {
+	switch {
+	case idleWithPC(function):
+		return "Idle + PC ($$)"
+	case function.Status == models.LambdaStatusNew:
+		return "New (no data)"
+	}
+	return function.Status
+}
 
+// lambdaIdleTotal formats the number of idle and failing functions for the total row
+func lambdaIdleTotal(functions []models.LambdaFunctionInfo) string {
+	idleCount, failingCount := 0, 0
 	for _, function := range functions {
-		if function.IsIdle {
+		switch function.Status {
+		case models.LambdaStatusIdle:
 			idleCount++
+		case models.LambdaStatusFailing:
+			failingCount++
 		}
-		totalMonthlyCost += function.EstimatedMonthlyCost
 	}
+	if failingCount > 0 {
+		return fmt.Sprintf("%d idle, %d failing", idleCount, failingCount)
+	}
+	return fmt.Sprintf("%d idle", idleCount)
+}
 
-	// Format totals with 2 decimal places
-	formattedMonthlyCost := fmt.Sprintf("$%.2f", totalMonthlyCost)
-
-	// Print summary with kubernetes style alignment
-	fmt.Fprintf(w, "Total:\t\t\t\t\t%d\t%s\t%d idle\n",
-		totalFunctions,
-		formattedMonthlyCost,
-		idleCount,
-	)
+// idleWithPC reports whether an idle function keeps paying for provisioned concurrency
+func idleWithPC(function models.LambdaFunctionInfo) bool {
+	return function.Status == models.LambdaStatusIdle && function.ProvisionedConcurrency > 0
 }
 
 // PrintLambdaSummary displays summary information about Lambda functions
-func PrintLambdaSummary(functions []models.LambdaFunctionInfo) {
+func PrintLambdaSummary(writer io.Writer, functions []models.LambdaFunctionInfo) {
 	if len(functions) == 0 {
 		return
 	}
@@ -129,21 +195,31 @@ func PrintLambdaSummary(functions []models.LambdaFunctionInfo) {
 	}
 	sort.Strings(runtimes)
 
-	fmt.Println("\n## Lambda Functions Summary")
+	fmt.Fprintln(writer, "\n## Lambda Functions Summary")
 
 	// Set up tabwriter with kubectl style spacing
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 
 	// Print header for status summary
 	fmt.Fprintln(w, "STATUS\tCOUNT")
 
-	// Count active and idle functions
+	// Count active, idle, failing, and new functions
 	activeCount := 0
 	idleCount := 0
+	idlePCCount := 0
+	failingCount := 0
+	newCount := 0
 	for _, function := range functions {
-		if function.IsIdle {
+		switch {
+		case idleWithPC(function):
+			idlePCCount++
+		case function.Status == models.LambdaStatusIdle:
 			idleCount++
-		} else {
+		case function.Status == models.LambdaStatusFailing:
+			failingCount++
+		case function.Status == models.LambdaStatusNew:
+			newCount++
+		default:
 			activeCount++
 		}
 	}
@@ -151,13 +227,16 @@ func PrintLambdaSummary(functions []models.LambdaFunctionInfo) {
 	// Print status summary
 	fmt.Fprintf(w, "Active\t%d\n", activeCount)
 	fmt.Fprintf(w, "Idle\t%d\n", idleCount)
+	fmt.Fprintf(w, "Idle + PC ($$)\t%d\n", idlePCCount)
+	fmt.Fprintf(w, "Failing\t%d\n", failingCount)
+	fmt.Fprintf(w, "New (no data)\t%d\n", newCount)
 
 	w.Flush()
 
 	// Print runtime distribution
-	fmt.Println("\n## Lambda Runtime Distribution")
+	fmt.Fprintln(writer, "\n## Lambda Runtime Distribution")
 
-	w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w = tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 	fmt.Fprintln(w, "RUNTIME\tCOUNT")
 
 	for _, runtime := range runtimes {
@@ -165,6 +244,11 @@ func PrintLambdaSummary(functions []models.LambdaFunctionInfo) {
 	}
 
 	w.Flush()
+
+	printRegionBreakdown(writer, "Lambda Functions by Region", functions,
+		func(f models.LambdaFunctionInfo) string { return f.Region },
+		func(f models.LambdaFunctionInfo) float64 { return f.EstimatedMonthlyCost },
+		nil)
 }
 
 // truncateString truncates a string to the given max length and adds "..." if necessary
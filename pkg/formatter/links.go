@@ -0,0 +1,127 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// consoleLinkTemplates maps a service to the console page of one of its resources, with
+// {region} and {id} replaced by the region and the resource ID, name, or ARN. Services with
+// several resource types are keyed by service and type.
+var consoleLinkTemplates = map[string]string{
+	"ec2":                  "/ec2/home?region={region}#InstanceDetails:instanceId={id}",
+	"ebs":                  "/ec2/home?region={region}#VolumeDetails:volumeId={id}",
+	"eip":                  "/ec2/home?region={region}#ElasticIpDetails:AllocationId={id}",
+	"eni":                  "/ec2/home?region={region}#NetworkInterface:networkInterfaceId={id}",
+	"asg":                  "/ec2/home?region={region}#AutoScalingGroupDetails:id={id}",
+	"elb":                  "/ec2/home?region={region}#LoadBalancer:loadBalancerArn={id}",
+	"s3":                   "/s3/buckets/{id}?region={region}",
+	"lambda":               "/lambda/home?region={region}#/functions/{id}",
+	"sfn":                  "/states/home?region={region}#/statemachines/view/{id}",
+	"cloudwatch/alarm":     "/cloudwatch/home?region={region}#alarmsV2:alarm/{id}",
+	"cloudwatch/dashboard": "/cloudwatch/home?region={region}#dashboards/dashboard/{id}",
+	"secretsmanager":       "/secretsmanager/secret?name={id}&region={region}",
+	"transfer":             "/transfer/home?region={region}#/servers/{id}",
+	"beanstalk":            "/elasticbeanstalk/home?region={region}#/environment/dashboard?environmentId={id}",
+	"workspaces":           "/workspaces/v2/workspaces/{id}?region={region}",
+	"sagemaker/endpoint":   "/sagemaker/home?region={region}#/endpoints/{id}",
+	"sagemaker/notebook":   "/sagemaker/home?region={region}#/notebook-instances/{id}",
+	"opensearch":           "/aos/home?region={region}#opensearch/domains/{id}",
+}
+
+// showLinks appends a CONSOLE URL column to the tables of services with a link template (--show-links)
+var showLinks bool
+
+// SetShowLinks enables the CONSOLE URL column
+func SetShowLinks(enabled bool) {
+	showLinks = enabled
+}
+
+// ConsoleURL returns the console page of a resource, empty if the service has no link template
+func ConsoleURL(service, region, id string) string {
+	template, ok := consoleLinkTemplates[service]
+	if !ok || id == "" {
+		return ""
+	}
+	path := strings.NewReplacer("{region}", region, "{id}", id).Replace(template)
+	return consoleHost(region) + path
+}
+
+// consoleHost returns the console of the partition of a region
+func consoleHost(region string) string {
+	switch utils.PartitionForRegion(region) {
+	case utils.PartitionAWSChina:
+		return "https://console.amazonaws.cn"
+	case utils.PartitionGovCloud:
+		return "https://console.amazonaws-us-gov.com"
+	default:
+		return "https://console.aws.amazon.com"
+	}
+}
+
+// ResourceConsoleURL returns the console page of a scanned resource, empty if its service has
+// no link template
+func ResourceConsoleURL(resource any) string {
+	switch r := resource.(type) {
+	case models.InstanceInfo:
+		return ConsoleURL("ec2", r.Region, r.InstanceID)
+	case models.UnderutilizedInstanceInfo:
+		return ConsoleURL("ec2", r.Region, r.InstanceID)
+	case models.VolumeInfo:
+		return ConsoleURL("ebs", r.Region, r.VolumeID)
+	case models.EIPInfo:
+		return ConsoleURL("eip", r.Region, r.AllocationID)
+	case models.ENIInfo:
+		return ConsoleURL("eni", r.Region, r.NetworkInterfaceID)
+	case models.AutoScalingGroupInfo:
+		return ConsoleURL("asg", r.Region, r.Name)
+	case models.ELBResource:
+		// Classic Load Balancers have no ARN to link to
+		return ConsoleURL("elb", r.Region, r.ARN)
+	case models.BucketInfo:
+		return ConsoleURL("s3", r.Region, r.BucketName)
+	case models.LambdaFunctionInfo:
+		return ConsoleURL("lambda", r.Region, r.FunctionName)
+	case models.StateMachineInfo:
+		return ConsoleURL("sfn", r.Region, r.ARN)
+	case models.CloudWatchAlarmInfo:
+		return ConsoleURL("cloudwatch/alarm", r.Region, r.Name)
+	case models.CloudWatchDashboardInfo:
+		return ConsoleURL("cloudwatch/dashboard", r.Region, r.Name)
+	case models.SecretInfo:
+		return ConsoleURL("secretsmanager", r.Region, r.Name)
+	case models.TransferServerInfo:
+		return ConsoleURL("transfer", r.Region, r.ServerID)
+	case models.BeanstalkEnvironmentInfo:
+		return ConsoleURL("beanstalk", r.Region, r.EnvironmentID)
+	case models.WorkSpaceInfo:
+		return ConsoleURL("workspaces", r.Region, r.WorkspaceID)
+	case models.SageMakerResourceInfo:
+		return ConsoleURL("sagemaker/"+strings.ToLower(r.ResourceType), r.Region, r.Name)
+	case models.OpenSearchDomainInfo:
+		return ConsoleURL("opensearch", r.Region, r.DomainName)
+	}
+	return ""
+}
+
+// linkHeader returns the extra header cell for the console URL column
+func linkHeader() string {
+	if !showLinks {
+		return ""
+	}
+	return "\tCONSOLE URL"
+}
+
+// linkCell returns the extra row cell with the console URL of a resource
+func linkCell(resource any) string {
+	if !showLinks {
+		return ""
+	}
+	url := ResourceConsoleURL(resource)
+	if url == "" {
+		url = "-"
+	}
+	return "\t" + url
+}
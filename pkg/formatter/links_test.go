@@ -0,0 +1,81 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+func TestConsoleURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		region  string
+		id      string
+		want    string
+	}{
+		{name: "aws", service: "ec2", region: "us-east-1", id: "i-0123456789abcdef0",
+			want: "https://console.aws.amazon.com/ec2/home?region=us-east-1#InstanceDetails:instanceId=i-0123456789abcdef0"},
+		{name: "aws-cn", service: "ebs", region: "cn-north-1", id: "vol-0123456789abcdef0",
+			want: "https://console.amazonaws.cn/ec2/home?region=cn-north-1#VolumeDetails:volumeId=vol-0123456789abcdef0"},
+		{name: "aws-cn ningxia", service: "s3", region: "cn-northwest-1", id: "logs",
+			want: "https://console.amazonaws.cn/s3/buckets/logs?region=cn-northwest-1"},
+		{name: "aws-us-gov", service: "lambda", region: "us-gov-west-1", id: "handler",
+			want: "https://console.amazonaws-us-gov.com/lambda/home?region=us-gov-west-1#/functions/handler"},
+		{name: "aws-us-gov east", service: "secretsmanager", region: "us-gov-east-1", id: "db-password",
+			want: "https://console.amazonaws-us-gov.com/secretsmanager/secret?name=db-password&region=us-gov-east-1"},
+		{name: "service without a template", service: "iam", region: "us-east-1", id: "user"},
+		{name: "empty id", service: "ec2", region: "us-east-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConsoleURL(tt.service, tt.region, tt.id); got != tt.want {
+				t.Errorf("ConsoleURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceConsoleURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource any
+		want     string
+	}{
+		{name: "sagemaker notebook in aws-cn", resource: models.SageMakerResourceInfo{ResourceType: "Notebook", Name: "research", Region: "cn-north-1"},
+			want: "https://console.amazonaws.cn/sagemaker/home?region=cn-north-1#/notebook-instances/research"},
+		{name: "cloudwatch alarm in aws-us-gov", resource: models.CloudWatchAlarmInfo{Name: "high-cpu", Region: "us-gov-west-1"},
+			want: "https://console.amazonaws-us-gov.com/cloudwatch/home?region=us-gov-west-1#alarmsV2:alarm/high-cpu"},
+		{name: "classic load balancer without an ARN", resource: models.ELBResource{Region: "us-east-1"}},
+		{name: "service without a template", resource: models.LogGroupInfo{Name: "/app/old", Region: "us-east-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResourceConsoleURL(tt.resource); got != tt.want {
+				t.Errorf("ResourceConsoleURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkCell(t *testing.T) {
+	t.Cleanup(func() { SetShowLinks(false) })
+	instance := models.InstanceInfo{InstanceID: "i-0123456789abcdef0", Region: "us-gov-west-1"}
+
+	if got := linkHeader() + linkCell(instance); got != "" {
+		t.Errorf("link column without --show-links = %q, want empty", got)
+	}
+
+	SetShowLinks(true)
+	if got, want := linkHeader(), "\tCONSOLE URL"; got != want {
+		t.Errorf("linkHeader() = %q, want %q", got, want)
+	}
+	if got, want := linkCell(instance), "\thttps://console.amazonaws-us-gov.com/ec2/home?region=us-gov-west-1#InstanceDetails:instanceId=i-0123456789abcdef0"; got != want {
+		t.Errorf("linkCell() = %q, want %q", got, want)
+	}
+	if got, want := linkCell(models.LogGroupInfo{Name: "/app/old"}), "\t-"; got != want {
+		t.Errorf("linkCell() without a link = %q, want %q", got, want)
+	}
+}
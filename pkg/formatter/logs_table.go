@@ -2,25 +2,29 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/younsl/idled/internal/models"
 )
 
 // PrintLogGroupsTable prints the found idle log groups using tabwriter for consistency.
-func PrintLogGroupsTable(logGroups []models.LogGroupInfo) {
+func PrintLogGroupsTable(writer io.Writer, logGroups []models.LogGroupInfo) {
 	if len(logGroups) == 0 {
 		// No need to print anything if the list is empty,
 		// the calling function already prints a summary message.
 		return
 	}
 
-	// Sort by effective timestamp (actual last event or creation time)
+	// Sort by stored size (largest first), then by effective timestamp (actual last event or creation time)
 	sort.SliceStable(logGroups, func(i, j int) bool {
+		if logGroups[i].StoredBytes != logGroups[j].StoredBytes {
+			return logGroups[i].StoredBytes > logGroups[j].StoredBytes
+		}
 		if logGroups[i].LastEventMillis == 0 {
 			return false
 		} // Put groups with unknown time at the end
@@ -30,41 +34,119 @@ func PrintLogGroupsTable(logGroups []models.LogGroupInfo) {
 		return logGroups[i].LastEventMillis < logGroups[j].LastEventMillis
 	})
 
-	fmt.Println("\nIdle CloudWatch Log Groups:")
+	fmt.Fprintln(writer, "\nIdle CloudWatch Log Groups:")
+
+	logGroupsTable.Print(writer, logGroups)
+}
 
-	// Use tabwriter, same settings as EC2/EBS formatter
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+// logGroupsTable defines the columns of the idle CloudWatch log groups table
+var logGroupsTable = newTable(Table[models.LogGroupInfo]{
+	Service: "logs",
+	Columns: []Column[models.LogGroupInfo]{
+		{Key: "name", Header: "LOG GROUP NAME", Value: func(lg models.LogGroupInfo) string { return lg.Name }},
+		{Key: "retention", Header: "RETENTION", Value: func(lg models.LogGroupInfo) string { return lg.RetentionDays }},
+		{Key: "size", Header: "SIZE",
+			Value:  func(lg models.LogGroupInfo) string { return humanize.Bytes(uint64(lg.StoredBytes)) },
+			Number: func(lg models.LogGroupInfo) float64 { return float64(lg.StoredBytes) },
+			Total: func(logGroups []models.LogGroupInfo) string {
+				var totalBytes int64
+				for _, lg := range logGroups {
+					totalBytes += lg.StoredBytes
+				}
+				return humanize.Bytes(uint64(totalBytes))
+			}},
+		{Key: "cost", Header: "COST/MO",
+			Value: func(lg models.LogGroupInfo) string {
+				return formatPricedCost(lg.EstimatedMonthlyCost, lg.PricingSource)
+			},
+			Number: func(lg models.LogGroupInfo) float64 { return lg.EstimatedMonthlyCost },
+			Total:  sumColumn(func(lg models.LogGroupInfo) float64 { return lg.EstimatedMonthlyCost })},
+		{Key: "created", Header: "CREATED", Value: func(lg models.LogGroupInfo) string {
+			if lg.CreationTime.IsZero() {
+				return "N/A"
+			}
+			return lg.CreationTime.Format("2006-01-02")
+		}},
+		{Key: "last-event", Header: "LAST EVENT",
+			Value:  formatLogGroupLastEvent,
+			Number: func(lg models.LogGroupInfo) float64 { return float64(lg.LastEventMillis) }},
+		{Key: "protected", Header: "PROTECTED", Value: logGroupProtection},
+		{Key: "recommendation", Header: "RECOMMENDATION", Value: func(lg models.LogGroupInfo) string {
+			if lg.Recommendation == "" {
+				return "-"
+			}
+			return lg.Recommendation
+		}},
+	},
+})
+
+// formatLogGroupLastEvent formats the last event time as a short date. The time already
+// holds a fallback like "N/A (Created...)" when there is no event, kept as is.
+func formatLogGroupLastEvent(lg models.LogGroupInfo) string {
+	if strings.HasPrefix(lg.LastEventTime, "N/A") {
+		return lg.LastEventTime
+	}
+	parsedTime, err := time.Parse("2006-01-02 15:04:05", lg.LastEventTime)
+	if err != nil {
+		return lg.LastEventTime // Keep original if parsing fails
+	}
+	return parsedTime.Format("2006-01-02")
+}
 
-	// Print header with tabs
-	fmt.Fprintln(w, "LOG GROUP NAME\tRETENTION\tSIZE\tCREATED\tLAST EVENT")
+// logGroupProtection returns why an idle log group should not be deleted, or "No"
+func logGroupProtection(lg models.LogGroupInfo) string {
+	switch {
+	case lg.HasSubscriptionFilter && lg.HasMetricFilter:
+		return "Yes (subscription, metric filter)"
+	case lg.HasSubscriptionFilter:
+		return "Yes (subscription filter)"
+	case lg.HasMetricFilter:
+		return "Yes (metric filter)"
+	case lg.FilterCheckFailed:
+		return "Unknown (check failed)"
+	default:
+		return "No"
+	}
+}
 
-	// Print rows with tabs
+// PrintLogGroupsSummary prints how many idle log groups are unreferenced and safe to
+// review for deletion, and how many are still referenced by subscription or metric filters
+func PrintLogGroupsSummary(writer io.Writer, logGroups []models.LogGroupInfo) {
+	if len(logGroups) == 0 {
+		return
+	}
+
+	var unreferenced, referenced []models.LogGroupInfo
 	for _, lg := range logGroups {
-		// Format CreationTime (short date)
-		creationTimeStr := lg.CreationTime.Format("2006-01-02")
-		if lg.CreationTime.IsZero() {
-			creationTimeStr = "N/A"
+		if lg.IsReferenced() {
+			referenced = append(referenced, lg)
+		} else {
+			unreferenced = append(unreferenced, lg)
 		}
+	}
 
-		// Format LastEventTime (short date or fallback string)
-		lastEventTimeStr := lg.LastEventTime // This already contains fallback like "N/A (Created...)"
-		if !strings.HasPrefix(lastEventTimeStr, "N/A") {
-			// Try to parse the full timestamp and format as short date
-			parsedTime, err := time.Parse("2006-01-02 15:04:05", lastEventTimeStr)
-			if err == nil {
-				lastEventTimeStr = parsedTime.Format("2006-01-02")
-			} // Keep original if parsing fails
-		}
+	fmt.Fprintln(writer, "\n## CloudWatch Log Groups Summary")
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			lg.Name,
-			lg.RetentionDays,
-			lg.StoredBytes,
-			creationTimeStr,
-			lastEventTimeStr,
-		)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tCOUNT\tSIZE\tCOST/MO")
+	for _, row := range []struct {
+		label  string
+		groups []models.LogGroupInfo
+	}{
+		{"Idle and unreferenced", unreferenced},
+		{"Idle but referenced", referenced},
+	} {
+		var bytes int64
+		var cost float64
+		for _, lg := range row.groups {
+			bytes += lg.StoredBytes
+			cost += lg.EstimatedMonthlyCost
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t$%.2f\n", row.label, len(row.groups), humanize.Bytes(uint64(bytes)), cost)
 	}
-
-	// Flush the writer to ensure output is displayed
 	w.Flush()
+
+	if len(referenced) > 0 {
+		fmt.Fprintln(writer, "\nReferenced log groups have subscription or metric filters (or could not be checked). Confirm the destinations and alarms are unused before deleting them.")
+	}
 }
@@ -2,8 +2,9 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
+	"strconv"
 	"text/tabwriter"
 	"time"
 
@@ -13,9 +14,9 @@ import (
 )
 
 // PrintMskTable prints the MSK cluster information in a table format using tabwriter.
-func PrintMskTable(clusters []models.MskClusterInfo, scanStartTime time.Time, scanDuration time.Duration) {
+func PrintMskTable(writer io.Writer, clusters []models.MskClusterInfo, scanStartTime time.Time, scanDuration time.Duration) {
 	if len(clusters) == 0 {
-		// fmt.Println("\nNo idle/underutilized MSK clusters found.") // Spinner handles this
+		// fmt.Fprintln(writer, "\nNo idle/underutilized MSK clusters found.") // Spinner handles this
 		return
 	}
 
@@ -28,64 +29,88 @@ func PrintMskTable(clusters []models.MskClusterInfo, scanStartTime time.Time, sc
 		return clusters[i].CreationTime.Before(clusters[j].CreationTime)
 	})
 
-	// Setup tabwriter for kubernetes style tables
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	mskClustersTable.Print(writer, clusters)
 
-	// Print header - move IDLE and REASON to the end
-	fmt.Fprintln(w, "CLUSTER NAME\tARN\tREGION\tSTATE\tINSTANCE TYPE\tCREATION TIME\tMAX CONN (30d)\tAVG CPU (30d %)\tIDLE\tREASON")
-
-	// Print table rows
-	for _, cluster := range clusters {
-		connCountStr := "N/A"
-		if cluster.ConnectionCount != nil {
-			connCountStr = fmt.Sprintf("%.0f", *cluster.ConnectionCount)
-		}
-		cpuUtilStr := "N/A"
-		if cluster.AvgCPUUtilization != nil {
-			cpuUtilStr = fmt.Sprintf("%.2f", *cluster.AvgCPUUtilization)
-		}
-
-		// Truncate ARN if necessary (using the function from this package)
-		truncatedARN := truncateString(cluster.ARN, 50)
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
-			cluster.ClusterName,
-			truncatedARN,
-			cluster.Region,
-			cluster.State,
-			cluster.InstanceType,
-			cluster.CreationTime.Format("2006-01-02"),
-			connCountStr,
-			cpuUtilStr,
-			cluster.IsIdle,
-			cluster.Reason,
-		)
-	}
-
-	// Update footer to show total scanned and idle/underutilized count
 	idleCount := 0
 	for _, cluster := range clusters {
 		if cluster.IsIdle {
 			idleCount++
 		}
 	}
-	footerStr := fmt.Sprintf("Showing %d scanned MSK clusters (%d Idle/Underutilized)", len(clusters), idleCount)
-	// Need to add caption/footer logic to tabwriter if not standard footer
-	// Tabwriter doesn't have a direct SetCaption like go-pretty.
-	// We'll just print the summary line after flushing the table.
-	w.Flush()
-	fmt.Printf("\n%s\n", footerStr) // Print summary line after table
+	fmt.Fprintf(writer, "\nShowing %d scanned MSK clusters (%d Idle/Underutilized)\n", len(clusters), idleCount)
 }
 
+// mskClustersTable defines the columns of the MSK clusters table
+var mskClustersTable = newTable(Table[models.MskClusterInfo]{
+	Service: "msk",
+	Columns: []Column[models.MskClusterInfo]{
+		{Key: "name", Header: "CLUSTER NAME", Value: func(c models.MskClusterInfo) string { return c.ClusterName }},
+		{Key: "arn", Header: "ARN", Value: func(c models.MskClusterInfo) string { return truncateString(c.ARN, 50) }},
+		{Key: "region", Header: "REGION", Value: func(c models.MskClusterInfo) string { return c.Region }},
+		{Key: "state", Header: "STATE", Value: func(c models.MskClusterInfo) string { return c.State }},
+		{Key: "type", Header: "TYPE", Value: func(c models.MskClusterInfo) string { return c.ClusterType }},
+		{Key: "instance-type", Header: "INSTANCE TYPE", Value: func(c models.MskClusterInfo) string { return c.InstanceType }},
+		// Serverless clusters are billed by usage, so only provisioned clusters have brokers
+		{Key: "brokers", Header: "BROKERS",
+			Value: func(c models.MskClusterInfo) string {
+				if c.ClusterType != "PROVISIONED" {
+					return "-"
+				}
+				return fmt.Sprintf("%d", c.BrokerCount)
+			},
+			Number: func(c models.MskClusterInfo) float64 { return float64(c.BrokerCount) }},
+		{Key: "created", Header: "CREATION TIME", Value: func(c models.MskClusterInfo) string { return c.CreationTime.Format("2006-01-02") }},
+		{Key: "max-connections", Header: "MAX CONN (30d)",
+			Value: func(c models.MskClusterInfo) string {
+				if c.ConnectionCount == nil {
+					return "N/A"
+				}
+				return fmt.Sprintf("%.0f", *c.ConnectionCount)
+			},
+			Number: func(c models.MskClusterInfo) float64 { return optionalFloat(c.ConnectionCount) }},
+		// Total zero connection days, with the latest consecutive ones that decide idleness
+		{Key: "zero-conn-days", Header: "ZERO CONN DAYS",
+			Value: func(c models.MskClusterInfo) string {
+				if c.ConnectionCount == nil {
+					return "N/A"
+				}
+				return fmt.Sprintf("%d (%d latest)", c.ZeroConnectionDays, c.ConsecutiveZeroConnectionDays)
+			},
+			Number: func(c models.MskClusterInfo) float64 { return float64(c.ZeroConnectionDays) }},
+		{Key: "cpu", Header: "AVG CPU (30d %)",
+			Value: func(c models.MskClusterInfo) string {
+				if c.AvgCPUUtilization == nil {
+					return "N/A"
+				}
+				return fmt.Sprintf("%.2f", *c.AvgCPUUtilization)
+			},
+			Number: func(c models.MskClusterInfo) float64 { return optionalFloat(c.AvgCPUUtilization) }},
+		{Key: "cost", Header: "COST/MO",
+			Value: func(c models.MskClusterInfo) string {
+				if c.PricingUnavailable() {
+					return "N/A"
+				}
+				return fmt.Sprintf("$%.2f", c.EstimatedMonthlyCost)
+			},
+			Number: func(c models.MskClusterInfo) float64 { return c.EstimatedMonthlyCost }},
+		{Key: "pricing", Header: "PRICING", Value: func(c models.MskClusterInfo) string { return GetPricingMarker(c.PricingSource) }},
+		{Key: "idle", Header: "IDLE", Value: func(c models.MskClusterInfo) string { return strconv.FormatBool(c.IsIdle) }},
+		{Key: "reason", Header: "REASON", Value: func(c models.MskClusterInfo) string { return c.Reason }},
+	},
+	NoTotal: true,
+})
+
 // PrintMskSummary prints the summary for MSK clusters using tabwriter.
-func PrintMskSummary(clusters []models.MskClusterInfo) {
+func PrintMskSummary(writer io.Writer, clusters []models.MskClusterInfo) {
 	// Count clusters by Reason (only those marked as idle/underutilized)
 	reasonCounts := make(map[string]int)
 	totalIdleCount := 0
+	var totalIdleCost float64
 	for _, cluster := range clusters {
 		if cluster.IsIdle { // Consider only clusters identified as idle/underutilized
 			reasonCounts[cluster.Reason]++
 			totalIdleCount++
+			totalIdleCost += cluster.EstimatedMonthlyCost
 		}
 	}
 
@@ -94,7 +119,7 @@ func PrintMskSummary(clusters []models.MskClusterInfo) {
 	}
 
 	// Setup tabwriter for summary
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 
 	fmt.Fprintln(w, "\n## MSK SUMMARY:") // Consistent summary title
 	fmt.Fprintln(w, "REASON\tCOUNT")
@@ -114,6 +139,7 @@ func PrintMskSummary(clusters []models.MskClusterInfo) {
 
 	// Print total count
 	fmt.Fprintf(w, "Total Idle/Underutilized:\t%d\n", totalIdleCount)
+	fmt.Fprintf(w, "Idle Broker Cost/Mo:\t$%.2f\n", totalIdleCost)
 
 	w.Flush()
 }
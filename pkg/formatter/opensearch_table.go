@@ -0,0 +1,127 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintOpenSearchTable prints the idle and underutilized OpenSearch domains in a table format.
+func PrintOpenSearchTable(writer io.Writer, domains []models.OpenSearchDomainInfo, _ time.Time, _ time.Duration) {
+	if len(domains) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by estimated cost (descending)
+	sort.SliceStable(domains, func(i, j int) bool {
+		return domains[i].EstimatedMonthlyCost > domains[j].EstimatedMonthlyCost
+	})
+
+	openSearchDomainsTable.Print(writer, domains)
+}
+
+// openSearchDomainsTable defines the columns of the idle OpenSearch domains table
+var openSearchDomainsTable = newTable(Table[models.OpenSearchDomainInfo]{
+	Service: "opensearch",
+	Columns: []Column[models.OpenSearchDomainInfo]{
+		{Key: "name", Header: "DOMAIN NAME", Value: func(d models.OpenSearchDomainInfo) string { return d.DomainName },
+			Total: countColumn[models.OpenSearchDomainInfo]("%d domains")},
+		{Key: "region", Header: "REGION", Value: func(d models.OpenSearchDomainInfo) string { return d.Region }},
+		{Key: "engine", Header: "ENGINE", Value: func(d models.OpenSearchDomainInfo) string { return d.EngineVersion }},
+		{Key: "instances", Header: "DATA NODES", Value: func(d models.OpenSearchDomainInfo) string {
+			return formatOpenSearchNodes(d.InstanceType, d.InstanceCount)
+		}, Number: func(d models.OpenSearchDomainInfo) float64 { return float64(d.InstanceCount) }},
+		{Key: "masters", Header: "MASTER NODES", Value: func(d models.OpenSearchDomainInfo) string {
+			return formatOpenSearchNodes(d.DedicatedMasterType, d.DedicatedMasterCount)
+		}},
+		{Key: "warm", Header: "WARM NODES", Value: func(d models.OpenSearchDomainInfo) string {
+			return formatOpenSearchNodes(d.WarmType, d.WarmCount)
+		}, Hidden: true},
+		{Key: "storage", Header: "EBS/NODE", Value: formatOpenSearchStorage,
+			Number: func(d models.OpenSearchDomainInfo) float64 { return float64(d.VolumeSizeGB) }},
+		{Key: "search-rate", Header: "SEARCH RATE (30d)",
+			Value:  func(d models.OpenSearchDomainInfo) string { return formatOptionalMetric(d.SearchRate) },
+			Number: func(d models.OpenSearchDomainInfo) float64 { return optionalFloat(d.SearchRate) }},
+		{Key: "indexing-rate", Header: "INDEXING RATE (30d)",
+			Value:  func(d models.OpenSearchDomainInfo) string { return formatOptionalMetric(d.IndexingRate) },
+			Number: func(d models.OpenSearchDomainInfo) float64 { return optionalFloat(d.IndexingRate) }},
+		{Key: "cpu", Header: "AVG CPU (30d %)",
+			Value:  func(d models.OpenSearchDomainInfo) string { return formatOptionalMetric(d.AvgCPUUtilization) },
+			Number: func(d models.OpenSearchDomainInfo) float64 { return optionalFloat(d.AvgCPUUtilization) }},
+		{Key: "cost", Header: "COST/MO",
+			Value: func(d models.OpenSearchDomainInfo) string {
+				return formatPricedCost(d.EstimatedMonthlyCost, d.PricingSource)
+			},
+			Number: func(d models.OpenSearchDomainInfo) float64 { return d.EstimatedMonthlyCost },
+			Total:  sumColumn(func(d models.OpenSearchDomainInfo) float64 { return d.EstimatedMonthlyCost })},
+		{Key: "pricing", Header: "PRICING", Value: func(d models.OpenSearchDomainInfo) string { return GetPricingMarker(d.PricingSource) }},
+		{Key: "reason", Header: "REASON", Value: func(d models.OpenSearchDomainInfo) string { return d.Reason }},
+	},
+	Links: true,
+})
+
+// formatOpenSearchNodes formats a node group as "<type> x<count>", "-" if the domain has none
+func formatOpenSearchNodes(instanceType string, count int) string {
+	if instanceType == "" || count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s x%d", instanceType, count)
+}
+
+// formatOpenSearchStorage formats the EBS volume of each data node, "-" without EBS storage
+func formatOpenSearchStorage(domain models.OpenSearchDomainInfo) string {
+	if domain.VolumeType == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%d GB %s", domain.VolumeSizeGB, domain.VolumeType)
+}
+
+// formatOptionalMetric formats a metric average with two decimals, "N/A" without data
+func formatOptionalMetric(value *float64) string {
+	if value == nil {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.2f", *value)
+}
+
+// PrintOpenSearchSummary prints the idle OpenSearch domain counts by reason and the monthly cost
+func PrintOpenSearchSummary(writer io.Writer, domains []models.OpenSearchDomainInfo) {
+	if len(domains) == 0 {
+		return
+	}
+
+	reasonCounts := make(map[string]int)
+	var totalMonthlyCost float64
+	for _, domain := range domains {
+		reasonCounts[domain.Reason]++
+		totalMonthlyCost += domain.EstimatedMonthlyCost
+	}
+
+	reasons := make([]string, 0, len(reasonCounts))
+	for reason := range reasonCounts {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## OpenSearch Summary")
+	fmt.Fprintln(w, "REASON\tCOUNT")
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "%s\t%d\n", reason, reasonCounts[reason])
+	}
+	fmt.Fprintf(w, "Total Idle/Underutilized:\t%d\n", len(domains))
+	fmt.Fprintf(w, "Estimated monthly cost:\t$%.2f\n", totalMonthlyCost)
+
+	w.Flush()
+
+	printRegionBreakdown(writer, "OpenSearch Domains by Region", domains,
+		func(d models.OpenSearchDomainInfo) string { return d.Region },
+		func(d models.OpenSearchDomainInfo) float64 { return d.EstimatedMonthlyCost },
+		nil)
+}
@@ -0,0 +1,93 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintResolverTable prints the idle Route 53 Resolver endpoints in a table format.
+func PrintResolverTable(writer io.Writer, endpoints []models.ResolverEndpointInfo, _ time.Time, _ time.Duration) {
+	if len(endpoints) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by estimated cost (descending), the endpoints with the most IP addresses first
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].EstimatedMonthlyCost > endpoints[j].EstimatedMonthlyCost
+	})
+
+	resolverEndpointsTable.Print(writer, endpoints)
+}
+
+// resolverEndpointsTable defines the columns of the idle Route 53 Resolver endpoints table
+var resolverEndpointsTable = newTable(Table[models.ResolverEndpointInfo]{
+	Service: "resolver",
+	Columns: []Column[models.ResolverEndpointInfo]{
+		{Key: "endpoint-id", Header: "ENDPOINT ID", Value: func(e models.ResolverEndpointInfo) string { return e.EndpointID }},
+		{Key: "name", Header: "NAME", Value: func(e models.ResolverEndpointInfo) string {
+			if e.Name == "" {
+				return "-"
+			}
+			return e.Name
+		}},
+		{Key: "region", Header: "REGION", Value: func(e models.ResolverEndpointInfo) string { return e.Region }},
+		{Key: "direction", Header: "DIRECTION", Value: func(e models.ResolverEndpointInfo) string { return e.Direction }},
+		{Key: "vpc", Header: "VPC ID", Value: func(e models.ResolverEndpointInfo) string { return e.VPCID }},
+		{Key: "ips", Header: "IP ADDRESSES",
+			Value:  func(e models.ResolverEndpointInfo) string { return strconv.Itoa(e.IPAddressCount) },
+			Number: func(e models.ResolverEndpointInfo) float64 { return float64(e.IPAddressCount) }},
+		{Key: "status", Header: "STATUS", Value: func(e models.ResolverEndpointInfo) string { return e.Status }},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(e models.ResolverEndpointInfo) string { return fmt.Sprintf("$%.2f", e.EstimatedMonthlyCost) },
+			Number: func(e models.ResolverEndpointInfo) float64 { return e.EstimatedMonthlyCost },
+			Total:  sumColumn(func(e models.ResolverEndpointInfo) float64 { return e.EstimatedMonthlyCost })},
+		{Key: "reason", Header: "REASON", Value: func(e models.ResolverEndpointInfo) string { return e.IdleReason }},
+	},
+})
+
+// PrintResolverSummary prints the idle Route 53 Resolver endpoint counts by direction and the monthly cost
+func PrintResolverSummary(writer io.Writer, endpoints []models.ResolverEndpointInfo) {
+	if len(endpoints) == 0 {
+		return
+	}
+
+	directionCounts := make(map[string]int)
+	var ipAddressCount int
+	var totalMonthlyCost float64
+	for _, endpoint := range endpoints {
+		directionCounts[endpoint.Direction]++
+		ipAddressCount += endpoint.IPAddressCount
+		totalMonthlyCost += endpoint.EstimatedMonthlyCost
+	}
+
+	directions := make([]string, 0, len(directionCounts))
+	for direction := range directionCounts {
+		directions = append(directions, direction)
+	}
+	sort.Strings(directions)
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## Route 53 Resolver Summary")
+	fmt.Fprintln(w, "DIRECTION\tCOUNT")
+	for _, direction := range directions {
+		fmt.Fprintf(w, "%s\t%d\n", direction, directionCounts[direction])
+	}
+	fmt.Fprintf(w, "Total idle endpoints:\t%d\n", len(endpoints))
+	fmt.Fprintf(w, "Billed IP addresses:\t%d\n", ipAddressCount)
+	fmt.Fprintf(w, "Estimated monthly cost:\t$%.2f\n", totalMonthlyCost)
+
+	w.Flush()
+
+	printRegionBreakdown(writer, "Resolver Endpoints by Region", endpoints,
+		func(e models.ResolverEndpointInfo) string { return e.Region },
+		func(e models.ResolverEndpointInfo) float64 { return e.EstimatedMonthlyCost },
+		nil)
+}
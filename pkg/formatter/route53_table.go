@@ -0,0 +1,95 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// FormatRoute53PrivateZonesTable writes the private hosted zones without records in a table format
+func FormatRoute53PrivateZonesTable(writer io.Writer, zones []models.HostedZoneInfo) {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ZONE ID\tNAME\tRECORDS\tCOST/MO\tIDLE REASON")
+
+	var totalMonthlyCost float64
+	for _, zone := range zones {
+		fmt.Fprintf(w, "%s\t%s\t%d\t$%.2f\t%s\n",
+			zone.ID,
+			zone.Name,
+			zone.RecordCount,
+			zone.EstimatedMonthlyCost,
+			zone.IdleReason,
+		)
+		totalMonthlyCost += zone.EstimatedMonthlyCost
+	}
+	fmt.Fprintf(w, "Total:\t\t\t$%.2f\t\n", totalMonthlyCost)
+
+	w.Flush()
+}
+
+// FormatRoute53PublicZonesTable writes the public hosted zones whose domain is not delegated
+// to them in a table format
+func FormatRoute53PublicZonesTable(writer io.Writer, zones []models.HostedZoneInfo) {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "ZONE ID\tNAME\tRECORDS\tZONE NAME SERVER\tRESOLVED NAME SERVER\tCOST/MO\tIDLE REASON")
+
+	var totalMonthlyCost float64
+	for _, zone := range zones {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t$%.2f\t%s\n",
+			zone.ID,
+			zone.Name,
+			zone.RecordCount,
+			joinFirst(zone.NameServers),
+			joinFirst(zone.ResolvedNameServers),
+			zone.EstimatedMonthlyCost,
+			zone.IdleReason,
+		)
+		totalMonthlyCost += zone.EstimatedMonthlyCost
+	}
+	fmt.Fprintf(w, "Total:\t\t\t\t\t$%.2f\t\n", totalMonthlyCost)
+
+	w.Flush()
+}
+
+// FormatRoute53HealthChecksTable writes the health checks not referenced by any record set in a table format
+func FormatRoute53HealthChecksTable(writer io.Writer, healthChecks []models.HealthCheckInfo) {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "HEALTH CHECK ID\tTYPE\tTARGET\tDISABLED\tCOST/MO\tIDLE REASON")
+
+	var totalMonthlyCost float64
+	for _, healthCheck := range healthChecks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t$%.2f\t%s\n",
+			healthCheck.ID,
+			healthCheck.Type,
+			healthCheck.Target,
+			healthCheck.Disabled,
+			healthCheck.EstimatedMonthlyCost,
+			healthCheck.IdleReason,
+		)
+		totalMonthlyCost += healthCheck.EstimatedMonthlyCost
+	}
+	fmt.Fprintf(w, "Total:\t\t\t\t$%.2f\t\n", totalMonthlyCost)
+
+	w.Flush()
+}
+
+// FormatRoute53Summary writes the idle hosted zone and health check counts with their monthly cost
+func FormatRoute53Summary(writer io.Writer, zones []models.HostedZoneInfo, healthChecks []models.HealthCheckInfo) {
+	if len(zones) == 0 && len(healthChecks) == 0 {
+		return
+	}
+
+	var totalMonthlyCost float64
+	for _, zone := range zones {
+		totalMonthlyCost += zone.EstimatedMonthlyCost
+	}
+	for _, healthCheck := range healthChecks {
+		totalMonthlyCost += healthCheck.EstimatedMonthlyCost
+	}
+
+	fmt.Fprintf(writer, "\nSummary: %d idle hosted zones and %d unreferenced health checks, $%.2f/month\n",
+		len(zones), len(healthChecks), totalMonthlyCost)
+	fmt.Fprintln(writer, "Hosted zones are charged $0.50/month for the first 25 zones and $0.10/month after, so the savings may be lower.")
+}
@@ -2,7 +2,7 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
 	"strings"
 	"text/tabwriter"
@@ -13,83 +13,96 @@ import (
 )
 
 // PrintBucketsTable prints S3 bucket information as a table
-func PrintBucketsTable(buckets []models.BucketInfo, scanStartTime time.Time, scanDuration time.Duration) {
+func PrintBucketsTable(writer io.Writer, buckets []models.BucketInfo, scanStartTime time.Time, scanDuration time.Duration) {
 	if len(buckets) == 0 {
-		fmt.Println("No idle S3 buckets found.")
+		fmt.Fprintln(writer, "No idle S3 buckets found.")
 		return
 	}
 
 	// Sort buckets by idle days (descending)
-	sort.Slice(buckets, func(i, j int) bool {
+	sort.SliceStable(buckets, func(i, j int) bool {
 		return buckets[i].IdleDays > buckets[j].IdleDays
 	})
 
-	// Setup tabwriter for kubernetes style tables
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
-
-	// Print header
-	fmt.Fprintln(w, "NAME\tREGION\tOBJECTS\tSIZE\tIDLE DAYS\tLAST MODIFIED\tEMPTY\tUSAGE")
-
-	// Print table rows
-	for _, bucket := range buckets {
-		var lastModified string
-		if bucket.LastModified != nil {
-			lastModified = bucket.LastModified.Format("2006-01-02")
-		} else {
-			lastModified = "N/A"
-		}
-
-		usage := formatBucketUsage(bucket)
-
-		// Format size
-		sizeFormatted := utils.FormatBytes(bucket.TotalSize)
-
-		// Format empty value as a string instead of boolean
-		emptyStr := "Yes"
-		if !bucket.IsEmpty {
-			emptyStr = "No"
-		}
-
-		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%s\t%s\t%s\n",
-			bucket.BucketName,
-			bucket.Region,
-			bucket.ObjectCount,
-			sizeFormatted,
-			bucket.IdleDays,
-			lastModified,
-			emptyStr,
-			usage)
-	}
-
-	// Print totals
-	printBucketsTotals(w, buckets)
-
-	w.Flush()
+	bucketsTable.Print(writer, buckets)
 }
 
-// printBucketsTotals prints the summary information at the bottom of the table
-func printBucketsTotals(w *tabwriter.Writer, buckets []models.BucketInfo) {
-	var totalObjects int64
-	var totalSize int64
-
-	for _, bucket := range buckets {
-		totalObjects += int64(bucket.ObjectCount)
-		totalSize += bucket.TotalSize
+// formatBucketLastModified formats the last modification of a bucket, "Unknown" if the
+// activity of a non-empty bucket could not be determined
+func formatBucketLastModified(bucket models.BucketInfo) string {
+	switch {
+	case bucket.LastModified != nil:
+		return bucket.LastModified.Format("2006-01-02")
+	case bucket.ActivityUnknown && !bucket.IsEmpty:
+		return "Unknown"
+	default:
+		return "N/A"
 	}
-
-	sizeFormatted := utils.FormatBytes(totalSize)
-
-	// Print summary with kubernetes style alignment
-	fmt.Fprintf(w, "Total:\t\t%d\t%s\t\t\t\n",
-		totalObjects,
-		sizeFormatted,
-	)
 }
 
+// bucketsTable defines the columns of the idle S3 buckets table
+var bucketsTable = newTable(Table[models.BucketInfo]{
+	Service: "s3",
+	Columns: []Column[models.BucketInfo]{
+		{Key: "name", Header: "NAME", Value: func(b models.BucketInfo) string { return b.BucketName }},
+		{Key: "region", Header: "REGION", Value: func(b models.BucketInfo) string { return b.Region }},
+		{Key: "objects", Header: "OBJECTS",
+			Value:  func(b models.BucketInfo) string { return fmt.Sprintf("%d", b.ObjectCount) },
+			Number: func(b models.BucketInfo) float64 { return float64(b.ObjectCount) },
+			Total: func(buckets []models.BucketInfo) string {
+				var totalObjects int64
+				for _, bucket := range buckets {
+					totalObjects += int64(bucket.ObjectCount)
+				}
+				return fmt.Sprintf("%d", totalObjects)
+			}},
+		{Key: "size", Header: "SIZE",
+			Value:  func(b models.BucketInfo) string { return utils.FormatBytes(b.TotalSize) },
+			Number: func(b models.BucketInfo) float64 { return float64(b.TotalSize) },
+			Total: func(buckets []models.BucketInfo) string {
+				var totalSize int64
+				for _, bucket := range buckets {
+					totalSize += bucket.TotalSize
+				}
+				return utils.FormatBytes(totalSize)
+			}},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(b models.BucketInfo) string { return fmt.Sprintf("$%.2f", b.EstimatedMonthlyCost) },
+			Number: func(b models.BucketInfo) float64 { return b.EstimatedMonthlyCost },
+			Total:  sumColumn(func(b models.BucketInfo) float64 { return b.EstimatedMonthlyCost })},
+		{Key: "pricing", Header: "PRICING", Value: func(b models.BucketInfo) string { return GetPricingMarker(b.PricingSource) }},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value:  func(b models.BucketInfo) string { return fmt.Sprintf("%d", b.IdleDays) },
+			Number: func(b models.BucketInfo) float64 { return float64(b.IdleDays) }},
+		{Key: "last-modified", Header: "LAST MODIFIED", Value: formatBucketLastModified},
+		{Key: "empty", Header: "EMPTY", Value: func(b models.BucketInfo) string {
+			if b.IsEmpty {
+				return "Yes"
+			}
+			return "No"
+		}},
+		protectedColumn[models.BucketInfo](),
+		{Key: "usage", Header: "USAGE", Value: formatBucketUsage},
+		firstSeenColumn[models.BucketInfo](),
+	},
+	Region: func(b models.BucketInfo) string { return b.Region },
+	Links:  true,
+})
+
 // formatBucketUsage returns a human-readable description of bucket usage
 func formatBucketUsage(bucket models.BucketInfo) string {
 	var usage []string
 
+	// Empty buckets are idle based on their age alone
+	if bucket.IsEmpty && bucket.IsIdle {
+		usage = append(usage, fmt.Sprintf("Empty since creation (%d days)", bucket.IdleDays))
+	}
+
+	// Flag buckets whose activity could not be determined from the metrics
+	if bucket.ActivityUnknown && !bucket.IsEmpty {
+		usage = append(usage, "Unknown activity")
+	}
+
 	// Add "Recently Modified" tag if modified within last 30 days
 	if bucket.LastModified != nil && utils.CalculateElapsedDays(*bucket.LastModified) <= 30 {
 		usage = append(usage, "Recently Modified")
@@ -110,6 +123,19 @@ func formatBucketUsage(bucket models.BucketInfo) string {
 		usage = append(usage, "Events")
 	}
 
+	// Versioned buckets keep noncurrent versions and delete markers, which lifecycle rules expire
+	if bucket.VersioningEnabled {
+		usage = append(usage, "Versioned")
+	}
+	if bucket.HasLifecyclePolicy {
+		usage = append(usage, "Lifecycle")
+	}
+
+	// Incomplete multipart uploads are billed as storage until aborted
+	if bucket.IncompleteMPUCount > 0 {
+		usage = append(usage, fmt.Sprintf("%d incomplete uploads", bucket.IncompleteMPUCount))
+	}
+
 	// Check for API activity pattern
 	if bucket.GetRequestsLast30Days > 1000 && bucket.PutRequestsLast30Days < 10 {
 		usage = append(usage, "Static Content")
@@ -126,12 +152,13 @@ func formatBucketUsage(bucket models.BucketInfo) string {
 }
 
 // PrintBucketsSummary prints a summary of idle S3 buckets
-func PrintBucketsSummary(buckets []models.BucketInfo) {
+func PrintBucketsSummary(writer io.Writer, buckets []models.BucketInfo) {
 	if len(buckets) == 0 {
 		return
 	}
 
 	var emptyBuckets, idleBuckets, bucketsByAge []models.BucketInfo
+	unknownActivity := 0
 
 	// Categorize buckets
 	for _, bucket := range buckets {
@@ -142,36 +169,44 @@ func PrintBucketsSummary(buckets []models.BucketInfo) {
 			idleBuckets = append(idleBuckets, bucket)
 			bucketsByAge = append(bucketsByAge, bucket)
 		}
+		if bucket.ActivityUnknown && !bucket.IsEmpty {
+			unknownActivity++
+		}
 	}
 
 	// Sort buckets by idle time
-	sort.Slice(bucketsByAge, func(i, j int) bool {
+	sort.SliceStable(bucketsByAge, func(i, j int) bool {
 		return bucketsByAge[i].IdleDays > bucketsByAge[j].IdleDays
 	})
 
 	// Calculate total size of IDLE buckets only
 	var totalIdleSize int64
+	var totalIdleCost float64
 	for _, bucket := range idleBuckets {
 		totalIdleSize += bucket.TotalSize
+		totalIdleCost += bucket.EstimatedMonthlyCost
 	}
 
 	// Setup tabwriter for kubernetes style tables
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 
 	fmt.Fprintln(w, "\n## S3 BUCKETS SUMMARY:")
 	fmt.Fprintf(w, "Total buckets scanned:\t%d\n", len(buckets))
 	fmt.Fprintf(w, "Empty buckets:\t%d\n", len(emptyBuckets))
 	fmt.Fprintf(w, "Idle buckets:\t%d\n", len(idleBuckets))
+	fmt.Fprintf(w, "Buckets with unknown activity (not flagged idle):\t%d\n", unknownActivity)
 	fmt.Fprintf(w, "Total idle storage:\t%s\n", utils.FormatBytes(totalIdleSize))
+	fmt.Fprintf(w, "Total potential monthly savings (idle buckets):\t$%.2f\n", totalIdleCost)
 
 	w.Flush()
 
 	// Print additional recommendations for buckets by age category
-	printBucketsAgeBreakdown(bucketsByAge)
+	printBucketsAgeBreakdown(writer, bucketsByAge)
+	printBucketsRecommendations(writer, buckets)
 }
 
 // printBucketsAgeBreakdown prints breakdown of buckets by age categories
-func printBucketsAgeBreakdown(buckets []models.BucketInfo) {
+func printBucketsAgeBreakdown(writer io.Writer, buckets []models.BucketInfo) {
 	if len(buckets) == 0 {
 		return
 	}
@@ -196,7 +231,7 @@ func printBucketsAgeBreakdown(buckets []models.BucketInfo) {
 	}
 
 	// Setup tabwriter for kubernetes style tables
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 
 	fmt.Fprintln(w, "\n## AGE BREAKDOWN:")
 	fmt.Fprintf(w, "≤ 30 days:\t%d buckets\n", b30Days)
@@ -205,12 +240,67 @@ func printBucketsAgeBreakdown(buckets []models.BucketInfo) {
 	fmt.Fprintf(w, "181-365 days:\t%d buckets\n", b365Days)
 	fmt.Fprintf(w, "> 365 days:\t%d buckets\n", bOlder)
 
-	// Suggest lifecycle policy if older buckets exist
-	if b180Days+b365Days+bOlder > 0 {
-		fmt.Fprintln(w, "\n## RECOMMENDATIONS:")
-		fmt.Fprintln(w, "- Consider implementing lifecycle policies for long-term idle buckets")
-		fmt.Fprintln(w, "- Review empty buckets for potential cleanup")
+	w.Flush()
+}
+
+// printBucketsRecommendations prints recommendations based on the lifecycle policy, versioning,
+// and incomplete multipart uploads of the scanned buckets
+func printBucketsRecommendations(writer io.Writer, buckets []models.BucketInfo) {
+	var recommendations []string
+
+	var versionedWithoutLifecycle, longIdleWithoutLifecycle, emptyIdle int
+	for _, bucket := range buckets {
+		if !bucket.IsIdle {
+			continue
+		}
+		if bucket.VersioningEnabled && !bucket.HasLifecyclePolicy {
+			versionedWithoutLifecycle++
+		}
+		if bucket.IdleDays > 180 && !bucket.HasLifecyclePolicy {
+			longIdleWithoutLifecycle++
+		}
+		if bucket.IsEmpty {
+			emptyIdle++
+		}
 	}
 
-	w.Flush()
+	if versionedWithoutLifecycle > 0 {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"%d idle buckets have versioning but no lifecycle policy: noncurrent versions and delete markers may dominate their cost",
+			versionedWithoutLifecycle))
+	}
+	if longIdleWithoutLifecycle > 0 {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"%d buckets idle for over 180 days have no lifecycle policy: transition or expire their objects",
+			longIdleWithoutLifecycle))
+	}
+	if emptyIdle > 0 {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"%d idle buckets are empty: review them for deletion", emptyIdle))
+	}
+
+	// Incomplete multipart uploads cost money whether the bucket is idle or not
+	var withUploads []models.BucketInfo
+	for _, bucket := range buckets {
+		if bucket.IncompleteMPUCount > 0 {
+			withUploads = append(withUploads, bucket)
+		}
+	}
+	sort.SliceStable(withUploads, func(i, j int) bool {
+		return withUploads[i].IncompleteMPUCount > withUploads[j].IncompleteMPUCount
+	})
+	for _, bucket := range withUploads {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"Bucket %s has %d incomplete multipart uploads: abort them or add an AbortIncompleteMultipartUpload lifecycle rule",
+			bucket.BucketName, bucket.IncompleteMPUCount))
+	}
+
+	if len(recommendations) == 0 {
+		return
+	}
+
+	fmt.Fprintln(writer, "\n## RECOMMENDATIONS:")
+	for _, recommendation := range recommendations {
+		fmt.Fprintf(writer, "- %s\n", recommendation)
+	}
 }
@@ -0,0 +1,134 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintSageMakerTable prints the idle SageMaker endpoints and notebook instances in a table format.
+func PrintSageMakerTable(writer io.Writer, resources []models.SageMakerResourceInfo, _ time.Time, _ time.Duration) {
+	if len(resources) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by estimated cost (descending), the most expensive resources first
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].EstimatedMonthlyCost > resources[j].EstimatedMonthlyCost
+	})
+
+	sageMakerTable.Print(writer, resources)
+}
+
+// sageMakerTable defines the columns of the idle SageMaker endpoints and notebook instances table
+var sageMakerTable = newTable(Table[models.SageMakerResourceInfo]{
+	Service: "sagemaker",
+	Columns: []Column[models.SageMakerResourceInfo]{
+		{Key: "name", Header: "NAME", Value: func(r models.SageMakerResourceInfo) string { return r.Name }},
+		{Key: "type", Header: "TYPE", Value: func(r models.SageMakerResourceInfo) string { return string(r.ResourceType) }},
+		{Key: "region", Header: "REGION", Value: func(r models.SageMakerResourceInfo) string { return r.Region }},
+		{Key: "status", Header: "STATUS", Value: func(r models.SageMakerResourceInfo) string { return r.Status }},
+		{Key: "instance-type", Header: "INSTANCE TYPE", Value: func(r models.SageMakerResourceInfo) string {
+			if r.Serverless {
+				return "Serverless"
+			}
+			return r.InstanceType
+		}},
+		{Key: "instances", Header: "INSTANCES",
+			Value: func(r models.SageMakerResourceInfo) string {
+				if r.Serverless {
+					return "-"
+				}
+				return strconv.Itoa(int(r.InstanceCount))
+			},
+			Number: func(r models.SageMakerResourceInfo) float64 { return float64(r.InstanceCount) }},
+		{Key: "volume", Header: "VOLUME",
+			Value: func(r models.SageMakerResourceInfo) string {
+				if r.VolumeSizeGB == 0 {
+					return "-"
+				}
+				return fmt.Sprintf("%d GB", r.VolumeSizeGB)
+			},
+			Number: func(r models.SageMakerResourceInfo) float64 { return float64(r.VolumeSizeGB) }},
+		{Key: "created", Header: "CREATED", Value: func(r models.SageMakerResourceInfo) string { return r.CreationTime.Format("2006-01-02") }},
+		{Key: "last-modified", Header: "LAST MODIFIED", Value: func(r models.SageMakerResourceInfo) string { return r.LastModifiedTime.Format("2006-01-02") }},
+		{Key: "invocations", Header: "INVOCATIONS (30d)",
+			Value: func(r models.SageMakerResourceInfo) string {
+				if r.Invocations == nil {
+					return "-"
+				}
+				return fmt.Sprintf("%.0f", *r.Invocations)
+			},
+			Number: func(r models.SageMakerResourceInfo) float64 { return optionalFloat(r.Invocations) }},
+		{Key: "cpu", Header: "AVG CPU (30d %)",
+			Value:  formatSageMakerCPU,
+			Number: func(r models.SageMakerResourceInfo) float64 { return optionalFloat(r.AvgCPUUtilization) }},
+		{Key: "cost", Header: "COST/MO",
+			Value: func(r models.SageMakerResourceInfo) string {
+				if r.PricingUnavailable() {
+					return "N/A"
+				}
+				return fmt.Sprintf("$%.2f", r.EstimatedMonthlyCost)
+			},
+			Number: func(r models.SageMakerResourceInfo) float64 { return r.EstimatedMonthlyCost },
+			Total:  sumColumn(func(r models.SageMakerResourceInfo) float64 { return r.EstimatedMonthlyCost })},
+		{Key: "pricing", Header: "PRICING", Value: func(r models.SageMakerResourceInfo) string { return GetPricingMarker(r.PricingSource) }},
+		{Key: "reason", Header: "REASON", Value: func(r models.SageMakerResourceInfo) string { return r.IdleReason }},
+	},
+	Links: true,
+})
+
+// formatSageMakerCPU formats the average CPU utilization, "N/A" for running notebook
+// instances without the metric
+func formatSageMakerCPU(resource models.SageMakerResourceInfo) string {
+	if resource.AvgCPUUtilization != nil {
+		return fmt.Sprintf("%.2f", *resource.AvgCPUUtilization)
+	}
+	if resource.ResourceType == models.SageMakerResourceNotebook && resource.Status != "Stopped" {
+		return "N/A"
+	}
+	return "-"
+}
+
+// PrintSageMakerSummary prints the idle SageMaker resource counts and costs by resource type
+func PrintSageMakerSummary(writer io.Writer, resources []models.SageMakerResourceInfo) {
+	if len(resources) == 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	costs := make(map[string]float64)
+	stoppedNotebooks := 0
+	for _, resource := range resources {
+		counts[resource.ResourceType]++
+		costs[resource.ResourceType] += resource.EstimatedMonthlyCost
+		if resource.ResourceType == models.SageMakerResourceNotebook && resource.Status == "Stopped" {
+			stoppedNotebooks++
+		}
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## SageMaker Summary")
+	fmt.Fprintln(w, "TYPE\tCOUNT\tCOST/MO")
+	for _, resourceType := range []string{models.SageMakerResourceEndpoint, models.SageMakerResourceNotebook} {
+		fmt.Fprintf(w, "%s\t%d\t$%.2f\n", resourceType, counts[resourceType], costs[resourceType])
+	}
+
+	w.Flush()
+
+	if stoppedNotebooks > 0 {
+		fmt.Fprintf(writer, "\n%d stopped notebook instances are only billed for their ML storage volume.\n", stoppedNotebooks)
+	}
+
+	printRegionBreakdown(writer, "SageMaker Resources by Region", resources,
+		func(r models.SageMakerResourceInfo) string { return r.Region },
+		func(r models.SageMakerResourceInfo) float64 { return r.EstimatedMonthlyCost },
+		nil)
+}
@@ -0,0 +1,112 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintSavingsReport prints the estimated monthly cost of the idle resources of every
+// scanned service with cost estimates, per service and per region, with a grand total. Resources without
+// pricing data are counted separately so the total is not silently understated.
+func PrintSavingsReport(writer io.Writer, summaries []models.CostSummary) {
+	// Only services with cost estimates and idle resources are reported
+	var sorted []models.CostSummary
+	for _, summary := range summaries {
+		if summary.Estimated && summary.Count > 0 {
+			sorted = append(sorted, summary)
+		}
+	}
+	if len(sorted) == 0 {
+		return
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Service != sorted[j].Service {
+			return sorted[i].Service < sorted[j].Service
+		}
+		return sorted[i].Region < sorted[j].Region
+	})
+
+	// Aggregate per service, keeping the sorted service order
+	var services []string
+	byService := make(map[string]models.CostSummary)
+	for _, summary := range sorted {
+		total, exists := byService[summary.Service]
+		if !exists {
+			services = append(services, summary.Service)
+			total.Service = summary.Service
+		}
+		total.Count += summary.Count
+		total.MonthlyCost += summary.MonthlyCost
+		total.Unpriced += summary.Unpriced
+		byService[summary.Service] = total
+	}
+
+	fmt.Fprintln(writer, "\n## Estimated Monthly Savings")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tIDLE\tCOST/MO\tNO PRICING")
+	var grandTotal models.CostSummary
+	for _, service := range services {
+		total := byService[service]
+		fmt.Fprintf(w, "%s\t%d\t$%.2f\t%s\n", service, total.Count, total.MonthlyCost, formatUnpriced(total.Unpriced))
+		grandTotal.Count += total.Count
+		grandTotal.MonthlyCost += total.MonthlyCost
+		grandTotal.Unpriced += total.Unpriced
+	}
+	fmt.Fprintf(w, "Total:\t%d\t$%.2f\t%s\n", grandTotal.Count, grandTotal.MonthlyCost, formatUnpriced(grandTotal.Unpriced))
+	w.Flush()
+
+	fmt.Fprintln(writer, "\n## Estimated Monthly Savings by Region")
+
+	w = tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tREGION\tIDLE\tCOST/MO\tNO PRICING")
+	for _, summary := range sorted {
+		fmt.Fprintf(w, "%s\t%s\t%d\t$%.2f\t%s\n",
+			summary.Service,
+			summary.Region,
+			summary.Count,
+			summary.MonthlyCost,
+			formatUnpriced(summary.Unpriced),
+		)
+	}
+	w.Flush()
+
+	if grandTotal.Unpriced > 0 {
+		fmt.Fprintf(writer, "\n⚠️  %d idle resource(s) have no pricing data (N/A) and are not included in the total, so actual savings are higher.\n",
+			grandTotal.Unpriced)
+	}
+}
+
+// formatUnpriced formats the number of resources without pricing data, "-" if none
+func formatUnpriced(count int) string {
+	if count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d (N/A)", count)
+}
+
+// PrintDiff prints the idle resources of a service that appeared or were resolved since the
+// previous scan, with their counts
+func PrintDiff(writer io.Writer, service string, since time.Time, added, resolved []string) {
+	fmt.Fprintf(writer, "\n## %s Changes Since %s\n", service, since.Local().Format("2006-01-02 15:04"))
+	fmt.Fprintf(writer, "NEW: %d, RESOLVED: %d\n", len(added), len(resolved))
+	if len(added) == 0 && len(resolved) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tRESOURCE")
+	for _, key := range added {
+		fmt.Fprintf(w, "NEW\t%s\n", key)
+	}
+	for _, key := range resolved {
+		fmt.Fprintf(w, "RESOLVED\t%s\n", key)
+	}
+	w.Flush()
+}
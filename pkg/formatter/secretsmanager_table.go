@@ -2,16 +2,24 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"sort"
-	"text/tabwriter"
+	"strconv"
 	"time"
 
 	"github.com/younsl/idled/internal/models"
 )
 
+// secretsIdleThreshold is the number of days without access used by the Secrets Manager scan
+var secretsIdleThreshold = 90
+
+// SetSecretsIdleThreshold sets the idle threshold shown in the Secrets Manager table footer
+func SetSecretsIdleThreshold(days int) {
+	secretsIdleThreshold = days
+}
+
 // PrintSecretsTable prints the idle Secrets Manager secret information in a table format.
-func PrintSecretsTable(secrets []models.SecretInfo, scanStartTime time.Time, scanDuration time.Duration) {
+func PrintSecretsTable(writer io.Writer, secrets []models.SecretInfo, scanStartTime time.Time, scanDuration time.Duration) {
 	if len(secrets) == 0 {
 		// Spinner will indicate if nothing was found
 		return
@@ -22,38 +30,79 @@ func PrintSecretsTable(secrets []models.SecretInfo, scanStartTime time.Time, sca
 		return secrets[i].IdleDays > secrets[j].IdleDays
 	})
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	secretsTable.Print(writer, secrets)
+
+	fmt.Fprintf(writer, "\nShowing %d idle Secrets Manager secrets (unused for at least %d days)\n", len(secrets), secretsIdleThreshold)
+}
 
-	// Print header
-	fmt.Fprintln(w, "NAME\tARN\tREGION\tLAST ACCESSED\tIDLE DAYS")
+// secretsTable defines the columns of the idle Secrets Manager secrets table
+var secretsTable = newTable(Table[models.SecretInfo]{
+	Service: "secretsmanager",
+	Columns: []Column[models.SecretInfo]{
+		{Key: "name", Header: "NAME", Value: func(s models.SecretInfo) string { return s.Name }},
+		{Key: "arn", Header: "ARN", Value: func(s models.SecretInfo) string { return truncateString(s.ARN, 60) }},
+		{Key: "region", Header: "REGION", Value: func(s models.SecretInfo) string { return s.Region }},
+		{Key: "last-accessed", Header: "LAST ACCESSED", Value: formatSecretLastAccessed},
+		{Key: "access", Header: "ACCESS", Value: formatSecretAccess},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value:  func(s models.SecretInfo) string { return strconv.Itoa(s.IdleDays) },
+			Number: func(s models.SecretInfo) float64 { return float64(s.IdleDays) }},
+		{Key: "rotation", Header: "ROTATION", Value: secretRotation},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(s models.SecretInfo) string { return fmt.Sprintf("$%.2f", s.EstimatedMonthlyCost) },
+			Number: func(s models.SecretInfo) float64 { return s.EstimatedMonthlyCost },
+			Total:  sumColumn(func(s models.SecretInfo) float64 { return s.EstimatedMonthlyCost })},
+	},
+	Links: true,
+})
 
-	// Print table rows
-	for _, secret := range secrets {
-		// Truncate ARN if necessary
-		truncatedARN := truncateString(secret.ARN, 60) // Assuming truncateString exists in common.go or similar
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n",
-			secret.Name,
-			truncatedARN,
-			secret.Region,
-			secret.LastAccessedDate.Format("2006-01-02"),
-			secret.IdleDays,
-		)
+// formatSecretLastAccessed formats the last access date of a secret, "-" if never accessed
+func formatSecretLastAccessed(secret models.SecretInfo) string {
+	if secret.NeverAccessed {
+		return "-"
 	}
+	return secret.LastAccessedDate.Format("2006-01-02")
+}
 
-	footerStr := fmt.Sprintf("Showing %d idle Secrets Manager secrets (unused for over %d days)", len(secrets), 90) // Assuming 90 days threshold
-	w.Flush()
-	fmt.Printf("\n%s\n", footerStr)
+// formatSecretAccess formats whether a secret was ever accessed
+func formatSecretAccess(secret models.SecretInfo) string {
+	if secret.NeverAccessed {
+		return "Never accessed"
+	}
+	return "Accessed"
+}
+
+// secretRotation returns the rotation status of a secret with its last rotation date
+func secretRotation(secret models.SecretInfo) string {
+	if !secret.RotationEnabled {
+		return "Disabled"
+	}
+	if secret.LastRotatedDate == nil {
+		return "Enabled (never rotated)"
+	}
+	return fmt.Sprintf("Enabled (%s)", secret.LastRotatedDate.Format("2006-01-02"))
 }
 
 // PrintSecretsSummary prints a simple summary for idle secrets.
-func PrintSecretsSummary(secrets []models.SecretInfo) {
+func PrintSecretsSummary(writer io.Writer, secrets []models.SecretInfo) {
 	if len(secrets) == 0 {
 		return
 	}
 
-	// For Secrets Manager, a simple count might be sufficient as the criteria is straightforward.
-	// If more complex summaries are needed later, this can be expanded.
-	fmt.Printf("\n## Secrets Manager Summary:")
-	fmt.Printf("\nTotal Idle Secrets Found: %d\n", len(secrets))
+	neverAccessed := 0
+	rotating := 0
+	var totalMonthlyCost float64
+	for _, secret := range secrets {
+		if secret.NeverAccessed {
+			neverAccessed++
+		}
+		if secret.RotationEnabled {
+			rotating++
+		}
+		totalMonthlyCost += secret.EstimatedMonthlyCost
+	}
+
+	fmt.Fprintf(writer, "\n## Secrets Manager Summary:")
+	fmt.Fprintf(writer, "\nTotal Idle Secrets Found: %d (%d never accessed, %d with rotation enabled)\n", len(secrets), neverAccessed, rotating)
+	fmt.Fprintf(writer, "Estimated Monthly Cost: $%.2f\n", totalMonthlyCost)
 }
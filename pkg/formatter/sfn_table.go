@@ -0,0 +1,125 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// stepFunctionsIdleThreshold is the number of days without an execution used by the Step Functions scan
+var stepFunctionsIdleThreshold = 90
+
+// SetStepFunctionsIdleThreshold sets the idle threshold shown in the Step Functions table footer
+func SetStepFunctionsIdleThreshold(days int) {
+	stepFunctionsIdleThreshold = days
+	stateMachinesTable.setHeader("executions", fmt.Sprintf("EXECUTIONS (%dd)", days))
+}
+
+// PrintStateMachinesTable formats and prints Step Functions state machines in a table
+func PrintStateMachinesTable(writer io.Writer, machines []models.StateMachineInfo, _ time.Time, _ time.Duration) {
+	if len(machines) == 0 {
+		fmt.Fprintln(writer, "No Step Functions state machines found.")
+		return
+	}
+
+	// Sort by idle status and then by idle days (descending)
+	sort.SliceStable(machines, func(i, j int) bool {
+		if machines[i].IsIdle != machines[j].IsIdle {
+			return machines[i].IsIdle // Idle state machines first
+		}
+		return machines[i].IdleDays > machines[j].IdleDays
+	})
+
+	stateMachinesTable.Print(writer, machines)
+}
+
+// stateMachinesTable defines the columns of the Step Functions state machines table
+var stateMachinesTable = newTable(Table[models.StateMachineInfo]{
+	Service: "sfn",
+	Columns: []Column[models.StateMachineInfo]{
+		{Key: "name", Header: "STATE MACHINE", Value: func(m models.StateMachineInfo) string { return truncateString(m.Name, 50) }},
+		{Key: "type", Header: "TYPE", Value: func(m models.StateMachineInfo) string { return m.Type }},
+		{Key: "region", Header: "REGION", Value: func(m models.StateMachineInfo) string { return m.Region }},
+		{Key: "created", Header: "CREATED", Value: func(m models.StateMachineInfo) string { return m.CreationDate.Format("2006-01-02") }},
+		{Key: "last-execution", Header: "LAST EXECUTION", Value: formatStateMachineLastExecution},
+		{Key: "executions", Header: fmt.Sprintf("EXECUTIONS (%dd)", stepFunctionsIdleThreshold),
+			Value:  func(m models.StateMachineInfo) string { return strconv.FormatInt(m.ExecutionsInWindow, 10) },
+			Number: func(m models.StateMachineInfo) float64 { return float64(m.ExecutionsInWindow) },
+			Total:  countColumn[models.StateMachineInfo]("%d")},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value:  formatStateMachineIdleDays,
+			Number: func(m models.StateMachineInfo) float64 { return float64(m.IdleDays) }},
+		{Key: "status", Header: "STATUS", Value: formatStateMachineStatus, Total: stateMachineIdleTotal},
+	},
+	Region: func(m models.StateMachineInfo) string { return m.Region },
+	Links:  true,
+})
+
+// formatStateMachineLastExecution formats the date of the last execution of a state machine
+func formatStateMachineLastExecution(machine models.StateMachineInfo) string {
+	if machine.LastExecution != nil {
+		return machine.LastExecution.Format("2006-01-02")
+	}
+	return "None found"
+}
+
+// formatStateMachineIdleDays formats the idle days of a state machine. Without an execution
+// within the lookback, the state machine is idle at least this long.
+func formatStateMachineIdleDays(machine models.StateMachineInfo) string {
+	if machine.LastExecution == nil {
+		return strconv.Itoa(machine.IdleDays) + "+"
+	}
+	return strconv.Itoa(machine.IdleDays)
+}
+
+// formatStateMachineStatus formats whether a state machine is idle
+func formatStateMachineStatus(machine models.StateMachineInfo) string {
+	if machine.IsIdle {
+		return "Idle"
+	}
+	return "Active"
+}
+
+// stateMachineIdleTotal formats the number of idle state machines for the total row
+func stateMachineIdleTotal(machines []models.StateMachineInfo) string {
+	idleCount := 0
+	for _, machine := range machines {
+		if machine.IsIdle {
+			idleCount++
+		}
+	}
+	return fmt.Sprintf("%d idle", idleCount)
+}
+
+// PrintStateMachinesSummary displays the active and idle state machine counts by type
+func PrintStateMachinesSummary(writer io.Writer, machines []models.StateMachineInfo) {
+	if len(machines) == 0 {
+		return
+	}
+
+	active := make(map[string]int)
+	idle := make(map[string]int)
+	for _, machine := range machines {
+		if machine.IsIdle {
+			idle[machine.Type]++
+		} else {
+			active[machine.Type]++
+		}
+	}
+
+	fmt.Fprintln(writer, "\n## Step Functions Summary")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tACTIVE\tIDLE")
+	for _, machineType := range []string{"STANDARD", "EXPRESS"} {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", machineType, active[machineType], idle[machineType])
+	}
+	w.Flush()
+
+	fmt.Fprintf(writer, "\nState machines without an execution in the last %d days are idle.\n", stepFunctionsIdleThreshold)
+}
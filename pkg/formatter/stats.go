@@ -2,34 +2,60 @@ package formatter
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"sort"
 	"text/tabwriter"
+	"time"
 
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/runner"
 	"github.com/younsl/idled/pkg/pricing"
 )
 
 // PrintPricingAPIStats prints the statistics of pricing API calls
-func PrintPricingAPIStats() {
+func PrintPricingAPIStats(writer io.Writer) {
+	if pricing.APIDisabled() {
+		fmt.Fprintln(writer, "\n## AWS Pricing API Call Statistics")
+		fmt.Fprintln(writer, "AWS Pricing API calls were disabled with --no-pricing-api. Prices come from the bundled default price table.")
+		return
+	}
+
 	stats := pricing.GetAPIStats()
 
 	if len(stats) == 0 {
 		return
 	}
 
-	fmt.Println("\n## AWS Pricing API Call Statistics")
+	fmt.Fprintln(writer, "\n## AWS Pricing API Call Statistics")
 
 	// Use tabwriter for clean tabular output
-	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
 
 	// Print header
-	fmt.Fprintln(w, "SERVICE\tREGION\tAPI CALLS\tSUCCESS\tFAILURE\tCACHE HITS\tSUCCESS RATE")
+	fmt.Fprintln(w, "SERVICE\tREGION\tAPI CALLS\tSUCCESS\tFAILURE\tCACHE HITS\tDISK CACHE HITS\tSUCCESS RATE")
 
 	// Print statistics for each service and region
-	for service, regions := range stats {
-		for region, statValues := range regions {
+	// Iterate services and regions in sorted order for stable output
+	serviceNames := make([]string, 0, len(stats))
+	for service := range stats {
+		serviceNames = append(serviceNames, service)
+	}
+	sort.Strings(serviceNames)
+
+	for _, service := range serviceNames {
+		regions := stats[service]
+		regionNames := make([]string, 0, len(regions))
+		for region := range regions {
+			regionNames = append(regionNames, region)
+		}
+		sort.Strings(regionNames)
+
+		for _, region := range regionNames {
+			statValues := regions[region]
 			success := statValues["success"]
 			failure := statValues["failure"]
 			cache := statValues["cache"]
+			disk := statValues["disk"]
 			total := success + failure
 
 			// Calculate success rate percentage
@@ -38,13 +64,14 @@ func PrintPricingAPIStats() {
 				successRate = float64(success) / float64(total) * 100.0
 			}
 
-			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%.1f%%\n",
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%.1f%%\n",
 				service,
 				region,
 				total,
 				success,
 				failure,
 				cache,
+				disk,
 				successRate,
 			)
 		}
@@ -52,3 +79,103 @@ func PrintPricingAPIStats() {
 
 	w.Flush()
 }
+
+// PrintAPIErrorStats prints the number of AWS API calls that failed after all retries
+// and the number of attempts rejected by throttling
+func PrintAPIErrorStats(writer io.Writer, apiErrors, throttledAttempts int64) {
+	fmt.Fprintln(writer, "\n## AWS API Errors")
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "FAILED CALLS\tTHROTTLED ATTEMPTS")
+	fmt.Fprintf(w, "%d\t%d\n", apiErrors, throttledAttempts)
+	w.Flush()
+}
+
+// PrintAPICallStats prints the AWS API calls made by the scan. The verbose form lists the calls
+// per AWS service, operation, and region, and the scan duration of each idled service, while the
+// compact form prints the totals only.
+func PrintAPICallStats(writer io.Writer, stats []models.APICallStat, results []runner.Result, verbose bool) {
+	if len(stats) == 0 {
+		return
+	}
+
+	var calls, throttles int64
+	services := make(map[string]bool)
+	for _, stat := range stats {
+		calls += stat.Calls
+		throttles += stat.Throttles
+		services[stat.Service] = true
+	}
+
+	fmt.Fprintln(writer, "\n## AWS API Calls")
+	if !verbose {
+		fmt.Fprintf(writer, "%d calls to %d AWS services, %d throttled attempts (--verbose for details)\n",
+			calls, len(services), throttles)
+		return
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "AWS SERVICE\tOPERATION\tREGION\tCALLS\tTHROTTLED\tFAILED")
+	for _, stat := range stats {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\n",
+			stat.Service, stat.Operation, stat.Region, stat.Calls, stat.Throttles, stat.Errors)
+	}
+	fmt.Fprintf(w, "Total\t\t\t%d\t%d\t\n", calls, throttles)
+	w.Flush()
+
+	if len(results) == 0 {
+		return
+	}
+	fmt.Fprintln(writer, "\n## Scan Duration")
+	w = tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tDURATION")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\n", result.Service, result.Duration.Round(time.Millisecond))
+	}
+	w.Flush()
+}
+
+// PrintSkippedResources prints the resources the scanners could not evaluate. The compact form
+// counts them per service and reason, while the verbose form lists each resource with its error.
+func PrintSkippedResources(writer io.Writer, skipped []models.SkippedResource, verbose bool) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	fmt.Fprintln(writer, "\n## Skipped Resources")
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if verbose {
+		fmt.Fprintln(w, "SERVICE\tREGION\tRESOURCE\tREASON\tERROR")
+		for _, skip := range skipped {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				skip.Service, skip.Region, truncateString(skip.Resource, 60), skip.Reason, truncateString(skip.Error, 100))
+		}
+		w.Flush()
+		fmt.Fprintf(writer, "%d resources were left out of the results.\n", len(skipped))
+		return
+	}
+
+	type reasonKey struct{ service, reason string }
+	counts := make(map[reasonKey]int)
+	var keys []reasonKey
+	for _, skip := range skipped {
+		key := reasonKey{skip.Service, skip.Reason}
+		if counts[key] == 0 {
+			keys = append(keys, key)
+		}
+		counts[key]++
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].service != keys[j].service {
+			return keys[i].service < keys[j].service
+		}
+		return keys[i].reason < keys[j].reason
+	})
+
+	fmt.Fprintln(w, "SERVICE\tREASON\tSKIPPED")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", key.service, key.reason, counts[key])
+	}
+	w.Flush()
+	fmt.Fprintf(writer, "%d resources were left out of the results (--verbose for details).\n", len(skipped))
+}
@@ -0,0 +1,49 @@
+package formatter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+	"github.com/younsl/idled/internal/runner"
+)
+
+func TestPrintAPICallStats(t *testing.T) {
+	stats := []models.APICallStat{
+		{Service: "EC2", Operation: "DescribeVolumes", Region: "us-east-1", Calls: 4, Throttles: 2},
+		{Service: "EC2", Operation: "DescribeVolumes", Region: "eu-west-1", Calls: 3, Errors: 1},
+		{Service: "S3", Operation: "ListBuckets", Region: "us-east-1", Calls: 1},
+	}
+	results := []runner.Result{{Service: "ebs", Duration: 1234567 * time.Microsecond}}
+
+	var compact bytes.Buffer
+	PrintAPICallStats(&compact, stats, results, false)
+	if want := "8 calls to 2 AWS services, 2 throttled attempts"; !strings.Contains(compact.String(), want) {
+		t.Errorf("compact output does not contain %q:\n%s", want, compact.String())
+	}
+	if strings.Contains(compact.String(), "DescribeVolumes") || strings.Contains(compact.String(), "Scan Duration") {
+		t.Errorf("compact output shows the details:\n%s", compact.String())
+	}
+
+	var verbose bytes.Buffer
+	PrintAPICallStats(&verbose, stats, results, true)
+	for _, want := range []string{
+		"EC2          DescribeVolumes  eu-west-1  3      0          1",
+		"Total                                    8      2",
+		"ebs      1.235s",
+	} {
+		if !strings.Contains(verbose.String(), want) {
+			t.Errorf("verbose output does not contain %q:\n%s", want, verbose.String())
+		}
+	}
+}
+
+func TestPrintAPICallStatsWithoutCalls(t *testing.T) {
+	var output bytes.Buffer
+	PrintAPICallStats(&output, nil, []runner.Result{{Service: "ebs"}}, true)
+	if output.Len() != 0 {
+		t.Errorf("output = %q, want nothing without API calls", output.String())
+	}
+}
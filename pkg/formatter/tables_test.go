@@ -0,0 +1,380 @@
+package formatter
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// ptr returns a pointer to v
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var (
+	created  = time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	modified = time.Date(2026, 6, 15, 18, 0, 0, 0, time.UTC)
+)
+
+// TestPrintTablesGolden renders the table and summary of every resource type with resources
+// of more than one region and state, and compares them with testdata/<name>-table.golden
+func TestPrintTablesGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		render func(w io.Writer)
+	}{
+		{name: "asg", render: func(w io.Writer) {
+			groups := []models.AutoScalingGroupInfo{
+				{Name: "web-blue", Region: "us-east-1", CreatedTime: created, IdleDays: 120, ZeroCapacity: true},
+				{Name: "batch", Region: "eu-west-1", DesiredCapacity: 2, MinSize: 1, MaxSize: 4, InstanceCount: 2,
+					SuspendedProcesses: []string{"Launch", "Terminate"}, TargetGroupCount: 1, CreatedTime: created,
+					LastActivity: &modified, IdleDays: 30, NoHealthyTargets: true},
+			}
+			PrintASGTable(w, groups, time.Time{}, 0)
+			PrintASGSummary(w, groups)
+		}},
+		{name: "beanstalk", render: func(w io.Writer) {
+			environments := []models.BeanstalkEnvironmentInfo{
+				{EnvironmentName: "api-staging", EnvironmentID: "e-abc123", ApplicationName: "api", Region: "us-east-1",
+					Status: "Ready", Health: "Grey", LoadBalancerType: "ALB", InstanceCount: 2, InstanceTypes: []string{"t3.small", "t3.small"},
+					DateCreated: created, DateUpdated: modified, IdleReason: "No requests in 14 days", EstimatedMonthlyCost: 46.72, PricingSource: "API"},
+				{EnvironmentName: "worker", EnvironmentID: "e-def456", ApplicationName: "jobs", Region: "ap-northeast-2",
+					Status: "Ready", Health: "Green", InstanceCount: 1, InstanceTypes: []string{"m5.large"},
+					DateCreated: created, DateUpdated: modified, IdleReason: "No requests in 14 days", PricingSource: "N/A"},
+			}
+			PrintBeanstalkTable(w, environments, time.Time{}, 0)
+			PrintBeanstalkSummary(w, environments)
+		}},
+		{name: "cloudfront", render: func(w io.Writer) {
+			distributions := []models.DistributionInfo{
+				{ID: "E2EXAMPLE", DomainName: "d111111abcdef8.cloudfront.net", Aliases: []string{"cdn.example.com"},
+					OriginDomains: []string{"assets.s3.amazonaws.com"}, Enabled: true, Status: "Deployed",
+					RequestCount: ptr(0.0), LastModifiedTime: modified, IdleReason: "No requests in 30 days"},
+				{ID: "E1EXAMPLE", DomainName: "d222222abcdef8.cloudfront.net", Status: "Deployed",
+					LastModifiedTime: created, IdleReason: "Disabled"},
+			}
+			PrintCloudFrontTable(w, distributions, time.Time{}, 0)
+			PrintCloudFrontSummary(w, distributions)
+		}},
+		{name: "cloudwatch", render: func(w io.Writer) {
+			alarms := []models.CloudWatchAlarmInfo{
+				{Name: "cpu-high", Region: "us-east-1", Type: models.CloudWatchAlarmMetric, Namespace: "AWS/EC2", MetricName: "CPUUtilization",
+					StateValue: "INSUFFICIENT_DATA", StateSince: created, ActionsEnabled: true, IdleDays: 200,
+					MetricMissing: true, IdleReason: "Metric no longer exists", EstimatedMonthlyCost: 0.10},
+				{Name: "service-health", Region: "eu-west-1", Type: models.CloudWatchAlarmComposite,
+					StateValue: "OK", StateSince: created, IdleDays: 200, IdleReason: "Actions disabled", EstimatedMonthlyCost: 0.50},
+			}
+			dashboards := []models.CloudWatchDashboardInfo{
+				{Name: "legacy-ops", Region: "us-east-1", LastModified: created, SizeBytes: 2048, IdleDays: 400, IsIdle: true, EstimatedMonthlyCost: 3},
+				{Name: "payments", Region: "us-east-1", LastModified: modified, SizeBytes: 512, IdleDays: 5, EstimatedMonthlyCost: 3},
+			}
+			PrintCloudWatchAlarmsTable(w, alarms)
+			PrintCloudWatchDashboardsTable(w, dashboards)
+			PrintCloudWatchSummary(w, alarms, dashboards)
+		}},
+		{name: "config", render: func(w io.Writer) {
+			// The last activity is printed relative to the current day
+			today, yesterday := time.Now(), time.Now().Add(-36*time.Hour)
+			rules := []models.ConfigRuleInfo{
+				{RuleName: "s3-bucket-versioning", RuleID: "config-rule-1", Region: "us-east-1", CreatedTime: &created,
+					IsActive: true, EvaluationMode: "DETECTIVE", LastErrorCode: "AccessDenied", IsFailing: true,
+					IdleDays: 90, IsIdle: true, LastActivity: &yesterday},
+				{RuleName: "custom-tags", RuleID: "config-rule-2", Region: "eu-west-1", CreatedTime: &created, IsActive: true, IsCustom: true,
+					IsCompliant: true, EvaluationMode: "DETECTIVE", ScopeResourceTypes: []string{"AWS::EC2::Instance"},
+					NoResourcesInScope: true, IdleDays: 30, IsIdle: true, LastActivity: &today},
+			}
+			recorders := []models.ConfigRecorderInfo{
+				{RecorderName: "default", Region: "us-east-1", AllResourceTypes: true, ResourceCount: 120, IdleDays: 60, IsIdle: true, LastActivity: &yesterday},
+				{RecorderName: "default", Region: "eu-west-1", IsRecording: true, ResourceCount: 5, LastActivity: &today},
+			}
+			channels := []models.ConfigDeliveryChannelInfo{
+				{ChannelName: "default", Region: "us-east-1", S3BucketName: "config-bucket", Frequency: "TwentyFour_Hours",
+					IdleDays: 60, IsIdle: true, NeverDelivered: true},
+				{ChannelName: "default", Region: "eu-west-1", S3BucketName: "config-bucket-eu", SNSTopicARN: "arn:aws:sns:eu-west-1:111111111111:config",
+					Frequency: "One_Hour", LastActivity: &today},
+			}
+			FormatConfigRulesTable(w, rules)
+			FormatConfigRulesSummary(w, rules)
+			FormatConfigRecordersTable(w, recorders)
+			FormatConfigRecordersSummary(w, recorders)
+			FormatConfigDeliveryChannelsTable(w, channels)
+			FormatConfigDeliveryChannelsSummary(w, channels)
+		}},
+		{name: "ebs", render: func(w io.Writer) {
+			volumes := []models.VolumeInfo{
+				{VolumeID: "vol-0b", Name: "data", Size: 500, VolumeType: "gp3", IOPS: 6000, Throughput: 250, State: "available",
+					Region: "us-east-1", AvailabilityZone: "us-east-1a", CreationTime: created, LastAttachmentTime: &created,
+					ElapsedDaysSinceUsed: 180, LastInstanceID: "i-0123", LastDetachTime: &modified, HasIOMetrics: true,
+					LatestSnapshotDate: &modified, HasRecentSnapshot: true, HasSnapshotInfo: true,
+					EstimatedMonthlyCost: 55, EstimatedSavings: 330, PricingSource: "API"},
+				{VolumeID: "vol-0a", Size: 100, VolumeType: "io2", IOPS: 3000, State: "in-use", Region: "ap-northeast-2",
+					AvailabilityZone: "ap-northeast-2c", CreationTime: created, ElapsedDaysSinceUsed: 40, AttachedInstanceID: "i-0456",
+					CreatedBy: "AWS Backup", HasSnapshotInfo: true, EstimatedMonthlyCost: 208.5, EstimatedSavings: 278, PricingSource: "Default"},
+			}
+			PrintVolumesTable(w, volumes, time.Time{}, 0)
+			PrintVolumesSummary(w, volumes)
+		}},
+		{name: "ec2", render: func(w io.Writer) {
+			instances := []models.InstanceInfo{
+				{InstanceID: "i-0b", Name: "bastion", InstanceType: "t3.micro", OperatingSystem: "Linux", Region: "us-east-1",
+					AvailabilityZone: "us-east-1b", StoppedTime: &created, StoppedTimeSource: models.StoppedTimeSourceStateTransition,
+					LaunchTime: created, ElapsedDays: 300, EstimatedMonthlyCost: 7.59, EstimatedSavings: 75.9, PricingSource: "API",
+					AttachedVolumes: 1, AttachedStorageGB: 8, StorageMonthlyCost: 0.64, ElasticIP: "198.51.100.1", EIPMonthlyCost: 3.65,
+					CurrentMonthlyCost: 4.29},
+				{InstanceID: "i-0a", Name: "build-windows", InstanceType: "m5.large", OperatingSystem: "Windows", Region: "eu-west-1",
+					AvailabilityZone: "eu-west-1a", StoppedTime: &created, StoppedTimeSource: models.StoppedTimeSourceLaunchTime,
+					LaunchTime: created, ElapsedDays: 300, EstimatedMonthlyCost: 140.16, EstimatedSavings: 1401.6, PricingSource: "Default",
+					AttachedVolumes: 2, AttachedStorageGB: 130, CurrentMonthlyCost: 10.4, CurrentCostUnpriced: true,
+					Protected: true, ProtectionReason: "termination protection"},
+			}
+			PrintInstancesTable(w, instances, time.Time{}, 0)
+			PrintInstancesSummary(w, instances)
+		}},
+		{name: "ec2-underutilized", render: func(w io.Writer) {
+			instances := []models.UnderutilizedInstanceInfo{
+				{InstanceID: "i-0c", Name: "reports", InstanceType: "c5.xlarge", OperatingSystem: "Linux", Region: "us-east-1",
+					AvailabilityZone: "us-east-1a", LaunchTime: created, LookbackDays: 14, AvgCPU: 1.2, MaxCPU: 4.8,
+					NetworkBytesPerSec: 1536, EBSBytesPerSec: 2048, HasEBSMetrics: true, EstimatedMonthlyCost: 124.1, PricingSource: "API"},
+				{InstanceID: "i-0d", InstanceType: "t2.medium", OperatingSystem: "RHEL", Region: "us-east-1", AvailabilityZone: "us-east-1c",
+					LaunchTime: created, AutoScalingGroup: "legacy-asg", LookbackDays: 14, AvgCPU: 0.4, MaxCPU: 2,
+					NetworkBytesPerSec: 10, PricingSource: "N/A"},
+			}
+			PrintUnderutilizedInstancesTable(w, instances, time.Time{}, 0)
+			PrintUnderutilizedInstancesSummary(w, instances)
+		}},
+		{name: "ecr", render: func(w io.Writer) {
+			repos := []models.RepositoryInfo{
+				{Name: "legacy/api", Region: "us-east-1", URI: "111111111111.dkr.ecr.us-east-1.amazonaws.com/legacy/api",
+					LastPush: &created, CreatedAt: &created, Idle: true, ImageCount: 42},
+				{Name: "empty", Region: "eu-west-1", CreatedAt: &created, Idle: true},
+			}
+			coverage := []models.ECRScanCoverageInfo{
+				{RepositoryName: "legacy/api", Region: "us-east-1", ScanFrequency: "CONTINUOUS_SCAN", ScanStatus: "ACTIVE",
+					LastScannedAt: &modified, LastPush: &created},
+			}
+			PrintECRTable(w, repos, time.Time{}, 0)
+			PrintECRSummary(w, repos)
+			PrintECRScanCoverageTable(w, coverage)
+		}},
+		{name: "elasticache", render: func(w io.Writer) {
+			caches := []models.ElastiCacheInfo{
+				{Name: "sessions", Region: "us-east-1", DeploymentType: "Serverless", Engine: "redis", EngineVersion: "7",
+					Status: "available", CreationTime: &created, StoredBytes: ptr(2.5e9), RequestCount: ptr(0.0), IsIdle: true,
+					IdleReason: "No requests in 14 days", EstimatedMonthlyCost: 312.5, ValkeyEligible: true, ValkeySavingsPercent: 33,
+					Recommendation: "Delete the cache, or migrate to Valkey to save 33%"},
+				{Name: "catalog", Region: "eu-west-1", DeploymentType: "Provisioned", Engine: "memcached", EngineVersion: "1.6.22",
+					NodeType: "cache.t3.micro", NodeCount: 2, Status: "available", CreationTime: &created},
+			}
+			PrintElastiCacheTable(w, caches, time.Time{}, 0)
+			PrintElastiCacheSummary(w, caches)
+		}},
+		{name: "elb", render: func(w io.Writer) {
+			elbs := []models.ELBResource{
+				{Name: "internal-api", Type: "ALB", Region: "us-east-1", State: "active", CreatedTime: created,
+					ARN: "arn:aws:elasticloadbalancing:us-east-1:111111111111:loadbalancer/app/internal-api/abc", IsIdle: true,
+					IdleReason: "No targets", LastActivitySum: ptr(0.0), EstimatedMonthlyCost: 16.43, PricingSource: "API",
+					Protected: true, ProtectionReason: "deletion protection"},
+				{Name: "legacy-web", Type: "CLB", Region: "eu-west-1", State: "active", CreatedTime: created, UnhealthyTargetCount: 2,
+					IdleReason: "No healthy targets", MetricCheckFailed: true, EstimatedMonthlyCost: 20.44, PricingSource: "Default"},
+			}
+			PrintELBTable(w, elbs, time.Time{}, 0)
+			PrintELBSummary(w, elbs)
+		}},
+		{name: "eni", render: func(w io.Writer) {
+			enis := []models.ENIInfo{
+				{NetworkInterfaceID: "eni-0b", Description: "AWS Lambda VPC ENI-worker", InterfaceType: "lambda",
+					Creator: models.ENICreatorLambda, RequesterManaged: true, SubnetID: "subnet-1", VpcID: "vpc-1",
+					AvailabilityZone: "us-east-1a", PrivateIP: "10.0.1.15", SecurityGroups: []string{"sg-1", "sg-2"}, Region: "us-east-1"},
+				{NetworkInterfaceID: "eni-0a", Description: "detached", InterfaceType: "interface", Creator: models.ENICreatorOther,
+					SubnetID: "subnet-2", VpcID: "vpc-2", AvailabilityZone: "eu-west-1b", PrivateIP: "10.1.2.3", Region: "eu-west-1"},
+			}
+			PrintENIsTable(w, enis, time.Time{}, 0)
+			PrintENIsSummary(w, enis)
+		}},
+		{name: "globalaccelerator", render: func(w io.Writer) {
+			accelerators := []models.AcceleratorInfo{
+				{AcceleratorID: "1234abcd", Name: "game-edge", Enabled: true, Status: "DEPLOYED", IPAddressType: "IPV4",
+					DNSName: "a1234567890abcdef.awsglobalaccelerator.com", ProcessedBytesIn: ptr(0.0), CreatedTime: created,
+					IdleReason: "No traffic in 30 days", EstimatedMonthlyCost: 18},
+				{AcceleratorID: "5678efgh", Name: "old", Status: "DEPLOYED", IPAddressType: "DUAL_STACK",
+					DNSName: "a0987654321fedcba.awsglobalaccelerator.com", CreatedTime: created, IdleReason: "Disabled", EstimatedMonthlyCost: 18},
+			}
+			PrintGlobalAcceleratorTable(w, accelerators, time.Time{}, 0)
+			PrintGlobalAcceleratorSummary(w, accelerators)
+		}},
+		{name: "iam", render: func(w io.Writer) {
+			users := []models.IAMUserInfo{
+				{UserName: "ci-deploy", ARN: "arn:aws:iam::111111111111:user/ci-deploy", Region: "global", AccessKeyCount: 1,
+					IsIdle: true, IdleDays: 400, HasActiveAccessKeys: true, HasStaleKeys: true, AttachedPolicyCount: 2,
+					AccessKeys: []models.IAMAccessKeyInfo{{KeyIDSuffix: "WXYZ", Status: "Active", AgeDays: 500, IsStale: true}}},
+				{UserName: "alice", ARN: "arn:aws:iam::111111111111:user/alice", Region: "global", HasConsoleAccess: true,
+					HasMFAEnabled: true, IdleDays: 2},
+			}
+			roles := []models.IAMRoleInfo{
+				{RoleName: "old-lambda-role", ARN: "arn:aws:iam::111111111111:role/old-lambda-role", Region: "global",
+					IsIdle: true, IdleDays: 365, TrustPolicy: "lambda.amazonaws.com", AttachedPolicyCount: 1},
+				{RoleName: "partner-access", ARN: "arn:aws:iam::111111111111:role/partner-access", Region: "global",
+					IsIdle: true, IdleDays: 120, IsCrossAccountRole: true, TrustPolicy: "222222222222", HasInlinePolicies: true},
+			}
+			policies := []models.IAMPolicyInfo{
+				{PolicyName: "legacy-s3", ARN: "arn:aws:iam::111111111111:policy/legacy-s3", Region: "global",
+					IsIdle: true, IdleDays: 500, VersionCount: 3, DefaultVersion: "v3"},
+				{PolicyName: "ci", ARN: "arn:aws:iam::111111111111:policy/ci", Region: "global", IsAttached: true,
+					AttachmentCount: 1, VersionCount: 1, DefaultVersion: "v1", UsedServiceCount: 2, UnusedServiceCount: 5},
+			}
+			FormatIAMUserTable(w, users)
+			FormatIAMUserSummary(w, users)
+			FormatIAMRoleTable(w, roles)
+			FormatIAMRoleSummary(w, roles)
+			FormatIAMPolicyTable(w, policies)
+			FormatIAMPolicySummary(w, policies)
+		}},
+		{name: "lambda", render: func(w io.Writer) {
+			functions := []models.LambdaFunctionInfo{
+				{FunctionName: "resize-images", Runtime: "python3.12", Architecture: "arm64", Region: "us-east-1", MemorySize: 1024,
+					Timeout: 30, LastModified: &created, Status: models.LambdaStatusIdle, IdleDays: 200,
+					EstimatedMonthlyCost: 0, PricingSource: "API", HasTrigger: true, ProvisionedConcurrency: 2},
+				{FunctionName: "webhook", Runtime: "nodejs20.x", Architecture: "x86_64", Region: "eu-west-1", MemorySize: 128,
+					Timeout: 3, LastModified: &created, LastInvocation: &modified, InvocationsLast30Days: 1000,
+					ErrorsLast30Days: 990, DurationP95Last30Days: 120.5, Status: models.LambdaStatusFailing, IdleDays: 1,
+					EstimatedMonthlyCost: 0.02, PricingSource: "Default", ReservedConcurrency: ptr(int32(5))},
+			}
+			PrintLambdaTable(w, functions, time.Time{}, 0)
+			PrintLambdaSummary(w, functions)
+		}},
+		{name: "logs", render: func(w io.Writer) {
+			logGroups := []models.LogGroupInfo{
+				{Name: "/aws/lambda/resize-images", Region: "us-east-1", RetentionDays: "Never Expire", StoredBytes: 5 << 30,
+					LastEventTime: "2025-03-01", CreationTime: created, EstimatedMonthlyCost: 0.15, PricingSource: "API",
+					Recommendation: "Set a retention period", HasMetricFilter: true},
+				{Name: "/ecs/old-service", Region: "eu-west-1", RetentionDays: "30", StoredBytes: 1024,
+					LastEventTime: "2026-06-15", CreationTime: created, PricingSource: "N/A", FilterCheckFailed: true},
+			}
+			PrintLogGroupsTable(w, logGroups)
+			PrintLogGroupsSummary(w, logGroups)
+		}},
+		{name: "msk", render: func(w io.Writer) {
+			clusters := []models.MskClusterInfo{
+				{ClusterName: "events", Region: "us-east-1", State: "ACTIVE", ClusterType: "PROVISIONED", BrokerCount: 3,
+					InstanceType: "kafka.m5.large", CreationTime: created, IsIdle: true, Reason: "No Connections",
+					ConnectionCount: ptr(0.0), AvgCPUUtilization: ptr(2.5), ZeroConnectionDays: 30, ConsecutiveZeroConnectionDays: 30,
+					EstimatedMonthlyCost: 459.9, PricingSource: "API"},
+				{ClusterName: "analytics", Region: "eu-west-1", State: "ACTIVE", ClusterType: "SERVERLESS", CreationTime: created,
+					ConnectionCount: ptr(12.0), PricingSource: "N/A"},
+			}
+			PrintMskTable(w, clusters, time.Time{}, 0)
+			PrintMskSummary(w, clusters)
+		}},
+		{name: "opensearch", render: func(w io.Writer) {
+			domains := []models.OpenSearchDomainInfo{
+				{DomainName: "logs", Region: "us-east-1", EngineVersion: "OpenSearch_2.11", InstanceType: "r6g.large.search",
+					InstanceCount: 2, DedicatedMasterType: "m6g.large.search", DedicatedMasterCount: 3, VolumeType: "gp3",
+					VolumeSizeGB: 100, SearchRate: ptr(0.0), IndexingRate: ptr(0.0), AvgCPUUtilization: ptr(1.5), IsIdle: true,
+					Reason: "No Search & Indexing", EstimatedMonthlyCost: 612.4, PricingSource: "API"},
+				{DomainName: "search", Region: "eu-west-1", EngineVersion: "Elasticsearch_7.10", InstanceType: "t3.small.search",
+					InstanceCount: 1, SearchRate: ptr(15.0), IndexingRate: ptr(2.0), AvgCPUUtilization: ptr(3.0), IsIdle: true,
+					Reason: "Low CPU Usage", EstimatedMonthlyCost: 26.28, PricingSource: "Default"},
+			}
+			PrintOpenSearchTable(w, domains, time.Time{}, 0)
+			PrintOpenSearchSummary(w, domains)
+		}},
+		{name: "resolver", render: func(w io.Writer) {
+			endpoints := []models.ResolverEndpointInfo{
+				{EndpointID: "rslvr-in-1", Name: "onprem-in", Region: "us-east-1", Direction: "INBOUND", VPCID: "vpc-1",
+					IPAddressCount: 2, Status: "OPERATIONAL", IdleReason: "No queries in 30 days", EstimatedMonthlyCost: 182.5},
+				{EndpointID: "rslvr-out-1", Region: "eu-west-1", Direction: "OUTBOUND", VPCID: "vpc-2", IPAddressCount: 3,
+					Status: "ACTION_NEEDED", QueryVolume: 4, IdleReason: "Fewer than 10 queries in 30 days", EstimatedMonthlyCost: 273.75},
+			}
+			PrintResolverTable(w, endpoints, time.Time{}, 0)
+			PrintResolverSummary(w, endpoints)
+		}},
+		{name: "route53", render: func(w io.Writer) {
+			zones := []models.HostedZoneInfo{
+				{ID: "Z1PRIVATE", Name: "corp.internal", Private: true, RecordCount: 2, IdleReason: "Not associated with a VPC", EstimatedMonthlyCost: 0.5},
+				{ID: "Z2PUBLIC", Name: "example.com", RecordCount: 2, NameServers: []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"},
+					IdleReason: "Not delegated", EstimatedMonthlyCost: 0.5},
+			}
+			healthChecks := []models.HealthCheckInfo{
+				{ID: "hc-1", Type: "HTTPS", Target: "api.example.com:443", Disabled: true, IdleReason: "Disabled", EstimatedMonthlyCost: 0.75},
+			}
+			FormatRoute53PrivateZonesTable(w, zones)
+			FormatRoute53PublicZonesTable(w, zones)
+			FormatRoute53HealthChecksTable(w, healthChecks)
+			FormatRoute53Summary(w, zones, healthChecks)
+		}},
+		{name: "s3", render: func(w io.Writer) {
+			buckets := []models.BucketInfo{
+				{BucketName: "old-exports", Region: "us-east-1", CreationTime: created, ObjectCount: 1200, TotalSize: 3 << 30,
+					EstimatedMonthlyCost: 0.07, PricingSource: "API", LastModified: &created, IsIdle: true, IdleDays: 500,
+					HasLifecyclePolicy: true, VersioningEnabled: true, IncompleteMPUCount: 3, Protected: true, ProtectionReason: "Object Lock"},
+				{BucketName: "empty-bucket", Region: "eu-west-1", CreationTime: created, PricingSource: "Default",
+					ActivityUnknown: true, IsEmpty: true, IsIdle: true, HasBucketPolicy: true},
+			}
+			PrintBucketsTable(w, buckets, time.Time{}, 0)
+			PrintBucketsSummary(w, buckets)
+		}},
+		{name: "sagemaker", render: func(w io.Writer) {
+			resources := []models.SageMakerResourceInfo{
+				{Name: "churn-model", Region: "us-east-1", ResourceType: models.SageMakerResourceEndpoint, InstanceType: "ml.m5.large",
+					InstanceCount: 2, Status: "InService", CreationTime: created, LastModifiedTime: modified, Invocations: ptr(0.0),
+					IdleReason: "No invocations in 14 days", EstimatedMonthlyCost: 168.19, PricingSource: "API"},
+				{Name: "research", Region: "eu-west-1", ResourceType: models.SageMakerResourceNotebook, InstanceType: "ml.t3.medium",
+					InstanceCount: 1, Status: "InService", CreationTime: created, LastModifiedTime: modified, VolumeSizeGB: 50,
+					AvgCPUUtilization: ptr(0.8), IdleReason: "CPU below 5%", EstimatedMonthlyCost: 41.5, PricingSource: "Default"},
+			}
+			PrintSageMakerTable(w, resources, time.Time{}, 0)
+			PrintSageMakerSummary(w, resources)
+		}},
+		{name: "secretsmanager", render: func(w io.Writer) {
+			secrets := []models.SecretInfo{
+				{Name: "prod/db-password", Region: "us-east-1", LastAccessedDate: created, CreatedDate: created, IdleDays: 400,
+					RotationEnabled: true, LastRotatedDate: &created, EstimatedMonthlyCost: 0.4},
+				{Name: "legacy/api-key", Region: "eu-west-1", CreatedDate: created, NeverAccessed: true, IdleDays: 500, EstimatedMonthlyCost: 0.4},
+			}
+			PrintSecretsTable(w, secrets, time.Time{}, 0)
+			PrintSecretsSummary(w, secrets)
+		}},
+		{name: "sfn", render: func(w io.Writer) {
+			machines := []models.StateMachineInfo{
+				{Name: "nightly-etl", Type: "STANDARD", Region: "us-east-1", CreationDate: created, LastExecution: &created,
+					IsIdle: true, IdleDays: 300},
+				{Name: "orders", Type: "EXPRESS", Region: "eu-west-1", CreationDate: created, LastExecution: &modified,
+					ExecutionsInWindow: 1500, IdleDays: 0},
+			}
+			PrintStateMachinesTable(w, machines, time.Time{}, 0)
+			PrintStateMachinesSummary(w, machines)
+		}},
+		{name: "transfer", render: func(w io.Writer) {
+			servers := []models.TransferServerInfo{
+				{ServerID: "s-0123", Region: "us-east-1", EndpointType: "PUBLIC", Domain: "S3", Protocols: []string{"SFTP", "FTPS"},
+					State: "ONLINE", UserCount: 3, IdleReason: "No files transferred in 30 days", EstimatedMonthlyCost: 438},
+				{ServerID: "s-0456", Region: "eu-west-1", EndpointType: "VPC", Domain: "EFS", Protocols: []string{"SFTP"},
+					State: "ONLINE", IdleReason: "No users", EstimatedMonthlyCost: 219},
+			}
+			PrintTransferTable(w, servers, time.Time{}, 0)
+			PrintTransferSummary(w, servers)
+		}},
+		{name: "workspaces", render: func(w io.Writer) {
+			workspaces := []models.WorkSpaceInfo{
+				{WorkspaceID: "ws-0a", UserName: "bob", DirectoryID: "d-1", BundleID: "wsb-1", ComputeType: "STANDARD",
+					RunningMode: "ALWAYS_ON", State: "AVAILABLE", Region: "us-east-1", LastConnection: &created, IdleDays: 400,
+					EstimatedMonthlyCost: 35, PricingSource: "Default"},
+				{WorkspaceID: "ws-0b", UserName: "carol", DirectoryID: "d-1", BundleID: "wsb-2", ComputeType: "PERFORMANCE",
+					RunningMode: "AUTO_STOP", State: "STOPPED", Region: "us-east-1", PricingSource: "N/A"},
+			}
+			PrintWorkSpacesTable(w, workspaces, time.Time{}, 0)
+			PrintWorkSpacesSummary(w, workspaces)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			tt.render(&output)
+			checkGolden(t, tt.name+"-table.golden", output.Bytes())
+		})
+	}
+}
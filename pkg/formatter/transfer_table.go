@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// PrintTransferTable prints the idle Transfer Family servers in a table format.
+func PrintTransferTable(writer io.Writer, servers []models.TransferServerInfo, _ time.Time, _ time.Duration) {
+	if len(servers) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by estimated cost (descending), the servers with the most protocols first
+	sort.SliceStable(servers, func(i, j int) bool {
+		return servers[i].EstimatedMonthlyCost > servers[j].EstimatedMonthlyCost
+	})
+
+	transferServersTable.Print(writer, servers)
+}
+
+// transferServersTable defines the columns of the idle Transfer Family servers table
+var transferServersTable = newTable(Table[models.TransferServerInfo]{
+	Service: "transfer",
+	Columns: []Column[models.TransferServerInfo]{
+		{Key: "server-id", Header: "SERVER ID", Value: func(s models.TransferServerInfo) string { return s.ServerID }},
+		{Key: "region", Header: "REGION", Value: func(s models.TransferServerInfo) string { return s.Region }},
+		{Key: "endpoint-type", Header: "ENDPOINT TYPE", Value: func(s models.TransferServerInfo) string { return s.EndpointType }},
+		{Key: "domain", Header: "DOMAIN", Value: func(s models.TransferServerInfo) string { return s.Domain }},
+		{Key: "protocols", Header: "PROTOCOLS", Value: formatTransferProtocols},
+		{Key: "state", Header: "STATE", Value: func(s models.TransferServerInfo) string { return s.State }},
+		{Key: "users", Header: "USERS",
+			Value:  func(s models.TransferServerInfo) string { return strconv.Itoa(s.UserCount) },
+			Number: func(s models.TransferServerInfo) float64 { return float64(s.UserCount) }},
+		{Key: "cost", Header: "COST/MO",
+			Value:  func(s models.TransferServerInfo) string { return fmt.Sprintf("$%.2f", s.EstimatedMonthlyCost) },
+			Number: func(s models.TransferServerInfo) float64 { return s.EstimatedMonthlyCost },
+			Total:  sumColumn(func(s models.TransferServerInfo) float64 { return s.EstimatedMonthlyCost })},
+		{Key: "reason", Header: "REASON", Value: func(s models.TransferServerInfo) string { return s.IdleReason }},
+	},
+	Links: true,
+})
+
+// formatTransferProtocols formats the protocols enabled on a server
+func formatTransferProtocols(server models.TransferServerInfo) string {
+	if len(server.Protocols) == 0 {
+		return "-"
+	}
+	return strings.Join(server.Protocols, ",")
+}
+
+// PrintTransferSummary prints the idle Transfer Family server counts by state and the monthly cost
+func PrintTransferSummary(writer io.Writer, servers []models.TransferServerInfo) {
+	if len(servers) == 0 {
+		return
+	}
+
+	var online, stopped, withoutUsers int
+	var totalMonthlyCost float64
+	for _, server := range servers {
+		if server.State == "ONLINE" {
+			online++
+		} else {
+			stopped++
+		}
+		if server.UserCount == 0 {
+			withoutUsers++
+		}
+		totalMonthlyCost += server.EstimatedMonthlyCost
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## Transfer Family Summary")
+	fmt.Fprintf(w, "Idle online servers:\t%d\n", online)
+	fmt.Fprintf(w, "Idle stopped or other servers:\t%d\n", stopped)
+	fmt.Fprintf(w, "Servers without users:\t%d\n", withoutUsers)
+	fmt.Fprintf(w, "Estimated monthly cost:\t$%.2f\n", totalMonthlyCost)
+
+	w.Flush()
+
+	printRegionBreakdown(writer, "Transfer Family Servers by Region", servers,
+		func(s models.TransferServerInfo) string { return s.Region },
+		func(s models.TransferServerInfo) float64 { return s.EstimatedMonthlyCost },
+		nil)
+}
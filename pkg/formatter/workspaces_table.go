@@ -0,0 +1,133 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/younsl/idled/internal/models"
+)
+
+// workSpacesIdleThreshold is the number of days without a user connection used by the WorkSpaces scan
+var workSpacesIdleThreshold = 30
+
+// SetWorkSpacesIdleThreshold sets the idle threshold shown in the WorkSpaces table footer
+func SetWorkSpacesIdleThreshold(days int) {
+	workSpacesIdleThreshold = days
+}
+
+// PrintWorkSpacesTable prints the idle WorkSpaces in a table format.
+func PrintWorkSpacesTable(writer io.Writer, workspaces []models.WorkSpaceInfo, _ time.Time, _ time.Duration) {
+	if len(workspaces) == 0 {
+		// Spinner will indicate if nothing was found
+		return
+	}
+
+	// Sort by savings (descending), then by idle days (longest idle first)
+	sort.SliceStable(workspaces, func(i, j int) bool {
+		if workspaces[i].EstimatedMonthlyCost != workspaces[j].EstimatedMonthlyCost {
+			return workspaces[i].EstimatedMonthlyCost > workspaces[j].EstimatedMonthlyCost
+		}
+		return workspaces[i].IdleDays > workspaces[j].IdleDays
+	})
+
+	workSpacesTable.Print(writer, workspaces)
+
+	fmt.Fprintf(writer, "\nShowing %d idle WorkSpaces (no user connection for at least %d days)\n", len(workspaces), workSpacesIdleThreshold)
+}
+
+// workSpacesTable defines the columns of the idle WorkSpaces table
+var workSpacesTable = newTable(Table[models.WorkSpaceInfo]{
+	Service: "workspaces",
+	Columns: []Column[models.WorkSpaceInfo]{
+		{Key: "workspace-id", Header: "WORKSPACE ID", Value: func(w models.WorkSpaceInfo) string { return w.WorkspaceID }},
+		{Key: "user", Header: "USER", Value: func(w models.WorkSpaceInfo) string { return w.UserName }},
+		{Key: "region", Header: "REGION", Value: func(w models.WorkSpaceInfo) string { return w.Region }},
+		{Key: "bundle", Header: "BUNDLE", Value: func(w models.WorkSpaceInfo) string { return w.BundleID }},
+		{Key: "compute", Header: "COMPUTE", Value: func(w models.WorkSpaceInfo) string { return w.ComputeType }},
+		{Key: "running-mode", Header: "RUNNING MODE", Value: func(w models.WorkSpaceInfo) string { return w.RunningMode }},
+		{Key: "state", Header: "STATE", Value: func(w models.WorkSpaceInfo) string { return w.State }},
+		{Key: "last-connection", Header: "LAST CONNECTION", Value: func(w models.WorkSpaceInfo) string {
+			if w.LastConnection == nil {
+				return "Never"
+			}
+			return w.LastConnection.Format("2006-01-02")
+		}},
+		{Key: "idle-days", Header: "IDLE DAYS",
+			Value: func(w models.WorkSpaceInfo) string {
+				if w.LastConnection == nil {
+					return "-"
+				}
+				return strconv.Itoa(w.IdleDays)
+			},
+			Number: func(w models.WorkSpaceInfo) float64 { return float64(w.IdleDays) }},
+		{Key: "savings", Header: "SAVINGS/MO",
+			Value: func(w models.WorkSpaceInfo) string {
+				if w.PricingUnavailable() {
+					return "N/A"
+				}
+				return fmt.Sprintf("$%.2f", w.EstimatedMonthlyCost)
+			},
+			Number: func(w models.WorkSpaceInfo) float64 { return w.EstimatedMonthlyCost },
+			Total:  sumColumn(func(w models.WorkSpaceInfo) float64 { return w.EstimatedMonthlyCost })},
+		{Key: "pricing", Header: "PRICING", Value: func(w models.WorkSpaceInfo) string { return GetPricingMarker(w.PricingSource) }},
+	},
+	Links: true,
+})
+
+// PrintWorkSpacesSummary prints the idle WorkSpaces grouped by directory, with their running modes and savings
+func PrintWorkSpacesSummary(writer io.Writer, workspaces []models.WorkSpaceInfo) {
+	if len(workspaces) == 0 {
+		return
+	}
+
+	type directorySummary struct {
+		region    string
+		directory string
+		count     int
+		alwaysOn  int
+		autoStop  int
+		never     int
+		savings   float64
+	}
+
+	var directories []*directorySummary
+	byDirectory := make(map[string]*directorySummary)
+	for _, workspace := range workspaces {
+		key := workspace.Region + "/" + workspace.DirectoryID
+		summary, exists := byDirectory[key]
+		if !exists {
+			summary = &directorySummary{region: workspace.Region, directory: workspace.DirectoryID}
+			byDirectory[key] = summary
+			directories = append(directories, summary)
+		}
+		summary.count++
+		if workspace.RunningMode == "ALWAYS_ON" {
+			summary.alwaysOn++
+		} else {
+			summary.autoStop++
+		}
+		if workspace.LastConnection == nil {
+			summary.never++
+		}
+		summary.savings += workspace.EstimatedMonthlyCost
+	}
+
+	sort.SliceStable(directories, func(i, j int) bool {
+		return directories[i].savings > directories[j].savings
+	})
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "\n## WorkSpaces by Directory")
+	fmt.Fprintln(w, "DIRECTORY\tREGION\tIDLE\tALWAYS_ON\tAUTO_STOP/MANUAL\tNEVER CONNECTED\tSAVINGS/MO")
+	for _, summary := range directories {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t$%.2f\n",
+			summary.directory, summary.region, summary.count, summary.alwaysOn, summary.autoStop, summary.never, summary.savings)
+	}
+
+	w.Flush()
+}
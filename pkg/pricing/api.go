@@ -3,104 +3,151 @@ package pricing
 import (
 	"context"
 	"fmt"
-	"sync"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/pricing"
 	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
-	"github.com/briandowns/spinner"
+	"github.com/younsl/idled/internal/progress"
 )
 
-// AWS pricing client implementation
-var (
-	// PricingClient is the AWS Pricing API client
-	PricingClient *pricing.Client
+// ProductsAPI is the part of the AWS Pricing API client used for price lookups, so that the
+// client can be replaced, e.g., by the failover client or a stub
+type ProductsAPI interface {
+	GetProducts(ctx context.Context, params *pricing.GetProductsInput, optFns ...func(*pricing.Options)) (*pricing.GetProductsOutput, error)
+}
 
-	// PricingInitOnce ensures the client is initialized only once
-	PricingInitOnce sync.Once
+// DefaultAPITimeout is the default timeout of each pricing API call
+const DefaultAPITimeout = 5 * time.Second
 
-	// Spinners for different services
-	pricingSpinners = make(map[string]*spinner.Spinner)
+// DefaultRegion is the default Pricing API endpoint region
+const DefaultRegion = "us-east-1"
 
-	// InitMessage stores the API initialization message to be displayed after spinners
-	InitMessage string
-)
+// Regions lists the regions with a Pricing API endpoint
+var Regions = []string{"us-east-1", "ap-south-1", "eu-central-1"}
+
+// SetRegion sets the preferred Pricing API endpoint region. It must be called before the
+// first price lookup.
+func (s *PricingService) SetRegion(region string) error {
+	if !slices.Contains(Regions, region) {
+		return fmt.Errorf("unsupported pricing region '%s' (supported: %s)", region, strings.Join(Regions, ", "))
+	}
+	s.region = region
+	return nil
+}
+
+// SetConfigOptions sets extra options of the AWS config the Pricing API client is built from,
+// e.g. an endpoint override. It must be called before the first lookup.
+func (s *PricingService) SetConfigOptions(optFns ...func(*config.LoadOptions) error) {
+	s.configOptions = optFns
+}
+
+// init initializes the Pricing API client on the first lookup, loading its config with the
+// context of that lookup
+func (s *PricingService) init(ctx context.Context) {
+	s.initOnce.Do(func() { s.initClient(ctx) })
+}
 
-// InitPricingClient initializes the AWS pricing client
-// The AWS Pricing API is only available in us-east-1 and ap-south-1 regions
-func InitPricingClient() {
-	pricingRegion := "us-east-1" // Pricing API is only available in us-east-1 and ap-south-1
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(pricingRegion))
+// initClient initializes the AWS pricing client for the preferred region, failing over
+// to an alternative endpoint if the first call cannot reach the preferred one
+func (s *PricingService) initClient(ctx context.Context) {
+	optFns := append([]func(*config.LoadOptions) error{config.WithRegion(s.region)}, s.configOptions...)
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
-		InitMessage = fmt.Sprintf("Error loading AWS config for pricing API: %v. Using fallback pricing.", err)
+		s.setInitMessage(fmt.Sprintf("Error loading AWS config for pricing API: %v. Using fallback pricing.", err))
 		return
 	}
 
-	PricingClient = pricing.NewFromConfig(cfg)
-	InitMessage = fmt.Sprintf("AWS Pricing API initialized in %s region (https://api.pricing.%s.amazonaws.com)", pricingRegion, pricingRegion)
+	alternateRegion := alternatePricingRegion(s.region)
+	s.client = newFailoverClient(
+		s.region, pricing.NewFromConfig(cfg),
+		alternateRegion, pricing.NewFromConfig(cfg, func(o *pricing.Options) { o.Region = alternateRegion }),
+		s.timeout, s.setInitMessage,
+	)
+	s.setInitMessage(fmt.Sprintf("AWS Pricing API initialized in %s region (%s), failing over to %s if unreachable",
+		s.region, pricingEndpoint(s.region), alternateRegion))
 }
 
-// GetInitMessage returns the initialization message and clears it
-func GetInitMessage() string {
-	msg := InitMessage
-	InitMessage = "" // Clear the message after it's retrieved
-	return msg
+// alternatePricingRegion returns the endpoint region to fail over to: ap-south-1 for us-east-1,
+// us-east-1 otherwise
+func alternatePricingRegion(region string) string {
+	if region == "us-east-1" {
+		return "ap-south-1"
+	}
+	return "us-east-1"
 }
 
-// initPricingSpinner initializes a spinner for a specific service
-func initPricingSpinner(service string) *spinner.Spinner {
-	if s, exists := pricingSpinners[service]; exists {
-		return s
-	}
+// pricingEndpoint returns the Pricing API endpoint URL of a region
+func pricingEndpoint(region string) string {
+	return fmt.Sprintf("https://api.pricing.%s.amazonaws.com", region)
+}
 
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-	s.Suffix = fmt.Sprintf(" Retrieving %s pricing information", service)
-	s.Color("green")
-	pricingSpinners[service] = s
+// setInitMessage replaces the initialization message
+func (s *PricingService) setInitMessage(msg string) {
+	s.initMessageMu.Lock()
+	defer s.initMessageMu.Unlock()
+	s.initMessage = msg
+}
 
-	return s
+// DisableAPI keeps the pricing client from being initialized, so every price comes from the
+// bundled fallback prices without any pricing API call. It must be called before the first price lookup.
+func (s *PricingService) DisableAPI() {
+	s.initOnce.Do(func() {
+		s.apiDisabled = true
+		s.setInitMessage("AWS Pricing API disabled. Using bundled default prices.")
+	})
 }
 
-// GetPricingSpinner returns the spinner for a specific service
-func GetPricingSpinner(service string) *spinner.Spinner {
-	// Initialize spinner if needed
-	if _, exists := pricingSpinners[service]; !exists && PricingClient != nil {
-		initPricingSpinner(service)
-	}
+// APIDisabled reports whether the pricing API was disabled with DisableAPI
+func (s *PricingService) APIDisabled() bool {
+	return s.apiDisabled
+}
 
-	return pricingSpinners[service]
+// SetAPITimeout sets the timeout of each pricing API call. It must be called before the
+// first price lookup.
+func (s *PricingService) SetAPITimeout(timeout time.Duration) {
+	s.timeout = timeout
 }
 
-// StartServiceSpinner starts the spinner for a service with appropriate text
-func StartServiceSpinner(service, resourceType, region string) {
-	spinner := GetPricingSpinner(service)
-	if spinner != nil {
-		spinner.Suffix = fmt.Sprintf(" %s in %s", resourceType, region)
-		spinner.Start()
-	}
+// GetInitMessage returns the initialization message and clears it
+func (s *PricingService) GetInitMessage() string {
+	s.initMessageMu.Lock()
+	defer s.initMessageMu.Unlock()
+
+	msg := s.initMessage
+	s.initMessage = "" // Clear the message after it's retrieved
+	return msg
 }
 
-// StopServiceSpinner stops the spinner for a service
-func StopServiceSpinner(service string) {
-	if spinner, exists := pricingSpinners[service]; exists {
-		spinner.Stop()
-	}
+// SetProgress sets the function receiving pricing lookups as progress messages, or nil for none.
+// Pricing lookups report through the progress display of the scan instead of their own spinner.
+func (s *PricingService) SetProgress(f progress.Func) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.progress = f
+}
+
+// reportProgress reports a pricing lookup to the progress function, if any
+func (s *PricingService) reportProgress(msg string) {
+	s.progressMu.RLock()
+	f := s.progress
+	s.progressMu.RUnlock()
+	f.Report(0, 0, msg)
 }
 
 // GetPriceFromAPI is a generic function to get pricing data from AWS API
-func GetPriceFromAPI(ctx context.Context, serviceCode string, filters []types.Filter, service, resourceType, region string) (string, error) {
+func (s *PricingService) GetPriceFromAPI(ctx context.Context, serviceCode string, filters []types.Filter, service, resourceType, region string) (string, error) {
 	// Ensure client is initialized
-	PricingInitOnce.Do(InitPricingClient)
+	s.init(ctx)
 
-	if PricingClient == nil {
+	if s.client == nil {
 		return "", fmt.Errorf("AWS pricing client not initialized")
 	}
 
-	// Start spinner
-	StartServiceSpinner(service, resourceType, GetRegionDescriptiveName(region))
-	defer StopServiceSpinner(service)
+	s.reportProgress(fmt.Sprintf("Retrieving %s pricing: %s in %s", service, resourceType, GetRegionDescriptiveName(region)))
 
 	// Prepare the API input
 	input := &pricing.GetProductsInput{
@@ -110,7 +157,7 @@ func GetPriceFromAPI(ctx context.Context, serviceCode string, filters []types.Fi
 	}
 
 	// Call the API
-	resp, err := PricingClient.GetProducts(ctx, input)
+	resp, err := s.client.GetProducts(ctx, input)
 	if err != nil {
 		return "", fmt.Errorf("error calling AWS Pricing API: %w", err)
 	}
@@ -123,17 +170,15 @@ func GetPriceFromAPI(ctx context.Context, serviceCode string, filters []types.Fi
 }
 
 // GetPricingProducts gets multiple pricing products from AWS API
-func GetPricingProducts(ctx context.Context, serviceCode string, filters []types.Filter, service, resourceType, region string) ([]string, error) {
+func (s *PricingService) GetPricingProducts(ctx context.Context, serviceCode string, filters []types.Filter, service, resourceType, region string) ([]string, error) {
 	// Ensure client is initialized
-	PricingInitOnce.Do(InitPricingClient)
+	s.init(ctx)
 
-	if PricingClient == nil {
+	if s.client == nil {
 		return nil, fmt.Errorf("AWS pricing client not initialized")
 	}
 
-	// Start spinner
-	StartServiceSpinner(service, resourceType, GetRegionDescriptiveName(region))
-	defer StopServiceSpinner(service)
+	s.reportProgress(fmt.Sprintf("Retrieving %s pricing: %s in %s", service, resourceType, GetRegionDescriptiveName(region)))
 
 	// Prepare the API input
 	input := &pricing.GetProductsInput{
@@ -143,7 +188,7 @@ func GetPricingProducts(ctx context.Context, serviceCode string, filters []types
 	}
 
 	// Call the API
-	resp, err := PricingClient.GetProducts(ctx, input)
+	resp, err := s.client.GetProducts(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("error calling AWS Pricing API: %w", err)
 	}
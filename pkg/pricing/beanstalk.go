@@ -0,0 +1,21 @@
+package pricing
+
+// CalculateBeanstalkEnvironmentMonthlyCostWithSource calculates the approximate monthly cost of
+// an Elastic Beanstalk environment from its EC2 instances, which all run the same operating system,
+// and its load balancer ("ALB", "NLB", or "CLB", empty if none), and returns the pricing source of the components that could be
+// priced. Beanstalk itself is free, and storage and data transfer are left out.
+func (s *PricingService) CalculateBeanstalkEnvironmentMonthlyCostWithSource(instanceTypes []string, operatingSystem, lbType, region string) (float64, string) {
+	var totalCost float64
+	source := PricingSourceNA
+	for _, instanceType := range instanceTypes {
+		cost, instanceSource := s.CalculateMonthlyCostWithSource(instanceType, region, operatingSystem)
+		totalCost += cost
+		source = combinePricingSources(source, PricingSource(instanceSource))
+	}
+	if lbType != "" {
+		cost, lbSource := s.CalculateELBMonthlyCostWithSource(lbType, region)
+		totalCost += cost
+		source = combinePricingSources(source, PricingSource(lbSource))
+	}
+	return totalCost, string(source)
+}
@@ -7,42 +7,48 @@ import (
 	"github.com/younsl/idled/pkg/utils"
 )
 
-// UpdateCacheHitStats updates stats when a cache hit occurs
-func UpdateCacheHitStats(service, region string) {
-	updatePricingAPIStats(service, region, "cache")
+// UpdateCacheHitStats updates stats when a cache hit occurs. Hits on prices loaded from the
+// disk cache are counted separately from prices retrieved during this run.
+func (s *PricingService) UpdateCacheHitStats(service, region, cacheKey string) {
+	if s.isDiskCacheHit(service, cacheKey) {
+		s.updatePricingAPIStats(service, region, "disk")
+		return
+	}
+	s.updatePricingAPIStats(service, region, "cache")
 }
 
 // UpdateAPISuccessStats updates stats when an API call succeeds
-func UpdateAPISuccessStats(service, region string) {
-	updatePricingAPIStats(service, region, "success")
+func (s *PricingService) UpdateAPISuccessStats(service, region string) {
+	s.updatePricingAPIStats(service, region, "success")
 }
 
 // UpdateAPIFailureStats updates stats when an API call fails
-func UpdateAPIFailureStats(service, region string) {
-	updatePricingAPIStats(service, region, "failure")
+func (s *PricingService) UpdateAPIFailureStats(service, region string) {
+	s.updatePricingAPIStats(service, region, "failure")
 }
 
 // updatePricingAPIStats updates the tracking statistics for Pricing API calls
-func updatePricingAPIStats(service, region, statType string) {
-	PricingAPIStatsLock.Lock()
-	defer PricingAPIStatsLock.Unlock()
+func (s *PricingService) updatePricingAPIStats(service, region, statType string) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
 
 	// Initialize service map if needed
-	if _, exists := PricingAPIStats[service]; !exists {
-		PricingAPIStats[service] = make(map[string]map[string]int)
+	if _, exists := s.stats[service]; !exists {
+		s.stats[service] = make(map[string]map[string]int)
 	}
 
 	// Initialize region map if needed
-	if _, exists := PricingAPIStats[service][region]; !exists {
-		PricingAPIStats[service][region] = map[string]int{
+	if _, exists := s.stats[service][region]; !exists {
+		s.stats[service][region] = map[string]int{
 			"success": 0,
 			"failure": 0,
 			"cache":   0,
+			"disk":    0,
 		}
 	}
 
 	// Increment the appropriate counter
-	PricingAPIStats[service][region][statType]++
+	s.stats[service][region][statType]++
 }
 
 // GetRegionDescriptiveName returns the human-readable region name used in AWS Pricing API
@@ -112,3 +118,19 @@ func ExtractOnDemandPrice(priceJSON string) (float64, error) {
 
 	return price, nil
 }
+
+// combinePricingSources returns the pricing source to report for a cost priced from several
+// components: Estimated wins over Default, which wins over Cache, which wins over API
+func combinePricingSources(a, b PricingSource) PricingSource {
+	rank := map[PricingSource]int{
+		PricingSourceNA:        0,
+		PricingSourceAPI:       1,
+		PricingSourceCache:     2,
+		PricingSourceDefault:   3,
+		PricingSourceEstimated: 4,
+	}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,50 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readPriceList returns a price list document of testdata, in the format GetProducts returns
+func readPriceList(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestExtractOnDemandPrice(t *testing.T) {
+	tests := []struct {
+		document  string
+		wantPrice float64
+		wantErr   string
+	}{
+		{document: "ec2-m5.large-linux.json", wantPrice: 0.096},
+		{document: "ebs-gp3-storage.json", wantPrice: 0.08},
+		{document: "truncated.json", wantErr: "error parsing pricing data"},
+		{document: "no-terms.json", wantErr: "terms field not found"},
+		{document: "reserved-only.json", wantErr: "OnDemand field not found"},
+		{document: "no-price-dimensions.json", wantErr: "no price dimension found"},
+		{document: "no-usd-price.json", wantErr: "USD price not found"},
+		{document: "invalid-usd-price.json", wantErr: "error parsing price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.document, func(t *testing.T) {
+			price, err := ExtractOnDemandPrice(readPriceList(t, tt.document))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ExtractOnDemandPrice() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || price != tt.wantPrice {
+				t.Errorf("ExtractOnDemandPrice() = %v, %v, want %v", price, err, tt.wantPrice)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/younsl/idled/internal/progress"
+)
+
+// The package-level functions below delegate to the default pricing service. See the
+// PricingService methods of the same name.
+
+// SetRegion sets the preferred Pricing API endpoint region of the default service
+func SetRegion(region string) error {
+	return defaultService.SetRegion(region)
+}
+
+// DisableAPI disables the Pricing API of the default service
+func DisableAPI() {
+	defaultService.DisableAPI()
+}
+
+// APIDisabled reports whether the Pricing API of the default service was disabled
+func APIDisabled() bool {
+	return defaultService.APIDisabled()
+}
+
+// SetAPITimeout sets the timeout of each Pricing API call of the default service
+func SetAPITimeout(timeout time.Duration) {
+	defaultService.SetAPITimeout(timeout)
+}
+
+// SetConfigOptions sets extra options of the AWS config of the default service's Pricing API client
+func SetConfigOptions(optFns ...func(*config.LoadOptions) error) {
+	defaultService.SetConfigOptions(optFns...)
+}
+
+// GetInitMessage returns the initialization message of the default service and clears it
+func GetInitMessage() string {
+	return defaultService.GetInitMessage()
+}
+
+// SetProgress sets the progress function of the default service
+func SetProgress(f progress.Func) {
+	defaultService.SetProgress(f)
+}
+
+// GetPriceFromAPI retrieves the first price list entry matching the filters with the default service
+func GetPriceFromAPI(ctx context.Context, serviceCode string, filters []types.Filter, service, resourceType, region string) (string, error) {
+	return defaultService.GetPriceFromAPI(ctx, serviceCode, filters, service, resourceType, region)
+}
+
+// GetPricingProducts retrieves the price list entries matching the filters with the default service
+func GetPricingProducts(ctx context.Context, serviceCode string, filters []types.Filter, service, resourceType, region string) ([]string, error) {
+	return defaultService.GetPricingProducts(ctx, serviceCode, filters, service, resourceType, region)
+}
+
+// UpdateCacheHitStats updates the stats of the default service when a cache hit occurs
+func UpdateCacheHitStats(service, region, cacheKey string) {
+	defaultService.UpdateCacheHitStats(service, region, cacheKey)
+}
+
+// UpdateAPISuccessStats updates the stats of the default service when an API call succeeds
+func UpdateAPISuccessStats(service, region string) {
+	defaultService.UpdateAPISuccessStats(service, region)
+}
+
+// UpdateAPIFailureStats updates the stats of the default service when an API call fails
+func UpdateAPIFailureStats(service, region string) {
+	defaultService.UpdateAPIFailureStats(service, region)
+}
+
+// GetAPIStats returns a copy of the pricing API statistics of the default service
+func GetAPIStats() map[string]map[string]map[string]int {
+	return defaultService.GetAPIStats()
+}
+
+// LoadDiskCache loads the disk cache into the default service
+func LoadDiskCache(path string, ttl time.Duration) (int, error) {
+	return defaultService.LoadDiskCache(path, ttl)
+}
+
+// SaveDiskCache writes the prices cached by the default service to the disk cache
+func SaveDiskCache(path string) error {
+	return defaultService.SaveDiskCache(path)
+}
+
+// GetInstanceHourlyPriceWithSource returns the hourly price for an EC2 instance and the source of the pricing
+func GetInstanceHourlyPriceWithSource(instanceType, region, operatingSystem string) (float64, string) {
+	return defaultService.GetInstanceHourlyPriceWithSource(instanceType, region, operatingSystem)
+}
+
+// GetInstanceHourlyPrice returns the hourly price for an EC2 instance
+func GetInstanceHourlyPrice(instanceType, region, operatingSystem string) float64 {
+	return defaultService.GetInstanceHourlyPrice(instanceType, region, operatingSystem)
+}
+
+// CalculateMonthlyCostWithSource returns the estimated monthly cost for an instance and the source of the pricing
+func CalculateMonthlyCostWithSource(instanceType, region, operatingSystem string) (float64, string) {
+	return defaultService.CalculateMonthlyCostWithSource(instanceType, region, operatingSystem)
+}
+
+// CalculateMonthlyCost returns the estimated monthly cost for an instance
+func CalculateMonthlyCost(instanceType, region, operatingSystem string) float64 {
+	return defaultService.CalculateMonthlyCost(instanceType, region, operatingSystem)
+}
+
+// CalculateSavingsWithSource returns the estimated savings since the instance was stopped and the source of the pricing
+func CalculateSavingsWithSource(instanceType, region, operatingSystem string, elapsedDays int) (float64, string) {
+	return defaultService.CalculateSavingsWithSource(instanceType, region, operatingSystem, elapsedDays)
+}
+
+// CalculateSavings returns the estimated savings since the instance was stopped
+func CalculateSavings(instanceType, region, operatingSystem string, elapsedDays int) float64 {
+	return defaultService.CalculateSavings(instanceType, region, operatingSystem, elapsedDays)
+}
+
+// GetEBSVolumePrice returns the price per GB-month for a given EBS volume type and region
+func GetEBSVolumePrice(volumeType string, region string) float64 {
+	return defaultService.GetEBSVolumePrice(volumeType, region)
+}
+
+// CalculateEBSMonthlyCostWithSource calculates the monthly cost of an EBS volume and returns the pricing source
+func CalculateEBSMonthlyCostWithSource(volumeType string, sizeGB, iops, throughput int, region string) (float64, string) {
+	return defaultService.CalculateEBSMonthlyCostWithSource(volumeType, sizeGB, iops, throughput, region)
+}
+
+// CalculateEBSMonthlyCost calculates the monthly cost of an EBS volume
+func CalculateEBSMonthlyCost(volumeType string, sizeGB, iops, throughput int, region string) float64 {
+	return defaultService.CalculateEBSMonthlyCost(volumeType, sizeGB, iops, throughput, region)
+}
+
+// CalculateEBSSavings calculates the estimated savings from an unused EBS volume
+func CalculateEBSSavings(volumeType string, sizeGB, iops, throughput int, region string, days int) float64 {
+	return defaultService.CalculateEBSSavings(volumeType, sizeGB, iops, throughput, region, days)
+}
+
+// GetEIPMonthlyCost returns the monthly cost of an idle public IPv4 address and the pricing source
+func GetEIPMonthlyCost(region string) (float64, string) {
+	return defaultService.GetEIPMonthlyCost(region)
+}
+
+// CalculateELBMonthlyCostWithSource returns the monthly cost of a load balancer and the pricing source
+func CalculateELBMonthlyCostWithSource(lbType, region string) (float64, string) {
+	return defaultService.CalculateELBMonthlyCostWithSource(lbType, region)
+}
+
+// CalculateMSKMonthlyCostWithSource returns the monthly cost of the brokers of an MSK cluster and the pricing source
+func CalculateMSKMonthlyCostWithSource(instanceType string, brokerCount int, region string) (float64, string) {
+	return defaultService.CalculateMSKMonthlyCostWithSource(instanceType, brokerCount, region)
+}
+
+// CalculateSageMakerMonthlyCostWithSource returns the monthly cost of SageMaker ML instances and the pricing source
+func CalculateSageMakerMonthlyCostWithSource(component, instanceType string, instanceCount int, region string) (float64, string) {
+	return defaultService.CalculateSageMakerMonthlyCostWithSource(component, instanceType, instanceCount, region)
+}
+
+// CalculateS3MonthlyCostWithSource returns the monthly storage cost of an S3 bucket and the pricing source
+func CalculateS3MonthlyCostWithSource(sizesByStorageType map[string]int64, region string) (float64, string) {
+	return defaultService.CalculateS3MonthlyCostWithSource(sizesByStorageType, region)
+}
+
+// CalculateLogsStorageMonthlyCostWithSource returns the monthly storage cost of a log group and the pricing source
+func CalculateLogsStorageMonthlyCostWithSource(storedBytes int64, region string) (float64, string) {
+	return defaultService.CalculateLogsStorageMonthlyCostWithSource(storedBytes, region)
+}
+
+// GetLambdaPricing returns the Lambda prices of a region and architecture and the pricing source
+func GetLambdaPricing(region, architecture string) (LambdaPrices, string) {
+	return defaultService.GetLambdaPricing(region, architecture)
+}
+
+// GetWorkSpacesMonthlySavingsWithSource returns the monthly cost saved by removing an unused WorkSpace and the pricing source
+func GetWorkSpacesMonthlySavingsWithSource(computeType, runningMode string) (float64, string) {
+	return defaultService.GetWorkSpacesMonthlySavingsWithSource(computeType, runningMode)
+}
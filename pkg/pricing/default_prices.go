@@ -0,0 +1,67 @@
+package pricing
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed data/default-prices.json
+var defaultPricesJSON []byte
+
+// DefaultPriceTable is the bundled fallback price table, generated with
+// hack/generate-default-prices
+type DefaultPriceTable struct {
+	Version     int                           `json:"version"`
+	GeneratedAt string                        `json:"generatedAt"` // Date the table was generated (YYYY-MM-DD)
+	Source      string                        `json:"source"`      // How the prices were obtained
+	Estimated   bool                          `json:"estimated"`   // Whether the prices are estimates rather than published list prices
+	EC2         map[string]map[string]float64 `json:"ec2"`         // Region -> instance type -> Linux on-demand USD per hour
+	EBS         map[string]map[string]float64 `json:"ebs"`         // Region -> volume type -> USD per GB-month
+}
+
+// DefaultEC2Prices holds the bundled Linux on-demand prices in USD per hour by region and instance type
+var DefaultEC2Prices = map[string]map[string]float64{}
+
+// defaultTableSource is the pricing source of the prices from the bundled table, Estimated
+// unless the table was generated from the AWS Pricing API
+var defaultTableSource = PricingSourceDefault
+
+// tableEBSPrices holds the region and volume type of the EBS prices taken from the bundled table
+var tableEBSPrices = map[string]map[string]bool{}
+
+func init() {
+	var table DefaultPriceTable
+	if err := json.Unmarshal(defaultPricesJSON, &table); err != nil {
+		panic(fmt.Sprintf("invalid bundled default price table: %v", err))
+	}
+
+	DefaultEC2Prices = table.EC2
+	if table.Estimated {
+		defaultTableSource = PricingSourceEstimated
+	}
+
+	// Prices maintained in types.go take precedence over the bundled table
+	for region, prices := range table.EBS {
+		if _, found := DefaultEBSPrices[region]; !found {
+			DefaultEBSPrices[region] = make(map[string]float64)
+		}
+		for volumeType, price := range prices {
+			if _, found := DefaultEBSPrices[region][volumeType]; !found {
+				DefaultEBSPrices[region][volumeType] = price
+				if tableEBSPrices[region] == nil {
+					tableEBSPrices[region] = make(map[string]bool)
+				}
+				tableEBSPrices[region][volumeType] = true
+			}
+		}
+	}
+}
+
+// defaultEBSSource returns the pricing source of the default EBS price of a region and volume type
+func defaultEBSSource(region, volumeType string) PricingSource {
+	if tableEBSPrices[region][volumeType] {
+		return defaultTableSource
+	}
+	return PricingSourceDefault
+}
@@ -0,0 +1,82 @@
+package pricing
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// withDefaultTableSource sets the source of the bundled table prices until the test ends
+func withDefaultTableSource(t *testing.T, source PricingSource) {
+	t.Helper()
+	saved := defaultTableSource
+	t.Cleanup(func() { defaultTableSource = saved })
+	defaultTableSource = source
+}
+
+func TestBundledTableSource(t *testing.T) {
+	var table DefaultPriceTable
+	if err := json.Unmarshal(defaultPricesJSON, &table); err != nil {
+		t.Fatalf("invalid bundled table: %v", err)
+	}
+
+	want := PricingSourceDefault
+	if table.Estimated {
+		want = PricingSourceEstimated
+	}
+	if defaultTableSource != want {
+		t.Errorf("defaultTableSource = %s, want %s for estimated = %t", defaultTableSource, want, table.Estimated)
+	}
+}
+
+func TestFallbackPricesReportEstimatedSource(t *testing.T) {
+	withDefaultTableSource(t, PricingSourceEstimated)
+	service := NewPricingService()
+	service.DisableAPI()
+
+	tests := []struct {
+		name       string
+		price      func() (float64, string)
+		wantSource PricingSource
+	}{
+		{
+			name: "EC2 price from the table",
+			price: func() (float64, string) {
+				return service.GetInstanceHourlyPriceWithSource("m5.large", "eu-west-1", EC2OperatingSystemLinux)
+			},
+			wantSource: PricingSourceEstimated,
+		},
+		{
+			name: "EBS price from the table",
+			price: func() (float64, string) {
+				return service.CalculateEBSMonthlyCostWithSource("gp3", 100, 0, 0, "eu-west-1")
+			},
+			wantSource: PricingSourceEstimated,
+		},
+		{
+			name: "EBS price maintained in the code",
+			price: func() (float64, string) {
+				return service.CalculateEBSMonthlyCostWithSource("gp3", 100, 0, 0, "us-east-1")
+			},
+			wantSource: PricingSourceDefault,
+		},
+		{
+			name: "EC2 price not in the table",
+			price: func() (float64, string) {
+				return service.GetInstanceHourlyPriceWithSource("m5.large", "eu-west-1", EC2OperatingSystemWindows)
+			},
+			wantSource: PricingSourceNA,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, source := tt.price()
+			if source != string(tt.wantSource) {
+				t.Errorf("source = %s, want %s", source, tt.wantSource)
+			}
+			if tt.wantSource != PricingSourceNA && price <= 0 {
+				t.Errorf("price = %v, want a positive price", price)
+			}
+		})
+	}
+}
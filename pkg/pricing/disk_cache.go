@@ -0,0 +1,146 @@
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DiskCacheVersion is the version of the disk cache format. Files of another version are
+// ignored and rebuilt.
+const DiskCacheVersion = 1
+
+// DefaultDiskCacheTTL is how long prices saved to the disk cache are reused
+const DefaultDiskCacheTTL = 7 * 24 * time.Hour
+
+// diskCacheFile is the on-disk form of the pricing caches
+type diskCacheFile struct {
+	Version int                       `json:"version"`
+	Entries map[string]diskCacheEntry `json:"entries"` // Keyed by service:cache key (e.g., EBS:ebs:gp3:us-east-1)
+}
+
+// diskCacheEntry is a price retrieved from the AWS Pricing API
+type diskCacheEntry struct {
+	Price     float64   `json:"price"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// DefaultDiskCachePath returns the disk cache file, ~/.idled/pricing-cache.json
+func DefaultDiskCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".idled", "pricing-cache.json"), nil
+}
+
+// LoadDiskCache loads the prices of the disk cache retrieved within the TTL into the
+// in-memory caches and returns how many were loaded. A missing file loads nothing. A file
+// that cannot be parsed returns an error and is overwritten by the next SaveDiskCache.
+func (s *PricingService) LoadDiskCache(path string, ttl time.Duration) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read pricing cache: %w", err)
+	}
+
+	var file diskCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return 0, fmt.Errorf("failed to parse pricing cache %s: %w", path, err)
+	}
+	if file.Version != DiskCacheVersion {
+		return 0, fmt.Errorf("unsupported pricing cache version %d in %s", file.Version, path)
+	}
+
+	s.diskMu.Lock()
+	defer s.diskMu.Unlock()
+
+	loaded := 0
+	for diskKey, entry := range file.Entries {
+		if time.Since(entry.FetchedAt) > ttl {
+			continue
+		}
+		service, cacheKey, found := strings.Cut(diskKey, ":")
+		if !found {
+			continue
+		}
+		cache, found := s.caches[service]
+		if !found {
+			continue
+		}
+
+		cache.mu.Lock()
+		cache.entries[cacheKey] = entry.Price
+		cache.mu.Unlock()
+
+		s.diskFetchedAt[diskKey] = entry.FetchedAt
+		loaded++
+	}
+
+	return loaded, nil
+}
+
+// SaveDiskCache writes every cached price to the disk cache. Prices loaded from disk keep
+// their retrieval time, so they expire with the TTL of the run that retrieved them. The file
+// is written to a temporary file first and renamed, so concurrent runs never read a partial file.
+func (s *PricingService) SaveDiskCache(path string) error {
+	file := diskCacheFile{Version: DiskCacheVersion, Entries: make(map[string]diskCacheEntry)}
+	now := time.Now().UTC()
+
+	s.diskMu.RLock()
+	for service, cache := range s.caches {
+		cache.mu.RLock()
+		for cacheKey, price := range cache.entries {
+			diskKey := service + ":" + cacheKey
+			fetchedAt, found := s.diskFetchedAt[diskKey]
+			if !found {
+				fetchedAt = now
+			}
+			file.Entries[diskKey] = diskCacheEntry{Price: price, FetchedAt: fetchedAt}
+		}
+		cache.mu.RUnlock()
+	}
+	s.diskMu.RUnlock()
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pricing cache: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create pricing cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".pricing-cache-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write pricing cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write pricing cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write pricing cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write pricing cache: %w", err)
+	}
+	return nil
+}
+
+// isDiskCacheHit reports whether a cached price of a service was loaded from the disk cache
+func (s *PricingService) isDiskCacheHit(service, cacheKey string) bool {
+	s.diskMu.RLock()
+	defer s.diskMu.RUnlock()
+
+	_, found := s.diskFetchedAt[service+":"+cacheKey]
+	return found
+}
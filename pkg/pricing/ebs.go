@@ -4,82 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
 )
 
 // GetEBSVolumePrice returns the price per GB-month for a given EBS volume type and region
-func GetEBSVolumePrice(volumeType string, region string) float64 {
-	// Initialize pricing client if not already done
-	PricingInitOnce.Do(InitPricingClient)
-
-	// Generate cache key
-	cacheKey := fmt.Sprintf("ebs:%s:%s", volumeType, region)
-
-	// Check cache first
-	EBSPricingCacheLock.RLock()
-	if price, found := EBSPricingCache[cacheKey]; found {
-		EBSPricingCacheLock.RUnlock()
-
-		// Update cache hit stats
-		UpdateCacheHitStats("EBS", region)
-
-		return price
-	}
-	EBSPricingCacheLock.RUnlock()
-
-	var price float64
-	var err error
-
-	// If pricing client is available, try to get price from AWS API
-	if PricingClient != nil {
-		price, err = getEBSPriceFromAPI(volumeType, region)
-	} else {
-		err = fmt.Errorf("pricing client not initialized")
-	}
-
-	// If API call failed, use fallback pricing
-	if err != nil {
-		log.Printf("Error getting EBS price from API: %v. Using fallback pricing for %s in %s.", err, volumeType, region)
-
-		// Update failure stats
-		UpdateAPIFailureStats("EBS", region)
-
-		// Fall back to default prices
-		if regionPrices, found := DefaultEBSPrices[region]; found {
-			if typePrice, found := regionPrices[volumeType]; found {
-				price = typePrice
-			} else {
-				// Default to gp2 price if type not found
-				price = regionPrices["gp2"]
-			}
-		} else {
-			// If region not found, use us-east-1 prices
-			price = DefaultEBSPrices["us-east-1"][volumeType]
-			if price == 0 {
-				price = DefaultEBSPrices["us-east-1"]["gp2"] // Default fallback
-			}
-		}
-	} else {
-		// Update success stats
-		UpdateAPISuccessStats("EBS", region)
-	}
-
-	// Cache the result
-	EBSPricingCacheLock.Lock()
-	EBSPricingCache[cacheKey] = price
-	EBSPricingCacheLock.Unlock()
-
+func (s *PricingService) GetEBSVolumePrice(volumeType string, region string) float64 {
+	price, _ := s.getEBSStoragePriceWithSource(volumeType, region)
 	return price
 }
 
 // getEBSPriceFromAPI retrieves EBS volume pricing from the AWS Pricing API
-func getEBSPriceFromAPI(volumeType, region string) (float64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (s *PricingService) getEBSPriceFromAPI(volumeType, region string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
 	defer cancel()
 
 	// Map volume type to API value
@@ -110,7 +50,7 @@ func getEBSPriceFromAPI(volumeType, region string) (float64, error) {
 	}
 
 	// Get multiple products to find exact match
-	pricingProducts, err := GetPricingProducts(ctx, "AmazonEC2", filters, "EBS", volumeType, region)
+	pricingProducts, err := s.GetPricingProducts(ctx, "AmazonEC2", filters, "EBS", volumeType, region)
 	if err != nil {
 		return 0, err
 	}
@@ -244,79 +184,68 @@ func extractEBSPrice(matchedProduct string) (float64, error) {
 	return price, nil
 }
 
-// CalculateEBSMonthlyCostWithSource calculates the monthly cost of an EBS volume and returns the pricing source
-func CalculateEBSMonthlyCostWithSource(volumeType string, sizeGB int, region string) (float64, string) {
-	// Initialize pricing client if not already done
-	PricingInitOnce.Do(InitPricingClient)
-
-	// Generate cache key
-	cacheKey := fmt.Sprintf("ebs:%s:%s", volumeType, region)
-
-	// Check cache first
-	EBSPricingCacheLock.RLock()
-	if price, found := EBSPricingCache[cacheKey]; found {
-		EBSPricingCacheLock.RUnlock()
-
-		// Update cache hit stats
-		UpdateCacheHitStats("EBS", region)
-
-		return float64(sizeGB) * price, string(PricingSourceCache)
+// CalculateEBSMonthlyCostWithSource calculates the monthly cost of an EBS volume and returns the pricing source.
+// Besides storage, the cost includes the provisioned IOPS of io1/io2 volumes and the IOPS and
+// throughput (MB/s) of gp3 volumes above the baseline included in the storage price. The
+// source is "Default" or "Estimated" if any component fell back to default pricing.
+func (s *PricingService) CalculateEBSMonthlyCostWithSource(volumeType string, sizeGB, iops, throughput int, region string) (float64, string) {
+	storagePrice, source := s.getEBSStoragePriceWithSource(volumeType, region)
+	if source == PricingSourceNA {
+		return 0, string(PricingSourceNA)
 	}
-	EBSPricingCacheLock.RUnlock()
+	cost := float64(sizeGB) * storagePrice
 
-	// Try to get price from AWS API
-	if PricingClient != nil {
-		price, err := getEBSPriceFromAPI(volumeType, region)
-		if err == nil {
-			// Update success stats
-			UpdateAPISuccessStats("EBS", region)
-
-			// Cache the result
-			EBSPricingCacheLock.Lock()
-			EBSPricingCache[cacheKey] = price
-			EBSPricingCacheLock.Unlock()
+	components := map[string]int{
+		ebsComponentIOPS:       ebsBillableIOPS(volumeType, iops),
+		ebsComponentThroughput: ebsBillableThroughput(volumeType, throughput),
+	}
+	for component, units := range components {
+		if units == 0 {
+			continue
+		}
 
-			return float64(sizeGB) * price, string(PricingSourceAPI)
+		price, componentSource := s.getEBSPerformancePriceWithSource(component, volumeType, region)
+		if componentSource == PricingSourceNA {
+			return 0, string(PricingSourceNA)
 		}
 
-		// Log the error but continue to use fallback pricing
-		log.Printf("Error getting EBS price from API: %v for %s in %s.", err, volumeType, region)
+		cost += float64(units) * price
+		source = combinePricingSources(source, componentSource)
 	}
 
-	// Update failure stats
-	UpdateAPIFailureStats("EBS", region)
-
-	// Use fallback pricing instead of returning N/A
-	if regionPrices, found := DefaultEBSPrices[region]; found {
-		if typePrice, found := regionPrices[volumeType]; found {
-			return float64(sizeGB) * typePrice, string(PricingSourceDefault)
-		} else if typePrice, found := regionPrices["gp2"]; found {
-			// Default to gp2 price if type not found
-			return float64(sizeGB) * typePrice, string(PricingSourceDefault)
-		}
-	} else if regionPrices, found := DefaultEBSPrices["us-east-1"]; found {
-		// If region not found, use us-east-1 prices
-		if typePrice, found := regionPrices[volumeType]; found {
-			return float64(sizeGB) * typePrice, string(PricingSourceDefault)
-		} else if typePrice, found := regionPrices["gp2"]; found {
-			return float64(sizeGB) * typePrice, string(PricingSourceDefault)
-		}
-	}
+	return cost, string(source)
+}
 
-	// Only return N/A if all fallbacks fail
-	return 0, string(PricingSourceNA)
+// getEBSStoragePriceWithSource returns the storage price per GB-month of a volume type and region
+func (s *PricingService) getEBSStoragePriceWithSource(volumeType, region string) (float64, PricingSource) {
+	return s.lookupPrice("EBS", region, fmt.Sprintf("ebs:%s:%s", volumeType, region),
+		func() (float64, error) { return s.getEBSPriceFromAPI(volumeType, region) },
+		func() (float64, PricingSource) {
+			priceRegion := region
+			if _, found := DefaultEBSPrices[priceRegion]; !found {
+				priceRegion = "us-east-1"
+			}
+			// Volume types without a default price are priced as gp2
+			for _, priceType := range []string{volumeType, "gp2"} {
+				if price, found := DefaultEBSPrices[priceRegion][priceType]; found {
+					return price, defaultEBSSource(priceRegion, priceType)
+				}
+			}
+			return 0, PricingSourceNA
+		},
+		"volumeType", volumeType)
 }
 
 // CalculateEBSMonthlyCost is a wrapper around CalculateEBSMonthlyCostWithSource
 // that returns only the cost for backward compatibility
-func CalculateEBSMonthlyCost(volumeType string, sizeGB int, region string) float64 {
-	cost, _ := CalculateEBSMonthlyCostWithSource(volumeType, sizeGB, region)
+func (s *PricingService) CalculateEBSMonthlyCost(volumeType string, sizeGB, iops, throughput int, region string) float64 {
+	cost, _ := s.CalculateEBSMonthlyCostWithSource(volumeType, sizeGB, iops, throughput, region)
 	return cost
 }
 
 // CalculateEBSSavings calculates the estimated savings from an unused EBS volume
-func CalculateEBSSavings(volumeType string, sizeGB int, region string, days int) float64 {
-	monthlyCost, source := CalculateEBSMonthlyCostWithSource(volumeType, sizeGB, region)
+func (s *PricingService) CalculateEBSSavings(volumeType string, sizeGB, iops, throughput int, region string, days int) float64 {
+	monthlyCost, source := s.CalculateEBSMonthlyCostWithSource(volumeType, sizeGB, iops, throughput, region)
 
 	// If we couldn't get a price, return 0
 	if source == string(PricingSourceNA) {
@@ -326,3 +255,114 @@ func CalculateEBSSavings(volumeType string, sizeGB int, region string, days int)
 	// Simply return monthly cost (ignore elapsed days)
 	return monthlyCost
 }
+
+// EBS performance price components
+const (
+	ebsComponentIOPS       = "iops"
+	ebsComponentThroughput = "throughput"
+)
+
+// Performance included in the storage price of gp3 volumes
+const (
+	EBSGP3BaselineIOPS       = 3000 // IOPS
+	EBSGP3BaselineThroughput = 125  // MB/s
+)
+
+// ebsPerformanceProductFamilies maps an EBS performance price component to its AmazonEC2
+// product family
+var ebsPerformanceProductFamilies = map[string]string{
+	ebsComponentIOPS:       "System Operation",
+	ebsComponentThroughput: "Provisioned Throughput",
+}
+
+// ebsBillableIOPS returns the IOPS billed on top of the storage of a volume: every provisioned
+// IOPS of io1/io2 volumes and the IOPS of gp3 volumes above the baseline
+func ebsBillableIOPS(volumeType string, iops int) int {
+	switch volumeType {
+	case "io1", "io2":
+		return iops
+	case "gp3":
+		return max(iops-EBSGP3BaselineIOPS, 0)
+	default:
+		return 0
+	}
+}
+
+// ebsBillableThroughput returns the throughput (MB/s) billed on top of the storage of a
+// volume, which only gp3 volumes above the baseline have
+func ebsBillableThroughput(volumeType string, throughput int) int {
+	if volumeType != "gp3" {
+		return 0
+	}
+	return max(throughput-EBSGP3BaselineThroughput, 0)
+}
+
+// getEBSPerformancePriceWithSource returns the price per provisioned IOPS-month or
+// MB/s-month of a volume type and region
+func (s *PricingService) getEBSPerformancePriceWithSource(component, volumeType, region string) (float64, PricingSource) {
+	return s.lookupPrice("EBS", region, fmt.Sprintf("ebs-%s:%s:%s", component, volumeType, region),
+		func() (float64, error) { return s.getEBSPerformancePriceFromAPI(component, volumeType, region) },
+		func() (float64, PricingSource) {
+			regionPrices, _ := defaultPrices(DefaultEBSPerformancePrices, region)
+			if price, found := regionPrices[volumeType+"-"+component]; found {
+				return price, PricingSourceDefault
+			}
+			return 0, PricingSourceNA
+		},
+		"component", component, "volumeType", volumeType)
+}
+
+// getEBSPerformancePriceFromAPI retrieves the provisioned IOPS or throughput price of a volume
+// type from the AWS Pricing API. io2 IOPS above 32,000 are sold as cheaper tiers in separate
+// products, so the highest price is the one of the first tier.
+func (s *PricingService) getEBSPerformancePriceFromAPI(component, volumeType, region string) (float64, error) {
+	productFamily, found := ebsPerformanceProductFamilies[component]
+	if !found {
+		return 0, fmt.Errorf("unsupported EBS price component: %s", component)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String(productFamily),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("volumeApiName"),
+			Value: aws.String(volumeType),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	pricingProducts, err := s.GetPricingProducts(ctx, "AmazonEC2", filters, "EBS", volumeType+" "+component, region)
+	if err != nil {
+		return 0, err
+	}
+
+	var price float64
+	for _, product := range pricingProducts {
+		productPrice, unit, err := extractFirstTierPriceAndUnit(product)
+		if err != nil {
+			continue
+		}
+
+		// Throughput is priced per GiBps-month in the price list, but provisioned per MB/s
+		if strings.HasPrefix(unit, "GiBps") {
+			productPrice /= 1024
+		}
+		price = max(price, productPrice)
+	}
+
+	if price == 0 {
+		return 0, fmt.Errorf("no %s price found for EBS volume type %s in region %s", component, volumeType, region)
+	}
+	return price, nil
+}
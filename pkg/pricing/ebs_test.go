@@ -0,0 +1,76 @@
+package pricing
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestExtractEBSPrice(t *testing.T) {
+	tests := []struct {
+		document  string
+		wantPrice float64
+		wantErr   string
+	}{
+		{document: "ebs-gp3-storage.json", wantPrice: 0.08},
+		{document: "ebs-io2-iops.json", wantErr: "unexpected pricing unit: IOPS-Mo"},
+		{document: "ec2-m5.large-linux.json", wantErr: "unexpected pricing unit: Hrs"},
+		{document: "truncated.json", wantErr: "error parsing pricing data"},
+		{document: "no-terms.json", wantErr: "terms field not found"},
+		{document: "reserved-only.json", wantErr: "OnDemand field not found"},
+		{document: "no-price-dimensions.json", wantErr: "no price dimension found"},
+		{document: "no-usd-price.json", wantErr: "USD price not found"},
+		{document: "invalid-usd-price.json", wantErr: "error parsing price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.document, func(t *testing.T) {
+			price, err := extractEBSPrice(readPriceList(t, tt.document))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("extractEBSPrice() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || price != tt.wantPrice {
+				t.Errorf("extractEBSPrice() = %v, %v, want %v", price, err, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestCalculateEBSMonthlyCostWithSource(t *testing.T) {
+	service := NewPricingService()
+	service.DisableAPI()
+
+	tests := []struct {
+		name       string
+		volumeType string
+		sizeGB     int
+		iops       int
+		throughput int
+		region     string
+		want       float64
+	}{
+		{name: "gp3 at the baseline", volumeType: "gp3", sizeGB: 100, iops: 3000, throughput: 125, region: "us-east-1", want: 8},
+		{name: "gp3 one IOPS above the baseline", volumeType: "gp3", sizeGB: 100, iops: 3001, throughput: 125, region: "us-east-1", want: 8.005},
+		// 3,000 IOPS at $0.005 and 125 MB/s at $0.04 above the baseline
+		{name: "gp3 above the baseline", volumeType: "gp3", sizeGB: 100, iops: 6000, throughput: 250, region: "us-east-1", want: 28},
+		{name: "gp3 throughput above the baseline", volumeType: "gp3", sizeGB: 100, iops: 3000, throughput: 1000, region: "us-east-1", want: 43},
+		{name: "gp3 in Seoul", volumeType: "gp3", sizeGB: 100, iops: 4000, throughput: 200, region: "ap-northeast-2", want: 18.32},
+		// Every provisioned IOPS of io1/io2 is billed, at $0.065
+		{name: "io1", volumeType: "io1", sizeGB: 100, iops: 1000, region: "us-east-1", want: 77.5},
+		{name: "io2", volumeType: "io2", sizeGB: 100, iops: 5000, region: "us-east-1", want: 337.5},
+		{name: "io2 throughput is not billed", volumeType: "io2", sizeGB: 100, iops: 5000, throughput: 500, region: "us-east-1", want: 337.5},
+		{name: "gp2 IOPS are not billed", volumeType: "gp2", sizeGB: 100, iops: 300, region: "us-east-1", want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, source := service.CalculateEBSMonthlyCostWithSource(tt.volumeType, tt.sizeGB, tt.iops, tt.throughput, tt.region)
+			if math.Abs(cost-tt.want) > 1e-9 || source != string(PricingSourceDefault) {
+				t.Errorf("CalculateEBSMonthlyCostWithSource() = %v, %s, want %v, %s", cost, source, tt.want, PricingSourceDefault)
+			}
+		})
+	}
+}
@@ -3,71 +3,69 @@ package pricing
 import (
 	"context"
 	"fmt"
-	"log"
-	"time"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
 )
 
-// GetInstanceHourlyPriceWithSource returns the hourly price for an EC2 instance and the source of the pricing
-func GetInstanceHourlyPriceWithSource(instanceType, region string) (float64, string) {
-	// Initialize pricing client if not already done
-	PricingInitOnce.Do(InitPricingClient)
-
-	// Generate cache key
-	cacheKey := fmt.Sprintf("%s:%s", region, instanceType)
-
-	// Check cache first
-	EC2PricingCacheLock.RLock()
-	if price, exists := EC2PricingCache[cacheKey]; exists {
-		EC2PricingCacheLock.RUnlock()
-
-		// Update cache hit stats
-		UpdateCacheHitStats("EC2", region)
-
-		return price, string(PricingSourceCache)
-	}
-	EC2PricingCacheLock.RUnlock()
-
-	// Try to get pricing from AWS API only if the client is available
-	if PricingClient != nil {
-		price, err := getEC2PriceFromAPI(instanceType, region)
-		if err == nil {
-			// Update success stats
-			UpdateAPISuccessStats("EC2", region)
-
-			// Cache the result
-			EC2PricingCacheLock.Lock()
-			EC2PricingCache[cacheKey] = price
-			EC2PricingCacheLock.Unlock()
-
-			return price, string(PricingSourceAPI)
-		}
+// EC2 operating systems as named by the operatingSystem attribute of the AWS Pricing API
+const (
+	EC2OperatingSystemLinux   = "Linux"
+	EC2OperatingSystemWindows = "Windows"
+	EC2OperatingSystemRHEL    = "RHEL"
+	EC2OperatingSystemSUSE    = "SUSE"
+)
 
-		// Log the error but return N/A
-		log.Printf("Error getting price from API: %v for %s in %s.", err, instanceType, region)
+// EC2OperatingSystem maps the PlatformDetails of an EC2 instance (e.g., "Linux/UNIX",
+// "Windows with SQL Server Standard", "Red Hat Enterprise Linux") to the operatingSystem
+// attribute of the AWS Pricing API. Unknown platform details map to Linux and are reported
+// as not known. Pre-installed software such as SQL Server is not priced.
+func EC2OperatingSystem(platformDetails string) (string, bool) {
+	switch {
+	case platformDetails == "Linux/UNIX":
+		return EC2OperatingSystemLinux, true
+	case strings.HasPrefix(platformDetails, "Windows"):
+		return EC2OperatingSystemWindows, true
+	case strings.HasPrefix(platformDetails, "Red Hat"):
+		return EC2OperatingSystemRHEL, true
+	case strings.HasPrefix(platformDetails, "SUSE"):
+		return EC2OperatingSystemSUSE, true
+	default:
+		return EC2OperatingSystemLinux, false
 	}
+}
 
-	// Update failure stats
-	UpdateAPIFailureStats("EC2", region)
+// GetInstanceHourlyPriceWithSource returns the hourly price for an EC2 instance running an
+// operating system and the source of the pricing
+func (s *PricingService) GetInstanceHourlyPriceWithSource(instanceType, region, operatingSystem string) (float64, string) {
+	price, source := s.lookupPrice("EC2", region, fmt.Sprintf("%s:%s:%s", region, instanceType, operatingSystem),
+		func() (float64, error) { return s.getEC2PriceFromAPI(instanceType, region, operatingSystem) },
+		func() (float64, PricingSource) {
+			// The bundled prices only cover Linux
+			if price, found := DefaultEC2Prices[region][instanceType]; found && operatingSystem == EC2OperatingSystemLinux {
+				return price, defaultTableSource
+			}
+			return 0, PricingSourceNA
+		},
+		"instanceType", instanceType, "operatingSystem", operatingSystem)
 
-	// Return 0 with N/A source, don't use fallback prices
-	return 0, string(PricingSourceNA)
+	return price, string(source)
 }
 
-// GetInstanceHourlyPrice returns the hourly price for an EC2 instance based on its type and region
-func GetInstanceHourlyPrice(instanceType, region string) float64 {
-	price, _ := GetInstanceHourlyPriceWithSource(instanceType, region)
+// GetInstanceHourlyPrice returns the hourly price for an EC2 instance based on its type, region,
+// and operating system
+func (s *PricingService) GetInstanceHourlyPrice(instanceType, region, operatingSystem string) float64 {
+	price, _ := s.GetInstanceHourlyPriceWithSource(instanceType, region, operatingSystem)
 	return price
 }
 
 // getEC2PriceFromAPI retrieves EC2 instance pricing from the AWS Pricing API
-func getEC2PriceFromAPI(instanceType, region string) (float64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (s *PricingService) getEC2PriceFromAPI(instanceType, region, operatingSystem string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
 	defer cancel()
 
-	// Construct filters for EC2 Linux on-demand instances
+	// Construct filters for EC2 on-demand instances without pre-installed software
 	filters := []types.Filter{
 		{
 			Type:  types.FilterTypeTermMatch,
@@ -82,7 +80,7 @@ func getEC2PriceFromAPI(instanceType, region string) (float64, error) {
 		{
 			Type:  types.FilterTypeTermMatch,
 			Field: aws.String("operatingSystem"),
-			Value: aws.String("Linux"),
+			Value: aws.String(operatingSystem),
 		},
 		{
 			Type:  types.FilterTypeTermMatch,
@@ -102,7 +100,7 @@ func getEC2PriceFromAPI(instanceType, region string) (float64, error) {
 	}
 
 	// Get pricing data from API
-	priceJSON, err := GetPriceFromAPI(ctx, "AmazonEC2", filters, "EC2", instanceType, region)
+	priceJSON, err := s.GetPriceFromAPI(ctx, "AmazonEC2", filters, "EC2", instanceType, region)
 	if err != nil {
 		return 0, err
 	}
@@ -112,8 +110,8 @@ func getEC2PriceFromAPI(instanceType, region string) (float64, error) {
 }
 
 // CalculateMonthlyCostWithSource returns the estimated monthly cost for an instance and the source of the pricing
-func CalculateMonthlyCostWithSource(instanceType, region string) (float64, string) {
-	hourlyPrice, source := GetInstanceHourlyPriceWithSource(instanceType, region)
+func (s *PricingService) CalculateMonthlyCostWithSource(instanceType, region, operatingSystem string) (float64, string) {
+	hourlyPrice, source := s.GetInstanceHourlyPriceWithSource(instanceType, region, operatingSystem)
 
 	// If we couldn't get a price, return 0 and N/A
 	if source == string(PricingSourceNA) {
@@ -125,14 +123,14 @@ func CalculateMonthlyCostWithSource(instanceType, region string) (float64, strin
 }
 
 // CalculateMonthlyCost returns the estimated monthly cost for an instance
-func CalculateMonthlyCost(instanceType, region string) float64 {
-	monthlyCost, _ := CalculateMonthlyCostWithSource(instanceType, region)
+func (s *PricingService) CalculateMonthlyCost(instanceType, region, operatingSystem string) float64 {
+	monthlyCost, _ := s.CalculateMonthlyCostWithSource(instanceType, region, operatingSystem)
 	return monthlyCost
 }
 
 // CalculateSavingsWithSource returns the estimated savings since the instance was stopped and the source of the pricing
-func CalculateSavingsWithSource(instanceType, region string, elapsedDays int) (float64, string) {
-	hourlyPrice, source := GetInstanceHourlyPriceWithSource(instanceType, region)
+func (s *PricingService) CalculateSavingsWithSource(instanceType, region, operatingSystem string, elapsedDays int) (float64, string) {
+	hourlyPrice, source := s.GetInstanceHourlyPriceWithSource(instanceType, region, operatingSystem)
 
 	// If we couldn't get a price, return 0 and N/A
 	if source == string(PricingSourceNA) {
@@ -147,7 +145,7 @@ func CalculateSavingsWithSource(instanceType, region string, elapsedDays int) (f
 }
 
 // CalculateSavings returns the estimated savings since the instance was stopped
-func CalculateSavings(instanceType, region string, elapsedDays int) float64 {
-	savings, _ := CalculateSavingsWithSource(instanceType, region, elapsedDays)
+func (s *PricingService) CalculateSavings(instanceType, region, operatingSystem string, elapsedDays int) float64 {
+	savings, _ := s.CalculateSavingsWithSource(instanceType, region, operatingSystem, elapsedDays)
 	return savings
 }
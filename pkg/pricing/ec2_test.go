@@ -0,0 +1,106 @@
+package pricing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+)
+
+// osPriceList is a Pricing API stub answering EC2 lookups with the price list of m5.large,
+// priced by the operatingSystem filter of the lookup
+type osPriceList struct {
+	template string
+	prices   map[string]string
+	lookups  []string
+}
+
+func (f *osPriceList) GetProducts(ctx context.Context, params *pricing.GetProductsInput, optFns ...func(*pricing.Options)) (*pricing.GetProductsOutput, error) {
+	var operatingSystem string
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Field) == "operatingSystem" {
+			operatingSystem = aws.ToString(filter.Value)
+		}
+	}
+	f.lookups = append(f.lookups, operatingSystem)
+	priceList := strings.Replace(f.template, "0.0960000000", f.prices[operatingSystem], 1)
+	return &pricing.GetProductsOutput{PriceList: []string{priceList}}, nil
+}
+
+func TestEC2OperatingSystem(t *testing.T) {
+	tests := []struct {
+		platformDetails string
+		want            string
+		wantKnown       bool
+	}{
+		{platformDetails: "Linux/UNIX", want: EC2OperatingSystemLinux, wantKnown: true},
+		{platformDetails: "Windows", want: EC2OperatingSystemWindows, wantKnown: true},
+		{platformDetails: "Windows with SQL Server Standard", want: EC2OperatingSystemWindows, wantKnown: true},
+		{platformDetails: "Red Hat Enterprise Linux", want: EC2OperatingSystemRHEL, wantKnown: true},
+		{platformDetails: "Red Hat Enterprise Linux with HA", want: EC2OperatingSystemRHEL, wantKnown: true},
+		{platformDetails: "SUSE Linux", want: EC2OperatingSystemSUSE, wantKnown: true},
+		{platformDetails: "Ubuntu Pro", want: EC2OperatingSystemLinux},
+		{platformDetails: "", want: EC2OperatingSystemLinux},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platformDetails, func(t *testing.T) {
+			got, known := EC2OperatingSystem(tt.platformDetails)
+			if got != tt.want || known != tt.wantKnown {
+				t.Errorf("EC2OperatingSystem(%q) = %q, %v, want %q, %v", tt.platformDetails, got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestGetInstanceHourlyPriceCachesByOperatingSystem(t *testing.T) {
+	stub := &osPriceList{
+		template: readPriceList(t, "ec2-m5.large-linux.json"),
+		prices:   map[string]string{EC2OperatingSystemLinux: "0.0960000000", EC2OperatingSystemWindows: "0.1880000000"},
+	}
+	service := NewPricingService()
+	service.SetClient(stub)
+
+	// Windows and Linux instances of the same type and region are priced and cached apart
+	lookups := []struct {
+		operatingSystem string
+		wantPrice       float64
+		wantSource      PricingSource
+	}{
+		{operatingSystem: EC2OperatingSystemWindows, wantPrice: 0.188, wantSource: PricingSourceAPI},
+		{operatingSystem: EC2OperatingSystemLinux, wantPrice: 0.096, wantSource: PricingSourceAPI},
+		{operatingSystem: EC2OperatingSystemWindows, wantPrice: 0.188, wantSource: PricingSourceCache},
+		{operatingSystem: EC2OperatingSystemLinux, wantPrice: 0.096, wantSource: PricingSourceCache},
+	}
+	for _, lookup := range lookups {
+		price, source := service.GetInstanceHourlyPriceWithSource("m5.large", "us-east-1", lookup.operatingSystem)
+		if price != lookup.wantPrice || source != string(lookup.wantSource) {
+			t.Errorf("GetInstanceHourlyPriceWithSource(%s) = %v, %s, want %v, %s", lookup.operatingSystem, price, source,
+				lookup.wantPrice, lookup.wantSource)
+		}
+	}
+
+	if len(stub.lookups) != 2 || stub.lookups[0] != EC2OperatingSystemWindows || stub.lookups[1] != EC2OperatingSystemLinux {
+		t.Errorf("Pricing API lookups = %v, want Windows then Linux", stub.lookups)
+	}
+}
+
+func TestGetInstanceHourlyPriceFallbackIsLinuxOnly(t *testing.T) {
+	service := NewPricingService()
+	service.DisableAPI()
+
+	linuxPrice, linuxSource := service.GetInstanceHourlyPriceWithSource("m5.large", "us-east-1", EC2OperatingSystemLinux)
+	if linuxPrice <= 0 || linuxSource != string(defaultTableSource) {
+		t.Errorf("Linux price = %v, %s, want the bundled price", linuxPrice, linuxSource)
+	}
+
+	// The bundled prices are Linux prices, which would understate the other operating systems
+	for _, operatingSystem := range []string{EC2OperatingSystemWindows, EC2OperatingSystemRHEL, EC2OperatingSystemSUSE} {
+		price, source := service.GetInstanceHourlyPriceWithSource("m5.large", "us-east-1", operatingSystem)
+		if price != 0 || source != string(PricingSourceNA) {
+			t.Errorf("%s price = %v, %s, want N/A", operatingSystem, price, source)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// GetEIPMonthlyCost returns the monthly cost of a public IPv4 address (Elastic IP) in a region
+// and the source of the pricing
+func (s *PricingService) GetEIPMonthlyCost(region string) (float64, string) {
+	price, source := s.lookupPrice("EIP", region, fmt.Sprintf("eip:%s", region),
+		func() (float64, error) { return s.getEIPPriceFromAPI(region) },
+		func() (float64, PricingSource) {
+			price, _ := defaultPrices(DefaultEIPPrices, region)
+			return price, PricingSourceDefault
+		})
+
+	return price * utils.GetMonthlyHours(), string(source)
+}
+
+// getEIPPriceFromAPI retrieves the hourly price of an idle public IPv4 address from the AWS Pricing API
+func (s *PricingService) getEIPPriceFromAPI(region string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String("IP Address"),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	pricingProducts, err := s.GetPricingProducts(ctx, "AmazonVPC", filters, "EIP", "Public IPv4 Address", region)
+	if err != nil {
+		return 0, err
+	}
+
+	// Prefer the idle address usage type, which is what an unused EIP is billed as
+	for _, product := range pricingProducts {
+		var priceData map[string]interface{}
+		if err := json.Unmarshal([]byte(product), &priceData); err != nil {
+			continue
+		}
+
+		productAttrs, ok := priceData["product"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		attributes, ok := productAttrs["attributes"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if usageType, ok := attributes["usagetype"].(string); ok && strings.HasSuffix(usageType, "IdleAddress") {
+			return ExtractOnDemandPrice(product)
+		}
+	}
+
+	return 0, fmt.Errorf("no idle public IPv4 address price found in region %s", region)
+}
@@ -0,0 +1,46 @@
+package pricing
+
+import "github.com/younsl/idled/pkg/utils"
+
+// ElastiCache deployment types
+const (
+	ElastiCacheDeploymentServerless  = "Serverless"
+	ElastiCacheDeploymentProvisioned = "Provisioned"
+)
+
+// ElastiCacheServerlessStoragePrices holds the serverless data storage price per GB-hour by engine.
+// Prices are the us-east-1 on-demand rates; other regions may differ slightly.
+var ElastiCacheServerlessStoragePrices = map[string]float64{
+	"redis":     0.125,
+	"valkey":    0.084,
+	"memcached": 0.125,
+}
+
+// ElastiCacheServerlessMinimumGB holds the minimum billable data storage in GB by engine
+var ElastiCacheServerlessMinimumGB = map[string]float64{
+	"redis":     1.0,
+	"valkey":    0.1,
+	"memcached": 1.0,
+}
+
+// ElastiCacheValkeyDiscountPercent holds how much cheaper Valkey is than Redis OSS by deployment type
+var ElastiCacheValkeyDiscountPercent = map[string]float64{
+	ElastiCacheDeploymentServerless:  33.0,
+	ElastiCacheDeploymentProvisioned: 20.0,
+}
+
+// CalculateElastiCacheServerlessMonthlyCost calculates the monthly data storage cost of a
+// serverless cache from its average stored bytes, applying the engine's minimum billable storage
+func CalculateElastiCacheServerlessMonthlyCost(engine string, storedBytes float64) float64 {
+	pricePerGBHour, found := ElastiCacheServerlessStoragePrices[engine]
+	if !found {
+		return 0
+	}
+
+	storedGB := storedBytes / (1024 * 1024 * 1024)
+	if minimumGB := ElastiCacheServerlessMinimumGB[engine]; storedGB < minimumGB {
+		storedGB = minimumGB
+	}
+
+	return storedGB * pricePerGBHour * utils.GetMonthlyHours()
+}
@@ -0,0 +1,33 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateElastiCacheServerlessMonthlyCost(t *testing.T) {
+	const gb = 1024 * 1024 * 1024
+
+	tests := []struct {
+		name        string
+		engine      string
+		storedBytes float64
+		want        float64
+	}{
+		{name: "redis below the minimum", engine: "redis", storedBytes: 0.2 * gb, want: 1 * 0.125 * 730},
+		{name: "redis without data", engine: "redis", want: 1 * 0.125 * 730},
+		{name: "redis above the minimum", engine: "redis", storedBytes: 5 * gb, want: 5 * 0.125 * 730},
+		{name: "valkey below the minimum", engine: "valkey", storedBytes: 0.05 * gb, want: 0.1 * 0.084 * 730},
+		{name: "valkey above the minimum", engine: "valkey", storedBytes: 2.5 * gb, want: 2.5 * 0.084 * 730},
+		{name: "memcached", engine: "memcached", storedBytes: 3 * gb, want: 3 * 0.125 * 730},
+		{name: "unknown engine", engine: "dragonfly", storedBytes: 5 * gb, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateElastiCacheServerlessMonthlyCost(tt.engine, tt.storedBytes); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CalculateElastiCacheServerlessMonthlyCost(%q, %v) = %v, want %v", tt.engine, tt.storedBytes, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// elbProductFamilies maps the short load balancer type to its AWSELB product family
+var elbProductFamilies = map[string]string{
+	"ALB":  "Load Balancer-Application",
+	"NLB":  "Load Balancer-Network",
+	"GWLB": "Load Balancer-Gateway",
+	"CLB":  "Load Balancer",
+}
+
+// CalculateELBMonthlyCostWithSource calculates the base monthly cost of a load balancer
+// ("ALB", "NLB", "GWLB", or "CLB") from its LoadBalancer-hour price and returns the pricing source.
+// LCU/NLCU charges are left out since an idle load balancer consumes almost none.
+func (s *PricingService) CalculateELBMonthlyCostWithSource(lbType, region string) (float64, string) {
+	price, source := s.lookupPrice("ELB", region, fmt.Sprintf("elb:%s:%s", lbType, region),
+		func() (float64, error) { return s.getELBPriceFromAPI(lbType, region) },
+		func() (float64, PricingSource) {
+			regionPrices, _ := defaultPrices(DefaultELBPrices, region)
+			if price, found := regionPrices[lbType]; found {
+				return price, PricingSourceDefault
+			}
+			return 0, PricingSourceNA
+		},
+		"type", lbType)
+
+	return price * utils.GetMonthlyHours(), string(source)
+}
+
+// getELBPriceFromAPI retrieves the LoadBalancer-hour price from the AWS Pricing API
+func (s *PricingService) getELBPriceFromAPI(lbType, region string) (float64, error) {
+	productFamily, found := elbProductFamilies[lbType]
+	if !found {
+		return 0, fmt.Errorf("unsupported load balancer type: %s", lbType)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String(productFamily),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	pricingProducts, err := s.GetPricingProducts(ctx, "AWSELB", filters, "ELB", lbType, region)
+	if err != nil {
+		return 0, err
+	}
+
+	// The product family also lists LCU/NLCU usage, so match the hourly usage type
+	for _, product := range pricingProducts {
+		var priceData map[string]interface{}
+		if err := json.Unmarshal([]byte(product), &priceData); err != nil {
+			continue
+		}
+
+		productAttrs, ok := priceData["product"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		attributes, ok := productAttrs["attributes"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if usageType, ok := attributes["usagetype"].(string); ok && strings.HasSuffix(usageType, "LoadBalancerUsage") {
+			return ExtractOnDemandPrice(product)
+		}
+	}
+
+	return 0, fmt.Errorf("no LoadBalancer-hour price found for %s in region %s", lbType, region)
+}
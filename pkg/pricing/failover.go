@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// authorizationErrorCodes are the API error codes of requests an endpoint refused, e.g.,
+// because a service control policy denies the region
+var authorizationErrorCodes = map[string]bool{
+	"AccessDenied":                true,
+	"AccessDeniedException":       true,
+	"UnauthorizedOperation":       true,
+	"UnrecognizedClientException": true,
+}
+
+// failoverClient sends price lookups to the preferred Pricing API endpoint. If the first
+// lookup fails with a connectivity or authorization error, it is retried once against the
+// alternate endpoint, and whichever endpoint worked is used for the rest of the run.
+type failoverClient struct {
+	mu        sync.Mutex
+	decided   bool        // Whether the first lookup picked the endpoint
+	active    ProductsAPI // Endpoint used once decided
+	region    string      // Region of the preferred endpoint
+	client    ProductsAPI
+	altRegion string // Region of the alternate endpoint
+	altClient ProductsAPI
+	timeout   time.Duration // Timeout of the lookup retried against the alternate endpoint
+	notify    func(string)  // Receives a message when the client fails over
+}
+
+// newFailoverClient creates a client preferring the endpoint of region over altRegion
+func newFailoverClient(region string, client ProductsAPI, altRegion string, altClient ProductsAPI, timeout time.Duration, notify func(string)) *failoverClient {
+	return &failoverClient{
+		active:    client,
+		region:    region,
+		client:    client,
+		altRegion: altRegion,
+		altClient: altClient,
+		timeout:   timeout,
+		notify:    notify,
+	}
+}
+
+// GetProducts calls the endpoint in use. Concurrent first lookups wait for the first one to
+// pick the endpoint.
+func (c *failoverClient) GetProducts(ctx context.Context, params *pricing.GetProductsInput, optFns ...func(*pricing.Options)) (*pricing.GetProductsOutput, error) {
+	c.mu.Lock()
+	if c.decided {
+		active := c.active
+		c.mu.Unlock()
+		return active.GetProducts(ctx, params, optFns...)
+	}
+	defer c.mu.Unlock()
+
+	// The endpoint is picked once, even if both endpoints fail
+	c.decided = true
+
+	out, err := c.client.GetProducts(ctx, params, optFns...)
+	if err == nil || !shouldFailover(err) {
+		return out, err
+	}
+
+	// The preferred endpoint may have used up the whole timeout of the lookup
+	altCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeout)
+	defer cancel()
+
+	altOut, altErr := c.altClient.GetProducts(altCtx, params, optFns...)
+	if altErr != nil {
+		slog.Warn("Alternate Pricing API endpoint failed as well", "region", c.altRegion, "error", altErr)
+		return out, err
+	}
+
+	c.active = c.altClient
+	slog.Warn("Pricing API endpoint unreachable, failed over", "from", c.region, "to", c.altRegion, "error", err)
+	c.notify(fmt.Sprintf("AWS Pricing API in %s region unreachable, using %s region (%s)",
+		c.region, c.altRegion, pricingEndpoint(c.altRegion)))
+	return altOut, nil
+}
+
+// shouldFailover reports whether a failed lookup means the endpoint cannot be used from here:
+// it could not be reached, timed out, or refused the request. Cancellations and other API
+// errors, such as invalid filters, are not retried.
+func shouldFailover(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var sendErr *smithyhttp.RequestSendError
+	if errors.As(err, &sendErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && authorizationErrorCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() == 403 {
+		return true
+	}
+	return false
+}
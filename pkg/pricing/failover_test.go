@@ -0,0 +1,128 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// fakeEndpoint is a Pricing API endpoint answering every lookup with err, or with a price list
+// naming the endpoint
+type fakeEndpoint struct {
+	name  string
+	err   error
+	calls atomic.Int32
+}
+
+func (f *fakeEndpoint) GetProducts(ctx context.Context, params *pricing.GetProductsInput, optFns ...func(*pricing.Options)) (*pricing.GetProductsOutput, error) {
+	f.calls.Add(1)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &pricing.GetProductsOutput{PriceList: []string{f.name}}, nil
+}
+
+// newTestFailoverClient returns a client preferring primary over alternate and the messages it notified
+func newTestFailoverClient(primary, alternate *fakeEndpoint) (*failoverClient, *[]string) {
+	var mu sync.Mutex
+	var messages []string
+	notify := func(msg string) {
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, msg)
+	}
+	return newFailoverClient("us-east-1", primary, "ap-south-1", alternate, time.Second, notify), &messages
+}
+
+func TestFailoverClient(t *testing.T) {
+	unreachable := &smithyhttp.RequestSendError{Err: errors.New("dial tcp: i/o timeout")}
+	denied := &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "explicit deny in a service control policy"}
+	invalid := &smithy.GenericAPIError{Code: "InvalidParameterException", Message: "unknown filter field"}
+
+	tests := []struct {
+		name         string
+		primaryErr   error
+		alternateErr error
+		wantEndpoint string // Endpoint answering the lookups, empty if they fail
+		wantFailover bool
+	}{
+		{name: "primary succeeds", wantEndpoint: "primary"},
+		{name: "unreachable", primaryErr: unreachable, wantEndpoint: "alternate", wantFailover: true},
+		{name: "timed out", primaryErr: context.DeadlineExceeded, wantEndpoint: "alternate", wantFailover: true},
+		{name: "access denied", primaryErr: denied, wantEndpoint: "alternate", wantFailover: true},
+		{name: "invalid request", primaryErr: invalid},
+		{name: "cancelled", primaryErr: context.Canceled},
+		{name: "both unreachable", primaryErr: unreachable, alternateErr: unreachable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeEndpoint{name: "primary", err: tt.primaryErr}
+			alternate := &fakeEndpoint{name: "alternate", err: tt.alternateErr}
+			client, messages := newTestFailoverClient(primary, alternate)
+
+			// The endpoint picked by the first lookup sticks for the next ones
+			for i := 0; i < 3; i++ {
+				out, err := client.GetProducts(context.Background(), &pricing.GetProductsInput{})
+				if tt.wantEndpoint == "" {
+					if err == nil || !errors.Is(err, tt.primaryErr) {
+						t.Errorf("lookup %d error = %v, want the primary error %v", i, err, tt.primaryErr)
+					}
+					continue
+				}
+				if err != nil || out.PriceList[0] != tt.wantEndpoint {
+					t.Errorf("lookup %d = %v, %v, want an answer of %s", i, out, err, tt.wantEndpoint)
+				}
+			}
+
+			wantPrimaryCalls, wantAlternateCalls := int32(3), int32(0)
+			switch {
+			case tt.wantFailover:
+				wantPrimaryCalls, wantAlternateCalls = 1, 3
+			case tt.alternateErr != nil:
+				wantAlternateCalls = 1
+			}
+			if got := primary.calls.Load(); got != wantPrimaryCalls {
+				t.Errorf("primary called %d times, want %d", got, wantPrimaryCalls)
+			}
+			if got := alternate.calls.Load(); got != wantAlternateCalls {
+				t.Errorf("alternate called %d times, want %d", got, wantAlternateCalls)
+			}
+			if notified := len(*messages) > 0; notified != tt.wantFailover || len(*messages) > 1 {
+				t.Errorf("notified %q, want a single message only on failover", *messages)
+			}
+		})
+	}
+}
+
+func TestFailoverClientNotifiesOnceForConcurrentLookups(t *testing.T) {
+	primary := &fakeEndpoint{name: "primary", err: &smithyhttp.RequestSendError{Err: errors.New("connection refused")}}
+	alternate := &fakeEndpoint{name: "alternate"}
+	client, messages := newTestFailoverClient(primary, alternate)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetProducts(context.Background(), &pricing.GetProductsInput{}); err != nil {
+				t.Errorf("GetProducts() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := primary.calls.Load(); got != 1 {
+		t.Errorf("primary called %d times, want 1", got)
+	}
+	if len(*messages) != 1 || (*messages)[0] != "AWS Pricing API in us-east-1 region unreachable, using ap-south-1 region (https://api.pricing.ap-south-1.amazonaws.com)" {
+		t.Errorf("notified %q, want one failover message", *messages)
+	}
+}
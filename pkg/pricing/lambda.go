@@ -0,0 +1,116 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+// Lambda architectures as reported in the function configuration
+const (
+	LambdaArchitectureX86 = "x86_64"
+	LambdaArchitectureARM = "arm64"
+)
+
+// LambdaPrices holds the Lambda prices in USD of a region and architecture
+type LambdaPrices struct {
+	Request                float64 // Per request
+	DurationPerGBSecond    float64 // Per GB-second of compute
+	ProvisionedPerGBSecond float64 // Per GB-second of provisioned concurrency, billed while configured
+}
+
+// lambdaPriceGroups maps a Lambda price component to the AWSLambda pricing group attribute of
+// x86_64 functions. The groups of arm64 functions carry an "-ARM" suffix.
+var lambdaPriceGroups = map[string]string{
+	"request":     "AWS-Lambda-Requests",
+	"duration":    "AWS-Lambda-Duration",
+	"provisioned": "AWS-Lambda-Provisioned-Concurrency",
+}
+
+// GetLambdaPricing returns the Lambda prices of a region and architecture and the pricing
+// source. Duration prices are taken from the first tier. An empty or unknown architecture is
+// priced as x86_64. The source is "Default" if any component fell back to default pricing.
+func (s *PricingService) GetLambdaPricing(region, architecture string) (LambdaPrices, string) {
+	if architecture != LambdaArchitectureARM {
+		architecture = LambdaArchitectureX86
+	}
+
+	var prices LambdaPrices
+	source := PricingSourceNA
+
+	components := map[string]*float64{
+		"request":     &prices.Request,
+		"duration":    &prices.DurationPerGBSecond,
+		"provisioned": &prices.ProvisionedPerGBSecond,
+	}
+	for component, price := range components {
+		componentPrice, componentSource := s.getLambdaPriceWithSource(component, architecture, region)
+		if componentSource == PricingSourceNA {
+			return LambdaPrices{}, string(PricingSourceNA)
+		}
+
+		*price = componentPrice
+		source = combinePricingSources(source, componentSource)
+	}
+
+	return prices, string(source)
+}
+
+// getLambdaPriceWithSource returns the price of one Lambda price component for an
+// architecture and region
+func (s *PricingService) getLambdaPriceWithSource(component, architecture, region string) (float64, PricingSource) {
+	return s.lookupPrice("Lambda", region, fmt.Sprintf("lambda:%s:%s:%s", component, architecture, region),
+		func() (float64, error) { return s.getLambdaPriceFromAPI(component, architecture, region) },
+		func() (float64, PricingSource) {
+			regionPrices, _ := defaultPrices(DefaultLambdaPrices, region)
+			if prices, found := regionPrices[architecture]; found {
+				switch component {
+				case "request":
+					return prices.Request, PricingSourceDefault
+				case "duration":
+					return prices.DurationPerGBSecond, PricingSourceDefault
+				case "provisioned":
+					return prices.ProvisionedPerGBSecond, PricingSourceDefault
+				}
+			}
+			return 0, PricingSourceNA
+		},
+		"component", component, "architecture", architecture)
+}
+
+// getLambdaPriceFromAPI retrieves the first tier price of a Lambda price component from the
+// AWS Pricing API
+func (s *PricingService) getLambdaPriceFromAPI(component, architecture, region string) (float64, error) {
+	group, found := lambdaPriceGroups[component]
+	if !found {
+		return 0, fmt.Errorf("unsupported Lambda price component: %s", component)
+	}
+	if architecture == LambdaArchitectureARM {
+		group += "-ARM"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("group"),
+			Value: aws.String(group),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	priceJSON, err := s.GetPriceFromAPI(ctx, "AWSLambda", filters, "Lambda", group, region)
+	if err != nil {
+		return 0, err
+	}
+
+	return extractFirstTierPrice(priceJSON)
+}
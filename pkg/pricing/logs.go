@@ -0,0 +1,82 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+// CalculateLogsStorageMonthlyCostWithSource calculates the monthly storage cost of a
+// CloudWatch Log Group from its stored bytes and returns the pricing source
+func (s *PricingService) CalculateLogsStorageMonthlyCostWithSource(storedBytes int64, region string) (float64, string) {
+	price, source := s.getLogsStoragePriceWithSource(region)
+	if source == PricingSourceNA {
+		return 0, string(PricingSourceNA)
+	}
+
+	return float64(storedBytes) / (1024 * 1024 * 1024) * price, string(source)
+}
+
+// getLogsStoragePriceWithSource returns the CloudWatch Logs storage price per GB-month for a region
+func (s *PricingService) getLogsStoragePriceWithSource(region string) (float64, PricingSource) {
+	return s.lookupPrice("Logs", region, fmt.Sprintf("logs:%s", region),
+		func() (float64, error) { return s.getLogsStoragePriceFromAPI(region) },
+		func() (float64, PricingSource) {
+			if price, found := defaultPrices(DefaultLogsStoragePrices, region); found {
+				return price, PricingSourceDefault
+			}
+			return 0, PricingSourceNA
+		})
+}
+
+// getLogsStoragePriceFromAPI retrieves the CloudWatch Logs archived storage price from the AWS Pricing API
+func (s *PricingService) getLogsStoragePriceFromAPI(region string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String("Storage Snapshot"),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	pricingProducts, err := s.GetPricingProducts(ctx, "AmazonCloudWatch", filters, "Logs", "Log Storage", region)
+	if err != nil {
+		return 0, err
+	}
+
+	// Log storage is billed as TimedStorage-ByteHrs, shown per GB-month in the price list
+	for _, product := range pricingProducts {
+		var priceData map[string]interface{}
+		if err := json.Unmarshal([]byte(product), &priceData); err != nil {
+			continue
+		}
+
+		productAttrs, ok := priceData["product"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		attributes, ok := productAttrs["attributes"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if usageType, ok := attributes["usagetype"].(string); ok && strings.HasSuffix(usageType, "TimedStorage-ByteHrs") {
+			return ExtractOnDemandPrice(product)
+		}
+	}
+
+	return 0, fmt.Errorf("no CloudWatch Logs storage price found in region %s", region)
+}
@@ -0,0 +1,54 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// CalculateMSKMonthlyCostWithSource calculates the monthly broker cost of a provisioned MSK
+// cluster from the broker instance hourly price and returns the pricing source.
+// Storage and data transfer charges are left out.
+func (s *PricingService) CalculateMSKMonthlyCostWithSource(instanceType string, brokerCount int, region string) (float64, string) {
+	price, source := s.lookupPrice("MSK", region, fmt.Sprintf("msk:%s:%s", instanceType, region),
+		func() (float64, error) { return s.getMSKPriceFromAPI(instanceType, region) },
+		func() (float64, PricingSource) {
+			regionPrices, _ := defaultPrices(DefaultMSKPrices, region)
+			if price, found := regionPrices[instanceType]; found {
+				return price, PricingSourceDefault
+			}
+			return 0, PricingSourceNA
+		},
+		"type", instanceType)
+
+	return price * float64(brokerCount) * utils.GetMonthlyHours(), string(source)
+}
+
+// getMSKPriceFromAPI retrieves the broker instance hourly price from the AWS Pricing API
+func (s *PricingService) getMSKPriceFromAPI(instanceType, region string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("instanceType"),
+			Value: aws.String(instanceType),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	priceJSON, err := s.GetPriceFromAPI(ctx, "AmazonMSK", filters, "MSK", instanceType, region)
+	if err != nil {
+		return 0, err
+	}
+
+	return ExtractOnDemandPrice(priceJSON)
+}
@@ -0,0 +1,68 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// CalculateOpenSearchMonthlyCostWithSource calculates the monthly cost of OpenSearch Service
+// nodes of an instance type (e.g., "r6g.large.search") from the instance hourly price and
+// returns the pricing source. Storage is left out.
+func (s *PricingService) CalculateOpenSearchMonthlyCostWithSource(instanceType string, instanceCount int, region string) (float64, string) {
+	price, source := s.lookupPrice("OpenSearch", region, fmt.Sprintf("opensearch:%s:%s", instanceType, region),
+		func() (float64, error) { return s.getOpenSearchPriceFromAPI(instanceType, region) },
+		func() (float64, PricingSource) {
+			regionPrices, _ := defaultPrices(DefaultOpenSearchPrices, region)
+			if price, found := regionPrices[instanceType]; found {
+				return price, PricingSourceDefault
+			}
+			return 0, PricingSourceNA
+		},
+		"type", instanceType)
+
+	return price * float64(instanceCount) * utils.GetMonthlyHours(), string(source)
+}
+
+// CalculateOpenSearchDomainMonthlyCostWithSource calculates the monthly node cost of a domain
+// from its node counts by instance type, and returns the pricing source of the instance types
+// that could be priced
+func (s *PricingService) CalculateOpenSearchDomainMonthlyCostWithSource(instanceCounts map[string]int, region string) (float64, string) {
+	var totalCost float64
+	source := PricingSourceNA
+	for instanceType, count := range instanceCounts {
+		cost, instanceSource := s.CalculateOpenSearchMonthlyCostWithSource(instanceType, count, region)
+		totalCost += cost
+		source = combinePricingSources(source, PricingSource(instanceSource))
+	}
+	return totalCost, string(source)
+}
+
+// getOpenSearchPriceFromAPI retrieves the node instance hourly price from the AWS Pricing API
+func (s *PricingService) getOpenSearchPriceFromAPI(instanceType, region string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("instanceType"),
+			Value: aws.String(instanceType),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	priceJSON, err := s.GetPriceFromAPI(ctx, "AmazonES", filters, "OpenSearch", instanceType, region)
+	if err != nil {
+		return 0, err
+	}
+
+	return ExtractOnDemandPrice(priceJSON)
+}
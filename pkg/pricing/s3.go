@@ -0,0 +1,153 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+// S3StorageVolumeTypes maps the CloudWatch BucketSizeBytes StorageType dimension to the
+// AmazonS3 pricing volumeType attribute. Only storage types listed here are priced.
+var S3StorageVolumeTypes = map[string]string{
+	"StandardStorage":                "Standard",
+	"StandardIAStorage":              "Standard - Infrequent Access",
+	"OneZoneIAStorage":               "One Zone - Infrequent Access",
+	"ReducedRedundancyStorage":       "Reduced Redundancy",
+	"IntelligentTieringFAStorage":    "Intelligent-Tiering Frequent Access",
+	"IntelligentTieringIAStorage":    "Intelligent-Tiering Infrequent Access",
+	"IntelligentTieringAIAStorage":   "Intelligent-Tiering Archive Instant Access",
+	"GlacierInstantRetrievalStorage": "Glacier Instant Retrieval",
+	"GlacierStorage":                 "Amazon Glacier",
+	"DeepArchiveStorage":             "Glacier Deep Archive",
+}
+
+// CalculateS3MonthlyCostWithSource calculates the monthly storage cost of a bucket from its
+// size per storage type and returns the pricing source. Every storage type is priced at its
+// first tier. The source is "Default" if any storage type fell back to default pricing.
+func (s *PricingService) CalculateS3MonthlyCostWithSource(sizesByStorageType map[string]int64, region string) (float64, string) {
+	var totalCost float64
+	source := PricingSourceNA
+
+	for storageType, sizeBytes := range sizesByStorageType {
+		if sizeBytes <= 0 {
+			continue
+		}
+
+		price, typeSource := s.getS3StoragePriceWithSource(storageType, region)
+		if typeSource == PricingSourceNA {
+			continue
+		}
+
+		totalCost += float64(sizeBytes) / (1024 * 1024 * 1024) * price
+		source = combinePricingSources(source, typeSource)
+	}
+
+	// An empty bucket costs nothing regardless of where prices would come from
+	if source == PricingSourceNA {
+		return 0, string(PricingSourceDefault)
+	}
+
+	return totalCost, string(source)
+}
+
+// getS3StoragePriceWithSource returns the price per GB-month for an S3 storage type and region
+func (s *PricingService) getS3StoragePriceWithSource(storageType, region string) (float64, PricingSource) {
+	return s.lookupPrice("S3", region, fmt.Sprintf("s3:%s:%s", storageType, region),
+		func() (float64, error) { return s.getS3PriceFromAPI(storageType, region) },
+		func() (float64, PricingSource) {
+			regionPrices, _ := defaultPrices(DefaultS3Prices, region)
+			if price, found := regionPrices[storageType]; found {
+				return price, PricingSourceDefault
+			}
+			return 0, PricingSourceNA
+		},
+		"storageType", storageType)
+}
+
+// getS3PriceFromAPI retrieves the first tier S3 storage price from the AWS Pricing API
+func (s *PricingService) getS3PriceFromAPI(storageType, region string) (float64, error) {
+	volumeType, found := S3StorageVolumeTypes[storageType]
+	if !found {
+		return 0, fmt.Errorf("unsupported S3 storage type: %s", storageType)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String("Storage"),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("volumeType"),
+			Value: aws.String(volumeType),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	priceJSON, err := s.GetPriceFromAPI(ctx, "AmazonS3", filters, "S3", storageType, region)
+	if err != nil {
+		return 0, err
+	}
+
+	return extractFirstTierPrice(priceJSON)
+}
+
+// extractFirstTierPrice extracts the on-demand price of the tier starting at 0 from tiered
+// pricing data such as S3 Standard storage, where the price dimensions are unordered
+func extractFirstTierPrice(priceJSON string) (float64, error) {
+	price, _, err := extractFirstTierPriceAndUnit(priceJSON)
+	return price, err
+}
+
+// extractFirstTierPriceAndUnit extracts the on-demand price of the tier starting at 0 and its
+// pricing unit (e.g., GB-Mo)
+func extractFirstTierPriceAndUnit(priceJSON string) (float64, string, error) {
+	var priceData struct {
+		Terms struct {
+			OnDemand map[string]struct {
+				PriceDimensions map[string]struct {
+					BeginRange   string            `json:"beginRange"`
+					Unit         string            `json:"unit"`
+					PricePerUnit map[string]string `json:"pricePerUnit"`
+				} `json:"priceDimensions"`
+			} `json:"OnDemand"`
+		} `json:"terms"`
+	}
+	if err := json.Unmarshal([]byte(priceJSON), &priceData); err != nil {
+		return 0, "", fmt.Errorf("error parsing pricing data: %w", err)
+	}
+
+	for _, offer := range priceData.Terms.OnDemand {
+		for _, dimension := range offer.PriceDimensions {
+			if dimension.BeginRange != "" && dimension.BeginRange != "0" {
+				continue
+			}
+
+			usd, found := dimension.PricePerUnit["USD"]
+			if !found {
+				return 0, "", fmt.Errorf("USD price not found or invalid")
+			}
+
+			price, err := strconv.ParseFloat(usd, 64)
+			if err != nil {
+				return 0, "", fmt.Errorf("error parsing price: %w", err)
+			}
+
+			return price, dimension.Unit, nil
+		}
+	}
+
+	return 0, "", fmt.Errorf("no first tier price dimension found")
+}
@@ -0,0 +1,79 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/younsl/idled/pkg/utils"
+)
+
+// SageMaker pricing components of an instance
+const (
+	SageMakerComponentHosting  = "Hosting"  // Real-time inference endpoint instances
+	SageMakerComponentNotebook = "Notebook" // Notebook instances
+)
+
+// CalculateSageMakerMonthlyCostWithSource calculates the monthly cost of SageMaker ML
+// instances of a pricing component (hosting or notebook) from the instance hourly price and
+// returns the pricing source. Storage and data processing charges are left out.
+func (s *PricingService) CalculateSageMakerMonthlyCostWithSource(component, instanceType string, instanceCount int, region string) (float64, string) {
+	price, source := s.lookupPrice("SageMaker", region, fmt.Sprintf("sagemaker:%s:%s:%s", component, instanceType, region),
+		func() (float64, error) { return s.getSageMakerPriceFromAPI(component, instanceType, region) },
+		func() (float64, PricingSource) {
+			regionPrices, _ := defaultPrices(DefaultSageMakerPrices, region)
+			if price, found := regionPrices[component][instanceType]; found {
+				return price, PricingSourceDefault
+			}
+			return 0, PricingSourceNA
+		},
+		"component", component, "type", instanceType)
+
+	return price * float64(instanceCount) * utils.GetMonthlyHours(), string(source)
+}
+
+// CalculateSageMakerEndpointMonthlyCostWithSource calculates the monthly instance cost of a
+// real-time endpoint from its instance counts by instance type, and returns the pricing source
+// of the instance types that could be priced
+func (s *PricingService) CalculateSageMakerEndpointMonthlyCostWithSource(instanceCounts map[string]int, region string) (float64, string) {
+	var totalCost float64
+	source := PricingSourceNA
+	for instanceType, count := range instanceCounts {
+		cost, instanceSource := s.CalculateSageMakerMonthlyCostWithSource(SageMakerComponentHosting, instanceType, count, region)
+		totalCost += cost
+		source = combinePricingSources(source, PricingSource(instanceSource))
+	}
+	return totalCost, string(source)
+}
+
+// getSageMakerPriceFromAPI retrieves the ML instance hourly price of a component from the AWS Pricing API
+func (s *PricingService) getSageMakerPriceFromAPI(component, instanceType, region string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("instanceName"),
+			Value: aws.String(instanceType),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("component"),
+			Value: aws.String(component),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+	}
+
+	priceJSON, err := s.GetPriceFromAPI(ctx, "AmazonSageMaker", filters, "SageMaker", instanceType, region)
+	if err != nil {
+		return 0, err
+	}
+
+	return ExtractOnDemandPrice(priceJSON)
+}
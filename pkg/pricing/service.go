@@ -0,0 +1,136 @@
+package pricing
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/younsl/idled/internal/progress"
+)
+
+// cachedServices are the services whose Pricing API prices are cached, by the service name
+// used in the pricing statistics
+var cachedServices = []string{"EC2", "EBS", "EIP", "ELB", "MSK", "S3", "Logs", "Lambda", "SageMaker", "OpenSearch"}
+
+// PricingService looks up AWS prices with its own Pricing API client, caches, and call
+// statistics. The Pricing API client is initialized on the first lookup. The package-level
+// functions delegate to a default service shared by every scanner.
+type PricingService struct {
+	client   ProductsAPI // Pricing API client, nil if unavailable or disabled
+	initOnce sync.Once   // Initializes the client on the first lookup
+
+	region      string        // Preferred Pricing API endpoint region
+	timeout     time.Duration // Timeout of each Pricing API call
+	apiDisabled bool          // Whether every price comes from the fallback prices
+
+	configOptions []func(*config.LoadOptions) error // Extra options of the Pricing API client config
+
+	initMessage   string     // Initialization message displayed after the scan progress
+	initMessageMu sync.Mutex // Protects initMessage, which the failover client updates during the scan
+
+	progress   progress.Func // Progress function of the service being scanned
+	progressMu sync.RWMutex
+
+	caches map[string]*priceCache // Pricing API prices by service name
+
+	stats   map[string]map[string]map[string]int // service -> region -> {success, failure, cache, disk}
+	statsMu sync.RWMutex
+
+	diskFetchedAt map[string]time.Time // Retrieval time of the prices loaded from the disk cache, by disk key
+	diskMu        sync.RWMutex
+}
+
+// priceCache caches the prices of one service by cache key
+type priceCache struct {
+	entries map[string]float64
+	mu      sync.RWMutex
+}
+
+// defaultService is the service the package-level functions delegate to
+var defaultService = NewPricingService()
+
+// NewPricingService creates a pricing service using the default endpoint region and timeout
+func NewPricingService() *PricingService {
+	s := &PricingService{
+		region:        DefaultRegion,
+		timeout:       DefaultAPITimeout,
+		caches:        make(map[string]*priceCache),
+		stats:         make(map[string]map[string]map[string]int),
+		diskFetchedAt: make(map[string]time.Time),
+	}
+	for _, service := range cachedServices {
+		s.caches[service] = &priceCache{entries: make(map[string]float64)}
+	}
+	return s
+}
+
+// Default returns the pricing service shared by the package-level functions
+func Default() *PricingService {
+	return defaultService
+}
+
+// SetClient replaces the Pricing API client, e.g., with a stub. The client is used as is,
+// without endpoint failover.
+func (s *PricingService) SetClient(client ProductsAPI) {
+	s.initOnce.Do(func() {})
+	s.client = client
+}
+
+// cachedPrice returns the cached price of a service and records the cache hit
+func (s *PricingService) cachedPrice(service, region, cacheKey string) (float64, bool) {
+	cache := s.caches[service]
+	cache.mu.RLock()
+	price, found := cache.entries[cacheKey]
+	cache.mu.RUnlock()
+
+	if found {
+		s.UpdateCacheHitStats(service, region, cacheKey)
+	}
+	return price, found
+}
+
+// cachePrice caches a price retrieved from the Pricing API
+func (s *PricingService) cachePrice(service, cacheKey string, price float64) {
+	cache := s.caches[service]
+	cache.mu.Lock()
+	cache.entries[cacheKey] = price
+	cache.mu.Unlock()
+}
+
+// lookupPrice returns a unit price of a service from the cache or the Pricing API, which
+// fetch queries. If the API is unavailable or fails, the failure is counted and the price
+// comes from fallback, which is not cached so that it never reaches the disk cache. logAttrs
+// identify the lookup in the warning of a failed API call.
+func (s *PricingService) lookupPrice(service, region, cacheKey string, fetch func() (float64, error), fallback func() (float64, PricingSource), logAttrs ...any) (float64, PricingSource) {
+	s.init(context.Background())
+
+	if price, found := s.cachedPrice(service, region, cacheKey); found {
+		return price, PricingSourceCache
+	}
+
+	if s.client != nil {
+		price, err := fetch()
+		if err == nil {
+			s.UpdateAPISuccessStats(service, region)
+			s.cachePrice(service, cacheKey, price)
+			return price, PricingSourceAPI
+		}
+		slog.Warn("Could not get "+service+" price from the pricing API", append(logAttrs, "region", region, "error", err)...)
+	}
+
+	s.UpdateAPIFailureStats(service, region)
+	return fallback()
+}
+
+// defaultPrices returns the fallback prices of a region from one of the Default*Prices
+// tables, or those of us-east-1 if the region is not listed
+func defaultPrices[V any](prices map[string]V, region string) (V, bool) {
+	if regionPrices, found := prices[region]; found {
+		return regionPrices, true
+	}
+	regionPrices, found := prices["us-east-1"]
+	return regionPrices, found
+}
@@ -0,0 +1,78 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupPrice(t *testing.T) {
+	service := NewPricingService()
+	service.SetClient(&fakeEndpoint{name: "stub"})
+
+	fetches := 0
+	fetchErr := errors.New("no price found")
+	fetch := func() (float64, error) {
+		fetches++
+		if fetchErr != nil {
+			return 0, fetchErr
+		}
+		return 0.5, nil
+	}
+	fallback := func() (float64, PricingSource) { return 0.25, PricingSourceDefault }
+
+	// Fallback prices are not cached, so the API is asked again
+	for i := 0; i < 2; i++ {
+		if price, source := service.lookupPrice("S3", "us-east-1", "s3:test", fetch, fallback); price != 0.25 || source != PricingSourceDefault {
+			t.Errorf("lookupPrice() after a failure = %v, %s, want the fallback price", price, source)
+		}
+	}
+
+	fetchErr = nil
+	for _, want := range []PricingSource{PricingSourceAPI, PricingSourceCache} {
+		if price, source := service.lookupPrice("S3", "us-east-1", "s3:test", fetch, fallback); price != 0.5 || source != want {
+			t.Errorf("lookupPrice() = %v, %s, want 0.5, %s", price, source, want)
+		}
+	}
+
+	if fetches != 3 {
+		t.Errorf("fetch called %d times, want 3", fetches)
+	}
+	stats := service.GetAPIStats()["S3"]["us-east-1"]
+	if stats["failure"] != 2 || stats["success"] != 1 || stats["cache"] != 1 {
+		t.Errorf("stats = %v, want 2 failures, 1 success, and 1 cache hit", stats)
+	}
+}
+
+func TestLookupPriceWithoutClient(t *testing.T) {
+	service := NewPricingService()
+	service.DisableAPI()
+
+	fetch := func() (float64, error) {
+		t.Error("fetch called without a Pricing API client")
+		return 0, nil
+	}
+	fallback := func() (float64, PricingSource) { return 0, PricingSourceNA }
+	if price, source := service.lookupPrice("S3", "us-east-1", "s3:test", fetch, fallback); price != 0 || source != PricingSourceNA {
+		t.Errorf("lookupPrice() = %v, %s, want N/A", price, source)
+	}
+}
+
+func TestDefaultPrices(t *testing.T) {
+	prices := map[string]float64{"us-east-1": 1, "ap-northeast-2": 2}
+	tests := []struct {
+		region    string
+		prices    map[string]float64
+		want      float64
+		wantFound bool
+	}{
+		{region: "ap-northeast-2", prices: prices, want: 2, wantFound: true},
+		{region: "eu-west-1", prices: prices, want: 1, wantFound: true},
+		{region: "eu-west-1", prices: map[string]float64{"ap-northeast-2": 2}},
+	}
+
+	for _, tt := range tests {
+		if got, found := defaultPrices(tt.prices, tt.region); got != tt.want || found != tt.wantFound {
+			t.Errorf("defaultPrices(%s) = %v, %v, want %v, %v", tt.region, got, found, tt.want, tt.wantFound)
+		}
+	}
+}
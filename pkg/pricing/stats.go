@@ -1,13 +1,13 @@
 package pricing
 
 // GetAPIStats returns a copy of the current pricing API statistics
-func GetAPIStats() map[string]map[string]map[string]int {
-	PricingAPIStatsLock.RLock()
-	defer PricingAPIStatsLock.RUnlock()
+func (s *PricingService) GetAPIStats() map[string]map[string]map[string]int {
+	s.statsMu.RLock()
+	defer s.statsMu.RUnlock()
 
 	// Create a deep copy of the stats
 	statsCopy := make(map[string]map[string]map[string]int)
-	for service, regions := range PricingAPIStats {
+	for service, regions := range s.stats {
 		statsCopy[service] = make(map[string]map[string]int)
 		for region, stats := range regions {
 			statsCopy[service][region] = make(map[string]int)
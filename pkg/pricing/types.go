@@ -1,9 +1,5 @@
 package pricing
 
-import (
-	"sync"
-)
-
 // PricingSource represents the source of pricing information
 type PricingSource string
 
@@ -17,39 +13,73 @@ const (
 	// PricingSourceDefault indicates pricing data came from hardcoded defaults
 	PricingSourceDefault PricingSource = "Default"
 
+	// PricingSourceEstimated indicates pricing data came from a bundled default price table
+	// that was estimated rather than generated from published prices
+	PricingSourceEstimated PricingSource = "Estimated"
+
 	// PricingSourceNA indicates pricing data is not available
 	PricingSourceNA PricingSource = "N/A"
 )
 
-// Stats tracking for pricing API calls
-var (
-	// PricingAPIStats tracks API call statistics by service and region
-	PricingAPIStats = make(map[string]map[string]map[string]int) // service -> region -> {success, failure, cache}
+// SecretsManagerMonthlyPricePerSecret is the Secrets Manager price in USD per secret-month.
+// It is the same in every region, so it is not looked up with the Pricing API.
+const SecretsManagerMonthlyPricePerSecret = 0.40
 
-	// PricingAPIStatsLock protects the stats map from concurrent access
-	PricingAPIStatsLock sync.RWMutex
-)
+// TransferFamilyHourlyPricePerProtocol is the AWS Transfer Family price in USD per protocol
+// enabled on a server endpoint per hour in us-east-1. It is charged until the server is deleted,
+// even while it is stopped, and is used in every region.
+const TransferFamilyHourlyPricePerProtocol = 0.30
+
+// GlobalAcceleratorHourlyPrice is the AWS Global Accelerator fixed fee in USD per accelerator
+// per hour. It is charged until the accelerator is deleted, even while it is disabled, and
+// comes on top of the data transfer premium, which an idle accelerator does not incur.
+const GlobalAcceleratorHourlyPrice = 0.025
+
+// Route53ResolverEndpointHourlyPricePerENI is the Route 53 Resolver endpoint price in USD per
+// elastic network interface (IP address) per hour in us-east-1, used in every region. Queries
+// are billed separately.
+const Route53ResolverEndpointHourlyPricePerENI = 0.125
+
+// CloudWatch prices in USD per month in us-east-1, used in every region. The free tier of
+// 10 alarm metrics and 3 dashboards is not deducted.
+const (
+	// CloudWatchStandardAlarmMonthlyPrice is the price of each metric of a standard resolution
+	// alarm (period of 60 seconds or longer)
+	CloudWatchStandardAlarmMonthlyPrice = 0.10
+
+	// CloudWatchHighResolutionAlarmMonthlyPrice is the price of each metric of a high resolution
+	// alarm (period under 60 seconds)
+	CloudWatchHighResolutionAlarmMonthlyPrice = 0.30
 
-// EC2 cache
-var (
-	// EC2PricingCache caches EC2 instance pricing data
-	EC2PricingCache = make(map[string]float64)
+	// CloudWatchCompositeAlarmMonthlyPrice is the price of a composite alarm
+	CloudWatchCompositeAlarmMonthlyPrice = 0.50
 
-	// EC2PricingCacheLock protects the EC2 cache from concurrent access
-	EC2PricingCacheLock sync.RWMutex
+	// CloudWatchDashboardMonthlyPrice is the price of a custom dashboard
+	CloudWatchDashboardMonthlyPrice = 3.00
 )
 
-// EBS cache
-var (
-	// EBSPricingCache caches EBS volume pricing data
-	EBSPricingCache = make(map[string]float64)
+// Route 53 prices in USD per month. They are global, so they are not looked up with the Pricing API.
+const (
+	// Route53HostedZoneMonthlyPrice is the price of each of the first 25 hosted zones
+	Route53HostedZoneMonthlyPrice = 0.50
+
+	// Route53HealthCheckAWSMonthlyPrice is the price of a basic health check of an AWS endpoint,
+	// also charged for calculated and CloudWatch metric health checks
+	Route53HealthCheckAWSMonthlyPrice = 0.50
+
+	// Route53HealthCheckNonAWSMonthlyPrice is the price of a basic health check of a non-AWS endpoint
+	Route53HealthCheckNonAWSMonthlyPrice = 0.75
 
-	// EBSPricingCacheLock protects the EBS cache from concurrent access
-	EBSPricingCacheLock sync.RWMutex
+	// Route53HealthCheckAWSFeatureMonthlyPrice is the price of each optional feature (HTTPS,
+	// string matching, fast interval, latency measurement) of an AWS endpoint health check
+	Route53HealthCheckAWSFeatureMonthlyPrice = 1.00
+
+	// Route53HealthCheckNonAWSFeatureMonthlyPrice is the price of each optional feature of a
+	// non-AWS endpoint health check
+	Route53HealthCheckNonAWSFeatureMonthlyPrice = 2.00
 )
 
 // Default EBS volume prices in USD per GB-month
-// These are fallback prices if Pricing API fails
 var DefaultEBSPrices = map[string]map[string]float64{
 	"us-east-1": { // US East (N. Virginia)
 		"gp2":      0.10,
@@ -71,3 +101,190 @@ var DefaultEBSPrices = map[string]map[string]float64{
 	},
 	// Add more regions as needed
 }
+
+// Default EBS performance prices in USD per provisioned IOPS-month or MB/s-month, keyed by
+// volume type and component (e.g., gp3-iops). gp3 prices apply above the included baseline.
+var DefaultEBSPerformancePrices = map[string]map[string]float64{
+	"us-east-1": { // US East (N. Virginia)
+		"gp3-iops":       0.005,
+		"gp3-throughput": 0.04,
+		"io1-iops":       0.065,
+		"io2-iops":       0.065,
+	},
+	"ap-northeast-2": { // Asia Pacific (Seoul)
+		"gp3-iops":       0.0057,
+		"gp3-throughput": 0.0456,
+		"io1-iops":       0.0741,
+		"io2-iops":       0.0741,
+	},
+	// Add more regions as needed
+}
+
+// Default public IPv4 address prices in USD per hour
+var DefaultEIPPrices = map[string]float64{
+	"us-east-1":      0.005, // US East (N. Virginia)
+	"ap-northeast-2": 0.005, // Asia Pacific (Seoul)
+	// Add more regions as needed
+}
+
+// Default load balancer prices in USD per LoadBalancer-hour, excluding LCU/NLCU charges
+var DefaultELBPrices = map[string]map[string]float64{
+	"us-east-1": { // US East (N. Virginia)
+		"ALB":  0.0225,
+		"NLB":  0.0225,
+		"GWLB": 0.0125,
+		"CLB":  0.025,
+	},
+	"ap-northeast-2": { // Asia Pacific (Seoul)
+		"ALB":  0.0225,
+		"NLB":  0.0225,
+		"GWLB": 0.0125,
+		"CLB":  0.025,
+	},
+	// Add more regions as needed
+}
+
+// Default SageMaker ML instance prices in USD per instance-hour by pricing component
+var DefaultSageMakerPrices = map[string]map[string]map[string]float64{
+	"us-east-1": { // US East (N. Virginia)
+		SageMakerComponentHosting: {
+			"ml.t2.medium":   0.056,
+			"ml.m5.large":    0.115,
+			"ml.m5.xlarge":   0.23,
+			"ml.m5.2xlarge":  0.461,
+			"ml.c5.large":    0.102,
+			"ml.c5.xlarge":   0.204,
+			"ml.g4dn.xlarge": 0.736,
+			"ml.g5.xlarge":   1.408,
+			"ml.p3.2xlarge":  3.825,
+		},
+		SageMakerComponentNotebook: {
+			"ml.t2.medium":   0.0464,
+			"ml.t3.medium":   0.05,
+			"ml.t3.large":    0.1,
+			"ml.m5.xlarge":   0.23,
+			"ml.m5.2xlarge":  0.461,
+			"ml.c5.xlarge":   0.204,
+			"ml.g4dn.xlarge": 0.7364,
+			"ml.p3.2xlarge":  3.825,
+		},
+	},
+	// Add more regions as needed
+}
+
+// SageMakerStorageMonthlyPricePerGB is the price of ML storage attached to notebook instances
+// in USD per GB-month in us-east-1. It is used in every region.
+const SageMakerStorageMonthlyPricePerGB = 0.14
+
+// WorkSpacesBundlePrice holds the monthly prices of a WorkSpaces bundle compute type
+type WorkSpacesBundlePrice struct {
+	AlwaysOnMonthly float64 // Flat monthly price of an ALWAYS_ON WorkSpace
+	AutoStopMonthly float64 // Fixed monthly fee of an AUTO_STOP WorkSpace (storage and infrastructure), excluding hourly usage
+}
+
+// Default WorkSpaces bundle prices in USD per month by compute type, for Windows bundles with
+// the default root and user volumes in us-east-1. WorkSpaces prices depend on the operating
+// system, license, and volume sizes, which the Pricing API does not expose in a regular way,
+// so this static table is used in every region.
+var DefaultWorkSpacesPrices = map[string]WorkSpacesBundlePrice{
+	"VALUE":            {AlwaysOnMonthly: 25, AutoStopMonthly: 7.25},
+	"STANDARD":         {AlwaysOnMonthly: 35, AutoStopMonthly: 9.75},
+	"PERFORMANCE":      {AlwaysOnMonthly: 60, AutoStopMonthly: 13},
+	"POWER":            {AlwaysOnMonthly: 80, AutoStopMonthly: 13},
+	"POWERPRO":         {AlwaysOnMonthly: 124, AutoStopMonthly: 19},
+	"GRAPHICS":         {AlwaysOnMonthly: 502, AutoStopMonthly: 22},
+	"GRAPHICSPRO":      {AlwaysOnMonthly: 999, AutoStopMonthly: 66},
+	"GRAPHICS_G4DN":    {AlwaysOnMonthly: 502, AutoStopMonthly: 33},
+	"GRAPHICSPRO_G4DN": {AlwaysOnMonthly: 1330, AutoStopMonthly: 66},
+}
+
+// Default MSK broker instance prices in USD per broker-hour, excluding storage
+var DefaultMSKPrices = map[string]map[string]float64{
+	"us-east-1": { // US East (N. Virginia)
+		"kafka.t3.small":   0.0456,
+		"kafka.m5.large":   0.21,
+		"kafka.m5.xlarge":  0.42,
+		"kafka.m5.2xlarge": 0.84,
+		"kafka.m7g.large":  0.204,
+		"kafka.m7g.xlarge": 0.408,
+	},
+	"ap-northeast-2": { // Asia Pacific (Seoul)
+		"kafka.t3.small":   0.0524,
+		"kafka.m5.large":   0.236,
+		"kafka.m5.xlarge":  0.472,
+		"kafka.m5.2xlarge": 0.944,
+		"kafka.m7g.large":  0.229,
+		"kafka.m7g.xlarge": 0.458,
+	},
+	// Add more regions as needed
+}
+
+// Default OpenSearch Service node instance prices in USD per instance-hour
+var DefaultOpenSearchPrices = map[string]map[string]float64{
+	"us-east-1": { // US East (N. Virginia)
+		"t3.small.search":          0.036,
+		"t3.medium.search":         0.073,
+		"m5.large.search":          0.142,
+		"m6g.large.search":         0.128,
+		"c6g.large.search":         0.113,
+		"r5.large.search":          0.186,
+		"r6g.large.search":         0.167,
+		"r6g.xlarge.search":        0.335,
+		"ultrawarm1.medium.search": 0.238,
+	},
+	// Add more regions as needed
+}
+
+// OpenSearchStorageMonthlyPricePerGB is the price of gp3 EBS storage attached to OpenSearch
+// Service data nodes in USD per GB-month in us-east-1. It is used for every volume type and region.
+const OpenSearchStorageMonthlyPricePerGB = 0.122
+
+// Default S3 storage prices in USD per GB-month for the first pricing tier, keyed by the
+// CloudWatch BucketSizeBytes StorageType dimension
+var DefaultS3Prices = map[string]map[string]float64{
+	"us-east-1": { // US East (N. Virginia)
+		"StandardStorage":                0.023,
+		"StandardIAStorage":              0.0125,
+		"OneZoneIAStorage":               0.01,
+		"ReducedRedundancyStorage":       0.024,
+		"IntelligentTieringFAStorage":    0.023,
+		"IntelligentTieringIAStorage":    0.0125,
+		"IntelligentTieringAIAStorage":   0.004,
+		"GlacierInstantRetrievalStorage": 0.004,
+		"GlacierStorage":                 0.0036,
+		"DeepArchiveStorage":             0.00099,
+	},
+	"ap-northeast-2": { // Asia Pacific (Seoul)
+		"StandardStorage":                0.025,
+		"StandardIAStorage":              0.0138,
+		"OneZoneIAStorage":               0.011,
+		"ReducedRedundancyStorage":       0.026,
+		"IntelligentTieringFAStorage":    0.025,
+		"IntelligentTieringIAStorage":    0.0138,
+		"IntelligentTieringAIAStorage":   0.005,
+		"GlacierInstantRetrievalStorage": 0.005,
+		"GlacierStorage":                 0.0045,
+		"DeepArchiveStorage":             0.002,
+	},
+	// Add more regions as needed
+}
+
+// Default CloudWatch Logs storage prices in USD per GB-month
+var DefaultLogsStoragePrices = map[string]float64{
+	"us-east-1":      0.03,   // US East (N. Virginia)
+	"ap-northeast-2": 0.0314, // Asia Pacific (Seoul)
+	// Add more regions as needed
+}
+
+// Default Lambda prices in USD, keyed by region and architecture
+var DefaultLambdaPrices = map[string]map[string]LambdaPrices{
+	"us-east-1": { // US East (N. Virginia)
+		LambdaArchitectureX86: {Request: 0.20 / 1000000, DurationPerGBSecond: 0.0000166667, ProvisionedPerGBSecond: 0.0000041667},
+		LambdaArchitectureARM: {Request: 0.20 / 1000000, DurationPerGBSecond: 0.0000133334, ProvisionedPerGBSecond: 0.0000033334},
+	},
+	"ap-northeast-2": { // Asia Pacific (Seoul)
+		LambdaArchitectureX86: {Request: 0.20 / 1000000, DurationPerGBSecond: 0.0000166667, ProvisionedPerGBSecond: 0.0000041667},
+		LambdaArchitectureARM: {Request: 0.20 / 1000000, DurationPerGBSecond: 0.0000133334, ProvisionedPerGBSecond: 0.0000033334},
+	},
+	// Add more regions as needed
+}
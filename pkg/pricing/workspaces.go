@@ -0,0 +1,23 @@
+package pricing
+
+// WorkSpaces running modes
+const (
+	WorkSpacesRunningModeAlwaysOn = "ALWAYS_ON"
+	WorkSpacesRunningModeAutoStop = "AUTO_STOP"
+	WorkSpacesRunningModeManual   = "MANUAL"
+)
+
+// GetWorkSpacesMonthlySavingsWithSource returns the monthly cost saved by removing a WorkSpace
+// that is not used. An ALWAYS_ON WorkSpace saves its full monthly bundle price, while an
+// AUTO_STOP or MANUAL WorkSpace only saves its fixed monthly fee, since its hourly usage is
+// not billed while nobody connects.
+func (s *PricingService) GetWorkSpacesMonthlySavingsWithSource(computeType, runningMode string) (float64, string) {
+	price, ok := DefaultWorkSpacesPrices[computeType]
+	if !ok {
+		return 0, string(PricingSourceNA)
+	}
+	if runningMode == WorkSpacesRunningModeAlwaysOn {
+		return price.AlwaysOnMonthly, string(PricingSourceDefault)
+	}
+	return price.AutoStopMonthly, string(PricingSourceDefault)
+}
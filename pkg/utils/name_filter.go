@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// NameFilter keeps or drops resources by name with --include and --exclude patterns. A name
+// is kept if it matches any include pattern (or there are none) and no exclude pattern.
+// Patterns wrapped in slashes (e.g., /^cdk-[a-z0-9]+-assets-/) are Go regular expressions,
+// anything else is a glob where * matches any characters, including '/', and ? matches one.
+// Matching is case-sensitive. Regular expressions can opt out with (?i).
+//
+// A nil NameFilter keeps every name.
+type NameFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+
+	mu       sync.Mutex
+	excluded map[string]bool // Keys of the resources dropped since the last TakeExcludedCount
+}
+
+// NewNameFilter compiles the include and exclude patterns. It returns nil without patterns.
+func NewNameFilter(include, exclude []string) (*NameFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	f := &NameFilter{excluded: make(map[string]bool)}
+	for _, pattern := range include {
+		re, err := compileNamePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --include pattern '%s': %w", pattern, err)
+		}
+		f.include = append(f.include, re)
+	}
+	for _, pattern := range exclude {
+		re, err := compileNamePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --exclude pattern '%s': %w", pattern, err)
+		}
+		f.exclude = append(f.exclude, re)
+	}
+	return f, nil
+}
+
+// compileNamePattern compiles a /regex/ or glob pattern into an anchored regular expression
+func compileNamePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return regexp.Compile(pattern[1 : len(pattern)-1])
+	}
+	return regexp.Compile("^" + globToRegexp(pattern) + "$")
+}
+
+// globToRegexp converts a glob into a regular expression. Character classes ([a-z], [!a-z])
+// are kept.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	inClass := false
+	for i, r := range glob {
+		switch {
+		case inClass && r == '!' && glob[i-1] == '[':
+			// [!a-z] negates the class in glob syntax
+			b.WriteRune('^')
+		case inClass:
+			b.WriteRune(r)
+			if r == ']' {
+				inClass = false
+			}
+		case r == '*':
+			b.WriteString(".*")
+		case r == '?':
+			b.WriteString(".")
+		case r == '[':
+			inClass = true
+			b.WriteRune(r)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether a name is kept by the filter
+func (f *NameFilter) Match(name string) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.include) > 0 {
+		included := false
+		for _, re := range f.include {
+			if re.MatchString(name) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, re := range f.exclude {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Excludes reports whether a name is dropped by the filter and counts the dropped resource
+// under key, so a resource listed by the scans of several regions (e.g., an S3 bucket) is
+// counted once
+func (f *NameFilter) Excludes(key, name string) bool {
+	if f.Match(name) {
+		return false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.excluded[key] = true
+	return true
+}
+
+// TakeExcludedCount returns the number of resources dropped since the last call and resets it
+func (f *NameFilter) TakeExcludedCount() int {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := len(f.excluded)
+	f.excluded = make(map[string]bool)
+	return count
+}
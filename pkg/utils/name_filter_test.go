@@ -0,0 +1,150 @@
+package utils
+
+import "testing"
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob string
+		want string
+	}{
+		{glob: "app.log", want: `app\.log`},
+		{glob: "c++", want: `c\+\+`},
+		{glob: "build(1)", want: `build\(1\)`},
+		{glob: "cost$|^all", want: `cost\$\|\^all`},
+		{glob: "tmp-*", want: `tmp-.*`},
+		{glob: "v?", want: `v.`},
+		{glob: "node-[0-9]", want: `node-[0-9]`},
+		{glob: "node-[!0-9]", want: `node-[^0-9]`},
+		{glob: "!keep", want: `!keep`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob, func(t *testing.T) {
+			if got := globToRegexp(tt.glob); got != tt.want {
+				t.Errorf("globToRegexp(%q) = %q, want %q", tt.glob, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameFilterMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		matches map[string]bool
+	}{
+		{
+			name:    "dot is literal",
+			include: []string{"app.log"},
+			matches: map[string]bool{"app.log": true, "app-log": false, "app.logs": false},
+		},
+		{
+			name:    "plus is literal",
+			include: []string{"c++-build"},
+			matches: map[string]bool{"c++-build": true, "cc-build": false, "c-build": false},
+		},
+		{
+			name:    "parenthesis is literal",
+			include: []string{"build(1)*"},
+			matches: map[string]bool{"build(1)": true, "build(1)-old": true, "build1": false},
+		},
+		{
+			name:    "star matches any characters including slashes",
+			include: []string{"cdk-*-assets"},
+			matches: map[string]bool{"cdk-hnb659fds-assets": true, "cdk-a/b-assets": true, "cdk--assets": true, "cdk-assets": false},
+		},
+		{
+			name:    "question mark matches one character",
+			include: []string{"db-?"},
+			matches: map[string]bool{"db-1": true, "db-": false, "db-12": false},
+		},
+		{
+			name:    "glob is anchored and case-sensitive",
+			include: []string{"prod"},
+			matches: map[string]bool{"prod": true, "my-prod": false, "prod-1": false, "PROD": false},
+		},
+		{
+			name:    "regular expression",
+			include: []string{"/^cdk-[a-z0-9]+-assets-/", "/(?i)^legacy/"},
+			matches: map[string]bool{"cdk-abc123-assets-eu": true, "LEGACY-app": true, "my-cdk-abc-assets-": false},
+		},
+		{
+			name:    "any include pattern",
+			include: []string{"web-*", "api-*"},
+			matches: map[string]bool{"web-1": true, "api-1": true, "worker-1": false},
+		},
+		{
+			name:    "exclude only",
+			exclude: []string{"*-keep"},
+			matches: map[string]bool{"logs": true, "logs-keep": false},
+		},
+		{
+			name:    "exclude wins over include",
+			include: []string{"prod-*"},
+			exclude: []string{"*-keep", "/-backup$/"},
+			matches: map[string]bool{"prod-app": true, "prod-app-keep": false, "prod-db-backup": false, "dev-app": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewNameFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewNameFilter() error = %v", err)
+			}
+			for name, want := range tt.matches {
+				if got := f.Match(name); got != want {
+					t.Errorf("Match(%q) = %t, want %t", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewNameFilterEmpty(t *testing.T) {
+	f, err := NewNameFilter(nil, nil)
+	if err != nil || f != nil {
+		t.Fatalf("NewNameFilter(nil, nil) = %v, %v, want nil", f, err)
+	}
+
+	// A nil filter keeps everything and excludes nothing
+	if !f.Match("anything") {
+		t.Error("Match() = false, want true")
+	}
+	if f.Excludes("us-east-1/vol-1", "anything") {
+		t.Error("Excludes() = true, want false")
+	}
+	if got := f.TakeExcludedCount(); got != 0 {
+		t.Errorf("TakeExcludedCount() = %d, want 0", got)
+	}
+}
+
+func TestNewNameFilterInvalidRegexp(t *testing.T) {
+	if _, err := NewNameFilter([]string{"/(unclosed/"}, nil); err == nil {
+		t.Error("NewNameFilter() error = nil, want an invalid --include pattern error")
+	}
+	if _, err := NewNameFilter(nil, []string{"/[z-a]/"}); err == nil {
+		t.Error("NewNameFilter() error = nil, want an invalid --exclude pattern error")
+	}
+}
+
+func TestNameFilterExcludedCount(t *testing.T) {
+	f, err := NewNameFilter(nil, []string{"tmp-*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same bucket listed by the scans of two regions
+	f.Excludes("tmp-bucket", "tmp-bucket")
+	f.Excludes("tmp-bucket", "tmp-bucket")
+	f.Excludes("us-east-1/vol-1", "tmp-volume")
+	f.Excludes("us-east-1/vol-2", "data")
+
+	if got := f.TakeExcludedCount(); got != 2 {
+		t.Errorf("TakeExcludedCount() = %d, want 2", got)
+	}
+	if got := f.TakeExcludedCount(); got != 0 {
+		t.Errorf("TakeExcludedCount() after taking = %d, want 0", got)
+	}
+}
@@ -1,5 +1,17 @@
 package utils
 
+import (
+	"sort"
+	"strings"
+)
+
+// AWS partitions, as used in ARNs
+const (
+	PartitionAWS      = "aws"
+	PartitionAWSChina = "aws-cn"
+	PartitionGovCloud = "aws-us-gov"
+)
+
 // RegionDescriptiveNames maps AWS region codes to descriptive names
 var RegionDescriptiveNames = map[string]string{
 	"us-east-1":      "US East (N. Virginia)",
@@ -23,6 +35,10 @@ var RegionDescriptiveNames = map[string]string{
 	"eu-south-1":     "EU (Milan)",
 	"me-south-1":     "Middle East (Bahrain)",
 	"sa-east-1":      "South America (Sao Paulo)",
+	"us-gov-east-1":  "AWS GovCloud (US-East)",
+	"us-gov-west-1":  "AWS GovCloud (US-West)",
+	"cn-north-1":     "China (Beijing)",
+	"cn-northwest-1": "China (Ningxia)",
 }
 
 // GetRegionDescriptiveName returns the human-readable region name for AWS services
@@ -40,7 +56,31 @@ func IsValidRegion(region string) bool {
 	return ok
 }
 
-// GetDefaultRegion returns the default AWS region
+// RegionNames returns the codes of the known AWS regions in alphabetical order
+func RegionNames() []string {
+	names := make([]string, 0, len(RegionDescriptiveNames))
+	for region := range RegionDescriptiveNames {
+		names = append(names, region)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PartitionForRegion returns the partition of a region: aws-cn for the China regions,
+// aws-us-gov for the GovCloud regions, and aws otherwise
+func PartitionForRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return PartitionAWSChina
+	case strings.HasPrefix(region, "us-gov-"):
+		return PartitionGovCloud
+	default:
+		return PartitionAWS
+	}
+}
+
+// GetDefaultRegion returns the region used when neither the environment nor the shared
+// config profile sets one
 func GetDefaultRegion() string {
 	return "us-east-1"
 }
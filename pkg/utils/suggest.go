@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// LevenshteinDistance returns the minimum number of single-character insertions, deletions,
+// and substitutions needed to turn a into b
+func LevenshteinDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	// Only the previous row of the distance matrix is kept
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+// SuggestClosest returns the candidate the input was most likely meant to be, or "" if none is
+// close enough. A candidate within a Levenshtein distance of a third of the input length (at
+// least 1) is a likely typo, and the closest one wins, in alphabetical order on ties. Otherwise
+// the input is taken as an abbreviation of the only candidate it is a prefix of (e.g., "secrets"
+// for "secretsmanager"). Matching is case-insensitive.
+func SuggestClosest(input string, candidates []string) string {
+	input = strings.ToLower(input)
+	if input == "" {
+		return ""
+	}
+
+	sorted := append([]string(nil), candidates...)
+	sort.Strings(sorted)
+
+	maxDistance := max(len(input)/3, 1)
+	best, bestDistance := "", maxDistance+1
+	for _, candidate := range sorted {
+		if distance := LevenshteinDistance(input, strings.ToLower(candidate)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	var prefixed []string
+	for _, candidate := range sorted {
+		if strings.HasPrefix(strings.ToLower(candidate), input) {
+			prefixed = append(prefixed, candidate)
+		}
+	}
+	if len(prefixed) == 1 {
+		return prefixed[0]
+	}
+	return ""
+}
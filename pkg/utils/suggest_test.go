@@ -0,0 +1,71 @@
+package utils
+
+import "testing"
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "ebs", want: 3},
+		{a: "ebs", b: "", want: 3},
+		{a: "lambda", b: "lambda", want: 0},
+		{a: "lamda", b: "lambda", want: 1},
+		{a: "ec3", b: "ec2", want: 1},
+		{a: "lambdas", b: "lambda", want: 1},
+		// A transposition counts as two edits
+		{a: "ebs", b: "bes", want: 2},
+		{a: "lmabda", b: "lambda", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+		// Runes, not bytes
+		{a: "café", b: "cafe", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := LevenshteinDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("LevenshteinDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := LevenshteinDistance(tt.b, tt.a); got != tt.want {
+				t.Errorf("LevenshteinDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestClosest(t *testing.T) {
+	services := []string{"cloudfront", "cloudwatch", "ec2", "ebs", "ecr", "eip", "lambda", "opensearch", "s3", "secretsmanager", "sagemaker", "sfn"}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "exact", input: "lambda", want: "lambda"},
+		{name: "case-insensitive", input: "LAMBDA", want: "lambda"},
+		{name: "missing letter", input: "lamda", want: "lambda"},
+		{name: "substitution in a short name", input: "ec3", want: "ec2"},
+		{name: "transposition within the distance", input: "lmabda", want: "lambda"},
+		{name: "tie resolved alphabetically", input: "ec", want: "ec2"},
+		{name: "distance at the threshold", input: "opnsarch", want: "opensearch"},
+		{name: "distance above the threshold", input: "opnsrch", want: ""},
+		{name: "transposition above the threshold", input: "bes", want: ""},
+		{name: "abbreviation of a single service", input: "secrets", want: "secretsmanager"},
+		{name: "abbreviation of several services", input: "cloud", want: ""},
+		{name: "unrelated", input: "kubernetes", want: ""},
+		{name: "empty", input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestClosest(tt.input, services); got != tt.want {
+				t.Errorf("SuggestClosest(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if got := SuggestClosest("lamda", nil); got != "" {
+		t.Errorf("SuggestClosest() without candidates = %q, want none", got)
+	}
+}